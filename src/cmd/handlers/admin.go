@@ -0,0 +1,82 @@
+/*
+ * Payment Registration System - Admin Handlers
+ * ------------------------------------------------
+ * This file defines the HTTP handlers of the administration routes, which work on
+ * both storage backends at once.
+ *
+ * Created: Mar. 26, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/consistency"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	consistency services.ConsistencyService
+}
+
+// NewAdminHandler creates a new instance of AdminHandler with the provided consistency service.
+func NewAdminHandler(consistency services.ConsistencyService) *AdminHandler {
+	return &AdminHandler{
+		consistency: consistency,
+	}
+}
+
+// CheckConsistency compares the SQL and NoSQL stores.
+//
+//	@Summary		Check the consistency of the SQL and NoSQL stores
+//	@Description	Compares the banks, cards, purchases and promotions of both stores by natural key and reports the records missing from the NoSQL store, the extra records in it and the mismatched fields, as JSON or CSV.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			format	query		string						false	"Report format: json (default) or csv"
+//	@Success		200		{object}	models.ConsistencyReport	"Consistency report"
+//	@Failure		400		{object}	map[string]interface{}		"Invalid format"
+//	@Failure		500		{object}	map[string]interface{}		"Failed to read a store"
+//	@Router			/admin/consistency [get]
+func (h *AdminHandler) CheckConsistency() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("CheckConsistency request from IP: %s", c.IP())
+
+		format := c.Query("format", "json")
+		if format != "json" && format != "csv" {
+			logger.Warn("Invalid consistency report format %s", format)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid format, expected json or csv",
+			})
+		}
+
+		report, err := h.consistency.CheckConsistency(c.UserContext())
+		if err != nil {
+			logger.Error("Failed to check consistency: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		logger.Info("Consistency checked, %d differences found", len(report.Differences))
+
+		if format == "csv" {
+			var body bytes.Buffer
+			if err := consistency.WriteCSV(&body, report); err != nil {
+				logger.Error("Failed to write consistency report: %v", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			c.Set(fiber.HeaderContentType, "text/csv")
+			c.Set(fiber.HeaderContentDisposition, `attachment; filename="consistency.csv"`)
+			return c.Send(body.Bytes())
+		}
+		return c.JSON(report)
+	}
+}
@@ -30,6 +30,147 @@ func NewBankHandler(bank services.BankService) *BankHandler {
 	}
 }
 
+// CreateBank registers a new bank.
+//
+//	@Summary		Register a bank
+//	@Description	Registers a new issuing bank. The name is required and the CUIT must follow the XX-XXXXXXXX-X format and be unique.
+//	@Tags			Bank
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.Bank				true	"Bank details"
+//	@Success		201		{object}	models.Bank				"Bank registered successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or bank"
+//	@Failure		409		{object}	map[string]interface{}	"Bank already exists"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to register bank"
+//	@Router			/sql/banks [post]
+//	@Router			/no-sql/banks [post]
+func (h *BankHandler) CreateBank() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("CreateBank request from IP: %s", c.IP())
+
+		var bank models.Bank
+		if err := c.BodyParser(&bank); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		created, err := h.bank.CreateBank(c.UserContext(), bank)
+		if err != nil {
+			logger.Error("Failed to register bank: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Bank %s registered successfully", created.Cuit)
+		return c.Status(fiber.StatusCreated).JSON(created)
+	}
+}
+
+// GetBanks lists all banks.
+//
+//	@Summary		List banks
+//	@Description	Retrieves all banks ordered by CUIT.
+//	@Tags			Bank
+//	@Produce		json
+//	@Success		200	{array}		models.Bank				"Banks retrieved successfully"
+//	@Failure		500	{object}	map[string]interface{}	"Failed to retrieve banks"
+//	@Router			/sql/banks [get]
+//	@Router			/no-sql/banks [get]
+func (h *BankHandler) GetBanks() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetBanks request from IP: %s", c.IP())
+
+		banks, err := h.bank.GetBanks(c.UserContext())
+		if err != nil {
+			logger.Error("Failed to retrieve banks: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(banks)
+	}
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+//
+//	@Summary		Get a bank
+//	@Description	Retrieves a bank by its CUIT.
+//	@Tags			Bank
+//	@Produce		json
+//	@Param			cuit	path		string					true	"CUIT of the bank"
+//	@Success		200		{object}	models.Bank				"Bank retrieved successfully"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to retrieve bank"
+//	@Router			/sql/banks/{cuit} [get]
+//	@Router			/no-sql/banks/{cuit} [get]
+func (h *BankHandler) GetBankByCuit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetBankByCuit request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		bank, err := h.bank.GetBankByCuit(c.UserContext(), cuit)
+		if err != nil {
+			logger.Error("Failed to retrieve bank %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(bank)
+	}
+}
+
+// UpdateBank replaces the details of a bank.
+//
+//	@Summary		Update a bank
+//	@Description	Replaces the name, address and telephone of a bank. The CUIT cannot be changed.
+//	@Tags			Bank
+//	@Accept			json
+//	@Produce		json
+//	@Param			cuit	path		string					true	"CUIT of the bank"
+//	@Param			request	body		models.Bank				true	"Bank details"
+//	@Success		200		{object}	models.Bank				"Bank updated successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or bank"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to update bank"
+//	@Router			/sql/banks/{cuit} [put]
+//	@Router			/no-sql/banks/{cuit} [put]
+func (h *BankHandler) UpdateBank() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("UpdateBank request from IP: %s", c.IP())
+
+		var bank models.Bank
+		if err := c.BodyParser(&bank); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		cuit := c.Params("cuit")
+		updated, err := h.bank.UpdateBank(c.UserContext(), cuit, bank)
+		if err != nil {
+			logger.Error("Failed to update bank %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Bank %s updated successfully", cuit)
+		return c.JSON(updated)
+	}
+}
+
 // AddFinancingPromotionToBank adds a financing promotion to a bank.
 //
 //	@Summary		Add a financing promotion to a bank
@@ -58,7 +199,7 @@ func (h *BankHandler) AddFinancingPromotionToBank() fiber.Handler {
 			})
 		}
 
-		if err := h.bank.AddFinancingPromotionToBank(promotion); err != nil {
+		if err := h.bank.AddFinancingPromotionToBank(c.UserContext(), promotion); err != nil {
 			logger.Error("Failed to add financing promotion: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error":   "Failed to add promotion",
@@ -74,6 +215,50 @@ func (h *BankHandler) AddFinancingPromotionToBank() fiber.Handler {
 	}
 }
 
+// AddDiscountPromotionToBank adds a discount promotion to a bank.
+//
+//	@Summary		Add a discount promotion to a bank
+//	@Description	Adds a new discount promotion using the request body data. The discount percentage must be greater than 0 and at most 100, the price cap cannot be negative (0 means no cap) and the validity end date must be after the start date.
+//	@Tags			Bank
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.Discount			true	"Discount promotion details"
+//	@Success		201		{object}	map[string]interface{}	"Discount promotion added successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or promotion"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to add promotion"
+//	@Router			/sql/promotions/discount [post]
+//	@Router			/no-sql/promotions/discount [post]
+func (h *BankHandler) AddDiscountPromotionToBank() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		// Log request
+		logger.Info("AddDiscountPromotionToBank request from IP: %s", c.IP())
+
+		var promotion models.Discount
+		if err := c.BodyParser(&promotion); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		if err := h.bank.AddDiscountPromotionToBank(c.UserContext(), promotion); err != nil {
+			logger.Error("Failed to add discount promotion: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Discount promotion %s added successfully", promotion.Code)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message": "Discount promotion added successfully",
+			"data":    promotion,
+		})
+	}
+}
+
 // ExtendFinancingPromotionValidity extends the validity of an existing financing promotion.
 //
 //	@Summary		Extend financing promotion validity
@@ -118,7 +303,7 @@ func (h *BankHandler) ExtendFinancingPromotionValidity() fiber.Handler {
 			})
 		}
 
-		err = h.bank.ExtendFinancingPromotionValidity(code, newDate)
+		err = h.bank.ExtendFinancingPromotionValidity(c.UserContext(), code, newDate)
 		if err != nil {
 			logger.Error("Failed to extend financing promotion validity %s due %s", code, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -179,7 +364,7 @@ func (h *BankHandler) ExtendDiscountPromotionValidity() fiber.Handler {
 			})
 		}
 
-		err = h.bank.ExtendDiscountPromotionValidity(code, newDate)
+		err = h.bank.ExtendDiscountPromotionValidity(c.UserContext(), code, newDate)
 		if err != nil {
 			logger.Error("Failed to extend discount promotion validity %s due %s", code, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -222,7 +407,7 @@ func (h *BankHandler) DeleteFinancingPromotion() fiber.Handler {
 			})
 		}
 
-		err := h.bank.DeleteFinancingPromotion(code)
+		err := h.bank.DeleteFinancingPromotion(c.UserContext(), code)
 		if err != nil {
 			logger.Error("Failed to delete financing promotion %s due %s", code, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -265,7 +450,7 @@ func (h *BankHandler) DeleteDiscountPromotion() fiber.Handler {
 		}
 
 		// Call the service to delete the promotion
-		err := h.bank.DeleteDiscountPromotion(code)
+		err := h.bank.DeleteDiscountPromotion(c.UserContext(), code)
 		if err != nil {
 			logger.Error("Failed to delete discount promotion %s due %s", code, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -298,7 +483,7 @@ func (h *BankHandler) GetBankCustomerCounts() fiber.Handler {
 
 		logger.Info("GetBankCustomerCounts request from IP: %s", c.IP())
 
-		customerCounts, err := h.bank.GetBankCustomerCounts()
+		customerCounts, err := h.bank.GetBankCustomerCounts(c.UserContext())
 		if err != nil {
 			logger.Error("Failed to get bank customer counts: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
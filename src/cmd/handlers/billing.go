@@ -0,0 +1,157 @@
+/*
+ * Payment Registration System - Billing Handlers
+ * ----------------------------------------------
+ * This file defines the HTTP handlers for billing cycles: the per-bank closing and due-date
+ * configuration, and the closing of the monthly payment summaries of cards.
+ *
+ * Created: Mar. 04, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BillingHandler struct {
+	billing services.BillingService
+}
+
+// NewBillingHandler creates a new instance of BillingHandler with the provided billing service.
+func NewBillingHandler(billing services.BillingService) *BillingHandler {
+	return &BillingHandler{
+		billing: billing,
+	}
+}
+
+// ConfigureBillingCycle creates or replaces the billing cycle configuration of a bank.
+//
+//	@Summary		Configure the billing cycle of a bank
+//	@Description	Sets the closing day, the due dates and the late payment surcharge used when closing the payment summaries of the bank's cards.
+//	@Tags			Billing
+//	@Accept			json
+//	@Produce		json
+//	@Param			cuit	path		string					true	"Bank CUIT"
+//	@Param			request	body		models.BillingCycle		true	"Billing cycle configuration"
+//	@Success		200		{object}	models.BillingCycle		"Billing cycle configured successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or configuration"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to configure billing cycle"
+//	@Router			/sql/banks/{cuit}/billing-cycle [put]
+//	@Router			/no-sql/banks/{cuit}/billing-cycle [put]
+func (h *BillingHandler) ConfigureBillingCycle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("ConfigureBillingCycle request from IP: %s", c.IP())
+
+		var cycle models.BillingCycle
+		if err := c.BodyParser(&cycle); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+		// The bank is identified by the path
+		cycle.BankCuit = c.Params("cuit")
+
+		configured, err := h.billing.ConfigureBillingCycle(c.UserContext(), cycle)
+		if err != nil {
+			logger.Error("Failed to configure billing cycle of bank %s: %v", cycle.BankCuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Billing cycle of bank %s configured successfully", cycle.BankCuit)
+		return c.JSON(configured)
+	}
+}
+
+// GetBillingCycle retrieves the billing cycle configuration of a bank.
+//
+//	@Summary		Get the billing cycle of a bank
+//	@Description	Retrieves the billing cycle configuration of a bank. Banks without a configuration of their own report the default cycle.
+//	@Tags			Billing
+//	@Accept			json
+//	@Produce		json
+//	@Param			cuit	path		string					true	"Bank CUIT"
+//	@Success		200		{object}	models.BillingCycle		"Billing cycle retrieved successfully"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to retrieve billing cycle"
+//	@Router			/sql/banks/{cuit}/billing-cycle [get]
+//	@Router			/no-sql/banks/{cuit}/billing-cycle [get]
+func (h *BillingHandler) GetBillingCycle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetBillingCycle request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		cycle, err := h.billing.GetBillingCycle(c.UserContext(), cuit)
+		if err != nil {
+			logger.Error("Failed to retrieve billing cycle of bank %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(cycle)
+	}
+}
+
+// CloseCycle closes the billing cycle of a card for a month and stores its payment summary.
+//
+//	@Summary		Close a billing cycle
+//	@Description	Closes the billing cycle of the card for the given month and stores its payment summary. A cycle can only be closed once, after its closing date.
+//	@Tags			Billing
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Param			month		path		int						true	"Month (1-12)"
+//	@Param			year		path		int						true	"Year (e.g., 2025)"
+//	@Success		201			{object}	models.PaymentSummary	"Billing cycle closed successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid parameters or cycle not closed yet"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		409			{object}	map[string]interface{}	"Billing cycle already closed"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to close billing cycle"
+//	@Router			/sql/cards/summary/{cardNumber}/{month}/{year} [post]
+//	@Router			/no-sql/cards/summary/{cardNumber}/{month}/{year} [post]
+func (h *BillingHandler) CloseCycle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("CloseCycle request from IP: %s", c.IP())
+
+		cardNumber := c.Params("cardNumber")
+		month, err := strconv.Atoi(c.Params("month"))
+		if err != nil {
+			logger.Warn("Invalid month parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid month parameter",
+			})
+		}
+		year, err := strconv.Atoi(c.Params("year"))
+		if err != nil {
+			logger.Warn("Invalid year parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid year parameter",
+			})
+		}
+
+		paymentSummary, err := h.billing.CloseCycle(c.UserContext(), cardNumber, month, year)
+		if err != nil {
+			logger.Error("Failed to close billing cycle %02d/%d of card %s: %v", month, year, cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Billing cycle %02d/%d of card %s closed successfully", month, year, cardNumber)
+		return c.Status(fiber.StatusCreated).JSON(paymentSummary)
+	}
+}
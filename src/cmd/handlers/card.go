@@ -10,8 +10,11 @@
 package handlers
 
 import (
+	"context"
 	"strconv"
+	"time"
 
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"github.com/gofiber/fiber/v2"
@@ -31,7 +34,7 @@ func NewCardHandler(card services.CardService) *CardHandler {
 // GetPaymentSummary retrieves the payment summary for a specific card and period.
 //
 //	@Summary		Get payment summary
-//	@Description	Retrieves the payment summary for a given card number, month, and year.
+//	@Description	Retrieves the stored payment summary for a given card number, month, and year. Summaries are created by closing the billing cycle.
 //	@Tags			Card
 //	@Accept			json
 //	@Produce		json
@@ -40,6 +43,7 @@ func NewCardHandler(card services.CardService) *CardHandler {
 //	@Param			year		path		int						true	"Year (e.g., 2025)"
 //	@Success		200			{object}	map[string]interface{}	"Payment summary retrieved successfully"
 //	@Failure		400			{object}	map[string]interface{}	"Invalid month or year parameter"
+//	@Failure		404			{object}	map[string]interface{}	"Card or payment summary not found"
 //	@Failure		500			{object}	map[string]interface{}	"Failed to retrieve payment summary"
 //	@Router			/sql/cards/payment-summary/{cardNumber}/{month}/{year} [get]
 //	@Router			/no-sql/cards/payment-summary/{cardNumber}/{month}/{year} [get]
@@ -70,10 +74,10 @@ func (h *CardHandler) GetPaymentSummary() fiber.Handler {
 		}
 
 		// Call the service to get the payment summary
-		paymentSummary, err := h.card.GetPaymentSummary(cardNumber, month, year)
+		paymentSummary, err := h.card.GetPaymentSummary(c.UserContext(), cardNumber, month, year)
 		if err != nil {
 			logger.Error("Failed to retrieve payment summary: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
@@ -139,7 +143,7 @@ func (h *CardHandler) GetCardsExpiringInNext30Days() fiber.Handler {
 		}
 
 		// Call the service to get cards expiring in the next 30 days
-		cards, err := h.card.GetCardsExpiringInNext30Days(day, month, year)
+		cards, err := h.card.GetCardsExpiringInNext30Days(c.UserContext(), day, month, year)
 		if err != nil {
 			logger.Error("Failed to retrieve expiring cards: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -167,7 +171,7 @@ func (h *CardHandler) GetCardsExpiringInNext30Days() fiber.Handler {
 //	@Accept			json
 //	@Produce		json
 //	@Param			cuit			path		string					true	"CUIT (Unique Tax Identification Code)"
-//	@Param			finalAmount		path		float64					true	"Final purchase amount"
+//	@Param			finalAmount		path		number					true	"Final purchase amount, with at most two decimal places"
 //	@Param			paymentVoucher	path		string					true	"Payment voucher identifier"
 //	@Success		200				{object}	map[string]interface{}	"Monthly purchase details retrieved successfully"
 //	@Failure		400				{object}	map[string]interface{}	"Invalid finalAmount parameter"
@@ -184,8 +188,8 @@ func (h *CardHandler) GetPurchaseMonthly() fiber.Handler {
 		finalAmountStr := c.Params("finalAmount")
 		paymentVoucher := c.Params("paymentVoucher")
 
-		// Convert finalAmount to float64
-		finalAmount, err := strconv.ParseFloat(finalAmountStr, 64)
+		// Parse finalAmount as an exact amount, so that it matches the stored one
+		finalAmount, err := models.ParseMoney(finalAmountStr)
 		if err != nil {
 			logger.Warn("Invalid finalAmount parameter")
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -194,7 +198,7 @@ func (h *CardHandler) GetPurchaseMonthly() fiber.Handler {
 		}
 
 		// Call the service to get the monthly purchase details
-		purchase, err := h.card.GetPurchaseMonthly(cuit, finalAmount, paymentVoucher)
+		purchase, err := h.card.GetPurchaseMonthly(c.UserContext(), cuit, finalAmount, paymentVoucher)
 		if err != nil {
 			logger.Error("Failed to retrieve monthly purchase details: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -231,7 +235,7 @@ func (h *CardHandler) GetTop10CardsByPurchases() fiber.Handler {
 		logger.Info("GetTop10CardsByPurchases request from IP: %s", c.IP())
 
 		// Call the service to get the top 10 cards by purchases
-		cards, err := h.card.GetTop10CardsByPurchases()
+		cards, err := h.card.GetTop10CardsByPurchases(c.UserContext())
 		if err != nil {
 			logger.Error("Failed to retrieve top 10 cards: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -250,3 +254,314 @@ func (h *CardHandler) GetTop10CardsByPurchases() fiber.Handler {
 		}
 	}
 }
+
+// RegisterPurchase registers a new single-payment or installment purchase on a card.
+//
+//	@Summary		Register a purchase
+//	@Description	Validates and registers a purchase on the given card. The final amount is computed from the promotions of the card's bank that apply to the store, and the payment voucher is generated by the system.
+//	@Tags			Card
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Param			request		body		models.PurchaseRequest	true	"Purchase details"
+//	@Success		201			{object}	map[string]interface{}	"Purchase registered successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid request body or purchase data"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to register purchase"
+//	@Router			/sql/cards/{cardNumber}/purchases [post]
+//	@Router			/no-sql/cards/{cardNumber}/purchases [post]
+func (h *CardHandler) RegisterPurchase() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("RegisterPurchase request from IP: %s", c.IP())
+
+		cardNumber := c.Params("cardNumber")
+
+		var request models.PurchaseRequest
+		if err := c.BodyParser(&request); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		// The purchase date is optional, the service defaults it to now
+		var purchaseDate time.Time
+		if request.PurchaseDate != "" {
+			parsedDate, err := time.Parse(time.RFC3339, request.PurchaseDate)
+			if err != nil {
+				logger.Warn("Invalid purchase date format")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid purchase_date format. Expected RFC3339 format.",
+				})
+			}
+			purchaseDate = parsedDate
+		}
+
+		purchase := models.Purchase{
+			Store:        request.Store,
+			CuitStore:    request.CuitStore,
+			Amount:       request.Amount,
+			Currency:     request.Currency,
+			PurchaseType: request.PurchaseType,
+			PurchaseDate: purchaseDate,
+		}
+
+		var (
+			created interface{}
+			voucher string
+			err     error
+		)
+		switch request.PurchaseType {
+		case models.SinglePayment:
+			var single *models.PurchaseSinglePayment
+			single, err = h.card.RegisterSinglePurchase(c.UserContext(), cardNumber, models.PurchaseSinglePayment{
+				Purchase:      purchase,
+				StoreDiscount: request.StoreDiscount,
+			})
+			if err == nil {
+				created, voucher = single, single.PaymentVoucher
+			}
+		case models.MonthlyPayments:
+			var monthly *models.PurchaseMonthlyPayment
+			monthly, err = h.card.RegisterMonthlyPurchase(c.UserContext(), cardNumber, models.PurchaseMonthlyPayment{
+				Purchase:       purchase,
+				Interest:       request.Interest,
+				InterestModel:  request.InterestModel,
+				NumberOfQuotas: request.NumberOfQuotas,
+			})
+			if err == nil {
+				created, voucher = monthly, monthly.PaymentVoucher
+			}
+		default:
+			logger.Warn("Invalid purchase type %d", request.PurchaseType)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid purchase_type. Expected 0 (single payment) or 1 (monthly payments).",
+			})
+		}
+
+		if err != nil {
+			logger.Error("Failed to register purchase on card %s: %v", cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Purchase %s registered successfully on card %s", voucher, cardNumber)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message":         "Purchase registered successfully",
+			"payment_voucher": voucher,
+			"data":            created,
+		})
+	}
+}
+
+// SimulatePurchase quotes a prospective purchase under every applicable promotion without registering it.
+//
+//	@Summary		Simulate the promotions of a purchase
+//	@Description	Quotes a purchase in pesos on the given card at the store with the given CUIT under every promotion the card's bank offers there on the purchase date (now by default): paid at once without a promotion and with each discount, and in quotas with each financing. When number_of_quotas is given and no financing offers it, installments are also quoted at the given interest and interest_model, without a promotion and with each discount not restricted to cash payments. Each quote has its final amount and, for installments, its quota schedule; within each payment terms, the quote a registered purchase would get is marked as applied. Nothing is persisted.
+//	@Tags			Promotion
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.PromotionSimulationRequest	true	"Card, store, amount and optional installments"
+//	@Success		200		{array}		models.PurchaseQuote				"Purchase quoted successfully"
+//	@Failure		400		{object}	map[string]interface{}				"Invalid request body, purchase or unusable card"
+//	@Failure		404		{object}	map[string]interface{}				"Card not found"
+//	@Failure		500		{object}	map[string]interface{}				"Failed to simulate promotions"
+//	@Router			/sql/promotions/simulate [post]
+//	@Router			/no-sql/promotions/simulate [post]
+func (h *CardHandler) SimulatePurchase() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("SimulatePurchase request from IP: %s", c.IP())
+
+		var request models.PromotionSimulationRequest
+		if err := c.BodyParser(&request); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		// The purchase date is optional, the service defaults it to now
+		var purchaseDate time.Time
+		if request.PurchaseDate != "" {
+			parsedDate, err := time.Parse(time.RFC3339, request.PurchaseDate)
+			if err != nil {
+				logger.Warn("Invalid purchase date format")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid purchase_date format. Expected RFC3339 format.",
+				})
+			}
+			purchaseDate = parsedDate
+		}
+
+		quotes, err := h.card.SimulatePurchase(c.UserContext(), request, purchaseDate)
+		if err != nil {
+			logger.Error("Failed to simulate promotions on card %s: %v", request.CardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(quotes)
+	}
+}
+
+// IssueCard issues a new card to a customer at a bank.
+//
+//	@Summary		Issue a card
+//	@Description	Issues a new active card to the customer with the given CUIT at the bank with the given CUIT. The number must have 16 digits and be unique, the verification code 3 digits, and the expiration date must be after the issuance date (now by default).
+//	@Tags			Card
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.Card				true	"Card details, with bank.cuit and customer_cuit"
+//	@Success		201		{object}	models.Card				"Card issued successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or card"
+//	@Failure		404		{object}	map[string]interface{}	"Bank or customer not found"
+//	@Failure		409		{object}	map[string]interface{}	"Card number already in use"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to issue card"
+//	@Router			/sql/cards [post]
+//	@Router			/no-sql/cards [post]
+func (h *CardHandler) IssueCard() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("IssueCard request from IP: %s", c.IP())
+
+		var card models.Card
+		if err := c.BodyParser(&card); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		issued, err := h.card.IssueCard(c.UserContext(), card)
+		if err != nil {
+			logger.Error("Failed to issue card: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Card %s issued successfully", issued.Number)
+		return c.Status(fiber.StatusCreated).JSON(issued)
+	}
+}
+
+// RenewCard extends the expiration date of a card.
+//
+//	@Summary		Renew a card
+//	@Description	Replaces the expiration date of a card that has not been cancelled. The new date must be in the future and after the current one.
+//	@Tags			Card
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Param			request		body		models.CardRenewal		true	"New expiration date"
+//	@Success		200			{object}	models.Card				"Card renewed successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid request body, date or card status"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to renew card"
+//	@Router			/sql/cards/{cardNumber}/renew [post]
+//	@Router			/no-sql/cards/{cardNumber}/renew [post]
+func (h *CardHandler) RenewCard() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("RenewCard request from IP: %s", c.IP())
+
+		var renewal models.CardRenewal
+		if err := c.BodyParser(&renewal); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		cardNumber := c.Params("cardNumber")
+		card, err := h.card.RenewCard(c.UserContext(), cardNumber, renewal.ExpirationDate)
+		if err != nil {
+			logger.Error("Failed to renew card %s: %v", cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Card %s renewed until %s", cardNumber, card.ExpirationDate.Format(time.RFC3339))
+		return c.JSON(card)
+	}
+}
+
+// BlockCard temporarily disables an active card.
+//
+//	@Summary		Block a card
+//	@Description	Blocks an active card. Purchases on blocked cards are rejected until the card is unblocked.
+//	@Tags			Card
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Success		200			{object}	models.Card				"Card blocked successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Card is not active"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to block card"
+//	@Router			/sql/cards/{cardNumber}/block [post]
+//	@Router			/no-sql/cards/{cardNumber}/block [post]
+func (h *CardHandler) BlockCard() fiber.Handler {
+	return h.changeCardStatus("BlockCard", h.card.BlockCard)
+}
+
+// UnblockCard enables a blocked card again.
+//
+//	@Summary		Unblock a card
+//	@Description	Unblocks a blocked card so that it can be used for purchases again.
+//	@Tags			Card
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Success		200			{object}	models.Card				"Card unblocked successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Card is not blocked"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to unblock card"
+//	@Router			/sql/cards/{cardNumber}/unblock [post]
+//	@Router			/no-sql/cards/{cardNumber}/unblock [post]
+func (h *CardHandler) UnblockCard() fiber.Handler {
+	return h.changeCardStatus("UnblockCard", h.card.UnblockCard)
+}
+
+// CancelCard permanently disables a card.
+//
+//	@Summary		Cancel a card
+//	@Description	Cancels an active or blocked card. Cancelled cards cannot be unblocked, renewed or used for purchases.
+//	@Tags			Card
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Success		200			{object}	models.Card				"Card cancelled successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Card is already cancelled"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to cancel card"
+//	@Router			/sql/cards/{cardNumber}/cancel [post]
+//	@Router			/no-sql/cards/{cardNumber}/cancel [post]
+func (h *CardHandler) CancelCard() fiber.Handler {
+	return h.changeCardStatus("CancelCard", h.card.CancelCard)
+}
+
+// changeCardStatus builds a handler that applies a status change to the card in the path.
+func (h *CardHandler) changeCardStatus(operation string, change func(ctx context.Context, cardNumber string) (*models.Card, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("%s request from IP: %s", operation, c.IP())
+
+		cardNumber := c.Params("cardNumber")
+		card, err := change(c.UserContext(), cardNumber)
+		if err != nil {
+			logger.Error("%s failed for card %s: %v", operation, cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Card %s is now %s", cardNumber, card.Status)
+		return c.JSON(card)
+	}
+}
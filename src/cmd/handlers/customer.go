@@ -0,0 +1,241 @@
+/*
+ * Payment Registration System - Customer Handlers
+ * -----------------------------------------------
+ * This file defines the HTTP handlers for registering customers and managing their bank memberships.
+ *
+ * Created: Mar. 09, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CustomerHandler struct {
+	customer services.CustomerService
+}
+
+// NewCustomerHandler creates a new instance of CustomerHandler with the provided customer service.
+func NewCustomerHandler(customer services.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customer: customer,
+	}
+}
+
+// CreateCustomer registers a new customer.
+//
+//	@Summary		Register a customer
+//	@Description	Registers a new customer. The name is required, the DNI must have 7 or 8 digits and the CUIT must follow the XX-XXXXXXXX-X format. CUIT and DNI must be unique; the entry date defaults to now.
+//	@Tags			Customer
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.Customer			true	"Customer details"
+//	@Success		201		{object}	models.Customer			"Customer registered successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or customer"
+//	@Failure		409		{object}	map[string]interface{}	"Customer already exists"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to register customer"
+//	@Router			/sql/customers [post]
+//	@Router			/no-sql/customers [post]
+func (h *CustomerHandler) CreateCustomer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("CreateCustomer request from IP: %s", c.IP())
+
+		var customer models.Customer
+		if err := c.BodyParser(&customer); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		created, err := h.customer.CreateCustomer(c.UserContext(), customer)
+		if err != nil {
+			logger.Error("Failed to register customer: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Customer %s registered successfully", created.Cuit)
+		return c.Status(fiber.StatusCreated).JSON(created)
+	}
+}
+
+// GetCustomers lists all customers.
+//
+//	@Summary		List customers
+//	@Description	Retrieves all customers ordered by CUIT, including the CUITs of the banks they are members of.
+//	@Tags			Customer
+//	@Produce		json
+//	@Success		200	{array}		models.Customer			"Customers retrieved successfully"
+//	@Failure		500	{object}	map[string]interface{}	"Failed to retrieve customers"
+//	@Router			/sql/customers [get]
+//	@Router			/no-sql/customers [get]
+func (h *CustomerHandler) GetCustomers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetCustomers request from IP: %s", c.IP())
+
+		customers, err := h.customer.GetCustomers(c.UserContext())
+		if err != nil {
+			logger.Error("Failed to retrieve customers: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(customers)
+	}
+}
+
+// GetCustomerByCuit retrieves a customer by its CUIT.
+//
+//	@Summary		Get a customer
+//	@Description	Retrieves a customer by its CUIT, including the CUITs of the banks it is a member of.
+//	@Tags			Customer
+//	@Produce		json
+//	@Param			cuit	path		string					true	"CUIT of the customer"
+//	@Success		200		{object}	models.Customer			"Customer retrieved successfully"
+//	@Failure		404		{object}	map[string]interface{}	"Customer not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to retrieve customer"
+//	@Router			/sql/customers/{cuit} [get]
+//	@Router			/no-sql/customers/{cuit} [get]
+func (h *CustomerHandler) GetCustomerByCuit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetCustomerByCuit request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		customer, err := h.customer.GetCustomerByCuit(c.UserContext(), cuit)
+		if err != nil {
+			logger.Error("Failed to retrieve customer %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(customer)
+	}
+}
+
+// UpdateCustomer replaces the details of a customer.
+//
+//	@Summary		Update a customer
+//	@Description	Replaces the name, DNI, address and telephone of a customer. The CUIT cannot be changed and a missing entry date keeps the current one.
+//	@Tags			Customer
+//	@Accept			json
+//	@Produce		json
+//	@Param			cuit	path		string					true	"CUIT of the customer"
+//	@Param			request	body		models.Customer			true	"Customer details"
+//	@Success		200		{object}	models.Customer			"Customer updated successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or customer"
+//	@Failure		404		{object}	map[string]interface{}	"Customer not found"
+//	@Failure		409		{object}	map[string]interface{}	"DNI already in use"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to update customer"
+//	@Router			/sql/customers/{cuit} [put]
+//	@Router			/no-sql/customers/{cuit} [put]
+func (h *CustomerHandler) UpdateCustomer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("UpdateCustomer request from IP: %s", c.IP())
+
+		var customer models.Customer
+		if err := c.BodyParser(&customer); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		cuit := c.Params("cuit")
+		updated, err := h.customer.UpdateCustomer(c.UserContext(), cuit, customer)
+		if err != nil {
+			logger.Error("Failed to update customer %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Customer %s updated successfully", cuit)
+		return c.JSON(updated)
+	}
+}
+
+// AddCustomerToBank makes a customer a member of a bank.
+//
+//	@Summary		Add a customer to a bank
+//	@Description	Makes the customer a member of the bank identified by its CUIT.
+//	@Tags			Customer
+//	@Produce		json
+//	@Param			cuit		path		string					true	"CUIT of the customer"
+//	@Param			bankCuit	path		string					true	"CUIT of the bank"
+//	@Success		200			{object}	map[string]interface{}	"Customer added to the bank successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid bank CUIT"
+//	@Failure		404			{object}	map[string]interface{}	"Customer or bank not found"
+//	@Failure		409			{object}	map[string]interface{}	"Customer is already a member of the bank"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to add customer to the bank"
+//	@Router			/sql/customers/{cuit}/banks/{bankCuit} [put]
+//	@Router			/no-sql/customers/{cuit}/banks/{bankCuit} [put]
+func (h *CustomerHandler) AddCustomerToBank() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("AddCustomerToBank request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		bankCuit := c.Params("bankCuit")
+		if err := h.customer.AddCustomerToBank(c.UserContext(), cuit, bankCuit); err != nil {
+			logger.Error("Failed to add customer %s to bank %s: %v", cuit, bankCuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Customer %s added to bank %s successfully", cuit, bankCuit)
+		return c.JSON(fiber.Map{
+			"message": "Customer added to the bank successfully",
+		})
+	}
+}
+
+// RemoveCustomerFromBank ends the membership of a customer in a bank.
+//
+//	@Summary		Remove a customer from a bank
+//	@Description	Ends the membership of the customer in the bank identified by its CUIT.
+//	@Tags			Customer
+//	@Produce		json
+//	@Param			cuit		path		string					true	"CUIT of the customer"
+//	@Param			bankCuit	path		string					true	"CUIT of the bank"
+//	@Success		200			{object}	map[string]interface{}	"Customer removed from the bank successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid bank CUIT"
+//	@Failure		404			{object}	map[string]interface{}	"Customer, bank or membership not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to remove customer from the bank"
+//	@Router			/sql/customers/{cuit}/banks/{bankCuit} [delete]
+//	@Router			/no-sql/customers/{cuit}/banks/{bankCuit} [delete]
+func (h *CustomerHandler) RemoveCustomerFromBank() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("RemoveCustomerFromBank request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		bankCuit := c.Params("bankCuit")
+		if err := h.customer.RemoveCustomerFromBank(c.UserContext(), cuit, bankCuit); err != nil {
+			logger.Error("Failed to remove customer %s from bank %s: %v", cuit, bankCuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Customer %s removed from bank %s successfully", cuit, bankCuit)
+		return c.JSON(fiber.Map{
+			"message": "Customer removed from the bank successfully",
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ * Payment Registration System - Request Deadlines
+ * -----------------------------------------------
+ * This file defines the middleware bounding the context that handlers pass down to the services
+ * and repositories, so that a request past its deadline stops its database operations.
+ *
+ * Created: Mar. 30, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatusClientClosedRequest is the non-standard status answered when a request is cancelled before it completes,
+// which only a forced shutdown does: fasthttp does not report clients closing their connection.
+const StatusClientClosedRequest = 499
+
+// WithDeadline returns a middleware that bounds the user context of the request by the given timeout, 0 meaning
+// no deadline. A handler failing after the context expired or was cancelled answers 504 or 499 respectively,
+// whatever status it chose for the error its storage operation returned.
+func WithDeadline(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+			c.SetUserContext(ctx)
+		}
+
+		err := c.Next()
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			logger.Warn("Request %s %s stopped: %v", c.Method(), c.OriginalURL(), err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil && c.Response().StatusCode() >= fiber.StatusBadRequest {
+			logger.Warn("Request %s %s stopped: %v", c.Method(), c.OriginalURL(), ctxErr)
+			c.Status(statusFromError(ctxErr))
+		}
+		return err
+	}
+}
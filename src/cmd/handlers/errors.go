@@ -0,0 +1,38 @@
+/*
+ * Payment Registration System - Handler Errors
+ * --------------------------------------------
+ * This file maps the errors returned by the services to HTTP status codes.
+ *
+ * Created: Mar. 02, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+// statusFromError returns the HTTP status code that best describes the given service error.
+// An expired or cancelled context takes precedence over the error the storage wrapped it in.
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, services.ErrValidation):
+		return fiber.StatusBadRequest
+	case errors.Is(err, storage.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, storage.ErrAlreadyExists):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
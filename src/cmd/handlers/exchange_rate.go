@@ -0,0 +1,151 @@
+/*
+ * Payment Registration System - Exchange Rate Handlers
+ * ----------------------------------------------------
+ * This file defines the HTTP handlers for the daily exchange rates of foreign currencies: importing
+ * them from CSV files and looking them up.
+ *
+ * Created: Apr. 05, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRate services.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new instance of ExchangeRateHandler with the provided exchange rate service.
+func NewExchangeRateHandler(exchangeRate services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRate: exchangeRate,
+	}
+}
+
+// ImportExchangeRates imports the exchange rates of a CSV file.
+//
+//	@Summary		Import exchange rates
+//	@Description	Imports the daily exchange rates of a CSV file with the header date,currency,rate and one rate per line, such as 2025-04-01,USD,1072.50. Rates already stored for the same currency and day are replaced. An invalid file is rejected as a whole.
+//	@Tags			Exchange Rates
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			request	body		string					true	"CSV file"
+//	@Success		201		{array}		models.ExchangeRate		"Exchange rates imported successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid CSV file"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to import exchange rates"
+//	@Router			/sql/exchange-rates [post]
+//	@Router			/no-sql/exchange-rates [post]
+func (h *ExchangeRateHandler) ImportExchangeRates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("ImportExchangeRates request from IP: %s", c.IP())
+
+		rates, err := h.exchangeRate.ImportExchangeRates(c.UserContext(), bytes.NewReader(c.Body()))
+		if err != nil {
+			logger.Error("Failed to import exchange rates: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("%d exchange rates imported successfully", len(rates))
+		return c.Status(fiber.StatusCreated).JSON(rates)
+	}
+}
+
+// GetExchangeRates retrieves the rates of a currency in a period.
+//
+//	@Summary		Get the exchange rates of a currency
+//	@Description	Retrieves the daily rates of a foreign currency from one day to another, both included, ordered by day.
+//	@Tags			Exchange Rates
+//	@Accept			json
+//	@Produce		json
+//	@Param			currency	path		string					true	"Currency code"	example(USD)
+//	@Param			from		query		string					true	"First day (YYYY-MM-DD)"
+//	@Param			to			query		string					true	"Last day (YYYY-MM-DD)"
+//	@Success		200			{array}		models.ExchangeRate		"Exchange rates retrieved successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid currency or period"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to retrieve exchange rates"
+//	@Router			/sql/exchange-rates/{currency} [get]
+//	@Router			/no-sql/exchange-rates/{currency} [get]
+func (h *ExchangeRateHandler) GetExchangeRates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetExchangeRates request from IP: %s", c.IP())
+
+		from, err := time.Parse(time.DateOnly, c.Query("from"))
+		if err != nil {
+			logger.Warn("Invalid from parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid from parameter, expected YYYY-MM-DD",
+			})
+		}
+		to, err := time.Parse(time.DateOnly, c.Query("to"))
+		if err != nil {
+			logger.Warn("Invalid to parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid to parameter, expected YYYY-MM-DD",
+			})
+		}
+
+		currency := c.Params("currency")
+		rates, err := h.exchangeRate.GetExchangeRates(c.UserContext(), currency, from, to)
+		if err != nil {
+			logger.Error("Failed to retrieve %s exchange rates: %v", currency, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(rates)
+	}
+}
+
+// GetExchangeRate retrieves the rate of a currency that applies on a day.
+//
+//	@Summary		Get the exchange rate of a currency on a day
+//	@Description	Retrieves the rate of a foreign currency that applies on a day: the latest one on or before it.
+//	@Tags			Exchange Rates
+//	@Accept			json
+//	@Produce		json
+//	@Param			currency	path		string					true	"Currency code"	example(USD)
+//	@Param			date		path		string					true	"Day (YYYY-MM-DD)"
+//	@Success		200			{object}	models.ExchangeRate		"Exchange rate retrieved successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid currency or day"
+//	@Failure		404			{object}	map[string]interface{}	"No exchange rate on or before the day"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to retrieve exchange rate"
+//	@Router			/sql/exchange-rates/{currency}/{date} [get]
+//	@Router			/no-sql/exchange-rates/{currency}/{date} [get]
+func (h *ExchangeRateHandler) GetExchangeRate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetExchangeRate request from IP: %s", c.IP())
+
+		date, err := time.Parse(time.DateOnly, c.Params("date"))
+		if err != nil {
+			logger.Warn("Invalid date parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid date parameter, expected YYYY-MM-DD",
+			})
+		}
+
+		currency := c.Params("currency")
+		rate, err := h.exchangeRate.GetExchangeRate(c.UserContext(), currency, date)
+		if err != nil {
+			logger.Error("Failed to retrieve %s exchange rate of %s: %v", currency, c.Params("date"), err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(rate)
+	}
+}
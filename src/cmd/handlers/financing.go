@@ -0,0 +1,84 @@
+/*
+ * Payment Registration System - Financing Handlers
+ * ------------------------------------------------
+ * This file defines the HTTP handlers for simulating the installment plans of purchases under the
+ * flat, French and zero-interest models.
+ *
+ * Created: Apr. 08, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FinancingHandler struct {
+	financing services.FinancingService
+}
+
+// NewFinancingHandler creates a new instance of FinancingHandler with the provided financing service.
+func NewFinancingHandler(financing services.FinancingService) *FinancingHandler {
+	return &FinancingHandler{
+		financing: financing,
+	}
+}
+
+// SimulateFinancing computes the installment plan of an amount without registering a purchase.
+//
+//	@Summary		Simulate a financing
+//	@Description	Computes the quota schedule of an amount, with the capital and interest of each quota, the final amount, and the nominal annual rate (TNA), effective annual rate (TEA) and total financial cost (CFT). The terms are those of a financing promotion when promotion_code is given, otherwise number_of_quotas, interest and interest_model (flat, french or zero; flat by default). Under the flat model the interest is added once over the plan; under the French model it is a nominal annual rate charged monthly on the outstanding capital.
+//	@Tags			Financing
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.FinancingSimulationRequest	true	"Amount and financing terms"
+//	@Success		200		{object}	models.InstallmentPlan				"Installment plan computed successfully"
+//	@Failure		400		{object}	map[string]interface{}				"Invalid request body or financing terms"
+//	@Failure		404		{object}	map[string]interface{}				"Promotion not found"
+//	@Failure		500		{object}	map[string]interface{}				"Failed to simulate financing"
+//	@Router			/sql/financing/simulate [post]
+//	@Router			/no-sql/financing/simulate [post]
+func (h *FinancingHandler) SimulateFinancing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("SimulateFinancing request from IP: %s", c.IP())
+
+		var request models.FinancingSimulationRequest
+		if err := c.BodyParser(&request); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		// The purchase date is optional, the service defaults it to now
+		var purchaseDate time.Time
+		if request.PurchaseDate != "" {
+			parsedDate, err := time.Parse(time.RFC3339, request.PurchaseDate)
+			if err != nil {
+				logger.Warn("Invalid purchase date format")
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid purchase_date format. Expected RFC3339 format.",
+				})
+			}
+			purchaseDate = parsedDate
+		}
+
+		plan, err := h.financing.Simulate(c.UserContext(), request, purchaseDate)
+		if err != nil {
+			logger.Error("Failed to simulate financing: %v", err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(plan)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Payment Registration System - Payment Handlers
+ * ----------------------------------------------
+ * This file defines the HTTP handlers for the payments of payment summaries and for the
+ * outstanding balance of cards, with the paid, partially paid and overdue items.
+ *
+ * Created: Apr. 14, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentHandler struct {
+	payments services.PaymentService
+}
+
+// NewPaymentHandler creates a new instance of PaymentHandler with the provided payment service.
+func NewPaymentHandler(payments services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		payments: payments,
+	}
+}
+
+// RecordPayment records a payment of the payment summary of a card for a month.
+//
+//	@Summary		Record a payment
+//	@Description	Records a payment of the payment summary of the card for the given month. The amount is allocated to the billed quotas first and then to the single-payment purchases, and cannot exceed what is left to pay.
+//	@Tags			Payment
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Param			month		path		int						true	"Month (1-12)"
+//	@Param			year		path		int						true	"Year (e.g., 2025)"
+//	@Param			request		body		models.PaymentRequest	true	"Amount paid and optional payment date"
+//	@Success		201			{object}	models.Payment			"Payment recorded successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid parameters or amount"
+//	@Failure		404			{object}	map[string]interface{}	"Card or payment summary not found"
+//	@Failure		409			{object}	map[string]interface{}	"Payment already recorded"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to record payment"
+//	@Router			/sql/cards/summary/{cardNumber}/{month}/{year}/payments [post]
+//	@Router			/no-sql/cards/summary/{cardNumber}/{month}/{year}/payments [post]
+func (h *PaymentHandler) RecordPayment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("RecordPayment request from IP: %s", c.IP())
+
+		cardNumber := c.Params("cardNumber")
+		month, err := strconv.Atoi(c.Params("month"))
+		if err != nil {
+			logger.Warn("Invalid month parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid month parameter",
+			})
+		}
+		year, err := strconv.Atoi(c.Params("year"))
+		if err != nil {
+			logger.Warn("Invalid year parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid year parameter",
+			})
+		}
+
+		var request models.PaymentRequest
+		if err := c.BodyParser(&request); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		payment, err := h.payments.RecordPayment(c.UserContext(), cardNumber, month, year, request)
+		if err != nil {
+			logger.Error("Failed to record payment of the %02d/%d summary of card %s: %v", month, year, cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Payment %s of the %02d/%d summary of card %s recorded successfully", payment.Code, month, year, cardNumber)
+		return c.Status(fiber.StatusCreated).JSON(payment)
+	}
+}
+
+// GetPayments retrieves the payments of the payment summary of a card for a month.
+//
+//	@Summary		Get the payments of a payment summary
+//	@Description	Retrieves the payments of the payment summary of the card for the given month, ordered by payment date, with their allocations.
+//	@Tags			Payment
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Param			month		path		int						true	"Month (1-12)"
+//	@Param			year		path		int						true	"Year (e.g., 2025)"
+//	@Success		200			{array}		models.Payment			"Payments retrieved successfully"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid parameters"
+//	@Failure		404			{object}	map[string]interface{}	"Card or payment summary not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to retrieve payments"
+//	@Router			/sql/cards/summary/{cardNumber}/{month}/{year}/payments [get]
+//	@Router			/no-sql/cards/summary/{cardNumber}/{month}/{year}/payments [get]
+func (h *PaymentHandler) GetPayments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetPayments request from IP: %s", c.IP())
+
+		cardNumber := c.Params("cardNumber")
+		month, err := strconv.Atoi(c.Params("month"))
+		if err != nil {
+			logger.Warn("Invalid month parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid month parameter",
+			})
+		}
+		year, err := strconv.Atoi(c.Params("year"))
+		if err != nil {
+			logger.Warn("Invalid year parameter")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid year parameter",
+			})
+		}
+
+		payments, err := h.payments.GetPayments(c.UserContext(), cardNumber, month, year)
+		if err != nil {
+			logger.Error("Failed to retrieve payments of the %02d/%d summary of card %s: %v", month, year, cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(payments)
+	}
+}
+
+// GetCardBalance retrieves the outstanding balance of a card.
+//
+//	@Summary		Get the outstanding balance of a card
+//	@Description	Retrieves what is left to pay of the payment summaries of the card, with the status of each summary and item. Items left to pay after the first expiration date are overdue.
+//	@Tags			Payment
+//	@Accept			json
+//	@Produce		json
+//	@Param			cardNumber	path		string					true	"Card Number"
+//	@Success		200			{object}	models.CardBalance		"Balance retrieved successfully"
+//	@Failure		404			{object}	map[string]interface{}	"Card not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to retrieve balance"
+//	@Router			/sql/cards/{cardNumber}/balance [get]
+//	@Router			/no-sql/cards/{cardNumber}/balance [get]
+func (h *PaymentHandler) GetCardBalance() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetCardBalance request from IP: %s", c.IP())
+
+		cardNumber := c.Params("cardNumber")
+		balance, err := h.payments.GetCardBalance(c.UserContext(), cardNumber)
+		if err != nil {
+			logger.Error("Failed to retrieve balance of card %s: %v", cardNumber, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(balance)
+	}
+}
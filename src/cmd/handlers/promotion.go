@@ -12,6 +12,7 @@ package handlers
 import (
 	"time"
 
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"github.com/gofiber/fiber/v2"
@@ -70,7 +71,7 @@ func (h *PromotionHandler) GetAvailablePromotionsByStoreAndDateRange() fiber.Han
 		}
 
 		// Call the service to get the available promotions
-		financingPromotions, discountPromotions, err := h.promotion.GetAvailablePromotionsByStoreAndDateRange(cuit, startDate, endDate)
+		financingPromotions, discountPromotions, err := h.promotion.GetAvailablePromotionsByStoreAndDateRange(c.UserContext(), cuit, startDate, endDate)
 		if err != nil {
 			logger.Error("Failed to retrieve available promotions: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -109,7 +110,7 @@ func (h *PromotionHandler) GetMostUsedPromotion() fiber.Handler {
 		logger.Info("GetMostUsedPromotion request from IP: %s", c.IP())
 
 		// Call the service to get the most used promotion
-		promotion, err := h.promotion.GetMostUsedPromotion()
+		promotion, err := h.promotion.GetMostUsedPromotion(c.UserContext())
 		if err != nil {
 			logger.Error("Failed to retrieve most used promotion: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -128,3 +129,148 @@ func (h *PromotionHandler) GetMostUsedPromotion() fiber.Handler {
 		}
 	}
 }
+
+// GetPromotionByCode retrieves a promotion by its code.
+//
+//	@Summary		Get a promotion by code
+//	@Description	Retrieves a discount or financing promotion, including logically deleted ones, together with its status.
+//	@Tags			Promotion
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string					true	"Promotion Code"
+//	@Success		200		{object}	models.PromotionDetail	"Promotion retrieved successfully"
+//	@Failure		404		{object}	map[string]interface{}	"Promotion not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to retrieve promotion"
+//	@Router			/sql/promotions/{code} [get]
+//	@Router			/no-sql/promotions/{code} [get]
+func (h *PromotionHandler) GetPromotionByCode() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetPromotionByCode request from IP: %s", c.IP())
+
+		code := c.Params("code")
+		promotion, err := h.promotion.GetPromotionByCode(c.UserContext(), code)
+		if err != nil {
+			logger.Error("Failed to retrieve promotion %s: %v", code, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Promotion %s retrieved successfully", code)
+		return c.JSON(promotion)
+	}
+}
+
+// GetBankPromotions retrieves the promotions of a bank filtered by status.
+//
+//	@Summary		Get the promotions of a bank
+//	@Description	Retrieves the financing and discount promotions of a bank with the given status: active (not deleted and not expired, the default), deleted or expired.
+//	@Tags			Promotion
+//	@Accept			json
+//	@Produce		json
+//	@Param			cuit	path		string					true	"Bank CUIT"
+//	@Param			status	query		string					false	"Promotion status"	Enums(active, deleted, expired)
+//	@Success		200		{object}	map[string]interface{}	"Bank promotions retrieved successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Unknown status"
+//	@Failure		404		{object}	map[string]interface{}	"Bank not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to retrieve bank promotions"
+//	@Router			/sql/banks/{cuit}/promotions [get]
+//	@Router			/no-sql/banks/{cuit}/promotions [get]
+func (h *PromotionHandler) GetBankPromotions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("GetBankPromotions request from IP: %s", c.IP())
+
+		cuit := c.Params("cuit")
+		financingPromotions, discountPromotions, err := h.promotion.GetBankPromotions(c.UserContext(), cuit, c.Query("status"))
+		if err != nil {
+			logger.Error("Failed to retrieve promotions of bank %s: %v", cuit, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Promotions of bank %s retrieved successfully", cuit)
+		return c.JSON(fiber.Map{
+			"financing_promotions": financingPromotions,
+			"discount_promotions":  discountPromotions,
+		})
+	}
+}
+
+// UpdatePromotion edits an existing promotion.
+//
+//	@Summary		Update a promotion
+//	@Description	Edits the title, comments and rates of a promotion. Only the fields present in the body are changed; discount fields only apply to discounts and financing fields only to financings.
+//	@Tags			Promotion
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string					true	"Promotion Code"
+//	@Param			request	body		models.PromotionUpdate	true	"Changes to apply"
+//	@Success		200		{object}	models.PromotionDetail	"Promotion updated successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or changes"
+//	@Failure		404		{object}	map[string]interface{}	"Promotion not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to update promotion"
+//	@Router			/sql/promotions/{code} [put]
+//	@Router			/no-sql/promotions/{code} [put]
+func (h *PromotionHandler) UpdatePromotion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("UpdatePromotion request from IP: %s", c.IP())
+
+		var update models.PromotionUpdate
+		if err := c.BodyParser(&update); err != nil {
+			logger.Warn("Invalid request body: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		code := c.Params("code")
+		promotion, err := h.promotion.UpdatePromotion(c.UserContext(), code, update)
+		if err != nil {
+			logger.Error("Failed to update promotion %s: %v", code, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Promotion %s updated successfully", code)
+		return c.JSON(promotion)
+	}
+}
+
+// RestorePromotion undoes the logical delete of a promotion.
+//
+//	@Summary		Restore a deleted promotion
+//	@Description	Clears the logical delete of a promotion so that it applies to purchases again while it is valid.
+//	@Tags			Promotion
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string					true	"Promotion Code"
+//	@Success		200		{object}	models.PromotionDetail	"Promotion restored successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Promotion is not deleted"
+//	@Failure		404		{object}	map[string]interface{}	"Promotion not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to restore promotion"
+//	@Router			/sql/promotions/{code}/restore [post]
+//	@Router			/no-sql/promotions/{code}/restore [post]
+func (h *PromotionHandler) RestorePromotion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Log request
+		logger.Info("RestorePromotion request from IP: %s", c.IP())
+
+		code := c.Params("code")
+		promotion, err := h.promotion.RestorePromotion(c.UserContext(), code)
+		if err != nil {
+			logger.Error("Failed to restore promotion %s: %v", code, err)
+			return c.Status(statusFromError(err)).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Promotion %s restored successfully", code)
+		return c.JSON(promotion)
+	}
+}
@@ -68,7 +68,7 @@ func (h *StoreHandler) GetStoreWithHighestRevenueByMonth() fiber.Handler {
 		}
 
 		// Call the service to get the store with the highest revenue
-		store, err := h.store.GetStoreWithHighestRevenueByMonth(month, year)
+		store, err := h.store.GetStoreWithHighestRevenueByMonth(c.UserContext(), month, year)
 		if err != nil {
 			logger.Error("Failed to retrieve store with highest revenue: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -2,7 +2,9 @@
  * Payment Registration System - Main Server
  * --------------------------------------------------
  * This file is the entry point for the Payment Registration System API.
- * It initializes the server and runs it with the specified configuration.
+ * It initializes the server and runs it with the specified configuration, or
+ * runs the migrate subcommand on the SQL database, the sync subcommand that
+ * replicates the SQL database into MongoDB, or the verify subcommand that compares them.
  *
  * Created: Oct. 19, 2024
  * License: GNU General Public License v3.0
@@ -13,8 +15,12 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
 
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/cmd/migrate"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/cmd/replicate"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/cmd/server"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/cmd/verify"
 	_ "github.com/GabrielEValenzuela/Payment-Registration-System/src/docs"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/config"
 )
@@ -31,6 +37,30 @@ import (
 // @BasePath		/v1
 func main() {
 
+	// The migrate subcommand manages the SQL schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Run(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("❌ Migration failed: %v", err)
+		}
+		return
+	}
+
+	// The sync subcommand replicates the SQL database into MongoDB and exits
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		if err := replicate.Run(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("❌ Sync failed: %v", err)
+		}
+		return
+	}
+
+	// The verify subcommand compares the SQL database with MongoDB and exits
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := verify.Run(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("❌ Verification failed: %v", err)
+		}
+		return
+	}
+
 	// Parse command-line flags
 	configPath := flag.String("config", "./config.yml", "path to the configuration file")
 	flag.Parse()
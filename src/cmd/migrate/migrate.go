@@ -0,0 +1,114 @@
+/*
+ * Payment Registration System - Migrate Command
+ * --------------------------------------------------
+ * This file implements the migrate subcommand, which applies, reverts and lists the versioned
+ * migrations of the SQL database configured in sqldb.
+ *
+ * Usage:
+ *   main migrate [-config path] [-steps n] up|down|status
+ *
+ * Created: Mar. 18, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package migrate
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/config"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/migrations"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+/*
+ * Run
+ * --------------------------------------------------
+ * Parses the arguments of the migrate subcommand and runs the requested action against the SQL database.
+ *
+ * Params:
+ * - args ([]string): Arguments after "migrate".
+ * - out (io.Writer): Destination of the report of the action.
+ *
+ * Returns:
+ * - error: If the arguments are invalid or a migration fails.
+ */
+func Run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configPath := flags.String("config", "./config.yml", "path to the configuration file")
+	steps := flags.Int("steps", 1, "number of migrations to revert with down")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	action := "up"
+	if flags.NArg() > 1 {
+		return fmt.Errorf("expected a single action, got %v", flags.Args())
+	}
+	if flags.NArg() == 1 {
+		action = flags.Arg(0)
+	}
+	if action != "up" && action != "down" && action != "status" {
+		return fmt.Errorf("unknown migrate action %q, expected up, down or status", action)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.IsProduction, cfg.LogPath)
+	defer logger.Sync()
+
+	db, err := relational.OpenSQLDB(cfg.SQLDb.Dialect, cfg.SQLDb.DSN)
+	if err != nil {
+		return err
+	}
+	defer relational.CloseDB(db)
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied  %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "the schema is up to date")
+		}
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(out, "no applied migrations to revert")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	}
+
+	return nil
+}
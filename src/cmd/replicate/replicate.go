@@ -0,0 +1,86 @@
+/*
+ * Payment Registration System - Sync Command
+ * --------------------------------------------------
+ * This file implements the sync subcommand, which replicates the SQL database configured in sqldb into the
+ * MongoDB database configured in nosqldb. An incremental sync replicates the rows changed since the last
+ * sync, a full sync replicates every row. An interrupted sync resumes from its last replicated batch.
+ *
+ * Usage:
+ *   main sync [-config path] [-full] [-batch n] [-overlap duration]
+ *
+ * Created: Mar. 24, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package replicate
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/config"
+	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/replication"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+/*
+ * Run
+ * --------------------------------------------------
+ * Parses the arguments of the sync subcommand and replicates the SQL database into MongoDB.
+ *
+ * Params:
+ * - args ([]string): Arguments after "sync".
+ * - out (io.Writer): Destination of the report of the sync.
+ *
+ * Returns:
+ * - error: If the arguments are invalid or the sync fails.
+ */
+func Run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	configPath := flags.String("config", "./config.yml", "path to the configuration file")
+	full := flags.Bool("full", false, "replicate every row instead of the rows changed since the last sync")
+	batchSize := flags.Int("batch", replication.DefaultBatchSize, "number of rows replicated at once")
+	overlap := flags.Duration("overlap", replication.DefaultOverlap, "how far behind its checkpoint the sync of a table starts")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("the batch size must be positive, got %d", *batchSize)
+	}
+	if *overlap < 0 {
+		return fmt.Errorf("the overlap must not be negative, got %s", *overlap)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.IsProduction, cfg.LogPath)
+	defer logger.Sync()
+
+	source, err := relational.OpenSQLDB(cfg.SQLDb.Dialect, cfg.SQLDb.DSN)
+	if err != nil {
+		return err
+	}
+	defer relational.CloseDB(source)
+
+	// The target is never cleaned, whatever nosqldb.clean says
+	target, err := nonrelational.NewMongoDB(cfg.NoSQLDb.URI, cfg.NoSQLDb.Database, false)
+	if err != nil {
+		return err
+	}
+	defer nonrelational.CloseMongoDB(target.Client())
+
+	reports, err := replication.NewSyncer(source, target, *batchSize, *overlap).Run(context.Background(), *full)
+	for _, report := range reports {
+		fmt.Fprintf(out, "%-26s %d rows\n", report.Table, report.Rows)
+	}
+	return err
+}
@@ -2,7 +2,7 @@
  * Payment Registration System - Server Configuration
  * --------------------------------------------------
  * This file defines the core server logic, including:
- * - Database initialization (SQL & NoSQL)
+ * - Database initialization (SQL, NoSQL & in-memory)
  * - Fiber-based HTTP server setup
  * - Route configuration for API endpoints
  * - Graceful shutdown handling
@@ -25,9 +25,14 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/cmd/handlers"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/config"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/consistency"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/dualwrite"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/memory"
 	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
 	non_relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational/repository"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/migrations"
 	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"github.com/gofiber/fiber/v2"
@@ -44,13 +49,17 @@ import (
  * Represents the main application server, including:
  * - Fiber HTTP server
  * - Configuration settings
- * - SQL (MySQL) and NoSQL (MongoDB) database connections
+ * - SQL (MySQL, SQLite or PostgreSQL), NoSQL (MongoDB) and in-memory database connections
+ * - The context every request derives from, cancelled when a graceful shutdown times out
  */
 type Server struct {
-	app     *fiber.App
-	cfg     *config.Config
-	sqlDb   *gorm.DB
-	noSqlDb *mongo.Database
+	app            *fiber.App
+	cfg            *config.Config
+	sqlDb          *gorm.DB
+	noSqlDb        *mongo.Database
+	memoryDb       *memory.Database
+	requests       context.Context
+	cancelRequests context.CancelFunc
 }
 
 /*
@@ -65,15 +74,18 @@ type Server struct {
  * - *Server: A new server instance.
  */
 func NewServer(cfg *config.Config) *Server {
+	requests, cancelRequests := context.WithCancel(context.Background())
 	return &Server{
-		cfg: cfg,
+		cfg:            cfg,
+		requests:       requests,
+		cancelRequests: cancelRequests,
 	}
 }
 
 /*
  * InitDatabases
  * --------------------------------------------------
- * Initializes both SQL (MySQL) and NoSQL (MongoDB) databases.
+ * Initializes the configured storage backends: SQL (MySQL, SQLite or PostgreSQL), NoSQL (MongoDB) and in-memory.
  * Also runs data initialization if required.
  */
 func (srv *Server) InitDatabases() {
@@ -82,37 +94,75 @@ func (srv *Server) InitDatabases() {
 	maxDelay := 60 * time.Second
 
 	for {
-		// Attempt to connect to MySQL
-		sqlDb, err := relational.NewMySQLDB(srv.cfg.SQLDb.DSN, srv.cfg.SQLDb.Clean)
-		if err != nil {
-			logger.Warn("Failed to initialize MySQL database: %v. Retrying in %v...", err, delay)
-			time.Sleep(delay)
-			if delay < maxDelay {
-				delay *= 2 // Exponential backoff
+		// Attempt to connect to the SQL database
+		if srv.cfg.Storage.Enabled(config.BackendSQL) && srv.sqlDb == nil {
+			sqlDb, err := srv.openSQLDB()
+			if err != nil {
+				logger.Warn("Failed to initialize %s database: %v. Retrying in %v...", srv.cfg.SQLDb.Dialect, err, delay)
+				time.Sleep(delay)
+				if delay < maxDelay {
+					delay *= 2 // Exponential backoff
+				}
+				continue
 			}
-			continue
-		}
 
-		srv.sqlDb = sqlDb
-		logger.Info("Successfully connected to MySQL database")
+			srv.sqlDb = sqlDb
+			logger.Info("Successfully connected to %s database", srv.cfg.SQLDb.Dialect)
+		}
 
 		// Attempt to connect to MongoDB
-		mongoDb, err := nonrelational.NewMongoDB(srv.cfg.NoSQLDb.URI, srv.cfg.NoSQLDb.Database, srv.cfg.NoSQLDb.Clean)
-		if err != nil {
-			logger.Warn("Failed to initialize MongoDB database: %v. Retrying in %v...", err, delay)
-			time.Sleep(delay)
-			if delay < maxDelay {
-				delay *= 2
+		if srv.cfg.Storage.Enabled(config.BackendNoSQL) && srv.noSqlDb == nil {
+			mongoDb, err := nonrelational.NewMongoDB(srv.cfg.NoSQLDb.URI, srv.cfg.NoSQLDb.Database, srv.cfg.NoSQLDb.Clean)
+			if err != nil {
+				logger.Warn("Failed to initialize MongoDB database: %v. Retrying in %v...", err, delay)
+				time.Sleep(delay)
+				if delay < maxDelay {
+					delay *= 2
+				}
+				continue
 			}
-			continue
-		}
 
-		srv.noSqlDb = mongoDb
-		logger.Info("Successfully connected to MongoDB database")
+			srv.noSqlDb = mongoDb
+			logger.Info("Successfully connected to MongoDB database")
+		}
 
-		// Both databases are successfully connected, break the loop
+		// All configured databases are successfully connected, break the loop
 		break
 	}
+
+	// The in-memory database starts empty on every run
+	if srv.cfg.Storage.Enabled(config.BackendMemory) {
+		srv.memoryDb = memory.NewMemoryDB()
+		logger.Info("Initialized in-memory database")
+	}
+}
+
+/*
+ * openSQLDB
+ * --------------------------------------------------
+ * Opens the SQL database. With sqldb.auto_migrate the schema is created by AutoMigrate, as in development,
+ * otherwise every migration must have been applied with the migrate command.
+ *
+ * Returns:
+ * - *gorm.DB: The database connection.
+ * - error: If the connection fails or migrations are pending.
+ */
+func (srv *Server) openSQLDB() (*gorm.DB, error) {
+	if srv.cfg.SQLDb.AutoMigrate {
+		return relational.NewSQLDB(srv.cfg.SQLDb.Dialect, srv.cfg.SQLDb.DSN, srv.cfg.SQLDb.Clean)
+	}
+
+	db, err := relational.OpenSQLDB(srv.cfg.SQLDb.Dialect, srv.cfg.SQLDb.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrations.RequireUpToDate(db); err != nil {
+		_ = relational.CloseDB(db)
+		return nil, err
+	}
+
+	return db, nil
 }
 
 /*
@@ -142,10 +192,14 @@ func (srv *Server) Run() error {
 	<-quit
 	logger.Info("Shutting down server...")
 
-	_, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := srv.app.Shutdown(); err != nil {
+	// Requests still running when the grace period ends are cancelled, stopping their database operations
+	stop := context.AfterFunc(ctx, srv.cancelRequests)
+	defer stop()
+
+	if err := srv.app.ShutdownWithContext(ctx); err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
@@ -179,13 +233,61 @@ func (srv *Server) initFiber() {
 		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 	}))
 
+	// Give every request its own context, cancelled when the request completes or when a forced shutdown cancels the
+	// server's. The context of the fasthttp request is not used: fasthttp does not notice clients disconnecting, and
+	// cancels it as soon as a graceful shutdown starts, which would stop requests the grace period lets complete.
+	srv.app.Use(func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithCancel(srv.requests)
+		defer cancel()
+		c.SetUserContext(ctx)
+		return c.Next()
+	})
+
 	srv.setupRoutes()
 }
 
+/*
+ * routeHandlers
+ * --------------------------------------------------
+ * Groups the handlers serving the API routes of a single storage backend.
+ */
+type routeHandlers struct {
+	bank         *handlers.BankHandler
+	billing      *handlers.BillingHandler
+	card         *handlers.CardHandler
+	promotion    *handlers.PromotionHandler
+	customer     *handlers.CustomerHandler
+	store        *handlers.StoreHandler
+	exchangeRate *handlers.ExchangeRateHandler
+	financing    *handlers.FinancingHandler
+	payment      *handlers.PaymentHandler
+}
+
+/*
+ * newRouteHandlers
+ * --------------------------------------------------
+ * Builds the services and handlers of a storage backend on top of its repositories.
+ */
+func newRouteHandlers(bankRepo storage.IBankStorage, cardRepo storage.ICardStorage, promotionRepo storage.IPromotionStorage, customerRepo storage.ICustomerStorage, storeRepo storage.IStoreStorage, exchangeRateRepo storage.IExchangeRateStorage, paymentRepo storage.IPaymentStorage) routeHandlers {
+	return routeHandlers{
+		bank:         handlers.NewBankHandler(services.NewBankService(bankRepo)),
+		billing:      handlers.NewBillingHandler(services.NewBillingService(bankRepo, cardRepo, exchangeRateRepo)),
+		card:         handlers.NewCardHandler(services.NewCardService(cardRepo, bankRepo, services.NewPromotionEngine(promotionRepo))),
+		promotion:    handlers.NewPromotionHandler(services.NewPromotionService(promotionRepo)),
+		customer:     handlers.NewCustomerHandler(services.NewCustomerService(customerRepo)),
+		store:        handlers.NewStoreHandler(services.NewStoreService(storeRepo)),
+		exchangeRate: handlers.NewExchangeRateHandler(services.NewExchangeRateService(exchangeRateRepo)),
+		financing:    handlers.NewFinancingHandler(services.NewFinancingService(promotionRepo)),
+		payment:      handlers.NewPaymentHandler(services.NewPaymentService(cardRepo, paymentRepo)),
+	}
+}
+
 /*
  * setupRoutes
  * --------------------------------------------------
- * Configures API routes for both SQL and NoSQL services.
+ * Configures API routes for every configured storage backend: /v1/sql, /v1/no-sql and /v1/memory,
+ * and the admin routes under /v1/admin when both the SQL and NoSQL backends are connected. With dual-write
+ * enabled, the same routes are also mounted directly under /v1, writing to both the SQL and NoSQL backends.
  */
 func (srv *Server) setupRoutes() {
 	srv.app.Get("/swagger/*", swagger.HandlerDefault)
@@ -194,63 +296,173 @@ func (srv *Server) setupRoutes() {
 		return c.SendString("Welcome to the Payment Registration System!")
 	})
 
-	// Initialize handlers
-	bankHandlerRelational := handlers.NewBankHandler(services.NewBankService(relational_repository.NewBankRelationalRepository(srv.sqlDb)))
-	bankHandlerNonRelational := handlers.NewBankHandler(services.NewBankService(non_relational_repository.NewBankNonRelationalRepository(srv.noSqlDb)))
-
-	cardHandlerRelational := handlers.NewCardHandler(services.NewCardService(relational_repository.NewCardRelationalRepository(srv.sqlDb)))
-	cardHandlerNonRelational := handlers.NewCardHandler(services.NewCardService(non_relational_repository.NewCardNonRelationalRepository(srv.noSqlDb)))
-
-	promotionHandlerRelation := handlers.NewPromotionHandler(services.NewPromotionService(relational_repository.NewPromotionRelationRepository(srv.sqlDb)))
-	promotionHandlerNonRelation := handlers.NewPromotionHandler(services.NewPromotionService(non_relational_repository.NewPromotionNonRelationalRepository(srv.noSqlDb)))
-
-	storeHandlerRelation := handlers.NewStoreHandler(services.NewStoreService(relational_repository.NewStoreRelationalRepository(srv.sqlDb)))
-	storeHandlerNonRelation := handlers.NewStoreHandler(services.NewStoreService(non_relational_repository.NewStoreNonRelationalRepository(srv.noSqlDb)))
-
 	// API version group
 	apiGroup := srv.app.Group("/v1")
 
 	// SQL routes group
-	sqlGroup := apiGroup.Group("/sql")
+	if srv.sqlDb != nil {
+		sql := srv.sqlBackend()
+		registerRoutes(apiGroup.Group("/"+config.BackendSQL), newRouteHandlers(sql.Banks, sql.Cards, sql.Promotions, sql.Customers, sql.Stores, sql.ExchangeRates, sql.Payments), srv.cfg.Timeouts)
+	}
+
 	// NoSQL routes group
-	mongoGroup := apiGroup.Group("/no-sql")
+	if srv.noSqlDb != nil {
+		noSQL := srv.noSQLBackend()
+		registerRoutes(apiGroup.Group("/"+config.BackendNoSQL), newRouteHandlers(noSQL.Banks, noSQL.Cards, noSQL.Promotions, noSQL.Customers, noSQL.Stores, noSQL.ExchangeRates, noSQL.Payments), srv.cfg.Timeouts)
+	}
+
+	// Admin routes, comparing the SQL and NoSQL stores
+	if srv.sqlDb != nil && srv.noSqlDb != nil {
+		admin := handlers.NewAdminHandler(services.NewConsistencyService(
+			consistency.NewSQLSource(srv.sqlDb),
+			consistency.NewMongoSource(srv.noSqlDb),
+		))
+		apiGroup.Get("/admin/consistency", handlers.WithDeadline(srv.cfg.Timeouts.For("check_consistency")), admin.CheckConsistency())
+	}
+
+	// In-memory routes group
+	if srv.memoryDb != nil {
+		registerRoutes(apiGroup.Group("/"+config.BackendMemory), newRouteHandlers(
+			memory.NewBankMemoryRepository(srv.memoryDb),
+			memory.NewCardMemoryRepository(srv.memoryDb),
+			memory.NewPromotionMemoryRepository(srv.memoryDb),
+			memory.NewCustomerMemoryRepository(srv.memoryDb),
+			memory.NewStoreMemoryRepository(srv.memoryDb),
+			memory.NewExchangeRateMemoryRepository(srv.memoryDb),
+			memory.NewPaymentMemoryRepository(srv.memoryDb),
+		), srv.cfg.Timeouts)
+	}
+
+	// Unified routes, writing to both the SQL and NoSQL backends
+	if dualWrite := srv.cfg.Storage.DualWrite; dualWrite.Enabled && srv.sqlDb != nil && srv.noSqlDb != nil {
+		primary, secondary := srv.sqlBackend(), srv.noSQLBackend()
+		if dualWrite.Primary == config.BackendNoSQL {
+			primary, secondary = secondary, primary
+		}
+		registerRoutes(apiGroup, newRouteHandlers(
+			dualwrite.NewBankDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewCardDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewPromotionDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewCustomerDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewStoreDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewExchangeRateDualWriteRepository(primary, secondary, dualWrite.Fallback),
+			dualwrite.NewPaymentDualWriteRepository(primary, secondary, dualWrite.Fallback),
+		), srv.cfg.Timeouts)
+		logger.Info("Unified routes mounted under /v1, writing to %s first", primary.Name)
+	}
+}
+
+/*
+ * sqlBackend
+ * --------------------------------------------------
+ * Builds the repositories of the SQL backend.
+ */
+func (srv *Server) sqlBackend() dualwrite.Backend {
+	return dualwrite.Backend{
+		Name:          config.BackendSQL,
+		Banks:         relational_repository.NewBankRelationalRepository(srv.sqlDb),
+		Cards:         relational_repository.NewCardRelationalRepository(srv.sqlDb),
+		Promotions:    relational_repository.NewPromotionRelationRepository(srv.sqlDb),
+		Customers:     relational_repository.NewCustomerRelationalRepository(srv.sqlDb),
+		Stores:        relational_repository.NewStoreRelationalRepository(srv.sqlDb),
+		ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(srv.sqlDb),
+		Payments:      relational_repository.NewPaymentRelationalRepository(srv.sqlDb),
+		Compensation:  relational_repository.NewCompensationRelationalRepository(srv.sqlDb),
+	}
+}
+
+/*
+ * noSQLBackend
+ * --------------------------------------------------
+ * Builds the repositories of the NoSQL backend.
+ */
+func (srv *Server) noSQLBackend() dualwrite.Backend {
+	return dualwrite.Backend{
+		Name:          config.BackendNoSQL,
+		Banks:         non_relational_repository.NewBankNonRelationalRepository(srv.noSqlDb),
+		Cards:         non_relational_repository.NewCardNonRelationalRepository(srv.noSqlDb),
+		Promotions:    non_relational_repository.NewPromotionNonRelationalRepository(srv.noSqlDb),
+		Customers:     non_relational_repository.NewCustomerNonRelationalRepository(srv.noSqlDb),
+		Stores:        non_relational_repository.NewStoreNonRelationalRepository(srv.noSqlDb),
+		ExchangeRates: non_relational_repository.NewExchangeRateNonRelationalRepository(srv.noSqlDb),
+		Payments:      non_relational_repository.NewPaymentNonRelationalRepository(srv.noSqlDb),
+		Compensation:  non_relational_repository.NewCompensationNonRelationalRepository(srv.noSqlDb),
+	}
+}
+
+/*
+ * registerRoutes
+ * --------------------------------------------------
+ * Registers every API route of a storage backend on its group, each bounded by the deadline configured for
+ * its operation.
+ */
+func registerRoutes(group fiber.Router, h routeHandlers, timeouts config.TimeoutConfig) {
+	deadline := func(operation string) fiber.Handler {
+		return handlers.WithDeadline(timeouts.For(operation))
+	}
 
 	// -- Bank Routes --
-	sqlGroup.Post("/promotions/add-promotion", bankHandlerRelational.AddFinancingPromotionToBank())
-	sqlGroup.Patch("/promotions/financing/:code", bankHandlerRelational.ExtendFinancingPromotionValidity())
-	sqlGroup.Delete("/promotions/financing/:code", bankHandlerRelational.DeleteFinancingPromotion())
-	sqlGroup.Patch("/promotions/discount/:code", bankHandlerRelational.ExtendDiscountPromotionValidity())
-	sqlGroup.Delete("/promotions/discount/:code", bankHandlerRelational.DeleteDiscountPromotion())
-	sqlGroup.Get("/banks/customers/count", bankHandlerRelational.GetBankCustomerCounts())
-
-	mongoGroup.Post("/promotions/add-promotion", bankHandlerNonRelational.AddFinancingPromotionToBank())
-	mongoGroup.Patch("/promotions/financing/:code", bankHandlerNonRelational.ExtendFinancingPromotionValidity())
-	mongoGroup.Delete("/promotions/financing/:code", bankHandlerNonRelational.DeleteFinancingPromotion())
-	mongoGroup.Patch("/promotions/discount/:code", bankHandlerNonRelational.ExtendDiscountPromotionValidity())
-	mongoGroup.Delete("/promotions/discount/:code", bankHandlerNonRelational.DeleteDiscountPromotion())
-	mongoGroup.Get("/banks/customers/count", bankHandlerNonRelational.GetBankCustomerCounts())
+	group.Post("/promotions/add-promotion", deadline("add_financing_promotion_to_bank"), h.bank.AddFinancingPromotionToBank())
+	group.Post("/promotions/discount", deadline("add_discount_promotion_to_bank"), h.bank.AddDiscountPromotionToBank())
+	group.Patch("/promotions/financing/:code", deadline("extend_financing_promotion_validity"), h.bank.ExtendFinancingPromotionValidity())
+	group.Delete("/promotions/financing/:code", deadline("delete_financing_promotion"), h.bank.DeleteFinancingPromotion())
+	group.Patch("/promotions/discount/:code", deadline("extend_discount_promotion_validity"), h.bank.ExtendDiscountPromotionValidity())
+	group.Delete("/promotions/discount/:code", deadline("delete_discount_promotion"), h.bank.DeleteDiscountPromotion())
+	group.Get("/banks/customers/count", deadline("get_bank_customer_counts"), h.bank.GetBankCustomerCounts())
+	group.Post("/banks", deadline("create_bank"), h.bank.CreateBank())
+	group.Get("/banks", deadline("get_banks"), h.bank.GetBanks())
+	group.Get("/banks/:cuit", deadline("get_bank_by_cuit"), h.bank.GetBankByCuit())
+	group.Put("/banks/:cuit", deadline("update_bank"), h.bank.UpdateBank())
 
 	// -- Card Routes --
-	sqlGroup.Get("/cards/summary/:cardNumber/:month/:year", cardHandlerRelational.GetPaymentSummary())
-	sqlGroup.Get("/cards/expiring/:day/:month/:year", cardHandlerRelational.GetCardsExpiringInNext30Days())
-	sqlGroup.Get("/cards/purchase/monthly/:cuit/:finalAmount/:paymentVoucher", cardHandlerRelational.GetPurchaseMonthly())
-	sqlGroup.Get("/cards/top", cardHandlerRelational.GetTop10CardsByPurchases())
-
-	mongoGroup.Get("/cards/summary/:cardNumber/:month/:year", cardHandlerNonRelational.GetPaymentSummary())
-	mongoGroup.Get("/cards/expiring/:day/:month/:year", cardHandlerNonRelational.GetCardsExpiringInNext30Days())
-	mongoGroup.Get("/cards/purchase/monthly/:cuit/:finalAmount/:paymentVoucher", cardHandlerNonRelational.GetPurchaseMonthly())
-	mongoGroup.Get("/cards/top", cardHandlerNonRelational.GetTop10CardsByPurchases())
+	group.Get("/cards/summary/:cardNumber/:month/:year", deadline("get_payment_summary"), h.card.GetPaymentSummary())
+	group.Get("/cards/expiring/:day/:month/:year", deadline("get_cards_expiring_in_next_30_days"), h.card.GetCardsExpiringInNext30Days())
+	group.Get("/cards/purchase/monthly/:cuit/:finalAmount/:paymentVoucher", deadline("get_purchase_monthly"), h.card.GetPurchaseMonthly())
+	group.Get("/cards/top", deadline("get_top_10_cards_by_purchases"), h.card.GetTop10CardsByPurchases())
+	group.Post("/cards/:cardNumber/purchases", deadline("register_purchase"), h.card.RegisterPurchase())
+	group.Post("/cards", deadline("issue_card"), h.card.IssueCard())
+	group.Post("/cards/:cardNumber/renew", deadline("renew_card"), h.card.RenewCard())
+	group.Post("/cards/:cardNumber/block", deadline("block_card"), h.card.BlockCard())
+	group.Post("/cards/:cardNumber/unblock", deadline("unblock_card"), h.card.UnblockCard())
+	group.Post("/cards/:cardNumber/cancel", deadline("cancel_card"), h.card.CancelCard())
+
+	// -- Billing Routes --
+	group.Put("/banks/:cuit/billing-cycle", deadline("configure_billing_cycle"), h.billing.ConfigureBillingCycle())
+	group.Get("/banks/:cuit/billing-cycle", deadline("get_billing_cycle"), h.billing.GetBillingCycle())
+	group.Post("/cards/summary/:cardNumber/:month/:year", deadline("close_cycle"), h.billing.CloseCycle())
+
+	// -- Payment Routes --
+	group.Post("/cards/summary/:cardNumber/:month/:year/payments", deadline("record_payment"), h.payment.RecordPayment())
+	group.Get("/cards/summary/:cardNumber/:month/:year/payments", deadline("get_payments"), h.payment.GetPayments())
+	group.Get("/cards/:cardNumber/balance", deadline("get_card_balance"), h.payment.GetCardBalance())
+
+	// -- Exchange Rate Routes --
+	group.Post("/exchange-rates", deadline("import_exchange_rates"), h.exchangeRate.ImportExchangeRates())
+	group.Get("/exchange-rates/:currency", deadline("get_exchange_rates"), h.exchangeRate.GetExchangeRates())
+	group.Get("/exchange-rates/:currency/:date", deadline("get_exchange_rate"), h.exchangeRate.GetExchangeRate())
+
+	// -- Financing Routes --
+	group.Post("/financing/simulate", deadline("simulate_financing"), h.financing.SimulateFinancing())
 
 	// -- Promotion Routes --
-	sqlGroup.Get("/promotions/:cuit/:startDate/:endDate", promotionHandlerRelation.GetAvailablePromotionsByStoreAndDateRange())
-	sqlGroup.Get("/promotions/most-used", promotionHandlerRelation.GetMostUsedPromotion())
-
-	mongoGroup.Get("/promotions/:cuit/:startDate/:endDate", promotionHandlerNonRelation.GetAvailablePromotionsByStoreAndDateRange())
-	mongoGroup.Get("/promotions/most-used", promotionHandlerNonRelation.GetMostUsedPromotion())
+	group.Get("/promotions/:cuit/:startDate/:endDate", deadline("get_available_promotions_by_store_and_date_range"), h.promotion.GetAvailablePromotionsByStoreAndDateRange())
+	group.Get("/promotions/most-used", deadline("get_most_used_promotion"), h.promotion.GetMostUsedPromotion())
+	group.Post("/promotions/simulate", deadline("simulate_promotions"), h.card.SimulatePurchase())
+	group.Get("/promotions/:code", deadline("get_promotion_by_code"), h.promotion.GetPromotionByCode())
+	group.Put("/promotions/:code", deadline("update_promotion"), h.promotion.UpdatePromotion())
+	group.Post("/promotions/:code/restore", deadline("restore_promotion"), h.promotion.RestorePromotion())
+	group.Get("/banks/:cuit/promotions", deadline("get_bank_promotions"), h.promotion.GetBankPromotions())
+
+	// -- Customer Routes --
+	group.Post("/customers", deadline("create_customer"), h.customer.CreateCustomer())
+	group.Get("/customers", deadline("get_customers"), h.customer.GetCustomers())
+	group.Get("/customers/:cuit", deadline("get_customer_by_cuit"), h.customer.GetCustomerByCuit())
+	group.Put("/customers/:cuit", deadline("update_customer"), h.customer.UpdateCustomer())
+	group.Put("/customers/:cuit/banks/:bankCuit", deadline("add_customer_to_bank"), h.customer.AddCustomerToBank())
+	group.Delete("/customers/:cuit/banks/:bankCuit", deadline("remove_customer_from_bank"), h.customer.RemoveCustomerFromBank())
 
 	// -- Store Routes --
-	sqlGroup.Get("/stores/highest-revenue/:month/:year", storeHandlerRelation.GetStoreWithHighestRevenueByMonth())
-	mongoGroup.Get("/stores/highest-revenue/:month/:year", storeHandlerNonRelation.GetStoreWithHighestRevenueByMonth())
+	group.Get("/stores/highest-revenue/:month/:year", deadline("get_store_with_highest_revenue_by_month"), h.store.GetStoreWithHighestRevenueByMonth())
 }
 
 /*
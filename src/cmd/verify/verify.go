@@ -0,0 +1,97 @@
+/*
+ * Payment Registration System - Verify Command
+ * --------------------------------------------------
+ * This file implements the verify subcommand, which compares the SQL database configured in sqldb with
+ * the MongoDB database configured in nosqldb and writes the consistency report as JSON or CSV. It fails
+ * when the stores differ, so that it can gate scripts.
+ *
+ * Usage:
+ *   main verify [-config path] [-format json|csv]
+ *
+ * Created: Mar. 26, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/config"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/consistency"
+	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+/*
+ * Run
+ * --------------------------------------------------
+ * Parses the arguments of the verify subcommand, compares both stores and writes the report.
+ *
+ * Params:
+ * - args ([]string): Arguments after "verify".
+ * - out (io.Writer): Destination of the report.
+ *
+ * Returns:
+ * - error: If the arguments are invalid, a store cannot be read or the stores differ.
+ */
+func Run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	configPath := flags.String("config", "./config.yml", "path to the configuration file")
+	format := flags.String("format", "json", "report format: json or csv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown report format %q, expected json or csv", *format)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.IsProduction, cfg.LogPath)
+	defer logger.Sync()
+
+	sqlDB, err := relational.OpenSQLDB(cfg.SQLDb.Dialect, cfg.SQLDb.DSN)
+	if err != nil {
+		return err
+	}
+	defer relational.CloseDB(sqlDB)
+
+	// The NoSQL store is never cleaned, whatever nosqldb.clean says
+	mongoDB, err := nonrelational.NewMongoDB(cfg.NoSQLDb.URI, cfg.NoSQLDb.Database, false)
+	if err != nil {
+		return err
+	}
+	defer nonrelational.CloseMongoDB(mongoDB.Client())
+
+	report, err := consistency.Check(context.Background(), consistency.NewSQLSource(sqlDB), consistency.NewMongoSource(mongoDB))
+	if err != nil {
+		return err
+	}
+
+	if *format == "csv" {
+		err = consistency.WriteCSV(out, report)
+	} else {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the report: %w", err)
+	}
+
+	if !report.Consistent {
+		return fmt.Errorf("%d differences found between the SQL and NoSQL stores", len(report.Differences))
+	}
+	return nil
+}
@@ -11,9 +11,26 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+// Storage backends that can be mounted by the server, named after their route prefix under /v1.
+const (
+	BackendSQL    = "sql"    // MySQL, mounted under /v1/sql
+	BackendNoSQL  = "no-sql" // MongoDB, mounted under /v1/no-sql
+	BackendMemory = "memory" // In-process memory, mounted under /v1/memory
+)
+
+// SQL dialects of the sql backend.
+const (
+	SQLDialectMySQL    = "mysql"    // MySQL server
+	SQLDialectSQLite   = "sqlite"   // SQLite file, no server required
+	SQLDialectPostgres = "postgres" // PostgreSQL server
+)
+
 /*
  * Config
  * ----------------------------------------
@@ -22,11 +39,13 @@ import (
  * and other relevant configurations.
  */
 type Config struct {
-	App          AppConfig   // Application-level configuration
-	SQLDb        SQLConfig   // SQL database connection settings
-	NoSQLDb      NoSQLConfig // NoSQL database connection settings
-	IsProduction bool        // Flag indicating if the app runs in production mode
-	LogPath      string      // Path for logging
+	App          AppConfig     // Application-level configuration
+	SQLDb        SQLConfig     // SQL database connection settings
+	NoSQLDb      NoSQLConfig   // NoSQL database connection settings
+	Storage      StorageConfig // Storage backends mounted by the server
+	Timeouts     TimeoutConfig // Deadlines of the API operations
+	IsProduction bool          // Flag indicating if the app runs in production mode
+	LogPath      string        // Path for logging
 }
 
 /*
@@ -44,8 +63,10 @@ type AppConfig struct {
  * Defines the SQL database configuration.
  */
 type SQLConfig struct {
-	DSN   string // Data Source Name for connecting to the SQL database
-	Clean bool   // Whether to clean the SQL database on startup
+	Dialect     string // SQL dialect: mysql, sqlite or postgres
+	DSN         string // Data Source Name for connecting to the SQL database, a file path for SQLite
+	Clean       bool   // Whether to drop every table on startup, only with AutoMigrate
+	AutoMigrate bool   `mapstructure:"auto_migrate"` // Whether to create the schema with AutoMigrate on startup instead of requiring the migrate command, for development only
 }
 
 /*
@@ -59,6 +80,76 @@ type NoSQLConfig struct {
 	Clean    bool   // Whether to clean the NoSQL database on startup
 }
 
+/*
+ * StorageConfig
+ * ----------------------------------------
+ * Defines which storage backends the server connects to and mounts.
+ */
+type StorageConfig struct {
+	Backends  []string        // Backends to mount: sql, no-sql and/or memory
+	DualWrite DualWriteConfig `mapstructure:"dual_write"` // Unified routes writing to both the sql and no-sql backends
+}
+
+/*
+ * DualWriteConfig
+ * ----------------------------------------
+ * Defines the unified routes mounted under /v1, writing to both the sql and no-sql backends.
+ */
+type DualWriteConfig struct {
+	Enabled  bool   // Whether to mount the unified routes, requires the sql and no-sql backends
+	Primary  string // Backend written first and serving the reads: sql or no-sql
+	Fallback bool   // Whether reads failing on the primary backend are served by the other one
+}
+
+/*
+ * Enabled
+ * ----------------------------------------
+ * Reports whether the given storage backend is configured.
+ *
+ * Parameters:
+ * - backend (string): One of BackendSQL, BackendNoSQL or BackendMemory.
+ *
+ * Returns:
+ * - bool: True if the backend must be mounted.
+ */
+func (c StorageConfig) Enabled(backend string) bool {
+	for _, configured := range c.Backends {
+		if configured == backend {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * TimeoutConfig
+ * ----------------------------------------
+ * Defines the deadlines of the API operations. A request is answered with 504 once the deadline of its
+ * operation expires, and its storage operations are cancelled.
+ */
+type TimeoutConfig struct {
+	Default    time.Duration            // Deadline of the operations without their own, 0 disables it
+	Operations map[string]time.Duration // Deadlines by operation name, such as close_cycle, overriding the default
+}
+
+/*
+ * For
+ * ----------------------------------------
+ * Returns the deadline of an API operation.
+ *
+ * Parameters:
+ * - operation (string): Name of the operation, in snake case.
+ *
+ * Returns:
+ * - time.Duration: The deadline of the operation, 0 if it has none.
+ */
+func (c TimeoutConfig) For(operation string) time.Duration {
+	if timeout, ok := c.Operations[operation]; ok {
+		return timeout
+	}
+	return c.Default
+}
+
 /*
  * LoadConfig
  * ----------------------------------------
@@ -82,14 +173,25 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetDefault("app.is_production", false)
 
 	// Set default values for SQL connection
+	viper.SetDefault("sqldb.dialect", SQLDialectMySQL)
 	viper.SetDefault("sqldb.dsn", "root:password@tcp(localhost:3306)/payment_registration_system?charset=utf8mb4&parseTime=True&loc=Local")
 	viper.SetDefault("sqldb.clean", false)
+	viper.SetDefault("sqldb.auto_migrate", false)
 
 	// Set default values for NoSQL connection
 	viper.SetDefault("nosqldb.uri", "mongodb://localhost:27017")
 	viper.SetDefault("nosqldb.database", "payment_registration_system")
 	viper.SetDefault("nosqldb.clean", false)
 
+	// Set default storage backends
+	viper.SetDefault("storage.backends", []string{BackendSQL, BackendNoSQL})
+	viper.SetDefault("storage.dual_write.enabled", false)
+	viper.SetDefault("storage.dual_write.primary", BackendSQL)
+	viper.SetDefault("storage.dual_write.fallback", false)
+
+	// Set default deadline of the API operations
+	viper.SetDefault("timeouts.default", 10*time.Second)
+
 	// Read in environment variables that match
 	viper.AutomaticEnv()
 
@@ -104,5 +206,38 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, err
 	}
 
+	for _, backend := range cfg.Storage.Backends {
+		if backend != BackendSQL && backend != BackendNoSQL && backend != BackendMemory {
+			return nil, fmt.Errorf("unknown storage backend %q, expected %s, %s or %s", backend, BackendSQL, BackendNoSQL, BackendMemory)
+		}
+	}
+
+	if dualWrite := cfg.Storage.DualWrite; dualWrite.Enabled {
+		if !cfg.Storage.Enabled(BackendSQL) || !cfg.Storage.Enabled(BackendNoSQL) {
+			return nil, fmt.Errorf("storage.dual_write requires the %s and %s backends", BackendSQL, BackendNoSQL)
+		}
+		if dualWrite.Primary != BackendSQL && dualWrite.Primary != BackendNoSQL {
+			return nil, fmt.Errorf("unknown dual-write primary backend %q, expected %s or %s", dualWrite.Primary, BackendSQL, BackendNoSQL)
+		}
+	}
+
+	if cfg.Timeouts.Default < 0 {
+		return nil, fmt.Errorf("negative default timeout %s", cfg.Timeouts.Default)
+	}
+	for operation, timeout := range cfg.Timeouts.Operations {
+		if timeout < 0 {
+			return nil, fmt.Errorf("negative timeout %s for operation %s", timeout, operation)
+		}
+	}
+
+	if cfg.SQLDb.Dialect != SQLDialectMySQL && cfg.SQLDb.Dialect != SQLDialectSQLite && cfg.SQLDb.Dialect != SQLDialectPostgres {
+		return nil, fmt.Errorf("unknown SQL dialect %q, expected %s, %s or %s", cfg.SQLDb.Dialect, SQLDialectMySQL, SQLDialectSQLite, SQLDialectPostgres)
+	}
+
+	// Dropping the tables is only allowed when the schema is recreated by AutoMigrate, migrated databases are reverted with the migrate command
+	if cfg.SQLDb.Clean && !cfg.SQLDb.AutoMigrate {
+		return nil, fmt.Errorf("sqldb.clean requires sqldb.auto_migrate, revert migrated databases with the migrate down command")
+	}
+
 	return &cfg, nil
 }
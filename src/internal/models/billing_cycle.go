@@ -0,0 +1,45 @@
+/*
+ * Payment Registration System - Billing Cycle Model
+ * -------------------------------------------------
+ * This file defines the data model for a billing cycle, representing how a bank closes the monthly
+ * payment summaries of its cards: the closing day, the due dates and the late payment surcharge.
+ *
+ * Created: Mar. 04, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+// Default billing cycle values, used for banks that have not configured their own cycle.
+const (
+	DefaultClosingDay          = 31              // Close on the last day of the month
+	DefaultFirstDueDays        = 15              // First expiration 15 days after closing
+	DefaultSecondDueDays       = 10              // Second expiration 10 days after the first one
+	DefaultSurchargePercentage = Percentage(500) // 5% surcharge after the first expiration
+)
+
+// BillingCycle represents the billing configuration of a bank.
+//
+//	@Summary		Billing cycle model
+//	@Description	Contains the billing configuration of a bank: the day of the month its cycles close, the due dates relative to the closing date, and the surcharge applied after the first expiration.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type BillingCycle struct {
+	BankCuit            string     `json:"bank_cuit" example:"30-12345678-9"`                       // Tax identification code (CUIT) of the bank
+	ClosingDay          int        `json:"closing_day" example:"25"`                                // Day of the month the cycle closes (1-31, clamped to the last day of shorter months)
+	FirstDueDays        int        `json:"first_due_days" example:"15"`                             // Days from the closing date to the first expiration
+	SecondDueDays       int        `json:"second_due_days" example:"10"`                            // Days from the first expiration to the second expiration
+	SurchargePercentage Percentage `json:"surcharge_percentage" swaggertype:"number" example:"5.0"` // Surcharge percentage applied after the first expiration
+}
+
+// DefaultBillingCycle returns the billing cycle used for a bank that has not configured its own.
+func DefaultBillingCycle(bankCuit string) BillingCycle {
+	return BillingCycle{
+		BankCuit:            bankCuit,
+		ClosingDay:          DefaultClosingDay,
+		FirstDueDays:        DefaultFirstDueDays,
+		SecondDueDays:       DefaultSecondDueDays,
+		SurchargePercentage: DefaultSurchargePercentage,
+	}
+}
@@ -14,6 +14,16 @@ import (
 	"time"
 )
 
+// CardStatus is the lifecycle state of a card. Expiration is not a status: a card is expired
+// when the date of use is after its expiration date.
+type CardStatus string
+
+const (
+	CardStatusActive    CardStatus = "active"    // The card can be used for purchases
+	CardStatusBlocked   CardStatus = "blocked"   // The card is temporarily disabled and can be unblocked
+	CardStatusCancelled CardStatus = "cancelled" // The card is permanently disabled
+)
+
 // Card represents a payment card issued by a bank.
 //
 //	@Summary		Card model
@@ -27,7 +37,20 @@ type Card struct {
 	CardholderNameInCard    string                   `json:"cardholdername_in_card" example:"John Doe"`      // Name as printed on the card
 	Since                   time.Time                `json:"since" example:"2020-01-01T00:00:00Z"`           // Issuance date of the card
 	ExpirationDate          time.Time                `json:"expiration_date" example:"2025-12-31T23:59:59Z"` // Expiration date of the card
+	Status                  CardStatus               `json:"status" example:"active"`                        // Lifecycle status of the card
 	Bank                    Bank                     `json:"bank"`                                           // Issuing bank details
+	CustomerCuit            string                   `json:"customer_cuit" example:"20-12345678-9"`          // CUIT of the cardholder
 	PurchaseMonthlyPayments []PurchaseMonthlyPayment `json:"purchase_monthly_payments"`                      // Monthly installment payments
 	PurchaseSinglePayments  []PurchaseSinglePayment  `json:"purchase_single_payment"`                        // Single-payment transactions
 }
+
+// CardRenewal contains the new expiration date of a renewed card.
+//
+//	@Summary		Card renewal model
+//	@Description	Contains the new expiration date of a card, which must be after the current one.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type CardRenewal struct {
+	ExpirationDate time.Time `json:"expiration_date" example:"2030-12-31T23:59:59Z"` // New expiration date of the card
+}
@@ -0,0 +1,54 @@
+/*
+ * Payment Registration System - Consistency Models
+ * ----------------------------------------
+ * This file defines the report of a comparison between the SQL and NoSQL stores, which
+ * lists the records missing from one of them and the records whose fields differ.
+ *
+ * Created: Mar. 26, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+import "time"
+
+// Kinds of difference between the stores. The SQL store is the reference, as it is replicated into the NoSQL store.
+const (
+	DifferenceMissing  = "missing"  // The record is in the SQL store but not in the NoSQL store
+	DifferenceExtra    = "extra"    // The record is in the NoSQL store but not in the SQL store
+	DifferenceMismatch = "mismatch" // The record is in both stores with a different field
+)
+
+// ConsistencyReport is the result of comparing the SQL and NoSQL stores.
+//
+//	@Summary		Consistency report model
+//	@Description	Lists, per entity, the records missing from the NoSQL store, the extra records in it and the mismatched fields.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type ConsistencyReport struct {
+	CheckedAt   time.Time           `json:"checked_at" example:"2025-03-26T10:00:00Z"` // When the stores were compared
+	Consistent  bool                `json:"consistent" example:"false"`                // True if no difference was found
+	Entities    []EntityConsistency `json:"entities"`                                  // Summary per entity
+	Differences []Difference        `json:"differences"`                               // Every difference found
+}
+
+// EntityConsistency summarizes the comparison of an entity.
+type EntityConsistency struct {
+	Entity     string `json:"entity" example:"card"`  // Entity: bank, card, purchase or promotion
+	SQL        int    `json:"sql" example:"120"`      // Records in the SQL store
+	NoSQL      int    `json:"no_sql" example:"118"`   // Records in the NoSQL store
+	Missing    int    `json:"missing" example:"2"`    // Records missing from the NoSQL store
+	Extra      int    `json:"extra" example:"0"`      // Records only in the NoSQL store
+	Mismatched int    `json:"mismatched" example:"1"` // Records in both stores with different fields
+}
+
+// Difference is a record missing from a store or a field that differs between them.
+type Difference struct {
+	Entity     string `json:"entity" example:"purchase"`                                      // Entity: bank, card, purchase or promotion
+	Key        string `json:"key" example:"PV20241001/4000000000000001/2024-10-01T10:00:00Z"` // Natural key of the record
+	Kind       string `json:"kind" example:"mismatch"`                                        // missing, extra or mismatch
+	Field      string `json:"field,omitempty" example:"final_amount"`                         // Field that differs, for mismatches
+	SQLValue   string `json:"sql_value,omitempty" example:"900.00"`                           // Value in the SQL store
+	NoSQLValue string `json:"no_sql_value,omitempty" example:"1000.00"`                       // Value in the NoSQL store
+}
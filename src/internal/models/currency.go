@@ -0,0 +1,82 @@
+/*
+ * Payment Registration System - Currency
+ * --------------------------------------
+ * This file defines the currencies purchases can be made in and the daily exchange rates used to
+ * convert them to pesos when a billing cycle is closed.
+ *
+ * Created: Apr. 05, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// Supported currencies. Purchases without a currency are in the base currency, in which summaries are totalled.
+const (
+	CurrencyARS  Currency = "ARS"
+	CurrencyUSD  Currency = "USD"
+	BaseCurrency          = CurrencyARS
+)
+
+// Currencies lists the supported currencies, the base currency first.
+var Currencies = []Currency{CurrencyARS, CurrencyUSD}
+
+// ParseCurrency parses a currency code, ignoring case. An empty code is the base currency.
+func ParseCurrency(s string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(s))).OrBase()
+	if !currency.Valid() {
+		return "", fmt.Errorf("unsupported currency %q", s)
+	}
+	return currency, nil
+}
+
+// Valid reports whether the currency is supported.
+func (c Currency) Valid() bool {
+	for _, currency := range Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// OrBase returns the currency, or the base currency if it is empty, as it is for purchases stored before currencies existed.
+func (c Currency) OrBase() Currency {
+	if c == "" {
+		return BaseCurrency
+	}
+	return c
+}
+
+// ExchangeRate is the price in the base currency of one unit of a currency on a given day.
+//
+//	@Summary		Exchange rate model
+//	@Description	Contains the price in pesos of one unit of a foreign currency on a given day.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type ExchangeRate struct {
+	Currency Currency  `json:"currency" swaggertype:"string" example:"USD"` // Foreign currency
+	Date     time.Time `json:"date" example:"2025-04-01T00:00:00Z"`         // Day the rate applies to, at midnight UTC
+	Rate     Money     `json:"rate" swaggertype:"number" example:"1072.50"` // Price in pesos of one unit of the currency
+}
+
+// Convert converts an amount in the rate's currency to the base currency, rounded to the cent, halves away from zero.
+func (r ExchangeRate) Convert(amount Money) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(r.Rate)))
+	return Money(roundQuotient(product, big.NewInt(scale)).Int64())
+}
+
+// RateDay truncates a time to the day an exchange rate applies to, at midnight UTC.
+func RateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
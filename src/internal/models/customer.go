@@ -29,4 +29,5 @@ type Customer struct {
 	EntryDate    time.Time `json:"entry_date" example:"2022-03-15T00:00:00Z"` // Date the customer was registered
 	BanksIds     []int     `json:"banks_ids"`                                 // List of bank IDs the customer is associated with
 	Cards        []int     `json:"cards"`                                     // List of card IDs linked to the customer
+	BankCuits    []string  `json:"bank_cuits"`                                // CUITs of the banks the customer is a member of
 }
@@ -0,0 +1,109 @@
+/*
+ * Payment Registration System - Installment Plan
+ * ----------------------------------------------
+ * This file defines the interest models of installment purchases and financing promotions, and the
+ * installment plans computed from them: the quota schedule with the capital and interest of each quota
+ * and the rates that summarize the cost of the financing.
+ *
+ * Created: Apr. 08, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+import "fmt"
+
+// InterestModel defines how the interest of an installment purchase is computed, and so the meaning of its interest rate.
+type InterestModel string
+
+const (
+	// InterestModelFlat adds the interest rate, as a percentage of the amount, once over the whole plan and spreads
+	// it evenly across the quotas. It is the model of purchases and financings stored before interest models existed.
+	InterestModelFlat InterestModel = "flat"
+	// InterestModelFrench is the French amortization system: the interest rate is a nominal annual rate (TNA),
+	// charged monthly on the outstanding capital, and every quota has the same price.
+	InterestModelFrench InterestModel = "french"
+	// InterestModelZero is a promotional plan without interest: the quotas only repay the capital.
+	InterestModelZero InterestModel = "zero"
+)
+
+// InterestModels lists the supported interest models.
+var InterestModels = []InterestModel{InterestModelFlat, InterestModelFrench, InterestModelZero}
+
+// ParseInterestModel parses the name of an interest model. An empty name is the flat model.
+func ParseInterestModel(s string) (InterestModel, error) {
+	model := InterestModel(s).OrFlat()
+	if !model.Valid() {
+		return "", fmt.Errorf("unknown interest model %q (expected flat, french or zero)", s)
+	}
+	return model, nil
+}
+
+// Valid reports whether the interest model is supported.
+func (m InterestModel) Valid() bool {
+	for _, model := range InterestModels {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// OrFlat returns the interest model, or the flat model if it is empty, as it is for records stored before interest models existed.
+func (m InterestModel) OrFlat() InterestModel {
+	if m == "" {
+		return InterestModelFlat
+	}
+	return m
+}
+
+// InstallmentQuota is a quota of an installment plan, split into the capital it repays and the interest it pays.
+//
+//	@Summary		Installment quota model
+//	@Description	Contains a quota of an installment plan with its capital, its interest and the capital still owed after paying it.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type InstallmentQuota struct {
+	Quota
+	Capital  Money `json:"capital" swaggertype:"number" example:"75.39"`   // Part of the price that repays the amount financed
+	Interest Money `json:"interest" swaggertype:"number" example:"60.00"`  // Part of the price that pays interest
+	Balance  Money `json:"balance" swaggertype:"number" example:"1124.61"` // Capital still owed after the quota is paid
+}
+
+// InstallmentPlan is the schedule and cost of paying an amount in monthly quotas under an interest model.
+//
+//	@Summary		Installment plan model
+//	@Description	Contains the quotas of an installment plan with their capital and interest, the final amount, and the nominal annual rate (TNA), effective annual rate (TEA) and total financial cost (CFT) of the plan.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type InstallmentPlan struct {
+	InterestModel  InterestModel      `json:"interest_model" swaggertype:"string" example:"french"` // Interest model of the plan
+	Amount         Money              `json:"amount" swaggertype:"number" example:"1200.00"`        // Amount financed
+	Interest       Percentage         `json:"interest" swaggertype:"number" example:"60"`           // Interest rate, whose meaning depends on the interest model
+	NumberOfQuotas int                `json:"number_of_quotas" example:"12"`                        // Number of monthly quotas
+	TotalInterest  Money              `json:"total_interest" swaggertype:"number" example:"424.69"` // Interest paid over the whole plan
+	FinalAmount    Money              `json:"final_amount" swaggertype:"number" example:"1624.69"`  // Sum of the prices of the quotas
+	TNA            Percentage         `json:"tna" swaggertype:"number" example:"60"`                // Nominal annual rate
+	TEA            Percentage         `json:"tea" swaggertype:"number" example:"79.59"`             // Effective annual rate of the nominal rate, compounded monthly
+	CFT            Percentage         `json:"cft" swaggertype:"number" example:"79.59"`             // Total financial cost: the effective annual rate at which the quotas repay the amount
+	Quotas         []InstallmentQuota `json:"quotas"`                                               // Quota schedule, ordered by number
+}
+
+// FinancingSimulationRequest is the financing an installment plan is simulated for: either a financing promotion,
+// identified by its code, or an explicit number of quotas, interest rate and interest model.
+//
+//	@Summary		Financing simulation request model
+//	@Description	Used to simulate the installment plan of an amount, financed by a promotion or with explicit terms.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type FinancingSimulationRequest struct {
+	Amount         Money         `json:"amount" swaggertype:"number" example:"1200.00"`                  // Amount to finance
+	PromotionCode  string        `json:"promotion_code,omitempty" example:"FIN-2025"`                    // Financing promotion whose terms are used
+	NumberOfQuotas int           `json:"number_of_quotas,omitempty" example:"12"`                        // Number of monthly quotas, without a promotion
+	Interest       Percentage    `json:"interest,omitempty" swaggertype:"number" example:"60"`           // Interest rate, without a promotion
+	InterestModel  InterestModel `json:"interest_model,omitempty" swaggertype:"string" example:"french"` // Interest model, without a promotion; flat by default
+	PurchaseDate   string        `json:"purchase_date,omitempty" example:"2025-04-08T00:00:00Z"`         // Optional purchase date in RFC3339 format, defaults to now
+}
@@ -0,0 +1,312 @@
+/*
+ * Payment Registration System - Money
+ * -----------------------------------
+ * This file defines the fixed-point types used for amounts of money and percentages, so that amounts
+ * are added, compared and matched exactly. Both types keep two decimal places and are encoded as
+ * decimal numbers in JSON, DECIMAL columns in SQL and Decimal128 values in MongoDB.
+ *
+ * Created: Apr. 02, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// decimals is the number of decimal places kept by Money and Percentage, and scale the matching power of ten.
+const (
+	decimals = 2
+	scale    = 100
+)
+
+// Money is an exact amount of money, counted in cents.
+type Money int64
+
+// Percentage is an exact percentage with two decimal places, counted in hundredths of a percent: 3.5% is 350.
+type Percentage int64
+
+// HundredPercent is 100%, the upper bound of discounts and surcharges.
+const HundredPercent Percentage = 100 * scale
+
+// ParseMoney parses a decimal amount such as "1500.75". Amounts with more than two decimal places are rejected.
+func ParseMoney(s string) (Money, error) {
+	value, err := parseFixed(s, true)
+	return Money(value), err
+}
+
+// MustParseMoney is like ParseMoney but panics if the amount is invalid. It is meant for constants and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents returns the amount as a whole number of cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Percent returns the given percentage of the amount, rounded to the cent, halves away from zero.
+func (m Money) Percent(p Percentage) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(p)))
+	return Money(roundQuotient(product, big.NewInt(100*scale)).Int64())
+}
+
+// Rat returns the amount in pesos as an exact fraction.
+func (m Money) Rat() *big.Rat {
+	return big.NewRat(int64(m), scale)
+}
+
+// RoundMoney rounds an amount in pesos to the cent, halves away from zero.
+func RoundMoney(r *big.Rat) Money {
+	cents := new(big.Rat).Mul(r, new(big.Rat).SetInt64(scale))
+	return Money(roundQuotient(cents.Num(), cents.Denom()).Int64())
+}
+
+// String formats the amount with two decimal places, e.g. "1500.75".
+func (m Money) String() string {
+	return formatFixed(int64(m))
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes an amount from a JSON number or string, rejecting more than two decimal places.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalFixedJSON(data)
+	if ok {
+		*m = Money(value)
+	}
+	return err
+}
+
+// Value stores the amount in a DECIMAL column.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads the amount from a DECIMAL column, or from a floating point column rounded to the cent.
+func (m *Money) Scan(src any) error {
+	value, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// MarshalBSONValue stores the amount as a Decimal128.
+func (m Money) MarshalBSONValue() (byte, []byte, error) {
+	return marshalFixedBSON(int64(m))
+}
+
+// UnmarshalBSONValue reads the amount from a Decimal128, or from a number stored by an earlier version rounded to the cent.
+func (m *Money) UnmarshalBSONValue(typ byte, data []byte) error {
+	value, err := unmarshalFixedBSON(typ, data)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// ParsePercentage parses a decimal percentage such as "3.5". Percentages with more than two decimal places are rejected.
+func ParsePercentage(s string) (Percentage, error) {
+	value, err := parseFixed(s, true)
+	return Percentage(value), err
+}
+
+// MustParsePercentage is like ParsePercentage but panics if the percentage is invalid. It is meant for constants and tests.
+func MustParsePercentage(s string) Percentage {
+	p, err := ParsePercentage(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Rat returns the percentage as an exact fraction of one: 3.5% is 7/200.
+func (p Percentage) Rat() *big.Rat {
+	return big.NewRat(int64(p), 100*scale)
+}
+
+// RoundPercentage rounds a fraction of one to a percentage with two decimal places, halves away from zero: 7/200 is 3.5%.
+func RoundPercentage(r *big.Rat) Percentage {
+	hundredths := new(big.Rat).Mul(r, new(big.Rat).SetInt64(100*scale))
+	return Percentage(roundQuotient(hundredths.Num(), hundredths.Denom()).Int64())
+}
+
+// String formats the percentage with two decimal places, e.g. "3.50".
+func (p Percentage) String() string {
+	return formatFixed(int64(p))
+}
+
+// MarshalJSON encodes the percentage as a JSON number with two decimal places.
+func (p Percentage) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON decodes a percentage from a JSON number or string, rejecting more than two decimal places.
+func (p *Percentage) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalFixedJSON(data)
+	if ok {
+		*p = Percentage(value)
+	}
+	return err
+}
+
+// Value stores the percentage in a DECIMAL column.
+func (p Percentage) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// Scan reads the percentage from a DECIMAL column, or from a floating point column rounded to two decimal places.
+func (p *Percentage) Scan(src any) error {
+	value, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*p = Percentage(value)
+	return nil
+}
+
+// MarshalBSONValue stores the percentage as a Decimal128.
+func (p Percentage) MarshalBSONValue() (byte, []byte, error) {
+	return marshalFixedBSON(int64(p))
+}
+
+// UnmarshalBSONValue reads the percentage from a Decimal128, or from a number stored by an earlier version.
+func (p *Percentage) UnmarshalBSONValue(typ byte, data []byte) error {
+	value, err := unmarshalFixedBSON(typ, data)
+	if err != nil {
+		return err
+	}
+	*p = Percentage(value)
+	return nil
+}
+
+// formatFixed formats a value counted in hundredths as a decimal number.
+func formatFixed(value int64) string {
+	sign := ""
+	magnitude := uint64(value)
+	if value < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/scale, decimals, magnitude%scale)
+}
+
+// parseFixed parses a decimal number into hundredths. When exact is set, numbers with more than two decimal places are
+// rejected; otherwise they are rounded, halves away from zero.
+func parseFixed(s string, exact bool) (int64, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid decimal number %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(scale))
+	if exact && !r.IsInt() {
+		return 0, fmt.Errorf("%q has more than %d decimal places", s, decimals)
+	}
+
+	value := roundQuotient(r.Num(), r.Denom())
+	if !value.IsInt64() {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	return value.Int64(), nil
+}
+
+// roundQuotient divides two integers rounding halves away from zero. The divisor must be positive.
+func roundQuotient(dividend *big.Int, divisor *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(dividend, divisor, new(big.Int))
+	if twice := new(big.Int).Lsh(remainder.Abs(remainder), 1); twice.Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(dividend.Sign())))
+	}
+	return quotient
+}
+
+// fromFloat converts a floating point number to hundredths, rounding to the nearest one.
+func fromFloat(f float64) (int64, error) {
+	rounded := math.Round(f * scale)
+	if math.IsNaN(rounded) || rounded >= math.MaxInt64 || rounded <= math.MinInt64 {
+		return 0, fmt.Errorf("%v is not a valid decimal number", f)
+	}
+	return int64(rounded), nil
+}
+
+// unmarshalFixedJSON decodes a JSON number or string into hundredths. It reports false for null, which leaves the value unchanged.
+func unmarshalFixedJSON(data []byte) (int64, bool, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return 0, false, nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	value, err := parseFixed(text, true)
+	return value, err == nil, err
+}
+
+// scanFixed reads a value in hundredths from a database column. NULL, returned for instance by a SUM over no rows, is zero.
+func scanFixed(src any) (int64, error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v * scale, nil
+	case float64:
+		return fromFloat(v)
+	case []byte:
+		return parseFixed(string(v), false)
+	case string:
+		return parseFixed(v, false)
+	default:
+		return 0, fmt.Errorf("cannot scan %T into a decimal number", src)
+	}
+}
+
+// marshalFixedBSON encodes a value in hundredths as a Decimal128.
+func marshalFixedBSON(value int64) (byte, []byte, error) {
+	decimal, ok := bson.ParseDecimal128FromBigInt(big.NewInt(value), -decimals)
+	if !ok {
+		return 0, nil, errors.New("decimal number out of the Decimal128 range")
+	}
+	typ, data, err := bson.MarshalValue(decimal)
+	return byte(typ), data, err
+}
+
+// unmarshalFixedBSON decodes a value in hundredths from a Decimal128, a double or an integer.
+func unmarshalFixedBSON(typ byte, data []byte) (int64, error) {
+	raw := bson.RawValue{Type: bson.Type(typ), Value: data}
+	switch raw.Type {
+	case bson.TypeNull:
+		return 0, nil
+	case bson.TypeDecimal128:
+		decimal := raw.Decimal128()
+		if decimal.IsNaN() || decimal.IsInf() != 0 {
+			return 0, fmt.Errorf("%s is not a valid decimal number", decimal)
+		}
+		return parseFixed(decimal.String(), false)
+	case bson.TypeDouble:
+		return fromFloat(raw.Double())
+	case bson.TypeInt32:
+		return int64(raw.Int32()) * scale, nil
+	case bson.TypeInt64:
+		return raw.Int64() * scale, nil
+	default:
+		return 0, fmt.Errorf("cannot decode BSON %s into a decimal number", raw.Type)
+	}
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseMoneyRoundTrip(t *testing.T) {
+	tests := []struct {
+		input string
+		cents int64
+		want  string
+	}{
+		{"1500.75", 150075, "1500.75"},
+		{"1500", 150000, "1500.00"},
+		{"0.5", 50, "0.50"},
+		{"0", 0, "0.00"},
+		{"-0.05", -5, "-0.05"},
+		{"-1234.5", -123450, "-1234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseMoney(tt.input)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.cents, m.Cents())
+			assert.Equal(t, tt.want, m.String())
+			assert.Equal(t, m, MustParseMoney(m.String()))
+		})
+	}
+}
+
+func TestParseMoneyRejectsInvalidAmounts(t *testing.T) {
+	for _, input := range []string{"1.005", "0.001", "-2.999", "abc", "", "99999999999999999999"} {
+		_, err := ParseMoney(input)
+		assert.Error(t, err, input)
+	}
+
+	_, err := ParsePercentage("3.125")
+	assert.Error(t, err)
+	assert.Panics(t, func() { MustParseMoney("1.005") })
+}
+
+func TestMoneyJSON(t *testing.T) {
+	encoded, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{MustParseMoney("-12.5")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": -12.50}`, string(encoded))
+
+	var decoded struct {
+		Amount Money `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 99.9}`), &decoded))
+	assert.Equal(t, MustParseMoney("99.90"), decoded.Amount)
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "10.25"}`), &decoded))
+	assert.Equal(t, MustParseMoney("10.25"), decoded.Amount)
+
+	// null leaves the amount unchanged
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": null}`), &decoded))
+	assert.Equal(t, MustParseMoney("10.25"), decoded.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 1.005}`), &decoded))
+	assert.Equal(t, MustParseMoney("10.25"), decoded.Amount, "a rejected amount leaves the value unchanged")
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want Money
+	}{
+		{"bytes", []byte("1500.75"), 150075},
+		{"string", "-20.10", -2010},
+		{"string rounded to the cent", "10.005", 1001},
+		{"float rounded to the cent", 19.994, 1999},
+		{"integer", int64(42), 4200},
+		{"null", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MustParseMoney("1")
+
+			require.NoError(t, m.Scan(tt.src))
+			assert.Equal(t, tt.want, m)
+		})
+	}
+
+	var m Money
+	assert.Error(t, m.Scan(true))
+}
+
+func TestMoneyBSON(t *testing.T) {
+	type document struct {
+		Amount Money `bson:"amount"`
+	}
+
+	// Amounts are stored as Decimal128 and read back exactly
+	encoded, err := bson.Marshal(document{MustParseMoney("-1500.75")})
+	require.NoError(t, err)
+	assert.Equal(t, bson.TypeDecimal128, bson.Raw(encoded).Lookup("amount").Type)
+	var decoded document
+	require.NoError(t, bson.Unmarshal(encoded, &decoded))
+	assert.Equal(t, MustParseMoney("-1500.75"), decoded.Amount)
+
+	// Numbers stored by earlier versions are read rounded to the cent
+	decimal, err := bson.ParseDecimal128("10.255")
+	require.NoError(t, err)
+	for name, tt := range map[string]struct {
+		value any
+		want  Money
+	}{
+		"decimal128": {decimal, MustParseMoney("10.26")},
+		"double":     {19.994, MustParseMoney("19.99")},
+		"int32":      {int32(7), MustParseMoney("7")},
+	} {
+		encoded, err := bson.Marshal(bson.D{{Key: "amount", Value: tt.value}})
+		require.NoError(t, err)
+
+		var decoded document
+		require.NoError(t, bson.Unmarshal(encoded, &decoded), name)
+		assert.Equal(t, tt.want, decoded.Amount, name)
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		amount     string
+		percentage string
+		want       string
+	}{
+		{"1000", "10", "100.00"},
+		{"0.10", "5", "0.01"},      // 0.005 rounds half away from zero
+		{"-0.10", "5", "-0.01"},    // also for negative amounts
+		{"0.09", "5", "0.00"},      // 0.0045 rounds down
+		{"333.33", "3.5", "11.67"}, // 11.66655
+		{"100", "0", "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+"*"+tt.percentage, func(t *testing.T) {
+			got := MustParseMoney(tt.amount).Percent(MustParsePercentage(tt.percentage))
+
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
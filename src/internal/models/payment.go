@@ -0,0 +1,134 @@
+/*
+ * Payment Registration System - Payment Model
+ * -------------------------------------------
+ * This file defines the payments of payment summaries and the outstanding balance of a card. A payment
+ * is allocated to the quotas and single-payment purchases billed in a summary, and the status of each of
+ * them follows from what was paid and the first expiration date of the summary.
+ *
+ * Created: Apr. 14, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+import (
+	"time"
+)
+
+// PaymentStatus is the state of a billed quota or single-payment purchase, or of a whole payment summary.
+//
+//	@Summary		Payment status model
+//	@Description	pending and partially_paid until the first expiration date, overdue afterwards while something is left to pay, paid once fully paid.
+//	@Tags			Models
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending is nothing paid yet, before the first expiration date.
+	PaymentStatusPending PaymentStatus = "pending"
+	// PaymentStatusPartiallyPaid is part of the amount paid, before the first expiration date.
+	PaymentStatusPartiallyPaid PaymentStatus = "partially_paid"
+	// PaymentStatusPaid is the whole amount paid.
+	PaymentStatusPaid PaymentStatus = "paid"
+	// PaymentStatusOverdue is an amount left to pay after the first expiration date.
+	PaymentStatusOverdue PaymentStatus = "overdue"
+)
+
+// Payment represents a payment of the payment summary of a card for a month.
+//
+//	@Summary		Payment model
+//	@Description	Contains a payment made against a payment summary and how it was allocated to the quotas and single-payment purchases billed in it.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type Payment struct {
+	Code        string              `json:"code" example:"PAYMENT-4000000000000001-2025-04-1"` // Unique code identifying the payment
+	CardNumber  string              `json:"card_number" example:"4000000000000001"`            // Card whose summary is paid
+	Month       int                 `json:"month" example:"4"`                                 // Month of the paid summary
+	Year        int                 `json:"year" example:"2025"`                               // Year of the paid summary
+	Amount      Money               `json:"amount" swaggertype:"number" example:"1500.00"`     // Amount paid, in pesos
+	PaymentDate time.Time           `json:"payment_date" example:"2025-05-10T12:30:00Z"`       // Date the payment was made
+	Allocations []PaymentAllocation `json:"allocations"`                                       // Parts of the amount applied to each billed item
+}
+
+// PaymentAllocation is the part of a payment applied to a quota or a single-payment purchase billed in a summary.
+// A quota is identified by the payment voucher of its purchase and its number, a single-payment purchase by its
+// payment voucher and purchase date.
+//
+//	@Summary		Payment allocation model
+//	@Description	Contains the part of a payment applied to a billed quota or single-payment purchase.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PaymentAllocation struct {
+	PaymentVoucher string     `json:"payment_voucher" example:"VCHR-202502"`                  // Payment voucher of the purchase
+	QuotaNumber    int        `json:"quota_number,omitempty" example:"2"`                     // Number of the quota, zero for single-payment purchases
+	PurchaseDate   *time.Time `json:"purchase_date,omitempty" example:"2025-04-02T00:00:00Z"` // Date of the single-payment purchase, nil for quotas
+	Amount         Money      `json:"amount" swaggertype:"number" example:"500.00"`           // Amount applied, in pesos
+	BilledAmount   Money      `json:"-"`                                                      // Amount billed of the item, in pesos, that its paid amount cannot exceed
+}
+
+// PaymentRequest represents a request to pay the payment summary of a card.
+//
+//	@Summary		Payment request model
+//	@Description	Contains the amount paid. It is allocated to the quotas first and then to the single-payment purchases of the summary, and cannot exceed what is left to pay.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PaymentRequest struct {
+	Amount      Money  `json:"amount" swaggertype:"number" example:"1500.00"`         // Amount paid, in pesos
+	PaymentDate string `json:"payment_date,omitempty" example:"2025-05-10T12:30:00Z"` // Optional payment date in RFC3339 format, defaults to now
+}
+
+// BalanceItem is a quota or single-payment purchase billed in a payment summary and what is left to pay of it.
+//
+//	@Summary		Balance item model
+//	@Description	Contains the amount billed for a quota or single-payment purchase, in pesos, what was paid of it and its status.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type BalanceItem struct {
+	PaymentVoucher string        `json:"payment_voucher" example:"VCHR-202502"`                  // Payment voucher of the purchase
+	Store          string        `json:"store" example:"ElectroStore"`                           // Name of the store where the purchase was made
+	QuotaNumber    int           `json:"quota_number,omitempty" example:"2"`                     // Number of the quota, zero for single-payment purchases
+	NumberOfQuotas int           `json:"number_of_quotas,omitempty" example:"12"`                // Total number of quotas of the purchase
+	PurchaseDate   *time.Time    `json:"purchase_date,omitempty" example:"2025-04-02T00:00:00Z"` // Date of the single-payment purchase, nil for quotas
+	Amount         Money         `json:"amount" swaggertype:"number" example:"125.50"`           // Amount billed, in pesos
+	PaidAmount     Money         `json:"paid_amount" swaggertype:"number" example:"100.00"`      // Amount paid
+	Outstanding    Money         `json:"outstanding" swaggertype:"number" example:"25.50"`       // Amount left to pay
+	Status         PaymentStatus `json:"status" swaggertype:"string" example:"partially_paid"`   // Status of the item
+}
+
+// SummaryBalance is what is left to pay of a payment summary.
+//
+//	@Summary		Summary balance model
+//	@Description	Contains the items of a payment summary that are not fully paid yet and the status of the summary.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type SummaryBalance struct {
+	Code             string        `json:"code" example:"SUMMARY-4000000000000001-2025-04"`    // Code of the payment summary
+	Month            int           `json:"month" example:"4"`                                  // Month of the payment summary
+	Year             int           `json:"year" example:"2025"`                                // Year of the payment summary
+	FirstExpiration  time.Time     `json:"first_expiration" example:"2025-05-10T00:00:00Z"`    // First expiration date, items left to pay are overdue after it
+	SecondExpiration time.Time     `json:"second_expiration" example:"2025-05-20T00:00:00Z"`   // Second expiration date
+	TotalPrice       Money         `json:"total_price" swaggertype:"number" example:"1500.75"` // Total price of the summary, in pesos
+	PaidAmount       Money         `json:"paid_amount" swaggertype:"number" example:"1000.00"` // Amount paid
+	Outstanding      Money         `json:"outstanding" swaggertype:"number" example:"500.75"`  // Amount left to pay
+	Status           PaymentStatus `json:"status" swaggertype:"string" example:"overdue"`      // Status of the summary
+	Items            []BalanceItem `json:"items"`                                              // Items left to pay
+}
+
+// CardBalance is what is left to pay of the payment summaries of a card on a date.
+//
+//	@Summary		Card balance model
+//	@Description	Contains the outstanding balance of a card across its payment summaries, and the part of it that is overdue. Quotas of later months are not included until they are billed.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type CardBalance struct {
+	CardNumber  string           `json:"card_number" example:"4000000000000001"`            // Number of the card
+	Date        time.Time        `json:"date" example:"2025-05-15T00:00:00Z"`               // Date the statuses are computed on
+	Outstanding Money            `json:"outstanding" swaggertype:"number" example:"500.75"` // Amount left to pay across the summaries
+	Overdue     Money            `json:"overdue" swaggertype:"number" example:"500.75"`     // Part of the outstanding amount past its first expiration date
+	Summaries   []SummaryBalance `json:"summaries"`                                         // Summaries with an amount left to pay, oldest first
+}
@@ -22,14 +22,30 @@ import (
 //	@Accept			json
 //	@Produce		json
 type PaymentSummary struct {
-	Code                string                   `json:"code" example:"PAY-202502"`                        // Unique code identifying the payment summary
-	Month               int                      `json:"month" example:"2"`                                // Month of the payment summary
-	Year                int                      `json:"year" example:"2025"`                              // Year of the payment summary
-	FirstExpiration     time.Time                `json:"first_expiration" example:"2025-02-10T00:00:00Z"`  // First expiration date
-	SecondExpiration    time.Time                `json:"second_expiration" example:"2025-02-20T00:00:00Z"` // Second expiration date
-	SurchargePercentage float64                  `json:"surcharge_percentage" example:"5.0"`               // Surcharge percentage applied after first expiration
-	TotalPrice          float64                  `json:"total_price" example:"1500.75"`                    // Total price to be paid
-	MonthlyPayments     []PurchaseMonthlyPayment `json:"monthly_payments"`                                 // List of monthly installment payments
-	SinglePayments      []PurchaseSinglePayment  `json:"single_payments"`                                  // List of single-payment transactions
-	Card                Card                     `json:"card"`                                             // Card used for the payment
+	Code                string                   `json:"code" example:"PAY-202502"`                               // Unique code identifying the payment summary
+	Month               int                      `json:"month" example:"2"`                                       // Month of the payment summary
+	Year                int                      `json:"year" example:"2025"`                                     // Year of the payment summary
+	FirstExpiration     time.Time                `json:"first_expiration" example:"2025-02-10T00:00:00Z"`         // First expiration date
+	SecondExpiration    time.Time                `json:"second_expiration" example:"2025-02-20T00:00:00Z"`        // Second expiration date
+	SurchargePercentage Percentage               `json:"surcharge_percentage" swaggertype:"number" example:"5.0"` // Surcharge percentage applied after first expiration
+	TotalPrice          Money                    `json:"total_price" swaggertype:"number" example:"1500.75"`      // Total price to be paid in pesos: the subtotals converted at the closing rate
+	Subtotals           []CurrencySubtotal       `json:"subtotals"`                                               // Amounts billed in each currency, pesos first
+	MonthlyPayments     []PurchaseMonthlyPayment `json:"monthly_payments"`                                        // Installment purchases made during the cycle
+	Quotas              []DueQuota               `json:"quotas"`                                                  // Installment quotas due in the month, across all installment purchases
+	SinglePayments      []PurchaseSinglePayment  `json:"single_payments"`                                         // List of single-payment transactions
+	Card                Card                     `json:"card"`                                                    // Card used for the payment
+}
+
+// CurrencySubtotal is the amount billed in a currency in a payment summary and its value in pesos.
+//
+//	@Summary		Currency subtotal model
+//	@Description	Contains the single payments and quotas billed in a currency, the exchange rate on the closing date of the cycle and the subtotal in pesos.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type CurrencySubtotal struct {
+	Currency     Currency `json:"currency" swaggertype:"string" example:"USD"`       // Currency of the purchases
+	Subtotal     Money    `json:"subtotal" swaggertype:"number" example:"120.50"`    // Single payments plus the quotas due, in the currency
+	ExchangeRate Money    `json:"exchange_rate" swaggertype:"number" example:"1.00"` // Price in pesos of one unit of the currency on the closing date
+	Total        Money    `json:"total" swaggertype:"number" example:"129236.25"`    // Subtotal converted to pesos
 }
@@ -37,9 +37,9 @@ type Promotion struct {
 //	@Produce		json
 type Discount struct {
 	Promotion
-	DiscountPercentage float64 `json:"discount_percentage" example:"10.5"` // Discount percentage applied to purchases
-	PriceCap           float64 `json:"price_cap" example:"5000.00"`        // Maximum price limit for the discount
-	OnlyCash           bool    `json:"only_cash" example:"true"`           // Indicates if the discount is cash-only
+	DiscountPercentage Percentage `json:"discount_percentage" swaggertype:"number" example:"10.5"` // Discount percentage applied to purchases
+	PriceCap           Money      `json:"price_cap" swaggertype:"number" example:"5000.00"`        // Maximum price limit for the discount
+	OnlyCash           bool       `json:"only_cash" example:"true"`                                // Indicates if the discount is cash-only
 }
 
 // Financing represents a promotion that provides financing options.
@@ -51,8 +51,9 @@ type Discount struct {
 //	@Produce		json
 type Financing struct {
 	Promotion
-	NumberOfQuotas int     `json:"number_of_quotas" example:"12"` // Number of installment payments available
-	Interest       float64 `json:"interest" example:"5.5"`        // Interest rate applied to the financing
+	NumberOfQuotas int           `json:"number_of_quotas" example:"12"`                      // Number of installment payments available
+	Interest       Percentage    `json:"interest" swaggertype:"number" example:"5.5"`        // Interest rate applied to the financing, read according to the interest model
+	InterestModel  InterestModel `json:"interest_model" swaggertype:"string" example:"flat"` // How the interest of the quotas is computed, defaults to flat
 }
 
 // ExtendPromotionRequest represents a request to extend a promotion's validity period.
@@ -65,3 +66,53 @@ type Financing struct {
 type ExtendPromotionRequest struct {
 	NewDate string `json:"new_date" example:"2026-01-01T00:00:00Z"` // New expiration date in RFC3339 format
 }
+
+// PromotionStatus classifies a promotion by its soft-delete flag and validity period.
+type PromotionStatus string
+
+const (
+	// PromotionStatusActive covers the promotions that are not deleted and have not expired yet, including those that have not started.
+	PromotionStatusActive PromotionStatus = "active"
+	// PromotionStatusDeleted covers the logically deleted promotions.
+	PromotionStatusDeleted PromotionStatus = "deleted"
+	// PromotionStatusExpired covers the promotions that are not deleted and whose validity end date has passed.
+	PromotionStatusExpired PromotionStatus = "expired"
+)
+
+// Promotion types reported by PromotionDetail.
+const (
+	PromotionTypeDiscount  = "discount"
+	PromotionTypeFinancing = "financing"
+)
+
+// PromotionDetail is a discount or financing promotion looked up by its code.
+//
+//	@Summary		Promotion detail model
+//	@Description	Contains a discount or a financing promotion, depending on its type, together with its status.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PromotionDetail struct {
+	Type      string          `json:"type" example:"discount"`    // Promotion type: discount or financing
+	Status    PromotionStatus `json:"status" example:"active"`    // Status of the promotion: active, deleted or expired
+	IsDeleted bool            `json:"is_deleted" example:"false"` // Indicates if the promotion is logically deleted
+	Discount  *Discount       `json:"discount,omitempty"`         // Promotion details, for discounts
+	Financing *Financing      `json:"financing,omitempty"`        // Promotion details, for financings
+}
+
+// PromotionUpdate represents a request to edit a promotion. Only the fields present are changed.
+//
+//	@Summary		Promotion update model
+//	@Description	Used to edit the title, comments and rates of a promotion. Discount fields only apply to discounts and financing fields only to financings.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PromotionUpdate struct {
+	PromotionTitle     *string     `json:"promotion_title,omitempty" example:"Holiday Special"`               // New title of the promotion
+	Comments           *string     `json:"comments,omitempty" example:"Extended offer"`                       // New comments about the promotion
+	DiscountPercentage *Percentage `json:"discount_percentage,omitempty" swaggertype:"number" example:"12.5"` // New discount percentage, for discounts
+	PriceCap           *Money      `json:"price_cap,omitempty" swaggertype:"number" example:"4000.00"`        // New price cap, for discounts
+	OnlyCash           *bool       `json:"only_cash,omitempty" example:"false"`                               // New cash-only restriction, for discounts
+	NumberOfQuotas     *int        `json:"number_of_quotas,omitempty" example:"6"`                            // New number of installments, for financings
+	Interest           *Percentage `json:"interest,omitempty" swaggertype:"number" example:"3.5"`             // New interest rate, for financings
+}
@@ -0,0 +1,47 @@
+/*
+ * Payment Registration System - Promotion Simulation
+ * --------------------------------------------------
+ * This file defines the checkout quotes of a purchase: what it would cost on a card at a store on a
+ * day under each promotion that applies, paid at once or in quotas, before it is registered.
+ *
+ * Created: Apr. 10, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package models
+
+// PromotionSimulationRequest is a prospective purchase whose promotions are simulated.
+//
+//	@Summary		Promotion simulation request model
+//	@Description	Used to quote a purchase on a card at a store on a day under every applicable promotion. The optional number of quotas, interest and interest model quote installments without a financing promotion, as a purchase registered with them would be charged.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PromotionSimulationRequest struct {
+	CardNumber     string        `json:"card_number" example:"4000000000000001"`                       // Card the purchase would be made with
+	CuitStore      string        `json:"cuit_store" example:"30-98765432-1"`                           // CUIT of the store
+	Amount         Money         `json:"amount" swaggertype:"number" example:"1200.00"`                // Amount of the purchase, in pesos
+	PurchaseDate   string        `json:"purchase_date,omitempty" example:"2025-04-10T00:00:00Z"`       // Optional purchase date in RFC3339 format, defaults to now
+	NumberOfQuotas int           `json:"number_of_quotas,omitempty" example:"6"`                       // Optional installments without a financing promotion
+	Interest       Percentage    `json:"interest,omitempty" swaggertype:"number" example:"10"`         // Interest of the installments without a financing promotion
+	InterestModel  InterestModel `json:"interest_model,omitempty" swaggertype:"string" example:"flat"` // Interest model of the installments without a financing promotion, defaults to flat
+}
+
+// PurchaseQuote is the cost of a purchase paid at once or in quotas under a promotion, or under none.
+//
+//	@Summary		Purchase quote model
+//	@Description	Contains the final amount of a purchase under a promotion and, for installments, its installment plan. Applied marks the quote the promotion engine would choose if the purchase were registered with the same payment terms.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PurchaseQuote struct {
+	PurchaseType   PurchaseType     `json:"purchase_type" example:"1"`                           // Type of purchase (0 = single payment, 1 = installments)
+	PromotionType  string           `json:"promotion_type,omitempty" example:"financing"`        // Type of the promotion, empty without one
+	PromotionCode  string           `json:"promotion_code,omitempty" example:"FIN-2025"`         // Code of the promotion, empty without one
+	PromotionTitle string           `json:"promotion_title,omitempty" example:"Twelve quotas"`   // Title of the promotion
+	Discount       Money            `json:"discount" swaggertype:"number" example:"0.00"`        // Amount taken off by a discount
+	NumberOfQuotas int              `json:"number_of_quotas" example:"12"`                       // Number of quotas, 1 for single payments
+	FinalAmount    Money            `json:"final_amount" swaggertype:"number" example:"1624.69"` // Amount that would be charged, interest included
+	Applied        bool             `json:"applied" example:"true"`                              // Whether the promotion engine would choose this quote for these payment terms
+	Plan           *InstallmentPlan `json:"installment_plan,omitempty"`                          // Quota schedule of installments
+}
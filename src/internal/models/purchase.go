@@ -11,6 +11,10 @@
 
 package models
 
+import (
+	"time"
+)
+
 // Purchase represents a financial transaction made at a store.
 //
 //	@Summary		Purchase model
@@ -19,12 +23,15 @@ package models
 //	@Accept			json
 //	@Produce		json
 type Purchase struct {
-	PaymentVoucher string       `json:"payment_voucher" example:"VCHR-202502"` // Unique identifier for the purchase
-	Store          string       `json:"store" example:"ElectroStore"`          // Name of the store where the purchase was made
-	CuitStore      string       `json:"cuit_store" example:"30-98765432-1"`    // Unique tax identification code (CUIT) of the store
-	Amount         float64      `json:"amount" example:"1500.75"`              // Initial purchase amount before any adjustments
-	FinalAmount    float64      `json:"final_amount" example:"1400.00"`        // Final amount after discounts or interest
-	PurchaseType   PurchaseType `json:"purchase_type" example:"0"`             // Type of purchase (single payment or installments)
+	PaymentVoucher string       `json:"payment_voucher" example:"VCHR-202502"`               // Unique identifier for the purchase
+	Store          string       `json:"store" example:"ElectroStore"`                        // Name of the store where the purchase was made
+	CuitStore      string       `json:"cuit_store" example:"30-98765432-1"`                  // Unique tax identification code (CUIT) of the store
+	Amount         Money        `json:"amount" swaggertype:"number" example:"1500.75"`       // Initial purchase amount before any adjustments
+	FinalAmount    Money        `json:"final_amount" swaggertype:"number" example:"1400.00"` // Final amount after discounts or interest
+	Currency       Currency     `json:"currency" swaggertype:"string" example:"ARS"`         // Currency of the amounts
+	PurchaseType   PurchaseType `json:"purchase_type" example:"0"`                           // Type of purchase (single payment or installments)
+	PurchaseDate   time.Time    `json:"purchase_date" example:"2025-02-01T00:00:00Z"`        // Date the purchase was made
+	PromotionCode  string       `json:"promotion_code,omitempty" example:"PROMO2025"`        // Code of the promotion applied to the purchase, if any
 }
 
 // PurchaseSinglePayment represents a single-payment purchase.
@@ -36,7 +43,8 @@ type Purchase struct {
 //	@Produce		json
 type PurchaseSinglePayment struct {
 	Purchase
-	StoreDiscount float64 `json:"store_discount" example:"5.0"` // Discount applied to the purchase
+	StoreDiscount Percentage `json:"store_discount" swaggertype:"number" example:"5.0"`           // Discount applied to the purchase
+	PaidAmount    Money      `json:"paid_amount,omitempty" swaggertype:"number" example:"900.00"` // Amount paid of the purchase, in pesos
 }
 
 // PurchaseMonthlyPayment represents a purchase paid in monthly installments.
@@ -48,9 +56,30 @@ type PurchaseSinglePayment struct {
 //	@Produce		json
 type PurchaseMonthlyPayment struct {
 	Purchase
-	Interest       float64 `json:"interest" example:"3.5"`        // Interest rate applied to the purchase
-	NumberOfQuotas int     `json:"number_of_quotas" example:"12"` // Number of monthly installments
-	Quota          []Quota `json:"quota"`                         // Breakdown of installment payments
+	Interest       Percentage    `json:"interest" swaggertype:"number" example:"3.5"`          // Interest rate applied to the purchase, read according to the interest model
+	InterestModel  InterestModel `json:"interest_model" swaggertype:"string" example:"french"` // How the interest of the quotas is computed
+	NumberOfQuotas int           `json:"number_of_quotas" example:"12"`                        // Number of monthly installments
+	Quota          []Quota       `json:"quota"`                                                // Breakdown of installment payments
+}
+
+// PurchaseRequest represents a request to register a new purchase on a card.
+//
+//	@Summary		PurchaseRequest model
+//	@Description	Contains the data required to register a single-payment or installment purchase. The payment voucher and the final amount are computed by the system.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type PurchaseRequest struct {
+	PurchaseType   PurchaseType  `json:"purchase_type" example:"1"`                          // Type of purchase (0 = single payment, 1 = installments)
+	Store          string        `json:"store" example:"ElectroStore"`                       // Name of the store where the purchase was made
+	CuitStore      string        `json:"cuit_store" example:"30-98765432-1"`                 // Unique tax identification code (CUIT) of the store
+	Amount         Money         `json:"amount" swaggertype:"number" example:"1500.75"`      // Initial purchase amount before any adjustments
+	Currency       Currency      `json:"currency" swaggertype:"string" example:"USD"`        // Optional currency of the amount, defaults to ARS. Installments must be in ARS
+	StoreDiscount  Percentage    `json:"store_discount" swaggertype:"number" example:"5.0"`  // Discount applied by the store (single payments only)
+	Interest       Percentage    `json:"interest" swaggertype:"number" example:"3.5"`        // Interest rate when no financing promotion applies (installments only)
+	InterestModel  InterestModel `json:"interest_model" swaggertype:"string" example:"flat"` // Interest model when no financing promotion applies, defaults to flat (installments only)
+	NumberOfQuotas int           `json:"number_of_quotas" example:"12"`                      // Number of monthly installments (installments only)
+	PurchaseDate   string        `json:"purchase_date" example:"2025-02-01T00:00:00Z"`       // Optional purchase date in RFC3339 format, defaults to now
 }
 
 // PurchaseType represents the type of a purchase, either single payment or monthly payments.
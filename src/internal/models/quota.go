@@ -17,8 +17,24 @@ package models
 //	@Accept			json
 //	@Produce		json
 type Quota struct {
-	Number int     `json:"number" example:"1"`       // Installment number
-	Price  float64 `json:"price" example:"125.50"`   // Price of the installment
-	Month  string  `json:"month" example:"February"` // Month when the installment is due
-	Year   string  `json:"year" example:"2025"`      // Year when the installment is due
+	Number     int    `json:"number" example:"1"`                                          // Installment number
+	Price      Money  `json:"price" swaggertype:"number" example:"125.50"`                 // Price of the installment
+	Month      string `json:"month" example:"February"`                                    // Month when the installment is due
+	Year       string `json:"year" example:"2025"`                                         // Year when the installment is due
+	PaidAmount Money  `json:"paid_amount,omitempty" swaggertype:"number" example:"100.00"` // Amount paid of the installment
+}
+
+// DueQuota represents an installment quota billed in a payment summary, along with the purchase it belongs to.
+//
+//	@Summary		Due quota model
+//	@Description	Contains an installment quota due in a given month and the details of the installment purchase it belongs to.
+//	@Tags			Models
+//	@Accept			json
+//	@Produce		json
+type DueQuota struct {
+	Quota
+	PaymentVoucher string `json:"payment_voucher" example:"VCHR-202502"` // Payment voucher of the installment purchase
+	Store          string `json:"store" example:"ElectroStore"`          // Name of the store where the purchase was made
+	CuitStore      string `json:"cuit_store" example:"30-98765432-1"`    // Unique tax identification code (CUIT) of the store
+	NumberOfQuotas int    `json:"number_of_quotas" example:"12"`         // Total number of installments of the purchase
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
@@ -12,12 +14,54 @@ import (
 // providing a clear contract for managing banks and their associated promotions and customers.
 type BankService interface {
 
+	// CreateBank validates and registers a new bank.
+	// Parameters:
+	// - bank: A Bank object containing the bank details.
+	// Returns:
+	// - *models.Bank: The registered bank.
+	// - error: A validation error if the name or CUIT are invalid,
+	//   storage.ErrAlreadyExists if a bank with the same CUIT exists, otherwise nil.
+	CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error)
+
+	// GetBanks retrieves all banks.
+	// Returns:
+	// - *[]models.Bank: The banks ordered by CUIT.
+	// - error: An error if the operation fails, otherwise nil.
+	GetBanks(ctx context.Context) (*[]models.Bank, error)
+
+	// GetBankByCuit retrieves a bank by its CUIT.
+	// Parameters:
+	// - cuit: The CUIT of the bank.
+	// Returns:
+	// - *models.Bank: The bank.
+	// - error: storage.ErrNotFound if the bank does not exist, otherwise nil.
+	GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error)
+
+	// UpdateBank validates and replaces the details of a bank. The CUIT cannot be changed.
+	// Parameters:
+	// - cuit: The CUIT of the bank.
+	// - bank: A Bank object containing the new name, address and telephone.
+	// Returns:
+	// - *models.Bank: The updated bank.
+	// - error: A validation error if the name is missing or the CUIT is changed,
+	//   storage.ErrNotFound if the bank does not exist, otherwise nil.
+	UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error)
+
 	// AddFinancingPromotionToBank adds a new financing promotion to a specific bank.
+	// The number of quotas, the interest and the interest model are checked; the interest model defaults to flat.
 	// Parameters:
 	// - promotionFinancing: A Financing object containing the promotion details.
 	// Returns:
-	// - error: An error if the operation fails, otherwise nil.
-	AddFinancingPromotionToBank(promotionFinancing models.Financing) error
+	// - error: A validation error if the financing terms are invalid, an error if the operation fails, otherwise nil.
+	AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error
+
+	// AddDiscountPromotionToBank validates and adds a new discount promotion to a specific bank.
+	// Parameters:
+	// - promotionDiscount: A Discount object containing the promotion details.
+	// Returns:
+	// - error: A validation error if the discount percentage, price cap or validity dates are invalid,
+	//   storage.ErrNotFound if the bank does not exist, otherwise nil.
+	AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error
 
 	// ExtendFinancingPromotionValidity extends the validity period of a financing promotion.
 	// Parameters:
@@ -25,7 +69,7 @@ type BankService interface {
 	// - newDate: The new expiration date for the promotion.
 	// Returns:
 	// - error: An error if the operation fails, otherwise nil.
-	ExtendFinancingPromotionValidity(code string, newDate time.Time) error
+	ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error
 
 	// ExtendDiscountPromotionValidity extends the validity period of a discount promotion.
 	// Parameters:
@@ -33,27 +77,27 @@ type BankService interface {
 	// - newDate: The new expiration date for the promotion.
 	// Returns:
 	// - error: An error if the operation fails, otherwise nil.
-	ExtendDiscountPromotionValidity(code string, newDate time.Time) error
+	ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error
 
 	// DeleteFinancingPromotion logically deletes a financing promotion by marking it as inactive.
 	// Parameters:
 	// - code: The unique identifier of the promotion.
 	// Returns:
 	// - error: An error if the operation fails, otherwise nil.
-	DeleteFinancingPromotion(code string) error
+	DeleteFinancingPromotion(ctx context.Context, code string) error
 
 	// DeleteDiscountPromotion logically deletes a discount promotion by marking it as inactive.
 	// Parameters:
 	// - code: The unique identifier of the promotion.
 	// Returns:
 	// - error: An error if the operation fails, otherwise nil.
-	DeleteDiscountPromotion(code string) error
+	DeleteDiscountPromotion(ctx context.Context, code string) error
 
 	// GetBankCustomerCounts retrieves the count of customers associated with each bank.
 	// Returns:
 	// - []models.BankCustomerCountDTO: A slice of BankCustomerCountDTO containing the bank name, CUIT, and customer count.
 	// - error: An error if the operation fails, otherwise nil.
-	GetBankCustomerCounts() ([]models.BankCustomerCountDTO, error)
+	GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error)
 }
 
 // service is a concrete implementation of the BankService interface.
@@ -73,32 +117,136 @@ func NewBankService(repo storage.IBankStorage) BankService {
 	}
 }
 
+// CreateBank validates and registers a new bank.
+func (s *bankService) CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error) {
+	bank = normalizeBank(bank)
+	if err := validateCuit("bank CUIT", bank.Cuit); err != nil {
+		return nil, err
+	}
+	if bank.Name == "" {
+		return nil, validationError("bank name is required")
+	}
+	return s.repo.CreateBank(ctx, bank)
+}
+
+// GetBanks retrieves all banks.
+func (s *bankService) GetBanks(ctx context.Context) (*[]models.Bank, error) {
+	return s.repo.GetBanks(ctx)
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+func (s *bankService) GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error) {
+	return s.repo.GetBankByCuit(ctx, strings.TrimSpace(cuit))
+}
+
+// UpdateBank validates and replaces the details of a bank.
+func (s *bankService) UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	cuit = strings.TrimSpace(cuit)
+	bank = normalizeBank(bank)
+	if bank.Cuit != "" && bank.Cuit != cuit {
+		return nil, validationError("bank CUIT cannot be changed from %s to %s", cuit, bank.Cuit)
+	}
+	if bank.Name == "" {
+		return nil, validationError("bank name is required")
+	}
+	bank.Cuit = cuit
+	return s.repo.UpdateBank(ctx, cuit, bank)
+}
+
 // AddFinancingPromotionToBank adds a new financing promotion to a specific bank.
-func (s *bankService) AddFinancingPromotionToBank(promotionFinancing models.Financing) error {
-	return s.repo.AddFinancingPromotionToBank(promotionFinancing)
+func (s *bankService) AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error {
+	if err := validateFinancingTerms(promotionFinancing.InterestModel, promotionFinancing.Interest, promotionFinancing.NumberOfQuotas); err != nil {
+		return err
+	}
+	promotionFinancing.InterestModel = promotionFinancing.InterestModel.OrFlat()
+	return s.repo.AddFinancingPromotionToBank(ctx, promotionFinancing)
+}
+
+// AddDiscountPromotionToBank validates and adds a new discount promotion to a specific bank.
+func (s *bankService) AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error {
+	if err := validateDiscount(promotionDiscount); err != nil {
+		return err
+	}
+	return s.repo.AddDiscountPromotionToBank(ctx, promotionDiscount)
 }
 
 // ExtendFinancingPromotionValidity extends the validity period of a financing promotion.
-func (s *bankService) ExtendFinancingPromotionValidity(code string, newDate time.Time) error {
-	return s.repo.ExtendFinancingPromotionValidity(code, newDate)
+func (s *bankService) ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	return s.repo.ExtendFinancingPromotionValidity(ctx, code, newDate)
 }
 
 // ExtendDiscountPromotionValidity extends the validity period of a discount promotion.
-func (s *bankService) ExtendDiscountPromotionValidity(code string, newDate time.Time) error {
-	return s.repo.ExtendDiscountPromotionValidity(code, newDate)
+func (s *bankService) ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	return s.repo.ExtendDiscountPromotionValidity(ctx, code, newDate)
 }
 
 // DeleteFinancingPromotion logically deletes a financing promotion by marking it as inactive.
-func (s *bankService) DeleteFinancingPromotion(code string) error {
-	return s.repo.DeleteFinancingPromotion(code)
+func (s *bankService) DeleteFinancingPromotion(ctx context.Context, code string) error {
+	return s.repo.DeleteFinancingPromotion(ctx, code)
 }
 
 // DeleteDiscountPromotion logically deletes a discount promotion by marking it as inactive.
-func (s *bankService) DeleteDiscountPromotion(code string) error {
-	return s.repo.DeleteDiscountPromotion(code)
+func (s *bankService) DeleteDiscountPromotion(ctx context.Context, code string) error {
+	return s.repo.DeleteDiscountPromotion(ctx, code)
 }
 
 // GetBankCustomerCounts retrieves the count of customers associated with each bank.
-func (s *bankService) GetBankCustomerCounts() ([]models.BankCustomerCountDTO, error) {
-	return s.repo.GetBankCustomerCounts()
+func (s *bankService) GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error) {
+	return s.repo.GetBankCustomerCounts(ctx)
+}
+
+// normalizeBank trims the surrounding whitespace of the bank's text fields.
+func normalizeBank(bank models.Bank) models.Bank {
+	bank.Name = strings.TrimSpace(bank.Name)
+	bank.Cuit = strings.TrimSpace(bank.Cuit)
+	bank.Address = strings.TrimSpace(bank.Address)
+	bank.Telephone = strings.TrimSpace(bank.Telephone)
+	return bank
+}
+
+// validateDiscount checks the fields of a discount promotion before it is stored.
+func validateDiscount(discount models.Discount) error {
+	if strings.TrimSpace(discount.Code) == "" {
+		return validationError("promotion code is required")
+	}
+	if err := validateCuit("bank CUIT", strings.TrimSpace(discount.Bank.Cuit)); err != nil {
+		return err
+	}
+	if err := validateCuit("store CUIT", discount.CuitStore); err != nil {
+		return err
+	}
+	if discount.DiscountPercentage <= 0 || discount.DiscountPercentage > models.HundredPercent {
+		return validationError("discount percentage must be greater than 0 and at most 100, got %s", discount.DiscountPercentage)
+	}
+	if discount.PriceCap < 0 {
+		return validationError("price cap cannot be negative, got %s", discount.PriceCap)
+	}
+
+	startDate, err := time.Parse(time.RFC3339, discount.ValidityStartDate)
+	if err != nil {
+		return validationError("validity start date '%s' is not a valid RFC3339 date", discount.ValidityStartDate)
+	}
+	endDate, err := time.Parse(time.RFC3339, discount.ValidityEndDate)
+	if err != nil {
+		return validationError("validity end date '%s' is not a valid RFC3339 date", discount.ValidityEndDate)
+	}
+	if !endDate.After(startDate) {
+		return validationError("validity end date %s must be after the start date %s", discount.ValidityEndDate, discount.ValidityStartDate)
+	}
+	return nil
+}
+
+// validateFinancingTerms checks the number of quotas and the interest of a financing promotion against its interest model.
+func validateFinancingTerms(model models.InterestModel, interest models.Percentage, numberOfQuotas int) error {
+	calculator, err := InstallmentCalculatorFor(model)
+	if err != nil {
+		return err
+	}
+	if err := validateNumberOfQuotas(numberOfQuotas); err != nil {
+		return err
+	}
+	if interest < 0 {
+		return validationError("interest cannot be negative, got %s", interest)
+	}
+	return calculator.ValidateInterest(interest)
 }
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *bankStorageStub) CreateBank(_ context.Context, bank models.Bank) (*models.Bank, error) {
+	for _, existing := range s.banks {
+		if existing.Cuit == bank.Cuit {
+			return nil, storage.ErrAlreadyExists
+		}
+	}
+	s.banks = append(s.banks, bank)
+	return &bank, nil
+}
+
+func (s *bankStorageStub) UpdateBank(_ context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	for i, existing := range s.banks {
+		if existing.Cuit == cuit {
+			s.banks[i] = bank
+			return &bank, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *bankStorageStub) AddDiscountPromotionToBank(_ context.Context, promotionDiscount models.Discount) error {
+	if promotionDiscount.Bank.Cuit != "30-12345678-9" {
+		return storage.ErrNotFound
+	}
+	s.discounts = append(s.discounts, promotionDiscount)
+	return nil
+}
+
+func TestAddDiscountPromotionToBank(t *testing.T) {
+	ctx := context.Background()
+	valid := models.Discount{
+		Promotion: models.Promotion{
+			Code:              "DISC-2025",
+			PromotionTitle:    "Back to school",
+			NameStore:         "Tech Store",
+			CuitStore:         "30-98765432-1",
+			ValidityStartDate: "2025-03-01T00:00:00Z",
+			ValidityEndDate:   "2025-03-31T00:00:00Z",
+			Bank:              models.Bank{Cuit: "30-12345678-9"},
+		},
+		DiscountPercentage: models.MustParsePercentage("15"),
+		PriceCap:           models.MustParseMoney("5000"),
+	}
+
+	banks := &bankStorageStub{}
+	service := NewBankService(banks)
+
+	assert.NoError(t, service.AddDiscountPromotionToBank(ctx, valid))
+	assert.Len(t, banks.discounts, 1)
+
+	unknownBank := valid
+	unknownBank.Bank = models.Bank{Cuit: "30-99999999-9"}
+	assert.ErrorIs(t, service.AddDiscountPromotionToBank(ctx, unknownBank), storage.ErrNotFound)
+
+	tests := []struct {
+		name   string
+		mutate func(d *models.Discount)
+	}{
+		{"missing code", func(d *models.Discount) { d.Code = " " }},
+		{"malformed bank CUIT", func(d *models.Discount) { d.Bank.Cuit = "30123456789" }},
+		{"malformed store CUIT", func(d *models.Discount) { d.CuitStore = "store" }},
+		{"no discount", func(d *models.Discount) { d.DiscountPercentage = 0 }},
+		{"discount above 100", func(d *models.Discount) { d.DiscountPercentage = models.MustParsePercentage("100.5") }},
+		{"negative price cap", func(d *models.Discount) { d.PriceCap = models.MustParseMoney("-1") }},
+		{"malformed start date", func(d *models.Discount) { d.ValidityStartDate = "2025-03-01" }},
+		{"malformed end date", func(d *models.Discount) { d.ValidityEndDate = "" }},
+		{"end before start", func(d *models.Discount) { d.ValidityEndDate = "2025-02-28T00:00:00Z" }},
+		{"end equal to start", func(d *models.Discount) { d.ValidityEndDate = d.ValidityStartDate }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discount := valid
+			tt.mutate(&discount)
+
+			err := service.AddDiscountPromotionToBank(ctx, discount)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+	assert.Len(t, banks.discounts, 1)
+}
+
+func (s *bankStorageStub) AddFinancingPromotionToBank(_ context.Context, promotionFinancing models.Financing) error {
+	s.financings = append(s.financings, promotionFinancing)
+	return nil
+}
+
+func TestAddFinancingPromotionToBank(t *testing.T) {
+	ctx := context.Background()
+	valid := models.Financing{
+		Promotion:      models.Promotion{Code: "FIN-2025", Bank: models.Bank{Cuit: "30-12345678-9"}},
+		NumberOfQuotas: 6,
+		Interest:       models.MustParsePercentage("5"),
+	}
+
+	banks := &bankStorageStub{}
+	service := NewBankService(banks)
+
+	assert.NoError(t, service.AddFinancingPromotionToBank(ctx, valid))
+	assert.Equal(t, models.InterestModelFlat, banks.financings[0].InterestModel, "the interest model defaults to flat")
+
+	tests := []struct {
+		name   string
+		mutate func(f *models.Financing)
+	}{
+		{"no quotas", func(f *models.Financing) { f.NumberOfQuotas = 0 }},
+		{"too many quotas", func(f *models.Financing) { f.NumberOfQuotas = maxQuotas + 1 }},
+		{"negative interest", func(f *models.Financing) { f.Interest = models.MustParsePercentage("-1") }},
+		{"unknown interest model", func(f *models.Financing) { f.InterestModel = "german" }},
+		{"interest on a zero-interest financing", func(f *models.Financing) { f.InterestModel = models.InterestModelZero }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			financing := valid
+			tt.mutate(&financing)
+
+			err := service.AddFinancingPromotionToBank(ctx, financing)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+	assert.Len(t, banks.financings, 1)
+}
+
+func TestCreateBank(t *testing.T) {
+	ctx := context.Background()
+	banks := &bankStorageStub{}
+	service := NewBankService(banks)
+
+	created, err := service.CreateBank(ctx, models.Bank{Name: " Santander ", Cuit: "30-12345678-9", Address: "123 Main St"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Santander", created.Name)
+
+	_, err = service.CreateBank(ctx, models.Bank{Name: "Santander Río", Cuit: "30-12345678-9"})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	_, err = service.CreateBank(ctx, models.Bank{Name: "", Cuit: "30-98765432-1"})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.CreateBank(ctx, models.Bank{Name: "BBVA", Cuit: "30987654321"})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	assert.Len(t, banks.banks, 1)
+}
+
+func TestUpdateBank(t *testing.T) {
+	ctx := context.Background()
+	banks := &bankStorageStub{banks: []models.Bank{{Name: "Santander", Cuit: "30-12345678-9"}}}
+	service := NewBankService(banks)
+
+	updated, err := service.UpdateBank(ctx, "30-12345678-9", models.Bank{Name: "Santander Río", Telephone: "0800-333-2000"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Santander Río", updated.Name)
+	assert.Equal(t, "30-12345678-9", updated.Cuit)
+
+	_, err = service.UpdateBank(ctx, "30-12345678-9", models.Bank{Name: "Santander", Cuit: "30-98765432-1"})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.UpdateBank(ctx, "30-12345678-9", models.Bank{Name: " "})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.UpdateBank(ctx, "30-00000000-0", models.Bank{Name: "Unknown"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
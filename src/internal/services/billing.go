@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// BillingService defines the interface for billing-cycle operations.
+// This service abstracts business logic and data layer interactions,
+// providing a clear contract for configuring the billing cycles of banks and closing the monthly payment summaries of cards.
+type BillingService interface {
+	// ConfigureBillingCycle validates and stores the billing cycle configuration of a bank.
+	// Parameters:
+	// - cycle: The billing cycle configuration, identified by the CUIT of the bank.
+	// Returns:
+	// - *models.BillingCycle: The stored configuration.
+	// - error: An error wrapping ErrValidation if the configuration is invalid, or any storage error.
+	ConfigureBillingCycle(ctx context.Context, cycle models.BillingCycle) (*models.BillingCycle, error)
+
+	// GetBillingCycle retrieves the billing cycle configuration of a bank.
+	// Banks without a configuration of their own use models.DefaultBillingCycle.
+	// Parameters:
+	// - bankCuit: The CUIT of the bank.
+	// Returns:
+	// - *models.BillingCycle: The billing cycle configuration of the bank.
+	// - error: An error if the bank does not exist or the operation fails, otherwise nil.
+	GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error)
+
+	// CloseCycle closes the billing cycle of a card for a month and stores its payment summary.
+	// The cycle of month M covers the purchases made after the closing date of month M-1 up to
+	// the closing date of month M. A summary is immutable: a cycle can only be closed once, and
+	// only once its closing date has passed.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// - month: The month of the cycle to close.
+	// - year: The year of the cycle to close.
+	// Returns:
+	// - *models.PaymentSummary: The stored payment summary.
+	// - error: An error wrapping ErrValidation if the cycle cannot be closed yet or a closing rate is missing, a wrapped storage.ErrAlreadyExists
+	//   if it was already closed, or any storage error.
+	CloseCycle(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error)
+}
+
+// billingService is a concrete implementation of the BillingService interface.
+// It uses the bank repository for the billing configuration, the card repository for purchases and summaries,
+// and the exchange rate repository for the closing rates.
+type billingService struct {
+	banks storage.IBankStorage
+	cards storage.ICardStorage
+	rates storage.IExchangeRateStorage
+	now   func() time.Time
+}
+
+// NewBillingService creates and initializes a new BillingService instance.
+// Parameters:
+// - banks: An IBankStorage repository interface holding the billing cycle configuration of the banks.
+// - cards: An ICardStorage repository interface holding the purchases and payment summaries of the cards.
+// - rates: An IExchangeRateStorage repository interface holding the daily exchange rates of foreign currencies.
+// Returns:
+// - BillingService: A new instance of the service struct implementing the BillingService interface.
+func NewBillingService(banks storage.IBankStorage, cards storage.ICardStorage, rates storage.IExchangeRateStorage) BillingService {
+	return &billingService{
+		banks: banks,
+		cards: cards,
+		rates: rates,
+		now:   time.Now,
+	}
+}
+
+// ConfigureBillingCycle validates and stores the billing cycle configuration of a bank.
+func (s *billingService) ConfigureBillingCycle(ctx context.Context, cycle models.BillingCycle) (*models.BillingCycle, error) {
+	if err := validateCuit("bank CUIT", cycle.BankCuit); err != nil {
+		return nil, err
+	}
+	if cycle.ClosingDay < 1 || cycle.ClosingDay > 31 {
+		return nil, validationError("closing day must be between 1 and 31, got %d", cycle.ClosingDay)
+	}
+	if cycle.FirstDueDays < 1 {
+		return nil, validationError("first due days must be at least 1, got %d", cycle.FirstDueDays)
+	}
+	if cycle.SecondDueDays < 0 {
+		return nil, validationError("second due days cannot be negative, got %d", cycle.SecondDueDays)
+	}
+	if cycle.SurchargePercentage < 0 || cycle.SurchargePercentage > models.HundredPercent {
+		return nil, validationError("surcharge percentage must be between 0 and 100, got %s", cycle.SurchargePercentage)
+	}
+
+	if err := s.banks.SaveBillingCycle(ctx, cycle); err != nil {
+		return nil, err
+	}
+	return &cycle, nil
+}
+
+// GetBillingCycle retrieves the billing cycle configuration of a bank, falling back to the default one.
+func (s *billingService) GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error) {
+	return billingCycleOf(ctx, s.banks, bankCuit)
+}
+
+// billingCycleOf retrieves the billing cycle configuration of a bank, falling back to the default one.
+func billingCycleOf(ctx context.Context, banks storage.IBankStorage, bankCuit string) (*models.BillingCycle, error) {
+	cycle, err := banks.GetBillingCycle(ctx, bankCuit)
+	if err != nil {
+		return nil, err
+	}
+	if cycle == nil {
+		defaultCycle := models.DefaultBillingCycle(bankCuit)
+		return &defaultCycle, nil
+	}
+	return cycle, nil
+}
+
+// CloseCycle closes the billing cycle of a card for a month and stores its payment summary.
+func (s *billingService) CloseCycle(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	if month < 1 || month > 12 {
+		return nil, validationError("month must be between 1 and 12, got %d", month)
+	}
+	if year < 1 {
+		return nil, validationError("year must be positive, got %d", year)
+	}
+
+	card, err := s.cards.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	cycle, err := s.GetBillingCycle(ctx, card.Bank.Cuit)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cycle covers whole days: from the day after the previous closing date to the end of the closing date
+	periodStart, periodEnd := cyclePeriod(*cycle, month, year)
+	if s.now().Before(periodEnd) {
+		return nil, validationError("the %02d/%d cycle of card %s closes on %s and cannot be closed yet",
+			month, year, cardNumber, periodEnd.AddDate(0, 0, -1).Format(time.DateOnly))
+	}
+
+	singlePayments, monthlyPayments, err := s.cards.GetPurchasesInPeriod(ctx, cardNumber, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Installment purchases are billed through their quotas, including those of purchases from previous cycles
+	quotas, err := s.cards.GetQuotasDueInMonth(ctx, cardNumber, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	subtotals, err := s.currencySubtotals(ctx, *singlePayments, *quotas, periodEnd.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	var total models.Money
+	for _, subtotal := range subtotals {
+		total += subtotal.Total
+	}
+
+	firstExpiration := periodEnd.AddDate(0, 0, cycle.FirstDueDays-1)
+	summary := models.PaymentSummary{
+		Code:                fmt.Sprintf("SUMMARY-%s-%d-%02d", cardNumber, year, month),
+		Month:               month,
+		Year:                year,
+		FirstExpiration:     firstExpiration,
+		SecondExpiration:    firstExpiration.AddDate(0, 0, cycle.SecondDueDays),
+		SurchargePercentage: cycle.SurchargePercentage,
+		TotalPrice:          total,
+		Subtotals:           subtotals,
+		SinglePayments:      *singlePayments,
+		MonthlyPayments:     *monthlyPayments,
+		Quotas:              *quotas,
+		Card:                *card,
+	}
+
+	return s.cards.SavePaymentSummary(ctx, cardNumber, summary)
+}
+
+// currencySubtotals adds up the purchases of a cycle by currency, the base currency first, and converts each subtotal
+// to pesos at the rate of its currency on the closing date. Quotas are always in the base currency.
+func (s *billingService) currencySubtotals(ctx context.Context, singlePayments []models.PurchaseSinglePayment, quotas []models.DueQuota, closing time.Time) ([]models.CurrencySubtotal, error) {
+	amounts := map[models.Currency]models.Money{models.BaseCurrency: 0}
+	for _, purchase := range singlePayments {
+		amounts[purchase.Currency.OrBase()] += purchase.FinalAmount
+	}
+	for _, quota := range quotas {
+		amounts[models.BaseCurrency] += quota.Price
+	}
+
+	subtotals := []models.CurrencySubtotal{}
+	for _, currency := range models.Currencies {
+		amount, ok := amounts[currency]
+		if !ok {
+			continue
+		}
+		if currency == models.BaseCurrency {
+			subtotals = append(subtotals, models.CurrencySubtotal{Currency: currency, Subtotal: amount, ExchangeRate: models.MustParseMoney("1"), Total: amount})
+			continue
+		}
+
+		rate, err := s.rates.GetExchangeRate(ctx, currency, closing)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, validationError("there is no %s exchange rate on or before the closing date %s", currency, closing.Format(time.DateOnly))
+		}
+		if err != nil {
+			return nil, err
+		}
+		subtotals = append(subtotals, models.CurrencySubtotal{Currency: currency, Subtotal: amount, ExchangeRate: rate.Rate, Total: rate.Convert(amount)})
+	}
+	return subtotals, nil
+}
+
+// closingDate returns the date on which the cycle of the given month closes.
+// Closing days beyond the end of the month are clamped to its last day.
+func closingDate(cycle models.BillingCycle, month int, year int) time.Time {
+	lastDay := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	day := cycle.ClosingDay
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// cycleMonth returns the first day of the month whose cycle covers the given date: the month of the date, or the
+// next one when the date is after its closing date.
+func cycleMonth(cycle models.BillingCycle, date time.Time) time.Time {
+	date = date.UTC()
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !date.Before(closingDate(cycle, int(date.Month()), date.Year()).AddDate(0, 0, 1)) {
+		month = month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
+// cyclePeriod returns the [start, end) period covered by the cycle of the given month:
+// from the day after the previous month's closing date to the day after this month's closing date.
+func cyclePeriod(cycle models.BillingCycle, month int, year int) (time.Time, time.Time) {
+	previousMonth := time.Date(year, time.Month(month)-1, 1, 0, 0, 0, 0, time.UTC)
+	start := closingDate(cycle, int(previousMonth.Month()), previousMonth.Year()).AddDate(0, 0, 1)
+	end := closingDate(cycle, month, year).AddDate(0, 0, 1)
+	return start, end
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// bankStorageStub is an in-test IBankStorage holding the billing cycles of the bank with CUIT 30-12345678-9.
+type bankStorageStub struct {
+	storage.IBankStorage
+	cycle      *models.BillingCycle
+	discounts  []models.Discount
+	financings []models.Financing
+	banks      []models.Bank
+}
+
+func (s *bankStorageStub) SaveBillingCycle(_ context.Context, cycle models.BillingCycle) error {
+	if cycle.BankCuit != "30-12345678-9" {
+		return storage.ErrNotFound
+	}
+	s.cycle = &cycle
+	return nil
+}
+
+func (s *bankStorageStub) GetBillingCycle(_ context.Context, bankCuit string) (*models.BillingCycle, error) {
+	if bankCuit != "30-12345678-9" {
+		return nil, storage.ErrNotFound
+	}
+	return s.cycle, nil
+}
+
+func (s *cardStorageStub) GetPurchasesInPeriod(_ context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error) {
+	s.periodFrom, s.periodTo = from, to
+	singles := []models.PurchaseSinglePayment{}
+	for _, purchase := range s.singles {
+		if !purchase.PurchaseDate.Before(from) && purchase.PurchaseDate.Before(to) {
+			singles = append(singles, purchase)
+		}
+	}
+	monthlys := []models.PurchaseMonthlyPayment{}
+	for _, purchase := range s.monthlys {
+		if !purchase.PurchaseDate.Before(from) && purchase.PurchaseDate.Before(to) {
+			monthlys = append(monthlys, purchase)
+		}
+	}
+	return &singles, &monthlys, nil
+}
+
+func (s *cardStorageStub) GetQuotasDueInMonth(_ context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error) {
+	quotas := []models.DueQuota{}
+	for _, purchase := range s.monthlys {
+		for _, quota := range purchase.Quota {
+			if quota.Month == fmt.Sprintf("%02d", month) && quota.Year == fmt.Sprintf("%d", year) {
+				quotas = append(quotas, models.DueQuota{Quota: quota, PaymentVoucher: purchase.PaymentVoucher, NumberOfQuotas: purchase.NumberOfQuotas})
+			}
+		}
+	}
+	return &quotas, nil
+}
+
+func (s *cardStorageStub) SavePaymentSummary(_ context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error) {
+	for _, saved := range s.summaries {
+		if saved.Month == summary.Month && saved.Year == summary.Year {
+			return nil, storage.ErrAlreadyExists
+		}
+	}
+	s.summaries = append(s.summaries, summary)
+	return &summary, nil
+}
+
+// exchangeRateStorageStub is an in-test IExchangeRateStorage holding rates ordered by day.
+type exchangeRateStorageStub struct {
+	storage.IExchangeRateStorage
+	rates []models.ExchangeRate
+}
+
+func (s *exchangeRateStorageStub) SaveExchangeRates(_ context.Context, rates []models.ExchangeRate) error {
+	s.rates = append(s.rates, rates...)
+	return nil
+}
+
+func (s *exchangeRateStorageStub) GetExchangeRate(_ context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error) {
+	for i := len(s.rates) - 1; i >= 0; i-- {
+		if s.rates[i].Currency == currency && !s.rates[i].Date.After(date) {
+			return &s.rates[i], nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func newBillingServiceAt(now time.Time, banks *bankStorageStub, cards *cardStorageStub, rates *exchangeRateStorageStub) BillingService {
+	service := NewBillingService(banks, cards, rates).(*billingService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func singlePurchaseAt(finalAmount models.Money, date time.Time) models.PurchaseSinglePayment {
+	return models.PurchaseSinglePayment{Purchase: models.Purchase{FinalAmount: finalAmount, PurchaseDate: date}}
+}
+
+func monthlyPurchaseAt(voucher string, finalAmount models.Money, numberOfQuotas int, date time.Time) models.PurchaseMonthlyPayment {
+	return models.PurchaseMonthlyPayment{
+		Purchase:       models.Purchase{PaymentVoucher: voucher, FinalAmount: finalAmount, PurchaseDate: date},
+		NumberOfQuotas: numberOfQuotas,
+		Quota:          GenerateQuotas(finalAmount, numberOfQuotas, date),
+	}
+}
+
+func TestCloseCycleWithDefaultBillingCycle(t *testing.T) {
+	ctx := context.Background()
+	cards := &cardStorageStub{
+		singles: []models.PurchaseSinglePayment{
+			singlePurchaseAt(models.MustParseMoney("100.10"), time.Date(2024, time.September, 30, 23, 0, 0, 0, time.UTC)),
+			singlePurchaseAt(models.MustParseMoney("200.20"), time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)),
+			singlePurchaseAt(models.MustParseMoney("300.30"), time.Date(2024, time.October, 31, 23, 59, 0, 0, time.UTC)),
+		},
+		monthlys: []models.PurchaseMonthlyPayment{
+			monthlyPurchaseAt("V-AUG", models.MustParseMoney("150"), 3, time.Date(2024, time.August, 20, 0, 0, 0, 0, time.UTC)),
+			monthlyPurchaseAt("V-OCT", models.MustParseMoney("330"), 3, time.Date(2024, time.October, 14, 0, 0, 0, 0, time.UTC)),
+			monthlyPurchaseAt("V-JUN", models.MustParseMoney("120"), 3, time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	service := newBillingServiceAt(time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), &bankStorageStub{}, cards, &exchangeRateStorageStub{})
+
+	summary, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), cards.periodFrom)
+	assert.Equal(t, time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), cards.periodTo)
+	assert.Equal(t, "SUMMARY-1234567812345678-2024-10", summary.Code)
+	// Single payments 200.20 + 300.30, plus the October quotas of V-AUG (50) and V-OCT (110)
+	assert.Equal(t, models.MustParseMoney("660.50"), summary.TotalPrice)
+	assert.Len(t, summary.SinglePayments, 2)
+	assert.Len(t, summary.MonthlyPayments, 1)
+	if assert.Len(t, summary.Quotas, 2) {
+		assert.Equal(t, "V-AUG", summary.Quotas[0].PaymentVoucher)
+		assert.Equal(t, 3, summary.Quotas[0].Number)
+		assert.Equal(t, "V-OCT", summary.Quotas[1].PaymentVoucher)
+		assert.Equal(t, 1, summary.Quotas[1].Number)
+	}
+	assert.Equal(t, time.Date(2024, time.November, 15, 0, 0, 0, 0, time.UTC), summary.FirstExpiration)
+	assert.Equal(t, time.Date(2024, time.November, 25, 0, 0, 0, 0, time.UTC), summary.SecondExpiration)
+	assert.Equal(t, models.DefaultSurchargePercentage, summary.SurchargePercentage)
+	assert.Len(t, cards.summaries, 1)
+}
+
+func TestCloseCycleWithConfiguredBillingCycle(t *testing.T) {
+	ctx := context.Background()
+	banks := &bankStorageStub{cycle: &models.BillingCycle{
+		BankCuit:            "30-12345678-9",
+		ClosingDay:          25,
+		FirstDueDays:        10,
+		SecondDueDays:       7,
+		SurchargePercentage: models.MustParsePercentage("3.5"),
+	}}
+	cards := &cardStorageStub{
+		singles: []models.PurchaseSinglePayment{
+			singlePurchaseAt(models.MustParseMoney("50"), time.Date(2024, time.September, 25, 12, 0, 0, 0, time.UTC)),
+			singlePurchaseAt(models.MustParseMoney("75"), time.Date(2024, time.September, 26, 12, 0, 0, 0, time.UTC)),
+			singlePurchaseAt(models.MustParseMoney("25"), time.Date(2024, time.October, 26, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	// Installment purchases made on and after the September closing date: the first quota of the later one is
+	// billed by the October cycle, as its single payments would be
+	purchases := &cardService{repo: cards, banks: banks, promotions: NewPromotionEngine(&promotionStorageStub{}), now: time.Now}
+	vouchers := map[string]string{}
+	for name, purchaseDate := range map[string]time.Time{
+		"on closing":    time.Date(2024, time.September, 25, 18, 0, 0, 0, time.UTC),
+		"after closing": time.Date(2024, time.September, 27, 10, 0, 0, 0, time.UTC),
+	} {
+		registered, err := purchases.RegisterMonthlyPurchase(ctx, "1234567812345678", models.PurchaseMonthlyPayment{
+			Purchase:       models.Purchase{Store: "Store B", CuitStore: "20-98765432-1", Amount: models.MustParseMoney("300"), PurchaseDate: purchaseDate},
+			NumberOfQuotas: 3,
+		})
+		assert.NoError(t, err)
+		vouchers[registered.PaymentVoucher] = name
+	}
+
+	service := newBillingServiceAt(time.Date(2024, time.October, 26, 9, 0, 0, 0, time.UTC), banks, cards, &exchangeRateStorageStub{})
+
+	summary, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.September, 26, 0, 0, 0, 0, time.UTC), cards.periodFrom)
+	assert.Equal(t, time.Date(2024, time.October, 26, 0, 0, 0, 0, time.UTC), cards.periodTo)
+	// The single payment of 75, the second quota of the purchase made on the closing date and the first quota of
+	// the one made after it
+	assert.Equal(t, models.MustParseMoney("275"), summary.TotalPrice)
+	billed := map[string]int{}
+	for _, quota := range summary.Quotas {
+		billed[vouchers[quota.PaymentVoucher]] = quota.Number
+	}
+	assert.Equal(t, map[string]int{"on closing": 2, "after closing": 1}, billed)
+	assert.Equal(t, time.Date(2024, time.November, 4, 0, 0, 0, 0, time.UTC), summary.FirstExpiration)
+	assert.Equal(t, time.Date(2024, time.November, 11, 0, 0, 0, 0, time.UTC), summary.SecondExpiration)
+	assert.Equal(t, models.MustParsePercentage("3.5"), summary.SurchargePercentage)
+}
+
+func TestCloseCycleConvertsForeignCurrencies(t *testing.T) {
+	ctx := context.Background()
+	dollars := singlePurchaseAt(models.MustParseMoney("120.50"), time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC))
+	dollars.Currency = models.CurrencyUSD
+	cards := &cardStorageStub{
+		singles: []models.PurchaseSinglePayment{
+			singlePurchaseAt(models.MustParseMoney("200.20"), time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)),
+			dollars,
+		},
+		monthlys: []models.PurchaseMonthlyPayment{
+			monthlyPurchaseAt("V-OCT", models.MustParseMoney("330"), 3, time.Date(2024, time.October, 14, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	rates := &exchangeRateStorageStub{rates: []models.ExchangeRate{
+		{Currency: models.CurrencyUSD, Date: time.Date(2024, time.October, 30, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1000")},
+		{Currency: models.CurrencyUSD, Date: time.Date(2024, time.October, 31, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1072.50")},
+		{Currency: models.CurrencyUSD, Date: time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("2000")},
+	}}
+	service := newBillingServiceAt(time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), &bankStorageStub{}, cards, rates)
+
+	summary, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+
+	assert.NoError(t, err)
+	// 120.50 dollars at the rate of the closing date, 1072.50, are 129236.25 pesos
+	assert.Equal(t, []models.CurrencySubtotal{
+		{Currency: models.CurrencyARS, Subtotal: models.MustParseMoney("310.20"), ExchangeRate: models.MustParseMoney("1"), Total: models.MustParseMoney("310.20")},
+		{Currency: models.CurrencyUSD, Subtotal: models.MustParseMoney("120.50"), ExchangeRate: models.MustParseMoney("1072.50"), Total: models.MustParseMoney("129236.25")},
+	}, summary.Subtotals)
+	assert.Equal(t, models.MustParseMoney("129546.45"), summary.TotalPrice)
+}
+
+func TestCloseCycleClampsClosingDayToMonthEnd(t *testing.T) {
+	ctx := context.Background()
+	banks := &bankStorageStub{cycle: &models.BillingCycle{BankCuit: "30-12345678-9", ClosingDay: 30, FirstDueDays: 10}}
+	cards := &cardStorageStub{}
+	service := newBillingServiceAt(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), banks, cards, &exchangeRateStorageStub{})
+
+	_, err := service.CloseCycle(ctx, "1234567812345678", 3, 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), cards.periodFrom)
+	assert.Equal(t, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), cards.periodTo)
+}
+
+func TestCloseCycleErrors(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("before the closing date", func(t *testing.T) {
+		service := newBillingServiceAt(time.Date(2024, time.October, 31, 23, 59, 0, 0, time.UTC), &bankStorageStub{}, &cardStorageStub{}, &exchangeRateStorageStub{})
+		_, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+		assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		service := newBillingServiceAt(now, &bankStorageStub{}, &cardStorageStub{}, &exchangeRateStorageStub{})
+		_, err := service.CloseCycle(ctx, "1234567812345678", 13, 2024)
+		assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+	})
+
+	t.Run("no closing rate", func(t *testing.T) {
+		dollars := singlePurchaseAt(models.MustParseMoney("10"), time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC))
+		dollars.Currency = models.CurrencyUSD
+		rates := &exchangeRateStorageStub{rates: []models.ExchangeRate{
+			{Currency: models.CurrencyUSD, Date: time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1000")},
+		}}
+		cards := &cardStorageStub{singles: []models.PurchaseSinglePayment{dollars}}
+		service := newBillingServiceAt(now, &bankStorageStub{}, cards, rates)
+		_, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+		assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		assert.Empty(t, cards.summaries)
+	})
+
+	t.Run("unknown card", func(t *testing.T) {
+		service := newBillingServiceAt(now, &bankStorageStub{}, &cardStorageStub{}, &exchangeRateStorageStub{})
+		_, err := service.CloseCycle(ctx, "0000000000000000", 10, 2024)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		service := newBillingServiceAt(now, &bankStorageStub{}, &cardStorageStub{}, &exchangeRateStorageStub{})
+		_, err := service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+		assert.NoError(t, err)
+		_, err = service.CloseCycle(ctx, "1234567812345678", 10, 2024)
+		assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	})
+}
+
+func TestConfigureBillingCycle(t *testing.T) {
+	ctx := context.Background()
+	valid := models.BillingCycle{BankCuit: "30-12345678-9", ClosingDay: 20, FirstDueDays: 10, SecondDueDays: 5, SurchargePercentage: models.MustParsePercentage("4")}
+
+	banks := &bankStorageStub{}
+	service := NewBillingService(banks, &cardStorageStub{}, &exchangeRateStorageStub{})
+
+	cycle, err := service.GetBillingCycle(ctx, "30-12345678-9")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultBillingCycle("30-12345678-9"), *cycle)
+
+	_, err = service.ConfigureBillingCycle(ctx, valid)
+	assert.NoError(t, err)
+
+	cycle, err = service.GetBillingCycle(ctx, "30-12345678-9")
+	assert.NoError(t, err)
+	assert.Equal(t, valid, *cycle)
+
+	_, err = service.GetBillingCycle(ctx, "30-99999999-9")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	tests := []struct {
+		name   string
+		mutate func(c *models.BillingCycle)
+	}{
+		{"malformed bank CUIT", func(c *models.BillingCycle) { c.BankCuit = "30123456789" }},
+		{"closing day out of range", func(c *models.BillingCycle) { c.ClosingDay = 32 }},
+		{"no days until the first expiration", func(c *models.BillingCycle) { c.FirstDueDays = 0 }},
+		{"negative days until the second expiration", func(c *models.BillingCycle) { c.SecondDueDays = -1 }},
+		{"surcharge out of range", func(c *models.BillingCycle) { c.SurchargePercentage = models.MustParsePercentage("150") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := valid
+			tt.mutate(&cycle)
+
+			_, err := service.ConfigureBillingCycle(ctx, cycle)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+}
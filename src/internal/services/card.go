@@ -1,10 +1,28 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 )
 
+// cardNumberPattern matches a card number of 16 digits.
+var cardNumberPattern = regexp.MustCompile(`^\d{16}$`)
+
+// ccvPattern matches a card verification code of 3 digits.
+var ccvPattern = regexp.MustCompile(`^\d{3}$`)
+
+// maxVoucherAttempts is the number of payment vouchers generated for a purchase before giving up, when the
+// card already has a purchase with each of them.
+const maxVoucherAttempts = 5
+
 // CardService defines the interface for card-related operations.
 // This service abstracts business logic and data layer interactions,
 // providing a clear contract for managing card operations like payment summaries, purchases, and expiring cards.
@@ -17,7 +35,7 @@ type CardService interface {
 	// Returns:
 	// - *models.PaymentSummary: A PaymentSummary object containing the payment details for the specified card.
 	// - error: An error if the operation fails, otherwise nil.
-	GetPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error)
+	GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error)
 
 	// GetCardsExpiringInNext30Days retrieves the cards that will expire in the next 30 days.
 	// Parameters:
@@ -27,7 +45,7 @@ type CardService interface {
 	// Returns:
 	// - *[]models.Card: A slice of Card objects representing the cards expiring in the next 30 days.
 	// - error: An error if the operation fails, otherwise nil.
-	GetCardsExpiringInNext30Days(day int, month int, year int) (*[]models.Card, error)
+	GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error)
 
 	// GetPurchaseMonthly retrieves the monthly purchase details for a card.
 	// Parameters:
@@ -37,7 +55,7 @@ type CardService interface {
 	// Returns:
 	// - *models.PurchaseMonthlyPayment: A PurchaseMonthlyPayment object containing the purchase details for the card.
 	// - error: An error if the operation fails, otherwise nil.
-	GetPurchaseMonthly(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseMonthlyPayment, error)
+	GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error)
 
 	// GetPurchaseSingle retrieves the single purchase details for a card.
 	// Parameters:
@@ -47,53 +65,367 @@ type CardService interface {
 	// Returns:
 	// - *models.PurchaseSinglePayment: A PurchaseSinglePayment object containing the purchase details for the card.
 	// - error: An error if the operation fails, otherwise nil.
-	GetPurchaseSingle(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseSinglePayment, error)
+	GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error)
 
 	// GetTop10CardsByPurchases retrieves the top 10 cards by the number of purchases.
 	// Returns:
 	// - *[]models.Card: A slice of Card objects representing the top 10 cards by purchases.
 	// - error: An error if the operation fails, otherwise nil.
-	GetTop10CardsByPurchases() (*[]models.Card, error)
+	GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error)
+
+	// RegisterSinglePurchase validates and registers a single-payment purchase on a card,
+	// applying the best discount the card's bank offers at the store on the purchase date.
+	// Parameters:
+	// - cardNumber: The number of the card used for the purchase.
+	// - purchase: The purchase details. The payment voucher and the final amount are computed by the service.
+	// Returns:
+	// - *models.PurchaseSinglePayment: The registered purchase, including its payment voucher, unique among the card's purchases.
+	// - error: An error wrapping ErrValidation if the purchase is invalid, or any storage error.
+	RegisterSinglePurchase(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error)
+
+	// RegisterMonthlyPurchase validates and registers an installment purchase on a card,
+	// applying the best promotion the card's bank offers at the store on the purchase date and
+	// generating its quota schedule from the number of quotas, the interest and the interest model. The first quota
+	// is due in the month whose billing cycle covers the purchase date.
+	// Parameters:
+	// - cardNumber: The number of the card used for the purchase.
+	// - purchase: The purchase details. The payment voucher and the final amount are computed by the service.
+	// Returns:
+	// - *models.PurchaseMonthlyPayment: The registered purchase, including its payment voucher, unique among the card's purchases.
+	// - error: An error wrapping ErrValidation if the purchase is invalid, or any storage error.
+	RegisterMonthlyPurchase(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error)
+
+	// SimulatePurchase quotes a prospective purchase on a card under every promotion its bank offers at the store
+	// on the purchase date, paid at once or in quotas, without registering anything.
+	// Parameters:
+	// - request: The card, the store, the amount in pesos and the optional installments without a financing promotion.
+	// - purchaseDate: The date of the purchase; the zero time defaults to now.
+	// Returns:
+	// - []models.PurchaseQuote: The quotes, the ones a registered purchase would get marked as applied.
+	// - error: An error wrapping ErrValidation if the request is invalid or the card cannot be used on the date,
+	//   storage.ErrNotFound if the card does not exist, otherwise nil.
+	SimulatePurchase(ctx context.Context, request models.PromotionSimulationRequest, purchaseDate time.Time) ([]models.PurchaseQuote, error)
+
+	// IssueCard validates and issues a new active card to a customer at a bank.
+	// Parameters:
+	// - card: The card details, including the bank CUIT and the customer CUIT. The issuance date defaults to now.
+	// Returns:
+	// - *models.Card: The issued card.
+	// - error: A validation error if the card details are invalid, storage.ErrNotFound if the bank or customer
+	//   do not exist, storage.ErrAlreadyExists if the card number is taken, otherwise nil.
+	IssueCard(ctx context.Context, card models.Card) (*models.Card, error)
+
+	// RenewCard extends the expiration date of a card that has not been cancelled.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// - expirationDate: The new expiration date, which must be after the current one and in the future.
+	// Returns:
+	// - *models.Card: The renewed card.
+	// - error: A validation error if the card is cancelled or the date is invalid, storage.ErrNotFound if the card does not exist.
+	RenewCard(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error)
+
+	// BlockCard temporarily disables an active card.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// Returns:
+	// - *models.Card: The blocked card.
+	// - error: A validation error if the card is not active, storage.ErrNotFound if the card does not exist.
+	BlockCard(ctx context.Context, cardNumber string) (*models.Card, error)
+
+	// UnblockCard enables a blocked card again.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// Returns:
+	// - *models.Card: The unblocked card.
+	// - error: A validation error if the card is not blocked, storage.ErrNotFound if the card does not exist.
+	UnblockCard(ctx context.Context, cardNumber string) (*models.Card, error)
+
+	// CancelCard permanently disables a card.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// Returns:
+	// - *models.Card: The cancelled card.
+	// - error: A validation error if the card is already cancelled, storage.ErrNotFound if the card does not exist.
+	CancelCard(ctx context.Context, cardNumber string) (*models.Card, error)
 }
 
 // service is a concrete implementation of the CardService interface.
-// It uses a repository (ICardStorage) to perform data operations.
+// It uses a repository (ICardStorage) to perform data operations, and the bank repository for the billing cycles
+// that set when the quotas of installment purchases are due.
 type cardService struct {
-	repo storage.ICardStorage
+	repo       storage.ICardStorage
+	banks      storage.IBankStorage
+	promotions PromotionEngine
+	now        func() time.Time
 }
 
 // NewCardService creates and initializes a new CardService instance.
 // Parameters:
 // - repo: An ICardStorage repository interface for interacting with the data layer.
+// - banks: An IBankStorage repository interface holding the billing cycle configuration of the banks.
+// - promotions: The PromotionEngine used to compute the final amount of new purchases.
 // Returns:
 // - CardService: A new instance of the service struct implementing the CardService interface.
-func NewCardService(repo storage.ICardStorage) CardService {
+func NewCardService(repo storage.ICardStorage, banks storage.IBankStorage, promotions PromotionEngine) CardService {
 	return &cardService{
-		repo: repo,
+		repo:       repo,
+		banks:      banks,
+		promotions: promotions,
+		now:        time.Now,
 	}
 }
 
 // GetPaymentSummary retrieves the payment summary for a card.
-func (s *cardService) GetPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error) {
-	return s.repo.GetPaymentSummary(cardNumber, month, year)
+func (s *cardService) GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	return s.repo.GetPaymentSummary(ctx, cardNumber, month, year)
 }
 
 // GetCardsExpiringInNext30Days retrieves the cards that will expire in the next 30 days.
-func (s *cardService) GetCardsExpiringInNext30Days(day int, month int, year int) (*[]models.Card, error) {
-	return s.repo.GetCardsExpiringInNext30Days(day, month, year)
+func (s *cardService) GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error) {
+	return s.repo.GetCardsExpiringInNext30Days(ctx, day, month, year)
 }
 
 // GetPurchaseMonthly retrieves the monthly purchase details for a card.
-func (s *cardService) GetPurchaseMonthly(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
-	return s.repo.GetPurchaseMonthly(cuit, finalAmount, paymentVoucher)
+func (s *cardService) GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+	return s.repo.GetPurchaseMonthly(ctx, cuit, finalAmount, paymentVoucher)
 }
 
 // GetPurchaseSingle retrieves the single purchase details for a card.
-func (s *cardService) GetPurchaseSingle(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
-	return s.repo.GetPurchaseSingle(cuit, finalAmount, paymentVoucher)
+func (s *cardService) GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+	return s.repo.GetPurchaseSingle(ctx, cuit, finalAmount, paymentVoucher)
 }
 
 // GetTop10CardsByPurchases retrieves the top 10 cards by purchases.
-func (s *cardService) GetTop10CardsByPurchases() (*[]models.Card, error) {
-	return s.repo.GetTop10CardsByPurchases()
+func (s *cardService) GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error) {
+	return s.repo.GetTop10CardsByPurchases(ctx)
+}
+
+// RegisterSinglePurchase validates and registers a single-payment purchase on a card.
+func (s *cardService) RegisterSinglePurchase(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	purchase.PurchaseType = models.SinglePayment
+	if err := validatePurchase(cardNumber, &purchase.Purchase, s.now()); err != nil {
+		return nil, err
+	}
+	if purchase.StoreDiscount < 0 || purchase.StoreDiscount > models.HundredPercent {
+		return nil, validationError("store discount must be between 0 and 100, got %s", purchase.StoreDiscount)
+	}
+
+	card, err := s.repo.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCardUsable(card, purchase.PurchaseDate); err != nil {
+		return nil, err
+	}
+	if err := s.promotions.ApplyToSinglePurchase(ctx, card.Bank.Cuit, &purchase); err != nil {
+		return nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		purchase.PaymentVoucher = newPaymentVoucher(purchase.PurchaseDate)
+		registered, err := s.repo.AddPurchaseSinglePayment(ctx, cardNumber, purchase)
+		if !errors.Is(err, storage.ErrAlreadyExists) || attempt == maxVoucherAttempts {
+			return registered, err
+		}
+	}
+}
+
+// RegisterMonthlyPurchase validates and registers an installment purchase on a card.
+func (s *cardService) RegisterMonthlyPurchase(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	purchase.PurchaseType = models.MonthlyPayments
+	if err := validatePurchase(cardNumber, &purchase.Purchase, s.now()); err != nil {
+		return nil, err
+	}
+	if err := validateNumberOfQuotas(purchase.NumberOfQuotas); err != nil {
+		return nil, err
+	}
+	if purchase.Interest < 0 {
+		return nil, validationError("interest cannot be negative, got %s", purchase.Interest)
+	}
+	if purchase.Currency != models.BaseCurrency {
+		return nil, validationError("installment purchases must be in %s, got %s", models.BaseCurrency, purchase.Currency)
+	}
+	if _, err := InstallmentCalculatorFor(purchase.InterestModel); err != nil {
+		return nil, err
+	}
+
+	card, err := s.repo.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCardUsable(card, purchase.PurchaseDate); err != nil {
+		return nil, err
+	}
+	if err := s.promotions.ApplyToMonthlyPurchase(ctx, card.Bank.Cuit, &purchase); err != nil {
+		return nil, err
+	}
+
+	// The first quota is billed by the cycle covering the purchase date, the next month's when it is made after the closing date
+	cycle, err := billingCycleOf(ctx, s.banks, card.Bank.Cuit)
+	if err != nil {
+		return nil, err
+	}
+	for i, quota := range quotaMonths(len(purchase.Quota), cycleMonth(*cycle, purchase.PurchaseDate)) {
+		purchase.Quota[i].Month, purchase.Quota[i].Year = quota.Month, quota.Year
+	}
+	for attempt := 1; ; attempt++ {
+		purchase.PaymentVoucher = newPaymentVoucher(purchase.PurchaseDate)
+		registered, err := s.repo.AddPurchaseMonthlyPayment(ctx, cardNumber, purchase)
+		if !errors.Is(err, storage.ErrAlreadyExists) || attempt == maxVoucherAttempts {
+			return registered, err
+		}
+	}
+}
+
+// SimulatePurchase quotes a prospective purchase on a card under every applicable promotion.
+func (s *cardService) SimulatePurchase(ctx context.Context, request models.PromotionSimulationRequest, purchaseDate time.Time) ([]models.PurchaseQuote, error) {
+	if strings.TrimSpace(request.CardNumber) == "" {
+		return nil, validationError("card number is required")
+	}
+	if err := validateCuit("store CUIT", request.CuitStore); err != nil {
+		return nil, err
+	}
+	if request.Amount <= 0 {
+		return nil, validationError("amount must be greater than zero, got %s", request.Amount)
+	}
+	if request.NumberOfQuotas != 0 {
+		if err := validateNumberOfQuotas(request.NumberOfQuotas); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := InstallmentCalculatorFor(request.InterestModel); err != nil {
+		return nil, err
+	}
+	if purchaseDate.IsZero() {
+		purchaseDate = s.now()
+	}
+
+	card, err := s.repo.GetCardByNumber(ctx, request.CardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCardUsable(card, purchaseDate); err != nil {
+		return nil, err
+	}
+	return s.promotions.QuotePurchase(ctx, card.Bank.Cuit, request, purchaseDate)
+}
+
+// IssueCard validates and issues a new active card to a customer at a bank.
+func (s *cardService) IssueCard(ctx context.Context, card models.Card) (*models.Card, error) {
+	card.Number = strings.TrimSpace(card.Number)
+	card.CardholderNameInCard = strings.TrimSpace(card.CardholderNameInCard)
+	card.Bank.Cuit = strings.TrimSpace(card.Bank.Cuit)
+	card.CustomerCuit = strings.TrimSpace(card.CustomerCuit)
+
+	if !cardNumberPattern.MatchString(card.Number) {
+		return nil, validationError("card number '%s' must have 16 digits", card.Number)
+	}
+	if !ccvPattern.MatchString(card.Ccv) {
+		return nil, validationError("card verification code must have 3 digits")
+	}
+	if card.CardholderNameInCard == "" {
+		return nil, validationError("cardholder name is required")
+	}
+	if err := validateCuit("bank CUIT", card.Bank.Cuit); err != nil {
+		return nil, err
+	}
+	if err := validateCuit("customer CUIT", card.CustomerCuit); err != nil {
+		return nil, err
+	}
+	if card.Since.IsZero() {
+		card.Since = s.now()
+	}
+	if !card.ExpirationDate.After(card.Since) {
+		return nil, validationError("expiration date %s must be after the issuance date %s",
+			card.ExpirationDate.Format(time.RFC3339), card.Since.Format(time.RFC3339))
+	}
+	card.Status = models.CardStatusActive
+
+	return s.repo.IssueCard(ctx, card)
+}
+
+// RenewCard extends the expiration date of a card that has not been cancelled.
+func (s *cardService) RenewCard(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	card, err := s.repo.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == models.CardStatusCancelled {
+		return nil, validationError("card %s is cancelled and cannot be renewed", cardNumber)
+	}
+	if !expirationDate.After(card.ExpirationDate) || !expirationDate.After(s.now()) {
+		return nil, validationError("new expiration date %s must be in the future and after the current one %s",
+			expirationDate.Format(time.RFC3339), card.ExpirationDate.Format(time.RFC3339))
+	}
+	return s.repo.UpdateCardExpiration(ctx, cardNumber, expirationDate)
+}
+
+// BlockCard temporarily disables an active card.
+func (s *cardService) BlockCard(ctx context.Context, cardNumber string) (*models.Card, error) {
+	return s.changeStatus(ctx, cardNumber, models.CardStatusBlocked, models.CardStatusActive)
+}
+
+// UnblockCard enables a blocked card again.
+func (s *cardService) UnblockCard(ctx context.Context, cardNumber string) (*models.Card, error) {
+	return s.changeStatus(ctx, cardNumber, models.CardStatusActive, models.CardStatusBlocked)
+}
+
+// CancelCard permanently disables a card.
+func (s *cardService) CancelCard(ctx context.Context, cardNumber string) (*models.Card, error) {
+	return s.changeStatus(ctx, cardNumber, models.CardStatusCancelled, models.CardStatusActive, models.CardStatusBlocked)
+}
+
+// changeStatus moves a card to the given status when its current status is one of the allowed ones.
+func (s *cardService) changeStatus(ctx context.Context, cardNumber string, status models.CardStatus, allowedFrom ...models.CardStatus) (*models.Card, error) {
+	card, err := s.repo.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, from := range allowedFrom {
+		if card.Status == from {
+			return s.repo.UpdateCardStatus(ctx, cardNumber, status)
+		}
+	}
+	return nil, validationError("card %s is %s and cannot be changed to %s", cardNumber, card.Status, status)
+}
+
+// validateCardUsable rejects purchases on cards that are not active or that are expired on the purchase date.
+func validateCardUsable(card *models.Card, purchaseDate time.Time) error {
+	if card.Status != models.CardStatusActive {
+		return validationError("card %s is %s", card.Number, card.Status)
+	}
+	if purchaseDate.After(card.ExpirationDate) {
+		return validationError("card %s expired on %s", card.Number, card.ExpirationDate.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// validatePurchase checks the fields shared by every purchase type, defaults the currency to pesos and the purchase date to now.
+func validatePurchase(cardNumber string, purchase *models.Purchase, now time.Time) error {
+	if strings.TrimSpace(cardNumber) == "" {
+		return validationError("card number is required")
+	}
+	if strings.TrimSpace(purchase.Store) == "" {
+		return validationError("store name is required")
+	}
+	if err := validateCuit("store CUIT", purchase.CuitStore); err != nil {
+		return err
+	}
+	if purchase.Amount <= 0 {
+		return validationError("amount must be greater than zero, got %s", purchase.Amount)
+	}
+	currency, err := models.ParseCurrency(string(purchase.Currency))
+	if err != nil {
+		return validationError("%v", err)
+	}
+	purchase.Currency = currency
+	if purchase.PurchaseDate.IsZero() {
+		purchase.PurchaseDate = now
+	}
+	return nil
+}
+
+// newPaymentVoucher generates a payment voucher for a purchase made at the given date. Two purchases made in the same
+// second may get the same one, the storages reject the second and the purchase is registered again with another voucher.
+func newPaymentVoucher(date time.Time) string {
+	return fmt.Sprintf("PV%s%04d", date.Format("20060102150405"), rand.Intn(10000))
 }
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// cardStorageStub is an in-test ICardStorage that records the purchases and payment summaries it receives.
+// It knows a single card, issued by the bank with CUIT 30-12345678-9.
+type cardStorageStub struct {
+	storage.ICardStorage
+	singles   []models.PurchaseSinglePayment
+	monthlys  []models.PurchaseMonthlyPayment
+	summaries []models.PaymentSummary
+
+	periodFrom time.Time
+	periodTo   time.Time
+
+	// status and expiration of the known card, active until the end of 2030 when unset
+	status     models.CardStatus
+	expiration time.Time
+
+	// number of purchases rejected as having a voucher already used on the card before one is accepted
+	repeatedVouchers int
+	vouchers         []string
+}
+
+func (s *cardStorageStub) GetCardByNumber(_ context.Context, cardNumber string) (*models.Card, error) {
+	if cardNumber != "1234567812345678" {
+		return nil, storage.ErrNotFound
+	}
+	card := &models.Card{
+		Number:         cardNumber,
+		Status:         s.status,
+		ExpirationDate: s.expiration,
+		Bank:           models.Bank{Cuit: "30-12345678-9"},
+	}
+	if card.Status == "" {
+		card.Status = models.CardStatusActive
+	}
+	if card.ExpirationDate.IsZero() {
+		card.ExpirationDate = time.Date(2030, time.December, 31, 23, 59, 59, 0, time.UTC)
+	}
+	return card, nil
+}
+
+func (s *cardStorageStub) IssueCard(_ context.Context, card models.Card) (*models.Card, error) {
+	if card.Number == "1234567812345678" {
+		return nil, storage.ErrAlreadyExists
+	}
+	return &card, nil
+}
+
+func (s *cardStorageStub) UpdateCardExpiration(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	s.expiration = expirationDate
+	return s.GetCardByNumber(ctx, cardNumber)
+}
+
+func (s *cardStorageStub) UpdateCardStatus(ctx context.Context, cardNumber string, status models.CardStatus) (*models.Card, error) {
+	s.status = status
+	return s.GetCardByNumber(ctx, cardNumber)
+}
+
+func (s *cardStorageStub) AddPurchaseSinglePayment(_ context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	s.vouchers = append(s.vouchers, purchase.PaymentVoucher)
+	if s.repeatedVouchers > 0 {
+		s.repeatedVouchers--
+		return nil, storage.ErrAlreadyExists
+	}
+	s.singles = append(s.singles, purchase)
+	return &purchase, nil
+}
+
+func (s *cardStorageStub) AddPurchaseMonthlyPayment(_ context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	s.vouchers = append(s.vouchers, purchase.PaymentVoucher)
+	if s.repeatedVouchers > 0 {
+		s.repeatedVouchers--
+		return nil, storage.ErrAlreadyExists
+	}
+	s.monthlys = append(s.monthlys, purchase)
+	return &purchase, nil
+}
+
+func TestRegisterSinglePurchase(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+
+	purchaseDate := time.Date(2025, time.March, 2, 10, 30, 0, 0, time.UTC)
+	purchase, err := service.RegisterSinglePurchase(ctx, "1234567812345678", models.PurchaseSinglePayment{
+		Purchase: models.Purchase{
+			Store:        "Store A",
+			CuitStore:    "30-12345678-9",
+			Amount:       models.MustParseMoney("150.50"),
+			PurchaseDate: purchaseDate,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.singles, 1)
+	assert.Equal(t, models.MustParseMoney("150.50"), purchase.FinalAmount)
+	assert.Equal(t, models.CurrencyARS, purchase.Currency)
+	assert.Equal(t, models.SinglePayment, purchase.PurchaseType)
+	assert.Regexp(t, `^PV20250302103000\d{4}$`, purchase.PaymentVoucher)
+}
+
+func TestRegisterPurchaseRetriesRepeatedVouchers(t *testing.T) {
+	ctx := context.Background()
+	purchase := models.Purchase{Store: "Store A", CuitStore: "30-12345678-9", Amount: models.MustParseMoney("100")}
+
+	repo := &cardStorageStub{repeatedVouchers: maxVoucherAttempts - 1}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+	registered, err := service.RegisterSinglePurchase(ctx, "1234567812345678", models.PurchaseSinglePayment{Purchase: purchase})
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.vouchers, maxVoucherAttempts)
+	assert.Equal(t, repo.vouchers[maxVoucherAttempts-1], registered.PaymentVoucher)
+	assert.Len(t, repo.singles, 1)
+
+	// The purchase is not registered when every generated voucher is taken
+	repo = &cardStorageStub{repeatedVouchers: maxVoucherAttempts}
+	service = NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+	_, err = service.RegisterMonthlyPurchase(ctx, "1234567812345678", models.PurchaseMonthlyPayment{Purchase: purchase, NumberOfQuotas: 3})
+
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	assert.Len(t, repo.vouchers, maxVoucherAttempts)
+	assert.Empty(t, repo.monthlys)
+}
+
+func TestRegisterMonthlyPurchase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 9, 10, 0, 0, 0, time.UTC)
+	repo := &cardStorageStub{}
+	service := &cardService{repo: repo, banks: &bankStorageStub{}, promotions: NewPromotionEngine(&promotionStorageStub{}), now: func() time.Time { return now }}
+
+	purchase, err := service.RegisterMonthlyPurchase(ctx, "1234567812345678", models.PurchaseMonthlyPayment{
+		Purchase: models.Purchase{
+			Store:     "Store B",
+			CuitStore: "20-98765432-1",
+			Amount:    models.MustParseMoney("300"),
+		},
+		Interest:       models.MustParsePercentage("10"),
+		NumberOfQuotas: 3,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.monthlys, 1)
+	assert.Equal(t, models.MustParseMoney("330"), purchase.FinalAmount)
+	assert.Equal(t, now, purchase.PurchaseDate, "the purchase date defaults to the service clock")
+	assert.Len(t, purchase.Quota, 3)
+	assert.Equal(t, models.MustParseMoney("110"), purchase.Quota[0].Price)
+	assert.Equal(t, models.InterestModelFlat, purchase.InterestModel)
+}
+
+func TestRegisterMonthlyPurchaseWithFrenchInterest(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+
+	purchase, err := service.RegisterMonthlyPurchase(ctx, "1234567812345678", models.PurchaseMonthlyPayment{
+		Purchase:       models.Purchase{Store: "Store B", CuitStore: "20-98765432-1", Amount: models.MustParseMoney("1000")},
+		Interest:       models.MustParsePercentage("12"),
+		InterestModel:  models.InterestModelFrench,
+		NumberOfQuotas: 3,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.InterestModelFrench, purchase.InterestModel)
+	assert.Equal(t, models.MustParseMoney("1020.07"), purchase.FinalAmount)
+	assert.Equal(t, models.MustParseMoney("340.02"), purchase.Quota[0].Price)
+	assert.Equal(t, models.MustParseMoney("340.03"), purchase.Quota[2].Price)
+}
+
+func TestRegisterPurchaseValidation(t *testing.T) {
+	ctx := context.Background()
+	valid := models.Purchase{Store: "Store A", CuitStore: "30-12345678-9", Amount: models.MustParseMoney("100")}
+
+	tests := []struct {
+		name       string
+		cardNumber string
+		mutate     func(p *models.PurchaseMonthlyPayment)
+	}{
+		{"missing card number", "", func(p *models.PurchaseMonthlyPayment) {}},
+		{"missing store", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.Store = "" }},
+		{"malformed store CUIT", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.CuitStore = "30123456789" }},
+		{"non-positive amount", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.Amount = 0 }},
+		{"no quotas", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.NumberOfQuotas = 0 }},
+		{"too many quotas", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.NumberOfQuotas = maxQuotas + 1 }},
+		{"negative interest", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.Interest = models.MustParsePercentage("-5") }},
+		{"unsupported currency", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.Currency = "EUR" }},
+		{"installments in dollars", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.Currency = models.CurrencyUSD }},
+		{"unknown interest model", "1234567812345678", func(p *models.PurchaseMonthlyPayment) { p.InterestModel = "german" }},
+		{"interest on a zero-interest purchase", "1234567812345678", func(p *models.PurchaseMonthlyPayment) {
+			p.InterestModel, p.Interest = models.InterestModelZero, models.MustParsePercentage("5")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &cardStorageStub{}
+			purchase := models.PurchaseMonthlyPayment{Purchase: valid, NumberOfQuotas: 3}
+			tt.mutate(&purchase)
+
+			_, err := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{})).RegisterMonthlyPurchase(ctx, tt.cardNumber, purchase)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+			assert.Empty(t, repo.monthlys)
+		})
+	}
+}
+
+func TestRegisterSinglePurchaseInDollars(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+
+	purchase, err := service.RegisterSinglePurchase(ctx, "1234567812345678", models.PurchaseSinglePayment{
+		Purchase: models.Purchase{Store: "Store A", CuitStore: "30-12345678-9", Amount: models.MustParseMoney("25"), Currency: "usd"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.CurrencyUSD, purchase.Currency)
+	assert.Equal(t, models.MustParseMoney("25"), purchase.FinalAmount)
+}
+
+func TestRegisterPurchaseUnknownCard(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{}))
+
+	_, err := service.RegisterSinglePurchase(ctx, "0000000000000000", models.PurchaseSinglePayment{
+		Purchase: models.Purchase{Store: "Store A", CuitStore: "30-12345678-9", Amount: models.MustParseMoney("100")},
+	})
+
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Empty(t, repo.singles)
+}
+
+func TestRegisterPurchaseAppliesPromotion(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	promotions := &promotionStorageStub{
+		discounts: []models.Discount{
+			{Promotion: models.Promotion{Code: "SALE20"}, DiscountPercentage: models.MustParsePercentage("20")},
+		},
+	}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(promotions))
+
+	purchase, err := service.RegisterSinglePurchase(ctx, "1234567812345678", models.PurchaseSinglePayment{
+		Purchase: models.Purchase{Store: "Store A", CuitStore: "30-99999999-9", Amount: models.MustParseMoney("200")},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "30-12345678-9", promotions.bankCuit)
+	assert.Equal(t, "30-99999999-9", promotions.storeCuit)
+	assert.Equal(t, models.MustParseMoney("160"), purchase.FinalAmount)
+	assert.Equal(t, "SALE20", purchase.PromotionCode)
+	assert.Equal(t, "SALE20", repo.singles[0].PromotionCode)
+}
+
+func TestRegisterPurchaseRejectsUnusableCards(t *testing.T) {
+	ctx := context.Background()
+	purchase := models.PurchaseSinglePayment{
+		Purchase: models.Purchase{
+			Store:        "Store A",
+			CuitStore:    "30-12345678-9",
+			Amount:       models.MustParseMoney("100"),
+			PurchaseDate: time.Date(2025, time.March, 2, 10, 30, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name string
+		repo *cardStorageStub
+	}{
+		{"blocked card", &cardStorageStub{status: models.CardStatusBlocked}},
+		{"cancelled card", &cardStorageStub{status: models.CardStatusCancelled}},
+		{"expired card", &cardStorageStub{expiration: time.Date(2025, time.February, 28, 23, 59, 59, 0, time.UTC)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCardService(tt.repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{})).RegisterSinglePurchase(ctx, "1234567812345678", purchase)
+
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Empty(t, tt.repo.singles)
+		})
+	}
+}
+
+func TestIssueCard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 9, 10, 0, 0, 0, time.UTC)
+	service := &cardService{repo: &cardStorageStub{}, now: func() time.Time { return now }}
+
+	valid := models.Card{
+		Number:               "4509123412341234",
+		Ccv:                  "321",
+		CardholderNameInCard: "JANE DOE",
+		ExpirationDate:       time.Date(2030, time.March, 31, 23, 59, 59, 0, time.UTC),
+		Bank:                 models.Bank{Cuit: "30-12345678-9"},
+		CustomerCuit:         "27-12345678-4",
+	}
+
+	card, err := service.IssueCard(ctx, valid)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusActive, card.Status)
+	assert.Equal(t, now, card.Since)
+
+	taken := valid
+	taken.Number = "1234567812345678"
+	_, err = service.IssueCard(ctx, taken)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	tests := []struct {
+		name   string
+		mutate func(c *models.Card)
+	}{
+		{"short number", func(c *models.Card) { c.Number = "4509" }},
+		{"malformed ccv", func(c *models.Card) { c.Ccv = "12a" }},
+		{"missing cardholder name", func(c *models.Card) { c.CardholderNameInCard = "" }},
+		{"malformed bank CUIT", func(c *models.Card) { c.Bank.Cuit = "30123456789" }},
+		{"malformed customer CUIT", func(c *models.Card) { c.CustomerCuit = "" }},
+		{"expires before issuance", func(c *models.Card) { c.ExpirationDate = now.AddDate(0, 0, -1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := valid
+			tt.mutate(&card)
+
+			_, err := service.IssueCard(ctx, card)
+
+			assert.ErrorIs(t, err, ErrValidation)
+		})
+	}
+}
+
+func TestCardLifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 9, 10, 0, 0, 0, time.UTC)
+	repo := &cardStorageStub{}
+	service := &cardService{repo: repo, now: func() time.Time { return now }}
+
+	// Renewal must move the expiration date forward
+	_, err := service.RenewCard(ctx, "1234567812345678", time.Date(2029, time.December, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrValidation)
+	card, err := service.RenewCard(ctx, "1234567812345678", time.Date(2034, time.December, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 2034, card.ExpirationDate.Year())
+
+	// Block and unblock
+	_, err = service.UnblockCard(ctx, "1234567812345678")
+	assert.ErrorIs(t, err, ErrValidation)
+	card, err = service.BlockCard(ctx, "1234567812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusBlocked, card.Status)
+	_, err = service.BlockCard(ctx, "1234567812345678")
+	assert.ErrorIs(t, err, ErrValidation)
+	card, err = service.UnblockCard(ctx, "1234567812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusActive, card.Status)
+
+	// Cancellation is final
+	card, err = service.CancelCard(ctx, "1234567812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusCancelled, card.Status)
+	_, err = service.CancelCard(ctx, "1234567812345678")
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = service.UnblockCard(ctx, "1234567812345678")
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = service.RenewCard(ctx, "1234567812345678", time.Date(2040, time.December, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.BlockCard(ctx, "0000000000000000")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestSimulatePurchase(t *testing.T) {
+	ctx := context.Background()
+	repo := &cardStorageStub{}
+	promotions := &promotionStorageStub{discounts: []models.Discount{discount("SALE20", "20", "0", false)}}
+	service := NewCardService(repo, &bankStorageStub{}, NewPromotionEngine(promotions))
+
+	quotes, err := service.SimulatePurchase(ctx, models.PromotionSimulationRequest{
+		CardNumber: "1234567812345678",
+		CuitStore:  "30-99999999-9",
+		Amount:     models.MustParseMoney("200"),
+	}, time.Time{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "30-12345678-9", promotions.bankCuit)
+	assert.Len(t, quotes, 2)
+	assert.Equal(t, "SALE20", quotes[0].PromotionCode)
+	assert.Equal(t, models.MustParseMoney("160"), quotes[0].FinalAmount)
+	assert.True(t, quotes[0].Applied)
+	assert.Empty(t, repo.singles, "simulations are not registered")
+	assert.Empty(t, repo.monthlys)
+}
+
+func TestSimulatePurchaseValidation(t *testing.T) {
+	ctx := context.Background()
+	valid := models.PromotionSimulationRequest{CardNumber: "1234567812345678", CuitStore: "30-99999999-9", Amount: models.MustParseMoney("100")}
+	tests := []struct {
+		name   string
+		repo   *cardStorageStub
+		modify func(*models.PromotionSimulationRequest)
+		want   error
+	}{
+		{"missing card number", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.CardNumber = "" }, ErrValidation},
+		{"invalid store CUIT", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.CuitStore = "123" }, ErrValidation},
+		{"no amount", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.Amount = 0 }, ErrValidation},
+		{"negative quotas", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.NumberOfQuotas = -1 }, ErrValidation},
+		{"too many quotas", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.NumberOfQuotas = maxQuotas + 1 }, ErrValidation},
+		{"unknown interest model", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.InterestModel = "german" }, ErrValidation},
+		{"blocked card", &cardStorageStub{status: models.CardStatusBlocked}, func(*models.PromotionSimulationRequest) {}, ErrValidation},
+		{"unknown card", &cardStorageStub{}, func(r *models.PromotionSimulationRequest) { r.CardNumber = "0000000000000000" }, storage.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid
+			tt.modify(&request)
+
+			_, err := NewCardService(tt.repo, &bankStorageStub{}, NewPromotionEngine(&promotionStorageStub{})).SimulatePurchase(ctx, request, time.Time{})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
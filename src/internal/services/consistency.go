@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/consistency"
+)
+
+// ConsistencyService defines the interface for comparing the SQL and NoSQL stores.
+type ConsistencyService interface {
+	// CheckConsistency compares the banks, cards, purchases and promotions of both stores by natural key.
+	// Returns:
+	// - *models.ConsistencyReport: The missing, extra and mismatched records.
+	// - error: An error if a store cannot be read, otherwise nil.
+	CheckConsistency(ctx context.Context) (*models.ConsistencyReport, error)
+}
+
+// consistencyService is a concrete implementation of the ConsistencyService interface.
+type consistencyService struct {
+	sql   consistency.Source
+	noSQL consistency.Source
+}
+
+// NewConsistencyService creates and initializes a new ConsistencyService instance.
+// Parameters:
+// - sql: The SQL store, the reference of the comparison.
+// - noSQL: The NoSQL store.
+// Returns:
+// - ConsistencyService: A new instance of the service struct implementing the ConsistencyService interface.
+func NewConsistencyService(sql consistency.Source, noSQL consistency.Source) ConsistencyService {
+	return &consistencyService{
+		sql:   sql,
+		noSQL: noSQL,
+	}
+}
+
+// CheckConsistency compares the banks, cards, purchases and promotions of both stores by natural key.
+func (s *consistencyService) CheckConsistency(ctx context.Context) (*models.ConsistencyReport, error) {
+	return consistency.Check(ctx, s.sql, s.noSQL)
+}
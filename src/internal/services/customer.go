@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// dniPattern matches a national identification number of 7 or 8 digits.
+var dniPattern = regexp.MustCompile(`^\d{7,8}$`)
+
+// CustomerService defines the interface for customer-related operations.
+// This service abstracts business logic and data layer interactions,
+// providing a clear contract for registering customers and managing their bank memberships.
+type CustomerService interface {
+	// CreateCustomer validates and registers a new customer.
+	// Parameters:
+	// - customer: A Customer object containing the customer details. The entry date defaults to now.
+	// Returns:
+	// - *models.Customer: The registered customer.
+	// - error: A validation error if the name, DNI or CUIT are invalid,
+	//   storage.ErrAlreadyExists if the CUIT or DNI are taken, otherwise nil.
+	CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error)
+
+	// GetCustomerByCuit retrieves a customer by its CUIT.
+	// Parameters:
+	// - cuit: The CUIT of the customer.
+	// Returns:
+	// - *models.Customer: The customer, including the CUITs of its banks.
+	// - error: storage.ErrNotFound if the customer does not exist, otherwise nil.
+	GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error)
+
+	// UpdateCustomer validates and replaces the details of a customer. The CUIT cannot be changed.
+	// Parameters:
+	// - cuit: The CUIT of the customer.
+	// - customer: A Customer object containing the new details. A zero entry date keeps the current one.
+	// Returns:
+	// - *models.Customer: The updated customer.
+	// - error: A validation error if the name or DNI are invalid,
+	//   storage.ErrNotFound if the customer does not exist, otherwise nil.
+	UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error)
+
+	// GetCustomers retrieves all customers.
+	// Returns:
+	// - *[]models.Customer: The customers ordered by CUIT.
+	// - error: An error if the operation fails, otherwise nil.
+	GetCustomers(ctx context.Context) (*[]models.Customer, error)
+
+	// AddCustomerToBank makes a customer a member of a bank.
+	// Parameters:
+	// - customerCuit: The CUIT of the customer.
+	// - bankCuit: The CUIT of the bank.
+	// Returns:
+	// - error: storage.ErrNotFound if the customer or bank do not exist,
+	//   storage.ErrAlreadyExists if the customer is already a member, otherwise nil.
+	AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error
+
+	// RemoveCustomerFromBank ends the membership of a customer in a bank.
+	// Parameters:
+	// - customerCuit: The CUIT of the customer.
+	// - bankCuit: The CUIT of the bank.
+	// Returns:
+	// - error: storage.ErrNotFound if the customer, the bank or the membership do not exist, otherwise nil.
+	RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error
+}
+
+// customerService is a concrete implementation of the CustomerService interface.
+// It uses a repository (ICustomerStorage) to perform data operations.
+type customerService struct {
+	repo storage.ICustomerStorage
+	now  func() time.Time
+}
+
+// NewCustomerService creates and initializes a new CustomerService instance.
+// Parameters:
+// - repo: An ICustomerStorage repository interface for interacting with the data layer.
+// Returns:
+// - CustomerService: A new instance of the service struct implementing the CustomerService interface.
+func NewCustomerService(repo storage.ICustomerStorage) CustomerService {
+	return &customerService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// CreateCustomer validates and registers a new customer.
+func (s *customerService) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	customer = normalizeCustomer(customer)
+	if err := validateCuit("customer CUIT", customer.Cuit); err != nil {
+		return nil, err
+	}
+	if err := validateCustomer(customer); err != nil {
+		return nil, err
+	}
+	if customer.EntryDate.IsZero() {
+		customer.EntryDate = s.now()
+	}
+	return s.repo.CreateCustomer(ctx, customer)
+}
+
+// GetCustomerByCuit retrieves a customer by its CUIT.
+func (s *customerService) GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error) {
+	return s.repo.GetCustomerByCuit(ctx, strings.TrimSpace(cuit))
+}
+
+// UpdateCustomer validates and replaces the details of a customer.
+func (s *customerService) UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	cuit = strings.TrimSpace(cuit)
+	customer = normalizeCustomer(customer)
+	if customer.Cuit != "" && customer.Cuit != cuit {
+		return nil, validationError("customer CUIT cannot be changed from %s to %s", cuit, customer.Cuit)
+	}
+	if err := validateCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	if customer.EntryDate.IsZero() {
+		existing, err := s.repo.GetCustomerByCuit(ctx, cuit)
+		if err != nil {
+			return nil, err
+		}
+		customer.EntryDate = existing.EntryDate
+	}
+	customer.Cuit = cuit
+	return s.repo.UpdateCustomer(ctx, cuit, customer)
+}
+
+// GetCustomers retrieves all customers.
+func (s *customerService) GetCustomers(ctx context.Context) (*[]models.Customer, error) {
+	return s.repo.GetCustomers(ctx)
+}
+
+// AddCustomerToBank makes a customer a member of a bank.
+func (s *customerService) AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	if err := validateCuit("bank CUIT", strings.TrimSpace(bankCuit)); err != nil {
+		return err
+	}
+	return s.repo.AddCustomerToBank(ctx, strings.TrimSpace(customerCuit), strings.TrimSpace(bankCuit))
+}
+
+// RemoveCustomerFromBank ends the membership of a customer in a bank.
+func (s *customerService) RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	if err := validateCuit("bank CUIT", strings.TrimSpace(bankCuit)); err != nil {
+		return err
+	}
+	return s.repo.RemoveCustomerFromBank(ctx, strings.TrimSpace(customerCuit), strings.TrimSpace(bankCuit))
+}
+
+// normalizeCustomer trims the surrounding whitespace of the customer's text fields.
+func normalizeCustomer(customer models.Customer) models.Customer {
+	customer.CompleteName = strings.TrimSpace(customer.CompleteName)
+	customer.Dni = strings.TrimSpace(customer.Dni)
+	customer.Cuit = strings.TrimSpace(customer.Cuit)
+	customer.Address = strings.TrimSpace(customer.Address)
+	customer.Telephone = strings.TrimSpace(customer.Telephone)
+	return customer
+}
+
+// validateCustomer checks the personal details of a customer before it is stored.
+func validateCustomer(customer models.Customer) error {
+	if customer.CompleteName == "" {
+		return validationError("customer name is required")
+	}
+	if !dniPattern.MatchString(customer.Dni) {
+		return validationError("DNI '%s' must have 7 or 8 digits", customer.Dni)
+	}
+	return nil
+}
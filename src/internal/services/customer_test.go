@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type customerStorageStub struct {
+	storage.ICustomerStorage
+	customers map[string]models.Customer
+}
+
+func (s *customerStorageStub) CreateCustomer(_ context.Context, customer models.Customer) (*models.Customer, error) {
+	if _, ok := s.customers[customer.Cuit]; ok {
+		return nil, storage.ErrAlreadyExists
+	}
+	s.customers[customer.Cuit] = customer
+	return &customer, nil
+}
+
+func (s *customerStorageStub) GetCustomerByCuit(_ context.Context, cuit string) (*models.Customer, error) {
+	customer, ok := s.customers[cuit]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &customer, nil
+}
+
+func (s *customerStorageStub) UpdateCustomer(_ context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	if _, ok := s.customers[cuit]; !ok {
+		return nil, storage.ErrNotFound
+	}
+	s.customers[cuit] = customer
+	return &customer, nil
+}
+
+func (s *customerStorageStub) AddCustomerToBank(_ context.Context, customerCuit string, bankCuit string) error {
+	customer, ok := s.customers[customerCuit]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	customer.BankCuits = append(customer.BankCuits, bankCuit)
+	s.customers[customerCuit] = customer
+	return nil
+}
+
+func TestCreateCustomer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 9, 10, 0, 0, 0, time.UTC)
+	customers := &customerStorageStub{customers: map[string]models.Customer{}}
+	service := &customerService{repo: customers, now: func() time.Time { return now }}
+
+	valid := models.Customer{
+		CompleteName: " Jane Doe ",
+		Dni:          "12345678",
+		Cuit:         "27-12345678-4",
+		Address:      "123 Elm St",
+	}
+
+	created, err := service.CreateCustomer(ctx, valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", created.CompleteName)
+	assert.Equal(t, now, created.EntryDate)
+
+	_, err = service.CreateCustomer(ctx, valid)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	tests := []struct {
+		name   string
+		mutate func(c *models.Customer)
+	}{
+		{"missing name", func(c *models.Customer) { c.CompleteName = "  " }},
+		{"short DNI", func(c *models.Customer) { c.Dni = "123456" }},
+		{"non numeric DNI", func(c *models.Customer) { c.Dni = "1234567A" }},
+		{"malformed CUIT", func(c *models.Customer) { c.Cuit = "27123456784" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := valid
+			customer.Cuit = "27-87654321-4"
+			tt.mutate(&customer)
+
+			_, err := service.CreateCustomer(ctx, customer)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+	assert.Len(t, customers.customers, 1)
+}
+
+func TestUpdateCustomer(t *testing.T) {
+	ctx := context.Background()
+	entryDate := time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)
+	customers := &customerStorageStub{customers: map[string]models.Customer{
+		"20-12345678-9": {CompleteName: "John Doe", Dni: "12345678", Cuit: "20-12345678-9", EntryDate: entryDate},
+	}}
+	service := NewCustomerService(customers)
+
+	updated, err := service.UpdateCustomer(ctx, "20-12345678-9", models.Customer{CompleteName: "John A. Doe", Dni: "12345678", Telephone: "555-0101"})
+	assert.NoError(t, err)
+	assert.Equal(t, "John A. Doe", updated.CompleteName)
+	assert.Equal(t, "20-12345678-9", updated.Cuit)
+	assert.Equal(t, entryDate, updated.EntryDate, "a missing entry date keeps the current one")
+
+	_, err = service.UpdateCustomer(ctx, "20-12345678-9", models.Customer{CompleteName: "John Doe", Dni: "12345678", Cuit: "20-87654321-9"})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.UpdateCustomer(ctx, "20-12345678-9", models.Customer{CompleteName: "", Dni: "12345678"})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = service.UpdateCustomer(ctx, "20-00000000-0", models.Customer{CompleteName: "Nobody", Dni: "1234567"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestAddCustomerToBank(t *testing.T) {
+	ctx := context.Background()
+	customers := &customerStorageStub{customers: map[string]models.Customer{
+		"20-12345678-9": {CompleteName: "John Doe", Dni: "12345678", Cuit: "20-12345678-9"},
+	}}
+	service := NewCustomerService(customers)
+
+	assert.NoError(t, service.AddCustomerToBank(ctx, "20-12345678-9", " 30-12345678-9 "))
+	assert.Equal(t, []string{"30-12345678-9"}, customers.customers["20-12345678-9"].BankCuits)
+
+	assert.ErrorIs(t, service.AddCustomerToBank(ctx, "20-12345678-9", "santander"), ErrValidation)
+	assert.ErrorIs(t, service.AddCustomerToBank(ctx, "20-00000000-0", "30-12345678-9"), storage.ErrNotFound)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// exchangeRateColumns is the header of the CSV files the exchange rates are imported from.
+var exchangeRateColumns = []string{"date", "currency", "rate"}
+
+// ExchangeRateService defines the interface for exchange-rate operations.
+// This service abstracts business logic and data layer interactions,
+// providing a clear contract for loading the daily exchange rates of foreign currencies and looking them up.
+type ExchangeRateService interface {
+	// ImportExchangeRates validates and stores the exchange rates of a CSV file.
+	// The file starts with the header date,currency,rate and has one rate per line, such as 2025-04-01,USD,1072.50.
+	// A rate already stored for the same currency and day is replaced. The file is imported entirely or not at all.
+	// Parameters:
+	// - reader: The content of the CSV file.
+	// Returns:
+	// - []models.ExchangeRate: The imported rates, in the order of the file.
+	// - error: An error wrapping ErrValidation naming the offending line if the file is invalid, or any storage error.
+	ImportExchangeRates(ctx context.Context, reader io.Reader) ([]models.ExchangeRate, error)
+
+	// GetExchangeRates retrieves the rates of a currency from one day to another, both included.
+	// Parameters:
+	// - currency: The code of the foreign currency.
+	// - from: The first day.
+	// - to: The last day.
+	// Returns:
+	// - *[]models.ExchangeRate: The rates ordered by day.
+	// - error: An error wrapping ErrValidation if the currency or the period is invalid, or any storage error.
+	GetExchangeRates(ctx context.Context, currency string, from time.Time, to time.Time) (*[]models.ExchangeRate, error)
+
+	// GetExchangeRate retrieves the rate of a currency that applies on a day: the latest one on or before it.
+	// Parameters:
+	// - currency: The code of the foreign currency.
+	// - date: The day.
+	// Returns:
+	// - *models.ExchangeRate: The applicable rate.
+	// - error: An error wrapping ErrValidation if the currency is invalid, a wrapped storage.ErrNotFound if there is
+	//   no rate on or before the day, or any storage error.
+	GetExchangeRate(ctx context.Context, currency string, date time.Time) (*models.ExchangeRate, error)
+}
+
+// exchangeRateService is a concrete implementation of the ExchangeRateService interface.
+// It uses a repository (IExchangeRateStorage) to perform data operations.
+type exchangeRateService struct {
+	repo storage.IExchangeRateStorage
+}
+
+// NewExchangeRateService creates and initializes a new ExchangeRateService instance.
+// Parameters:
+// - repo: An IExchangeRateStorage repository interface for interacting with the data layer.
+// Returns:
+// - ExchangeRateService: A new instance of the service struct implementing the ExchangeRateService interface.
+func NewExchangeRateService(repo storage.IExchangeRateStorage) ExchangeRateService {
+	return &exchangeRateService{
+		repo: repo,
+	}
+}
+
+// ImportExchangeRates validates the rates of a CSV file and stores them.
+func (s *exchangeRateService) ImportExchangeRates(ctx context.Context, reader io.Reader) ([]models.ExchangeRate, error) {
+	rates, err := parseExchangeRates(reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveExchangeRates(ctx, rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetExchangeRates retrieves the rates of a currency in a period.
+func (s *exchangeRateService) GetExchangeRates(ctx context.Context, currency string, from time.Time, to time.Time) (*[]models.ExchangeRate, error) {
+	parsed, err := parseForeignCurrency(currency)
+	if err != nil {
+		return nil, validationError("%v", err)
+	}
+	if to.Before(from) {
+		return nil, validationError("the period ends on %s, before it starts on %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	return s.repo.GetExchangeRates(ctx, parsed, from, to)
+}
+
+// GetExchangeRate retrieves the rate of a currency that applies on a day.
+func (s *exchangeRateService) GetExchangeRate(ctx context.Context, currency string, date time.Time) (*models.ExchangeRate, error) {
+	parsed, err := parseForeignCurrency(currency)
+	if err != nil {
+		return nil, validationError("%v", err)
+	}
+	return s.repo.GetExchangeRate(ctx, parsed, date)
+}
+
+// parseExchangeRates reads and validates the rates of a CSV file.
+func parseExchangeRates(reader io.Reader) ([]models.ExchangeRate, error) {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = len(exchangeRateColumns)
+	records.TrimLeadingSpace = true
+
+	header, err := records.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, validationError("the exchange rates file is empty")
+	}
+	if err != nil {
+		return nil, validationError("invalid exchange rates file: %v", err)
+	}
+	for i, column := range exchangeRateColumns {
+		if !strings.EqualFold(strings.TrimSpace(header[i]), column) {
+			return nil, validationError("line 1: expected the header %s", strings.Join(exchangeRateColumns, ","))
+		}
+	}
+
+	var rates []models.ExchangeRate
+	seen := map[string]int{}
+	for {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, validationError("invalid exchange rates file: %v", err)
+		}
+		line, _ := records.FieldPos(0)
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, validationError("line %d: invalid date '%s', expected YYYY-MM-DD", line, record[0])
+		}
+		currency, err := parseForeignCurrency(record[1])
+		if err != nil {
+			return nil, validationError("line %d: %v", line, err)
+		}
+		rate, err := models.ParseMoney(strings.TrimSpace(record[2]))
+		if err != nil || rate <= 0 {
+			return nil, validationError("line %d: the rate must be a positive amount, got '%s'", line, record[2])
+		}
+
+		key := string(currency) + " " + date.Format(time.DateOnly)
+		if previous, ok := seen[key]; ok {
+			return nil, validationError("line %d: the %s rate of %s is already on line %d", line, currency, date.Format(time.DateOnly), previous)
+		}
+		seen[key] = line
+
+		rates = append(rates, models.ExchangeRate{Currency: currency, Date: date, Rate: rate})
+	}
+
+	if len(rates) == 0 {
+		return nil, validationError("the exchange rates file has no rates")
+	}
+	return rates, nil
+}
+
+// parseForeignCurrency parses the code of a supported currency other than the base one, which has no exchange rate.
+func parseForeignCurrency(code string) (models.Currency, error) {
+	if strings.TrimSpace(code) == "" {
+		return "", errors.New("the currency is required")
+	}
+	currency, err := models.ParseCurrency(code)
+	if err != nil {
+		return "", err
+	}
+	if currency == models.BaseCurrency {
+		return "", fmt.Errorf("%s is the base currency and has no exchange rate", currency)
+	}
+	return currency, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportExchangeRates(t *testing.T) {
+	ctx := context.Background()
+	rates := &exchangeRateStorageStub{}
+	service := NewExchangeRateService(rates)
+
+	imported, err := service.ImportExchangeRates(ctx, strings.NewReader("date,currency,rate\n2025-04-01,USD,1072.50\n2025-04-02, usd ,1080\n"))
+
+	assert.NoError(t, err)
+	expected := []models.ExchangeRate{
+		{Currency: models.CurrencyUSD, Date: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1072.50")},
+		{Currency: models.CurrencyUSD, Date: time.Date(2025, time.April, 2, 0, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1080")},
+	}
+	assert.Equal(t, expected, imported)
+	assert.Equal(t, expected, rates.rates)
+}
+
+func TestImportExchangeRatesValidation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"empty file", "", "empty"},
+		{"no rates", "date,currency,rate\n", "no rates"},
+		{"wrong header", "day,currency,rate\n2025-04-01,USD,1072.50\n", "line 1"},
+		{"malformed date", "date,currency,rate\n01/04/2025,USD,1072.50\n", "line 2"},
+		{"unsupported currency", "date,currency,rate\n2025-04-01,EUR,1150\n", "line 2"},
+		{"base currency", "date,currency,rate\n2025-04-01,ARS,1\n", "line 2"},
+		{"zero rate", "date,currency,rate\n2025-04-01,USD,0\n", "line 2"},
+		{"malformed rate", "date,currency,rate\n2025-04-01,USD,1.072,50\n", "line 2"},
+		{"duplicated day", "date,currency,rate\n2025-04-01,USD,1072.50\n2025-04-02,USD,1080\n2025-04-01,USD,1075\n", "line 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := &exchangeRateStorageStub{}
+
+			_, err := NewExchangeRateService(rates).ImportExchangeRates(ctx, strings.NewReader(tt.content))
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+			assert.ErrorContains(t, err, tt.message)
+			assert.Empty(t, rates.rates, "nothing is stored from an invalid file")
+		})
+	}
+}
+
+func TestGetExchangeRateValidation(t *testing.T) {
+	ctx := context.Background()
+	service := NewExchangeRateService(&exchangeRateStorageStub{})
+	day := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetExchangeRate(ctx, "ARS", day)
+	assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+
+	_, err = service.GetExchangeRates(ctx, "XYZ", day, day)
+	assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+
+	_, err = service.GetExchangeRates(ctx, "USD", day, day.AddDate(0, 0, -1))
+	assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// FinancingService defines the interface for simulating the installment plans of purchases.
+// Nothing is stored: the plans show what a purchase would cost before it is registered.
+type FinancingService interface {
+	// Simulate computes the installment plan of an amount, with the terms of a financing promotion or explicit terms.
+	// Parameters:
+	// - request: The amount and either the code of a financing promotion, or the number of quotas, interest and interest model.
+	// - purchaseDate: The date of the purchase, which sets the month of the first quota. It defaults to now.
+	// Returns:
+	// - *models.InstallmentPlan: The plan, with the capital and interest of each quota and its TNA, TEA and CFT.
+	// - error: A validation error if the terms are invalid or the promotion is not an active financing,
+	//   storage.ErrNotFound if no promotion has the code, otherwise nil.
+	Simulate(ctx context.Context, request models.FinancingSimulationRequest, purchaseDate time.Time) (*models.InstallmentPlan, error)
+}
+
+// financingService is a concrete implementation of the FinancingService interface.
+// It uses a repository (IPromotionStorage) to look up the terms of financing promotions.
+type financingService struct {
+	repo storage.IPromotionStorage
+	now  func() time.Time
+}
+
+// NewFinancingService creates and initializes a new FinancingService instance.
+// Parameters:
+// - repo: An IPromotionStorage repository interface for interacting with the data layer.
+// Returns:
+// - FinancingService: A new instance of the service struct implementing the FinancingService interface.
+func NewFinancingService(repo storage.IPromotionStorage) FinancingService {
+	return &financingService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Simulate computes the installment plan of an amount.
+func (s *financingService) Simulate(ctx context.Context, request models.FinancingSimulationRequest, purchaseDate time.Time) (*models.InstallmentPlan, error) {
+	if purchaseDate.IsZero() {
+		purchaseDate = s.now()
+	}
+	if request.PromotionCode == "" {
+		return NewInstallmentPlan(request.InterestModel, request.Amount, request.Interest, request.NumberOfQuotas, purchaseDate)
+	}
+
+	if request.NumberOfQuotas != 0 || request.Interest != 0 || request.InterestModel != "" {
+		return nil, validationError("number of quotas, interest and interest model are taken from promotion %s", request.PromotionCode)
+	}
+	detail, err := s.repo.GetPromotionByCode(ctx, request.PromotionCode)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Financing == nil {
+		return nil, validationError("promotion %s is not a financing promotion", request.PromotionCode)
+	}
+	if detail.IsDeleted {
+		return nil, validationError("promotion %s is deleted", request.PromotionCode)
+	}
+
+	financing := detail.Financing
+	return NewInstallmentPlan(financing.InterestModel, request.Amount, financing.Interest, financing.NumberOfQuotas, purchaseDate)
+}
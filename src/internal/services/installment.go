@@ -0,0 +1,265 @@
+package services
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+)
+
+// monthsPerYear converts the monthly rates of installment plans to annual ones.
+const monthsPerYear = 12
+
+// QuotaSplit is the part of a quota price that repays capital and the part that pays interest.
+type QuotaSplit struct {
+	Capital  models.Money
+	Interest models.Money
+}
+
+// InstallmentCalculator computes the quotas of an installment plan under an interest model.
+// Calculators are looked up by interest model with InstallmentCalculatorFor; supporting a new interest model
+// is a matter of implementing this interface and registering it in installmentCalculators.
+type InstallmentCalculator interface {
+	// ValidateInterest checks that the interest rate is meaningful for the interest model.
+	// Returns:
+	// - error: An error wrapping ErrValidation if the rate cannot be used, otherwise nil.
+	ValidateInterest(interest models.Percentage) error
+
+	// Split splits an amount into the capital and interest of each quota.
+	// Parameters:
+	// - amount: The amount financed. The capitals of the quotas add up to it exactly.
+	// - interest: The interest rate, read according to the interest model.
+	// - numberOfQuotas: The number of monthly quotas, between 1 and maxQuotas.
+	// Returns:
+	// - []QuotaSplit: The split of each quota, ordered by quota number.
+	Split(amount models.Money, interest models.Percentage, numberOfQuotas int) []QuotaSplit
+
+	// NominalAnnualRate returns the nominal annual rate (TNA) of the interest rate, as a fraction of one.
+	// Parameters:
+	// - interest: The interest rate, read according to the interest model.
+	// - numberOfQuotas: The number of monthly quotas, between 1 and maxQuotas.
+	// Returns:
+	// - *big.Rat: The exact nominal annual rate.
+	NominalAnnualRate(interest models.Percentage, numberOfQuotas int) *big.Rat
+}
+
+// installmentCalculators are the calculators of the supported interest models.
+var installmentCalculators = map[models.InterestModel]InstallmentCalculator{
+	models.InterestModelFlat:   flatCalculator{},
+	models.InterestModelFrench: frenchCalculator{},
+	models.InterestModelZero:   zeroCalculator{},
+}
+
+// InstallmentCalculatorFor returns the calculator of an interest model. An empty model is the flat model.
+// Returns:
+// - InstallmentCalculator: The calculator of the interest model.
+// - error: An error wrapping ErrValidation if the interest model is not supported.
+func InstallmentCalculatorFor(model models.InterestModel) (InstallmentCalculator, error) {
+	calculator, ok := installmentCalculators[model.OrFlat()]
+	if !ok {
+		return nil, validationError("unknown interest model '%s' (expected flat, french or zero)", model)
+	}
+	return calculator, nil
+}
+
+// NewInstallmentPlan computes the installment plan of an amount: the quota schedule with the capital and interest
+// of each quota, and the nominal annual rate (TNA), effective annual rate (TEA) and total financial cost (CFT).
+// The first quota is due in the month of the purchase, as in GenerateQuotas.
+// Parameters:
+// - model: The interest model; empty is the flat model.
+// - amount: The amount financed.
+// - interest: The interest rate, read according to the interest model.
+// - numberOfQuotas: The number of monthly quotas, between 1 and maxQuotas.
+// - purchaseDate: The date the purchase is made.
+// Returns:
+// - *models.InstallmentPlan: The computed plan.
+// - error: An error wrapping ErrValidation if the terms of the plan are invalid.
+func NewInstallmentPlan(model models.InterestModel, amount models.Money, interest models.Percentage, numberOfQuotas int, purchaseDate time.Time) (*models.InstallmentPlan, error) {
+	calculator, err := InstallmentCalculatorFor(model)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, validationError("amount must be positive, got %s", amount)
+	}
+	if err := validateNumberOfQuotas(numberOfQuotas); err != nil {
+		return nil, err
+	}
+	if interest < 0 {
+		return nil, validationError("interest cannot be negative, got %s", interest)
+	}
+	if err := calculator.ValidateInterest(interest); err != nil {
+		return nil, err
+	}
+
+	plan := &models.InstallmentPlan{
+		InterestModel:  model.OrFlat(),
+		Amount:         amount,
+		Interest:       interest,
+		NumberOfQuotas: numberOfQuotas,
+		Quotas:         make([]models.InstallmentQuota, 0, numberOfQuotas),
+	}
+
+	balance := amount
+	months := quotaMonths(numberOfQuotas, purchaseDate)
+	for i, split := range calculator.Split(amount, interest, numberOfQuotas) {
+		balance -= split.Capital
+		quota := months[i]
+		quota.Price = split.Capital + split.Interest
+		plan.Quotas = append(plan.Quotas, models.InstallmentQuota{Quota: quota, Capital: split.Capital, Interest: split.Interest, Balance: balance})
+		plan.TotalInterest += split.Interest
+		plan.FinalAmount += quota.Price
+	}
+
+	nominal := calculator.NominalAnnualRate(interest, numberOfQuotas)
+	plan.TNA = models.RoundPercentage(nominal)
+	plan.TEA = models.RoundPercentage(annualize(new(big.Rat).Quo(nominal, big.NewRat(monthsPerYear, 1))))
+	plan.CFT = models.RoundPercentage(annualize(internalRate(amount, plan.Quotas)))
+	return plan, nil
+}
+
+// PlanQuotas returns the quotas of an installment plan without their capital and interest, as they are stored.
+func PlanQuotas(plan *models.InstallmentPlan) []models.Quota {
+	quotas := make([]models.Quota, 0, len(plan.Quotas))
+	for _, quota := range plan.Quotas {
+		quotas = append(quotas, quota.Quota)
+	}
+	return quotas
+}
+
+// flatCalculator adds the interest rate, as a percentage of the amount, once over the whole plan.
+// The final amount and the capital are split evenly, the last quota absorbing the remainders.
+type flatCalculator struct{}
+
+func (flatCalculator) ValidateInterest(models.Percentage) error {
+	return nil
+}
+
+func (flatCalculator) Split(amount models.Money, interest models.Percentage, numberOfQuotas int) []QuotaSplit {
+	prices := splitEvenly(amount+amount.Percent(interest), numberOfQuotas)
+	capitals := splitEvenly(amount, numberOfQuotas)
+
+	splits := make([]QuotaSplit, numberOfQuotas)
+	for i := range splits {
+		splits[i] = QuotaSplit{Capital: capitals[i], Interest: prices[i] - capitals[i]}
+	}
+	return splits
+}
+
+// NominalAnnualRate spreads the rate over the months of the plan: 10% over 6 quotas is a TNA of 20%.
+func (flatCalculator) NominalAnnualRate(interest models.Percentage, numberOfQuotas int) *big.Rat {
+	return new(big.Rat).Mul(interest.Rat(), big.NewRat(monthsPerYear, int64(numberOfQuotas)))
+}
+
+// frenchCalculator charges a monthly rate, the nominal annual rate over twelve, on the outstanding capital.
+// Every quota has the same price, rounded to the cent, and the last one repays the remaining capital.
+type frenchCalculator struct{}
+
+func (frenchCalculator) ValidateInterest(models.Percentage) error {
+	return nil
+}
+
+func (frenchCalculator) Split(amount models.Money, interest models.Percentage, numberOfQuotas int) []QuotaSplit {
+	rate := new(big.Rat).Quo(interest.Rat(), big.NewRat(monthsPerYear, 1))
+	if rate.Sign() == 0 {
+		return zeroCalculator{}.Split(amount, interest, numberOfQuotas)
+	}
+
+	// price = amount * rate * (1 + rate)^n / ((1 + rate)^n - 1)
+	growth := ratPow(new(big.Rat).Add(big.NewRat(1, 1), rate), numberOfQuotas)
+	price := new(big.Rat).Mul(amount.Rat(), rate)
+	price.Mul(price, growth)
+	price.Quo(price, new(big.Rat).Sub(growth, big.NewRat(1, 1)))
+	roundedPrice := models.RoundMoney(price)
+
+	splits := make([]QuotaSplit, numberOfQuotas)
+	balance := amount
+	for i := range splits {
+		interest := models.RoundMoney(new(big.Rat).Mul(balance.Rat(), rate))
+		capital := roundedPrice - interest
+		if i == numberOfQuotas-1 || capital > balance {
+			capital = balance
+		}
+		splits[i] = QuotaSplit{Capital: capital, Interest: interest}
+		balance -= capital
+	}
+	return splits
+}
+
+func (frenchCalculator) NominalAnnualRate(interest models.Percentage, _ int) *big.Rat {
+	return interest.Rat()
+}
+
+// zeroCalculator splits the amount evenly without interest, the last quota absorbing the remainder.
+type zeroCalculator struct{}
+
+func (zeroCalculator) ValidateInterest(interest models.Percentage) error {
+	if interest != 0 {
+		return validationError("zero-interest plans cannot have an interest rate, got %s", interest)
+	}
+	return nil
+}
+
+func (zeroCalculator) Split(amount models.Money, _ models.Percentage, numberOfQuotas int) []QuotaSplit {
+	splits := make([]QuotaSplit, numberOfQuotas)
+	for i, capital := range splitEvenly(amount, numberOfQuotas) {
+		splits[i] = QuotaSplit{Capital: capital}
+	}
+	return splits
+}
+
+func (zeroCalculator) NominalAnnualRate(models.Percentage, int) *big.Rat {
+	return new(big.Rat)
+}
+
+// ratPow raises a fraction to a non-negative integer power.
+func ratPow(base *big.Rat, exponent int) *big.Rat {
+	result := big.NewRat(1, 1)
+	for i := 0; i < exponent; i++ {
+		result.Mul(result, base)
+	}
+	return result
+}
+
+// annualize returns the effective annual rate of a monthly rate compounded for a year.
+func annualize(monthly *big.Rat) *big.Rat {
+	effective := ratPow(new(big.Rat).Add(big.NewRat(1, 1), monthly), monthsPerYear)
+	return effective.Sub(effective, big.NewRat(1, 1))
+}
+
+// internalRate returns the monthly rate at which the quotas, the first one paid a month after the purchase, repay the
+// amount: the rate whose present value of the quotas is the amount. It is found by bisection, precise well beyond the
+// hundredth of a percent the rates are reported with.
+func internalRate(amount models.Money, quotas []models.InstallmentQuota) *big.Rat {
+	var total models.Money
+	for _, quota := range quotas {
+		total += quota.Price
+	}
+	if total <= amount {
+		return new(big.Rat)
+	}
+
+	target := float64(amount.Cents())
+	presentValue := func(rate float64) float64 {
+		value := 0.0
+		for i, quota := range quotas {
+			value += float64(quota.Price.Cents()) / math.Pow(1+rate, float64(i+1))
+		}
+		return value
+	}
+
+	low, high := 0.0, 1.0
+	for presentValue(high) > target {
+		high *= 2
+	}
+	for i := 0; i < 200 && high-low > 1e-15; i++ {
+		middle := (low + high) / 2
+		if presentValue(middle) > target {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return new(big.Rat).SetFloat64((low + high) / 2)
+}
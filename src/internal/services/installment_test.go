@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInstallmentPlanFrench(t *testing.T) {
+	purchaseDate := time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC)
+
+	plan, err := NewInstallmentPlan(models.InterestModelFrench, models.MustParseMoney("1000"), models.MustParsePercentage("12"), 3, purchaseDate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.InstallmentQuota{
+		{Quota: models.Quota{Number: 1, Price: models.MustParseMoney("340.02"), Month: "04", Year: "2025"}, Capital: models.MustParseMoney("330.02"), Interest: models.MustParseMoney("10.00"), Balance: models.MustParseMoney("669.98")},
+		{Quota: models.Quota{Number: 2, Price: models.MustParseMoney("340.02"), Month: "05", Year: "2025"}, Capital: models.MustParseMoney("333.32"), Interest: models.MustParseMoney("6.70"), Balance: models.MustParseMoney("336.66")},
+		{Quota: models.Quota{Number: 3, Price: models.MustParseMoney("340.03"), Month: "06", Year: "2025"}, Capital: models.MustParseMoney("336.66"), Interest: models.MustParseMoney("3.37"), Balance: 0},
+	}, plan.Quotas)
+	assert.Equal(t, models.MustParseMoney("20.07"), plan.TotalInterest)
+	assert.Equal(t, models.MustParseMoney("1020.07"), plan.FinalAmount)
+	assert.Equal(t, models.MustParsePercentage("12"), plan.TNA)
+	assert.Equal(t, models.MustParsePercentage("12.68"), plan.TEA)
+	assert.Equal(t, models.MustParsePercentage("12.68"), plan.CFT)
+}
+
+func TestNewInstallmentPlanFrenchLongTerm(t *testing.T) {
+	plan, err := NewInstallmentPlan(models.InterestModelFrench, models.MustParseMoney("1200"), models.MustParsePercentage("60"), 12, time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	for _, quota := range plan.Quotas[:11] {
+		assert.Equal(t, models.MustParseMoney("135.39"), quota.Price, "quota %d", quota.Number)
+	}
+	assert.Equal(t, models.MustParseMoney("135.40"), plan.Quotas[11].Price, "the last quota repays the remaining capital")
+	assert.Equal(t, models.Money(0), plan.Quotas[11].Balance)
+	assert.Equal(t, models.MustParseMoney("1624.69"), plan.FinalAmount)
+	assert.Equal(t, models.MustParsePercentage("79.59"), plan.TEA)
+	assert.Equal(t, "01", plan.Quotas[9].Month, "quotas roll over to the next year")
+	assert.Equal(t, "2026", plan.Quotas[9].Year)
+}
+
+func TestNewInstallmentPlanFlat(t *testing.T) {
+	plan, err := NewInstallmentPlan(models.InterestModelFlat, models.MustParseMoney("1000"), models.MustParsePercentage("10"), 3, time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.InstallmentQuota{
+		{Quota: models.Quota{Number: 1, Price: models.MustParseMoney("366.66"), Month: "04", Year: "2025"}, Capital: models.MustParseMoney("333.33"), Interest: models.MustParseMoney("33.33"), Balance: models.MustParseMoney("666.67")},
+		{Quota: models.Quota{Number: 2, Price: models.MustParseMoney("366.66"), Month: "05", Year: "2025"}, Capital: models.MustParseMoney("333.33"), Interest: models.MustParseMoney("33.33"), Balance: models.MustParseMoney("333.34")},
+		{Quota: models.Quota{Number: 3, Price: models.MustParseMoney("366.68"), Month: "06", Year: "2025"}, Capital: models.MustParseMoney("333.34"), Interest: models.MustParseMoney("33.34"), Balance: 0},
+	}, plan.Quotas)
+	assert.Equal(t, models.MustParseMoney("1100"), plan.FinalAmount, "the flat model keeps the final amount of earlier versions")
+	assert.Equal(t, GenerateQuotas(plan.FinalAmount, 3, time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC)), PlanQuotas(plan))
+	assert.Equal(t, models.MustParsePercentage("40"), plan.TNA, "10% over 3 months is 40% a year")
+	assert.Equal(t, models.MustParsePercentage("48.21"), plan.TEA)
+	assert.Equal(t, models.MustParsePercentage("77.97"), plan.CFT, "the flat model charges interest on capital already repaid")
+}
+
+func TestNewInstallmentPlanZero(t *testing.T) {
+	plan, err := NewInstallmentPlan(models.InterestModelZero, models.MustParseMoney("1000"), 0, 3, time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("1000"), plan.FinalAmount)
+	assert.Equal(t, models.Money(0), plan.TotalInterest)
+	assert.Equal(t, models.Percentage(0), plan.CFT)
+	assert.Equal(t, models.MustParseMoney("333.34"), plan.Quotas[2].Capital)
+}
+
+func TestNewInstallmentPlanDefaultsToFlat(t *testing.T) {
+	plan, err := NewInstallmentPlan("", models.MustParseMoney("1000"), models.MustParsePercentage("10"), 3, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.InterestModelFlat, plan.InterestModel)
+}
+
+func TestNewInstallmentPlanValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		model          models.InterestModel
+		amount         string
+		interest       string
+		numberOfQuotas int
+	}{
+		{"unknown interest model", "german", "1000", "10", 3},
+		{"zero-interest plan with interest", models.InterestModelZero, "1000", "5", 3},
+		{"no quotas", models.InterestModelFrench, "1000", "10", 0},
+		{"too many quotas", models.InterestModelFrench, "1000", "10", maxQuotas + 1},
+		{"negative interest", models.InterestModelFrench, "1000", "-1", 3},
+		{"no amount", models.InterestModelFlat, "0", "10", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewInstallmentPlan(tt.model, models.MustParseMoney(tt.amount), models.MustParsePercentage(tt.interest), tt.numberOfQuotas, time.Now())
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+}
+
+func TestSimulateFinancing(t *testing.T) {
+	ctx := context.Background()
+	purchaseDate := time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC)
+	fin3, d10 := financing("FIN3", 3, "12"), discount("D10", "10", "0", false)
+	fin3.InterestModel = models.InterestModelFrench
+	service := NewFinancingService(&promotionStorageStub{details: map[string]*models.PromotionDetail{
+		"FIN3": {Type: models.PromotionTypeFinancing, Financing: &fin3},
+		"D10":  {Type: models.PromotionTypeDiscount, Discount: &d10},
+		"DEL":  {Type: models.PromotionTypeFinancing, IsDeleted: true, Financing: &fin3},
+	}})
+
+	plan, err := service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), PromotionCode: "FIN3"}, purchaseDate)
+	assert.NoError(t, err)
+	assert.Equal(t, models.InterestModelFrench, plan.InterestModel)
+	assert.Equal(t, models.MustParseMoney("1020.07"), plan.FinalAmount)
+
+	plan, err = service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), NumberOfQuotas: 3, Interest: models.MustParsePercentage("10")}, purchaseDate)
+	assert.NoError(t, err)
+	assert.Equal(t, models.InterestModelFlat, plan.InterestModel)
+	assert.Equal(t, models.MustParseMoney("1100"), plan.FinalAmount)
+
+	_, err = service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), PromotionCode: "D10"}, purchaseDate)
+	assert.True(t, errors.Is(err, ErrValidation), "a discount cannot be simulated, got %v", err)
+
+	_, err = service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), PromotionCode: "DEL"}, purchaseDate)
+	assert.True(t, errors.Is(err, ErrValidation), "a deleted financing cannot be simulated, got %v", err)
+
+	_, err = service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), PromotionCode: "FIN3", NumberOfQuotas: 6}, purchaseDate)
+	assert.True(t, errors.Is(err, ErrValidation), "the terms of a promotion cannot be overridden, got %v", err)
+
+	_, err = service.Simulate(ctx, models.FinancingSimulationRequest{Amount: models.MustParseMoney("1000"), PromotionCode: "MISSING"}, purchaseDate)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// maxPaymentAttempts is the number of times a payment is allocated before giving up, when a concurrent payment of
+// the same summary takes its code or pays the items it was allocated to each time.
+const maxPaymentAttempts = 5
+
+// PaymentService defines the interface for payment operations.
+// This service abstracts business logic and data layer interactions,
+// providing a clear contract for recording the payments of payment summaries and computing the outstanding balance of cards.
+type PaymentService interface {
+	// RecordPayment records a payment of the payment summary of a card for a month.
+	// The amount is allocated to the quotas billed in the summary first, in the order they were billed,
+	// and then to its single-payment purchases, each of them up to what is left to pay of it.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// - month: The month of the paid summary.
+	// - year: The year of the paid summary.
+	// - request: The amount paid and, optionally, the date of the payment.
+	// Returns:
+	// - *models.Payment: The recorded payment and its allocations.
+	// - error: An error wrapping ErrValidation if the request is invalid or the amount exceeds what is left to pay,
+	//   a wrapped storage.ErrNotFound if the card or the summary does not exist, or any storage error.
+	RecordPayment(ctx context.Context, cardNumber string, month int, year int, request models.PaymentRequest) (*models.Payment, error)
+
+	// GetPayments retrieves the payments of the payment summary of a card for a month.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// - month: The month of the summary.
+	// - year: The year of the summary.
+	// Returns:
+	// - *[]models.Payment: The payments, ordered by payment date.
+	// - error: An error if the card or the summary does not exist or the operation fails, otherwise nil.
+	GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error)
+
+	// GetCardBalance computes what is left to pay of the payment summaries of a card.
+	// Items left to pay are overdue once the first expiration date of their summary has passed.
+	// Parameters:
+	// - cardNumber: The number of the card.
+	// Returns:
+	// - *models.CardBalance: The outstanding and overdue amounts, with the summaries and items left to pay.
+	// - error: An error if the card does not exist or the operation fails, otherwise nil.
+	GetCardBalance(ctx context.Context, cardNumber string) (*models.CardBalance, error)
+}
+
+// paymentService is a concrete implementation of the PaymentService interface.
+// It uses the card repository for the paid summary and the payment repository for the payments and the summaries of a card.
+type paymentService struct {
+	cards    storage.ICardStorage
+	payments storage.IPaymentStorage
+	now      func() time.Time
+}
+
+// NewPaymentService creates and initializes a new PaymentService instance.
+// Parameters:
+// - cards: An ICardStorage repository interface holding the payment summaries of the cards.
+// - payments: An IPaymentStorage repository interface holding the payments of the summaries.
+// Returns:
+// - PaymentService: A new instance of the service struct implementing the PaymentService interface.
+func NewPaymentService(cards storage.ICardStorage, payments storage.IPaymentStorage) PaymentService {
+	return &paymentService{
+		cards:    cards,
+		payments: payments,
+		now:      time.Now,
+	}
+}
+
+// RecordPayment validates a payment, allocates it to the items left to pay of the summary and stores it.
+// The storages reject a payment whose code was taken or whose allocations exceed what is left to pay of an item
+// because of a concurrent payment; the payment is then allocated again from the summary as it is now.
+func (s *paymentService) RecordPayment(ctx context.Context, cardNumber string, month int, year int, request models.PaymentRequest) (*models.Payment, error) {
+	if err := validateSummaryMonth(month, year); err != nil {
+		return nil, err
+	}
+	if request.Amount <= 0 {
+		return nil, validationError("amount must be positive, got %s", request.Amount)
+	}
+	paymentDate := s.now().UTC()
+	if request.PaymentDate != "" {
+		parsed, err := time.Parse(time.RFC3339, request.PaymentDate)
+		if err != nil {
+			return nil, validationError("payment date '%s' is not a valid RFC3339 date", request.PaymentDate)
+		}
+		paymentDate = parsed.UTC()
+	}
+
+	for attempt := 1; ; attempt++ {
+		payment, err := s.allocatePayment(ctx, cardNumber, month, year, request.Amount, paymentDate)
+		if err != nil {
+			return nil, err
+		}
+		recorded, err := s.payments.RecordPayment(ctx, *payment)
+		if !errors.Is(err, storage.ErrAlreadyExists) || attempt == maxPaymentAttempts {
+			return recorded, err
+		}
+	}
+}
+
+// allocatePayment builds a payment of the summary of a card for a month, allocating its amount to the items left to
+// pay of the summary, and numbers it after the payments already recorded.
+func (s *paymentService) allocatePayment(ctx context.Context, cardNumber string, month int, year int, amount models.Money, paymentDate time.Time) (*models.Payment, error) {
+	summary, err := s.cards.GetPaymentSummary(ctx, cardNumber, month, year)
+	if err != nil {
+		return nil, err
+	}
+	items := billedItems(summary)
+	var outstanding models.Money
+	for _, item := range items {
+		outstanding += item.Outstanding
+	}
+	if amount > outstanding {
+		return nil, validationError("amount %s exceeds the %s left to pay of the %02d/%d summary of card %s",
+			amount, outstanding, month, year, cardNumber)
+	}
+
+	payments, err := s.payments.GetPayments(ctx, cardNumber, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &models.Payment{
+		Code:        fmt.Sprintf("PAYMENT-%s-%d-%02d-%d", cardNumber, year, month, len(*payments)+1),
+		CardNumber:  cardNumber,
+		Month:       month,
+		Year:        year,
+		Amount:      amount,
+		PaymentDate: paymentDate,
+		Allocations: []models.PaymentAllocation{},
+	}
+	remaining := amount
+	for _, item := range items {
+		if remaining == 0 {
+			break
+		}
+		allocated := min(remaining, item.Outstanding)
+		if allocated <= 0 {
+			continue
+		}
+		payment.Allocations = append(payment.Allocations, models.PaymentAllocation{
+			PaymentVoucher: item.PaymentVoucher,
+			QuotaNumber:    item.QuotaNumber,
+			PurchaseDate:   item.PurchaseDate,
+			Amount:         allocated,
+			BilledAmount:   item.Amount,
+		})
+		remaining -= allocated
+	}
+	return payment, nil
+}
+
+// GetPayments retrieves the payments of the payment summary of a card for a month.
+func (s *paymentService) GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	if err := validateSummaryMonth(month, year); err != nil {
+		return nil, err
+	}
+	return s.payments.GetPayments(ctx, cardNumber, month, year)
+}
+
+// GetCardBalance computes what is left to pay of the payment summaries of a card, keeping only the summaries and
+// items that are not fully paid.
+func (s *paymentService) GetCardBalance(ctx context.Context, cardNumber string) (*models.CardBalance, error) {
+	summaries, err := s.payments.GetPaymentSummaries(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	balance := models.CardBalance{CardNumber: cardNumber, Date: now, Summaries: []models.SummaryBalance{}}
+	for i := range *summaries {
+		summary := &(*summaries)[i]
+		overdue := isOverdue(summary, now)
+		summaryBalance := models.SummaryBalance{
+			Code:             summary.Code,
+			Month:            summary.Month,
+			Year:             summary.Year,
+			FirstExpiration:  summary.FirstExpiration,
+			SecondExpiration: summary.SecondExpiration,
+			TotalPrice:       summary.TotalPrice,
+			Items:            []models.BalanceItem{},
+		}
+		for _, item := range billedItems(summary) {
+			summaryBalance.PaidAmount += item.PaidAmount
+			summaryBalance.Outstanding += item.Outstanding
+			if item.Outstanding == 0 {
+				continue
+			}
+			item.Status = paymentStatus(item.PaidAmount, item.Outstanding, overdue)
+			summaryBalance.Items = append(summaryBalance.Items, item)
+		}
+		summaryBalance.Status = paymentStatus(summaryBalance.PaidAmount, summaryBalance.Outstanding, overdue)
+		if summaryBalance.Outstanding == 0 {
+			continue
+		}
+
+		balance.Outstanding += summaryBalance.Outstanding
+		if overdue {
+			balance.Overdue += summaryBalance.Outstanding
+		}
+		balance.Summaries = append(balance.Summaries, summaryBalance)
+	}
+	return &balance, nil
+}
+
+// validateSummaryMonth checks the month and year that identify a payment summary.
+func validateSummaryMonth(month int, year int) error {
+	if month < 1 || month > 12 {
+		return validationError("month must be between 1 and 12, got %d", month)
+	}
+	if year < 1 {
+		return validationError("year must be positive, got %d", year)
+	}
+	return nil
+}
+
+// billedItems lists the quotas and then the single-payment purchases billed in a summary, with their amount in pesos
+// and what is left to pay of them. The total of each currency subtotal is split among its items in proportion to
+// their amount, the last item taking the rounding difference, so that the items add up to the total price. Summaries
+// closed before purchases had a currency have no subtotals, their items are in pesos.
+func billedItems(summary *models.PaymentSummary) []models.BalanceItem {
+	items := []models.BalanceItem{}
+	amounts := []models.Money{}
+	currencies := []models.Currency{}
+	for _, quota := range summary.Quotas {
+		items = append(items, models.BalanceItem{
+			PaymentVoucher: quota.PaymentVoucher,
+			Store:          quota.Store,
+			QuotaNumber:    quota.Number,
+			NumberOfQuotas: quota.NumberOfQuotas,
+			PaidAmount:     quota.PaidAmount,
+		})
+		amounts = append(amounts, quota.Price)
+		currencies = append(currencies, models.BaseCurrency)
+	}
+	for _, purchase := range summary.SinglePayments {
+		purchaseDate := purchase.PurchaseDate.UTC()
+		items = append(items, models.BalanceItem{
+			PaymentVoucher: purchase.PaymentVoucher,
+			Store:          purchase.Store,
+			PurchaseDate:   &purchaseDate,
+			PaidAmount:     purchase.PaidAmount,
+		})
+		amounts = append(amounts, purchase.FinalAmount)
+		currencies = append(currencies, purchase.Currency.OrBase())
+	}
+	for i := range items {
+		items[i].Amount = amounts[i]
+	}
+
+	for _, subtotal := range summary.Subtotals {
+		last := -1
+		var allocated models.Money
+		for i := range items {
+			if currencies[i] != subtotal.Currency {
+				continue
+			}
+			items[i].Amount = subtotal.Total
+			if subtotal.Subtotal != 0 {
+				share := new(big.Rat).Mul(subtotal.Total.Rat(), amounts[i].Rat())
+				items[i].Amount = models.RoundMoney(share.Quo(share, subtotal.Subtotal.Rat()))
+			}
+			allocated += items[i].Amount
+			last = i
+		}
+		if last >= 0 {
+			items[last].Amount += subtotal.Total - allocated
+		}
+	}
+
+	for i := range items {
+		items[i].Outstanding = max(items[i].Amount-items[i].PaidAmount, 0)
+	}
+	return items
+}
+
+// isOverdue reports whether the first expiration date of a summary, which is due until the end of the day, has passed.
+func isOverdue(summary *models.PaymentSummary, now time.Time) bool {
+	return !now.Before(summary.FirstExpiration.AddDate(0, 0, 1))
+}
+
+// paymentStatus returns the status of an item or summary from what was paid and what is left to pay of it.
+func paymentStatus(paid models.Money, outstanding models.Money, overdue bool) models.PaymentStatus {
+	switch {
+	case outstanding == 0:
+		return models.PaymentStatusPaid
+	case overdue:
+		return models.PaymentStatusOverdue
+	case paid > 0:
+		return models.PaymentStatusPartiallyPaid
+	default:
+		return models.PaymentStatusPending
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentStorageStub is an in-test IPaymentStorage sharing the summaries of a cardStorageStub. Recording a payment
+// adds its allocations to the paid amounts of the summary, as the storages do. A concurrent payment is recorded
+// first the next time a payment is recorded, which is then rejected as the storages reject a stale one.
+type paymentStorageStub struct {
+	storage.IPaymentStorage
+	cards      *cardStorageStub
+	payments   []models.Payment
+	concurrent *models.Payment
+}
+
+func (s *cardStorageStub) GetPaymentSummary(_ context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	for i := range s.summaries {
+		if s.summaries[i].Month == month && s.summaries[i].Year == year {
+			return &s.summaries[i], nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *paymentStorageStub) RecordPayment(ctx context.Context, payment models.Payment) (*models.Payment, error) {
+	if concurrent := s.concurrent; concurrent != nil {
+		s.concurrent = nil
+		if _, err := s.RecordPayment(ctx, *concurrent); err != nil {
+			return nil, err
+		}
+		return nil, storage.ErrAlreadyExists
+	}
+	summary, err := s.cards.GetPaymentSummary(context.Background(), payment.CardNumber, payment.Month, payment.Year)
+	if err != nil {
+		return nil, err
+	}
+	for _, allocation := range payment.Allocations {
+		for i := range summary.Quotas {
+			if allocation.PurchaseDate == nil && summary.Quotas[i].PaymentVoucher == allocation.PaymentVoucher && summary.Quotas[i].Number == allocation.QuotaNumber {
+				summary.Quotas[i].PaidAmount += allocation.Amount
+			}
+		}
+		for i := range summary.SinglePayments {
+			if allocation.PurchaseDate != nil && summary.SinglePayments[i].PaymentVoucher == allocation.PaymentVoucher {
+				summary.SinglePayments[i].PaidAmount += allocation.Amount
+			}
+		}
+	}
+	s.payments = append(s.payments, payment)
+	return &payment, nil
+}
+
+func (s *paymentStorageStub) GetPayments(_ context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	payments := []models.Payment{}
+	for _, payment := range s.payments {
+		if payment.Month == month && payment.Year == year {
+			payments = append(payments, payment)
+		}
+	}
+	return &payments, nil
+}
+
+func (s *paymentStorageStub) GetPaymentSummaries(_ context.Context, cardNumber string) (*[]models.PaymentSummary, error) {
+	if cardNumber != "1234567812345678" {
+		return nil, storage.ErrNotFound
+	}
+	summaries := append([]models.PaymentSummary{}, s.cards.summaries...)
+	return &summaries, nil
+}
+
+func newPaymentServiceAt(now time.Time, cards *cardStorageStub) (PaymentService, *paymentStorageStub) {
+	payments := &paymentStorageStub{cards: cards}
+	service := NewPaymentService(cards, payments).(*paymentService)
+	service.now = func() time.Time { return now }
+	return service, payments
+}
+
+// billedSummary bills two quotas of 100 and a purchase of 50 dollars at 10.25 pesos each, due on April 10.
+func billedSummary() models.PaymentSummary {
+	return models.PaymentSummary{
+		Code:            "SUMMARY-1234567812345678-2025-03",
+		Month:           3,
+		Year:            2025,
+		FirstExpiration: time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC),
+		TotalPrice:      models.MustParseMoney("712.50"),
+		Subtotals: []models.CurrencySubtotal{
+			{Currency: models.BaseCurrency, Subtotal: models.MustParseMoney("200"), ExchangeRate: models.MustParseMoney("1"), Total: models.MustParseMoney("200")},
+			{Currency: "USD", Subtotal: models.MustParseMoney("50"), ExchangeRate: models.MustParseMoney("10.25"), Total: models.MustParseMoney("512.50")},
+		},
+		Quotas: []models.DueQuota{
+			{Quota: models.Quota{Number: 2, Price: models.MustParseMoney("100")}, PaymentVoucher: "V-JAN", NumberOfQuotas: 3},
+			{Quota: models.Quota{Number: 1, Price: models.MustParseMoney("100")}, PaymentVoucher: "V-FEB", NumberOfQuotas: 3},
+		},
+		SinglePayments: []models.PurchaseSinglePayment{{Purchase: models.Purchase{
+			PaymentVoucher: "V-USD", FinalAmount: models.MustParseMoney("50"), Currency: "USD",
+			PurchaseDate: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+		}}},
+	}
+}
+
+func TestRecordPaymentAllocatesQuotasFirst(t *testing.T) {
+	ctx := context.Background()
+	cards := &cardStorageStub{summaries: []models.PaymentSummary{billedSummary()}}
+	service, _ := newPaymentServiceAt(time.Date(2025, time.April, 5, 12, 0, 0, 0, time.UTC), cards)
+
+	payment, err := service.RecordPayment(ctx, "1234567812345678", 3, 2025, models.PaymentRequest{Amount: models.MustParseMoney("150")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "PAYMENT-1234567812345678-2025-03-1", payment.Code)
+	assert.Equal(t, time.Date(2025, time.April, 5, 12, 0, 0, 0, time.UTC), payment.PaymentDate)
+	assert.Equal(t, []models.PaymentAllocation{
+		{PaymentVoucher: "V-JAN", QuotaNumber: 2, Amount: models.MustParseMoney("100"), BilledAmount: models.MustParseMoney("100")},
+		{PaymentVoucher: "V-FEB", QuotaNumber: 1, Amount: models.MustParseMoney("50"), BilledAmount: models.MustParseMoney("100")},
+	}, payment.Allocations)
+
+	// The rest goes to what is left of the second quota and then to the purchase, converted to pesos
+	purchaseDate := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	payment, err = service.RecordPayment(ctx, "1234567812345678", 3, 2025, models.PaymentRequest{Amount: models.MustParseMoney("562.50"), PaymentDate: "2025-04-08T09:30:00-03:00"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "PAYMENT-1234567812345678-2025-03-2", payment.Code)
+	assert.Equal(t, time.Date(2025, time.April, 8, 12, 30, 0, 0, time.UTC), payment.PaymentDate)
+	assert.Equal(t, []models.PaymentAllocation{
+		{PaymentVoucher: "V-FEB", QuotaNumber: 1, Amount: models.MustParseMoney("50"), BilledAmount: models.MustParseMoney("100")},
+		{PaymentVoucher: "V-USD", PurchaseDate: &purchaseDate, Amount: models.MustParseMoney("512.50"), BilledAmount: models.MustParseMoney("512.50")},
+	}, payment.Allocations)
+}
+
+func TestRecordPaymentRetriesAfterConcurrentPayments(t *testing.T) {
+	ctx := context.Background()
+	cards := &cardStorageStub{summaries: []models.PaymentSummary{billedSummary()}}
+	service, payments := newPaymentServiceAt(time.Date(2025, time.April, 5, 12, 0, 0, 0, time.UTC), cards)
+	payments.concurrent = &models.Payment{Code: "PAYMENT-1234567812345678-2025-03-1", Month: 3, Year: 2025, Allocations: []models.PaymentAllocation{
+		{PaymentVoucher: "V-JAN", QuotaNumber: 2, Amount: models.MustParseMoney("100")},
+	}}
+
+	payment, err := service.RecordPayment(ctx, "1234567812345678", 3, 2025, models.PaymentRequest{Amount: models.MustParseMoney("100")})
+
+	// The payment is numbered and allocated again after the one that got in first
+	require.NoError(t, err)
+	assert.Equal(t, "PAYMENT-1234567812345678-2025-03-2", payment.Code)
+	assert.Equal(t, []models.PaymentAllocation{
+		{PaymentVoucher: "V-FEB", QuotaNumber: 1, Amount: models.MustParseMoney("100"), BilledAmount: models.MustParseMoney("100")},
+	}, payment.Allocations)
+	assert.Len(t, payments.payments, 2)
+
+	// What is left to pay is checked again as well
+	payments.concurrent = &models.Payment{Code: "PAYMENT-1234567812345678-2025-03-3", Month: 3, Year: 2025, Allocations: []models.PaymentAllocation{
+		{PaymentVoucher: "V-USD", PurchaseDate: &cards.summaries[0].SinglePayments[0].PurchaseDate, Amount: models.MustParseMoney("512.50")},
+	}}
+	_, err = service.RecordPayment(ctx, "1234567812345678", 3, 2025, models.PaymentRequest{Amount: models.MustParseMoney("512.50")})
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestRecordPaymentRejectsInvalidRequests(t *testing.T) {
+	ctx := context.Background()
+	cards := &cardStorageStub{summaries: []models.PaymentSummary{billedSummary()}}
+	service, payments := newPaymentServiceAt(time.Date(2025, time.April, 5, 0, 0, 0, 0, time.UTC), cards)
+
+	for name, request := range map[string]models.PaymentRequest{
+		"zero amount":         {},
+		"negative amount":     {Amount: models.MustParseMoney("-10")},
+		"more than left":      {Amount: models.MustParseMoney("712.51")},
+		"invalid date format": {Amount: models.MustParseMoney("10"), PaymentDate: "05/04/2025"},
+	} {
+		_, err := service.RecordPayment(ctx, "1234567812345678", 3, 2025, request)
+		assert.True(t, errors.Is(err, ErrValidation), name)
+	}
+	_, err := service.RecordPayment(ctx, "1234567812345678", 13, 2025, models.PaymentRequest{Amount: models.MustParseMoney("10")})
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	_, err = service.RecordPayment(ctx, "1234567812345678", 4, 2025, models.PaymentRequest{Amount: models.MustParseMoney("10")})
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+	assert.Empty(t, payments.payments)
+}
+
+func TestGetCardBalanceStatuses(t *testing.T) {
+	ctx := context.Background()
+	paid := billedSummary()
+	paid.Month, paid.FirstExpiration = 2, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	paid.Quotas[0].PaidAmount = models.MustParseMoney("100")
+	paid.Quotas[1].PaidAmount = models.MustParseMoney("100")
+	paid.SinglePayments[0].PaidAmount = models.MustParseMoney("512.50")
+	partial := billedSummary()
+	partial.Quotas[0].PaidAmount = models.MustParseMoney("100")
+	partial.Quotas[1].PaidAmount = models.MustParseMoney("40")
+	cards := &cardStorageStub{summaries: []models.PaymentSummary{paid, partial}}
+
+	// On the first expiration date nothing is overdue yet
+	service, _ := newPaymentServiceAt(time.Date(2025, time.April, 10, 23, 0, 0, 0, time.UTC), cards)
+	balance, err := service.GetCardBalance(ctx, "1234567812345678")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("572.50"), balance.Outstanding)
+	assert.Zero(t, balance.Overdue)
+	require.Len(t, balance.Summaries, 1, "fully paid summaries are left out")
+	summary := balance.Summaries[0]
+	assert.Equal(t, models.PaymentStatusPartiallyPaid, summary.Status)
+	assert.Equal(t, models.MustParseMoney("140"), summary.PaidAmount)
+	require.Len(t, summary.Items, 2, "fully paid items are left out")
+	assert.Equal(t, models.PaymentStatusPartiallyPaid, summary.Items[0].Status)
+	assert.Equal(t, models.MustParseMoney("60"), summary.Items[0].Outstanding)
+	assert.Equal(t, models.PaymentStatusPending, summary.Items[1].Status)
+	assert.Equal(t, models.MustParseMoney("512.50"), summary.Items[1].Amount)
+
+	service, _ = newPaymentServiceAt(time.Date(2025, time.April, 11, 0, 0, 0, 0, time.UTC), cards)
+	balance, err = service.GetCardBalance(ctx, "1234567812345678")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("572.50"), balance.Overdue)
+	assert.Equal(t, models.PaymentStatusOverdue, balance.Summaries[0].Status)
+	assert.Equal(t, models.PaymentStatusOverdue, balance.Summaries[0].Items[1].Status)
+
+	_, err = service.GetCardBalance(ctx, "0000000000000000")
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+}
+
+func TestBilledItemsAddUpToTheTotalPrice(t *testing.T) {
+	summary := billedSummary()
+	summary.SinglePayments = append(summary.SinglePayments, models.PurchaseSinglePayment{Purchase: models.Purchase{
+		PaymentVoucher: "V-USD-2", FinalAmount: models.MustParseMoney("0.01"), Currency: "USD",
+	}})
+	summary.Subtotals[1] = models.CurrencySubtotal{Currency: "USD", Subtotal: models.MustParseMoney("50.01"), ExchangeRate: models.MustParseMoney("10.25"), Total: models.MustParseMoney("512.60")}
+
+	var total models.Money
+	for _, item := range billedItems(&summary) {
+		total += item.Amount
+	}
+	assert.Equal(t, models.MustParseMoney("712.60"), total)
+
+	// Summaries without subtotals bill every item in pesos
+	summary.Subtotals = nil
+	items := billedItems(&summary)
+	assert.Equal(t, models.MustParseMoney("50"), items[2].Amount)
+}
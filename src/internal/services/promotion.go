@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
@@ -20,19 +22,56 @@ type PromotionService interface {
 	// - *[]models.Financing: A slice of available financing promotions.
 	// - *[]models.Discount: A slice of available discount promotions.
 	// - error: An error if the operation fails, otherwise nil.
-	GetAvailablePromotionsByStoreAndDateRange(cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error)
+	GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error)
 
 	// GetMostUsedPromotion retrieves the most used promotion.
 	// Returns:
 	// - interface{}: The most used promotion.
 	// - error: An error if the operation fails, otherwise nil.
-	GetMostUsedPromotion() (interface{}, error)
+	GetMostUsedPromotion(ctx context.Context) (interface{}, error)
+
+	// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+	// Parameters:
+	// - code: The unique identifier of the promotion.
+	// Returns:
+	// - *models.PromotionDetail: The promotion and its current status.
+	// - error: storage.ErrNotFound if no promotion has that code, otherwise nil.
+	GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error)
+
+	// GetBankPromotions retrieves the promotions of a bank with the given status.
+	// Parameters:
+	// - bankCuit: The CUIT of the bank.
+	// - status: One of active, deleted or expired. Empty means active.
+	// Returns:
+	// - *[]models.Financing: The financing promotions of the bank with that status.
+	// - *[]models.Discount: The discount promotions of the bank with that status.
+	// - error: A validation error for an unknown status, storage.ErrNotFound if the bank does not exist, otherwise nil.
+	GetBankPromotions(ctx context.Context, bankCuit string, status string) (*[]models.Financing, *[]models.Discount, error)
+
+	// UpdatePromotion edits the title, comments and rates of a promotion.
+	// Parameters:
+	// - code: The unique identifier of the promotion.
+	// - update: The changes to apply. Only the fields present are changed.
+	// Returns:
+	// - *models.PromotionDetail: The updated promotion.
+	// - error: A validation error if a change is out of range, does not apply to the promotion type or does not suit the
+	//   interest model of a financing, storage.ErrNotFound if no promotion has that code, otherwise nil.
+	UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) (*models.PromotionDetail, error)
+
+	// RestorePromotion undoes the logical delete of a promotion.
+	// Parameters:
+	// - code: The unique identifier of the promotion.
+	// Returns:
+	// - *models.PromotionDetail: The restored promotion.
+	// - error: A validation error if the promotion is not deleted, storage.ErrNotFound if no promotion has that code, otherwise nil.
+	RestorePromotion(ctx context.Context, code string) (*models.PromotionDetail, error)
 }
 
 // promotionService is a concrete implementation of the PromotionService interface.
 // It uses a repository (IPromotionStorage) to perform data operations.
 type promotionService struct {
 	repo storage.IPromotionStorage
+	now  func() time.Time
 }
 
 // NewPromotionService creates and initializes a new PromotionService instance.
@@ -43,15 +82,133 @@ type promotionService struct {
 func NewPromotionService(repo storage.IPromotionStorage) PromotionService {
 	return &promotionService{
 		repo: repo,
+		now:  time.Now,
 	}
 }
 
 // GetAvailablePromotionsByStoreAndDateRange retrieves available promotions by store and date range.
-func (s *promotionService) GetAvailablePromotionsByStoreAndDateRange(cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
-	return s.repo.GetAvailablePromotionsByStoreAndDateRange(cuit, startDate, endDate)
+func (s *promotionService) GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	return s.repo.GetAvailablePromotionsByStoreAndDateRange(ctx, cuit, startDate, endDate)
 }
 
 // GetMostUsedPromotion retrieves the most used promotion.
-func (s *promotionService) GetMostUsedPromotion() (interface{}, error) {
-	return s.repo.GetMostUsedPromotion()
+func (s *promotionService) GetMostUsedPromotion(ctx context.Context) (interface{}, error) {
+	return s.repo.GetMostUsedPromotion(ctx)
+}
+
+// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+func (s *promotionService) GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error) {
+	detail, err := s.repo.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	detail.Status = promotionStatus(detail, s.now())
+	return detail, nil
+}
+
+// GetBankPromotions retrieves the promotions of a bank with the given status.
+func (s *promotionService) GetBankPromotions(ctx context.Context, bankCuit string, status string) (*[]models.Financing, *[]models.Discount, error) {
+	promotionStatus := models.PromotionStatus(status)
+	switch promotionStatus {
+	case "":
+		promotionStatus = models.PromotionStatusActive
+	case models.PromotionStatusActive, models.PromotionStatusDeleted, models.PromotionStatusExpired:
+	default:
+		return nil, nil, validationError("unknown promotion status '%s' (expected active, deleted or expired)", status)
+	}
+	return s.repo.GetBankPromotions(ctx, bankCuit, promotionStatus, s.now())
+}
+
+// UpdatePromotion edits the title, comments and rates of a promotion.
+func (s *promotionService) UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) (*models.PromotionDetail, error) {
+	detail, err := s.repo.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePromotionUpdate(detail.Type, update); err != nil {
+		return nil, err
+	}
+	if detail.Financing != nil && (update.Interest != nil || update.NumberOfQuotas != nil) {
+		financing := *detail.Financing
+		if update.Interest != nil {
+			financing.Interest = *update.Interest
+		}
+		if update.NumberOfQuotas != nil {
+			financing.NumberOfQuotas = *update.NumberOfQuotas
+		}
+		if err := validateFinancingTerms(financing.InterestModel, financing.Interest, financing.NumberOfQuotas); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdatePromotion(ctx, code, update); err != nil {
+		return nil, err
+	}
+	return s.GetPromotionByCode(ctx, code)
+}
+
+// RestorePromotion undoes the logical delete of a promotion.
+func (s *promotionService) RestorePromotion(ctx context.Context, code string) (*models.PromotionDetail, error) {
+	detail, err := s.repo.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !detail.IsDeleted {
+		return nil, validationError("promotion %s is not deleted", code)
+	}
+
+	if err := s.repo.RestorePromotion(ctx, code); err != nil {
+		return nil, err
+	}
+	return s.GetPromotionByCode(ctx, code)
+}
+
+// promotionStatus returns the status of a promotion on the given date.
+func promotionStatus(detail *models.PromotionDetail, date time.Time) models.PromotionStatus {
+	if detail.IsDeleted {
+		return models.PromotionStatusDeleted
+	}
+
+	validityEndDate := ""
+	if detail.Discount != nil {
+		validityEndDate = detail.Discount.ValidityEndDate
+	} else if detail.Financing != nil {
+		validityEndDate = detail.Financing.ValidityEndDate
+	}
+	if endDate, err := time.Parse(time.RFC3339, validityEndDate); err == nil && endDate.Before(date) {
+		return models.PromotionStatusExpired
+	}
+	return models.PromotionStatusActive
+}
+
+// validatePromotionUpdate checks that the changes apply to the promotion type and are within range.
+func validatePromotionUpdate(promotionType string, update models.PromotionUpdate) error {
+	if update.PromotionTitle != nil && strings.TrimSpace(*update.PromotionTitle) == "" {
+		return validationError("promotion title cannot be empty")
+	}
+
+	discountChanges := update.DiscountPercentage != nil || update.PriceCap != nil || update.OnlyCash != nil
+	financingChanges := update.NumberOfQuotas != nil || update.Interest != nil
+	if promotionType == models.PromotionTypeDiscount && financingChanges {
+		return validationError("number of quotas and interest only apply to financing promotions")
+	}
+	if promotionType == models.PromotionTypeFinancing && discountChanges {
+		return validationError("discount percentage, price cap and cash-only only apply to discount promotions")
+	}
+
+	if update.DiscountPercentage != nil && (*update.DiscountPercentage <= 0 || *update.DiscountPercentage > models.HundredPercent) {
+		return validationError("discount percentage must be greater than 0 and at most 100, got %s", *update.DiscountPercentage)
+	}
+	if update.PriceCap != nil && *update.PriceCap < 0 {
+		return validationError("price cap cannot be negative, got %s", *update.PriceCap)
+	}
+	if update.NumberOfQuotas != nil {
+		if err := validateNumberOfQuotas(*update.NumberOfQuotas); err != nil {
+			return err
+		}
+	}
+	if update.Interest != nil && *update.Interest < 0 {
+		return validationError("interest cannot be negative, got %s", *update.Interest)
+	}
+	return nil
 }
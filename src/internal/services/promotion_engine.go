@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// PromotionEngine defines the interface for evaluating the promotions that apply to a purchase.
+// A purchase benefits from at most one promotion: the engine picks the most convenient eligible one,
+// computes the final amount and records the promotion code on the purchase.
+type PromotionEngine interface {
+	// ApplyToSinglePurchase applies the best eligible discount to a single-payment purchase.
+	// Every discount of the bank for the store that is valid on the purchase date is eligible;
+	// the discounted amount is limited by the price cap of the promotion. Promotions are in pesos,
+	// so purchases in other currencies pay their full amount.
+	// Parameters:
+	// - bankCuit: The CUIT of the bank that issued the card used for the purchase.
+	// - purchase: The purchase to update with the final amount and the applied promotion code.
+	// Returns:
+	// - error: An error if the promotions could not be retrieved, otherwise nil.
+	ApplyToSinglePurchase(ctx context.Context, bankCuit string, purchase *models.PurchaseSinglePayment) error
+
+	// ApplyToMonthlyPurchase applies the best eligible promotion to an installment purchase and computes its quotas.
+	// A financing offering the purchase's number of quotas takes precedence and replaces its interest and interest model,
+	// the cheapest one being chosen when several apply; otherwise the best discount that is not restricted to cash
+	// payments is applied before interest.
+	// Parameters:
+	// - bankCuit: The CUIT of the bank that issued the card used for the purchase.
+	// - purchase: The purchase to update with the interest, interest model, final amount, quotas and applied promotion code.
+	// Returns:
+	// - error: A validation error if the interest does not suit the interest model, an error if the promotions
+	//   could not be retrieved, otherwise nil.
+	ApplyToMonthlyPurchase(ctx context.Context, bankCuit string, purchase *models.PurchaseMonthlyPayment) error
+
+	// QuotePurchase quotes a purchase in pesos under every promotion of the bank for the store on the purchase date.
+	// A single payment is quoted without a promotion and with each discount, and installments with each financing.
+	// When the request has a number of quotas no financing offers, installments are also quoted at its interest and
+	// interest model, without a promotion and with each discount not restricted to cash payments. Within each of
+	// these payment terms, the quote the engine would apply to a registered purchase is marked as applied.
+	// Parameters:
+	// - bankCuit: The CUIT of the bank that issued the card.
+	// - request: The store, the amount and the optional installments without a financing promotion.
+	// - purchaseDate: The date of the purchase.
+	// Returns:
+	// - []models.PurchaseQuote: The quotes, single payments first, then by number of quotas and final amount.
+	// - error: A validation error if the installments of the request are invalid, an error if the promotions
+	//   could not be retrieved, otherwise nil.
+	QuotePurchase(ctx context.Context, bankCuit string, request models.PromotionSimulationRequest, purchaseDate time.Time) ([]models.PurchaseQuote, error)
+}
+
+// promotionEngine is a concrete implementation of the PromotionEngine interface.
+// It uses a repository (IPromotionStorage) to look up the applicable promotions.
+type promotionEngine struct {
+	repo storage.IPromotionStorage
+}
+
+// NewPromotionEngine creates and initializes a new PromotionEngine instance.
+// Parameters:
+// - repo: An IPromotionStorage repository interface for interacting with the data layer.
+// Returns:
+// - PromotionEngine: A new instance of the engine struct implementing the PromotionEngine interface.
+func NewPromotionEngine(repo storage.IPromotionStorage) PromotionEngine {
+	return &promotionEngine{
+		repo: repo,
+	}
+}
+
+// ApplyToSinglePurchase applies the best eligible discount to a single-payment purchase.
+func (e *promotionEngine) ApplyToSinglePurchase(ctx context.Context, bankCuit string, purchase *models.PurchaseSinglePayment) error {
+	purchase.FinalAmount = purchase.Amount
+	purchase.PromotionCode = ""
+	if purchase.Currency.OrBase() != models.BaseCurrency {
+		return nil
+	}
+
+	_, discounts, err := e.repo.GetApplicablePromotions(ctx, bankCuit, purchase.CuitStore, purchase.PurchaseDate)
+	if err != nil {
+		return fmt.Errorf("could not retrieve applicable promotions: %w", err)
+	}
+
+	applyToSinglePurchase(*discounts, purchase)
+	return nil
+}
+
+// ApplyToMonthlyPurchase applies the best eligible promotion to an installment purchase.
+func (e *promotionEngine) ApplyToMonthlyPurchase(ctx context.Context, bankCuit string, purchase *models.PurchaseMonthlyPayment) error {
+	financings, discounts, err := e.repo.GetApplicablePromotions(ctx, bankCuit, purchase.CuitStore, purchase.PurchaseDate)
+	if err != nil {
+		return fmt.Errorf("could not retrieve applicable promotions: %w", err)
+	}
+
+	return applyToMonthlyPurchase(*financings, *discounts, purchase)
+}
+
+// QuotePurchase quotes a purchase under every applicable promotion.
+func (e *promotionEngine) QuotePurchase(ctx context.Context, bankCuit string, request models.PromotionSimulationRequest, purchaseDate time.Time) ([]models.PurchaseQuote, error) {
+	financings, discounts, err := e.repo.GetApplicablePromotions(ctx, bankCuit, request.CuitStore, purchaseDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve applicable promotions: %w", err)
+	}
+	purchase := models.Purchase{CuitStore: request.CuitStore, Amount: request.Amount, PurchaseDate: purchaseDate}
+
+	single := models.PurchaseSinglePayment{Purchase: purchase}
+	applyToSinglePurchase(*discounts, &single)
+	quotes := []models.PurchaseQuote{{PurchaseType: models.SinglePayment, NumberOfQuotas: 1, FinalAmount: request.Amount, Applied: single.PromotionCode == ""}}
+	for _, discount := range *discounts {
+		if amount := discountAmount(discount, request.Amount); amount > 0 {
+			quote := promotionQuote(models.SinglePayment, models.PromotionTypeDiscount, discount.Promotion, amount, single.PromotionCode)
+			quote.NumberOfQuotas = 1
+			quote.FinalAmount = request.Amount - amount
+			quotes = append(quotes, quote)
+		}
+	}
+
+	offered := make(map[int]bool)
+	for _, financing := range *financings {
+		plan, err := NewInstallmentPlan(financing.InterestModel, request.Amount, financing.Interest, financing.NumberOfQuotas, purchaseDate)
+		if err != nil {
+			continue
+		}
+		offered[financing.NumberOfQuotas] = true
+		best, _ := bestFinancing(*financings, request.Amount, financing.NumberOfQuotas, purchaseDate)
+		quotes = append(quotes, planQuote(promotionQuote(models.MonthlyPayments, models.PromotionTypeFinancing, financing.Promotion, 0, best.Code), plan))
+	}
+
+	if request.NumberOfQuotas > 0 && !offered[request.NumberOfQuotas] {
+		monthly := models.PurchaseMonthlyPayment{Purchase: purchase, Interest: request.Interest, InterestModel: request.InterestModel, NumberOfQuotas: request.NumberOfQuotas}
+		if err := applyToMonthlyPurchase(nil, *discounts, &monthly); err != nil {
+			return nil, err
+		}
+		plan, err := NewInstallmentPlan(request.InterestModel, request.Amount, request.Interest, request.NumberOfQuotas, purchaseDate)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, planQuote(models.PurchaseQuote{PurchaseType: models.MonthlyPayments, Applied: monthly.PromotionCode == ""}, plan))
+
+		for _, discount := range *discounts {
+			amount := discountAmount(discount, request.Amount)
+			if discount.OnlyCash || amount <= 0 {
+				continue
+			}
+			plan, err := NewInstallmentPlan(request.InterestModel, request.Amount-amount, request.Interest, request.NumberOfQuotas, purchaseDate)
+			if err != nil {
+				return nil, err
+			}
+			quotes = append(quotes, planQuote(promotionQuote(models.MonthlyPayments, models.PromotionTypeDiscount, discount.Promotion, amount, monthly.PromotionCode), plan))
+		}
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		a, b := quotes[i], quotes[j]
+		if a.PurchaseType != b.PurchaseType {
+			return a.PurchaseType < b.PurchaseType
+		}
+		if a.NumberOfQuotas != b.NumberOfQuotas {
+			return a.NumberOfQuotas < b.NumberOfQuotas
+		}
+		if a.FinalAmount != b.FinalAmount {
+			return a.FinalAmount < b.FinalAmount
+		}
+		return a.PromotionCode < b.PromotionCode
+	})
+	return quotes, nil
+}
+
+// promotionQuote starts the quote of a promotion, applied when its code is the one the engine chose.
+func promotionQuote(purchaseType models.PurchaseType, promotionType string, promotion models.Promotion, discount models.Money, appliedCode string) models.PurchaseQuote {
+	return models.PurchaseQuote{
+		PurchaseType:   purchaseType,
+		PromotionType:  promotionType,
+		PromotionCode:  promotion.Code,
+		PromotionTitle: promotion.PromotionTitle,
+		Discount:       discount,
+		Applied:        promotion.Code == appliedCode,
+	}
+}
+
+// planQuote completes the quote of installments with their plan.
+func planQuote(quote models.PurchaseQuote, plan *models.InstallmentPlan) models.PurchaseQuote {
+	quote.NumberOfQuotas = plan.NumberOfQuotas
+	quote.FinalAmount = plan.FinalAmount
+	quote.Plan = plan
+	return quote
+}
+
+// applyToSinglePurchase applies the best of the given discounts to a single-payment purchase.
+func applyToSinglePurchase(discounts []models.Discount, purchase *models.PurchaseSinglePayment) {
+	if best, amount := bestDiscount(discounts, purchase.Amount, true); best != nil {
+		purchase.FinalAmount = purchase.Amount - amount
+		purchase.PromotionCode = best.Code
+	}
+}
+
+// applyToMonthlyPurchase applies the best of the given promotions to an installment purchase and computes its quotas.
+func applyToMonthlyPurchase(financings []models.Financing, discounts []models.Discount, purchase *models.PurchaseMonthlyPayment) error {
+	var err error
+	purchase.PromotionCode = ""
+	best, plan := bestFinancing(financings, purchase.Amount, purchase.NumberOfQuotas, purchase.PurchaseDate)
+	if best != nil {
+		purchase.Interest = best.Interest
+		purchase.PromotionCode = best.Code
+	} else {
+		baseAmount := purchase.Amount
+		if best, amount := bestDiscount(discounts, purchase.Amount, false); best != nil {
+			baseAmount -= amount
+			purchase.PromotionCode = best.Code
+		}
+		if plan, err = NewInstallmentPlan(purchase.InterestModel, baseAmount, purchase.Interest, purchase.NumberOfQuotas, purchase.PurchaseDate); err != nil {
+			return err
+		}
+	}
+
+	purchase.InterestModel = plan.InterestModel
+	purchase.FinalAmount = plan.FinalAmount
+	purchase.Quota = PlanQuotas(plan)
+	return nil
+}
+
+// discountAmount returns the amount a discount takes off the given purchase amount, limited by its price cap.
+// A price cap of zero means the discount is not capped.
+func discountAmount(discount models.Discount, amount models.Money) models.Money {
+	discounted := amount.Percent(discount.DiscountPercentage)
+	if discount.PriceCap > 0 && discounted > discount.PriceCap {
+		discounted = discount.PriceCap
+	}
+	if discounted > amount {
+		discounted = amount
+	}
+	return discounted
+}
+
+// bestDiscount selects the discount that takes the most off the given amount.
+// Cash-only discounts are considered only when allowCashOnly is true. Ties are broken by promotion code
+// so that the choice does not depend on the order in which the storage returns the promotions.
+func bestDiscount(discounts []models.Discount, amount models.Money, allowCashOnly bool) (*models.Discount, models.Money) {
+	var (
+		best       *models.Discount
+		bestAmount models.Money
+	)
+	for i := range discounts {
+		discount := &discounts[i]
+		if discount.OnlyCash && !allowCashOnly {
+			continue
+		}
+		discounted := discountAmount(*discount, amount)
+		if discounted <= 0 {
+			continue
+		}
+		if best == nil || discounted > bestAmount || (discounted == bestAmount && discount.Code < best.Code) {
+			best, bestAmount = discount, discounted
+		}
+	}
+	return best, bestAmount
+}
+
+// bestFinancing selects the financing whose installment plan costs the least among those offering the given number of quotas.
+// Plans are compared by final amount, since the interest rates of different interest models are not comparable;
+// financings whose terms do not make a valid plan are skipped. Ties are broken by promotion code.
+func bestFinancing(financings []models.Financing, amount models.Money, numberOfQuotas int, purchaseDate time.Time) (*models.Financing, *models.InstallmentPlan) {
+	var (
+		best     *models.Financing
+		bestPlan *models.InstallmentPlan
+	)
+	for i := range financings {
+		financing := &financings[i]
+		if financing.NumberOfQuotas != numberOfQuotas {
+			continue
+		}
+		plan, err := NewInstallmentPlan(financing.InterestModel, amount, financing.Interest, numberOfQuotas, purchaseDate)
+		if err != nil {
+			continue
+		}
+		if best == nil || plan.FinalAmount < bestPlan.FinalAmount || (plan.FinalAmount == bestPlan.FinalAmount && financing.Code < best.Code) {
+			best, bestPlan = financing, plan
+		}
+	}
+	return best, bestPlan
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// promotionStorageStub is an in-test IPromotionStorage returning a fixed set of applicable promotions.
+type promotionStorageStub struct {
+	storage.IPromotionStorage
+	financings []models.Financing
+	discounts  []models.Discount
+	err        error
+
+	bankCuit  string
+	storeCuit string
+
+	details map[string]*models.PromotionDetail
+	status  models.PromotionStatus
+}
+
+func (s *promotionStorageStub) GetApplicablePromotions(_ context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	s.bankCuit, s.storeCuit = bankCuit, storeCuit
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	financings := append([]models.Financing{}, s.financings...)
+	discounts := append([]models.Discount{}, s.discounts...)
+	return &financings, &discounts, nil
+}
+
+func discount(code string, percentage string, priceCap string, onlyCash bool) models.Discount {
+	return models.Discount{
+		Promotion:          models.Promotion{Code: code},
+		DiscountPercentage: models.MustParsePercentage(percentage),
+		PriceCap:           models.MustParseMoney(priceCap),
+		OnlyCash:           onlyCash,
+	}
+}
+
+func financing(code string, numberOfQuotas int, interest string) models.Financing {
+	return models.Financing{
+		Promotion:      models.Promotion{Code: code},
+		NumberOfQuotas: numberOfQuotas,
+		Interest:       models.MustParsePercentage(interest),
+	}
+}
+
+func TestApplyToSinglePurchase(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		discounts     []models.Discount
+		amount        string
+		expectedFinal string
+		expectedCode  string
+	}{
+		{"no promotions", nil, "1000", "1000", ""},
+		{"uncapped discount", []models.Discount{discount("D10", "10", "0", false)}, "1000", "900", "D10"},
+		{"price cap limits the discount", []models.Discount{discount("D50", "50", "100", false)}, "1000", "900", "D50"},
+		{"cash-only discounts apply", []models.Discount{discount("CASH", "15", "0", true)}, "1000", "850", "CASH"},
+		{"discount is rounded to the cent", []models.Discount{discount("D12", "12.5", "0", false)}, "99.99", "87.49", "D12"},
+		{
+			"best discount after caps wins",
+			[]models.Discount{discount("CAPPED", "50", "100", false), discount("PLAIN", "20", "0", false)},
+			"1000", "800", "PLAIN",
+		},
+		{
+			"ties are broken by code",
+			[]models.Discount{discount("B", "10", "0", false), discount("A", "10", "0", false)},
+			"1000", "900", "A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewPromotionEngine(&promotionStorageStub{discounts: tt.discounts})
+			purchase := models.PurchaseSinglePayment{Purchase: models.Purchase{Amount: models.MustParseMoney(tt.amount)}}
+
+			err := engine.ApplyToSinglePurchase(ctx, "30-12345678-9", &purchase)
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.MustParseMoney(tt.expectedFinal), purchase.FinalAmount)
+			assert.Equal(t, tt.expectedCode, purchase.PromotionCode)
+		})
+	}
+}
+
+func TestApplyToMonthlyPurchase(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name             string
+		financings       []models.Financing
+		discounts        []models.Discount
+		interest         string
+		expectedInterest string
+		expectedFinal    string
+		expectedCode     string
+	}{
+		{"no promotions keeps the requested interest", nil, nil, "10", "10", "1100", ""},
+		{
+			"financing matching the quotas replaces the interest",
+			[]models.Financing{financing("F6", 6, "0"), financing("F3", 3, "5"), financing("F3B", 3, "8")},
+			nil, "10", "5", "1050", "F3",
+		},
+		{
+			"financing takes precedence over discounts",
+			[]models.Financing{financing("F3", 3, "0")},
+			[]models.Discount{discount("D10", "10", "0", false)},
+			"10", "0", "1000", "F3",
+		},
+		{
+			"discount applies before interest when no financing matches",
+			[]models.Financing{financing("F6", 6, "0")},
+			[]models.Discount{discount("D10", "10", "0", false)},
+			"10", "10", "990", "D10",
+		},
+		{
+			"cash-only discounts do not apply",
+			nil,
+			[]models.Discount{discount("CASH", "30", "0", true)},
+			"0", "0", "1000", "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewPromotionEngine(&promotionStorageStub{financings: tt.financings, discounts: tt.discounts})
+			purchase := models.PurchaseMonthlyPayment{
+				Purchase:       models.Purchase{Amount: models.MustParseMoney("1000")},
+				Interest:       models.MustParsePercentage(tt.interest),
+				NumberOfQuotas: 3,
+			}
+
+			err := engine.ApplyToMonthlyPurchase(ctx, "30-12345678-9", &purchase)
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.MustParsePercentage(tt.expectedInterest), purchase.Interest)
+			assert.Equal(t, models.MustParseMoney(tt.expectedFinal), purchase.FinalAmount)
+			assert.Equal(t, tt.expectedCode, purchase.PromotionCode)
+		})
+	}
+}
+
+func TestApplyToMonthlyPurchaseComparesInterestModels(t *testing.T) {
+	ctx := context.Background()
+	flat := financing("FLAT", 3, "5")
+	french := financing("FRENCH", 3, "36")
+	french.InterestModel = models.InterestModelFrench
+	invalid := financing("BROKEN", 3, "1")
+	invalid.InterestModel = models.InterestModelZero
+	engine := NewPromotionEngine(&promotionStorageStub{financings: []models.Financing{flat, french, invalid}})
+	purchase := models.PurchaseMonthlyPayment{
+		Purchase:       models.Purchase{Amount: models.MustParseMoney("1000"), PurchaseDate: time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC)},
+		NumberOfQuotas: 3,
+	}
+
+	err := engine.ApplyToMonthlyPurchase(ctx, "30-12345678-9", &purchase)
+
+	// 5% flat costs 1050.00, less than 36% French at 1060.59, despite the higher rate of the latter
+	assert.NoError(t, err)
+	assert.Equal(t, "FLAT", purchase.PromotionCode)
+	assert.Equal(t, models.InterestModelFlat, purchase.InterestModel)
+	assert.Equal(t, models.MustParseMoney("1050"), purchase.FinalAmount)
+	assert.Equal(t, []models.Quota{
+		{Number: 1, Price: models.MustParseMoney("350"), Month: "04", Year: "2025"},
+		{Number: 2, Price: models.MustParseMoney("350"), Month: "05", Year: "2025"},
+		{Number: 3, Price: models.MustParseMoney("350"), Month: "06", Year: "2025"},
+	}, purchase.Quota)
+
+	french.Interest = models.MustParsePercentage("12")
+	engine = NewPromotionEngine(&promotionStorageStub{financings: []models.Financing{flat, french}})
+
+	err = engine.ApplyToMonthlyPurchase(ctx, "30-12345678-9", &purchase)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FRENCH", purchase.PromotionCode)
+	assert.Equal(t, models.InterestModelFrench, purchase.InterestModel)
+	assert.Equal(t, models.MustParsePercentage("12"), purchase.Interest)
+	assert.Equal(t, models.MustParseMoney("1020.07"), purchase.FinalAmount)
+}
+
+func TestApplyToSinglePurchaseInForeignCurrency(t *testing.T) {
+	stub := &promotionStorageStub{discounts: []models.Discount{discount("D10", "10", "0", false)}}
+	engine := NewPromotionEngine(stub)
+	purchase := models.PurchaseSinglePayment{Purchase: models.Purchase{
+		CuitStore: "30-98765432-1",
+		Amount:    models.MustParseMoney("100"),
+		Currency:  models.CurrencyUSD,
+	}}
+
+	err := engine.ApplyToSinglePurchase(context.Background(), "30-12345678-9", &purchase)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("100"), purchase.FinalAmount)
+	assert.Empty(t, purchase.PromotionCode)
+	assert.Empty(t, stub.storeCuit, "promotions should not be looked up")
+}
+
+func TestApplyPromotionsStorageError(t *testing.T) {
+	ctx := context.Background()
+	storageErr := errors.New("connection lost")
+	engine := NewPromotionEngine(&promotionStorageStub{err: storageErr})
+
+	err := engine.ApplyToSinglePurchase(ctx, "30-12345678-9", &models.PurchaseSinglePayment{})
+
+	assert.ErrorIs(t, err, storageErr)
+}
+
+func TestQuotePurchase(t *testing.T) {
+	ctx := context.Background()
+	purchaseDate := time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC)
+	french := financing("FIN3B", 3, "36")
+	french.InterestModel = models.InterestModelFrench
+	zero := financing("FIN6", 6, "0")
+	zero.InterestModel = models.InterestModelZero
+	stub := &promotionStorageStub{
+		financings: []models.Financing{french, financing("FIN3", 3, "5"), zero},
+		discounts:  []models.Discount{discount("D10", "10", "0", false), discount("CASH15", "15", "100", true)},
+	}
+	engine := NewPromotionEngine(stub)
+	request := models.PromotionSimulationRequest{
+		CuitStore:      "30-98765432-1",
+		Amount:         models.MustParseMoney("1000"),
+		NumberOfQuotas: 12,
+		Interest:       models.MustParsePercentage("10"),
+	}
+
+	quotes, err := engine.QuotePurchase(ctx, "30-12345678-9", request, purchaseDate)
+
+	type quote struct {
+		purchaseType   models.PurchaseType
+		code           string
+		numberOfQuotas int
+		finalAmount    string
+		applied        bool
+	}
+	var got []quote
+	for _, q := range quotes {
+		got = append(got, quote{q.PurchaseType, q.PromotionCode, q.NumberOfQuotas, q.FinalAmount.String(), q.Applied})
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, "30-98765432-1", stub.storeCuit)
+	assert.Equal(t, []quote{
+		{models.SinglePayment, "CASH15", 1, "900.00", true},
+		{models.SinglePayment, "D10", 1, "900.00", false},
+		{models.SinglePayment, "", 1, "1000.00", false},
+		{models.MonthlyPayments, "FIN3", 3, "1050.00", true},
+		{models.MonthlyPayments, "FIN3B", 3, "1060.59", false},
+		{models.MonthlyPayments, "FIN6", 6, "1000.00", true},
+		{models.MonthlyPayments, "D10", 12, "990.00", true},
+		{models.MonthlyPayments, "", 12, "1100.00", false},
+	}, got)
+	assert.Equal(t, models.PromotionTypeDiscount, quotes[0].PromotionType)
+	assert.Equal(t, models.MustParseMoney("100"), quotes[0].Discount)
+	assert.Nil(t, quotes[0].Plan, "single payments have no quota schedule")
+	assert.Equal(t, models.PromotionTypeFinancing, quotes[4].PromotionType)
+	assert.Equal(t, models.InterestModelFrench, quotes[4].Plan.InterestModel)
+	assert.Len(t, quotes[6].Plan.Quotas, 12)
+	assert.Equal(t, models.MustParseMoney("900"), quotes[6].Plan.Amount, "the discount is applied before interest")
+}
+
+func TestQuotePurchaseInstallmentsOfferedByFinancing(t *testing.T) {
+	engine := NewPromotionEngine(&promotionStorageStub{financings: []models.Financing{financing("FIN3", 3, "5")}})
+	request := models.PromotionSimulationRequest{Amount: models.MustParseMoney("1000"), NumberOfQuotas: 3, Interest: models.MustParsePercentage("20")}
+
+	quotes, err := engine.QuotePurchase(context.Background(), "30-12345678-9", request, time.Now())
+
+	assert.NoError(t, err)
+	assert.Len(t, quotes, 2, "installments offered by a financing are not quoted at the interest of the request")
+	assert.Equal(t, "FIN3", quotes[1].PromotionCode)
+}
+
+func TestQuotePurchaseErrors(t *testing.T) {
+	ctx := context.Background()
+	request := models.PromotionSimulationRequest{
+		Amount:         models.MustParseMoney("1000"),
+		NumberOfQuotas: 3,
+		Interest:       models.MustParsePercentage("5"),
+		InterestModel:  models.InterestModelZero,
+	}
+
+	_, err := NewPromotionEngine(&promotionStorageStub{}).QuotePurchase(ctx, "30-12345678-9", request, time.Now())
+	assert.ErrorIs(t, err, ErrValidation)
+
+	storageErr := errors.New("connection lost")
+	_, err = NewPromotionEngine(&promotionStorageStub{err: storageErr}).QuotePurchase(ctx, "30-12345678-9", request, time.Now())
+	assert.ErrorIs(t, err, storageErr)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *promotionStorageStub) GetPromotionByCode(_ context.Context, code string) (*models.PromotionDetail, error) {
+	detail, ok := s.details[code]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	copied := *detail
+	return &copied, nil
+}
+
+func (s *promotionStorageStub) GetBankPromotions(_ context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	s.bankCuit, s.status = bankCuit, status
+	financings := append([]models.Financing{}, s.financings...)
+	discounts := append([]models.Discount{}, s.discounts...)
+	return &financings, &discounts, nil
+}
+
+func (s *promotionStorageStub) UpdatePromotion(_ context.Context, code string, update models.PromotionUpdate) error {
+	detail, ok := s.details[code]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if update.PromotionTitle != nil && detail.Discount != nil {
+		detail.Discount.PromotionTitle = *update.PromotionTitle
+	}
+	if update.DiscountPercentage != nil {
+		detail.Discount.DiscountPercentage = *update.DiscountPercentage
+	}
+	if update.Interest != nil {
+		detail.Financing.Interest = *update.Interest
+	}
+	return nil
+}
+
+func (s *promotionStorageStub) RestorePromotion(_ context.Context, code string) error {
+	detail, ok := s.details[code]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	detail.IsDeleted = false
+	return nil
+}
+
+func newPromotionServiceAt(now time.Time, repo *promotionStorageStub) PromotionService {
+	service := NewPromotionService(repo).(*promotionService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func promotionsStub() *promotionStorageStub {
+	d10 := discount("D10", "10", "0", false)
+	d10.ValidityEndDate = "2025-06-30T00:00:00Z"
+	f6 := financing("F6", 6, "5")
+	f6.ValidityEndDate = "2025-01-31T00:00:00Z"
+	z3 := financing("Z3", 3, "0")
+	z3.InterestModel = models.InterestModelZero
+	return &promotionStorageStub{details: map[string]*models.PromotionDetail{
+		"D10": {Type: models.PromotionTypeDiscount, Discount: &d10},
+		"F6":  {Type: models.PromotionTypeFinancing, Financing: &f6},
+		"Z3":  {Type: models.PromotionTypeFinancing, Financing: &z3},
+		"DEL": {Type: models.PromotionTypeDiscount, IsDeleted: true, Discount: &d10},
+	}}
+}
+
+func TestGetPromotionByCode(t *testing.T) {
+	ctx := context.Background()
+	service := newPromotionServiceAt(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), promotionsStub())
+
+	tests := []struct {
+		code     string
+		expected models.PromotionStatus
+	}{
+		{"D10", models.PromotionStatusActive},
+		{"F6", models.PromotionStatusExpired},
+		{"DEL", models.PromotionStatusDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			detail, err := service.GetPromotionByCode(ctx, tt.code)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, detail.Status)
+		})
+	}
+
+	_, err := service.GetPromotionByCode(ctx, "MISSING")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGetBankPromotions(t *testing.T) {
+	ctx := context.Background()
+	repo := promotionsStub()
+	service := newPromotionServiceAt(time.Now(), repo)
+
+	_, _, err := service.GetBankPromotions(ctx, "30-12345678-9", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PromotionStatusActive, repo.status)
+
+	_, _, err = service.GetBankPromotions(ctx, "30-12345678-9", "expired")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PromotionStatusExpired, repo.status)
+
+	_, _, err = service.GetBankPromotions(ctx, "30-12345678-9", "archived")
+	assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+}
+
+func TestUpdatePromotion(t *testing.T) {
+	ctx := context.Background()
+	service := newPromotionServiceAt(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), promotionsStub())
+	title, percentage, interest, quotas := "Autumn sale", models.MustParsePercentage("20"), models.MustParsePercentage("3.5"), 0
+
+	detail, err := service.UpdatePromotion(ctx, "D10", models.PromotionUpdate{PromotionTitle: &title, DiscountPercentage: &percentage})
+	assert.NoError(t, err)
+	assert.Equal(t, "Autumn sale", detail.Discount.PromotionTitle)
+	assert.Equal(t, percentage, detail.Discount.DiscountPercentage)
+
+	detail, err = service.UpdatePromotion(ctx, "F6", models.PromotionUpdate{Interest: &interest})
+	assert.NoError(t, err)
+	assert.Equal(t, interest, detail.Financing.Interest)
+
+	_, err = service.UpdatePromotion(ctx, "MISSING", models.PromotionUpdate{PromotionTitle: &title})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	empty, tooHigh, tooManyQuotas := " ", models.MustParsePercentage("120"), maxQuotas+1
+	invalid := []struct {
+		name   string
+		code   string
+		update models.PromotionUpdate
+	}{
+		{"empty title", "D10", models.PromotionUpdate{PromotionTitle: &empty}},
+		{"discount above 100", "D10", models.PromotionUpdate{DiscountPercentage: &tooHigh}},
+		{"financing fields on a discount", "D10", models.PromotionUpdate{Interest: &interest}},
+		{"discount fields on a financing", "F6", models.PromotionUpdate{DiscountPercentage: &percentage}},
+		{"no quotas", "F6", models.PromotionUpdate{NumberOfQuotas: &quotas}},
+		{"too many quotas", "F6", models.PromotionUpdate{NumberOfQuotas: &tooManyQuotas}},
+		{"interest on a zero-interest financing", "Z3", models.PromotionUpdate{Interest: &interest}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UpdatePromotion(ctx, tt.code, tt.update)
+
+			assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+		})
+	}
+}
+
+func TestRestorePromotion(t *testing.T) {
+	ctx := context.Background()
+	service := newPromotionServiceAt(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), promotionsStub())
+
+	detail, err := service.RestorePromotion(ctx, "DEL")
+	assert.NoError(t, err)
+	assert.False(t, detail.IsDeleted)
+	assert.Equal(t, models.PromotionStatusActive, detail.Status)
+
+	_, err = service.RestorePromotion(ctx, "DEL")
+	assert.True(t, errors.Is(err, ErrValidation), "expected a validation error, got %v", err)
+
+	_, err = service.RestorePromotion(ctx, "MISSING")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+)
+
+// maxQuotas is the largest number of monthly quotas an installment purchase or financing can have.
+const maxQuotas = 60
+
+// GenerateQuotas builds the monthly quota schedule of an installment purchase.
+// The first quota is due in the month of the purchase and each following quota one month later,
+// rolling over to the next year when needed. The final amount is split in cents so that the quota
+// prices always add up exactly to it; the last quota absorbs the remainder.
+// Parameters:
+// - finalAmount: The amount to be paid, interest included.
+// - numberOfQuotas: The number of monthly installments, between 1 and maxQuotas.
+// - purchaseDate: The date the purchase was made.
+// Returns:
+// - []models.Quota: The quota schedule, ordered by quota number, or nil if the number of quotas is out of range.
+func GenerateQuotas(finalAmount models.Money, numberOfQuotas int, purchaseDate time.Time) []models.Quota {
+	if validateNumberOfQuotas(numberOfQuotas) != nil {
+		return nil
+	}
+
+	quotas := quotaMonths(numberOfQuotas, purchaseDate)
+	for i, price := range splitEvenly(finalAmount, numberOfQuotas) {
+		quotas[i].Price = price
+	}
+	return quotas
+}
+
+// quotaMonths numbers the quotas of a schedule and sets the month each one is due, leaving their prices empty.
+func quotaMonths(numberOfQuotas int, purchaseDate time.Time) []models.Quota {
+	// Anchor on the first day of the month so that the month arithmetic never overflows (e.g. Jan 31 + 1 month)
+	firstDueMonth := time.Date(purchaseDate.Year(), purchaseDate.Month(), 1, 0, 0, 0, 0, purchaseDate.Location())
+
+	quotas := make([]models.Quota, 0, numberOfQuotas)
+	for i := 0; i < numberOfQuotas; i++ {
+		dueMonth := firstDueMonth.AddDate(0, i, 0)
+		quotas = append(quotas, models.Quota{
+			Number: i + 1,
+			Month:  fmt.Sprintf("%02d", int(dueMonth.Month())),
+			Year:   fmt.Sprintf("%d", dueMonth.Year()),
+		})
+	}
+	return quotas
+}
+
+// splitEvenly splits an amount in cents into equal parts that add up exactly to it; the last part absorbs the remainder.
+func splitEvenly(amount models.Money, parts int) []models.Money {
+	totalCents := amount.Cents()
+	partCents := totalCents / int64(parts)
+	remainderCents := totalCents - partCents*int64(parts)
+
+	split := make([]models.Money, parts)
+	for i := range split {
+		split[i] = models.Money(partCents)
+	}
+	split[parts-1] += models.Money(remainderCents)
+	return split
+}
+
+// validateNumberOfQuotas checks that a number of quotas is between 1 and maxQuotas.
+func validateNumberOfQuotas(numberOfQuotas int) error {
+	if numberOfQuotas < 1 || numberOfQuotas > maxQuotas {
+		return validationError("number of quotas must be between 1 and %d, got %d", maxQuotas, numberOfQuotas)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateQuotas(t *testing.T) {
+	tests := []struct {
+		name           string
+		finalAmount    models.Money
+		numberOfQuotas int
+		purchaseDate   time.Time
+		expected       []models.Quota
+	}{
+		{
+			name:           "even split",
+			finalAmount:    models.MustParseMoney("330.00"),
+			numberOfQuotas: 3,
+			purchaseDate:   time.Date(2024, time.October, 14, 1, 0, 0, 0, time.UTC),
+			expected: []models.Quota{
+				{Number: 1, Price: models.MustParseMoney("110.00"), Month: "10", Year: "2024"},
+				{Number: 2, Price: models.MustParseMoney("110.00"), Month: "11", Year: "2024"},
+				{Number: 3, Price: models.MustParseMoney("110.00"), Month: "12", Year: "2024"},
+			},
+		},
+		{
+			name:           "remainder goes to the last quota",
+			finalAmount:    models.MustParseMoney("100.00"),
+			numberOfQuotas: 3,
+			purchaseDate:   time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+			expected: []models.Quota{
+				{Number: 1, Price: models.MustParseMoney("33.33"), Month: "03", Year: "2024"},
+				{Number: 2, Price: models.MustParseMoney("33.33"), Month: "04", Year: "2024"},
+				{Number: 3, Price: models.MustParseMoney("33.34"), Month: "05", Year: "2024"},
+			},
+		},
+		{
+			name:           "year rollover from the end of a long month",
+			finalAmount:    models.MustParseMoney("400.00"),
+			numberOfQuotas: 4,
+			purchaseDate:   time.Date(2024, time.November, 30, 23, 0, 0, 0, time.UTC),
+			expected: []models.Quota{
+				{Number: 1, Price: models.MustParseMoney("100.00"), Month: "11", Year: "2024"},
+				{Number: 2, Price: models.MustParseMoney("100.00"), Month: "12", Year: "2024"},
+				{Number: 3, Price: models.MustParseMoney("100.00"), Month: "01", Year: "2025"},
+				{Number: 4, Price: models.MustParseMoney("100.00"), Month: "02", Year: "2025"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GenerateQuotas(tt.finalAmount, tt.numberOfQuotas, tt.purchaseDate))
+		})
+	}
+}
+
+func TestGenerateQuotasSumsToFinalAmount(t *testing.T) {
+	quotas := GenerateQuotas(models.MustParseMoney("1234.57"), 12, time.Now())
+
+	var total models.Money
+	for _, quota := range quotas {
+		total += quota.Price
+	}
+
+	assert.Len(t, quotas, 12)
+	assert.Equal(t, models.MustParseMoney("1234.57"), total)
+}
+
+func TestGenerateQuotasWithoutQuotas(t *testing.T) {
+	assert.Empty(t, GenerateQuotas(models.MustParseMoney("100"), 0, time.Now()))
+	assert.Empty(t, GenerateQuotas(models.MustParseMoney("100"), maxQuotas+1, time.Now()))
+}
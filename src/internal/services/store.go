@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 )
@@ -16,7 +18,7 @@ type StoreService interface {
 	// Returns:
 	// - models.StoreDTO: The store with the highest revenue.
 	// - error: An error if the operation fails, otherwise nil.
-	GetStoreWithHighestRevenueByMonth(month int, year int) (models.StoreDTO, error)
+	GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error)
 }
 
 // storeService is a concrete implementation of the StoreService interface.
@@ -37,6 +39,6 @@ func NewStoreService(repo storage.IStoreStorage) StoreService {
 }
 
 // GetStoreWithHighestRevenueByMonth retrieves the store with the highest revenue in a specific month and year.
-func (s *storeService) GetStoreWithHighestRevenueByMonth(month int, year int) (models.StoreDTO, error) {
-	return s.repo.GetStoreWithHighestRevenueByMonth(month, year)
+func (s *storeService) GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error) {
+	return s.repo.GetStoreWithHighestRevenueByMonth(ctx, month, year)
 }
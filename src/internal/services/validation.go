@@ -0,0 +1,27 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrValidation is returned (wrapped) when the input of a service operation breaks a business rule.
+// Handlers use it to answer with a client error instead of an internal one.
+var ErrValidation = errors.New("validation failed")
+
+// cuitPattern matches a CUIT in its usual XX-XXXXXXXX-X representation.
+var cuitPattern = regexp.MustCompile(`^\d{2}-\d{8}-\d$`)
+
+// validationError builds an error wrapping ErrValidation with a descriptive message.
+func validationError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
+
+// validateCuit checks that the given value is a well-formed CUIT.
+func validateCuit(field string, cuit string) error {
+	if !cuitPattern.MatchString(cuit) {
+		return validationError("%s '%s' is not a valid CUIT (expected XX-XXXXXXXX-X)", field, cuit)
+	}
+	return nil
+}
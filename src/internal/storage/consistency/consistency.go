@@ -0,0 +1,195 @@
+/*
+ * Payment Registration System - Consistency
+ * --------------------------------------------------
+ * This file compares snapshots of the SQL and NoSQL stores. Records are matched by their natural key
+ * (bank CUIT, card number, payment voucher, promotion code) and their fields are compared as strings,
+ * formatted the same way by both sources so that equal values compare equal.
+ *
+ * Created: Mar. 26, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package consistency
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+)
+
+// Compared entities, in report order.
+const (
+	EntityBank      = "bank"
+	EntityCard      = "card"
+	EntityPurchase  = "purchase"
+	EntityPromotion = "promotion"
+)
+
+var compared = []string{EntityBank, EntityCard, EntityPurchase, EntityPromotion}
+
+// Record is an entity of a store, identified by its natural key, with the fields that are compared.
+type Record struct {
+	Key    string
+	Fields map[string]string
+}
+
+// Snapshot holds the records of a store by entity.
+type Snapshot map[string][]Record
+
+// Source reads a snapshot of a store.
+type Source interface {
+	Snapshot(ctx context.Context) (Snapshot, error)
+}
+
+/*
+ * Check
+ * --------------------------------------------------
+ * Reads a snapshot of both stores and compares them.
+ *
+ * Params:
+ * - ctx (context.Context): Context of the reads.
+ * - sql (Source): The SQL store, the reference of the comparison.
+ * - noSQL (Source): The NoSQL store.
+ *
+ * Returns:
+ * - *models.ConsistencyReport: The differences between the stores.
+ * - error: If a snapshot cannot be read.
+ */
+func Check(ctx context.Context, sql Source, noSQL Source) (*models.ConsistencyReport, error) {
+	sqlSnapshot, err := sql.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the SQL store: %w", err)
+	}
+	noSQLSnapshot, err := noSQL.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the NoSQL store: %w", err)
+	}
+	return Compare(sqlSnapshot, noSQLSnapshot), nil
+}
+
+// Compare reports the records of every entity missing from the NoSQL snapshot, only in the NoSQL snapshot,
+// or whose fields differ. Differences are sorted by entity, key and field.
+func Compare(sql Snapshot, noSQL Snapshot) *models.ConsistencyReport {
+	report := &models.ConsistencyReport{CheckedAt: time.Now(), Entities: []models.EntityConsistency{}, Differences: []models.Difference{}}
+	for _, entity := range compared {
+		summary, differences := compareEntity(entity, sql[entity], noSQL[entity])
+		report.Entities = append(report.Entities, summary)
+		report.Differences = append(report.Differences, differences...)
+	}
+	report.Consistent = len(report.Differences) == 0
+	return report
+}
+
+func compareEntity(entity string, sql []Record, noSQL []Record) (models.EntityConsistency, []models.Difference) {
+	summary := models.EntityConsistency{Entity: entity, SQL: len(sql), NoSQL: len(noSQL)}
+	differences := []models.Difference{}
+
+	byKey := map[string]Record{}
+	for _, record := range noSQL {
+		byKey[record.Key] = record
+	}
+	for _, record := range sortedByKey(sql) {
+		other, ok := byKey[record.Key]
+		if !ok {
+			summary.Missing++
+			differences = append(differences, models.Difference{Entity: entity, Key: record.Key, Kind: models.DifferenceMissing})
+			continue
+		}
+		delete(byKey, record.Key)
+
+		mismatches := compareFields(entity, record, other)
+		if len(mismatches) > 0 {
+			summary.Mismatched++
+			differences = append(differences, mismatches...)
+		}
+	}
+
+	extra := []Record{}
+	for _, record := range byKey {
+		extra = append(extra, record)
+	}
+	for _, record := range sortedByKey(extra) {
+		summary.Extra++
+		differences = append(differences, models.Difference{Entity: entity, Key: record.Key, Kind: models.DifferenceExtra})
+	}
+	return summary, differences
+}
+
+// compareFields reports the fields of a record that differ between the stores, a field missing from one of
+// them being compared as an empty value.
+func compareFields(entity string, sql Record, noSQL Record) []models.Difference {
+	fields := map[string]bool{}
+	for field := range sql.Fields {
+		fields[field] = true
+	}
+	for field := range noSQL.Fields {
+		fields[field] = true
+	}
+	names := []string{}
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	differences := []models.Difference{}
+	for _, field := range names {
+		if sql.Fields[field] != noSQL.Fields[field] {
+			differences = append(differences, models.Difference{
+				Entity:     entity,
+				Key:        sql.Key,
+				Kind:       models.DifferenceMismatch,
+				Field:      field,
+				SQLValue:   sql.Fields[field],
+				NoSQLValue: noSQL.Fields[field],
+			})
+		}
+	}
+	return differences
+}
+
+func sortedByKey(records []Record) []Record {
+	sorted := append([]Record{}, records...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	return sorted
+}
+
+// WriteCSV writes the differences of a report as CSV, with a header row.
+func WriteCSV(w io.Writer, report *models.ConsistencyReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"entity", "key", "kind", "field", "sql_value", "no_sql_value"}); err != nil {
+		return err
+	}
+	for _, difference := range report.Differences {
+		row := []string{difference.Entity, difference.Key, difference.Kind, difference.Field, difference.SQLValue, difference.NoSQLValue}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ------------ Field formats shared by the sources ------------	//
+
+// purchaseKey identifies a purchase. Payment vouchers are not unique in seed data, so the key also holds the card
+// and the purchase date.
+func purchaseKey(paymentVoucher string, cardNumber string, purchaseDate time.Time) string {
+	return paymentVoucher + "/" + cardNumber + "/" + formatTime(purchaseDate)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// cardStatus maps the status of a card stored before statuses existed to active, as the mappers do.
+func cardStatus(status string) string {
+	if status == "" {
+		return string(models.CardStatusActive)
+	}
+	return status
+}
@@ -0,0 +1,145 @@
+package consistency
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+// fixedSource returns the same snapshot on every read.
+type fixedSource Snapshot
+
+func (f fixedSource) Snapshot(_ context.Context) (Snapshot, error) {
+	return Snapshot(f), nil
+}
+
+func TestCompareEqualSnapshots(t *testing.T) {
+	snapshot := Snapshot{
+		EntityBank: {bankRecord("30-00000001-0", "Banco Contrato", "Av. Corrientes 1000", "0800-111-1111")},
+		EntityCard: {cardRecord("4000000000000001", "ANA PEREZ", "", time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC), "30-00000001-0", "20-70000001-3")},
+	}
+
+	report, err := Check(context.Background(), fixedSource(snapshot), fixedSource(snapshot))
+
+	require.NoError(t, err)
+	assert.True(t, report.Consistent)
+	assert.Empty(t, report.Differences)
+	assert.Equal(t, []models.EntityConsistency{
+		{Entity: EntityBank, SQL: 1, NoSQL: 1},
+		{Entity: EntityCard, SQL: 1, NoSQL: 1},
+		{Entity: EntityPurchase},
+		{Entity: EntityPromotion},
+	}, report.Entities)
+}
+
+func TestCompareReportsMissingExtraAndMismatchedRecords(t *testing.T) {
+	sql := Snapshot{EntityBank: {
+		bankRecord("30-00000002-0", "Banco Paridad", "Av. Santa Fe 2000", "0800-222-2222"),
+		bankRecord("30-00000001-0", "Banco Contrato", "Av. Corrientes 1000", "0800-111-1111"),
+	}}
+	noSQL := Snapshot{EntityBank: {
+		bankRecord("30-00000001-0", "Banco Contrato", "Av. Corrientes 1500", "0800-111-1112"),
+		bankRecord("30-00000003-0", "Banco Huérfano", "Av. Rivadavia 3000", "0800-333-3333"),
+	}}
+
+	report := Compare(sql, noSQL)
+
+	assert.False(t, report.Consistent)
+	assert.Equal(t, models.EntityConsistency{Entity: EntityBank, SQL: 2, NoSQL: 2, Missing: 1, Extra: 1, Mismatched: 1}, report.Entities[0])
+	assert.Equal(t, []models.Difference{
+		{Entity: EntityBank, Key: "30-00000001-0", Kind: models.DifferenceMismatch, Field: "address", SQLValue: "Av. Corrientes 1000", NoSQLValue: "Av. Corrientes 1500"},
+		{Entity: EntityBank, Key: "30-00000001-0", Kind: models.DifferenceMismatch, Field: "telephone", SQLValue: "0800-111-1111", NoSQLValue: "0800-111-1112"},
+		{Entity: EntityBank, Key: "30-00000002-0", Kind: models.DifferenceMissing},
+		{Entity: EntityBank, Key: "30-00000003-0", Kind: models.DifferenceExtra},
+	}, report.Differences)
+}
+
+func TestCompareFieldMissingFromOneStore(t *testing.T) {
+	purchaseDate := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+	sql := Snapshot{EntityPurchase: {purchaseRecord("4000000000000002", "FIN-2025", "Tienda Sur", "30-11111111-1", 800, 800, "ARS", purchaseDate, "", 2)}}
+	noSQL := Snapshot{EntityPurchase: {purchaseRecord("4000000000000002", "FIN-2025", "Tienda Sur", "30-11111111-1", 800, 800, "ARS", purchaseDate, "", 0)}}
+
+	report := Compare(sql, noSQL)
+
+	assert.Equal(t, []models.Difference{
+		{Entity: EntityPurchase, Key: "FIN-2025/4000000000000002/2025-03-08T00:00:00Z", Kind: models.DifferenceMismatch, Field: "kind", SQLValue: "monthly", NoSQLValue: "single"},
+		{Entity: EntityPurchase, Key: "FIN-2025/4000000000000002/2025-03-08T00:00:00Z", Kind: models.DifferenceMismatch, Field: "number_of_quotas", SQLValue: "2"},
+	}, report.Differences)
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &models.ConsistencyReport{Differences: []models.Difference{
+		{Entity: EntityCard, Key: "4000000000000001", Kind: models.DifferenceMismatch, Field: "cardholder_name", SQLValue: "ANA PEREZ", NoSQLValue: "PEREZ, ANA"},
+		{Entity: EntityPromotion, Key: "DISC-2025", Kind: models.DifferenceMissing},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, report))
+
+	assert.Equal(t, "entity,key,kind,field,sql_value,no_sql_value\n"+
+		"card,4000000000000001,mismatch,cardholder_name,ANA PEREZ,\"PEREZ, ANA\"\n"+
+		"promotion,DISC-2025,missing,,,\n", out.String())
+}
+
+func TestSQLSourceReadsEveryEntity(t *testing.T) {
+	db, err := relational.NewSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+	require.NoError(t, storagetest.DefaultFixture().Load(storagetest.Storages{
+		Banks:         relational_repository.NewBankRelationalRepository(db),
+		Cards:         relational_repository.NewCardRelationalRepository(db),
+		Promotions:    relational_repository.NewPromotionRelationRepository(db),
+		Stores:        relational_repository.NewStoreRelationalRepository(db),
+		Customers:     relational_repository.NewCustomerRelationalRepository(db),
+		ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(db),
+	}))
+
+	snapshot, err := NewSQLSource(db).Snapshot(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, snapshot[EntityBank], 2)
+	assert.Len(t, snapshot[EntityCard], 3)
+	assert.Len(t, snapshot[EntityPurchase], 5)
+	assert.Len(t, snapshot[EntityPromotion], 4)
+
+	cards := map[string]Record{}
+	for _, card := range snapshot[EntityCard] {
+		cards[card.Key] = card
+	}
+	assert.Equal(t, map[string]string{
+		"cardholder_name": "ANA PEREZ",
+		"status":          string(models.CardStatusActive),
+		"expiration_date": "2030-01-31T00:00:00Z",
+		"bank_cuit":       storagetest.BankCuit,
+		"customer_cuit":   storagetest.CustomerCuit,
+	}, cards[storagetest.CardNumber].Fields)
+
+	purchases := map[string]Record{}
+	for _, purchase := range snapshot[EntityPurchase] {
+		purchases[purchase.Key] = purchase
+	}
+	monthly, ok := purchases["FIN-2025/"+storagetest.CardNumber+"/2025-04-10T00:00:00Z"]
+	require.True(t, ok, "purchases are keyed by voucher, card and date")
+	assert.Equal(t, "monthly", monthly.Fields["kind"])
+	assert.Equal(t, "3", monthly.Fields["number_of_quotas"])
+	assert.Equal(t, "300.00", monthly.Fields["final_amount"])
+
+	// A snapshot is consistent with itself
+	assert.True(t, Compare(snapshot, snapshot).Consistent)
+}
@@ -0,0 +1,279 @@
+/*
+ * Payment Registration System - Consistency Sources
+ * --------------------------------------------------
+ * This file reads the snapshots of the SQL and NoSQL stores compared by the consistency check.
+ * Both sources map their entities to the same keys and fields.
+ *
+ * Created: Mar. 26, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"gorm.io/gorm"
+)
+
+// ------------ Records ------------	//
+
+func bankRecord(cuit string, name string, address string, telephone string) Record {
+	return Record{Key: cuit, Fields: map[string]string{"name": name, "address": address, "telephone": telephone}}
+}
+
+func cardRecord(number string, holder string, status string, expiration time.Time, bankCuit string, customerCuit string) Record {
+	return Record{Key: number, Fields: map[string]string{
+		"cardholder_name": holder,
+		"status":          cardStatus(status),
+		"expiration_date": formatTime(expiration),
+		"bank_cuit":       bankCuit,
+		"customer_cuit":   customerCuit,
+	}}
+}
+
+// purchaseRecord maps a purchase, numberOfQuotas is zero for single payments.
+func purchaseRecord(cardNumber string, voucher string, store string, cuitStore string, amount models.Money, finalAmount models.Money, currency string, purchaseDate time.Time, promotionCode string, numberOfQuotas int) Record {
+	fields := map[string]string{
+		"kind":           "single",
+		"store":          store,
+		"cuit_store":     cuitStore,
+		"amount":         amount.String(),
+		"final_amount":   finalAmount.String(),
+		"currency":       string(models.Currency(currency).OrBase()),
+		"promotion_code": promotionCode,
+	}
+	if numberOfQuotas > 0 {
+		fields["kind"] = "monthly"
+		fields["number_of_quotas"] = strconv.Itoa(numberOfQuotas)
+	}
+	return Record{Key: purchaseKey(voucher, cardNumber, purchaseDate), Fields: fields}
+}
+
+func promotionRecord(kind string, code string, title string, cuitStore string, bankCuit string, start time.Time, end time.Time, isDeleted bool) Record {
+	return Record{Key: code, Fields: map[string]string{
+		"kind":                kind,
+		"title":               title,
+		"cuit_store":          cuitStore,
+		"bank_cuit":           bankCuit,
+		"validity_start_date": formatTime(start),
+		"validity_end_date":   formatTime(end),
+		"is_deleted":          strconv.FormatBool(isDeleted),
+	}}
+}
+
+func discountFields(record Record, percentage models.Percentage, priceCap models.Money, onlyCash bool) Record {
+	record.Fields["discount_percentage"] = percentage.String()
+	record.Fields["price_cap"] = priceCap.String()
+	record.Fields["only_cash"] = strconv.FormatBool(onlyCash)
+	return record
+}
+
+func financingFields(record Record, numberOfQuotas int, interest models.Percentage) Record {
+	record.Fields["number_of_quotas"] = strconv.Itoa(numberOfQuotas)
+	record.Fields["interest"] = interest.String()
+	return record
+}
+
+// interestModelField adds the interest model of an installment purchase or a financing, flat when it was not stored.
+func interestModelField(record Record, interestModel string) Record {
+	record.Fields["interest_model"] = string(models.InterestModel(interestModel).OrFlat())
+	return record
+}
+
+// ------------ SQL ------------	//
+
+type sqlSource struct {
+	db *gorm.DB
+}
+
+// NewSQLSource creates a Source reading the tables of a relational database.
+func NewSQLSource(db *gorm.DB) Source {
+	return sqlSource{db: db}
+}
+
+func (s sqlSource) Snapshot(ctx context.Context) (Snapshot, error) {
+	db := s.db.WithContext(ctx)
+	snapshot := Snapshot{}
+
+	var banks []entities.BankEntitySQL
+	if err := db.Find(&banks).Error; err != nil {
+		return nil, fmt.Errorf("error reading banks: %w", err)
+	}
+	for _, bank := range banks {
+		snapshot[EntityBank] = append(snapshot[EntityBank], bankRecord(bank.Cuit, bank.Name, bank.Address, bank.Telephone))
+	}
+
+	var customers []entities.CustomerEntitySQL
+	if err := db.Select("id", "cuit").Find(&customers).Error; err != nil {
+		return nil, fmt.Errorf("error reading customers: %w", err)
+	}
+	customerCuits := map[uint]string{}
+	for _, customer := range customers {
+		customerCuits[customer.ID] = customer.Cuit
+	}
+
+	var cards []entities.CardEntitySQL
+	if err := db.Preload("Bank").Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("error reading cards: %w", err)
+	}
+	cardNumbers := map[uint]string{}
+	for _, card := range cards {
+		cardNumbers[card.ID] = card.Number
+		snapshot[EntityCard] = append(snapshot[EntityCard], cardRecord(
+			card.Number, card.CardholderNameInCard, card.Status, card.ExpirationDate, card.Bank.Cuit, customerCuits[card.CustomerID],
+		))
+	}
+
+	var singlePayments []entities.PurchaseSinglePaymentEntitySQL
+	if err := db.Find(&singlePayments).Error; err != nil {
+		return nil, fmt.Errorf("error reading single payment purchases: %w", err)
+	}
+	for _, payment := range singlePayments {
+		purchase := payment.PurchaseEntity
+		snapshot[EntityPurchase] = append(snapshot[EntityPurchase], purchaseRecord(
+			cardNumbers[purchase.CardID], purchase.PaymentVoucher, purchase.Store, purchase.CuitStore,
+			purchase.Amount, purchase.FinalAmount, purchase.Currency, purchase.CreatedAt, purchase.PromotionCode, 0,
+		))
+	}
+
+	var monthlyPayments []entities.PurchaseMonthlyPaymentsEntitySQL
+	if err := db.Find(&monthlyPayments).Error; err != nil {
+		return nil, fmt.Errorf("error reading installment purchases: %w", err)
+	}
+	for _, payment := range monthlyPayments {
+		purchase := payment.PurchaseEntity
+		snapshot[EntityPurchase] = append(snapshot[EntityPurchase], interestModelField(purchaseRecord(
+			cardNumbers[purchase.CardID], purchase.PaymentVoucher, purchase.Store, purchase.CuitStore,
+			purchase.Amount, purchase.FinalAmount, purchase.Currency, purchase.CreatedAt, purchase.PromotionCode, payment.NumberOfQuotas,
+		), payment.InterestModel))
+	}
+
+	var discounts []entities.DiscountEntitySQL
+	if err := db.Preload("Bank").Find(&discounts).Error; err != nil {
+		return nil, fmt.Errorf("error reading discounts: %w", err)
+	}
+	for _, discount := range discounts {
+		record := sqlPromotionRecord(models.PromotionTypeDiscount, &discount.PromotionEntitySQL)
+		snapshot[EntityPromotion] = append(snapshot[EntityPromotion], discountFields(record, discount.DiscountPercentage, discount.PriceCap, discount.OnlyCash))
+	}
+
+	var financings []entities.FinancingEntitySQL
+	if err := db.Preload("Bank").Find(&financings).Error; err != nil {
+		return nil, fmt.Errorf("error reading financings: %w", err)
+	}
+	for _, financing := range financings {
+		record := sqlPromotionRecord(models.PromotionTypeFinancing, &financing.PromotionEntitySQL)
+		snapshot[EntityPromotion] = append(snapshot[EntityPromotion], interestModelField(financingFields(record, financing.NumberOfQuotas, financing.Interest), financing.InterestModel))
+	}
+
+	return snapshot, nil
+}
+
+func sqlPromotionRecord(kind string, promotion *entities.PromotionEntitySQL) Record {
+	return promotionRecord(kind, promotion.Code, promotion.PromotionTitle, promotion.CuitStore, promotion.Bank.Cuit,
+		promotion.ValidityStartDate, promotion.ValidityEndDate, promotion.IsDeleted)
+}
+
+// ------------ MongoDB ------------	//
+
+type mongoSource struct {
+	db *mongo.Database
+}
+
+// NewMongoSource creates a Source reading the collections of a MongoDB database.
+func NewMongoSource(db *mongo.Database) Source {
+	return mongoSource{db: db}
+}
+
+func (s mongoSource) Snapshot(ctx context.Context) (Snapshot, error) {
+	snapshot := Snapshot{}
+
+	var banks []entities.BankEntityNonSQL
+	if err := s.findAll(ctx, "banks", &banks); err != nil {
+		return nil, err
+	}
+	bankCuits := map[bson.ObjectID]string{}
+	for _, bank := range banks {
+		bankCuits[bank.ID] = bank.Cuit
+		snapshot[EntityBank] = append(snapshot[EntityBank], bankRecord(bank.Cuit, bank.Name, bank.Address, bank.Telephone))
+	}
+
+	var cards []entities.CardEntityNonSQL
+	if err := s.findAll(ctx, "cards", &cards); err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		snapshot[EntityCard] = append(snapshot[EntityCard], cardRecord(
+			card.Number, card.CardholderNameInCard, card.Status, card.ExpirationDate, card.BankCuit, card.CustomerCuit,
+		))
+	}
+
+	var singlePayments []entities.PurchaseSinglePaymentEntityNonSQL
+	if err := s.findAll(ctx, "purchase_single_payments", &singlePayments); err != nil {
+		return nil, err
+	}
+	for _, payment := range singlePayments {
+		purchase := payment.PurchaseEntity
+		snapshot[EntityPurchase] = append(snapshot[EntityPurchase], purchaseRecord(
+			purchase.CardNumber, purchase.PaymentVoucher, purchase.Store, purchase.CuitStore,
+			purchase.Amount, purchase.FinalAmount, purchase.Currency, purchase.CreatedAt, purchase.PromotionCode, 0,
+		))
+	}
+
+	var monthlyPayments []entities.PurchaseMonthlyPaymentsEntityNonSQL
+	if err := s.findAll(ctx, "purchase_monthly_payments", &monthlyPayments); err != nil {
+		return nil, err
+	}
+	for _, payment := range monthlyPayments {
+		purchase := payment.PurchaseEntity
+		snapshot[EntityPurchase] = append(snapshot[EntityPurchase], interestModelField(purchaseRecord(
+			purchase.CardNumber, purchase.PaymentVoucher, purchase.Store, purchase.CuitStore,
+			purchase.Amount, purchase.FinalAmount, purchase.Currency, purchase.CreatedAt, purchase.PromotionCode, payment.NumberOfQuotas,
+		), payment.InterestModel))
+	}
+
+	var discounts []entities.DiscountEntityNonSQL
+	if err := s.findAll(ctx, "discounts", &discounts); err != nil {
+		return nil, err
+	}
+	for _, discount := range discounts {
+		record := mongoPromotionRecord(models.PromotionTypeDiscount, &discount.PromotionEntity, bankCuits[discount.BankID], discount.IsDeleted)
+		snapshot[EntityPromotion] = append(snapshot[EntityPromotion], discountFields(record, discount.DiscountPercentage, discount.PriceCap, discount.OnlyCash))
+	}
+
+	var financings []entities.FinancingEntityNonSQL
+	if err := s.findAll(ctx, "financings", &financings); err != nil {
+		return nil, err
+	}
+	for _, financing := range financings {
+		record := mongoPromotionRecord(models.PromotionTypeFinancing, &financing.PromotionEntity, bankCuits[financing.BankID], financing.IsDeleted)
+		snapshot[EntityPromotion] = append(snapshot[EntityPromotion], interestModelField(financingFields(record, financing.NumberOfQuotas, financing.Interest), financing.InterestModel))
+	}
+
+	return snapshot, nil
+}
+
+// findAll decodes every document of a collection into documents.
+func (s mongoSource) findAll(ctx context.Context, collection string, documents interface{}) error {
+	cursor, err := s.db.Collection(collection).Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", collection, err)
+	}
+	if err := cursor.All(ctx, documents); err != nil {
+		return fmt.Errorf("error decoding %s: %w", collection, err)
+	}
+	return nil
+}
+
+func mongoPromotionRecord(kind string, promotion *entities.PromotionEntityNonSQL, bankCuit string, isDeleted bool) Record {
+	return promotionRecord(kind, promotion.Code, promotion.PromotionTitle, promotion.CuitStore, bankCuit,
+		promotion.ValidityStartDate, promotion.ValidityEndDate, isDeleted)
+}
@@ -0,0 +1,168 @@
+package dualwrite
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type BankRepositoryDualWrite struct {
+	dual
+}
+
+// NewBankDualWriteRepository creates a new instance of BankRepositoryDualWrite
+func NewBankDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.IBankStorage {
+	return &BankRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// CreateBank registers a bank on both backends, removing it from the primary when the secondary fails.
+func (r *BankRepositoryDualWrite) CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error) {
+	return write(ctx, r.dual, "CreateBank", func(ctx context.Context, b Backend) (*models.Bank, error) {
+		return b.Banks.CreateBank(ctx, bank)
+	}, func(ctx context.Context, b Backend, created *models.Bank) error {
+		return b.Compensation.RemoveBank(ctx, created.Cuit)
+	})
+}
+
+// GetBanks retrieves all banks.
+func (r *BankRepositoryDualWrite) GetBanks(ctx context.Context) (*[]models.Bank, error) {
+	return read(ctx, r.dual, "GetBanks", func(ctx context.Context, b Backend) (*[]models.Bank, error) {
+		return b.Banks.GetBanks(ctx)
+	})
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+func (r *BankRepositoryDualWrite) GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error) {
+	return read(ctx, r.dual, "GetBankByCuit", func(ctx context.Context, b Backend) (*models.Bank, error) {
+		return b.Banks.GetBankByCuit(ctx, cuit)
+	})
+}
+
+// UpdateBank updates a bank on both backends, restoring its previous details on the primary when the secondary fails.
+func (r *BankRepositoryDualWrite) UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	previous, err := r.primary.Banks.GetBankByCuit(ctx, cuit)
+	if err != nil {
+		return nil, err
+	}
+
+	return write(ctx, r.dual, "UpdateBank", func(ctx context.Context, b Backend) (*models.Bank, error) {
+		return b.Banks.UpdateBank(ctx, cuit, bank)
+	}, func(ctx context.Context, b Backend, _ *models.Bank) error {
+		_, err := b.Banks.UpdateBank(ctx, cuit, *previous)
+		return err
+	})
+}
+
+// AddFinancingPromotionToBank registers a financing promotion on both backends.
+func (r *BankRepositoryDualWrite) AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error {
+	_, err := write(ctx, r.dual, "AddFinancingPromotionToBank", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Banks.AddFinancingPromotionToBank(ctx, promotionFinancing)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return b.Compensation.RemovePromotion(ctx, promotionFinancing.Code)
+	})
+	return err
+}
+
+// AddDiscountPromotionToBank registers a discount promotion on both backends.
+func (r *BankRepositoryDualWrite) AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error {
+	_, err := write(ctx, r.dual, "AddDiscountPromotionToBank", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Banks.AddDiscountPromotionToBank(ctx, promotionDiscount)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return b.Compensation.RemovePromotion(ctx, promotionDiscount.Code)
+	})
+	return err
+}
+
+// ExtendFinancingPromotionValidity extends a financing promotion on both backends.
+func (r *BankRepositoryDualWrite) ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	return r.extendValidity(ctx, "ExtendFinancingPromotionValidity", code, newDate, storage.IBankStorage.ExtendFinancingPromotionValidity)
+}
+
+// ExtendDiscountPromotionValidity extends a discount promotion on both backends.
+func (r *BankRepositoryDualWrite) ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	return r.extendValidity(ctx, "ExtendDiscountPromotionValidity", code, newDate, storage.IBankStorage.ExtendDiscountPromotionValidity)
+}
+
+// DeleteFinancingPromotion logically deletes a financing promotion on both backends.
+func (r *BankRepositoryDualWrite) DeleteFinancingPromotion(ctx context.Context, code string) error {
+	return r.deletePromotion(ctx, "DeleteFinancingPromotion", code, storage.IBankStorage.DeleteFinancingPromotion)
+}
+
+// DeleteDiscountPromotion logically deletes a discount promotion on both backends.
+func (r *BankRepositoryDualWrite) DeleteDiscountPromotion(ctx context.Context, code string) error {
+	return r.deletePromotion(ctx, "DeleteDiscountPromotion", code, storage.IBankStorage.DeleteDiscountPromotion)
+}
+
+// GetBankCustomerCounts counts the customers of every bank.
+func (r *BankRepositoryDualWrite) GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error) {
+	return read(ctx, r.dual, "GetBankCustomerCounts", func(ctx context.Context, b Backend) ([]models.BankCustomerCountDTO, error) {
+		return b.Banks.GetBankCustomerCounts(ctx)
+	})
+}
+
+// SaveBillingCycle saves the billing cycle of a bank on both backends. When the secondary fails, the previous cycle
+// is saved again on the primary, or the cycle is removed if the bank had none.
+func (r *BankRepositoryDualWrite) SaveBillingCycle(ctx context.Context, cycle models.BillingCycle) error {
+	previous, err := r.primary.Banks.GetBillingCycle(ctx, cycle.BankCuit)
+	if err != nil {
+		return err
+	}
+
+	_, err = write(ctx, r.dual, "SaveBillingCycle", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Banks.SaveBillingCycle(ctx, cycle)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		if previous == nil {
+			return b.Compensation.RemoveBillingCycle(ctx, cycle.BankCuit)
+		}
+		return b.Banks.SaveBillingCycle(ctx, *previous)
+	})
+	return err
+}
+
+// GetBillingCycle retrieves the billing cycle configured for a bank.
+func (r *BankRepositoryDualWrite) GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error) {
+	return read(ctx, r.dual, "GetBillingCycle", func(ctx context.Context, b Backend) (*models.BillingCycle, error) {
+		return b.Banks.GetBillingCycle(ctx, bankCuit)
+	})
+}
+
+// extendValidity extends a promotion with the given extend method, restoring its previous end date on the primary
+// when the secondary fails.
+func (r *BankRepositoryDualWrite) extendValidity(ctx context.Context, operation string, code string, newDate time.Time, extend func(storage.IBankStorage, context.Context, string, time.Time) error) error {
+	previous, err := r.primary.Promotions.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	previousDate, err := parseValidityDate(promotionOf(previous).ValidityEndDate)
+	if err != nil {
+		return err
+	}
+
+	_, err = write(ctx, r.dual, operation, func(ctx context.Context, b Backend) (none, error) {
+		return none{}, extend(b.Banks, ctx, code, newDate)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return extend(b.Banks, ctx, code, previousDate)
+	})
+	return err
+}
+
+// deletePromotion logically deletes a promotion with the given delete method, restoring it on the primary when the
+// secondary fails, unless it was already deleted.
+func (r *BankRepositoryDualWrite) deletePromotion(ctx context.Context, operation string, code string, remove func(storage.IBankStorage, context.Context, string) error) error {
+	previous, err := r.primary.Promotions.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = write(ctx, r.dual, operation, func(ctx context.Context, b Backend) (none, error) {
+		return none{}, remove(b.Banks, ctx, code)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		if previous.IsDeleted {
+			return nil
+		}
+		return b.Promotions.RestorePromotion(ctx, code)
+	})
+	return err
+}
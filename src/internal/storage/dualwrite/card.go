@@ -0,0 +1,155 @@
+package dualwrite
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// purchases is the result of the reads returning single and monthly payment purchases.
+type purchases = pair[*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment]
+
+type CardRepositoryDualWrite struct {
+	dual
+}
+
+// NewCardDualWriteRepository creates a new instance of CardRepositoryDualWrite
+func NewCardDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.ICardStorage {
+	return &CardRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// GetPaymentSummary retrieves the payment summary of a card for a month.
+func (r *CardRepositoryDualWrite) GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	return read(ctx, r.dual, "GetPaymentSummary", func(ctx context.Context, b Backend) (*models.PaymentSummary, error) {
+		return b.Cards.GetPaymentSummary(ctx, cardNumber, month, year)
+	})
+}
+
+// SavePaymentSummary saves the payment summary of a card on both backends.
+func (r *CardRepositoryDualWrite) SavePaymentSummary(ctx context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error) {
+	return write(ctx, r.dual, "SavePaymentSummary", func(ctx context.Context, b Backend) (*models.PaymentSummary, error) {
+		return b.Cards.SavePaymentSummary(ctx, cardNumber, summary)
+	}, func(ctx context.Context, b Backend, _ *models.PaymentSummary) error {
+		return b.Compensation.RemovePaymentSummary(ctx, cardNumber, summary.Month, summary.Year)
+	})
+}
+
+// GetQuotasDueInMonth retrieves the quotas of a card due in a month.
+func (r *CardRepositoryDualWrite) GetQuotasDueInMonth(ctx context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error) {
+	return read(ctx, r.dual, "GetQuotasDueInMonth", func(ctx context.Context, b Backend) (*[]models.DueQuota, error) {
+		return b.Cards.GetQuotasDueInMonth(ctx, cardNumber, month, year)
+	})
+}
+
+// GetPurchasesInPeriod retrieves the purchases of a card made within a period.
+func (r *CardRepositoryDualWrite) GetPurchasesInPeriod(ctx context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error) {
+	result, err := read(ctx, r.dual, "GetPurchasesInPeriod", func(ctx context.Context, b Backend) (purchases, error) {
+		single, monthly, err := b.Cards.GetPurchasesInPeriod(ctx, cardNumber, from, to)
+		return purchases{first: single, second: monthly}, err
+	})
+	return result.first, result.second, err
+}
+
+// GetCardsExpiringInNext30Days retrieves the cards expiring within 30 days of a date.
+func (r *CardRepositoryDualWrite) GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error) {
+	return read(ctx, r.dual, "GetCardsExpiringInNext30Days", func(ctx context.Context, b Backend) (*[]models.Card, error) {
+		return b.Cards.GetCardsExpiringInNext30Days(ctx, day, month, year)
+	})
+}
+
+// GetPurchaseMonthly retrieves a monthly-payment purchase.
+func (r *CardRepositoryDualWrite) GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+	return read(ctx, r.dual, "GetPurchaseMonthly", func(ctx context.Context, b Backend) (*models.PurchaseMonthlyPayment, error) {
+		return b.Cards.GetPurchaseMonthly(ctx, cuit, finalAmount, paymentVoucher)
+	})
+}
+
+// GetPurchaseSingle retrieves a single-payment purchase.
+func (r *CardRepositoryDualWrite) GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+	return read(ctx, r.dual, "GetPurchaseSingle", func(ctx context.Context, b Backend) (*models.PurchaseSinglePayment, error) {
+		return b.Cards.GetPurchaseSingle(ctx, cuit, finalAmount, paymentVoucher)
+	})
+}
+
+// GetTop10CardsByPurchases retrieves the ten cards with the most purchases.
+func (r *CardRepositoryDualWrite) GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error) {
+	return read(ctx, r.dual, "GetTop10CardsByPurchases", func(ctx context.Context, b Backend) (*[]models.Card, error) {
+		return b.Cards.GetTop10CardsByPurchases(ctx)
+	})
+}
+
+// GetCardByNumber retrieves a card by its number.
+func (r *CardRepositoryDualWrite) GetCardByNumber(ctx context.Context, cardNumber string) (*models.Card, error) {
+	return read(ctx, r.dual, "GetCardByNumber", func(ctx context.Context, b Backend) (*models.Card, error) {
+		return b.Cards.GetCardByNumber(ctx, cardNumber)
+	})
+}
+
+// AddPurchaseSinglePayment registers a single-payment purchase on both backends. A missing purchase date defaults
+// to now here, so that both backends store the same date.
+func (r *CardRepositoryDualWrite) AddPurchaseSinglePayment(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	if purchase.PurchaseDate.IsZero() {
+		purchase.PurchaseDate = time.Now()
+	}
+
+	return write(ctx, r.dual, "AddPurchaseSinglePayment", func(ctx context.Context, b Backend) (*models.PurchaseSinglePayment, error) {
+		return b.Cards.AddPurchaseSinglePayment(ctx, cardNumber, purchase)
+	}, func(ctx context.Context, b Backend, _ *models.PurchaseSinglePayment) error {
+		return b.Compensation.RemovePurchaseSinglePayment(ctx, cardNumber, purchase.PaymentVoucher)
+	})
+}
+
+// AddPurchaseMonthlyPayment registers a monthly-payment purchase on both backends. A missing purchase date defaults
+// to now here, so that both backends store the same date.
+func (r *CardRepositoryDualWrite) AddPurchaseMonthlyPayment(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	if purchase.PurchaseDate.IsZero() {
+		purchase.PurchaseDate = time.Now()
+	}
+
+	return write(ctx, r.dual, "AddPurchaseMonthlyPayment", func(ctx context.Context, b Backend) (*models.PurchaseMonthlyPayment, error) {
+		return b.Cards.AddPurchaseMonthlyPayment(ctx, cardNumber, purchase)
+	}, func(ctx context.Context, b Backend, _ *models.PurchaseMonthlyPayment) error {
+		return b.Compensation.RemovePurchaseMonthlyPayment(ctx, cardNumber, purchase.PaymentVoucher)
+	})
+}
+
+// IssueCard issues a card on both backends.
+func (r *CardRepositoryDualWrite) IssueCard(ctx context.Context, card models.Card) (*models.Card, error) {
+	return write(ctx, r.dual, "IssueCard", func(ctx context.Context, b Backend) (*models.Card, error) {
+		return b.Cards.IssueCard(ctx, card)
+	}, func(ctx context.Context, b Backend, issued *models.Card) error {
+		return b.Compensation.RemoveCard(ctx, issued.Number)
+	})
+}
+
+// UpdateCardExpiration updates the expiration date of a card on both backends.
+func (r *CardRepositoryDualWrite) UpdateCardExpiration(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	previous, err := r.primary.Cards.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return write(ctx, r.dual, "UpdateCardExpiration", func(ctx context.Context, b Backend) (*models.Card, error) {
+		return b.Cards.UpdateCardExpiration(ctx, cardNumber, expirationDate)
+	}, func(ctx context.Context, b Backend, _ *models.Card) error {
+		_, err := b.Cards.UpdateCardExpiration(ctx, cardNumber, previous.ExpirationDate)
+		return err
+	})
+}
+
+// UpdateCardStatus updates the status of a card on both backends.
+func (r *CardRepositoryDualWrite) UpdateCardStatus(ctx context.Context, cardNumber string, status models.CardStatus) (*models.Card, error) {
+	previous, err := r.primary.Cards.GetCardByNumber(ctx, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return write(ctx, r.dual, "UpdateCardStatus", func(ctx context.Context, b Backend) (*models.Card, error) {
+		return b.Cards.UpdateCardStatus(ctx, cardNumber, status)
+	}, func(ctx context.Context, b Backend, _ *models.Card) error {
+		_, err := b.Cards.UpdateCardStatus(ctx, cardNumber, previous.Status)
+		return err
+	})
+}
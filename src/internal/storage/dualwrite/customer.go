@@ -0,0 +1,76 @@
+package dualwrite
+
+import (
+	"context"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type CustomerRepositoryDualWrite struct {
+	dual
+}
+
+// NewCustomerDualWriteRepository creates a new instance of CustomerRepositoryDualWrite
+func NewCustomerDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.ICustomerStorage {
+	return &CustomerRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// CreateCustomer registers a customer on both backends.
+func (r *CustomerRepositoryDualWrite) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	return write(ctx, r.dual, "CreateCustomer", func(ctx context.Context, b Backend) (*models.Customer, error) {
+		return b.Customers.CreateCustomer(ctx, customer)
+	}, func(ctx context.Context, b Backend, created *models.Customer) error {
+		return b.Compensation.RemoveCustomer(ctx, created.Cuit)
+	})
+}
+
+// GetCustomerByCuit retrieves a customer by its CUIT.
+func (r *CustomerRepositoryDualWrite) GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error) {
+	return read(ctx, r.dual, "GetCustomerByCuit", func(ctx context.Context, b Backend) (*models.Customer, error) {
+		return b.Customers.GetCustomerByCuit(ctx, cuit)
+	})
+}
+
+// UpdateCustomer updates a customer on both backends, restoring its previous details on the primary when the
+// secondary fails.
+func (r *CustomerRepositoryDualWrite) UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	previous, err := r.primary.Customers.GetCustomerByCuit(ctx, cuit)
+	if err != nil {
+		return nil, err
+	}
+
+	return write(ctx, r.dual, "UpdateCustomer", func(ctx context.Context, b Backend) (*models.Customer, error) {
+		return b.Customers.UpdateCustomer(ctx, cuit, customer)
+	}, func(ctx context.Context, b Backend, _ *models.Customer) error {
+		_, err := b.Customers.UpdateCustomer(ctx, cuit, *previous)
+		return err
+	})
+}
+
+// GetCustomers retrieves all customers.
+func (r *CustomerRepositoryDualWrite) GetCustomers(ctx context.Context) (*[]models.Customer, error) {
+	return read(ctx, r.dual, "GetCustomers", func(ctx context.Context, b Backend) (*[]models.Customer, error) {
+		return b.Customers.GetCustomers(ctx)
+	})
+}
+
+// AddCustomerToBank associates a customer with a bank on both backends.
+func (r *CustomerRepositoryDualWrite) AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	_, err := write(ctx, r.dual, "AddCustomerToBank", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Customers.AddCustomerToBank(ctx, customerCuit, bankCuit)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return b.Customers.RemoveCustomerFromBank(ctx, customerCuit, bankCuit)
+	})
+	return err
+}
+
+// RemoveCustomerFromBank removes the association between a customer and a bank on both backends.
+func (r *CustomerRepositoryDualWrite) RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	_, err := write(ctx, r.dual, "RemoveCustomerFromBank", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Customers.RemoveCustomerFromBank(ctx, customerCuit, bankCuit)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return b.Customers.AddCustomerToBank(ctx, customerCuit, bankCuit)
+	})
+	return err
+}
@@ -0,0 +1,137 @@
+/*
+ * Payment Registration System - Dual-Write Storage
+ * --------------------------------------------------
+ * This file defines decorators around the storage interfaces that keep two backends in step. Writes are
+ * applied to the primary backend and then to the secondary one; when the secondary write fails, the primary
+ * write is compensated, either by restoring the previous state or by removing the created record. Reads are
+ * served by the primary backend, optionally falling back to the secondary one when the primary fails.
+ *
+ * A dual write is not atomic: concurrent writes to the same record may interleave between the backends, and a
+ * failed compensation leaves them different, which is logged and reported by the consistency check.
+ *
+ * Created: Mar. 28, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package dualwrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+// Backend groups one implementation of each storage interface, all backed by the same database.
+type Backend struct {
+	Name          string // Name of the backend in logs and errors, such as sql or no-sql
+	Banks         storage.IBankStorage
+	Cards         storage.ICardStorage
+	Promotions    storage.IPromotionStorage
+	Customers     storage.ICustomerStorage
+	Stores        storage.IStoreStorage
+	ExchangeRates storage.IExchangeRateStorage
+	Payments      storage.IPaymentStorage
+	Compensation  storage.ICompensationStorage
+}
+
+// dual holds the backends of a decorator.
+type dual struct {
+	primary   Backend
+	secondary Backend
+	fallback  bool // Whether reads fall back to the secondary backend when the primary fails
+}
+
+// none is the result of the writes that only return an error.
+type none struct{}
+
+// pair is the result of the reads that return two values.
+type pair[A any, B any] struct {
+	first  A
+	second B
+}
+
+/*
+ * read
+ * --------------------------------------------------
+ * Serves a read from the primary backend. With fallback enabled, a read failing on the primary backend for a
+ * reason other than a missing record is served by the secondary backend.
+ *
+ * Params:
+ * - ctx (context.Context): Context of the read.
+ * - d (dual): The backends.
+ * - operation (string): Name of the read, for logs.
+ * - call (func(context.Context, Backend) (T, error)): The read on a backend.
+ *
+ * Returns:
+ * - T: The result of the read.
+ * - error: The error of the backend that served the read.
+ */
+func read[T any](ctx context.Context, d dual, operation string, call func(ctx context.Context, b Backend) (T, error)) (T, error) {
+	result, err := call(ctx, d.primary)
+	if err == nil || !d.fallback || errors.Is(err, storage.ErrNotFound) {
+		return result, err
+	}
+
+	logger.Warn("%s failed on the %s backend, reading from the %s backend: %v", operation, d.primary.Name, d.secondary.Name, err)
+	return call(ctx, d.secondary)
+}
+
+/*
+ * write
+ * --------------------------------------------------
+ * Applies a write to the primary backend and then to the secondary one. When the secondary write fails, undo
+ * compensates the primary write, given its result. The compensation runs even if ctx has been cancelled or has
+ * expired meanwhile, as the primary write has already been applied.
+ *
+ * Params:
+ * - ctx (context.Context): Context of the write.
+ * - d (dual): The backends.
+ * - operation (string): Name of the write, for logs and errors.
+ * - apply (func(context.Context, Backend) (T, error)): The write on a backend.
+ * - undo (func(context.Context, Backend, T) error): Reverts the write on the primary backend.
+ *
+ * Returns:
+ * - T: The result of the write on the primary backend.
+ * - error: The error of the primary write, or of the secondary write once compensated.
+ */
+func write[T any](ctx context.Context, d dual, operation string, apply func(ctx context.Context, b Backend) (T, error), undo func(ctx context.Context, primary Backend, written T) error) (T, error) {
+	written, err := apply(ctx, d.primary)
+	if err != nil {
+		return written, err
+	}
+
+	if _, err := apply(ctx, d.secondary); err != nil {
+		var zero T
+		return zero, d.compensate(operation, err, func(primary Backend) error {
+			return undo(context.WithoutCancel(ctx), primary, written)
+		})
+	}
+	return written, nil
+}
+
+// compensate reverts a write on the primary backend after it failed on the secondary one. The returned error wraps
+// the secondary error, so that callers can still match storage.ErrNotFound and storage.ErrAlreadyExists.
+func (d dual) compensate(operation string, err error, undo func(primary Backend) error) error {
+	logger.Error("%s failed on the %s backend, compensating on the %s backend: %v", operation, d.secondary.Name, d.primary.Name, err)
+	err = fmt.Errorf("%s failed on the %s backend: %w", operation, d.secondary.Name, err)
+
+	if undoErr := undo(d.primary); undoErr != nil {
+		logger.Error("Compensating %s on the %s backend failed, the backends differ: %v", operation, d.primary.Name, undoErr)
+		return fmt.Errorf("%w; compensating on the %s backend failed: %v", err, d.primary.Name, undoErr)
+	}
+
+	logger.Info("%s compensated on the %s backend", operation, d.primary.Name)
+	return err
+}
+
+// parseValidityDate parses a promotion validity date as returned by the storages: RFC3339 or a plain date.
+func parseValidityDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
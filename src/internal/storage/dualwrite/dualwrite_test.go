@@ -0,0 +1,312 @@
+package dualwrite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/memory"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errUnavailable stands for a backend that cannot be reached.
+var errUnavailable = errors.New("backend unavailable")
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+// memoryBackend returns a backend on its own in-memory database.
+func memoryBackend(name string) Backend {
+	db := memory.NewMemoryDB()
+	return Backend{
+		Name:          name,
+		Banks:         memory.NewBankMemoryRepository(db),
+		Cards:         memory.NewCardMemoryRepository(db),
+		Promotions:    memory.NewPromotionMemoryRepository(db),
+		Customers:     memory.NewCustomerMemoryRepository(db),
+		Stores:        memory.NewStoreMemoryRepository(db),
+		ExchangeRates: memory.NewExchangeRateMemoryRepository(db),
+		Payments:      memory.NewPaymentMemoryRepository(db),
+		Compensation:  memory.NewCompensationMemoryRepository(db),
+	}
+}
+
+// dualStorages returns the dual-write decorators of the backends.
+func dualStorages(primary Backend, secondary Backend, fallback bool) storagetest.Storages {
+	return storagetest.Storages{
+		Banks:         NewBankDualWriteRepository(primary, secondary, fallback),
+		Cards:         NewCardDualWriteRepository(primary, secondary, fallback),
+		Promotions:    NewPromotionDualWriteRepository(primary, secondary, fallback),
+		Customers:     NewCustomerDualWriteRepository(primary, secondary, fallback),
+		Stores:        NewStoreDualWriteRepository(primary, secondary, fallback),
+		ExchangeRates: NewExchangeRateDualWriteRepository(primary, secondary, fallback),
+		Payments:      NewPaymentDualWriteRepository(primary, secondary, fallback),
+	}
+}
+
+// bothCompensations removes records from both backends, so that the contract suite can remove what it created.
+type bothCompensations [2]storage.ICompensationStorage
+
+func (c bothCompensations) each(remove func(storage.ICompensationStorage) error) error {
+	return errors.Join(remove(c[0]), remove(c[1]))
+}
+
+func (c bothCompensations) RemoveBank(ctx context.Context, cuit string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemoveBank(ctx, cuit) })
+}
+
+func (c bothCompensations) RemoveCustomer(ctx context.Context, cuit string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemoveCustomer(ctx, cuit) })
+}
+
+func (c bothCompensations) RemoveCard(ctx context.Context, cardNumber string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemoveCard(ctx, cardNumber) })
+}
+
+func (c bothCompensations) RemovePromotion(ctx context.Context, code string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemovePromotion(ctx, code) })
+}
+
+func (c bothCompensations) RemovePurchaseSinglePayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	return c.each(func(s storage.ICompensationStorage) error {
+		return s.RemovePurchaseSinglePayment(ctx, cardNumber, paymentVoucher)
+	})
+}
+
+func (c bothCompensations) RemovePurchaseMonthlyPayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	return c.each(func(s storage.ICompensationStorage) error {
+		return s.RemovePurchaseMonthlyPayment(ctx, cardNumber, paymentVoucher)
+	})
+}
+
+func (c bothCompensations) RemovePaymentSummary(ctx context.Context, cardNumber string, month int, year int) error {
+	return c.each(func(s storage.ICompensationStorage) error {
+		return s.RemovePaymentSummary(ctx, cardNumber, month, year)
+	})
+}
+
+func (c bothCompensations) RemoveBillingCycle(ctx context.Context, bankCuit string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemoveBillingCycle(ctx, bankCuit) })
+}
+
+func (c bothCompensations) RemoveExchangeRate(ctx context.Context, currency models.Currency, date time.Time) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemoveExchangeRate(ctx, currency, date) })
+}
+
+func (c bothCompensations) RemovePayment(ctx context.Context, code string) error {
+	return c.each(func(s storage.ICompensationStorage) error { return s.RemovePayment(ctx, code) })
+}
+
+// unavailableBanks fails the bank reads and the bank writes used by the tests.
+type unavailableBanks struct {
+	storage.IBankStorage
+}
+
+func (unavailableBanks) GetBankByCuit(context.Context, string) (*models.Bank, error) {
+	return nil, errUnavailable
+}
+
+func (unavailableBanks) DeleteDiscountPromotion(context.Context, string) error { return errUnavailable }
+
+func (unavailableBanks) SaveBillingCycle(context.Context, models.BillingCycle) error {
+	return errUnavailable
+}
+
+// unavailableCustomers fails the customer updates.
+type unavailableCustomers struct {
+	storage.ICustomerStorage
+}
+
+func (unavailableCustomers) UpdateCustomer(context.Context, string, models.Customer) (*models.Customer, error) {
+	return nil, errUnavailable
+}
+
+// unavailableExchangeRates fails the exchange rate writes.
+type unavailableExchangeRates struct {
+	storage.IExchangeRateStorage
+}
+
+func (unavailableExchangeRates) SaveExchangeRates(context.Context, []models.ExchangeRate) error {
+	return errUnavailable
+}
+
+// unavailableCompensation fails every removal.
+type unavailableCompensation struct {
+	storage.ICompensationStorage
+}
+
+func (unavailableCompensation) RemoveBank(context.Context, string) error { return errUnavailable }
+
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storages {
+		primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+		storages := dualStorages(primary, secondary, false)
+		storages.Compensation = bothCompensations{primary.Compensation, secondary.Compensation}
+		return storages
+	})
+}
+
+func TestWritesReachBothBackends(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+
+	for _, backend := range []Backend{primary, secondary} {
+		banks, err := backend.Banks.GetBanks(ctx)
+		require.NoError(t, err)
+		assert.Len(t, *banks, 2, backend.Name)
+
+		card, err := backend.Cards.GetCardByNumber(ctx, storagetest.CardNumber)
+		require.NoError(t, err)
+		assert.Equal(t, models.CardStatusActive, card.Status, backend.Name)
+	}
+
+	// Purchases without a date get the same one on both backends
+	purchase := models.PurchaseSinglePayment{Purchase: models.Purchase{PaymentVoucher: "DUAL-0001", Store: "Tienda Norte", CuitStore: storagetest.NorthStoreCuit, Amount: models.MustParseMoney("100"), FinalAmount: models.MustParseMoney("100")}}
+	cards := NewCardDualWriteRepository(primary, secondary, false)
+	registered, err := cards.AddPurchaseSinglePayment(ctx, storagetest.CardNumber, purchase)
+	require.NoError(t, err)
+	require.False(t, registered.PurchaseDate.IsZero())
+
+	for _, backend := range []Backend{primary, secondary} {
+		single, _, err := backend.Cards.GetPurchasesInPeriod(ctx, storagetest.CardNumber, registered.PurchaseDate.Add(-time.Second), registered.PurchaseDate.Add(time.Second))
+		require.NoError(t, err)
+		require.Len(t, *single, 1, backend.Name)
+		assert.True(t, registered.PurchaseDate.Equal((*single)[0].PurchaseDate), backend.Name)
+	}
+}
+
+func TestCreateIsRemovedFromPrimaryWhenSecondaryFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	bank := models.Bank{Name: "Banco Dual", Cuit: "30-00000009-0", Address: "Av. Belgrano 900", Telephone: "0800-999-9999"}
+	_, err := secondary.Banks.CreateBank(ctx, bank)
+	require.NoError(t, err)
+
+	_, err = NewBankDualWriteRepository(primary, secondary, false).CreateBank(ctx, bank)
+
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	_, err = primary.Banks.GetBankByCuit(ctx, bank.Cuit)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestUpdateIsRestoredOnPrimaryWhenSecondaryFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+	before, err := primary.Customers.GetCustomerByCuit(ctx, storagetest.CustomerCuit)
+	require.NoError(t, err)
+
+	secondary.Customers = unavailableCustomers{secondary.Customers}
+	update := *before
+	update.Address = "Calle Falsa 123"
+	_, err = NewCustomerDualWriteRepository(primary, secondary, false).UpdateCustomer(ctx, storagetest.CustomerCuit, update)
+
+	assert.ErrorIs(t, err, errUnavailable)
+	after, err := primary.Customers.GetCustomerByCuit(ctx, storagetest.CustomerCuit)
+	require.NoError(t, err)
+	assert.Equal(t, before.Address, after.Address)
+}
+
+func TestDeletedPromotionIsRestoredOnPrimaryWhenSecondaryFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+	before, err := primary.Promotions.GetPromotionByCode(ctx, "DISC-2025")
+	require.NoError(t, err)
+	require.False(t, before.IsDeleted)
+
+	secondary.Banks = unavailableBanks{secondary.Banks}
+	err = NewBankDualWriteRepository(primary, secondary, false).DeleteDiscountPromotion(ctx, "DISC-2025")
+
+	assert.ErrorIs(t, err, errUnavailable)
+	detail, err := primary.Promotions.GetPromotionByCode(ctx, "DISC-2025")
+	require.NoError(t, err)
+	assert.False(t, detail.IsDeleted)
+}
+
+func TestNewBillingCycleIsRemovedFromPrimaryWhenSecondaryFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+
+	secondary.Banks = unavailableBanks{secondary.Banks}
+	cycle := models.BillingCycle{BankCuit: storagetest.OtherBankCuit, ClosingDay: 20, FirstDueDays: 10, SecondDueDays: 5, SurchargePercentage: models.MustParsePercentage("3")}
+	err := NewBankDualWriteRepository(primary, secondary, false).SaveBillingCycle(ctx, cycle)
+
+	assert.ErrorIs(t, err, errUnavailable)
+	saved, err := primary.Banks.GetBillingCycle(ctx, storagetest.OtherBankCuit)
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+}
+
+func TestExchangeRatesAreRestoredOnPrimaryWhenSecondaryFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+
+	secondary.ExchangeRates = unavailableExchangeRates{secondary.ExchangeRates}
+	april := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	err := NewExchangeRateDualWriteRepository(primary, secondary, false).SaveExchangeRates(ctx, []models.ExchangeRate{
+		{Currency: models.CurrencyUSD, Date: april, Rate: models.MustParseMoney("2000")},
+		{Currency: models.CurrencyUSD, Date: april.AddDate(0, 0, 1), Rate: models.MustParseMoney("2001")},
+	})
+
+	assert.ErrorIs(t, err, errUnavailable)
+	rates, err := primary.ExchangeRates.GetExchangeRates(ctx, models.CurrencyUSD, april, april.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	if assert.Len(t, *rates, 1, "the rate of a new day is removed") {
+		assert.Equal(t, models.MustParseMoney("1060.50"), (*rates)[0].Rate, "the replaced rate is restored")
+	}
+}
+
+func TestFailedCompensationIsReported(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	bank := models.Bank{Name: "Banco Dual", Cuit: "30-00000009-0", Address: "Av. Belgrano 900", Telephone: "0800-999-9999"}
+	_, err := secondary.Banks.CreateBank(ctx, bank)
+	require.NoError(t, err)
+
+	primary.Compensation = unavailableCompensation{primary.Compensation}
+	_, err = NewBankDualWriteRepository(primary, secondary, false).CreateBank(ctx, bank)
+
+	require.ErrorIs(t, err, storage.ErrAlreadyExists)
+	assert.ErrorContains(t, err, "compensating on the primary backend failed: backend unavailable")
+	_, err = primary.Banks.GetBankByCuit(ctx, bank.Cuit)
+	assert.NoError(t, err, "the primary keeps the bank when the compensation fails")
+}
+
+func TestReadsFallBackToSecondary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memoryBackend("primary"), memoryBackend("secondary")
+	require.NoError(t, storagetest.DefaultFixture().Load(dualStorages(primary, secondary, false)))
+	primary.Banks = unavailableBanks{primary.Banks}
+
+	t.Run("without fallback", func(t *testing.T) {
+		_, err := NewBankDualWriteRepository(primary, secondary, false).GetBankByCuit(ctx, storagetest.BankCuit)
+		assert.ErrorIs(t, err, errUnavailable)
+	})
+
+	t.Run("with fallback", func(t *testing.T) {
+		bank, err := NewBankDualWriteRepository(primary, secondary, true).GetBankByCuit(ctx, storagetest.BankCuit)
+		require.NoError(t, err)
+		assert.Equal(t, storagetest.BankCuit, bank.Cuit)
+	})
+
+	t.Run("missing records are not read from the secondary", func(t *testing.T) {
+		_, err := secondary.Customers.CreateCustomer(ctx, models.Customer{CompleteName: "Solo Secundario", Cuit: "20-00000009-0"})
+		require.NoError(t, err)
+
+		_, err = NewCustomerDualWriteRepository(primary, secondary, true).GetCustomerByCuit(ctx, "20-00000009-0")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -0,0 +1,65 @@
+package dualwrite
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type ExchangeRateRepositoryDualWrite struct {
+	dual
+}
+
+// NewExchangeRateDualWriteRepository creates a new instance of ExchangeRateRepositoryDualWrite
+func NewExchangeRateDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.IExchangeRateStorage {
+	return &ExchangeRateRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// SaveExchangeRates saves exchange rates on both backends. When the secondary fails, the rates they replaced are
+// saved again on the primary and the rates of new days are removed.
+func (r *ExchangeRateRepositoryDualWrite) SaveExchangeRates(ctx context.Context, rates []models.ExchangeRate) error {
+	var replaced, added []models.ExchangeRate
+	for _, rate := range rates {
+		day := models.RateDay(rate.Date)
+		previous, err := r.primary.ExchangeRates.GetExchangeRates(ctx, rate.Currency, day, day)
+		if err != nil {
+			return err
+		}
+		if len(*previous) > 0 {
+			replaced = append(replaced, (*previous)[0])
+		} else {
+			added = append(added, rate)
+		}
+	}
+
+	_, err := write(ctx, r.dual, "SaveExchangeRates", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.ExchangeRates.SaveExchangeRates(ctx, rates)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		for _, rate := range added {
+			if err := b.Compensation.RemoveExchangeRate(ctx, rate.Currency, rate.Date); err != nil {
+				return err
+			}
+		}
+		if len(replaced) == 0 {
+			return nil
+		}
+		return b.ExchangeRates.SaveExchangeRates(ctx, replaced)
+	})
+	return err
+}
+
+// GetExchangeRates retrieves the rates of a currency between two days.
+func (r *ExchangeRateRepositoryDualWrite) GetExchangeRates(ctx context.Context, currency models.Currency, from time.Time, to time.Time) (*[]models.ExchangeRate, error) {
+	return read(ctx, r.dual, "GetExchangeRates", func(ctx context.Context, b Backend) (*[]models.ExchangeRate, error) {
+		return b.ExchangeRates.GetExchangeRates(ctx, currency, from, to)
+	})
+}
+
+// GetExchangeRate retrieves the latest rate of a currency on or before a day.
+func (r *ExchangeRateRepositoryDualWrite) GetExchangeRate(ctx context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error) {
+	return read(ctx, r.dual, "GetExchangeRate", func(ctx context.Context, b Backend) (*models.ExchangeRate, error) {
+		return b.ExchangeRates.GetExchangeRate(ctx, currency, date)
+	})
+}
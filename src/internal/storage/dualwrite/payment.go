@@ -0,0 +1,40 @@
+package dualwrite
+
+import (
+	"context"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type PaymentRepositoryDualWrite struct {
+	dual
+}
+
+// NewPaymentDualWriteRepository creates a new instance of PaymentRepositoryDualWrite
+func NewPaymentDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.IPaymentStorage {
+	return &PaymentRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// RecordPayment records a payment and its allocations on both backends.
+func (r *PaymentRepositoryDualWrite) RecordPayment(ctx context.Context, payment models.Payment) (*models.Payment, error) {
+	return write(ctx, r.dual, "RecordPayment", func(ctx context.Context, b Backend) (*models.Payment, error) {
+		return b.Payments.RecordPayment(ctx, payment)
+	}, func(ctx context.Context, b Backend, _ *models.Payment) error {
+		return b.Compensation.RemovePayment(ctx, payment.Code)
+	})
+}
+
+// GetPayments retrieves the payments of the summary of a card for a month.
+func (r *PaymentRepositoryDualWrite) GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	return read(ctx, r.dual, "GetPayments", func(ctx context.Context, b Backend) (*[]models.Payment, error) {
+		return b.Payments.GetPayments(ctx, cardNumber, month, year)
+	})
+}
+
+// GetPaymentSummaries retrieves the payment summaries of a card.
+func (r *PaymentRepositoryDualWrite) GetPaymentSummaries(ctx context.Context, cardNumber string) (*[]models.PaymentSummary, error) {
+	return read(ctx, r.dual, "GetPaymentSummaries", func(ctx context.Context, b Backend) (*[]models.PaymentSummary, error) {
+		return b.Payments.GetPaymentSummaries(ctx, cardNumber)
+	})
+}
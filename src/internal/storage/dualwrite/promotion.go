@@ -0,0 +1,145 @@
+package dualwrite
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// promotions is the result of the reads returning financing and discount promotions.
+type promotions = pair[*[]models.Financing, *[]models.Discount]
+
+type PromotionRepositoryDualWrite struct {
+	dual
+}
+
+// NewPromotionDualWriteRepository creates a new instance of PromotionRepositoryDualWrite
+func NewPromotionDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.IPromotionStorage {
+	return &PromotionRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// GetAvailablePromotionsByStoreAndDateRange retrieves the promotions of a store valid within a date range.
+func (r *PromotionRepositoryDualWrite) GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	result, err := read(ctx, r.dual, "GetAvailablePromotionsByStoreAndDateRange", func(ctx context.Context, b Backend) (promotions, error) {
+		financings, discounts, err := b.Promotions.GetAvailablePromotionsByStoreAndDateRange(ctx, cuit, startDate, endDate)
+		return promotions{first: financings, second: discounts}, err
+	})
+	return result.first, result.second, err
+}
+
+// GetMostUsedPromotion retrieves the promotion used by the most purchases.
+func (r *PromotionRepositoryDualWrite) GetMostUsedPromotion(ctx context.Context) (interface{}, error) {
+	return read(ctx, r.dual, "GetMostUsedPromotion", func(ctx context.Context, b Backend) (interface{}, error) {
+		return b.Promotions.GetMostUsedPromotion(ctx)
+	})
+}
+
+// GetApplicablePromotions retrieves the promotions of a bank applicable to a purchase in a store on a date.
+func (r *PromotionRepositoryDualWrite) GetApplicablePromotions(ctx context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	result, err := read(ctx, r.dual, "GetApplicablePromotions", func(ctx context.Context, b Backend) (promotions, error) {
+		financings, discounts, err := b.Promotions.GetApplicablePromotions(ctx, bankCuit, storeCuit, date)
+		return promotions{first: financings, second: discounts}, err
+	})
+	return result.first, result.second, err
+}
+
+// GetPromotionByCode retrieves a promotion by its code.
+func (r *PromotionRepositoryDualWrite) GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error) {
+	return read(ctx, r.dual, "GetPromotionByCode", func(ctx context.Context, b Backend) (*models.PromotionDetail, error) {
+		return b.Promotions.GetPromotionByCode(ctx, code)
+	})
+}
+
+// GetBankPromotions retrieves the promotions of a bank with the given status.
+func (r *PromotionRepositoryDualWrite) GetBankPromotions(ctx context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	result, err := read(ctx, r.dual, "GetBankPromotions", func(ctx context.Context, b Backend) (promotions, error) {
+		financings, discounts, err := b.Promotions.GetBankPromotions(ctx, bankCuit, status, date)
+		return promotions{first: financings, second: discounts}, err
+	})
+	return result.first, result.second, err
+}
+
+// UpdatePromotion edits a promotion on both backends, restoring the previous values of the edited fields on the
+// primary when the secondary fails.
+func (r *PromotionRepositoryDualWrite) UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) error {
+	previous, err := r.primary.Promotions.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = write(ctx, r.dual, "UpdatePromotion", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Promotions.UpdatePromotion(ctx, code, update)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		return b.Promotions.UpdatePromotion(ctx, code, reverseUpdate(previous, update))
+	})
+	return err
+}
+
+// RestorePromotion restores a logically deleted promotion on both backends, deleting it again on the primary when
+// the secondary fails.
+func (r *PromotionRepositoryDualWrite) RestorePromotion(ctx context.Context, code string) error {
+	previous, err := r.primary.Promotions.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = write(ctx, r.dual, "RestorePromotion", func(ctx context.Context, b Backend) (none, error) {
+		return none{}, b.Promotions.RestorePromotion(ctx, code)
+	}, func(ctx context.Context, b Backend, _ none) error {
+		switch {
+		case !previous.IsDeleted:
+			return nil
+		case previous.Type == models.PromotionTypeFinancing:
+			return b.Banks.DeleteFinancingPromotion(ctx, code)
+		default:
+			return b.Banks.DeleteDiscountPromotion(ctx, code)
+		}
+	})
+	return err
+}
+
+// promotionOf returns the common details of a discount or financing promotion.
+func promotionOf(detail *models.PromotionDetail) models.Promotion {
+	if detail.Financing != nil {
+		return detail.Financing.Promotion
+	}
+	if detail.Discount != nil {
+		return detail.Discount.Promotion
+	}
+	return models.Promotion{}
+}
+
+// reverseUpdate returns the update setting the fields present in update back to their values in previous.
+func reverseUpdate(previous *models.PromotionDetail, update models.PromotionUpdate) models.PromotionUpdate {
+	promotion := promotionOf(previous)
+
+	var reverse models.PromotionUpdate
+	if update.PromotionTitle != nil {
+		reverse.PromotionTitle = &promotion.PromotionTitle
+	}
+	if update.Comments != nil {
+		reverse.Comments = &promotion.Comments
+	}
+	if discount := previous.Discount; discount != nil {
+		if update.DiscountPercentage != nil {
+			reverse.DiscountPercentage = &discount.DiscountPercentage
+		}
+		if update.PriceCap != nil {
+			reverse.PriceCap = &discount.PriceCap
+		}
+		if update.OnlyCash != nil {
+			reverse.OnlyCash = &discount.OnlyCash
+		}
+	}
+	if financing := previous.Financing; financing != nil {
+		if update.NumberOfQuotas != nil {
+			reverse.NumberOfQuotas = &financing.NumberOfQuotas
+		}
+		if update.Interest != nil {
+			reverse.Interest = &financing.Interest
+		}
+	}
+	return reverse
+}
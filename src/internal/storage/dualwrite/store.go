@@ -0,0 +1,24 @@
+package dualwrite
+
+import (
+	"context"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type StoreRepositoryDualWrite struct {
+	dual
+}
+
+// NewStoreDualWriteRepository creates a new instance of StoreRepositoryDualWrite
+func NewStoreDualWriteRepository(primary Backend, secondary Backend, fallback bool) storage.IStoreStorage {
+	return &StoreRepositoryDualWrite{dual{primary: primary, secondary: secondary, fallback: fallback}}
+}
+
+// GetStoreWithHighestRevenueByMonth retrieves the store with the highest revenue in a month.
+func (r *StoreRepositoryDualWrite) GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error) {
+	return read(ctx, r.dual, "GetStoreWithHighestRevenueByMonth", func(ctx context.Context, b Backend) (models.StoreDTO, error) {
+		return b.Stores.GetStoreWithHighestRevenueByMonth(ctx, month, year)
+	})
+}
@@ -21,21 +21,22 @@ import (
 )
 
 type BankEntityNonSQL struct {
-	ID        bson.ObjectID   `bson:"_id,omitempty"` // 🔥 Ensure `_id` exists
-	Name      string          `bson:"name"`
-	Cuit      string          `bson:"cuit"`
-	Address   string          `bson:"address"`
-	Telephone string          `bson:"telephone"`
-	Customers []bson.ObjectID `bson:"customers,omitempty"`
-	CreatedAt time.Time       `bson:"created_at,omitempty"`
-	UpdatedAt time.Time       `bson:"updated_at,omitempty"`
+	ID           bson.ObjectID             `bson:"_id,omitempty"` // 🔥 Ensure `_id` exists
+	Name         string                    `bson:"name"`
+	Cuit         string                    `bson:"cuit"`
+	Address      string                    `bson:"address"`
+	Telephone    string                    `bson:"telephone"`
+	Customers    []bson.ObjectID           `bson:"customers,omitempty"`
+	BillingCycle *BillingCycleEntityNonSQL `bson:"billing_cycle,omitempty"`
+	CreatedAt    time.Time                 `bson:"created_at,omitempty"`
+	UpdatedAt    time.Time                 `bson:"updated_at,omitempty"`
 }
 
 // Bank represents a financial institution that holds customers and issues cards.
 type BankEntitySQL struct {
 	ID        uint                `gorm:"primaryKey;autoIncrement"`
 	Name      string              `gorm:"size:255"`
-	Cuit      string              `gorm:"size:255"`
+	Cuit      string              `gorm:"size:255;uniqueIndex;not null"`
 	Address   string              `gorm:"size:255"`
 	Telephone string              `gorm:"size:255"`
 	Customers []CustomerEntitySQL `gorm:"many2many:CUSTOMERS_BANKS;"`
@@ -61,9 +62,22 @@ func ToBankEntity(bank *models.Bank) *BankEntitySQL {
 	}
 }
 
+// Bank a BankModel mapper for non-relational storage
+func ToBankEntityNonSQL(bank *models.Bank) *BankEntityNonSQL {
+	return &BankEntityNonSQL{
+		Name:      bank.Name,
+		Cuit:      bank.Cuit,
+		Address:   bank.Address,
+		Telephone: bank.Telephone,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
 // BankModel a Bank mapper (si necesitas convertir de nuevo)
 func ToBank(bankModel *BankEntitySQL) *models.Bank {
 	return &models.Bank{
+		Name:      bankModel.Name,
 		Cuit:      bankModel.Cuit,
 		Address:   bankModel.Address,
 		Telephone: bankModel.Telephone,
@@ -74,6 +88,7 @@ func ToBank(bankModel *BankEntitySQL) *models.Bank {
 // BankModel NoSQL case overload
 func ToBankNonSQL(bank *BankEntityNonSQL) *models.Bank {
 	return &models.Bank{
+		Name:      bank.Name,
 		Cuit:      bank.Cuit,
 		Address:   bank.Address,
 		Telephone: bank.Telephone,
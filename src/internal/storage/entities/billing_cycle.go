@@ -0,0 +1,86 @@
+/*
+ * Payment Registration System - Billing Cycle Entity (SQL and NoSQL)
+ * ------------------------------------------------------------------
+ *
+ * Description: Billing cycle entity holds the billing configuration of a bank.
+ * The SQL implementation stores it in its own table, one row per bank.
+ * The NoSQL implementation embeds it in the bank document.
+ *
+ * Created: Mar. 04, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package entities
+
+import (
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+)
+
+// BillingCycleEntityNonSQL is embedded in the bank document under `billing_cycle`.
+type BillingCycleEntityNonSQL struct {
+	ClosingDay          int               `bson:"closing_day"`
+	FirstDueDays        int               `bson:"first_due_days"`
+	SecondDueDays       int               `bson:"second_due_days"`
+	SurchargePercentage models.Percentage `bson:"surcharge_percentage"`
+	UpdatedAt           time.Time         `bson:"updated_at,omitempty"`
+}
+
+type BillingCycleEntitySQL struct {
+	ID                  uint              `gorm:"primaryKey;autoIncrement"`
+	BankID              uint              `gorm:"uniqueIndex;not null"`
+	Bank                BankEntitySQL     `gorm:"foreignKey:BankID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ClosingDay          int               `gorm:"not null"`
+	FirstDueDays        int               `gorm:"not null"`
+	SecondDueDays       int               `gorm:"not null"`
+	SurchargePercentage models.Percentage `gorm:"type:decimal(7,2);not null;default:0"`
+	CreatedAt           time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time         `gorm:"autoUpdateTime"`
+}
+
+func (BillingCycleEntitySQL) TableName() string {
+	return "BILLING_CYCLES"
+}
+
+// ------------ Mappers ------------	//
+
+func ToBillingCycleEntity(cycle *models.BillingCycle, bankId uint) *BillingCycleEntitySQL {
+	return &BillingCycleEntitySQL{
+		BankID:              bankId,
+		ClosingDay:          cycle.ClosingDay,
+		FirstDueDays:        cycle.FirstDueDays,
+		SecondDueDays:       cycle.SecondDueDays,
+		SurchargePercentage: cycle.SurchargePercentage,
+	}
+}
+
+func ToBillingCycleEntityNonSQL(cycle *models.BillingCycle) *BillingCycleEntityNonSQL {
+	return &BillingCycleEntityNonSQL{
+		ClosingDay:          cycle.ClosingDay,
+		FirstDueDays:        cycle.FirstDueDays,
+		SecondDueDays:       cycle.SecondDueDays,
+		SurchargePercentage: cycle.SurchargePercentage,
+		UpdatedAt:           time.Now(),
+	}
+}
+
+func ToBillingCycle(entity *BillingCycleEntitySQL, bankCuit string) *models.BillingCycle {
+	return &models.BillingCycle{
+		BankCuit:            bankCuit,
+		ClosingDay:          entity.ClosingDay,
+		FirstDueDays:        entity.FirstDueDays,
+		SecondDueDays:       entity.SecondDueDays,
+		SurchargePercentage: entity.SurchargePercentage,
+	}
+}
+
+func ToBillingCycleNonSQL(entity *BillingCycleEntityNonSQL, bankCuit string) *models.BillingCycle {
+	return &models.BillingCycle{
+		BankCuit:            bankCuit,
+		ClosingDay:          entity.ClosingDay,
+		FirstDueDays:        entity.FirstDueDays,
+		SecondDueDays:       entity.SecondDueDays,
+		SurchargePercentage: entity.SurchargePercentage,
+	}
+}
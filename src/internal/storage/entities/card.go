@@ -15,6 +15,7 @@ type CardEntityNonSQL struct {
 	CardholderNameInCard    string                                `bson:"cardholder_name_in_card"`
 	Since                   time.Time                             `bson:"since"` // When the card was issued
 	ExpirationDate          time.Time                             `bson:"expiration_date"`
+	Status                  string                                `bson:"status,omitempty"`        // Lifecycle status, active when missing
 	BankCuit                string                                `bson:"bank_cuit,omitempty"`     // Reference to the bank (if using references)
 	CustomerCuit            string                                `bson:"customer_cuit,omitempty"` // Reference to the customer
 	PurchaseSinglePayments  []PurchaseSinglePaymentEntityNonSQL   `bson:"purchase_single_payments,omitempty"`
@@ -27,7 +28,7 @@ type PaymentSummaryResult struct {
 	Card                    CardEntityNonSQL `bson:",inline"`
 	PurchaseSinglePayments  []bson.Raw       `bson:"purchase_single_payments"`
 	PurchaseMonthlyPayments []bson.Raw       `bson:"purchase_monthly_payments"`
-	TotalAmount             float64          `bson:"total_amount"`
+	TotalAmount             models.Money     `bson:"total_amount"`
 }
 
 type CardEntitySQL struct {
@@ -37,6 +38,7 @@ type CardEntitySQL struct {
 	CardholderNameInCard    string                             `gorm:"size:255;not null"`
 	Since                   time.Time                          `gorm:"not null"`
 	ExpirationDate          time.Time                          `gorm:"not null"`
+	Status                  string                             `gorm:"size:20;not null;default:active"`
 	Bank                    BankEntitySQL                      `gorm:"foreignKey:BankID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	BankID                  uint                               `gorm:"index"`
 	CustomerID              uint                               `gorm:"index"`
@@ -60,6 +62,7 @@ func ToCardEntityRelational(card *models.Card) *CardEntitySQL {
 		CardholderNameInCard: card.CardholderNameInCard,
 		Since:                card.Since,
 		ExpirationDate:       card.ExpirationDate,
+		Status:               string(card.Status),
 		CreatedAt:            time.Now(),
 		UpdatedAt:            time.Now(),
 	}
@@ -73,6 +76,9 @@ func ToCardEntityNonRelational(card *models.Card) *CardEntityNonSQL {
 		CardholderNameInCard: card.CardholderNameInCard,
 		Since:                card.Since,
 		ExpirationDate:       card.ExpirationDate,
+		Status:               string(card.Status),
+		BankCuit:             card.Bank.Cuit,
+		CustomerCuit:         card.CustomerCuit,
 		CreatedAt:            time.Now(),
 		UpdatedAt:            time.Now(),
 	}
@@ -88,6 +94,7 @@ func ToCard[T any](cardEntity *T) *models.Card {
 			CardholderNameInCard:    v.CardholderNameInCard,
 			Since:                   v.Since,
 			ExpirationDate:          v.ExpirationDate,
+			Status:                  toCardStatus(v.Status),
 			Bank:                    *ToBank(&v.Bank),
 			PurchaseMonthlyPayments: *ConvertPurchaseMonthlyPaymentsList(&v.PurchaseMonthlyPayments),
 			PurchaseSinglePayments:  *ConvertPurchaseSinglePaymentList(&v.PurchaseSinglePayments),
@@ -99,9 +106,19 @@ func ToCard[T any](cardEntity *T) *models.Card {
 			CardholderNameInCard:    v.CardholderNameInCard,
 			Since:                   v.Since,
 			ExpirationDate:          v.ExpirationDate,
+			Status:                  toCardStatus(v.Status),
+			CustomerCuit:            v.CustomerCuit,
 			PurchaseMonthlyPayments: *ConvertPurchaseMonthlyPaymentListMongo(&v.PurchaseMonthlyPayments),
 			PurchaseSinglePayments:  *ConvertPurchaseSinglePaymentListMongo(&v.PurchaseSinglePayments),
 		}
 	}
 	return nil
 }
+
+// toCardStatus maps a stored status to the model. Cards stored before statuses existed are active.
+func toCardStatus(status string) models.CardStatus {
+	if status == "" {
+		return models.CardStatusActive
+	}
+	return models.CardStatus(status)
+}
@@ -74,6 +74,10 @@ func ToCustomerEntityNonRelational(customer *models.Customer) *CustomerEntityNon
 func ToCustomer[T any](customerEntity *T) *models.Customer {
 	switch v := any(customerEntity).(type) {
 	case *CustomerEntitySQL:
+		bankCuits := []string{}
+		for _, bank := range v.Banks {
+			bankCuits = append(bankCuits, bank.Cuit)
+		}
 		return &models.Customer{
 			CompleteName: v.CompleteName,
 			Dni:          v.Dni,
@@ -81,6 +85,7 @@ func ToCustomer[T any](customerEntity *T) *models.Customer {
 			Address:      v.Address,
 			Telephone:    v.Telephone,
 			EntryDate:    v.EntryDate,
+			BankCuits:    bankCuits,
 		}
 	case *CustomerEntityNonSQL:
 		return &models.Customer{
@@ -0,0 +1,76 @@
+/*
+ * Payment Registration System - Exchange Rate Entity (SQL and NoSQL)
+ * ------------------------------------------------------------------
+ *
+ * Description: Exchange rate entity holds the price in pesos of a foreign currency on a day.
+ * Both implementations store one row or document per currency and day.
+ *
+ * Created: Apr. 05, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package entities
+
+import (
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ExchangeRateEntityNonSQL is stored in the `exchange_rates` collection, unique by currency and date.
+type ExchangeRateEntityNonSQL struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`        // MongoDB primary key
+	Currency  string        `bson:"currency"`             // Foreign currency
+	Date      time.Time     `bson:"date"`                 // Day the rate applies to, at midnight UTC
+	Rate      models.Money  `bson:"rate"`                 // Price in pesos of one unit of the currency
+	UpdatedAt time.Time     `bson:"updated_at,omitempty"` // Update timestamp
+}
+
+type ExchangeRateEntitySQL struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement"`
+	Currency  string       `gorm:"size:3;not null;uniqueIndex:idx_EXCHANGE_RATES_currency_date"`
+	Date      time.Time    `gorm:"not null;uniqueIndex:idx_EXCHANGE_RATES_currency_date"`
+	Rate      models.Money `gorm:"type:decimal(15,2);not null"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"autoUpdateTime"`
+}
+
+func (ExchangeRateEntitySQL) TableName() string {
+	return "EXCHANGE_RATES"
+}
+
+// ------------ Mappers ------------	//
+
+func ToExchangeRateEntity(rate *models.ExchangeRate) *ExchangeRateEntitySQL {
+	return &ExchangeRateEntitySQL{
+		Currency: string(rate.Currency),
+		Date:     models.RateDay(rate.Date),
+		Rate:     rate.Rate,
+	}
+}
+
+func ToExchangeRateEntityNonSQL(rate *models.ExchangeRate) *ExchangeRateEntityNonSQL {
+	return &ExchangeRateEntityNonSQL{
+		Currency:  string(rate.Currency),
+		Date:      models.RateDay(rate.Date),
+		Rate:      rate.Rate,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func ToExchangeRate(entity *ExchangeRateEntitySQL) *models.ExchangeRate {
+	return &models.ExchangeRate{
+		Currency: models.Currency(entity.Currency),
+		Date:     models.RateDay(entity.Date),
+		Rate:     entity.Rate,
+	}
+}
+
+func ToExchangeRateNonSQL(entity *ExchangeRateEntityNonSQL) *models.ExchangeRate {
+	return &models.ExchangeRate{
+		Currency: models.Currency(entity.Currency),
+		Date:     models.RateDay(entity.Date),
+		Rate:     entity.Rate,
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Payment Registration System - Payment Entity (SQL and NoSQL)
+ * ------------------------------------------------------------
+ *
+ * Description: Payment entity holds a payment of a payment summary and its allocations to the quotas
+ * and single-payment purchases billed in it. The relational implementation references the allocated
+ * rows, the document implementation embeds the allocations with the keys of the billed items.
+ *
+ * Created: Apr. 14, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package entities
+
+import (
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PaymentEntityNonSQL is stored in the `payments` collection, unique by code.
+type PaymentEntityNonSQL struct {
+	ID          bson.ObjectID                   `bson:"_id,omitempty"`        // MongoDB primary key
+	Code        string                          `bson:"code"`                 // Unique code of the payment
+	CardNumber  string                          `bson:"card_number"`          // Card whose summary is paid
+	Month       int                             `bson:"month"`                // Month of the paid summary
+	Year        int                             `bson:"year"`                 // Year of the paid summary
+	Amount      models.Money                    `bson:"amount"`               // Amount paid
+	PaymentDate time.Time                       `bson:"payment_date"`         // Date the payment was made
+	Allocations []PaymentAllocationEntityNonSQL `bson:"allocations"`          // Parts of the amount applied to each billed item
+	CreatedAt   time.Time                       `bson:"created_at,omitempty"` // Creation timestamp
+	UpdatedAt   time.Time                       `bson:"updated_at,omitempty"` // Update timestamp
+}
+
+// PaymentAllocationEntityNonSQL is the part of a payment applied to a billed item, embedded in a payment.
+type PaymentAllocationEntityNonSQL struct {
+	PaymentVoucher string       `bson:"payment_voucher"`         // Payment voucher of the purchase
+	QuotaNumber    int          `bson:"quota_number,omitempty"`  // Number of the quota, missing for single-payment purchases
+	PurchaseDate   *time.Time   `bson:"purchase_date,omitempty"` // Date of the single-payment purchase, missing for quotas
+	Amount         models.Money `bson:"amount"`                  // Amount applied
+}
+
+type PaymentEntitySQL struct {
+	ID               uint                         `gorm:"primaryKey;autoIncrement"`
+	Code             string                       `gorm:"size:255;not null;uniqueIndex"`
+	CardID           uint                         `gorm:"index;not null"`
+	Card             CardEntitySQL                `gorm:"foreignKey:CardID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	PaymentSummaryID uint                         `gorm:"index;not null"`
+	PaymentSummary   PaymentSummaryEntitySQL      `gorm:"foreignKey:PaymentSummaryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount           models.Money                 `gorm:"type:decimal(15,2);not null"`
+	PaymentDate      time.Time                    `gorm:"not null"`
+	CreatedAt        time.Time                    `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time                    `gorm:"autoUpdateTime"`
+	Allocations      []PaymentAllocationEntitySQL `gorm:"foreignKey:PaymentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (PaymentEntitySQL) TableName() string {
+	return "PAYMENTS"
+}
+
+// PaymentAllocationEntitySQL is the part of a payment applied to either a quota or a single-payment purchase.
+type PaymentAllocationEntitySQL struct {
+	ID                      uint                            `gorm:"primaryKey;autoIncrement"`
+	PaymentID               uint                            `gorm:"index;not null"`
+	QuotaID                 *uint                           `gorm:"index"`
+	Quota                   *QuotaEntitySQL                 `gorm:"foreignKey:QuotaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	PurchaseSinglePaymentID *uint                           `gorm:"index"`
+	PurchaseSinglePayment   *PurchaseSinglePaymentEntitySQL `gorm:"foreignKey:PurchaseSinglePaymentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount                  models.Money                    `gorm:"type:decimal(15,2);not null"`
+}
+
+func (PaymentAllocationEntitySQL) TableName() string {
+	return "PAYMENT_ALLOCATIONS"
+}
+
+// ------------ Mappers ------------	//
+
+func ToPaymentEntityNonSQL(payment *models.Payment) *PaymentEntityNonSQL {
+	allocations := []PaymentAllocationEntityNonSQL{}
+	for _, src := range payment.Allocations {
+		allocations = append(allocations, PaymentAllocationEntityNonSQL{
+			PaymentVoucher: src.PaymentVoucher,
+			QuotaNumber:    src.QuotaNumber,
+			PurchaseDate:   src.PurchaseDate,
+			Amount:         src.Amount,
+		})
+	}
+	return &PaymentEntityNonSQL{
+		Code:        payment.Code,
+		CardNumber:  payment.CardNumber,
+		Month:       payment.Month,
+		Year:        payment.Year,
+		Amount:      payment.Amount,
+		PaymentDate: payment.PaymentDate,
+		Allocations: allocations,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func ToPaymentNonSQL(entity *PaymentEntityNonSQL) *models.Payment {
+	allocations := []models.PaymentAllocation{}
+	for _, src := range entity.Allocations {
+		allocation := models.PaymentAllocation{PaymentVoucher: src.PaymentVoucher, QuotaNumber: src.QuotaNumber, Amount: src.Amount}
+		if src.PurchaseDate != nil {
+			date := src.PurchaseDate.UTC()
+			allocation.PurchaseDate = &date
+		}
+		allocations = append(allocations, allocation)
+	}
+	return &models.Payment{
+		Code:        entity.Code,
+		CardNumber:  entity.CardNumber,
+		Month:       entity.Month,
+		Year:        entity.Year,
+		Amount:      entity.Amount,
+		PaymentDate: entity.PaymentDate.UTC(),
+		Allocations: allocations,
+	}
+}
+
+// ToPayment maps a payment, with its card, summary and allocated items preloaded, to a model.
+func ToPayment(entity *PaymentEntitySQL) *models.Payment {
+	allocations := []models.PaymentAllocation{}
+	for _, src := range entity.Allocations {
+		allocation := models.PaymentAllocation{Amount: src.Amount}
+		if src.Quota != nil {
+			allocation.PaymentVoucher = src.Quota.PurchaseMonthlyPaymentsEntity.PurchaseEntity.PaymentVoucher
+			allocation.QuotaNumber = src.Quota.Number
+		}
+		if src.PurchaseSinglePayment != nil {
+			date := src.PurchaseSinglePayment.PurchaseEntity.CreatedAt.UTC()
+			allocation.PaymentVoucher = src.PurchaseSinglePayment.PurchaseEntity.PaymentVoucher
+			allocation.PurchaseDate = &date
+		}
+		allocations = append(allocations, allocation)
+	}
+	return &models.Payment{
+		Code:        entity.Code,
+		CardNumber:  entity.Card.Number,
+		Month:       entity.PaymentSummary.Month,
+		Year:        entity.PaymentSummary.Year,
+		Amount:      entity.Amount,
+		PaymentDate: entity.PaymentDate.UTC(),
+		Allocations: allocations,
+	}
+}
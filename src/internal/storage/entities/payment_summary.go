@@ -9,42 +9,88 @@ import (
 
 // PaymentSummaryEntity represents a summary of payments associated with a card.
 type PaymentSummaryEntityNonSQL struct {
-	ID                  bson.ObjectID `bson:"_id,omitempty"`         // MongoDB primary key
-	Code                string        `bson:"code"`                  // Unique code for the payment summary
-	Month               int           `bson:"month"`                 // Payment month
-	Year                int           `bson:"year"`                  // Payment year
-	FirstExpiration     time.Time     `bson:"first_expiration"`      // First expiration date
-	SecondExpiration    time.Time     `bson:"second_expiration"`     // Second expiration date
-	SurchargePercentage float64       `bson:"surcharge_percentage"`  // Surcharge percentage
-	TotalPrice          float64       `bson:"total_price"`           // Total price
-	CardNumber          string        `bson:"card_number,omitempty"` // Reference to the associated card
-	CreatedAt           time.Time     `bson:"created_at,omitempty"`  // Creation timestamp
-	UpdatedAt           time.Time     `bson:"updated_at,omitempty"`  // Update timestamp
+	ID                  bson.ObjectID                  `bson:"_id,omitempty"`         // MongoDB primary key
+	Code                string                         `bson:"code"`                  // Unique code for the payment summary
+	Month               int                            `bson:"month"`                 // Payment month
+	Year                int                            `bson:"year"`                  // Payment year
+	FirstExpiration     time.Time                      `bson:"first_expiration"`      // First expiration date
+	SecondExpiration    time.Time                      `bson:"second_expiration"`     // Second expiration date
+	SurchargePercentage models.Percentage              `bson:"surcharge_percentage"`  // Surcharge percentage
+	TotalPrice          models.Money                   `bson:"total_price"`           // Total price
+	Subtotals           []CurrencySubtotalEntityNonSQL `bson:"subtotals,omitempty"`   // Amounts billed in each currency
+	CardNumber          string                         `bson:"card_number,omitempty"` // Reference to the associated card
+	CreatedAt           time.Time                      `bson:"created_at,omitempty"`  // Creation timestamp
+	UpdatedAt           time.Time                      `bson:"updated_at,omitempty"`  // Update timestamp
+
+	// Snapshot of the purchases billed in the summary, so that it does not change once closed
+	SinglePayments  []PurchaseSinglePaymentEntityNonSQL   `bson:"single_payments,omitempty"`
+	MonthlyPayments []PurchaseMonthlyPaymentsEntityNonSQL `bson:"monthly_payments,omitempty"`
+	Quotas          []DueQuotaEntityNonSQL                `bson:"quotas,omitempty"`
 }
 
+// PaymentSummaryEntitySQL is a payment summary of a card. A card has at most one summary per month.
 type PaymentSummaryEntitySQL struct {
-	ID                  uint          `gorm:"primaryKey;autoIncrement"`
-	Code                string        `gorm:"size:255;not null"`
-	Month               int           `gorm:"not null"`
-	Year                int           `gorm:"not null"`
-	FirstExpiration     time.Time     `gorm:"not null"`
-	SecondExpiration    time.Time     `gorm:"not null"`
-	SurchargePercentage float64       `gorm:"not null"`
-	TotalPrice          float64       `gorm:"not null"`
-	CardID              uint          `gorm:"not null"`
-	Card                CardEntitySQL `gorm:"foreignKey:CardID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	CreatedAt           time.Time     `gorm:"autoCreateTime"`
-	UpdatedAt           time.Time     `gorm:"autoUpdateTime"`
+	ID                  uint              `gorm:"primaryKey;autoIncrement"`
+	Code                string            `gorm:"size:255;not null"`
+	Month               int               `gorm:"not null;uniqueIndex:,composite:card_month_year,priority:2"`
+	Year                int               `gorm:"not null;uniqueIndex:,composite:card_month_year,priority:3"`
+	FirstExpiration     time.Time         `gorm:"not null"`
+	SecondExpiration    time.Time         `gorm:"not null"`
+	SurchargePercentage models.Percentage `gorm:"type:decimal(7,2);not null"`
+	TotalPrice          models.Money      `gorm:"type:decimal(15,2);not null"`
+	CardID              uint              `gorm:"not null;uniqueIndex:,composite:card_month_year,priority:1"`
+	Card                CardEntitySQL     `gorm:"foreignKey:CardID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CreatedAt           time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time         `gorm:"autoUpdateTime"`
+
+	// Purchases billed in the summary
+	SinglePayments  []PurchaseSinglePaymentEntitySQL   `gorm:"many2many:PAYMENT_SUMMARY_SINGLE_PAYMENTS;"`
+	MonthlyPayments []PurchaseMonthlyPaymentsEntitySQL `gorm:"many2many:PAYMENT_SUMMARY_MONTHLY_PAYMENTS;"`
+	Quotas          []QuotaEntitySQL                   `gorm:"many2many:PAYMENT_SUMMARY_QUOTAS;"`
+
+	Subtotals []PaymentSummarySubtotalEntitySQL `gorm:"foreignKey:PaymentSummaryID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
 func (PaymentSummaryEntitySQL) TableName() string {
 	return "PAYMENT_SUMMARIES"
 }
 
+// CurrencySubtotalEntityNonSQL is the amount billed in a currency, embedded in a payment summary.
+type CurrencySubtotalEntityNonSQL struct {
+	Currency     string       `bson:"currency"`      // Currency of the purchases
+	Subtotal     models.Money `bson:"subtotal"`      // Amount billed in the currency
+	ExchangeRate models.Money `bson:"exchange_rate"` // Price in pesos of one unit on the closing date
+	Total        models.Money `bson:"total"`         // Subtotal converted to pesos
+}
+
+// PaymentSummarySubtotalEntitySQL is the amount billed in a currency in a payment summary.
+type PaymentSummarySubtotalEntitySQL struct {
+	ID               uint         `gorm:"primaryKey;autoIncrement"`
+	PaymentSummaryID uint         `gorm:"index;not null"`
+	Currency         string       `gorm:"size:3;not null"`
+	Subtotal         models.Money `gorm:"type:decimal(15,2);not null"`
+	ExchangeRate     models.Money `gorm:"type:decimal(15,2);not null"`
+	Total            models.Money `gorm:"type:decimal(15,2);not null"`
+}
+
+func (PaymentSummarySubtotalEntitySQL) TableName() string {
+	return "PAYMENT_SUMMARY_SUBTOTALS"
+}
+
 // ------------ Mappers ------------	//
 
 // Take a model and convert it to a PaymentSummaryEntity for relational storage
 func ToPaymentSummaryEntityRelational(paymentSummary *models.PaymentSummary) *PaymentSummaryEntitySQL {
+	var subtotals []PaymentSummarySubtotalEntitySQL
+	for _, src := range paymentSummary.Subtotals {
+		subtotals = append(subtotals, PaymentSummarySubtotalEntitySQL{
+			Currency:     string(src.Currency),
+			Subtotal:     src.Subtotal,
+			ExchangeRate: src.ExchangeRate,
+			Total:        src.Total,
+		})
+	}
+
 	return &PaymentSummaryEntitySQL{
 		Code:                paymentSummary.Code,
 		Month:               paymentSummary.Month,
@@ -53,6 +99,7 @@ func ToPaymentSummaryEntityRelational(paymentSummary *models.PaymentSummary) *Pa
 		SecondExpiration:    paymentSummary.SecondExpiration,
 		SurchargePercentage: paymentSummary.SurchargePercentage,
 		TotalPrice:          paymentSummary.TotalPrice,
+		Subtotals:           subtotals,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
@@ -60,6 +107,28 @@ func ToPaymentSummaryEntityRelational(paymentSummary *models.PaymentSummary) *Pa
 
 // Take a model and convert it to a PaymentSummaryEntity for non-relational storage
 func ToPaymentSummaryEntityNonRelational(paymentSummary *models.PaymentSummary) *PaymentSummaryEntityNonSQL {
+	var singlePayments []PurchaseSinglePaymentEntityNonSQL
+	for _, src := range paymentSummary.SinglePayments {
+		singlePayments = append(singlePayments, *ToPurchaseSinglePaymentEntityNonSQL(&src, paymentSummary.Card.Number))
+	}
+	var monthlyPayments []PurchaseMonthlyPaymentsEntityNonSQL
+	for _, src := range paymentSummary.MonthlyPayments {
+		monthlyPayments = append(monthlyPayments, *ToPurchaseMonthlyPaymentsEntityNonSQL(&src, paymentSummary.Card.Number))
+	}
+	var quotas []DueQuotaEntityNonSQL
+	for _, src := range paymentSummary.Quotas {
+		quotas = append(quotas, *ToDueQuotaEntityNonSQL(&src))
+	}
+	var subtotals []CurrencySubtotalEntityNonSQL
+	for _, src := range paymentSummary.Subtotals {
+		subtotals = append(subtotals, CurrencySubtotalEntityNonSQL{
+			Currency:     string(src.Currency),
+			Subtotal:     src.Subtotal,
+			ExchangeRate: src.ExchangeRate,
+			Total:        src.Total,
+		})
+	}
+
 	return &PaymentSummaryEntityNonSQL{
 		Code:                paymentSummary.Code,
 		Month:               paymentSummary.Month,
@@ -68,8 +137,13 @@ func ToPaymentSummaryEntityNonRelational(paymentSummary *models.PaymentSummary)
 		SecondExpiration:    paymentSummary.SecondExpiration,
 		SurchargePercentage: paymentSummary.SurchargePercentage,
 		TotalPrice:          paymentSummary.TotalPrice,
+		Subtotals:           subtotals,
+		CardNumber:          paymentSummary.Card.Number,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
+		SinglePayments:      singlePayments,
+		MonthlyPayments:     monthlyPayments,
+		Quotas:              quotas,
 	}
 }
 
@@ -77,6 +151,10 @@ func ToPaymentSummaryEntityNonRelational(paymentSummary *models.PaymentSummary)
 func ToPaymentSummary[T any](paymentSummaryEntity *T) *models.PaymentSummary {
 	switch v := any(paymentSummaryEntity).(type) {
 	case *PaymentSummaryEntitySQL:
+		subtotals := []models.CurrencySubtotal{}
+		for _, src := range v.Subtotals {
+			subtotals = append(subtotals, models.CurrencySubtotal{Currency: models.Currency(src.Currency), Subtotal: src.Subtotal, ExchangeRate: src.ExchangeRate, Total: src.Total})
+		}
 		return &models.PaymentSummary{
 			Code:                v.Code,
 			Month:               v.Month,
@@ -85,8 +163,17 @@ func ToPaymentSummary[T any](paymentSummaryEntity *T) *models.PaymentSummary {
 			SecondExpiration:    v.SecondExpiration,
 			SurchargePercentage: v.SurchargePercentage,
 			TotalPrice:          v.TotalPrice,
+			Subtotals:           subtotals,
+			SinglePayments:      *ConvertPurchaseSinglePaymentList(&v.SinglePayments),
+			MonthlyPayments:     *ConvertPurchaseMonthlyPaymentsList(&v.MonthlyPayments),
+			Quotas:              *ConvertDueQuotaList(&v.Quotas),
+			Card:                *ToCard(&v.Card),
 		}
 	case *PaymentSummaryEntityNonSQL:
+		subtotals := []models.CurrencySubtotal{}
+		for _, src := range v.Subtotals {
+			subtotals = append(subtotals, models.CurrencySubtotal{Currency: models.Currency(src.Currency), Subtotal: src.Subtotal, ExchangeRate: src.ExchangeRate, Total: src.Total})
+		}
 		return &models.PaymentSummary{
 			Code:                v.Code,
 			Month:               v.Month,
@@ -95,6 +182,11 @@ func ToPaymentSummary[T any](paymentSummaryEntity *T) *models.PaymentSummary {
 			SecondExpiration:    v.SecondExpiration,
 			SurchargePercentage: v.SurchargePercentage,
 			TotalPrice:          v.TotalPrice,
+			Subtotals:           subtotals,
+			SinglePayments:      *ConvertPurchaseSinglePaymentListMongo(&v.SinglePayments),
+			MonthlyPayments:     *ConvertPurchaseMonthlyPaymentListMongo(&v.MonthlyPayments),
+			Quotas:              *ConvertDueQuotaListMongo(&v.Quotas),
+			Card:                models.Card{Number: v.CardNumber},
 		}
 	default:
 		return nil
@@ -25,7 +25,8 @@ type FinancingEntityNonSQL struct {
 	ID              bson.ObjectID         `bson:"_id,omitempty"`
 	PromotionEntity PromotionEntityNonSQL `bson:"promotion_entity"`
 	NumberOfQuotas  int                   `bson:"number_of_quotas"`
-	Interest        float64               `bson:"interest"`
+	Interest        models.Percentage     `bson:"interest"`
+	InterestModel   string                `bson:"interest_model,omitempty"`
 	IsDeleted       bool                  `bson:"is_deleted"`
 	BankID          bson.ObjectID         `bson:"bank_id"`
 	CreatedAt       time.Time             `bson:"created_at,omitempty"`
@@ -34,10 +35,15 @@ type FinancingEntityNonSQL struct {
 
 // DiscountEntityNonSQL represents discount promotions in NoSQL
 type DiscountEntityNonSQL struct {
+	ID                 bson.ObjectID         `bson:"_id,omitempty"`
 	PromotionEntity    PromotionEntityNonSQL `bson:"promotion_entity"`
-	DiscountPercentage float64               `bson:"discount_percentage"`
-	PriceCap           float64               `bson:"price_cap,omitempty"`
+	DiscountPercentage models.Percentage     `bson:"discount_percentage"`
+	PriceCap           models.Money          `bson:"price_cap,omitempty"`
 	OnlyCash           bool                  `bson:"only_cash"`
+	IsDeleted          bool                  `bson:"is_deleted"`
+	BankID             bson.ObjectID         `bson:"bank_id,omitempty"`
+	CreatedAt          time.Time             `bson:"created_at,omitempty"`
+	UpdatedAt          time.Time             `bson:"updated_at,omitempty"`
 }
 
 // PaymentVoucherCountNonSQL represents voucher usage counts in NoSQL
@@ -68,18 +74,19 @@ type PromotionEntitySQL struct {
 // DiscountEntitySQL represents discount promotions in SQL
 type DiscountEntitySQL struct {
 	PromotionEntitySQL `gorm:"embedded"`
-	ID                 uint    `gorm:"primaryKey;autoIncrement"`
-	DiscountPercentage float64 `gorm:"not null"`
-	PriceCap           float64 `gorm:"not null;default:0"`
-	OnlyCash           bool    `gorm:"default:false;not null"`
+	ID                 uint              `gorm:"primaryKey;autoIncrement"`
+	DiscountPercentage models.Percentage `gorm:"type:decimal(7,2);not null"`
+	PriceCap           models.Money      `gorm:"type:decimal(15,2);not null;default:0"`
+	OnlyCash           bool              `gorm:"default:false;not null"`
 }
 
 // FinancingEntitySQL represents installment-based promotions in SQL
 type FinancingEntitySQL struct {
 	PromotionEntitySQL `gorm:"embedded"`
-	ID                 uint    `gorm:"primaryKey;autoIncrement"`
-	NumberOfQuotas     int     `gorm:"not null"`
-	Interest           float64 `gorm:"not null;default:0"`
+	ID                 uint              `gorm:"primaryKey;autoIncrement"`
+	NumberOfQuotas     int               `gorm:"not null"`
+	Interest           models.Percentage `gorm:"type:decimal(7,2);not null;default:0"`
+	InterestModel      string            `gorm:"size:10;not null;default:flat"`
 }
 
 // PaymentVoucherCountSQL represents voucher usage counts in SQL
@@ -134,23 +141,9 @@ func ToPromotionNonSQL(promotionEntity *PromotionEntityNonSQL) *models.Promotion
 	}
 }
 
-// Models -> SQL
 // Models -> SQL
 func ToPromotionEntity(promotion *models.Promotion, bankId uint) *PromotionEntitySQL {
-	defaultTime := time.Time{} // Zero time
-
-	// Parse the date strings into time.Time, use default time if parsing fails
-	startDate, err := time.Parse(time.RFC3339, promotion.ValidityStartDate)
-	if err != nil {
-		logger.Warn("Invalid format for ValidityStartDate '%s', using default time: %v", promotion.ValidityStartDate, err)
-		startDate = defaultTime
-	}
-
-	endDate, err := time.Parse(time.RFC3339, promotion.ValidityEndDate)
-	if err != nil {
-		logger.Warn("Invalid format for ValidityEndDate '%s', using default time: %v", promotion.ValidityEndDate, err)
-		endDate = defaultTime
-	}
+	startDate, endDate := parsePromotionDates(promotion)
 
 	return &PromotionEntitySQL{
 		Code:              promotion.Code,
@@ -171,6 +164,7 @@ func ToFinancing(financingEntity *FinancingEntitySQL) *models.Financing {
 		Promotion:      *ToPromotion(&financingEntity.PromotionEntitySQL),
 		NumberOfQuotas: financingEntity.NumberOfQuotas,
 		Interest:       financingEntity.Interest,
+		InterestModel:  models.InterestModel(financingEntity.InterestModel).OrFlat(),
 	}
 }
 
@@ -180,6 +174,7 @@ func ToFinancingNonSQL(financingEntity *FinancingEntityNonSQL) *models.Financing
 		Promotion:      *ToPromotionNonSQL(&financingEntity.PromotionEntity),
 		NumberOfQuotas: financingEntity.NumberOfQuotas,
 		Interest:       financingEntity.Interest,
+		InterestModel:  models.InterestModel(financingEntity.InterestModel).OrFlat(),
 	}
 }
 
@@ -189,7 +184,42 @@ func ToFinancingEntity(financing *models.Financing, bankId uint) *FinancingEntit
 		PromotionEntitySQL: *ToPromotionEntity(&financing.Promotion, bankId),
 		NumberOfQuotas:     financing.NumberOfQuotas,
 		Interest:           financing.Interest,
+		InterestModel:      string(financing.InterestModel.OrFlat()),
+	}
+}
+
+// Models -> NoSQL
+func ToPromotionEntityNonSQL(promotion *models.Promotion) *PromotionEntityNonSQL {
+	startDate, endDate := parsePromotionDates(promotion)
+
+	return &PromotionEntityNonSQL{
+		Code:              promotion.Code,
+		PromotionTitle:    promotion.PromotionTitle,
+		NameStore:         promotion.NameStore,
+		CuitStore:         promotion.CuitStore,
+		ValidityStartDate: startDate,
+		ValidityEndDate:   endDate,
+		Comments:          promotion.Comments,
+	}
+}
+
+// parsePromotionDates parses the RFC3339 validity dates of a promotion, using the zero time for malformed values.
+func parsePromotionDates(promotion *models.Promotion) (time.Time, time.Time) {
+	defaultTime := time.Time{} // Zero time
+
+	startDate, err := time.Parse(time.RFC3339, promotion.ValidityStartDate)
+	if err != nil {
+		logger.Warn("Invalid format for ValidityStartDate '%s', using default time: %v", promotion.ValidityStartDate, err)
+		startDate = defaultTime
+	}
+
+	endDate, err := time.Parse(time.RFC3339, promotion.ValidityEndDate)
+	if err != nil {
+		logger.Warn("Invalid format for ValidityEndDate '%s', using default time: %v", promotion.ValidityEndDate, err)
+		endDate = defaultTime
 	}
+
+	return startDate, endDate
 }
 
 // SQL -> Models
@@ -211,3 +241,27 @@ func ToDiscountNonSQL(discountEntity *DiscountEntityNonSQL) *models.Discount {
 		OnlyCash:           discountEntity.OnlyCash,
 	}
 }
+
+// Models -> SQL
+func ToDiscountEntity(discount *models.Discount, bankId uint) *DiscountEntitySQL {
+	return &DiscountEntitySQL{
+		PromotionEntitySQL: *ToPromotionEntity(&discount.Promotion, bankId),
+		DiscountPercentage: discount.DiscountPercentage,
+		PriceCap:           discount.PriceCap,
+		OnlyCash:           discount.OnlyCash,
+	}
+}
+
+// Models -> NoSQL
+func ToDiscountEntityNonSQL(discount *models.Discount, bankID bson.ObjectID) *DiscountEntityNonSQL {
+	return &DiscountEntityNonSQL{
+		PromotionEntity:    *ToPromotionEntityNonSQL(&discount.Promotion),
+		DiscountPercentage: discount.DiscountPercentage,
+		PriceCap:           discount.PriceCap,
+		OnlyCash:           discount.OnlyCash,
+		IsDeleted:          false,
+		BankID:             bankID,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+}
@@ -9,70 +9,66 @@ import (
 
 // PurchaseEntity represents the base details of a purchase.
 type PurchaseEntityNonSQL struct {
-	PaymentVoucher string    `bson:"payment_voucher"`       // Payment voucher code
-	Store          string    `bson:"store"`                 // Store name
-	CuitStore      string    `bson:"cuit_store"`            // Store CUIT
-	Amount         float64   `bson:"amount"`                // Purchase amount
-	FinalAmount    float64   `bson:"final_amount"`          // Final amount after adjustments
-	CreatedAt      time.Time `bson:"created_at,omitempty"`  // Creation timestamp
-	UpdatedAt      time.Time `bson:"updated_at,omitempty"`  // Update timestamp
-	CardNumber     string    `bson:"card_number,omitempty"` // Reference to the associated card
+	PaymentVoucher string       `bson:"payment_voucher"`          // Payment voucher code
+	Store          string       `bson:"store"`                    // Store name
+	CuitStore      string       `bson:"cuit_store"`               // Store CUIT
+	Amount         models.Money `bson:"amount"`                   // Purchase amount
+	FinalAmount    models.Money `bson:"final_amount"`             // Final amount after adjustments
+	Currency       string       `bson:"currency,omitempty"`       // Currency of the amounts, pesos when missing
+	CreatedAt      time.Time    `bson:"created_at,omitempty"`     // Creation timestamp
+	UpdatedAt      time.Time    `bson:"updated_at,omitempty"`     // Update timestamp
+	CardNumber     string       `bson:"card_number,omitempty"`    // Reference to the associated card
+	PromotionCode  string       `bson:"promotion_code,omitempty"` // Code of the applied promotion
 }
 
 // PurchaseSinglePaymentEntity represents a single-payment purchase.
 type PurchaseSinglePaymentEntityNonSQL struct {
-	ID             bson.ObjectID        `bson:"_id,omitempty"`  // MongoDB primary key
-	PurchaseEntity PurchaseEntityNonSQL `bson:"purchase"`       // Embedded base purchase details
-	StoreDiscount  float64              `bson:"store_discount"` // Discount applied by the store
+	ID             bson.ObjectID        `bson:"_id,omitempty"`         // MongoDB primary key
+	PurchaseEntity PurchaseEntityNonSQL `bson:"purchase"`              // Embedded base purchase details
+	StoreDiscount  models.Percentage    `bson:"store_discount"`        // Discount applied by the store
+	PaidAmount     models.Money         `bson:"paid_amount,omitempty"` // Amount paid of the purchase, in pesos
 }
 
 // PurchaseMonthlyPaymentsEntity represents a monthly installment purchase.
 type PurchaseMonthlyPaymentsEntityNonSQL struct {
-	ID             bson.ObjectID        `bson:"_id,omitempty"`    // MongoDB primary key
-	PurchaseEntity PurchaseEntityNonSQL `bson:"purchase"`         // Embedded base purchase details
-	Interest       float64              `bson:"interest"`         // Interest rate for the installments
-	NumberOfQuotas int                  `bson:"number_of_quotas"` // Number of monthly quotas
-	Quotas         []QuotaEntityNonSQL  `bson:"quotas,omitempty"` // Embedded list of quotas
+	ID             bson.ObjectID        `bson:"_id,omitempty"`            // MongoDB primary key
+	PurchaseEntity PurchaseEntityNonSQL `bson:"purchase"`                 // Embedded base purchase details
+	Interest       models.Percentage    `bson:"interest"`                 // Interest rate for the installments
+	InterestModel  string               `bson:"interest_model,omitempty"` // Interest model of the quotas, flat when missing
+	NumberOfQuotas int                  `bson:"number_of_quotas"`         // Number of monthly quotas
+	Quotas         []QuotaEntityNonSQL  `bson:"quotas,omitempty"`         // Embedded list of quotas
 }
 
+// PurchaseEntitySQL holds the columns shared by both purchase tables. A voucher is unique among the purchases of a card in each table.
 type PurchaseEntitySQL struct {
-	PaymentVoucher string    `gorm:"size:255;not null"`
-	Store          string    `gorm:"size:255;not null"`
-	CuitStore      string    `gorm:"size:20;not null"`
-	Amount         float64   `gorm:"not null"`
-	FinalAmount    float64   `gorm:"not null"`
-	CreatedAt      time.Time `gorm:"autoCreateTime"`
-	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
-	CardID         uint      `gorm:"index;not null"`
+	PaymentVoucher string       `gorm:"size:255;not null;uniqueIndex:,composite:card_payment_voucher,priority:2"`
+	Store          string       `gorm:"size:255;not null"`
+	CuitStore      string       `gorm:"size:20;not null"`
+	Amount         models.Money `gorm:"type:decimal(15,2);not null"`
+	FinalAmount    models.Money `gorm:"type:decimal(15,2);not null"`
+	Currency       string       `gorm:"size:3;not null;default:ARS"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time    `gorm:"autoUpdateTime"`
+	CardID         uint         `gorm:"index;not null;uniqueIndex:,composite:card_payment_voucher,priority:1"`
+	PromotionCode  string       `gorm:"size:255"`
 }
 
 type PurchaseSinglePaymentEntitySQL struct {
 	ID             uint              `gorm:"primaryKey;autoIncrement"`
 	PurchaseEntity PurchaseEntitySQL `gorm:"embedded"`
-	StoreDiscount  float64           `gorm:"not null"`
+	StoreDiscount  models.Percentage `gorm:"type:decimal(7,2);not null"`
+	PaidAmount     models.Money      `gorm:"type:decimal(15,2);not null;default:0"`
 }
 
 type PurchaseMonthlyPaymentsEntitySQL struct {
 	ID             uint              `gorm:"primaryKey;autoIncrement"`
 	PurchaseEntity PurchaseEntitySQL `gorm:"embedded"`
-	Interest       float64           `gorm:"not null"`
+	Interest       models.Percentage `gorm:"type:decimal(7,2);not null"`
+	InterestModel  string            `gorm:"size:10;not null;default:flat"`
 	NumberOfQuotas int               `gorm:"not null"`
 	Quotas         []QuotaEntitySQL  `gorm:"foreignKey:PurchaseMonthlyPaymentsEntityID"`
 }
 
-type PaymentSummaryNoSQL struct {
-	Number          string                                `bson:"number"`
-	CCV             string                                `bson:"ccv"`
-	CardholderName  string                                `bson:"cardholder_name_in_card"`
-	BankCuit        string                                `bson:"bank_cuit"`
-	CustomerCuit    string                                `bson:"customer_cuit"`
-	CreatedAt       time.Time                             `bson:"created_at"`
-	UpdatedAt       time.Time                             `bson:"updated_at"`
-	SinglePayments  []PurchaseSinglePaymentEntityNonSQL   `bson:"single_payments"`
-	MonthlyPayments []PurchaseMonthlyPaymentsEntityNonSQL `bson:"monthly_payments"`
-	TotalPrice      float64                               `bson:"total_price"`
-}
-
 func (PurchaseSinglePaymentEntitySQL) TableName() string {
 	return "PURCHASE_SINGLE_PAYMENTS"
 }
@@ -87,6 +83,7 @@ func ToPurchaseSinglePayment(entity *PurchaseSinglePaymentEntitySQL) *models.Pur
 	return &models.PurchaseSinglePayment{
 		Purchase:      *toPurchase(&entity.PurchaseEntity),
 		StoreDiscount: entity.StoreDiscount,
+		PaidAmount:    entity.PaidAmount,
 	}
 }
 
@@ -94,6 +91,7 @@ func ToPurchaseSinglePaymentNonSQL(entity *PurchaseSinglePaymentEntityNonSQL) *m
 	return &models.PurchaseSinglePayment{
 		Purchase:      *toPurchaseNonSQL(&entity.PurchaseEntity),
 		StoreDiscount: entity.StoreDiscount,
+		PaidAmount:    entity.PaidAmount,
 	}
 }
 
@@ -101,6 +99,7 @@ func ToPurchaseSinglePaymentEntity(model *models.PurchaseSinglePayment) *Purchas
 	return &PurchaseSinglePaymentEntitySQL{
 		PurchaseEntity: *ToPurchaseEntity(&model.Purchase),
 		StoreDiscount:  model.StoreDiscount,
+		PaidAmount:     model.PaidAmount,
 	}
 }
 
@@ -114,6 +113,7 @@ func ToPurchaseMonthlyPayments(entity *PurchaseMonthlyPaymentsEntitySQL) *models
 		Purchase:       *toPurchase(&entity.PurchaseEntity),
 		NumberOfQuotas: entity.NumberOfQuotas,
 		Interest:       entity.Interest,
+		InterestModel:  models.InterestModel(entity.InterestModel).OrFlat(),
 		Quota:          quotas,
 	}
 }
@@ -128,6 +128,7 @@ func ToPurchaseMonthlyPaymentsNonSQL(entity *PurchaseMonthlyPaymentsEntityNonSQL
 		Purchase:       *toPurchaseNonSQL(&entity.PurchaseEntity),
 		NumberOfQuotas: entity.NumberOfQuotas,
 		Interest:       entity.Interest,
+		InterestModel:  models.InterestModel(entity.InterestModel).OrFlat(),
 		Quota:          quotas,
 	}
 }
@@ -140,6 +141,7 @@ func ToPurchaseMonthlyPaymentsEntity(model *models.PurchaseMonthlyPayment) *Purc
 	return &PurchaseMonthlyPaymentsEntitySQL{
 		PurchaseEntity: *ToPurchaseEntity(&model.Purchase),
 		Interest:       model.Interest,
+		InterestModel:  string(model.InterestModel.OrFlat()),
 		NumberOfQuotas: model.NumberOfQuotas,
 		Quotas:         quotas,
 	}
@@ -152,6 +154,46 @@ func ToPurchaseEntity(model *models.Purchase) *PurchaseEntitySQL {
 		CuitStore:      model.CuitStore,
 		Amount:         model.Amount,
 		FinalAmount:    model.FinalAmount,
+		Currency:       string(model.Currency.OrBase()),
+		CreatedAt:      model.PurchaseDate,
+		PromotionCode:  model.PromotionCode,
+	}
+}
+
+func ToPurchaseSinglePaymentEntityNonSQL(model *models.PurchaseSinglePayment, cardNumber string) *PurchaseSinglePaymentEntityNonSQL {
+	return &PurchaseSinglePaymentEntityNonSQL{
+		PurchaseEntity: *ToPurchaseEntityNonSQL(&model.Purchase, cardNumber),
+		StoreDiscount:  model.StoreDiscount,
+		PaidAmount:     model.PaidAmount,
+	}
+}
+
+func ToPurchaseMonthlyPaymentsEntityNonSQL(model *models.PurchaseMonthlyPayment, cardNumber string) *PurchaseMonthlyPaymentsEntityNonSQL {
+	var quotas []QuotaEntityNonSQL
+	for _, src := range model.Quota {
+		quotas = append(quotas, *ToQuotaEntityNonSQL(&src))
+	}
+	return &PurchaseMonthlyPaymentsEntityNonSQL{
+		PurchaseEntity: *ToPurchaseEntityNonSQL(&model.Purchase, cardNumber),
+		Interest:       model.Interest,
+		InterestModel:  string(model.InterestModel.OrFlat()),
+		NumberOfQuotas: model.NumberOfQuotas,
+		Quotas:         quotas,
+	}
+}
+
+func ToPurchaseEntityNonSQL(model *models.Purchase, cardNumber string) *PurchaseEntityNonSQL {
+	return &PurchaseEntityNonSQL{
+		PaymentVoucher: model.PaymentVoucher,
+		Store:          model.Store,
+		CuitStore:      model.CuitStore,
+		Amount:         model.Amount,
+		FinalAmount:    model.FinalAmount,
+		Currency:       string(model.Currency.OrBase()),
+		CreatedAt:      model.PurchaseDate,
+		PromotionCode:  model.PromotionCode,
+		UpdatedAt:      time.Now(),
+		CardNumber:     cardNumber,
 	}
 }
 
@@ -162,6 +204,9 @@ func toPurchase(entity *PurchaseEntitySQL) *models.Purchase {
 		CuitStore:      entity.CuitStore,
 		Amount:         entity.Amount,
 		FinalAmount:    entity.FinalAmount,
+		Currency:       models.Currency(entity.Currency).OrBase(),
+		PurchaseDate:   entity.CreatedAt,
+		PromotionCode:  entity.PromotionCode,
 	}
 }
 
@@ -172,6 +217,9 @@ func toPurchaseNonSQL(entity *PurchaseEntityNonSQL) *models.Purchase {
 		CuitStore:      entity.CuitStore,
 		Amount:         entity.Amount,
 		FinalAmount:    entity.FinalAmount,
+		Currency:       models.Currency(entity.Currency).OrBase(),
+		PurchaseDate:   entity.CreatedAt,
+		PromotionCode:  entity.PromotionCode,
 	}
 }
 
@@ -11,9 +11,10 @@ import (
 type QuotaEntityNonSQL struct {
 	ID                              bson.ObjectID `bson:"_id,omitempty"`                 // MongoDB primary key
 	Number                          int           `bson:"number"`                        // Quota number
-	Price                           float64       `bson:"price"`                         // Price of the quota
+	Price                           models.Money  `bson:"price"`                         // Price of the quota
 	Month                           string        `bson:"month"`                         // Month of the quota (e.g., "01" for January)
 	Year                            string        `bson:"year"`                          // Year of the quota (e.g., "2024")
+	PaidAmount                      models.Money  `bson:"paid_amount,omitempty"`         // Amount paid of the quota
 	PurchaseMonthlyPaymentsEntityID bson.ObjectID `bson:"purchase_monthly_id,omitempty"` // Reference to the parent PurchaseMonthlyPaymentsEntity
 	CreatedAt                       time.Time     `bson:"created_at,omitempty"`          // Creation timestamp
 	UpdatedAt                       time.Time     `bson:"updated_at,omitempty"`          // Update timestamp
@@ -22,9 +23,10 @@ type QuotaEntityNonSQL struct {
 type QuotaEntitySQL struct {
 	ID                              uint                             `gorm:"primaryKey;autoIncrement"`
 	Number                          int                              `gorm:"not null"`
-	Price                           float64                          `gorm:"not null"`
+	Price                           models.Money                     `gorm:"type:decimal(15,2);not null"`
 	Month                           string                           `gorm:"size:2;not null"`
 	Year                            string                           `gorm:"size:4;not null"`
+	PaidAmount                      models.Money                     `gorm:"type:decimal(15,2);not null;default:0"`
 	PurchaseMonthlyPaymentsEntityID uint                             `gorm:"index;not null"`
 	PurchaseMonthlyPaymentsEntity   PurchaseMonthlyPaymentsEntitySQL `gorm:"foreignKey:PurchaseMonthlyPaymentsEntityID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	CreatedAt                       time.Time                        `gorm:"autoCreateTime"`
@@ -39,27 +41,99 @@ func (QuotaEntitySQL) TableName() string {
 
 func ToQuotaEntity(model *models.Quota) *QuotaEntitySQL {
 	return &QuotaEntitySQL{
-		Number: model.Number,
-		Price:  model.Price,
-		Month:  model.Month,
-		Year:   model.Year,
+		Number:     model.Number,
+		Price:      model.Price,
+		Month:      model.Month,
+		Year:       model.Year,
+		PaidAmount: model.PaidAmount,
+	}
+}
+
+func ToQuotaEntityNonSQL(model *models.Quota) *QuotaEntityNonSQL {
+	return &QuotaEntityNonSQL{
+		Number:     model.Number,
+		Price:      model.Price,
+		Month:      model.Month,
+		Year:       model.Year,
+		PaidAmount: model.PaidAmount,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}
 }
 
 func ToQuota(entity *QuotaEntitySQL) *models.Quota {
 	return &models.Quota{
-		Number: entity.Number,
-		Price:  entity.Price,
-		Month:  entity.Month,
-		Year:   entity.Year,
+		Number:     entity.Number,
+		Price:      entity.Price,
+		Month:      entity.Month,
+		Year:       entity.Year,
+		PaidAmount: entity.PaidAmount,
 	}
 }
 
 func ToQuotaNonSQL(model *QuotaEntityNonSQL) *models.Quota {
 	return &models.Quota{
-		Number: model.Number,
-		Price:  model.Price,
-		Month:  model.Month,
-		Year:   model.Year,
+		Number:     model.Number,
+		Price:      model.Price,
+		Month:      model.Month,
+		Year:       model.Year,
+		PaidAmount: model.PaidAmount,
+	}
+}
+
+// DueQuotaEntityNonSQL is an installment quota together with the details of its purchase.
+// It is the shape of the quotas due in a month, both as an aggregation result and embedded in payment summaries.
+type DueQuotaEntityNonSQL struct {
+	Quota          QuotaEntityNonSQL `bson:"quota"`
+	PaymentVoucher string            `bson:"payment_voucher"`
+	Store          string            `bson:"store"`
+	CuitStore      string            `bson:"cuit_store"`
+	NumberOfQuotas int               `bson:"number_of_quotas"`
+}
+
+// ToDueQuota maps a quota, with its purchase preloaded, to a due quota.
+func ToDueQuota(entity *QuotaEntitySQL) *models.DueQuota {
+	return &models.DueQuota{
+		Quota:          *ToQuota(entity),
+		PaymentVoucher: entity.PurchaseMonthlyPaymentsEntity.PurchaseEntity.PaymentVoucher,
+		Store:          entity.PurchaseMonthlyPaymentsEntity.PurchaseEntity.Store,
+		CuitStore:      entity.PurchaseMonthlyPaymentsEntity.PurchaseEntity.CuitStore,
+		NumberOfQuotas: entity.PurchaseMonthlyPaymentsEntity.NumberOfQuotas,
+	}
+}
+
+func ToDueQuotaNonSQL(entity *DueQuotaEntityNonSQL) *models.DueQuota {
+	return &models.DueQuota{
+		Quota:          *ToQuotaNonSQL(&entity.Quota),
+		PaymentVoucher: entity.PaymentVoucher,
+		Store:          entity.Store,
+		CuitStore:      entity.CuitStore,
+		NumberOfQuotas: entity.NumberOfQuotas,
+	}
+}
+
+func ToDueQuotaEntityNonSQL(model *models.DueQuota) *DueQuotaEntityNonSQL {
+	return &DueQuotaEntityNonSQL{
+		Quota:          *ToQuotaEntityNonSQL(&model.Quota),
+		PaymentVoucher: model.PaymentVoucher,
+		Store:          model.Store,
+		CuitStore:      model.CuitStore,
+		NumberOfQuotas: model.NumberOfQuotas,
+	}
+}
+
+func ConvertDueQuotaList(quotaEntityList *[]QuotaEntitySQL) *[]models.DueQuota {
+	quotas := []models.DueQuota{}
+	for _, v := range *quotaEntityList {
+		quotas = append(quotas, *ToDueQuota(&v))
+	}
+	return &quotas
+}
+
+func ConvertDueQuotaListMongo(quotaEntityList *[]DueQuotaEntityNonSQL) *[]models.DueQuota {
+	quotas := []models.DueQuota{}
+	for _, v := range *quotaEntityList {
+		quotas = append(quotas, *ToDueQuotaNonSQL(&v))
 	}
+	return &quotas
 }
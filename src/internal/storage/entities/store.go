@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -9,14 +10,14 @@ type StoreNonSQL struct {
 	ID          bson.ObjectID `bson:"_id,omitempty"` // MongoDB primary key
 	StoreName   string        `bson:"store_name"`    // Name of the store
 	CuitStore   string        `bson:"cuit_store"`    // Store CUIT
-	TotalAmount float64       `bson:"total_amount"`  // Total amount associated with the store
+	TotalAmount models.Money  `bson:"total_amount"`  // Total amount associated with the store
 }
 
 // StoreSQL represents a store and its associated information.
 type StoreSQL struct {
 	Store       string
 	CuitStore   string
-	TotalAmount float64
+	TotalAmount models.Money `gorm:"type:decimal(15,2)"`
 }
 
 func (StoreSQL) TableName() string {
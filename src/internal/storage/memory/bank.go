@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type BankRepositoryMemory struct {
+	db *Database
+}
+
+// NewBankMemoryRepository creates a new instance of BankRepositoryMemory
+func NewBankMemoryRepository(db *Database) storage.IBankStorage {
+	return &BankRepositoryMemory{db: db}
+}
+
+// CreateBank registers a new bank. The CUIT is unique among banks.
+func (r *BankRepositoryMemory) CreateBank(_ context.Context, bank models.Bank) (*models.Bank, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, err := r.db.findBank(bank.Cuit); err == nil {
+		return nil, fmt.Errorf("a bank with cuit %s already exists: %w", bank.Cuit, storage.ErrAlreadyExists)
+	}
+
+	stored := models.Bank{Name: bank.Name, Cuit: bank.Cuit, Address: bank.Address, Telephone: bank.Telephone}
+	r.db.banks = append(r.db.banks, &stored)
+
+	logger.Info("Bank %s registered", bank.Cuit)
+	result := r.db.bankOf(bank.Cuit)
+	return &result, nil
+}
+
+// GetBanks retrieves all banks ordered by CUIT.
+func (r *BankRepositoryMemory) GetBanks(_ context.Context) (*[]models.Bank, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	banks := []models.Bank{}
+	for _, bank := range r.db.banks {
+		banks = append(banks, r.db.bankOf(bank.Cuit))
+	}
+	sort.Slice(banks, func(i, j int) bool { return banks[i].Cuit < banks[j].Cuit })
+	return &banks, nil
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+func (r *BankRepositoryMemory) GetBankByCuit(_ context.Context, cuit string) (*models.Bank, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, err := r.db.findBank(cuit); err != nil {
+		return nil, err
+	}
+	bank := r.db.bankOf(cuit)
+	return &bank, nil
+}
+
+// UpdateBank replaces the name, address and telephone of a bank.
+func (r *BankRepositoryMemory) UpdateBank(_ context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	existing, err := r.db.findBank(cuit)
+	if err != nil {
+		return nil, err
+	}
+	existing.Name = bank.Name
+	existing.Address = bank.Address
+	existing.Telephone = bank.Telephone
+
+	logger.Info("Bank %s updated", cuit)
+	result := r.db.bankOf(cuit)
+	return &result, nil
+}
+
+// AddFinancingPromotionToBank adds a financing promotion to the bank identified by the promotion's bank CUIT.
+func (r *BankRepositoryMemory) AddFinancingPromotionToBank(_ context.Context, promotionFinancing models.Financing) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	promotionFinancing.Bank.Cuit = strings.TrimSpace(promotionFinancing.Bank.Cuit)
+	if _, err := r.db.findBank(promotionFinancing.Bank.Cuit); err != nil {
+		return err
+	}
+	if r.db.findFinancing(promotionFinancing.Code) != nil {
+		return fmt.Errorf("a financing promotion with code %s already exists: %w", promotionFinancing.Code, storage.ErrAlreadyExists)
+	}
+	promotionFinancing.InterestModel = promotionFinancing.InterestModel.OrFlat()
+
+	r.db.financings = append(r.db.financings, &financingRecord{
+		promotionRecord: newPromotionRecord(promotionFinancing.Promotion),
+		financing:       promotionFinancing,
+	})
+
+	logger.Info("Financing promotion %s added to bank %s", promotionFinancing.Code, promotionFinancing.Bank.Cuit)
+	return nil
+}
+
+// AddDiscountPromotionToBank adds a discount promotion to the bank identified by the promotion's bank CUIT.
+func (r *BankRepositoryMemory) AddDiscountPromotionToBank(_ context.Context, promotionDiscount models.Discount) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	promotionDiscount.Bank.Cuit = strings.TrimSpace(promotionDiscount.Bank.Cuit)
+	if _, err := r.db.findBank(promotionDiscount.Bank.Cuit); err != nil {
+		return err
+	}
+	if r.db.findDiscount(promotionDiscount.Code) != nil {
+		return fmt.Errorf("a discount promotion with code %s already exists: %w", promotionDiscount.Code, storage.ErrAlreadyExists)
+	}
+
+	r.db.discounts = append(r.db.discounts, &discountRecord{
+		promotionRecord: newPromotionRecord(promotionDiscount.Promotion),
+		discount:        promotionDiscount,
+	})
+
+	logger.Info("Discount promotion %s added to bank %s", promotionDiscount.Code, promotionDiscount.Bank.Cuit)
+	return nil
+}
+
+// ExtendFinancingPromotionValidity replaces the end of the validity of a financing promotion.
+func (r *BankRepositoryMemory) ExtendFinancingPromotionValidity(_ context.Context, code string, newDate time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record := r.db.findFinancing(code)
+	if record == nil {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	record.setValidityEndDate(&record.financing.Promotion, newDate)
+
+	logger.Info("Promotion Code %s updated successfully", code)
+	return nil
+}
+
+// ExtendDiscountPromotionValidity replaces the end of the validity of a discount promotion.
+func (r *BankRepositoryMemory) ExtendDiscountPromotionValidity(_ context.Context, code string, newDate time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record := r.db.findDiscount(code)
+	if record == nil {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	record.setValidityEndDate(&record.discount.Promotion, newDate)
+
+	logger.Info("Promotion Code %s updated successfully", code)
+	return nil
+}
+
+// DeleteFinancingPromotion logically deletes a financing promotion.
+func (r *BankRepositoryMemory) DeleteFinancingPromotion(_ context.Context, code string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record := r.db.findFinancing(code)
+	if record == nil {
+		return fmt.Errorf("could not find financing promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	record.isDeleted = true
+
+	logger.Info("Financing promotion %s was successfully logically deleted.", code)
+	return nil
+}
+
+// DeleteDiscountPromotion logically deletes a discount promotion.
+func (r *BankRepositoryMemory) DeleteDiscountPromotion(_ context.Context, code string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record := r.db.findDiscount(code)
+	if record == nil {
+		return fmt.Errorf("could not find discount promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	record.isDeleted = true
+
+	logger.Info("Discount promotion %s was successfully logically deleted.", code)
+	return nil
+}
+
+// GetBankCustomerCounts counts the customers of every bank, in registration order.
+func (r *BankRepositoryMemory) GetBankCustomerCounts(_ context.Context) ([]models.BankCustomerCountDTO, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	results := []models.BankCustomerCountDTO{}
+	for _, bank := range r.db.banks {
+		count := 0
+		for _, customer := range r.db.customers {
+			if containsCuit(customer.BankCuits, bank.Cuit) {
+				count++
+			}
+		}
+		results = append(results, models.BankCustomerCountDTO{BankCuit: bank.Cuit, BankName: bank.Name, CustomerCount: count})
+	}
+	return results, nil
+}
+
+// SaveBillingCycle creates or replaces the billing cycle configuration of a bank.
+func (r *BankRepositoryMemory) SaveBillingCycle(_ context.Context, cycle models.BillingCycle) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, err := r.db.findBank(cycle.BankCuit); err != nil {
+		return err
+	}
+	r.db.cycles[cycle.BankCuit] = cycle
+
+	logger.Info("Billing cycle of bank %s saved: closing day %d", cycle.BankCuit, cycle.ClosingDay)
+	return nil
+}
+
+// GetBillingCycle retrieves the billing cycle configuration of a bank, or nil if the bank has not configured one.
+func (r *BankRepositoryMemory) GetBillingCycle(_ context.Context, bankCuit string) (*models.BillingCycle, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, err := r.db.findBank(bankCuit); err != nil {
+		return nil, err
+	}
+	cycle, ok := r.db.cycles[bankCuit]
+	if !ok {
+		return nil, nil
+	}
+	return &cycle, nil
+}
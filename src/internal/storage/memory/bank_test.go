@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankRegistry(t *testing.T) {
+	ctx := context.Background()
+	bankRepo := NewBankMemoryRepository(newTestDatabase(t))
+
+	_, err := bankRepo.CreateBank(ctx, models.Bank{Name: "Santander Río", Cuit: "30-12345678-9"})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	updated, err := bankRepo.UpdateBank(ctx, "30-98765432-1", models.Bank{Name: "BBVA Argentina", Address: "789 Oak St"})
+	assert.NoError(t, err)
+	assert.Equal(t, "BBVA Argentina", updated.Name)
+	assert.Equal(t, "30-98765432-1", updated.Cuit)
+
+	_, err = bankRepo.GetBankByCuit(ctx, "30-00000000-0")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	banks, err := bankRepo.GetBanks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"30-12345678-9", "30-98765432-1"}, []string{(*banks)[0].Cuit, (*banks)[1].Cuit})
+
+	counts, err := bankRepo.GetBankCustomerCounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BankCustomerCountDTO{
+		{BankCuit: "30-12345678-9", BankName: "Santander", CustomerCount: 1},
+		{BankCuit: "30-98765432-1", BankName: "BBVA Argentina", CustomerCount: 0},
+	}, counts)
+}
+
+func TestPromotionSoftDeleteAndExtend(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	bankRepo := NewBankMemoryRepository(db)
+	promotionRepo := NewPromotionMemoryRepository(db)
+
+	discount := models.Discount{
+		Promotion: models.Promotion{
+			Code:              "DISC2025",
+			CuitStore:         "30-11111111-1",
+			ValidityStartDate: "2025-01-01T00:00:00Z",
+			ValidityEndDate:   "2025-06-30T00:00:00Z",
+			Bank:              models.Bank{Cuit: "30-12345678-9"},
+		},
+		DiscountPercentage: models.MustParsePercentage("10"),
+	}
+	assert.NoError(t, bankRepo.AddDiscountPromotionToBank(ctx, discount))
+	assert.ErrorIs(t, bankRepo.AddDiscountPromotionToBank(ctx, discount), storage.ErrAlreadyExists)
+
+	discount.Code = "DISC-NOBANK"
+	discount.Bank.Cuit = "30-00000000-0"
+	assert.ErrorIs(t, bankRepo.AddDiscountPromotionToBank(ctx, discount), storage.ErrNotFound)
+
+	newEnd := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, bankRepo.ExtendDiscountPromotionValidity(ctx, "DISC2025", newEnd))
+	assert.ErrorIs(t, bankRepo.ExtendFinancingPromotionValidity(ctx, "DISC2025", newEnd), storage.ErrNotFound)
+
+	assert.NoError(t, bankRepo.DeleteDiscountPromotion(ctx, "DISC2025"))
+	assert.ErrorIs(t, bankRepo.DeleteDiscountPromotion(ctx, "UNKNOWN"), storage.ErrNotFound)
+
+	// The promotion is kept, flagged as deleted
+	detail, err := promotionRepo.GetPromotionByCode(ctx, "DISC2025")
+	assert.NoError(t, err)
+	assert.True(t, detail.IsDeleted)
+	assert.Equal(t, "2025-12-31T00:00:00Z", detail.Discount.ValidityEndDate)
+	assert.Equal(t, "Santander", detail.Discount.Bank.Name)
+
+	_, discounts, err := promotionRepo.GetApplicablePromotions(ctx, "30-12345678-9", "30-11111111-1", time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Empty(t, *discounts)
+}
+
+func TestBillingCycle(t *testing.T) {
+	ctx := context.Background()
+	bankRepo := NewBankMemoryRepository(newTestDatabase(t))
+
+	cycle, err := bankRepo.GetBillingCycle(ctx, "30-12345678-9")
+	assert.NoError(t, err)
+	assert.Nil(t, cycle)
+
+	assert.NoError(t, bankRepo.SaveBillingCycle(ctx, models.BillingCycle{BankCuit: "30-12345678-9", ClosingDay: 25, FirstDueDays: 10, SecondDueDays: 5, SurchargePercentage: models.MustParsePercentage("3")}))
+	assert.ErrorIs(t, bankRepo.SaveBillingCycle(ctx, models.DefaultBillingCycle("30-00000000-0")), storage.ErrNotFound)
+
+	cycle, err = bankRepo.GetBillingCycle(ctx, "30-12345678-9")
+	assert.NoError(t, err)
+	assert.Equal(t, 25, cycle.ClosingDay)
+}
@@ -0,0 +1,380 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type CardRepositoryMemory struct {
+	db  *Database
+	now func() time.Time
+}
+
+// NewCardMemoryRepository creates a new instance of CardRepositoryMemory
+func NewCardMemoryRepository(db *Database) storage.ICardStorage {
+	return &CardRepositoryMemory{db: db, now: time.Now}
+}
+
+// GetPaymentSummary retrieves the stored payment summary of a card for a month, including the purchases it bills.
+func (r *CardRepositoryMemory) GetPaymentSummary(_ context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return r.findPaymentSummary(cardNumber, month, year)
+}
+
+// SavePaymentSummary stores the payment summary of a card for a month, keeping only the purchases and quotas of the card it bills.
+func (r *CardRepositoryMemory) SavePaymentSummary(_ context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, stored := range r.db.summaries {
+		if stored.cardNumber == cardNumber && stored.summary.Month == summary.Month && stored.summary.Year == summary.Year {
+			return nil, fmt.Errorf("card %s already has a payment summary for %02d/%d: %w", cardNumber, summary.Month, summary.Year, storage.ErrAlreadyExists)
+		}
+	}
+
+	// Link the billed purchases, identified by their payment voucher
+	billedVouchers := map[string]bool{}
+	for _, purchase := range summary.SinglePayments {
+		billedVouchers[purchase.PaymentVoucher] = true
+	}
+	for _, purchase := range summary.MonthlyPayments {
+		billedVouchers[purchase.PaymentVoucher] = true
+	}
+	billedQuotas := map[string]bool{}
+	for _, quota := range summary.Quotas {
+		billedQuotas[dueQuotaKey(quota.PaymentVoucher, quota.Number)] = true
+	}
+
+	stored := summary
+	stored.Subtotals = append([]models.CurrencySubtotal{}, summary.Subtotals...)
+	stored.SinglePayments = []models.PurchaseSinglePayment{}
+	for _, purchase := range record.singlePayments {
+		if billedVouchers[purchase.PaymentVoucher] {
+			stored.SinglePayments = append(stored.SinglePayments, purchase)
+		}
+	}
+	stored.MonthlyPayments = []models.PurchaseMonthlyPayment{}
+	for _, purchase := range record.monthlyPayments {
+		if billedVouchers[purchase.PaymentVoucher] {
+			stored.MonthlyPayments = append(stored.MonthlyPayments, purchase)
+		}
+	}
+	stored.Quotas = []models.DueQuota{}
+	for _, quota := range dueQuotas(record, summary.Month, summary.Year) {
+		if billedQuotas[dueQuotaKey(quota.PaymentVoucher, quota.Number)] {
+			stored.Quotas = append(stored.Quotas, quota)
+		}
+	}
+	r.db.summaries = append(r.db.summaries, &summaryRecord{cardNumber: cardNumber, summary: stored})
+
+	logger.Info("Payment summary %s stored for card %s", summary.Code, cardNumber)
+	return r.findPaymentSummary(cardNumber, summary.Month, summary.Year)
+}
+
+// GetPurchasesInPeriod retrieves the purchases made with a card between from (inclusive) and to (exclusive).
+func (r *CardRepositoryMemory) GetPurchasesInPeriod(_ context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inPeriod := func(date time.Time) bool { return !date.Before(from) && date.Before(to) }
+
+	singlePayments := []models.PurchaseSinglePayment{}
+	for _, purchase := range record.singlePayments {
+		if inPeriod(purchase.PurchaseDate) {
+			singlePayments = append(singlePayments, purchase)
+		}
+	}
+	sort.SliceStable(singlePayments, func(i, j int) bool {
+		return singlePayments[i].PurchaseDate.Before(singlePayments[j].PurchaseDate)
+	})
+
+	monthlyPayments := []models.PurchaseMonthlyPayment{}
+	for _, purchase := range record.monthlyPayments {
+		if inPeriod(purchase.PurchaseDate) {
+			monthlyPayments = append(monthlyPayments, purchase)
+		}
+	}
+	sort.SliceStable(monthlyPayments, func(i, j int) bool {
+		return monthlyPayments[i].PurchaseDate.Before(monthlyPayments[j].PurchaseDate)
+	})
+
+	return &singlePayments, &monthlyPayments, nil
+}
+
+// GetQuotasDueInMonth retrieves the installment quotas of a card due in the given month, across all its installment purchases.
+func (r *CardRepositoryMemory) GetQuotasDueInMonth(_ context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := dueQuotas(record, month, year)
+	return &quotas, nil
+}
+
+// GetCardsExpiringInNext30Days retrieves the cards whose payment summaries have a first expiration within 30 days of the given date.
+func (r *CardRepositoryMemory) GetCardsExpiringInNext30Days(_ context.Context, day int, month int, year int) (*[]models.Card, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	startDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	next30Days := startDate.AddDate(0, 0, 30)
+
+	var cards []models.Card
+	for _, stored := range r.db.summaries {
+		expiration := stored.summary.FirstExpiration
+		if expiration.Before(startDate) || expiration.After(next30Days) {
+			continue
+		}
+		record, err := r.db.findCard(stored.cardNumber)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, *toCard(record))
+	}
+
+	return &cards, nil
+}
+
+// GetPurchaseSingle retrieves a single-payment purchase by its store CUIT, final amount and payment voucher.
+func (r *CardRepositoryMemory) GetPurchaseSingle(_ context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, record := range r.db.cards {
+		for _, purchase := range record.singlePayments {
+			if purchase.CuitStore == cuit && purchase.FinalAmount == finalAmount && purchase.PaymentVoucher == paymentVoucher {
+				return &purchase, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("could not find single-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+}
+
+// GetPurchaseMonthly retrieves a monthly-payment purchase, including its quotas, by its store CUIT, final amount and payment voucher.
+func (r *CardRepositoryMemory) GetPurchaseMonthly(_ context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, record := range r.db.cards {
+		for _, purchase := range record.monthlyPayments {
+			if purchase.CuitStore == cuit && purchase.FinalAmount == finalAmount && purchase.PaymentVoucher == paymentVoucher {
+				return &purchase, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("could not find monthly-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+}
+
+// GetTop10CardsByPurchases retrieves the 10 cards with the most purchases, including their purchases.
+// Cards with the same number of purchases are ordered by number.
+func (r *CardRepositoryMemory) GetTop10CardsByPurchases(_ context.Context) (*[]models.Card, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	records := append([]*cardRecord{}, r.db.cards...)
+	sort.SliceStable(records, func(i, j int) bool {
+		countI := len(records[i].singlePayments) + len(records[i].monthlyPayments)
+		countJ := len(records[j].singlePayments) + len(records[j].monthlyPayments)
+		if countI != countJ {
+			return countI > countJ
+		}
+		return records[i].card.Number < records[j].card.Number
+	})
+	if len(records) > 10 {
+		records = records[:10]
+	}
+
+	var cards []models.Card
+	for _, record := range records {
+		cards = append(cards, *toCardWithPurchases(record))
+	}
+	return &cards, nil
+}
+
+// GetCardByNumber retrieves a card, including its bank and the CUIT of its holder, by its number.
+func (r *CardRepositoryMemory) GetCardByNumber(_ context.Context, cardNumber string) (*models.Card, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	return r.toCard(record), nil
+}
+
+// IssueCard issues a new card to the customer with the card's customer CUIT at the card's bank. Card numbers are unique.
+func (r *CardRepositoryMemory) IssueCard(_ context.Context, card models.Card) (*models.Card, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, err := r.db.findCard(card.Number); err == nil {
+		return nil, fmt.Errorf("a card with number %s already exists: %w", card.Number, storage.ErrAlreadyExists)
+	}
+	if _, err := r.db.findBank(card.Bank.Cuit); err != nil {
+		return nil, err
+	}
+	if _, err := r.db.findCustomer(card.CustomerCuit); err != nil {
+		return nil, err
+	}
+
+	stored := card
+	stored.Bank = models.Bank{Cuit: card.Bank.Cuit}
+	stored.PurchaseSinglePayments = nil
+	stored.PurchaseMonthlyPayments = nil
+	if stored.Status == "" {
+		stored.Status = models.CardStatusActive
+	}
+	record := &cardRecord{card: stored}
+	r.db.cards = append(r.db.cards, record)
+
+	logger.Info("Card %s issued to customer %s at bank %s", card.Number, card.CustomerCuit, card.Bank.Cuit)
+	return r.toCard(record), nil
+}
+
+// UpdateCardExpiration replaces the expiration date of a card.
+func (r *CardRepositoryMemory) UpdateCardExpiration(_ context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	return r.updateCard(cardNumber, func(card *models.Card) { card.ExpirationDate = expirationDate })
+}
+
+// UpdateCardStatus replaces the lifecycle status of a card.
+func (r *CardRepositoryMemory) UpdateCardStatus(_ context.Context, cardNumber string, status models.CardStatus) (*models.Card, error) {
+	return r.updateCard(cardNumber, func(card *models.Card) { card.Status = status })
+}
+
+// updateCard applies the change to the card with the given number and returns the updated card.
+func (r *CardRepositoryMemory) updateCard(cardNumber string, change func(card *models.Card)) (*models.Card, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	change(&record.card)
+
+	logger.Info("Card %s updated", cardNumber)
+	return r.toCard(record), nil
+}
+
+// AddPurchaseSinglePayment registers a single-payment purchase on a card. A missing purchase date defaults to now.
+// The voucher must not be used by another single-payment purchase of the card.
+func (r *CardRepositoryMemory) AddPurchaseSinglePayment(_ context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if purchase.PurchaseDate.IsZero() {
+		purchase.PurchaseDate = r.now()
+	}
+	for _, existing := range record.singlePayments {
+		if existing.PaymentVoucher == purchase.PaymentVoucher {
+			return nil, fmt.Errorf("card %s already has a single-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+	}
+	purchase.Currency = purchase.Currency.OrBase()
+	record.singlePayments = append(record.singlePayments, purchase)
+
+	logger.Info("Single-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return &purchase, nil
+}
+
+// AddPurchaseMonthlyPayment registers a monthly-payment purchase, together with its quotas, on a card. A missing purchase date defaults to now.
+// The voucher must not be used by another monthly-payment purchase of the card.
+func (r *CardRepositoryMemory) AddPurchaseMonthlyPayment(_ context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if purchase.PurchaseDate.IsZero() {
+		purchase.PurchaseDate = r.now()
+	}
+	for _, existing := range record.monthlyPayments {
+		if existing.PaymentVoucher == purchase.PaymentVoucher {
+			return nil, fmt.Errorf("card %s already has a monthly-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+	}
+	purchase.Currency = purchase.Currency.OrBase()
+	purchase.InterestModel = purchase.InterestModel.OrFlat()
+	purchase.Quota = append([]models.Quota{}, purchase.Quota...)
+	record.monthlyPayments = append(record.monthlyPayments, purchase)
+
+	logger.Info("Monthly-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return &purchase, nil
+}
+
+// findPaymentSummary returns a copy of the payment summary of a card for a month. The caller must hold the lock.
+func (r *CardRepositoryMemory) findPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stored := range r.db.summaries {
+		if stored.cardNumber == cardNumber && stored.summary.Month == month && stored.summary.Year == year {
+			summary := stored.summary
+			summary.Card = *r.toCard(record)
+			return &summary, nil
+		}
+	}
+	return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+}
+
+// toCard returns a copy of a stored card, without its purchases, with its bank resolved. The caller must hold the lock.
+func (r *CardRepositoryMemory) toCard(record *cardRecord) *models.Card {
+	card := toCard(record)
+	card.Bank = r.db.bankOf(record.card.Bank.Cuit)
+	return card
+}
+
+// dueQuotas returns the quotas of a card's installment purchases due in the given month, ordered by purchase and number.
+// Months are matched both zero-padded and unpadded.
+func dueQuotas(record *cardRecord, month int, year int) []models.DueQuota {
+	months := quotaMonths(month)
+	quotas := []models.DueQuota{}
+	for _, purchase := range record.monthlyPayments {
+		purchaseQuotas := append([]models.Quota{}, purchase.Quota...)
+		sort.SliceStable(purchaseQuotas, func(i, j int) bool { return purchaseQuotas[i].Number < purchaseQuotas[j].Number })
+		for _, quota := range purchaseQuotas {
+			if !months[quota.Month] || quota.Year != strconv.Itoa(year) {
+				continue
+			}
+			quotas = append(quotas, models.DueQuota{
+				Quota:          quota,
+				PaymentVoucher: purchase.PaymentVoucher,
+				Store:          purchase.Store,
+				CuitStore:      purchase.CuitStore,
+				NumberOfQuotas: purchase.NumberOfQuotas,
+			})
+		}
+	}
+	return quotas
+}
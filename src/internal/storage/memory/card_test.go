@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardLifecycle(t *testing.T) {
+	ctx := context.Background()
+	cardRepo := NewCardMemoryRepository(newTestDatabase(t))
+
+	card, err := cardRepo.GetCardByNumber(ctx, "1234567812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusActive, card.Status)
+	assert.Equal(t, "Santander", card.Bank.Name)
+	assert.Equal(t, "20-12345678-9", card.CustomerCuit)
+
+	_, err = cardRepo.IssueCard(ctx, models.Card{Number: "1234567812345678", Bank: models.Bank{Cuit: "30-12345678-9"}, CustomerCuit: "20-12345678-9"})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	_, err = cardRepo.IssueCard(ctx, models.Card{Number: "8765432187654321", Bank: models.Bank{Cuit: "30-12345678-9"}, CustomerCuit: "20-00000000-0"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	card, err = cardRepo.UpdateCardStatus(ctx, "1234567812345678", models.CardStatusBlocked)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusBlocked, card.Status)
+
+	renewal := time.Date(2035, time.December, 31, 0, 0, 0, 0, time.UTC)
+	card, err = cardRepo.UpdateCardExpiration(ctx, "1234567812345678", renewal)
+	assert.NoError(t, err)
+	assert.Equal(t, renewal, card.ExpirationDate)
+
+	_, err = cardRepo.UpdateCardStatus(ctx, "0000000000000000", models.CardStatusCancelled)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestPurchasesAndPaymentSummary(t *testing.T) {
+	ctx := context.Background()
+	cardRepo := NewCardMemoryRepository(newTestDatabase(t))
+	march := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	_, err := cardRepo.AddPurchaseSinglePayment(ctx, "1234567812345678", models.PurchaseSinglePayment{
+		Purchase: models.Purchase{PaymentVoucher: "VCHR-1", Store: "Store A", CuitStore: "30-11111111-1", Amount: models.MustParseMoney("100"), FinalAmount: models.MustParseMoney("90"), PurchaseDate: march},
+	})
+	require.NoError(t, err)
+	_, err = cardRepo.AddPurchaseMonthlyPayment(ctx, "1234567812345678", models.PurchaseMonthlyPayment{
+		Purchase:       models.Purchase{PaymentVoucher: "VCHR-2", Store: "Store B", CuitStore: "30-22222222-2", Amount: models.MustParseMoney("300"), FinalAmount: models.MustParseMoney("300"), PurchaseDate: march.AddDate(0, 0, 5)},
+		NumberOfQuotas: 3,
+		Quota: []models.Quota{
+			{Number: 1, Price: models.MustParseMoney("100"), Month: "03", Year: "2025"},
+			{Number: 2, Price: models.MustParseMoney("100"), Month: "04", Year: "2025"},
+			{Number: 3, Price: models.MustParseMoney("100"), Month: "5", Year: "2025"},
+		},
+	})
+	require.NoError(t, err)
+
+	singlePayments, monthlyPayments, err := cardRepo.GetPurchasesInPeriod(ctx, "1234567812345678", march, march.AddDate(0, 0, 5))
+	assert.NoError(t, err)
+	assert.Len(t, *singlePayments, 1)
+	assert.Empty(t, *monthlyPayments, "the end of the period is exclusive")
+
+	quotas, err := cardRepo.GetQuotasDueInMonth(ctx, "1234567812345678", 5, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DueQuota{{Quota: models.Quota{Number: 3, Price: models.MustParseMoney("100"), Month: "5", Year: "2025"}, PaymentVoucher: "VCHR-2", Store: "Store B", CuitStore: "30-22222222-2", NumberOfQuotas: 3}}, *quotas)
+
+	purchase, err := cardRepo.GetPurchaseMonthly(ctx, "30-22222222-2", models.MustParseMoney("300"), "VCHR-2")
+	assert.NoError(t, err)
+	assert.Len(t, purchase.Quota, 3)
+	_, err = cardRepo.GetPurchaseSingle(ctx, "30-11111111-1", models.MustParseMoney("100"), "VCHR-1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	dueQuotas, err := cardRepo.GetQuotasDueInMonth(ctx, "1234567812345678", 3, 2025)
+	require.NoError(t, err)
+	summary := models.PaymentSummary{
+		Code:            "SUM-2025-03",
+		Month:           3,
+		Year:            2025,
+		FirstExpiration: time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC),
+		TotalPrice:      models.MustParseMoney("190"),
+		SinglePayments:  []models.PurchaseSinglePayment{{Purchase: models.Purchase{PaymentVoucher: "VCHR-1"}}, {Purchase: models.Purchase{PaymentVoucher: "UNKNOWN"}}},
+		Quotas:          *dueQuotas,
+	}
+	stored, err := cardRepo.SavePaymentSummary(ctx, "1234567812345678", summary)
+	assert.NoError(t, err)
+	assert.Len(t, stored.SinglePayments, 1, "only the purchases of the card are billed")
+	assert.Len(t, stored.Quotas, 1)
+	assert.Equal(t, "1234567812345678", stored.Card.Number)
+
+	_, err = cardRepo.SavePaymentSummary(ctx, "1234567812345678", summary)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	cards, err := cardRepo.GetCardsExpiringInNext30Days(ctx, 1, 4, 2025)
+	assert.NoError(t, err)
+	assert.Len(t, *cards, 1)
+	cards, err = cardRepo.GetCardsExpiringInNext30Days(ctx, 1, 6, 2025)
+	assert.NoError(t, err)
+	assert.Empty(t, *cards)
+}
+
+func TestGetTop10CardsByPurchases(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	cardRepo := NewCardMemoryRepository(db)
+
+	// Twelve more cards, card i with i purchases
+	for i := 1; i <= 12; i++ {
+		number := fmt.Sprintf("40000000000000%02d", i)
+		_, err := cardRepo.IssueCard(ctx, models.Card{Number: number, Bank: models.Bank{Cuit: "30-98765432-1"}, CustomerCuit: "20-12345678-9"})
+		require.NoError(t, err)
+		for p := 0; p < i; p++ {
+			_, err := cardRepo.AddPurchaseSinglePayment(ctx, number, models.PurchaseSinglePayment{Purchase: models.Purchase{PaymentVoucher: fmt.Sprintf("V-%d-%d", i, p)}})
+			require.NoError(t, err)
+		}
+	}
+
+	cards, err := cardRepo.GetTop10CardsByPurchases(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, *cards, 10)
+	assert.Equal(t, "4000000000000012", (*cards)[0].Number)
+	assert.Len(t, (*cards)[0].PurchaseSinglePayments, 12)
+	assert.Equal(t, "4000000000000003", (*cards)[9].Number)
+}
@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type CompensationRepositoryMemory struct {
+	db *Database
+}
+
+// NewCompensationMemoryRepository creates a new instance of CompensationRepositoryMemory
+func NewCompensationMemoryRepository(db *Database) storage.ICompensationStorage {
+	return &CompensationRepositoryMemory{db: db}
+}
+
+// RemoveBank removes a bank by its CUIT.
+func (r *CompensationRepositoryMemory) RemoveBank(_ context.Context, cuit string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	banks, removed := removeLast(r.db.banks, func(bank *models.Bank) bool { return bank.Cuit == cuit })
+	if !removed {
+		return fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound)
+	}
+	r.db.banks = banks
+
+	logger.Info("Bank %s removed", cuit)
+	return nil
+}
+
+// RemoveCustomer removes a customer by its CUIT.
+func (r *CompensationRepositoryMemory) RemoveCustomer(_ context.Context, cuit string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	customers, removed := removeLast(r.db.customers, func(customer *models.Customer) bool { return customer.Cuit == cuit })
+	if !removed {
+		return fmt.Errorf("could not find customer with cuit %s: %w", cuit, storage.ErrNotFound)
+	}
+	r.db.customers = customers
+
+	logger.Info("Customer %s removed", cuit)
+	return nil
+}
+
+// RemoveCard removes a card by its number.
+func (r *CompensationRepositoryMemory) RemoveCard(_ context.Context, cardNumber string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	cards, removed := removeLast(r.db.cards, func(record *cardRecord) bool { return record.card.Number == cardNumber })
+	if !removed {
+		return fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+	}
+	r.db.cards = cards
+
+	logger.Info("Card %s removed", cardNumber)
+	return nil
+}
+
+// RemovePromotion removes a discount or financing promotion by its code.
+func (r *CompensationRepositoryMemory) RemovePromotion(_ context.Context, code string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	discounts, removedDiscount := removeLast(r.db.discounts, func(record *discountRecord) bool { return record.discount.Code == code })
+	financings, removedFinancing := removeLast(r.db.financings, func(record *financingRecord) bool { return record.financing.Code == code })
+	if !removedDiscount && !removedFinancing {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	r.db.discounts = discounts
+	r.db.financings = financings
+
+	logger.Info("Promotion %s removed", code)
+	return nil
+}
+
+// RemovePurchaseSinglePayment removes the latest single-payment purchase registered on a card with the given voucher.
+func (r *CompensationRepositoryMemory) RemovePurchaseSinglePayment(_ context.Context, cardNumber string, paymentVoucher string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return err
+	}
+	purchases, removed := removeLast(record.singlePayments, func(purchase models.PurchaseSinglePayment) bool {
+		return purchase.PaymentVoucher == paymentVoucher
+	})
+	if !removed {
+		return fmt.Errorf("could not find single-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound)
+	}
+	record.singlePayments = purchases
+
+	logger.Info("Single-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePurchaseMonthlyPayment removes the latest installment purchase registered on a card with the given voucher, and its quotas.
+func (r *CompensationRepositoryMemory) RemovePurchaseMonthlyPayment(_ context.Context, cardNumber string, paymentVoucher string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return err
+	}
+	purchases, removed := removeLast(record.monthlyPayments, func(purchase models.PurchaseMonthlyPayment) bool {
+		return purchase.PaymentVoucher == paymentVoucher
+	})
+	if !removed {
+		return fmt.Errorf("could not find monthly-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound)
+	}
+	record.monthlyPayments = purchases
+
+	logger.Info("Monthly-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePaymentSummary removes the payment summary of a card for a month.
+func (r *CompensationRepositoryMemory) RemovePaymentSummary(_ context.Context, cardNumber string, month int, year int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	summaries, removed := removeLast(r.db.summaries, func(stored *summaryRecord) bool {
+		return stored.cardNumber == cardNumber && stored.summary.Month == month && stored.summary.Year == year
+	})
+	if !removed {
+		return fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+	}
+	r.db.summaries = summaries
+
+	logger.Info("Payment summary of card %s for %02d/%d removed", cardNumber, month, year)
+	return nil
+}
+
+// RemoveBillingCycle removes the billing cycle configuration of a bank.
+func (r *CompensationRepositoryMemory) RemoveBillingCycle(_ context.Context, bankCuit string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.cycles[bankCuit]; !ok {
+		return fmt.Errorf("bank %s has no billing cycle: %w", bankCuit, storage.ErrNotFound)
+	}
+	delete(r.db.cycles, bankCuit)
+
+	logger.Info("Billing cycle of bank %s removed", bankCuit)
+	return nil
+}
+
+// RemoveExchangeRate removes the rate of a currency for a day.
+func (r *CompensationRepositoryMemory) RemoveExchangeRate(_ context.Context, currency models.Currency, date time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	day := models.RateDay(date)
+	i, found := r.db.findExchangeRate(currency, day)
+	if !found {
+		return fmt.Errorf("no %s exchange rate on %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound)
+	}
+	r.db.rates = append(r.db.rates[:i:i], r.db.rates[i+1:]...)
+
+	logger.Info("%s exchange rate of %s removed", currency, day.Format(time.DateOnly))
+	return nil
+}
+
+// RemovePayment removes a payment by its code and takes its allocations off the paid amounts of the billed items.
+func (r *CompensationRepositoryMemory) RemovePayment(_ context.Context, code string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var payment *models.Payment
+	for i := range r.db.payments {
+		if r.db.payments[i].Code == code {
+			payment = &r.db.payments[i]
+		}
+	}
+	if payment == nil {
+		return fmt.Errorf("could not find payment with code %s: %w", code, storage.ErrNotFound)
+	}
+	if err := r.db.allocatePayment(*payment, -1); err != nil {
+		return err
+	}
+	r.db.payments, _ = removeLast(r.db.payments, func(stored models.Payment) bool { return stored.Code == code })
+
+	logger.Info("Payment %s removed", code)
+	return nil
+}
+
+// removeLast returns the items without the last one that matches, and whether one matched.
+func removeLast[T any](items []T, match func(item T) bool) ([]T, bool) {
+	for i := len(items) - 1; i >= 0; i-- {
+		if match(items[i]) {
+			return append(items[:i:i], items[i+1:]...), true
+		}
+	}
+	return items, false
+}
@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+)
+
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storages {
+		db := NewMemoryDB()
+		return storagetest.Storages{
+			Banks:         NewBankMemoryRepository(db),
+			Cards:         NewCardMemoryRepository(db),
+			Promotions:    NewPromotionMemoryRepository(db),
+			Stores:        NewStoreMemoryRepository(db),
+			Customers:     NewCustomerMemoryRepository(db),
+			ExchangeRates: NewExchangeRateMemoryRepository(db),
+			Payments:      NewPaymentMemoryRepository(db),
+			Compensation:  NewCompensationMemoryRepository(db),
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type CustomerRepositoryMemory struct {
+	db *Database
+}
+
+// NewCustomerMemoryRepository creates a new instance of CustomerRepositoryMemory
+func NewCustomerMemoryRepository(db *Database) storage.ICustomerStorage {
+	return &CustomerRepositoryMemory{db: db}
+}
+
+// CreateCustomer stores a new customer. CUIT and DNI are unique among customers.
+func (r *CustomerRepositoryMemory) CreateCustomer(_ context.Context, customer models.Customer) (*models.Customer, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, existing := range r.db.customers {
+		if existing.Cuit == customer.Cuit || existing.Dni == customer.Dni {
+			return nil, fmt.Errorf("a customer with cuit %s or dni %s already exists: %w", customer.Cuit, customer.Dni, storage.ErrAlreadyExists)
+		}
+	}
+
+	stored := customer
+	stored.BankCuits = []string{}
+	r.db.customers = append(r.db.customers, &stored)
+
+	logger.Info("Customer %s registered", customer.Cuit)
+	return toCustomer(&stored), nil
+}
+
+// GetCustomerByCuit retrieves a customer, including the CUITs of its banks, by its CUIT.
+func (r *CustomerRepositoryMemory) GetCustomerByCuit(_ context.Context, cuit string) (*models.Customer, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	customer, err := r.db.findCustomer(cuit)
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(customer), nil
+}
+
+// UpdateCustomer replaces the name, DNI, address, telephone and entry date of a customer.
+func (r *CustomerRepositoryMemory) UpdateCustomer(_ context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	existing, err := r.db.findCustomer(cuit)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range r.db.customers {
+		if other != existing && other.Dni == customer.Dni {
+			return nil, fmt.Errorf("a customer with dni %s already exists: %w", customer.Dni, storage.ErrAlreadyExists)
+		}
+	}
+
+	existing.CompleteName = customer.CompleteName
+	existing.Dni = customer.Dni
+	existing.Address = customer.Address
+	existing.Telephone = customer.Telephone
+	existing.EntryDate = customer.EntryDate
+
+	logger.Info("Customer %s updated", cuit)
+	return toCustomer(existing), nil
+}
+
+// GetCustomers retrieves all customers ordered by CUIT.
+func (r *CustomerRepositoryMemory) GetCustomers(_ context.Context) (*[]models.Customer, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	customers := []models.Customer{}
+	for _, customer := range r.db.customers {
+		customers = append(customers, *toCustomer(customer))
+	}
+	sort.Slice(customers, func(i, j int) bool { return customers[i].Cuit < customers[j].Cuit })
+	return &customers, nil
+}
+
+// AddCustomerToBank makes a customer a member of a bank.
+func (r *CustomerRepositoryMemory) AddCustomerToBank(_ context.Context, customerCuit string, bankCuit string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	customer, err := r.findMembership(customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+	if containsCuit(customer.BankCuits, bankCuit) {
+		return fmt.Errorf("customer %s is already a member of bank %s: %w", customerCuit, bankCuit, storage.ErrAlreadyExists)
+	}
+	customer.BankCuits = append(customer.BankCuits, bankCuit)
+
+	logger.Info("Customer %s joined bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// RemoveCustomerFromBank ends the membership of a customer in a bank.
+func (r *CustomerRepositoryMemory) RemoveCustomerFromBank(_ context.Context, customerCuit string, bankCuit string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	customer, err := r.findMembership(customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+	if !containsCuit(customer.BankCuits, bankCuit) {
+		return fmt.Errorf("customer %s is not a member of bank %s: %w", customerCuit, bankCuit, storage.ErrNotFound)
+	}
+
+	remaining := []string{}
+	for _, cuit := range customer.BankCuits {
+		if cuit != bankCuit {
+			remaining = append(remaining, cuit)
+		}
+	}
+	customer.BankCuits = remaining
+
+	logger.Info("Customer %s left bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// findMembership retrieves the customer of a membership after checking that both the customer and the bank exist.
+func (r *CustomerRepositoryMemory) findMembership(customerCuit string, bankCuit string) (*models.Customer, error) {
+	customer, err := r.db.findCustomer(customerCuit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.db.findBank(bankCuit); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func containsCuit(cuits []string, cuit string) bool {
+	for _, candidate := range cuits {
+		if candidate == cuit {
+			return true
+		}
+	}
+	return false
+}
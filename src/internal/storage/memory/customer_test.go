@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	customerRepo := NewCustomerMemoryRepository(newTestDatabase(t))
+
+	newCustomer := models.Customer{CompleteName: "Ana Gómez", Dni: "31234567", Cuit: "27-31234567-3"}
+	_, err := customerRepo.CreateCustomer(ctx, newCustomer)
+	assert.NoError(t, err)
+	_, err = customerRepo.CreateCustomer(ctx, newCustomer)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	newCustomer.Dni = "12345678"
+	_, err = customerRepo.UpdateCustomer(ctx, "27-31234567-3", newCustomer)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "the DNI belongs to another customer")
+
+	assert.NoError(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-98765432-1"))
+	assert.NoError(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-12345678-9"))
+	assert.ErrorIs(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-12345678-9"), storage.ErrAlreadyExists)
+	assert.ErrorIs(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-00000000-0"), storage.ErrNotFound)
+
+	customer, err := customerRepo.GetCustomerByCuit(ctx, "27-31234567-3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"30-12345678-9", "30-98765432-1"}, customer.BankCuits)
+
+	assert.NoError(t, customerRepo.RemoveCustomerFromBank(ctx, "27-31234567-3", "30-12345678-9"))
+	assert.ErrorIs(t, customerRepo.RemoveCustomerFromBank(ctx, "27-31234567-3", "30-12345678-9"), storage.ErrNotFound)
+
+	customers, err := customerRepo.GetCustomers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "20-12345678-9", (*customers)[0].Cuit)
+	assert.Equal(t, []string{"30-98765432-1"}, (*customers)[1].BankCuits)
+}
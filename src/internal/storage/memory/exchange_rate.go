@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type ExchangeRateRepositoryMemory struct {
+	db *Database
+}
+
+// NewExchangeRateMemoryRepository creates a new instance of ExchangeRateRepositoryMemory
+func NewExchangeRateMemoryRepository(db *Database) storage.IExchangeRateStorage {
+	return &ExchangeRateRepositoryMemory{db: db}
+}
+
+// SaveExchangeRates stores exchange rates, replacing the rate of a currency already stored for the same day.
+func (r *ExchangeRateRepositoryMemory) SaveExchangeRates(_ context.Context, rates []models.ExchangeRate) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, rate := range rates {
+		rate.Date = models.RateDay(rate.Date)
+		if i, found := r.db.findExchangeRate(rate.Currency, rate.Date); found {
+			r.db.rates[i] = rate
+			continue
+		}
+		r.db.rates = append(r.db.rates, rate)
+	}
+	sort.Slice(r.db.rates, func(i, j int) bool {
+		if r.db.rates[i].Currency != r.db.rates[j].Currency {
+			return r.db.rates[i].Currency < r.db.rates[j].Currency
+		}
+		return r.db.rates[i].Date.Before(r.db.rates[j].Date)
+	})
+
+	logger.Info("%d exchange rates saved", len(rates))
+	return nil
+}
+
+// GetExchangeRates retrieves the rates of a currency from one day to another, both included, ordered by day.
+func (r *ExchangeRateRepositoryMemory) GetExchangeRates(_ context.Context, currency models.Currency, from time.Time, to time.Time) (*[]models.ExchangeRate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	from, to = models.RateDay(from), models.RateDay(to)
+	rates := []models.ExchangeRate{}
+	for _, rate := range r.db.rates {
+		if rate.Currency == currency && !rate.Date.Before(from) && !rate.Date.After(to) {
+			rates = append(rates, rate)
+		}
+	}
+	return &rates, nil
+}
+
+// GetExchangeRate retrieves the latest rate of a currency on or before a day.
+func (r *ExchangeRateRepositoryMemory) GetExchangeRate(_ context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	day := models.RateDay(date)
+	for i := len(r.db.rates) - 1; i >= 0; i-- {
+		rate := r.db.rates[i]
+		if rate.Currency == currency && !rate.Date.After(day) {
+			return &rate, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s exchange rate on or before %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound)
+}
+
+// findExchangeRate returns the index of the rate of a currency for a day, and whether there is one. The caller must hold the lock.
+func (db *Database) findExchangeRate(currency models.Currency, day time.Time) (int, bool) {
+	for i, rate := range db.rates {
+		if rate.Currency == currency && rate.Date.Equal(day) {
+			return i, true
+		}
+	}
+	return 0, false
+}
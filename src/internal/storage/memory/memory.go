@@ -0,0 +1,222 @@
+/*
+ * Payment Registration System - In-Memory Storage
+ * -----------------------------------------------
+ * This file defines the in-memory database shared by the memory repositories. It keeps banks,
+ * customers, cards, purchases, promotions, payment summaries, payments, billing cycles and exchange rates in process memory,
+ * so services and handlers can run without MySQL or MongoDB. Operations never wait on I/O, so the
+ * repositories ignore the context they are given.
+ *
+ * Created: Mar. 12, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+// Database holds the records of every memory repository. All access goes through its lock,
+// so a single Database can be shared by repositories serving concurrent requests.
+type Database struct {
+	mu         sync.RWMutex
+	banks      []*models.Bank
+	customers  []*models.Customer
+	cards      []*cardRecord
+	discounts  []*discountRecord
+	financings []*financingRecord
+	summaries  []*summaryRecord
+	payments   []models.Payment
+	cycles     map[string]models.BillingCycle
+	rates      []models.ExchangeRate // Ordered by currency and day
+}
+
+// cardRecord is a card together with the purchases made with it.
+type cardRecord struct {
+	card            models.Card
+	singlePayments  []models.PurchaseSinglePayment
+	monthlyPayments []models.PurchaseMonthlyPayment
+}
+
+// promotionRecord keeps the parsed validity dates and the logical delete flag of a promotion.
+type promotionRecord struct {
+	bankCuit  string
+	startDate time.Time
+	endDate   time.Time
+	isDeleted bool
+}
+
+type discountRecord struct {
+	promotionRecord
+	discount models.Discount
+}
+
+type financingRecord struct {
+	promotionRecord
+	financing models.Financing
+}
+
+// summaryRecord is a payment summary stored for a card and month.
+type summaryRecord struct {
+	cardNumber string
+	summary    models.PaymentSummary
+}
+
+// NewMemoryDB creates an empty in-memory database.
+func NewMemoryDB() *Database {
+	return &Database{
+		cycles: map[string]models.BillingCycle{},
+	}
+}
+
+// findBank returns the bank with the given CUIT or a wrapped storage.ErrNotFound. The caller must hold the lock.
+func (db *Database) findBank(cuit string) (*models.Bank, error) {
+	for _, bank := range db.banks {
+		if bank.Cuit == cuit {
+			return bank, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound)
+}
+
+// findCustomer returns the customer with the given CUIT or a wrapped storage.ErrNotFound. The caller must hold the lock.
+func (db *Database) findCustomer(cuit string) (*models.Customer, error) {
+	for _, customer := range db.customers {
+		if customer.Cuit == cuit {
+			return customer, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find customer with cuit %s: %w", cuit, storage.ErrNotFound)
+}
+
+// findCard returns the card with the given number or a wrapped storage.ErrNotFound. The caller must hold the lock.
+func (db *Database) findCard(cardNumber string) (*cardRecord, error) {
+	for _, record := range db.cards {
+		if record.card.Number == cardNumber {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+}
+
+func (db *Database) findDiscount(code string) *discountRecord {
+	for _, record := range db.discounts {
+		if record.discount.Code == code {
+			return record
+		}
+	}
+	return nil
+}
+
+func (db *Database) findFinancing(code string) *financingRecord {
+	for _, record := range db.financings {
+		if record.financing.Code == code {
+			return record
+		}
+	}
+	return nil
+}
+
+// bankOf returns a copy of the bank with the given CUIT, without its members, or an empty bank if it is gone.
+func (db *Database) bankOf(cuit string) models.Bank {
+	bank, err := db.findBank(cuit)
+	if err != nil {
+		return models.Bank{Cuit: cuit}
+	}
+	return models.Bank{Name: bank.Name, Cuit: bank.Cuit, Address: bank.Address, Telephone: bank.Telephone}
+}
+
+// toDiscount returns a copy of a stored discount with its bank resolved.
+func (db *Database) toDiscount(record *discountRecord) models.Discount {
+	discount := record.discount
+	discount.Bank = db.bankOf(record.bankCuit)
+	return discount
+}
+
+// toFinancing returns a copy of a stored financing with its bank resolved.
+func (db *Database) toFinancing(record *financingRecord) models.Financing {
+	financing := record.financing
+	financing.Bank = db.bankOf(record.bankCuit)
+	return financing
+}
+
+// toCustomer returns a copy of a stored customer with its bank CUITs sorted.
+func toCustomer(customer *models.Customer) *models.Customer {
+	result := *customer
+	result.BankCuits = append([]string{}, customer.BankCuits...)
+	sort.Strings(result.BankCuits)
+	return &result
+}
+
+// toCard returns a copy of a stored card without its purchases.
+func toCard(record *cardRecord) *models.Card {
+	card := record.card
+	card.PurchaseSinglePayments = nil
+	card.PurchaseMonthlyPayments = nil
+	return &card
+}
+
+// toCardWithPurchases returns a copy of a stored card including the purchases made with it.
+func toCardWithPurchases(record *cardRecord) *models.Card {
+	card := toCard(record)
+	card.PurchaseSinglePayments = append([]models.PurchaseSinglePayment{}, record.singlePayments...)
+	card.PurchaseMonthlyPayments = append([]models.PurchaseMonthlyPayment{}, record.monthlyPayments...)
+	return card
+}
+
+// newPromotionRecord parses the RFC3339 validity dates of a promotion, using the zero time for malformed values.
+func newPromotionRecord(promotion models.Promotion) promotionRecord {
+	startDate, _ := time.Parse(time.RFC3339, promotion.ValidityStartDate)
+	endDate, _ := time.Parse(time.RFC3339, promotion.ValidityEndDate)
+	return promotionRecord{
+		bankCuit:  promotion.Bank.Cuit,
+		startDate: startDate,
+		endDate:   endDate,
+	}
+}
+
+// hasStatus reports whether the promotion has the given status on the given date.
+func (p promotionRecord) hasStatus(status models.PromotionStatus, date time.Time) bool {
+	switch status {
+	case models.PromotionStatusDeleted:
+		return p.isDeleted
+	case models.PromotionStatusExpired:
+		return !p.isDeleted && p.endDate.Before(date)
+	default:
+		return !p.isDeleted && !p.endDate.Before(date)
+	}
+}
+
+// validOn reports whether the promotion is not deleted and valid on the given date.
+func (p promotionRecord) validOn(date time.Time) bool {
+	return !p.isDeleted && !p.startDate.After(date) && !p.endDate.Before(date)
+}
+
+// availableIn reports whether the promotion is not deleted and either lies within or covers the given range.
+func (p promotionRecord) availableIn(startDate time.Time, endDate time.Time) bool {
+	within := !p.startDate.Before(startDate) && !p.endDate.After(endDate)
+	covering := !p.startDate.After(startDate) && !p.endDate.Before(endDate)
+	return !p.isDeleted && (within || covering)
+}
+
+// setValidityEndDate replaces the end of the validity of a promotion.
+func (p *promotionRecord) setValidityEndDate(promotion *models.Promotion, newDate time.Time) {
+	p.endDate = newDate
+	promotion.ValidityEndDate = newDate.Format(time.RFC3339)
+}
+
+// quotaMonths returns the representations of a month stored in quotas: zero-padded and unpadded.
+func quotaMonths(month int) map[string]bool {
+	return map[string]bool{fmt.Sprintf("%02d", month): true, strconv.Itoa(month): true}
+}
+
+func dueQuotaKey(paymentVoucher string, number int) string {
+	return fmt.Sprintf("%s#%d", paymentVoucher, number)
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+// newTestDatabase returns a database with two banks, a customer of the first one and a card issued to it.
+func newTestDatabase(t *testing.T) *Database {
+	ctx := context.Background()
+	t.Helper()
+	db := NewMemoryDB()
+
+	banks := NewBankMemoryRepository(db)
+	_, err := banks.CreateBank(ctx, models.Bank{Name: "Santander", Cuit: "30-12345678-9", Address: "123 Main St", Telephone: "555-1234"})
+	require.NoError(t, err)
+	_, err = banks.CreateBank(ctx, models.Bank{Name: "BBVA", Cuit: "30-98765432-1", Address: "456 Elm St", Telephone: "555-5678"})
+	require.NoError(t, err)
+
+	customers := NewCustomerMemoryRepository(db)
+	_, err = customers.CreateCustomer(ctx, models.Customer{CompleteName: "John Doe", Dni: "12345678", Cuit: "20-12345678-9"})
+	require.NoError(t, err)
+	require.NoError(t, customers.AddCustomerToBank(ctx, "20-12345678-9", "30-12345678-9"))
+
+	_, err = NewCardMemoryRepository(db).IssueCard(ctx, models.Card{
+		Number:               "1234567812345678",
+		Ccv:                  "123",
+		CardholderNameInCard: "John Doe",
+		Since:                time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ExpirationDate:       time.Date(2030, time.December, 31, 0, 0, 0, 0, time.UTC),
+		Bank:                 models.Bank{Cuit: "30-12345678-9"},
+		CustomerCuit:         "20-12345678-9",
+	})
+	require.NoError(t, err)
+
+	return db
+}
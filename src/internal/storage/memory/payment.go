@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type PaymentRepositoryMemory struct {
+	db *Database
+}
+
+// NewPaymentMemoryRepository creates a new instance of PaymentRepositoryMemory
+func NewPaymentMemoryRepository(db *Database) storage.IPaymentStorage {
+	return &PaymentRepositoryMemory{db: db}
+}
+
+// RecordPayment stores a payment of a payment summary and adds its allocations to the paid amounts of the billed
+// quotas and single-payment purchases, both in the summary and in the purchases of the card.
+func (r *PaymentRepositoryMemory) RecordPayment(_ context.Context, payment models.Payment) (*models.Payment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, stored := range r.db.payments {
+		if stored.Code == payment.Code {
+			return nil, fmt.Errorf("payment %s already exists: %w", payment.Code, storage.ErrAlreadyExists)
+		}
+	}
+	if err := r.db.allocatePayment(payment, 1); err != nil {
+		return nil, err
+	}
+
+	stored := payment
+	stored.Allocations = append([]models.PaymentAllocation{}, payment.Allocations...)
+	r.db.payments = append(r.db.payments, stored)
+
+	logger.Info("Payment %s of %s recorded for card %s in %02d/%d", payment.Code, payment.Amount, payment.CardNumber, payment.Month, payment.Year)
+	result := stored
+	result.Allocations = append([]models.PaymentAllocation{}, stored.Allocations...)
+	return &result, nil
+}
+
+// GetPayments retrieves the payments of the summary of a card for a month, ordered by payment date.
+func (r *PaymentRepositoryMemory) GetPayments(_ context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, err := r.db.findSummary(cardNumber, month, year); err != nil {
+		return nil, err
+	}
+
+	payments := []models.Payment{}
+	for _, stored := range r.db.payments {
+		if stored.CardNumber == cardNumber && stored.Month == month && stored.Year == year {
+			payment := stored
+			payment.Allocations = append([]models.PaymentAllocation{}, stored.Allocations...)
+			payments = append(payments, payment)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].PaymentDate.Before(payments[j].PaymentDate) })
+	return &payments, nil
+}
+
+// GetPaymentSummaries retrieves the stored payment summaries of a card, ordered by year and month.
+func (r *PaymentRepositoryMemory) GetPaymentSummaries(_ context.Context, cardNumber string) (*[]models.PaymentSummary, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	record, err := r.db.findCard(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	card := toCard(record)
+	card.Bank = r.db.bankOf(record.card.Bank.Cuit)
+	summaries := []models.PaymentSummary{}
+	for _, stored := range r.db.summaries {
+		if stored.cardNumber == cardNumber {
+			summary := stored.summary
+			summary.Card = *card
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Year != summaries[j].Year {
+			return summaries[i].Year < summaries[j].Year
+		}
+		return summaries[i].Month < summaries[j].Month
+	})
+	return &summaries, nil
+}
+
+// findSummary returns the stored payment summary of a card for a month or a wrapped storage.ErrNotFound.
+// The caller must hold the lock.
+func (db *Database) findSummary(cardNumber string, month int, year int) (*summaryRecord, error) {
+	if _, err := db.findCard(cardNumber); err != nil {
+		return nil, err
+	}
+	for _, stored := range db.summaries {
+		if stored.cardNumber == cardNumber && stored.summary.Month == month && stored.summary.Year == year {
+			return stored, nil
+		}
+	}
+	return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+}
+
+// allocatePayment adds the allocations of a payment, times sign, to the paid amounts of the items they name in the
+// summary and in the purchases of the card. Nothing changes unless every item is found and, when adding, stays within
+// the billed amount of its allocation. The caller must hold the lock.
+func (db *Database) allocatePayment(payment models.Payment, sign models.Money) error {
+	stored, err := db.findSummary(payment.CardNumber, payment.Month, payment.Year)
+	if err != nil {
+		return err
+	}
+	record, _ := db.findCard(payment.CardNumber)
+
+	var paidAmounts []*models.Money
+	amounts := []models.Money{}
+	for _, allocation := range payment.Allocations {
+		billed, live := findBilledItem(&stored.summary, record, allocation)
+		if billed == nil || live == nil {
+			return fmt.Errorf("summary of card %s in %02d/%d bills no %s: %w", payment.CardNumber, payment.Month, payment.Year, describeAllocation(allocation), storage.ErrNotFound)
+		}
+		if sign > 0 && *billed+allocation.Amount > allocation.BilledAmount {
+			return fmt.Errorf("allocation of %s exceeds what is left to pay of %s: %w", allocation.Amount, describeAllocation(allocation), storage.ErrAlreadyExists)
+		}
+		paidAmounts = append(paidAmounts, billed, live)
+		amounts = append(amounts, allocation.Amount, allocation.Amount)
+	}
+	for i, paidAmount := range paidAmounts {
+		*paidAmount += sign * amounts[i]
+	}
+	return nil
+}
+
+// findBilledItem returns the paid amount of the item an allocation names in a summary and in the purchases of the card.
+// Installment purchases in a summary share their quotas with the purchases of the card, so only due quotas are copies.
+func findBilledItem(summary *models.PaymentSummary, record *cardRecord, allocation models.PaymentAllocation) (*models.Money, *models.Money) {
+	var billed, live *models.Money
+	if allocation.PurchaseDate == nil {
+		for i := range summary.Quotas {
+			quota := &summary.Quotas[i]
+			if quota.PaymentVoucher == allocation.PaymentVoucher && quota.Number == allocation.QuotaNumber {
+				billed = &quota.PaidAmount
+			}
+		}
+		months := quotaMonths(summary.Month)
+		for i := range record.monthlyPayments {
+			purchase := &record.monthlyPayments[i]
+			if purchase.PaymentVoucher != allocation.PaymentVoucher {
+				continue
+			}
+			for j := range purchase.Quota {
+				quota := &purchase.Quota[j]
+				if quota.Number == allocation.QuotaNumber && months[quota.Month] && quota.Year == strconv.Itoa(summary.Year) {
+					live = &quota.PaidAmount
+				}
+			}
+		}
+		return billed, live
+	}
+
+	for i := range summary.SinglePayments {
+		purchase := &summary.SinglePayments[i]
+		if purchase.PaymentVoucher == allocation.PaymentVoucher && purchase.PurchaseDate.Equal(*allocation.PurchaseDate) {
+			billed = &purchase.PaidAmount
+		}
+	}
+	for i := range record.singlePayments {
+		purchase := &record.singlePayments[i]
+		if purchase.PaymentVoucher == allocation.PaymentVoucher && purchase.PurchaseDate.Equal(*allocation.PurchaseDate) {
+			live = &purchase.PaidAmount
+		}
+	}
+	return billed, live
+}
+
+// describeAllocation names the item an allocation is applied to, for error messages.
+func describeAllocation(allocation models.PaymentAllocation) string {
+	if allocation.PurchaseDate == nil {
+		return fmt.Sprintf("quota %d of purchase %s", allocation.QuotaNumber, allocation.PaymentVoucher)
+	}
+	return fmt.Sprintf("purchase %s of %s", allocation.PaymentVoucher, allocation.PurchaseDate.Format("2006-01-02"))
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+)
+
+type PromotionRepositoryMemory struct {
+	db *Database
+}
+
+// NewPromotionMemoryRepository creates a new instance of PromotionRepositoryMemory
+func NewPromotionMemoryRepository(db *Database) storage.IPromotionStorage {
+	return &PromotionRepositoryMemory{db: db}
+}
+
+// GetAvailablePromotionsByStoreAndDateRange retrieves the non-deleted promotions of a store that lie within or cover the given range.
+func (r *PromotionRepositoryMemory) GetAvailablePromotionsByStoreAndDateRange(_ context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var promotionsDiscount []models.Discount
+	var promotionsFinancing []models.Financing
+
+	for _, record := range r.db.discounts {
+		if record.discount.CuitStore == cuit && record.availableIn(startDate, endDate) {
+			promotionsDiscount = append(promotionsDiscount, r.db.toDiscount(record))
+		}
+	}
+	for _, record := range r.db.financings {
+		if record.financing.CuitStore == cuit && record.availableIn(startDate, endDate) {
+			promotionsFinancing = append(promotionsFinancing, r.db.toFinancing(record))
+		}
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// GetMostUsedPromotion retrieves the promotion whose code is the most repeated payment voucher across all purchases.
+// Vouchers with the same number of uses are ordered alphabetically.
+func (r *PromotionRepositoryMemory) GetMostUsedPromotion(_ context.Context) (interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	uses := map[string]int{}
+	for _, record := range r.db.cards {
+		for _, purchase := range record.singlePayments {
+			uses[purchase.PaymentVoucher]++
+		}
+		for _, purchase := range record.monthlyPayments {
+			uses[purchase.PaymentVoucher]++
+		}
+	}
+
+	vouchers := make([]string, 0, len(uses))
+	for voucher := range uses {
+		vouchers = append(vouchers, voucher)
+	}
+	sort.Slice(vouchers, func(i, j int) bool {
+		if uses[vouchers[i]] != uses[vouchers[j]] {
+			return uses[vouchers[i]] > uses[vouchers[j]]
+		}
+		return vouchers[i] < vouchers[j]
+	})
+	if len(vouchers) == 0 {
+		return nil, fmt.Errorf("no purchases registered: %w", storage.ErrNotFound)
+	}
+
+	if record := r.db.findFinancing(vouchers[0]); record != nil {
+		financing := r.db.toFinancing(record)
+		return &financing, nil
+	}
+	if record := r.db.findDiscount(vouchers[0]); record != nil {
+		discount := r.db.toDiscount(record)
+		return &discount, nil
+	}
+	return nil, fmt.Errorf("could not find promotion with code %s: %w", vouchers[0], storage.ErrNotFound)
+}
+
+// GetApplicablePromotions retrieves the non-deleted promotions of a bank for a store that are valid on the given date.
+func (r *PromotionRepositoryMemory) GetApplicablePromotions(_ context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	promotionsDiscount := []models.Discount{}
+	promotionsFinancing := []models.Financing{}
+
+	for _, record := range r.db.discounts {
+		if record.bankCuit == bankCuit && record.discount.CuitStore == storeCuit && record.validOn(date) {
+			promotionsDiscount = append(promotionsDiscount, r.db.toDiscount(record))
+		}
+	}
+	for _, record := range r.db.financings {
+		if record.bankCuit == bankCuit && record.financing.CuitStore == storeCuit && record.validOn(date) {
+			promotionsFinancing = append(promotionsFinancing, r.db.toFinancing(record))
+		}
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+func (r *PromotionRepositoryMemory) GetPromotionByCode(_ context.Context, code string) (*models.PromotionDetail, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if record := r.db.findDiscount(code); record != nil {
+		discount := r.db.toDiscount(record)
+		return &models.PromotionDetail{Type: models.PromotionTypeDiscount, IsDeleted: record.isDeleted, Discount: &discount}, nil
+	}
+	if record := r.db.findFinancing(code); record != nil {
+		financing := r.db.toFinancing(record)
+		return &models.PromotionDetail{Type: models.PromotionTypeFinancing, IsDeleted: record.isDeleted, Financing: &financing}, nil
+	}
+	return nil, fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// GetBankPromotions retrieves the promotions of a bank that have the given status on the given date,
+// ordered by validity start date and code.
+func (r *PromotionRepositoryMemory) GetBankPromotions(_ context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, err := r.db.findBank(bankCuit); err != nil {
+		return nil, nil, err
+	}
+
+	var discountRecords []*discountRecord
+	for _, record := range r.db.discounts {
+		if record.bankCuit == bankCuit && record.hasStatus(status, date) {
+			discountRecords = append(discountRecords, record)
+		}
+	}
+	sort.SliceStable(discountRecords, func(i, j int) bool {
+		return promotionBefore(discountRecords[i].promotionRecord, discountRecords[i].discount.Code, discountRecords[j].promotionRecord, discountRecords[j].discount.Code)
+	})
+
+	var financingRecords []*financingRecord
+	for _, record := range r.db.financings {
+		if record.bankCuit == bankCuit && record.hasStatus(status, date) {
+			financingRecords = append(financingRecords, record)
+		}
+	}
+	sort.SliceStable(financingRecords, func(i, j int) bool {
+		return promotionBefore(financingRecords[i].promotionRecord, financingRecords[i].financing.Code, financingRecords[j].promotionRecord, financingRecords[j].financing.Code)
+	})
+
+	promotionsDiscount := []models.Discount{}
+	for _, record := range discountRecords {
+		promotionsDiscount = append(promotionsDiscount, r.db.toDiscount(record))
+	}
+	promotionsFinancing := []models.Financing{}
+	for _, record := range financingRecords {
+		promotionsFinancing = append(promotionsFinancing, r.db.toFinancing(record))
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// UpdatePromotion applies the given changes to a promotion. Changes that do not apply to its type are ignored.
+func (r *PromotionRepositoryMemory) UpdatePromotion(_ context.Context, code string, update models.PromotionUpdate) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if record := r.db.findDiscount(code); record != nil {
+		applyPromotionUpdate(&record.discount.Promotion, update)
+		if update.DiscountPercentage != nil {
+			record.discount.DiscountPercentage = *update.DiscountPercentage
+		}
+		if update.PriceCap != nil {
+			record.discount.PriceCap = *update.PriceCap
+		}
+		if update.OnlyCash != nil {
+			record.discount.OnlyCash = *update.OnlyCash
+		}
+		logger.Info("Promotion %s updated", code)
+		return nil
+	}
+
+	if record := r.db.findFinancing(code); record != nil {
+		applyPromotionUpdate(&record.financing.Promotion, update)
+		if update.NumberOfQuotas != nil {
+			record.financing.NumberOfQuotas = *update.NumberOfQuotas
+		}
+		if update.Interest != nil {
+			record.financing.Interest = *update.Interest
+		}
+		logger.Info("Promotion %s updated", code)
+		return nil
+	}
+
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// RestorePromotion clears the logical delete of a promotion.
+func (r *PromotionRepositoryMemory) RestorePromotion(_ context.Context, code string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if record := r.db.findDiscount(code); record != nil {
+		record.isDeleted = false
+		logger.Info("Promotion %s restored", code)
+		return nil
+	}
+	if record := r.db.findFinancing(code); record != nil {
+		record.isDeleted = false
+		logger.Info("Promotion %s restored", code)
+		return nil
+	}
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// applyPromotionUpdate applies the changes common to every promotion type.
+func applyPromotionUpdate(promotion *models.Promotion, update models.PromotionUpdate) {
+	if update.PromotionTitle != nil {
+		promotion.PromotionTitle = *update.PromotionTitle
+	}
+	if update.Comments != nil {
+		promotion.Comments = *update.Comments
+	}
+}
+
+// promotionBefore orders promotions by validity start date and code.
+func promotionBefore(a promotionRecord, codeA string, b promotionRecord, codeB string) bool {
+	if !a.startDate.Equal(b.startDate) {
+		return a.startDate.Before(b.startDate)
+	}
+	return codeA < codeB
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addTestPromotions registers an October 2024 discount and financing and a 2025 discount for the same store.
+func addTestPromotions(t *testing.T, db *Database) {
+	ctx := context.Background()
+	t.Helper()
+	bankRepo := NewBankMemoryRepository(db)
+	promotion := func(code string, start string, end string) models.Promotion {
+		return models.Promotion{
+			Code:              code,
+			NameStore:         "Store A",
+			CuitStore:         "20-98765432-1",
+			ValidityStartDate: start,
+			ValidityEndDate:   end,
+			Bank:              models.Bank{Cuit: "30-12345678-9"},
+		}
+	}
+
+	require.NoError(t, bankRepo.AddDiscountPromotionToBank(ctx, models.Discount{Promotion: promotion("DISC-OCT", "2024-10-05T00:00:00Z", "2024-10-20T00:00:00Z"), DiscountPercentage: models.MustParsePercentage("10")}))
+	require.NoError(t, bankRepo.AddFinancingPromotionToBank(ctx, models.Financing{Promotion: promotion("PV20241001", "2024-09-01T00:00:00Z", "2024-12-31T00:00:00Z"), NumberOfQuotas: 6}))
+	require.NoError(t, bankRepo.AddDiscountPromotionToBank(ctx, models.Discount{Promotion: promotion("DISC-2025", "2025-01-01T00:00:00Z", "2025-12-31T00:00:00Z"), DiscountPercentage: models.MustParsePercentage("5")}))
+}
+
+func TestGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	addTestPromotions(t, db)
+	promotionRepo := NewPromotionMemoryRepository(db)
+
+	// The discount lies within the range and the financing covers it
+	startDate := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)
+	financings, discounts, err := promotionRepo.GetAvailablePromotionsByStoreAndDateRange(ctx, "20-98765432-1", startDate, startDate.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Len(t, *discounts, 1)
+	assert.Equal(t, "DISC-OCT", (*discounts)[0].Code)
+	assert.Len(t, *financings, 1)
+
+	assert.NoError(t, NewBankMemoryRepository(db).DeleteFinancingPromotion(ctx, "PV20241001"))
+	financings, _, err = promotionRepo.GetAvailablePromotionsByStoreAndDateRange(ctx, "20-98765432-1", startDate, startDate.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Empty(t, *financings)
+}
+
+func TestGetBankPromotions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	addTestPromotions(t, db)
+	promotionRepo := NewPromotionMemoryRepository(db)
+	assert.NoError(t, NewBankMemoryRepository(db).DeleteDiscountPromotion(ctx, "DISC-2025"))
+
+	date := time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC)
+	financings, discounts, err := promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusActive, date)
+	assert.NoError(t, err)
+	assert.Len(t, *financings, 1)
+	assert.Empty(t, *discounts)
+
+	_, discounts, err = promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusExpired, date)
+	assert.NoError(t, err)
+	assert.Equal(t, "DISC-OCT", (*discounts)[0].Code)
+
+	_, discounts, err = promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusDeleted, date)
+	assert.NoError(t, err)
+	assert.Equal(t, "DISC-2025", (*discounts)[0].Code)
+
+	assert.NoError(t, promotionRepo.RestorePromotion(ctx, "DISC-2025"))
+	_, discounts, err = promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusDeleted, date)
+	assert.NoError(t, err)
+	assert.Empty(t, *discounts)
+
+	_, _, err = promotionRepo.GetBankPromotions(ctx, "30-00000000-0", models.PromotionStatusActive, date)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestUpdatePromotion(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	addTestPromotions(t, db)
+	promotionRepo := NewPromotionMemoryRepository(db)
+
+	title := "Spring Sale"
+	quotas := 12
+	percentage := models.MustParsePercentage("15")
+	assert.NoError(t, promotionRepo.UpdatePromotion(ctx, "DISC-2025", models.PromotionUpdate{PromotionTitle: &title, DiscountPercentage: &percentage, NumberOfQuotas: &quotas}))
+
+	detail, err := promotionRepo.GetPromotionByCode(ctx, "DISC-2025")
+	assert.NoError(t, err)
+	assert.Equal(t, "Spring Sale", detail.Discount.PromotionTitle)
+	assert.Equal(t, percentage, detail.Discount.DiscountPercentage)
+
+	assert.ErrorIs(t, promotionRepo.UpdatePromotion(ctx, "UNKNOWN", models.PromotionUpdate{PromotionTitle: &title}), storage.ErrNotFound)
+}
+
+func TestGetMostUsedPromotion(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	addTestPromotions(t, db)
+	promotionRepo := NewPromotionMemoryRepository(db)
+
+	_, err := promotionRepo.GetMostUsedPromotion(ctx)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	cardRepo := NewCardMemoryRepository(db)
+	for _, voucher := range []string{"PV20241001", "DISC-OCT"} {
+		_, err := cardRepo.AddPurchaseSinglePayment(ctx, "1234567812345678", models.PurchaseSinglePayment{Purchase: models.Purchase{PaymentVoucher: voucher}})
+		assert.NoError(t, err)
+	}
+	_, err = cardRepo.AddPurchaseMonthlyPayment(ctx, "1234567812345678", models.PurchaseMonthlyPayment{Purchase: models.Purchase{PaymentVoucher: "PV20241001"}})
+	assert.NoError(t, err)
+
+	mostUsed, err := promotionRepo.GetMostUsedPromotion(ctx)
+	assert.NoError(t, err)
+	financing, ok := mostUsed.(*models.Financing)
+	assert.True(t, ok, "expected a financing, got %T", mostUsed)
+	assert.Equal(t, "PV20241001", financing.Code)
+}
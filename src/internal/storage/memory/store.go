@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+)
+
+type StoreRepositoryMemory struct {
+	db *Database
+}
+
+// NewStoreMemoryRepository creates a new instance of StoreRepositoryMemory
+func NewStoreMemoryRepository(db *Database) storage.IStoreStorage {
+	return &StoreRepositoryMemory{db: db}
+}
+
+// GetStoreWithHighestRevenueByMonth retrieves the store with the highest sum of final amounts among the purchases of the month.
+// It returns an empty store when there are no purchases in the month.
+func (r *StoreRepositoryMemory) GetStoreWithHighestRevenueByMonth(_ context.Context, month int, year int) (models.StoreDTO, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	revenues := map[models.StoreDTO]models.Money{}
+	inMonth := func(date time.Time) bool { return int(date.Month()) == month && date.Year() == year }
+
+	for _, record := range r.db.cards {
+		for _, purchase := range record.singlePayments {
+			if inMonth(purchase.PurchaseDate) {
+				revenues[models.StoreDTO{Name: purchase.Store, Cuit: purchase.CuitStore}] += purchase.FinalAmount
+			}
+		}
+		for _, purchase := range record.monthlyPayments {
+			if inMonth(purchase.PurchaseDate) {
+				revenues[models.StoreDTO{Name: purchase.Store, Cuit: purchase.CuitStore}] += purchase.FinalAmount
+			}
+		}
+	}
+
+	// Stores with the same revenue are ordered by CUIT so the result does not depend on map iteration
+	var result models.StoreDTO
+	var highest models.Money
+	for store, revenue := range revenues {
+		if result == (models.StoreDTO{}) || revenue > highest || (revenue == highest && store.Cuit < result.Cuit) {
+			result = store
+			highest = revenue
+		}
+	}
+
+	return result, nil
+}
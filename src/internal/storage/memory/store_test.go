@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStoreWithHighestRevenueByMonth(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	cardRepo := NewCardMemoryRepository(db)
+	storeRepo := NewStoreMemoryRepository(db)
+
+	october := time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC)
+	purchase := func(voucher string, store string, cuit string, amount string, date time.Time) models.Purchase {
+		return models.Purchase{PaymentVoucher: voucher, Store: store, CuitStore: cuit, Amount: models.MustParseMoney(amount), FinalAmount: models.MustParseMoney(amount), PurchaseDate: date}
+	}
+
+	_, err := cardRepo.AddPurchaseSinglePayment(ctx, "1234567812345678", models.PurchaseSinglePayment{Purchase: purchase("V1", "Store N", "30-15066777-9", "500", october)})
+	require.NoError(t, err)
+	_, err = cardRepo.AddPurchaseSinglePayment(ctx, "1234567812345678", models.PurchaseSinglePayment{Purchase: purchase("V2", "Store O", "30-15066778-9", "300", october)})
+	require.NoError(t, err)
+	_, err = cardRepo.AddPurchaseMonthlyPayment(ctx, "1234567812345678", models.PurchaseMonthlyPayment{Purchase: purchase("V3", "Store O", "30-15066778-9", "400", october)})
+	require.NoError(t, err)
+	_, err = cardRepo.AddPurchaseSinglePayment(ctx, "1234567812345678", models.PurchaseSinglePayment{Purchase: purchase("V4", "Store N", "30-15066777-9", "1000", october.AddDate(0, 1, 0))})
+	require.NoError(t, err)
+
+	result, err := storeRepo.GetStoreWithHighestRevenueByMonth(ctx, 10, 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StoreDTO{Name: "Store O", Cuit: "30-15066778-9"}, result)
+
+	result, err = storeRepo.GetStoreWithHighestRevenueByMonth(ctx, 1, 2020)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StoreDTO{}, result)
+}
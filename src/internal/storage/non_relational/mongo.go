@@ -73,7 +73,18 @@ func initMongoDB(ctx context.Context, db *mongo.Database, cleanDB bool) error {
 		}
 	}
 
-	// Create indexes or seed data here if needed
+	// Apply the declared indexes and validators, reporting what had to change
+	changes, err := EnsureSchema(ctx, db)
+	for _, change := range changes {
+		logger.Info("MongoDB schema: %s", change)
+	}
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		logger.Info("MongoDB schema is up to date.")
+	}
+
 	logger.Info("MongoDB schema initialized successfully.")
 	return nil
 }
@@ -12,6 +12,7 @@ package nonrelational
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,7 +22,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.uber.org/zap"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type BankRepositoryMongo struct {
@@ -32,14 +33,73 @@ func NewBankNonRelationalRepository(db *mongo.Database) storage.IBankStorage {
 	return &BankRepositoryMongo{db: db}
 }
 
-// AddFinancingPromotionToBank adds a financing promotion to a bank.
-func (r *BankRepositoryMongo) AddFinancingPromotionToBank(promotionFinancing models.Financing) error {
-	ctx := context.Background()
+// CreateBank registers a new bank. The CUIT is unique among banks, enforced by the unique index on banks.cuit.
+func (r *BankRepositoryMongo) CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error) {
+	if _, err := r.db.Collection("banks").InsertOne(ctx, entities.ToBankEntityNonSQL(&bank)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("a bank with cuit %s already exists: %w", bank.Cuit, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting bank: %w", err)
+	}
 
-	bankCollection := r.db.Collection("banks")
-	var bank entities.BankEntityNonSQL
-	if err := bankCollection.FindOne(ctx, bson.M{"cuit": promotionFinancing.Promotion.Bank.Cuit}).Decode(&bank); err != nil {
-		return fmt.Errorf("could not find bank with 'cuit' %s: %w", promotionFinancing.Promotion.Bank.Cuit, err)
+	logger.Info("Bank %s registered", bank.Cuit)
+	return r.GetBankByCuit(ctx, bank.Cuit)
+}
+
+// GetBanks retrieves all banks ordered by CUIT.
+func (r *BankRepositoryMongo) GetBanks(ctx context.Context) (*[]models.Bank, error) {
+	cursor, err := r.db.Collection("banks").Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "cuit", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving banks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var bankEntities []entities.BankEntityNonSQL
+	if err := cursor.All(ctx, &bankEntities); err != nil {
+		return nil, fmt.Errorf("error decoding banks: %w", err)
+	}
+
+	banks := []models.Bank{}
+	for _, bank := range bankEntities {
+		banks = append(banks, *entities.ToBankNonSQL(&bank))
+	}
+	return &banks, nil
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+func (r *BankRepositoryMongo) GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error) {
+	bank, err := findBankByCuit(ctx, r.db, cuit)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ToBankNonSQL(bank), nil
+}
+
+// UpdateBank replaces the name, address and telephone of a bank.
+func (r *BankRepositoryMongo) UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	update := bson.M{"$set": bson.M{
+		"name":       bank.Name,
+		"address":    bank.Address,
+		"telephone":  bank.Telephone,
+		"updated_at": time.Now(),
+	}}
+	result, err := r.db.Collection("banks").UpdateOne(ctx, bson.M{"cuit": cuit}, update)
+	if err != nil {
+		return nil, fmt.Errorf("error updating bank %s: %w", cuit, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound)
+	}
+
+	logger.Info("Bank %s updated", cuit)
+	return r.GetBankByCuit(ctx, cuit)
+}
+
+// AddFinancingPromotionToBank adds a financing promotion to a bank.
+func (r *BankRepositoryMongo) AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error {
+	bank, err := findBankByCuit(ctx, r.db, promotionFinancing.Promotion.Bank.Cuit)
+	if err != nil {
+		return err
 	}
 
 	// Fetched bank
@@ -72,6 +132,7 @@ func (r *BankRepositoryMongo) AddFinancingPromotionToBank(promotionFinancing mod
 		},
 		NumberOfQuotas: promotionFinancing.NumberOfQuotas,
 		Interest:       promotionFinancing.Interest,
+		InterestModel:  string(promotionFinancing.InterestModel.OrFlat()),
 		IsDeleted:      false,
 		BankID:         bank.ID,
 		CreatedAt:      time.Now(),
@@ -80,6 +141,9 @@ func (r *BankRepositoryMongo) AddFinancingPromotionToBank(promotionFinancing mod
 
 	_, err = r.db.Collection("financings").InsertOne(ctx, financingEntity)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("a financing promotion with code %s already exists: %w", promotionFinancing.Code, storage.ErrAlreadyExists)
+		}
 		logger.Error("Failed to add financing promotion %v", err)
 		return fmt.Errorf("could not add financing promotion: %w", err)
 	}
@@ -91,10 +155,26 @@ func (r *BankRepositoryMongo) AddFinancingPromotionToBank(promotionFinancing mod
 
 }
 
-// ExtendPromotionValidity extends the validity of a promotion.
-func (r *BankRepositoryMongo) ExtendPromotionValidity(code string, newDate time.Time) error {
+// AddDiscountPromotionToBank adds a discount promotion to the bank identified by the promotion's bank CUIT.
+func (r *BankRepositoryMongo) AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error {
+	bank, err := findBankByCuit(ctx, r.db, promotionDiscount.Bank.Cuit)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.Collection("discounts").InsertOne(ctx, entities.ToDiscountEntityNonSQL(&promotionDiscount, bank.ID)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("a discount promotion with code %s already exists: %w", promotionDiscount.Code, storage.ErrAlreadyExists)
+		}
+		return fmt.Errorf("could not add discount promotion %s: %w", promotionDiscount.Code, err)
+	}
 
-	ctx := context.Background()
+	logger.Info("Discount promotion %s added to bank %s", promotionDiscount.Code, bank.Cuit)
+	return nil
+}
+
+// ExtendPromotionValidity extends the validity of a promotion.
+func (r *BankRepositoryMongo) ExtendPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
 
 	// Update the promotion
 	filter := bson.M{"code": code}
@@ -116,9 +196,7 @@ func (r *BankRepositoryMongo) ExtendPromotionValidity(code string, newDate time.
 }
 
 // DeletePromotion marks a promotion as deleted.
-func (r *BankRepositoryMongo) DeletePromotion(code string) error {
-
-	ctx := context.Background()
+func (r *BankRepositoryMongo) DeletePromotion(ctx context.Context, code string) error {
 
 	// Mark the promotion as deleted
 	filter := bson.M{"promotion_entity.code": code}
@@ -132,90 +210,39 @@ func (r *BankRepositoryMongo) DeletePromotion(code string) error {
 	return nil
 }
 
-// DeleteFinancingPromotion deletes a financing promotion from the bank.
-func (r *BankRepositoryMongo) DeleteFinancingPromotion(code string) error {
-
-	ctx := context.Background()
-
-	// Find the promotion by code
-	promotionCollection := r.db.Collection("financings")
-	var promotion bson.M
-	if err := promotionCollection.FindOne(ctx, bson.M{"promotion_entity.code": code}).Decode(&promotion); err != nil {
-		return fmt.Errorf("could not find promotion with code %s: %w", code, err)
-	}
-
-	// Delete the promotion
-	_, err := promotionCollection.DeleteOne(ctx, bson.M{"code": code})
-	if err != nil {
-		logger.Error("Failed to delete financing promotion: %v", zap.Error(err))
-		return fmt.Errorf("could not delete financing promotion: %w", err)
+// DeleteFinancingPromotion logically deletes a financing promotion by marking it as deleted.
+func (r *BankRepositoryMongo) DeleteFinancingPromotion(ctx context.Context, code string) error {
+	if err := r.setPromotionFields(ctx, "financings", code, bson.M{"is_deleted": true}); err != nil {
+		return err
 	}
 
 	logger.Info("Successfully deleted financing promotion %s", code)
 	return nil
 }
 
-// DeleteDiscountPromotion deletes a discount promotion from the bank.
-func (r *BankRepositoryMongo) DeleteDiscountPromotion(code string) error {
-
-	ctx := context.Background()
-
-	// Find the promotion by code
-	promotionCollection := r.db.Collection("discounts")
-	var promotion bson.M
-	if err := promotionCollection.FindOne(ctx, bson.M{"promotion_entity.code": code}).Decode(&promotion); err != nil {
-		return fmt.Errorf("could not find promotion with code %s: %w", code, err)
-	}
-
-	// Delete the promotion
-	_, err := promotionCollection.DeleteOne(ctx, bson.M{"code": code})
-	if err != nil {
-		logger.Error("Failed to delete discount promotion: %v", zap.Error(err))
-		return fmt.Errorf("could not delete discount promotion: %w", err)
+// DeleteDiscountPromotion logically deletes a discount promotion by marking it as deleted.
+func (r *BankRepositoryMongo) DeleteDiscountPromotion(ctx context.Context, code string) error {
+	if err := r.setPromotionFields(ctx, "discounts", code, bson.M{"is_deleted": true}); err != nil {
+		return err
 	}
 
 	logger.Info("Successfully deleted discount promotion %s", code)
 	return nil
 }
 
-// ExtendDiscountPromotionValidity extends the validity of a promotion.
-func (r *BankRepositoryMongo) ExtendDiscountPromotionValidity(code string, newDate time.Time) error {
-	ctx := context.Background()
-
-	// Update the promotion
-	filter := bson.M{"code": code}
-	update := bson.M{"$set": bson.M{"validity_end_date": newDate}}
-	result, err := r.db.Collection("discounts").UpdateOne(ctx, filter, update)
-	if err != nil {
-		return fmt.Errorf("failed to update promotion %s: %w", code, err)
-	}
-
-	// If no document was modified, return an explicit error
-	if result.ModifiedCount == 0 {
-		return fmt.Errorf("no promotion found with code %s or validity date was already up to date", code)
+// ExtendDiscountPromotionValidity extends the validity of a discount promotion.
+func (r *BankRepositoryMongo) ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	if err := r.setPromotionFields(ctx, "discounts", code, bson.M{"promotion_entity.validity_end_date": newDate}); err != nil {
+		return err
 	}
 
-	logger.Info("Successfully extended promotion %s validity to %s", code, newDate)
+	logger.Info("Successfully extended discount promotion %s validity. New end date: '%s'", code, newDate)
 	return nil
 }
 
-// ExtendFinancingPromotionValidity extends the validity of a promotion.
-func (r *BankRepositoryMongo) ExtendFinancingPromotionValidity(code string, newDate time.Time) error {
-
-	ctx := context.Background()
-
-	// Update the promotion
-	filter := bson.M{"promotion_entity.code": code}
-	update := bson.M{"$set": bson.M{"validity_end_date": newDate}}
-	result, err := r.db.Collection("financings").UpdateOne(ctx, filter, update)
-	if err != nil {
-		logger.Error("Failed to update promotion %s: %v", code, err)
-		return err
-	}
-
-	// If no document was modified, return an explicit error
-	if result.ModifiedCount == 0 {
-		logger.Error("No promotion found with code %s or validity date was already up to date", code)
+// ExtendFinancingPromotionValidity extends the validity of a financing promotion.
+func (r *BankRepositoryMongo) ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	if err := r.setPromotionFields(ctx, "financings", code, bson.M{"promotion_entity.validity_end_date": newDate}); err != nil {
 		return err
 	}
 
@@ -224,25 +251,14 @@ func (r *BankRepositoryMongo) ExtendFinancingPromotionValidity(code string, newD
 }
 
 // GetBankCustomerCounts retrieves the number of customers for each bank.
-func (r *BankRepositoryMongo) GetBankCustomerCounts() ([]models.BankCustomerCountDTO, error) {
-	ctx := context.Background()
-
+func (r *BankRepositoryMongo) GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error) {
 	// Reference the banks collection
 	bankCollection := r.db.Collection("banks")
 	pipeline := []bson.M{
-		// Lookup to join BANKS with CUSTOMERS_BANKS
-		{
-			"$lookup": bson.M{
-				"from":         "customers_banks",
-				"localField":   "_id",     // BANKS _id field
-				"foreignField": "bank_id", // CUSTOMERS_BANKS bank_id field
-				"as":           "customer_relations",
-			},
-		},
-		// Add a field to count the number of customers
+		// Memberships are kept on the bank document as references to its customers
 		{
 			"$addFields": bson.M{
-				"customer_count": bson.M{"$size": "$customer_relations"},
+				"customer_count": bson.M{"$size": bson.M{"$ifNull": bson.A{"$customers", bson.A{}}}},
 			},
 		},
 		// Project the desired fields
@@ -285,3 +301,64 @@ func (r *BankRepositoryMongo) GetBankCustomerCounts() ([]models.BankCustomerCoun
 
 	return results, nil
 }
+
+// SaveBillingCycle creates or replaces the billing cycle configuration of a bank.
+// The configuration is embedded in the bank document.
+func (r *BankRepositoryMongo) SaveBillingCycle(ctx context.Context, cycle models.BillingCycle) error {
+	filter := bson.M{"cuit": cycle.BankCuit}
+	update := bson.M{"$set": bson.M{
+		"billing_cycle": entities.ToBillingCycleEntityNonSQL(&cycle),
+		"updated_at":    time.Now(),
+	}}
+	result, err := r.db.Collection("banks").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("error saving billing cycle for bank %s: %w", cycle.BankCuit, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("could not find bank with cuit %s: %w", cycle.BankCuit, storage.ErrNotFound)
+	}
+
+	logger.Info("Billing cycle of bank %s saved: closing day %d", cycle.BankCuit, cycle.ClosingDay)
+	return nil
+}
+
+// GetBillingCycle retrieves the billing cycle configuration of a bank, or nil if the bank has not configured one.
+func (r *BankRepositoryMongo) GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error) {
+	bank, err := findBankByCuit(ctx, r.db, bankCuit)
+	if err != nil {
+		return nil, err
+	}
+	if bank.BillingCycle == nil {
+		return nil, nil
+	}
+
+	return entities.ToBillingCycleNonSQL(bank.BillingCycle, bank.Cuit), nil
+}
+
+// setPromotionFields sets the given fields on the promotion with the given code in the collection.
+// It returns a wrapped storage.ErrNotFound when no promotion has that code.
+func (r *BankRepositoryMongo) setPromotionFields(ctx context.Context, collection string, code string, fields bson.M) error {
+	fields["updated_at"] = time.Now()
+
+	result, err := r.db.Collection(collection).UpdateOne(ctx, bson.M{"promotion_entity.code": code}, bson.M{"$set": fields})
+	if err != nil {
+		logger.Error("Failed to update promotion %s: %v", code, err)
+		return fmt.Errorf("could not update promotion %s: %w", code, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+	return nil
+}
+
+// findBankByCuit retrieves the bank document with the given CUIT or a wrapped storage.ErrNotFound.
+func findBankByCuit(ctx context.Context, db *mongo.Database, cuit string) (*entities.BankEntityNonSQL, error) {
+	var bank entities.BankEntityNonSQL
+	if err := db.Collection("banks").FindOne(ctx, bson.M{"cuit": cuit}).Decode(&bank); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find bank with cuit %s: %w", cuit, err)
+	}
+	return &bank, nil
+}
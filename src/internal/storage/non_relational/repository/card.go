@@ -2,7 +2,9 @@ package nonrelational
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
@@ -11,6 +13,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type CardRepositoryMongo struct {
@@ -22,152 +25,132 @@ func NewCardNonRelationalRepository(db *mongo.Database) storage.ICardStorage {
 	return &CardRepositoryMongo{db: db}
 }
 
-func (r *CardRepositoryMongo) GetPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error) {
-	collection := r.db.Collection("cards")
+// GetPaymentSummary retrieves the stored payment summary of a card for a month.
+func (r *CardRepositoryMongo) GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, err
+	}
 
-	// Define date range for the month
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0) // Next month
+	logger.Info("Fetching payment summary for card '%s' for %02d/%d", cardNumber, month, year)
 
-	logger.Info("Fetching payment summary for card '%s' from %s to %s", cardNumber, startDate, endDate)
+	filter := bson.M{"card_number": cardNumber, "month": month, "year": year}
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
 
-	// MongoDB Aggregation Pipeline
-	pipeline := []bson.M{
-		// 1) Match the specific card
-		{"$match": bson.M{"number": cardNumber}},
+	var paymentSummary entities.PaymentSummaryEntityNonSQL
+	if err := r.db.Collection("payment_summaries").FindOne(ctx, filter, opts).Decode(&paymentSummary); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error fetching payment summary from MongoDB: %w", err)
+	}
 
-		// 2) Lookup Bank details
-		{"$lookup": bson.M{
-			"from":         "banks",
-			"localField":   "bank_cuit",
-			"foreignField": "cuit",
-			"as":           "bank",
-		}},
-		{"$unwind": bson.M{"path": "$bank", "preserveNullAndEmptyArrays": true}},
-
-		// 3) Lookup Customer details
-		{"$lookup": bson.M{
-			"from":         "customers",
-			"localField":   "customer_cuit",
-			"foreignField": "cuit",
-			"as":           "customer",
-		}},
-		{"$unwind": bson.M{"path": "$customer", "preserveNullAndEmptyArrays": true}},
-
-		// 4) Lookup Single Payments *with* date filtering in the sub-pipeline
-		{
-			"$lookup": bson.M{
-				"from": "purchase_single_payments",
-				"let":  bson.M{"cNumber": "$number"},
-				"pipeline": []bson.M{
-					// Match same card_number
-					{
-						"$match": bson.M{
-							"$expr": bson.M{"$eq": []interface{}{"$purchase.card_number", "$$cNumber"}},
-						},
-					},
-					// Match created_at in range
-					{
-						"$match": bson.M{
-							"purchase.created_at": bson.M{"$gte": startDate, "$lt": endDate},
-						},
-					},
-				},
-				"as": "single_payments",
-			},
-		},
+	return entities.ToPaymentSummary(&paymentSummary), nil
+}
 
-		// 5) Lookup Monthly Payments *with* date filtering in the sub-pipeline
-		{
-			"$lookup": bson.M{
-				"from": "purchase_monthly_payments",
-				"let":  bson.M{"cNumber": "$number"},
-				"pipeline": []bson.M{
-					// Match same card_number
-					{
-						"$match": bson.M{
-							"$expr": bson.M{"$eq": []interface{}{"$purchase.card_number", "$$cNumber"}},
-						},
-					},
-					// Match created_at in range
-					{
-						"$match": bson.M{
-							"purchase.created_at": bson.M{"$gte": startDate, "$lt": endDate},
-						},
-					},
-				},
-				"as": "monthly_payments",
-			},
-		},
+// SavePaymentSummary stores the payment summary of a card for a month.
+// The billed purchases are embedded in the summary document as a snapshot.
+func (r *CardRepositoryMongo) SavePaymentSummary(ctx context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, err
+	}
 
-		{
-			"$project": bson.M{
-				// Keep the root fields you care about
-				"number":                  1,
-				"ccv":                     1,
-				"cardholder_name_in_card": 1,
-				"bank_cuit":               1,
-				"customer_cuit":           1,
-				"created_at":              1,
-				"updated_at":              1,
-
-				// Bring along the arrays
-				"single_payments":  1,
-				"monthly_payments": 1,
-
-				// Example total
-				"total_price": bson.M{
-					"$sum": []interface{}{
-						// Sum of all single_payments.purchase.final_amount
-						bson.M{"$sum": "$single_payments.purchase.final_amount"},
-						// Sum of all monthly_payments.purchase.final_amount
-						bson.M{"$sum": "$monthly_payments.purchase.final_amount"},
-					},
-				},
-			},
-		},
+	summary.Card.Number = cardNumber
+	paymentSummary := entities.ToPaymentSummaryEntityNonRelational(&summary)
+
+	// Insert only if the card has no summary for the month yet
+	filter := bson.M{"card_number": cardNumber, "month": summary.Month, "year": summary.Year}
+	update := bson.M{"$setOnInsert": paymentSummary}
+	result, err := r.db.Collection("payment_summaries").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting payment summary: %w", err)
+	}
+	if result.UpsertedCount == 0 {
+		return nil, fmt.Errorf("card %s already has a payment summary for %02d/%d: %w", cardNumber, summary.Month, summary.Year, storage.ErrAlreadyExists)
 	}
 
-	// Execute Aggregation Query
-	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	logger.Info("Payment summary %s stored for card %s", summary.Code, cardNumber)
+	return entities.ToPaymentSummary(paymentSummary), nil
+}
+
+// GetPurchasesInPeriod retrieves the purchases made with a card between from (inclusive) and to (exclusive).
+func (r *CardRepositoryMongo) GetPurchasesInPeriod(ctx context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, nil, err
+	}
+
+	filter := bson.M{
+		"purchase.card_number": cardNumber,
+		"purchase.created_at":  bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "purchase.created_at", Value: 1}})
+
+	cursor, err := r.db.Collection("purchase_single_payments").Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching payment summary from MongoDB: %v", err)
+		return nil, nil, fmt.Errorf("error finding single-payment purchases of card %s: %w", cardNumber, err)
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
-	// Decode Result
-	if !cursor.Next(context.TODO()) {
-		return nil, fmt.Errorf("no payment summary found for card %s", cardNumber)
+	var singlePayments []entities.PurchaseSinglePaymentEntityNonSQL
+	if err := cursor.All(ctx, &singlePayments); err != nil {
+		return nil, nil, fmt.Errorf("error decoding single-payment purchases: %w", err)
 	}
 
-	var result entities.PaymentSummaryNoSQL
-	if err := cursor.Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding payment summary: %v", err)
+	cursor, err = r.db.Collection("purchase_monthly_payments").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding monthly-payment purchases of card %s: %w", cardNumber, err)
 	}
+	defer cursor.Close(ctx)
 
-	logger.Info("Payment generated: %+v", result)
+	var monthlyPayments []entities.PurchaseMonthlyPaymentsEntityNonSQL
+	if err := cursor.All(ctx, &monthlyPayments); err != nil {
+		return nil, nil, fmt.Errorf("error decoding monthly-payment purchases: %w", err)
+	}
 
-	firstExpiration := time.Now().AddDate(0, 0, 15)       // 15 days from today
-	secondExpiration := firstExpiration.AddDate(0, 0, 10) // 10 days later
+	return entities.ConvertPurchaseSinglePaymentListMongo(&singlePayments), entities.ConvertPurchaseMonthlyPaymentListMongo(&monthlyPayments), nil
+}
 
-	code := fmt.Sprintf("SUMMARY-%d-%d", year, month)
+// GetQuotasDueInMonth retrieves the installment quotas of a card due in the given month, across all its installment purchases.
+func (r *CardRepositoryMongo) GetQuotasDueInMonth(ctx context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, err
+	}
+
+	// Months are stored zero-padded, unpadded values from older documents are matched as well
+	quotaFilter := bson.D{
+		{Key: "quotas.month", Value: bson.M{"$in": bson.A{fmt.Sprintf("%02d", month), strconv.Itoa(month)}}},
+		{Key: "quotas.year", Value: strconv.Itoa(year)},
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "purchase.card_number", Value: cardNumber}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "purchase.created_at", Value: 1}}}},
+		bson.D{{Key: "$unwind", Value: "$quotas"}},
+		bson.D{{Key: "$match", Value: quotaFilter}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "quota", Value: "$quotas"},
+			{Key: "payment_voucher", Value: "$purchase.payment_voucher"},
+			{Key: "store", Value: "$purchase.store"},
+			{Key: "cuit_store", Value: "$purchase.cuit_store"},
+			{Key: "number_of_quotas", Value: "$number_of_quotas"},
+		}}},
+	}
 
-	paymentSummary := &models.PaymentSummary{
-		Code:                code,
-		Month:               month,
-		Year:                year,
-		FirstExpiration:     firstExpiration,
-		SecondExpiration:    secondExpiration,
-		SurchargePercentage: 0, // Assuming a default value, update as needed
-		TotalPrice:          result.TotalPrice,
-		SinglePayments:      *entities.ConvertPurchaseSinglePaymentListMongo(&result.SinglePayments),
-		MonthlyPayments:     *entities.ConvertPurchaseMonthlyPaymentListMongo(&result.MonthlyPayments),
+	cursor, err := r.db.Collection("purchase_monthly_payments").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error finding quotas of card %s due in %02d/%d: %w", cardNumber, month, year, err)
+	}
+	defer cursor.Close(ctx)
+
+	var quotas []entities.DueQuotaEntityNonSQL
+	if err := cursor.All(ctx, &quotas); err != nil {
+		return nil, fmt.Errorf("error decoding due quotas: %w", err)
 	}
 
-	return paymentSummary, nil
+	return entities.ConvertDueQuotaListMongo(&quotas), nil
 }
 
-func (r *CardRepositoryMongo) GetCardsExpiringInNext30Days(day int, month int, year int) (*[]models.Card, error) {
+func (r *CardRepositoryMongo) GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error) {
 	collection := r.db.Collection("cards")
 
 	// Define the date range
@@ -177,7 +160,7 @@ func (r *CardRepositoryMongo) GetCardsExpiringInNext30Days(day int, month int, y
 	logger.Info("Fetching cards expiring between %s and %s", startDate, endDate)
 
 	// Query for cards with expiration dates in the next 30 days
-	cursor, err := collection.Find(context.TODO(), bson.M{
+	cursor, err := collection.Find(ctx, bson.M{
 		"expiration_date": bson.M{
 			"$gte": startDate,
 			"$lte": endDate,
@@ -186,10 +169,10 @@ func (r *CardRepositoryMongo) GetCardsExpiringInNext30Days(day int, month int, y
 	if err != nil {
 		return nil, fmt.Errorf("error querying cards expiring in the next 30 days: %v", err)
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var cards []models.Card
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var card entities.CardEntityNonSQL
 		if err := cursor.Decode(&card); err != nil {
 			return nil, err
@@ -202,50 +185,56 @@ func (r *CardRepositoryMongo) GetCardsExpiringInNext30Days(day int, month int, y
 	return &cards, nil
 }
 
-func (r *CardRepositoryMongo) GetPurchaseSingle(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+func (r *CardRepositoryMongo) GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
 	collection := r.db.Collection("purchase_single_payments")
 
-	logger.Info("Fetching single-payment purchase with CUIT: %s, final amount: %f, and payment voucher: %s", cuit, finalAmount, paymentVoucher)
+	logger.Info("Fetching single-payment purchase with CUIT: %s, final amount: %s, and payment voucher: %s", cuit, finalAmount, paymentVoucher)
 
 	// Query for a single-payment purchase by nested fields
 	var purchase entities.PurchaseSinglePaymentEntityNonSQL
 	if err := collection.FindOne(
-		context.TODO(),
+		ctx,
 		bson.M{
 			"purchase.cuit_store":      cuit,
 			"purchase.final_amount":    finalAmount,
 			"purchase.payment_voucher": paymentVoucher,
 		},
 	).Decode(&purchase); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find single-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+		}
 		return nil, fmt.Errorf("error finding single-payment purchase: %v", err)
 	}
 
 	return entities.ToPurchaseSinglePaymentNonSQL(&purchase), nil
 }
 
-func (r *CardRepositoryMongo) GetPurchaseMonthly(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+func (r *CardRepositoryMongo) GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
 	collection := r.db.Collection("purchase_monthly_payments")
 
-	logger.Info("Fetching monthly-payment purchase with CUIT: %s, final amount: %f, and payment voucher: %s",
+	logger.Info("Fetching monthly-payment purchase with CUIT: %s, final amount: %s, and payment voucher: %s",
 		cuit, finalAmount, paymentVoucher)
 
 	// Query for a single-payment purchase by nested fields
 	var purchase entities.PurchaseMonthlyPaymentsEntityNonSQL
 	if err := collection.FindOne(
-		context.TODO(),
+		ctx,
 		bson.M{
 			"purchase.cuit_store":      cuit,
 			"purchase.final_amount":    finalAmount,
 			"purchase.payment_voucher": paymentVoucher,
 		},
 	).Decode(&purchase); err != nil {
-		return nil, fmt.Errorf("error finding single-payment purchase: %v", err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find monthly-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error finding monthly-payment purchase: %v", err)
 	}
 
 	return entities.ToPurchaseMonthlyPaymentsNonSQL(&purchase), nil
 }
 
-func (r *CardRepositoryMongo) GetTop10CardsByPurchases() (*[]models.Card, error) {
+func (r *CardRepositoryMongo) GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error) {
 	collection := r.db.Collection("cards")
 
 	logger.Info("Fetching top 10 cards by total number of purchases")
@@ -300,10 +289,10 @@ func (r *CardRepositoryMongo) GetTop10CardsByPurchases() (*[]models.Card, error)
 				},
 			},
 		}},
-		// 4. Sort by purchase_count (descending)
+		// 4. Sort by purchase_count (descending), then by card number
 		bson.D{{
 			Key:   "$sort",
-			Value: bson.D{{Key: "purchase_count", Value: -1}},
+			Value: bson.D{{Key: "purchase_count", Value: -1}, {Key: "number", Value: 1}},
 		}},
 		// 5. Limit to top 10
 		bson.D{{
@@ -313,14 +302,14 @@ func (r *CardRepositoryMongo) GetTop10CardsByPurchases() (*[]models.Card, error)
 	}
 
 	// Aggregate the top 10 cards by total number of purchases
-	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("error aggregating top 10 cards: %w", err)
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var cards []models.Card
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var cardDoc entities.CardEntityNonSQL
 		if err := cursor.Decode(&cardDoc); err != nil {
 			return nil, fmt.Errorf("error decoding card doc: %w", err)
@@ -336,3 +325,132 @@ func (r *CardRepositoryMongo) GetTop10CardsByPurchases() (*[]models.Card, error)
 
 	return &cards, nil
 }
+
+func (r *CardRepositoryMongo) GetCardByNumber(ctx context.Context, cardNumber string) (*models.Card, error) {
+	var cardEntity entities.CardEntityNonSQL
+	if err := r.db.Collection("cards").FindOne(ctx, bson.M{"number": cardNumber}).Decode(&cardEntity); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, err)
+	}
+
+	card := entities.ToCard(&cardEntity)
+	card.Bank = models.Bank{Cuit: cardEntity.BankCuit}
+
+	// Cards reference their bank by CUIT, resolve the rest of the bank details when available
+	var bank entities.BankEntityNonSQL
+	if err := r.db.Collection("banks").FindOne(ctx, bson.M{"cuit": cardEntity.BankCuit}).Decode(&bank); err == nil {
+		card.Bank = *entities.ToBankNonSQL(&bank)
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("could not find bank with cuit %s: %w", cardEntity.BankCuit, err)
+	}
+
+	return card, nil
+}
+
+// IssueCard issues a new card to the customer with the card's customer CUIT at the card's bank. Card numbers are unique.
+// The card is referenced from the customer document as well.
+func (r *CardRepositoryMongo) IssueCard(ctx context.Context, card models.Card) (*models.Card, error) {
+	count, err := r.db.Collection("cards").CountDocuments(ctx, bson.M{"number": card.Number})
+	if err != nil {
+		return nil, fmt.Errorf("error checking existing cards: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("a card with number %s already exists: %w", card.Number, storage.ErrAlreadyExists)
+	}
+
+	if _, err := findBankByCuit(ctx, r.db, card.Bank.Cuit); err != nil {
+		return nil, err
+	}
+	var customer entities.CustomerEntityNonSQL
+	if err := r.db.Collection("customers").FindOne(ctx, bson.M{"cuit": card.CustomerCuit}).Decode(&customer); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find customer with cuit %s: %w", card.CustomerCuit, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find customer with cuit %s: %w", card.CustomerCuit, err)
+	}
+
+	result, err := r.db.Collection("cards").InsertOne(ctx, entities.ToCardEntityNonRelational(&card))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting card: %w", err)
+	}
+	update := bson.M{"$addToSet": bson.M{"cards": result.InsertedID}, "$set": bson.M{"updated_at": time.Now()}}
+	if _, err := r.db.Collection("customers").UpdateByID(ctx, customer.ID, update); err != nil {
+		return nil, fmt.Errorf("error linking card %s to customer %s: %w", card.Number, card.CustomerCuit, err)
+	}
+
+	logger.Info("Card %s issued to customer %s at bank %s", card.Number, card.CustomerCuit, card.Bank.Cuit)
+	return r.GetCardByNumber(ctx, card.Number)
+}
+
+// UpdateCardExpiration replaces the expiration date of a card.
+func (r *CardRepositoryMongo) UpdateCardExpiration(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	return r.updateCard(ctx, cardNumber, "expiration_date", expirationDate)
+}
+
+// UpdateCardStatus replaces the lifecycle status of a card.
+func (r *CardRepositoryMongo) UpdateCardStatus(ctx context.Context, cardNumber string, status models.CardStatus) (*models.Card, error) {
+	return r.updateCard(ctx, cardNumber, "status", string(status))
+}
+
+// updateCard sets a single field of the card with the given number and returns the updated card.
+func (r *CardRepositoryMongo) updateCard(ctx context.Context, cardNumber string, field string, value interface{}) (*models.Card, error) {
+	update := bson.M{"$set": bson.M{field: value, "updated_at": time.Now()}}
+	result, err := r.db.Collection("cards").UpdateOne(ctx, bson.M{"number": cardNumber}, update)
+	if err != nil {
+		return nil, fmt.Errorf("error updating %s of card %s: %w", field, cardNumber, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+	}
+
+	logger.Info("Card %s: %s set to %v", cardNumber, field, value)
+	return r.GetCardByNumber(ctx, cardNumber)
+}
+
+func (r *CardRepositoryMongo) AddPurchaseSinglePayment(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, err
+	}
+
+	paymentEntity := entities.ToPurchaseSinglePaymentEntityNonSQL(&purchase, cardNumber)
+	if _, err := r.db.Collection("purchase_single_payments").InsertOne(ctx, paymentEntity); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("card %s already has a single-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting single-payment purchase: %w", err)
+	}
+
+	logger.Info("Single-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return entities.ToPurchaseSinglePaymentNonSQL(paymentEntity), nil
+}
+
+func (r *CardRepositoryMongo) AddPurchaseMonthlyPayment(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	if err := r.ensureCardExists(ctx, cardNumber); err != nil {
+		return nil, err
+	}
+
+	// Quotas are embedded in the purchase document and reference it by its _id
+	paymentEntity := entities.ToPurchaseMonthlyPaymentsEntityNonSQL(&purchase, cardNumber)
+	paymentEntity.ID = bson.NewObjectID()
+	for i := range paymentEntity.Quotas {
+		paymentEntity.Quotas[i].ID = bson.NewObjectID()
+		paymentEntity.Quotas[i].PurchaseMonthlyPaymentsEntityID = paymentEntity.ID
+	}
+
+	if _, err := r.db.Collection("purchase_monthly_payments").InsertOne(ctx, paymentEntity); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("card %s already has a monthly-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting monthly-payment purchase: %w", err)
+	}
+
+	logger.Info("Monthly-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return entities.ToPurchaseMonthlyPaymentsNonSQL(paymentEntity), nil
+}
+
+// ensureCardExists returns a wrapped storage.ErrNotFound when no card has the given number.
+func (r *CardRepositoryMongo) ensureCardExists(ctx context.Context, cardNumber string) error {
+	return ensureCardExists(ctx, r.db, cardNumber)
+}
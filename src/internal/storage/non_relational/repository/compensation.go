@@ -0,0 +1,199 @@
+package nonrelational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CompensationRepositoryMongo struct {
+	db *mongo.Database
+}
+
+// NewCompensationNonRelationalRepository creates a new instance of CompensationRepositoryMongo
+func NewCompensationNonRelationalRepository(db *mongo.Database) storage.ICompensationStorage {
+	return &CompensationRepositoryMongo{db: db}
+}
+
+// RemoveBank removes a bank by its CUIT.
+func (r *CompensationRepositoryMongo) RemoveBank(ctx context.Context, cuit string) error {
+	if err := r.deleteOne(ctx, "banks", bson.M{"cuit": cuit}); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound))
+	}
+
+	logger.Info("Bank %s removed", cuit)
+	return nil
+}
+
+// RemoveCustomer removes a customer by its CUIT.
+func (r *CompensationRepositoryMongo) RemoveCustomer(ctx context.Context, cuit string) error {
+	if err := r.deleteOne(ctx, "customers", bson.M{"cuit": cuit}); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find customer with cuit %s: %w", cuit, storage.ErrNotFound))
+	}
+
+	logger.Info("Customer %s removed", cuit)
+	return nil
+}
+
+// RemoveCard removes a card by its number, together with its reference from the customer document.
+func (r *CompensationRepositoryMongo) RemoveCard(ctx context.Context, cardNumber string) error {
+	var card entities.CardEntityNonSQL
+	if err := r.db.Collection("cards").FindOneAndDelete(ctx, bson.M{"number": cardNumber}).Decode(&card); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+		}
+		return fmt.Errorf("error removing card %s: %w", cardNumber, err)
+	}
+
+	update := bson.M{"$pull": bson.M{"cards": card.ID}, "$set": bson.M{"updated_at": time.Now()}}
+	if _, err := r.db.Collection("customers").UpdateOne(ctx, bson.M{"cuit": card.CustomerCuit}, update); err != nil {
+		return fmt.Errorf("error unlinking card %s from customer %s: %w", cardNumber, card.CustomerCuit, err)
+	}
+
+	logger.Info("Card %s removed", cardNumber)
+	return nil
+}
+
+// RemovePromotion removes a discount or financing promotion by its code.
+func (r *CompensationRepositoryMongo) RemovePromotion(ctx context.Context, code string) error {
+	var removed int64
+	for _, collection := range []string{"discounts", "financings"} {
+		result, err := r.db.Collection(collection).DeleteOne(ctx, bson.M{"promotion_entity.code": code})
+		if err != nil {
+			return fmt.Errorf("error removing promotion %s: %w", code, err)
+		}
+		removed += result.DeletedCount
+	}
+	if removed == 0 {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+
+	logger.Info("Promotion %s removed", code)
+	return nil
+}
+
+// RemovePurchaseSinglePayment removes the latest single-payment purchase registered on a card with the given voucher.
+func (r *CompensationRepositoryMongo) RemovePurchaseSinglePayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	if err := r.deleteLatestPurchase(ctx, "purchase_single_payments", cardNumber, paymentVoucher); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find single-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound))
+	}
+
+	logger.Info("Single-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePurchaseMonthlyPayment removes the latest installment purchase registered on a card with the given voucher.
+// Its quotas are embedded in the purchase document.
+func (r *CompensationRepositoryMongo) RemovePurchaseMonthlyPayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	if err := r.deleteLatestPurchase(ctx, "purchase_monthly_payments", cardNumber, paymentVoucher); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find monthly-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound))
+	}
+
+	logger.Info("Monthly-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePaymentSummary removes the payment summary of a card for a month.
+func (r *CompensationRepositoryMongo) RemovePaymentSummary(ctx context.Context, cardNumber string, month int, year int) error {
+	if err := r.deleteOne(ctx, "payment_summaries", bson.M{"card_number": cardNumber, "month": month, "year": year}); err != nil {
+		return notFoundOr(err, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound))
+	}
+
+	logger.Info("Payment summary of card %s for %02d/%d removed", cardNumber, month, year)
+	return nil
+}
+
+// RemoveBillingCycle removes the billing cycle configuration embedded in the bank document.
+func (r *CompensationRepositoryMongo) RemoveBillingCycle(ctx context.Context, bankCuit string) error {
+	bank, err := findBankByCuit(ctx, r.db, bankCuit)
+	if err != nil {
+		return err
+	}
+	if bank.BillingCycle == nil {
+		return fmt.Errorf("bank %s has no billing cycle: %w", bankCuit, storage.ErrNotFound)
+	}
+
+	update := bson.M{"$unset": bson.M{"billing_cycle": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	if _, err := r.db.Collection("banks").UpdateByID(ctx, bank.ID, update); err != nil {
+		return fmt.Errorf("error removing billing cycle of bank %s: %w", bankCuit, err)
+	}
+
+	logger.Info("Billing cycle of bank %s removed", bankCuit)
+	return nil
+}
+
+// RemoveExchangeRate removes the rate of a currency for a day.
+func (r *CompensationRepositoryMongo) RemoveExchangeRate(ctx context.Context, currency models.Currency, date time.Time) error {
+	day := models.RateDay(date)
+	if err := r.deleteOne(ctx, "exchange_rates", bson.M{"currency": string(currency), "date": day}); err != nil {
+		return notFoundOr(err, fmt.Errorf("no %s exchange rate on %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound))
+	}
+
+	logger.Info("%s exchange rate of %s removed", currency, day.Format(time.DateOnly))
+	return nil
+}
+
+// RemovePayment removes a payment by its code and takes its allocations off the paid amounts of the billed items.
+func (r *CompensationRepositoryMongo) RemovePayment(ctx context.Context, code string) error {
+	var payment entities.PaymentEntityNonSQL
+	if err := r.db.Collection("payments").FindOne(ctx, bson.M{"code": code}).Decode(&payment); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find payment with code %s: %w", code, storage.ErrNotFound))
+	}
+
+	allocation, err := newPaymentAllocation(ctx, r.db, *entities.ToPaymentNonSQL(&payment))
+	if err != nil {
+		return err
+	}
+	if err := allocation.apply(ctx, r.db, -1); err != nil {
+		return err
+	}
+	if err := r.deleteOne(ctx, "payments", bson.M{"code": code}); err != nil {
+		return notFoundOr(err, fmt.Errorf("could not find payment with code %s: %w", code, storage.ErrNotFound))
+	}
+
+	logger.Info("Payment %s removed", code)
+	return nil
+}
+
+// deleteOne deletes the first document matching the filter, returning mongo.ErrNoDocuments when none matches.
+func (r *CompensationRepositoryMongo) deleteOne(ctx context.Context, collection string, filter bson.M) error {
+	result, err := r.db.Collection(collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error removing from %s: %w", collection, err)
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// deleteLatestPurchase deletes the most recently inserted purchase of a card with the given voucher, returning
+// mongo.ErrNoDocuments when none matches.
+func (r *CompensationRepositoryMongo) deleteLatestPurchase(ctx context.Context, collection string, cardNumber string, paymentVoucher string) error {
+	filter := bson.M{"purchase.card_number": cardNumber, "purchase.payment_voucher": paymentVoucher}
+	opts := options.FindOneAndDelete().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if err := r.db.Collection(collection).FindOneAndDelete(ctx, filter, opts).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		return fmt.Errorf("error removing from %s: %w", collection, err)
+	}
+	return nil
+}
+
+// notFoundOr returns notFound when err reports that no document matched, and err otherwise.
+func notFoundOr(err error, notFound error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notFound
+	}
+	return err
+}
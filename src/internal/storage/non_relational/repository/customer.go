@@ -0,0 +1,220 @@
+package nonrelational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CustomerRepositoryMongo struct {
+	db *mongo.Database
+}
+
+// NewCustomerNonRelationalRepository creates a new instance of CustomerRepositoryMongo
+func NewCustomerNonRelationalRepository(db *mongo.Database) storage.ICustomerStorage {
+	return &CustomerRepositoryMongo{db: db}
+}
+
+// CreateCustomer stores a new customer. CUIT and DNI are unique among customers.
+func (r *CustomerRepositoryMongo) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	count, err := r.db.Collection("customers").CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"cuit": customer.Cuit},
+		bson.M{"dni": customer.Dni},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("error checking existing customers: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("a customer with cuit %s or dni %s already exists: %w", customer.Cuit, customer.Dni, storage.ErrAlreadyExists)
+	}
+
+	if _, err := r.db.Collection("customers").InsertOne(ctx, entities.ToCustomerEntityNonRelational(&customer)); err != nil {
+		return nil, fmt.Errorf("error inserting customer: %w", err)
+	}
+
+	logger.Info("Customer %s registered", customer.Cuit)
+	return r.GetCustomerByCuit(ctx, customer.Cuit)
+}
+
+// GetCustomerByCuit retrieves a customer, including the CUITs of its banks, by its CUIT.
+func (r *CustomerRepositoryMongo) GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error) {
+	customer, err := r.findCustomerByCuit(ctx, cuit)
+	if err != nil {
+		return nil, err
+	}
+	return r.toCustomer(ctx, customer)
+}
+
+// UpdateCustomer replaces the name, DNI, address, telephone and entry date of a customer.
+func (r *CustomerRepositoryMongo) UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	existing, err := r.findCustomerByCuit(ctx, cuit)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := r.db.Collection("customers").CountDocuments(ctx, bson.M{"dni": customer.Dni, "_id": bson.M{"$ne": existing.ID}})
+	if err != nil {
+		return nil, fmt.Errorf("error checking existing customers: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("a customer with dni %s already exists: %w", customer.Dni, storage.ErrAlreadyExists)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"complete_name": customer.CompleteName,
+		"dni":           customer.Dni,
+		"address":       customer.Address,
+		"telephone":     customer.Telephone,
+		"entry_date":    customer.EntryDate,
+		"updated_at":    time.Now(),
+	}}
+	if _, err := r.db.Collection("customers").UpdateByID(ctx, existing.ID, update); err != nil {
+		return nil, fmt.Errorf("error updating customer %s: %w", cuit, err)
+	}
+
+	logger.Info("Customer %s updated", cuit)
+	return r.GetCustomerByCuit(ctx, cuit)
+}
+
+// GetCustomers retrieves all customers ordered by CUIT.
+func (r *CustomerRepositoryMongo) GetCustomers(ctx context.Context) (*[]models.Customer, error) {
+	cursor, err := r.db.Collection("customers").Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "cuit", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving customers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var customerEntities []entities.CustomerEntityNonSQL
+	if err := cursor.All(ctx, &customerEntities); err != nil {
+		return nil, fmt.Errorf("error decoding customers: %w", err)
+	}
+
+	customers := []models.Customer{}
+	for _, entity := range customerEntities {
+		customer, err := r.toCustomer(ctx, &entity)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, *customer)
+	}
+	return &customers, nil
+}
+
+// AddCustomerToBank makes a customer a member of a bank.
+// The membership is recorded on both documents: the customer's banks and the bank's customers.
+func (r *CustomerRepositoryMongo) AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	customer, bank, err := r.findMembership(ctx, customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+	if containsID(customer.Banks, bank.ID) {
+		return fmt.Errorf("customer %s is already a member of bank %s: %w", customerCuit, bankCuit, storage.ErrAlreadyExists)
+	}
+
+	if err := r.updateMembership(ctx, customer.ID, bank.ID, "$addToSet"); err != nil {
+		return fmt.Errorf("error adding customer %s to bank %s: %w", customerCuit, bankCuit, err)
+	}
+
+	logger.Info("Customer %s joined bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// RemoveCustomerFromBank ends the membership of a customer in a bank.
+func (r *CustomerRepositoryMongo) RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	customer, bank, err := r.findMembership(ctx, customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+	if !containsID(customer.Banks, bank.ID) {
+		return fmt.Errorf("customer %s is not a member of bank %s: %w", customerCuit, bankCuit, storage.ErrNotFound)
+	}
+
+	if err := r.updateMembership(ctx, customer.ID, bank.ID, "$pull"); err != nil {
+		return fmt.Errorf("error removing customer %s from bank %s: %w", customerCuit, bankCuit, err)
+	}
+
+	logger.Info("Customer %s left bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// updateMembership applies the array operator ($addToSet or $pull) to both sides of a membership.
+func (r *CustomerRepositoryMongo) updateMembership(ctx context.Context, customerID bson.ObjectID, bankID bson.ObjectID, operator string) error {
+	now := bson.M{"updated_at": time.Now()}
+
+	if _, err := r.db.Collection("customers").UpdateByID(ctx, customerID, bson.M{operator: bson.M{"banks": bankID}, "$set": now}); err != nil {
+		return err
+	}
+	if _, err := r.db.Collection("banks").UpdateByID(ctx, bankID, bson.M{operator: bson.M{"customers": customerID}, "$set": now}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findMembership retrieves the customer and bank documents of a membership.
+func (r *CustomerRepositoryMongo) findMembership(ctx context.Context, customerCuit string, bankCuit string) (*entities.CustomerEntityNonSQL, *entities.BankEntityNonSQL, error) {
+	customer, err := r.findCustomerByCuit(ctx, customerCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+	bank, err := findBankByCuit(ctx, r.db, bankCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return customer, bank, nil
+}
+
+// findCustomerByCuit retrieves the customer document with the given CUIT or a wrapped storage.ErrNotFound.
+func (r *CustomerRepositoryMongo) findCustomerByCuit(ctx context.Context, cuit string) (*entities.CustomerEntityNonSQL, error) {
+	var customer entities.CustomerEntityNonSQL
+	if err := r.db.Collection("customers").FindOne(ctx, bson.M{"cuit": cuit}).Decode(&customer); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("could not find customer with cuit %s: %w", cuit, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find customer with cuit %s: %w", cuit, err)
+	}
+	return &customer, nil
+}
+
+// toCustomer maps a customer document to the model, resolving the CUITs of the referenced banks.
+func (r *CustomerRepositoryMongo) toCustomer(ctx context.Context, entity *entities.CustomerEntityNonSQL) (*models.Customer, error) {
+	customer := entities.ToCustomer(entity)
+	customer.BankCuits = []string{}
+	if len(entity.Banks) == 0 {
+		return customer, nil
+	}
+
+	cursor, err := r.db.Collection("banks").Find(ctx, bson.M{"_id": bson.M{"$in": entity.Banks}})
+	if err != nil {
+		return nil, fmt.Errorf("error resolving banks of customer %s: %w", entity.Cuit, err)
+	}
+	defer cursor.Close(ctx)
+
+	var banks []entities.BankEntityNonSQL
+	if err := cursor.All(ctx, &banks); err != nil {
+		return nil, fmt.Errorf("error decoding banks of customer %s: %w", entity.Cuit, err)
+	}
+	for _, bank := range banks {
+		customer.BankCuits = append(customer.BankCuits, bank.Cuit)
+	}
+	sort.Strings(customer.BankCuits)
+	return customer, nil
+}
+
+func containsID(ids []bson.ObjectID, id bson.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
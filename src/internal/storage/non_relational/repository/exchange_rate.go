@@ -0,0 +1,88 @@
+/*
+ * Payment Registration System - Non-Relational Repository
+ * --------------------------------------------------------
+ * This file contains the implementation of the ExchangeRateRepositoryMongo struct.
+ * The struct implements the IExchangeRateStorage interface and keeps one document per currency and day.
+ *
+ * Created: Apr. 05, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package nonrelational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ExchangeRateRepositoryMongo struct {
+	db *mongo.Database
+}
+
+// NewExchangeRateNonRelationalRepository creates a new instance of ExchangeRateRepositoryMongo
+func NewExchangeRateNonRelationalRepository(db *mongo.Database) storage.IExchangeRateStorage {
+	return &ExchangeRateRepositoryMongo{db: db}
+}
+
+// SaveExchangeRates stores exchange rates, replacing the rate of a currency already stored for the same day.
+func (r *ExchangeRateRepositoryMongo) SaveExchangeRates(ctx context.Context, rates []models.ExchangeRate) error {
+	for _, rate := range rates {
+		entity := entities.ToExchangeRateEntityNonSQL(&rate)
+		filter := bson.M{"currency": entity.Currency, "date": entity.Date}
+		update := bson.M{"$set": bson.M{"rate": entity.Rate, "updated_at": entity.UpdatedAt}}
+		if _, err := r.db.Collection("exchange_rates").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+			return fmt.Errorf("error saving %s exchange rate of %s: %w", rate.Currency, entity.Date.Format(time.DateOnly), err)
+		}
+	}
+
+	logger.Info("%d exchange rates saved", len(rates))
+	return nil
+}
+
+// GetExchangeRates retrieves the rates of a currency from one day to another, both included, ordered by day.
+func (r *ExchangeRateRepositoryMongo) GetExchangeRates(ctx context.Context, currency models.Currency, from time.Time, to time.Time) (*[]models.ExchangeRate, error) {
+	filter := bson.M{"currency": string(currency), "date": bson.M{"$gte": models.RateDay(from), "$lte": models.RateDay(to)}}
+	cursor, err := r.db.Collection("exchange_rates").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving %s exchange rates: %w", currency, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rateEntities []entities.ExchangeRateEntityNonSQL
+	if err := cursor.All(ctx, &rateEntities); err != nil {
+		return nil, fmt.Errorf("error decoding %s exchange rates: %w", currency, err)
+	}
+
+	rates := []models.ExchangeRate{}
+	for _, entity := range rateEntities {
+		rates = append(rates, *entities.ToExchangeRateNonSQL(&entity))
+	}
+	return &rates, nil
+}
+
+// GetExchangeRate retrieves the latest rate of a currency on or before a day.
+func (r *ExchangeRateRepositoryMongo) GetExchangeRate(ctx context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error) {
+	day := models.RateDay(date)
+	filter := bson.M{"currency": string(currency), "date": bson.M{"$lte": day}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}})
+
+	var entity entities.ExchangeRateEntityNonSQL
+	if err := r.db.Collection("exchange_rates").FindOne(ctx, filter, opts).Decode(&entity); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("no %s exchange rate on or before %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error fetching %s exchange rate for %s: %w", currency, day.Format(time.DateOnly), err)
+	}
+
+	return entities.ToExchangeRateNonSQL(&entity), nil
+}
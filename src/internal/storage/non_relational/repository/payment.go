@@ -0,0 +1,279 @@
+package nonrelational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type PaymentRepositoryMongo struct {
+	db *mongo.Database
+}
+
+// NewPaymentNonRelationalRepository creates a new instance of PaymentRepositoryMongo
+func NewPaymentNonRelationalRepository(db *mongo.Database) storage.IPaymentStorage {
+	return &PaymentRepositoryMongo{db: db}
+}
+
+// RecordPayment stores a payment of a payment summary and adds its allocations to the paid amounts of the billed
+// quotas and single-payment purchases, both in the summary snapshot and in the purchase documents.
+// The code is unique among payments, enforced by the unique index on payments.code. The summary is only updated if
+// every item stays within its billed amount; otherwise the payment is removed again.
+func (r *PaymentRepositoryMongo) RecordPayment(ctx context.Context, payment models.Payment) (*models.Payment, error) {
+	allocation, err := newPaymentAllocation(ctx, r.db, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := entities.ToPaymentEntityNonSQL(&payment)
+	if _, err := r.db.Collection("payments").InsertOne(ctx, entity); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("payment %s already exists: %w", payment.Code, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting payment: %w", err)
+	}
+	if err := allocation.apply(ctx, r.db, 1); err != nil {
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			if _, removeErr := r.db.Collection("payments").DeleteOne(ctx, bson.M{"code": payment.Code}); removeErr != nil {
+				logger.Error("Failed to remove payment %s left without allocations: %v", payment.Code, removeErr)
+			}
+		}
+		return nil, err
+	}
+
+	logger.Info("Payment %s of %s recorded for card %s in %02d/%d", payment.Code, payment.Amount, payment.CardNumber, payment.Month, payment.Year)
+	return entities.ToPaymentNonSQL(entity), nil
+}
+
+// GetPayments retrieves the payments of the summary of a card for a month, ordered by payment date.
+func (r *PaymentRepositoryMongo) GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	if _, err := findPaymentSummary(ctx, r.db, cardNumber, month, year); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"card_number": cardNumber, "month": month, "year": year}
+	opts := options.Find().SetSort(bson.D{{Key: "payment_date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.db.Collection("payments").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding payments of card %s for %02d/%d: %w", cardNumber, month, year, err)
+	}
+	defer cursor.Close(ctx)
+
+	var paymentEntities []entities.PaymentEntityNonSQL
+	if err := cursor.All(ctx, &paymentEntities); err != nil {
+		return nil, fmt.Errorf("error decoding payments: %w", err)
+	}
+
+	payments := []models.Payment{}
+	for _, entity := range paymentEntities {
+		payments = append(payments, *entities.ToPaymentNonSQL(&entity))
+	}
+	return &payments, nil
+}
+
+// GetPaymentSummaries retrieves the stored payment summaries of a card, ordered by year and month.
+func (r *PaymentRepositoryMongo) GetPaymentSummaries(ctx context.Context, cardNumber string) (*[]models.PaymentSummary, error) {
+	if err := ensureCardExists(ctx, r.db, cardNumber); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "year", Value: 1}, {Key: "month", Value: 1}})
+	cursor, err := r.db.Collection("payment_summaries").Find(ctx, bson.M{"card_number": cardNumber}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding payment summaries of card %s: %w", cardNumber, err)
+	}
+	defer cursor.Close(ctx)
+
+	var summaryEntities []entities.PaymentSummaryEntityNonSQL
+	if err := cursor.All(ctx, &summaryEntities); err != nil {
+		return nil, fmt.Errorf("error decoding payment summaries: %w", err)
+	}
+
+	summaries := []models.PaymentSummary{}
+	for _, entity := range summaryEntities {
+		summaries = append(summaries, *entities.ToPaymentSummary(&entity))
+	}
+	return &summaries, nil
+}
+
+// paymentAllocation holds the updates adding the allocations of a payment to the paid amounts of the billed items.
+// Amounts are kept apart from the paths so that the same allocation can be applied and reverted.
+type paymentAllocation struct {
+	summaryID bson.ObjectID
+	paths     []string         // Paths of the paid amounts in the summary document
+	amounts   []models.Money   // Amount added to each path
+	bounds    bson.M           // Conditions keeping the paid amount of each billed item within its billed amount
+	purchases []purchaseUpdate // Updates of the purchase documents
+}
+
+// purchaseUpdate adds an amount to the paid amount at a path of the purchase document matching a filter.
+type purchaseUpdate struct {
+	collection string
+	filter     bson.M
+	path       string
+	amount     models.Money
+}
+
+// newPaymentAllocation locates the items the allocations of a payment name in the snapshot of its payment summary.
+// Returns a wrapped storage.ErrNotFound if the card, the summary or one of the items does not exist.
+func newPaymentAllocation(ctx context.Context, db *mongo.Database, payment models.Payment) (*paymentAllocation, error) {
+	summary, err := findPaymentSummary(ctx, db, payment.CardNumber, payment.Month, payment.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &paymentAllocation{summaryID: summary.ID, bounds: bson.M{}}
+	for _, allocation := range payment.Allocations {
+		paths, update, found := locateBilledItem(summary, allocation)
+		if !found {
+			return nil, fmt.Errorf("summary of card %s in %02d/%d bills no %s: %w", payment.CardNumber, payment.Month, payment.Year, describeAllocation(allocation), storage.ErrNotFound)
+		}
+		// The first path is the billed item itself, the others are copies of it. A missing paid amount is zero.
+		result.bounds[paths[0]] = bson.M{"$not": bson.M{"$gt": allocation.BilledAmount - allocation.Amount}}
+		for _, path := range paths {
+			result.paths = append(result.paths, path)
+			result.amounts = append(result.amounts, allocation.Amount)
+		}
+		update.filter["purchase.card_number"] = payment.CardNumber
+		update.amount = allocation.Amount
+		result.purchases = append(result.purchases, update)
+	}
+	return result, nil
+}
+
+// locateBilledItem returns the paths of the paid amount of the item an allocation names in a summary snapshot, and
+// the update of the purchase document holding it. A quota is both a due quota and part of its billed purchase.
+func locateBilledItem(summary *entities.PaymentSummaryEntityNonSQL, allocation models.PaymentAllocation) ([]string, purchaseUpdate, bool) {
+	var paths []string
+	if allocation.PurchaseDate == nil {
+		for i, quota := range summary.Quotas {
+			if quota.PaymentVoucher == allocation.PaymentVoucher && quota.Quota.Number == allocation.QuotaNumber {
+				paths = append(paths, fmt.Sprintf("quotas.%d.quota.paid_amount", i))
+			}
+		}
+		if len(paths) == 0 {
+			return nil, purchaseUpdate{}, false
+		}
+		months := bson.A{fmt.Sprintf("%02d", summary.Month), strconv.Itoa(summary.Month)}
+		for i, purchase := range summary.MonthlyPayments {
+			if purchase.PurchaseEntity.PaymentVoucher != allocation.PaymentVoucher {
+				continue
+			}
+			for j, quota := range purchase.Quotas {
+				if quota.Number == allocation.QuotaNumber {
+					paths = append(paths, fmt.Sprintf("monthly_payments.%d.quotas.%d.paid_amount", i, j))
+				}
+			}
+		}
+		return paths, purchaseUpdate{
+			collection: "purchase_monthly_payments",
+			filter: bson.M{
+				"purchase.payment_voucher": allocation.PaymentVoucher,
+				"quotas": bson.M{"$elemMatch": bson.M{
+					"number": allocation.QuotaNumber,
+					"month":  bson.M{"$in": months},
+					"year":   strconv.Itoa(summary.Year),
+				}},
+			},
+			path: "quotas.$.paid_amount",
+		}, true
+	}
+
+	for i, purchase := range summary.SinglePayments {
+		if purchase.PurchaseEntity.PaymentVoucher == allocation.PaymentVoucher && purchase.PurchaseEntity.CreatedAt.Equal(*allocation.PurchaseDate) {
+			return []string{fmt.Sprintf("single_payments.%d.paid_amount", i)}, purchaseUpdate{
+				collection: "purchase_single_payments",
+				filter: bson.M{
+					"purchase.payment_voucher": allocation.PaymentVoucher,
+					"purchase.created_at":      *allocation.PurchaseDate,
+				},
+				path: "paid_amount",
+			}, true
+		}
+	}
+	return nil, purchaseUpdate{}, false
+}
+
+// apply adds the allocated amounts, times sign, to the paid amounts in the summary and purchase documents.
+// Added amounts are checked against the billed amounts by the update of the summary document, which is atomic, and
+// nothing is updated if an item is left with too little to pay: a wrapped storage.ErrAlreadyExists is returned.
+func (a *paymentAllocation) apply(ctx context.Context, db *mongo.Database, sign models.Money) error {
+	now := time.Now()
+	increments := bson.M{}
+	for i, path := range a.paths {
+		increments[path] = sign * a.amounts[i]
+	}
+	if len(increments) > 0 {
+		filter := bson.M{"_id": a.summaryID}
+		if sign > 0 {
+			for path, bound := range a.bounds {
+				filter[path] = bound
+			}
+		}
+		update := bson.M{"$inc": increments, "$set": bson.M{"updated_at": now}}
+		result, err := db.Collection("payment_summaries").UpdateOne(ctx, filter, update)
+		if err != nil {
+			return fmt.Errorf("error updating paid amounts of payment summary: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("the allocations exceed what is left to pay of the billed items: %w", storage.ErrAlreadyExists)
+		}
+	}
+
+	for _, purchase := range a.purchases {
+		update := bson.M{"$inc": bson.M{purchase.path: sign * purchase.amount}, "$set": bson.M{"purchase.updated_at": now}}
+		if _, err := db.Collection(purchase.collection).UpdateOne(ctx, purchase.filter, update); err != nil {
+			return fmt.Errorf("error updating paid amounts of %s: %w", purchase.collection, err)
+		}
+	}
+	return nil
+}
+
+// findPaymentSummary retrieves the payment summary document of a card for a month or a wrapped storage.ErrNotFound.
+func findPaymentSummary(ctx context.Context, db *mongo.Database, cardNumber string, month int, year int) (*entities.PaymentSummaryEntityNonSQL, error) {
+	if err := ensureCardExists(ctx, db, cardNumber); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"card_number": cardNumber, "month": month, "year": year}
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	var summary entities.PaymentSummaryEntityNonSQL
+	if err := db.Collection("payment_summaries").FindOne(ctx, filter, opts).Decode(&summary); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error fetching payment summary from MongoDB: %w", err)
+	}
+	return &summary, nil
+}
+
+// ensureCardExists returns a wrapped storage.ErrNotFound if no card has the given number.
+func ensureCardExists(ctx context.Context, db *mongo.Database, cardNumber string) error {
+	count, err := db.Collection("cards").CountDocuments(ctx, bson.M{"number": cardNumber})
+	if err != nil {
+		return fmt.Errorf("error looking up card %s: %w", cardNumber, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+	}
+	return nil
+}
+
+// describeAllocation names the item an allocation is applied to, for error messages.
+func describeAllocation(allocation models.PaymentAllocation) string {
+	if allocation.PurchaseDate == nil {
+		return fmt.Sprintf("quota %d of purchase %s", allocation.QuotaNumber, allocation.PaymentVoucher)
+	}
+	return fmt.Sprintf("purchase %s of %s", allocation.PaymentVoucher, allocation.PurchaseDate.Format("2006-01-02"))
+}
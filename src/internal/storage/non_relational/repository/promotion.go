@@ -12,6 +12,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type PromotionRepositoryMongo struct {
@@ -24,7 +25,7 @@ func NewPromotionNonRelationalRepository(db *mongo.Database) storage.IPromotionS
 }
 
 // GetAvailablePromotionsByStoreAndDateRange retrieves available promotions for a store within a date range.
-func (r *PromotionRepositoryMongo) GetAvailablePromotionsByStoreAndDateRange(cuit string, startDate, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+func (r *PromotionRepositoryMongo) GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
 	discountsCollection := r.db.Collection("discounts")
 	financingsCollection := r.db.Collection("financings")
 
@@ -33,40 +34,47 @@ func (r *PromotionRepositoryMongo) GetAvailablePromotionsByStoreAndDateRange(cui
 	var promotionsDiscount []models.Discount
 	var promotionsFinancing []models.Financing
 
-	// Build the query
+	// Promotions valid within the range or covering it, as in the relational repository
 	filter := bson.M{
 		"promotion_entity.cuit_store": cuit,
-		"$and": []bson.M{
-			{"promotion_entity.validity_start_date": bson.M{"$gte": startDate, "$lte": endDate}},
-			{"promotion_entity.validity_end_date": bson.M{"$gte": startDate, "$lte": endDate}},
+		"is_deleted":                  bson.M{"$ne": true},
+		"$or": []bson.M{
+			{
+				"promotion_entity.validity_start_date": bson.M{"$gte": startDate},
+				"promotion_entity.validity_end_date":   bson.M{"$lte": endDate},
+			},
+			{
+				"promotion_entity.validity_start_date": bson.M{"$lte": startDate},
+				"promotion_entity.validity_end_date":   bson.M{"$gte": endDate},
+			},
 		},
 	}
 
 	// Query discounts
-	cursor, err := discountsCollection.Find(context.TODO(), filter)
+	cursor, err := discountsCollection.Find(ctx, filter)
 	if err != nil {
 		logger.Error("Error finding DiscountEntity with CUIT %s between %v and %v: %v", cuit, startDate, endDate, err)
 		return nil, nil, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var discounts []entities.DiscountEntityNonSQL
-	if err := cursor.All(context.TODO(), &discounts); err != nil {
+	if err := cursor.All(ctx, &discounts); err != nil {
 		return nil, nil, err
 	}
 
 	logger.Info("Found %d discounts for store with CUIT %s between %v and %v", len(discounts), cuit, startDate, endDate)
 
 	// Query financings
-	cursor, err = financingsCollection.Find(context.TODO(), filter)
+	cursor, err = financingsCollection.Find(ctx, filter)
 	if err != nil {
 		logger.Info("Error finding FinancingEntity with CUIT %s between %v and %v: %v", cuit, startDate, endDate, err)
 		return nil, nil, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var financings []entities.FinancingEntityNonSQL
-	if err := cursor.All(context.TODO(), &financings); err != nil {
+	if err := cursor.All(ctx, &financings); err != nil {
 		return nil, nil, err
 	}
 
@@ -83,112 +91,310 @@ func (r *PromotionRepositoryMongo) GetAvailablePromotionsByStoreAndDateRange(cui
 	return &promotionsFinancing, &promotionsDiscount, nil
 }
 
-// GetMostUsedPromotion retrieves the most used promotion based on its usage.
-func (r *PromotionRepositoryMongo) GetMostUsedPromotion() (interface{}, error) {
+// GetMostUsedPromotion retrieves the promotion whose code is used most often as a payment voucher, counting
+// single and monthly purchases together. Ties are broken by the lowest code.
+func (r *PromotionRepositoryMongo) GetMostUsedPromotion(ctx context.Context) (interface{}, error) {
 
 	logger.Info("Finding most used promotion on Non-Relational database")
 
-	// Collection references
-	monthlyPurchasesCollection := r.db.Collection("purchase_monthly_payments")
-	singlePurchasesCollection := r.db.Collection("purchase_single_payments")
-
-	// Aggregation pipeline to count occurrences of payment_voucher in single payments
 	pipeline := mongo.Pipeline{
-		{
-			{Key: "$group", Value: bson.D{
-				{Key: "_id", Value: "$purchase.payment_voucher"},
-				{Key: "total_repeticiones", Value: bson.D{{Key: "$sum", Value: 1}}},
-			}},
-		},
-		{
-			{Key: "$sort", Value: bson.D{
-				{Key: "total_repeticiones", Value: -1},
-			}},
-		},
-		{
-			{Key: "$limit", Value: 1},
-		},
-	}
-
-	// Aggregate for single purchases
-	cursorSingle, err := singlePurchasesCollection.Aggregate(context.TODO(), pipeline)
-	if err != nil {
-		logger.Info("Error aggregating single purchases for most used promotion: %v", err)
-		return nil, err
+		{{Key: "$project", Value: bson.M{"payment_voucher": "$purchase.payment_voucher"}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll":     "purchase_monthly_payments",
+			"pipeline": mongo.Pipeline{{{Key: "$project", Value: bson.M{"payment_voucher": "$purchase.payment_voucher"}}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$payment_voucher"},
+			{Key: "total_repeticiones", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "total_repeticiones", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: 1}},
 	}
-	defer cursorSingle.Close(context.TODO())
 
-	// Aggregate for monthly purchases
-	cursorMonthly, err := monthlyPurchasesCollection.Aggregate(context.TODO(), pipeline)
+	cursor, err := r.db.Collection("purchase_single_payments").Aggregate(ctx, pipeline)
 	if err != nil {
-		logger.Info("Error aggregating monthly purchases for most used promotion: %v", err)
+		logger.Info("Error aggregating purchases for most used promotion: %v", err)
 		return nil, err
 	}
-	defer cursorMonthly.Close(context.TODO())
+	defer cursor.Close(ctx)
 
-	// Decode results
-	var singleResult, monthlyResult struct {
+	var result struct {
 		PaymentVoucher    string `bson:"_id"`
 		TotalRepeticiones int    `bson:"total_repeticiones"`
 	}
-
-	hasSingle := cursorSingle.Next(context.TODO())
-	if hasSingle {
-		if err := cursorSingle.Decode(&singleResult); err != nil {
-			return nil, fmt.Errorf("error decoding single purchases: %w", err)
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return nil, fmt.Errorf("error reading most used promotion: %w", err)
 		}
+		return nil, fmt.Errorf("no purchases to find the most used promotion: %w", storage.ErrNotFound)
+	}
+	if err := cursor.Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding most used promotion: %w", err)
 	}
+	logger.Info("Most used promotion found: %s, used %d times", result.PaymentVoucher, result.TotalRepeticiones)
 
-	hasMonthly := cursorMonthly.Next(context.TODO())
-	if hasMonthly {
-		if err := cursorMonthly.Decode(&monthlyResult); err != nil {
-			return nil, fmt.Errorf("error decoding monthly purchases: %w", err)
+	// Fetch the promotion details using the most used promotion code
+	return r.findPromotionByCode(ctx, result.PaymentVoucher)
+}
+
+// GetApplicablePromotions retrieves the non-deleted promotions of a bank for a store that are valid on the given date.
+func (r *PromotionRepositoryMongo) GetApplicablePromotions(ctx context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	promotionsDiscount := []models.Discount{}
+	promotionsFinancing := []models.Financing{}
+
+	var bank entities.BankEntityNonSQL
+	if err := r.db.Collection("banks").FindOne(ctx, bson.M{"cuit": bankCuit}).Decode(&bank); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Info("No bank with CUIT %s, no promotions apply", bankCuit)
+			return &promotionsFinancing, &promotionsDiscount, nil
 		}
+		return nil, nil, fmt.Errorf("could not find bank with cuit %s: %w", bankCuit, err)
 	}
 
-	// Determine the most used promotion between single and monthly
-	var mostUsedPromotion string
-	if hasSingle && hasMonthly {
-		if singleResult.TotalRepeticiones >= monthlyResult.TotalRepeticiones {
-			logger.Info("Most used promotion found for single payments vs monthly payments: %s", singleResult.PaymentVoucher)
-			mostUsedPromotion = singleResult.PaymentVoucher
-		} else {
-			logger.Info("Most used promotion found for monthly payments vs single payments: %s", monthlyResult.PaymentVoucher)
-			mostUsedPromotion = monthlyResult.PaymentVoucher
-		}
-	} else if hasSingle {
-		logger.Info("Most used promotion found for single payments: %s", singleResult.PaymentVoucher)
-		mostUsedPromotion = singleResult.PaymentVoucher
-	} else if hasMonthly {
-		logger.Info("Most used promotion found for monthly payments: %s", monthlyResult.PaymentVoucher)
-		mostUsedPromotion = monthlyResult.PaymentVoucher
-	} else {
-		return nil, errors.New("no promotions found")
+	// Documents written before the soft-delete flag existed have no is_deleted field
+	filter := bson.M{
+		"bank_id":                              bank.ID,
+		"promotion_entity.cuit_store":          storeCuit,
+		"is_deleted":                           bson.M{"$ne": true},
+		"promotion_entity.validity_start_date": bson.M{"$lte": date},
+		"promotion_entity.validity_end_date":   bson.M{"$gte": date},
 	}
 
-	// Fetch the promotion details using the most used promotion code
-	return r.findPromotionByCode(mostUsedPromotion)
-}
+	cursor, err := r.db.Collection("discounts").Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding applicable discounts for bank %s and store %s on %v: %v", bankCuit, storeCuit, date, err)
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
 
-func (r *PromotionRepositoryMongo) findPromotionByCode(code string) (interface{}, error) {
-	logger.Info("Finding promotion with code %s", code)
+	var discounts []entities.DiscountEntityNonSQL
+	if err := cursor.All(ctx, &discounts); err != nil {
+		return nil, nil, err
+	}
 
-	discountsCollection := r.db.Collection("discounts")
-	financingsCollection := r.db.Collection("financings")
+	cursor, err = r.db.Collection("financings").Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding applicable financings for bank %s and store %s on %v: %v", bankCuit, storeCuit, date, err)
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var financings []entities.FinancingEntityNonSQL
+	if err := cursor.All(ctx, &financings); err != nil {
+		return nil, nil, err
+	}
+
+	for _, discount := range discounts {
+		promotionsDiscount = append(promotionsDiscount, *entities.ToDiscountNonSQL(&discount))
+	}
+	for _, financing := range financings {
+		promotionsFinancing = append(promotionsFinancing, *entities.ToFinancingNonSQL(&financing))
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+func (r *PromotionRepositoryMongo) GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error) {
+	filter := bson.M{"promotion_entity.code": code}
 
-	// Try to find in discount promotions
 	var discount entities.DiscountEntityNonSQL
-	if err := discountsCollection.FindOne(context.TODO(), bson.M{"promotion_entity.code": code}).Decode(&discount); err == nil {
-		logger.Info("Found discount promotion with code %s", code)
-		return discount, nil
+	err := r.db.Collection("discounts").FindOne(ctx, filter).Decode(&discount)
+	if err == nil {
+		detail := &models.PromotionDetail{Type: models.PromotionTypeDiscount, IsDeleted: discount.IsDeleted, Discount: entities.ToDiscountNonSQL(&discount)}
+		detail.Discount.Bank = r.bankByID(ctx, discount.BankID)
+		return detail, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("error finding discount promotion %s: %w", code, err)
 	}
 
-	// Try to find in financing promotions
 	var financing entities.FinancingEntityNonSQL
-	if err := financingsCollection.FindOne(context.TODO(), bson.M{"promotion_entity.code": code}).Decode(&financing); err == nil {
-		logger.Info("Found financing promotion with code %s", code)
-		return financing, nil
+	err = r.db.Collection("financings").FindOne(ctx, filter).Decode(&financing)
+	if err == nil {
+		detail := &models.PromotionDetail{Type: models.PromotionTypeFinancing, IsDeleted: financing.IsDeleted, Financing: entities.ToFinancingNonSQL(&financing)}
+		detail.Financing.Bank = r.bankByID(ctx, financing.BankID)
+		return detail, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("error finding financing promotion %s: %w", code, err)
+	}
+
+	return nil, fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// GetBankPromotions retrieves the promotions of a bank that have the given status on the given date.
+func (r *PromotionRepositoryMongo) GetBankPromotions(ctx context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	bank, err := findBankByCuit(ctx, r.db, bankCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	promotionsDiscount := []models.Discount{}
+	promotionsFinancing := []models.Financing{}
+
+	filter := promotionStatusFilter(status, date)
+	filter["bank_id"] = bank.ID
+	opts := options.Find().SetSort(bson.D{
+		{Key: "promotion_entity.validity_start_date", Value: 1},
+		{Key: "promotion_entity.code", Value: 1},
+	})
+
+	cursor, err := r.db.Collection("discounts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding %s discounts of bank %s: %w", status, bankCuit, err)
+	}
+	defer cursor.Close(ctx)
+
+	var discounts []entities.DiscountEntityNonSQL
+	if err := cursor.All(ctx, &discounts); err != nil {
+		return nil, nil, fmt.Errorf("error decoding discounts: %w", err)
+	}
+
+	cursor, err = r.db.Collection("financings").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding %s financings of bank %s: %w", status, bankCuit, err)
+	}
+	defer cursor.Close(ctx)
+
+	var financings []entities.FinancingEntityNonSQL
+	if err := cursor.All(ctx, &financings); err != nil {
+		return nil, nil, fmt.Errorf("error decoding financings: %w", err)
+	}
+
+	bankModel := entities.ToBankNonSQL(bank)
+	for _, discount := range discounts {
+		promotion := entities.ToDiscountNonSQL(&discount)
+		promotion.Bank = *bankModel
+		promotionsDiscount = append(promotionsDiscount, *promotion)
+	}
+	for _, financing := range financings {
+		promotion := entities.ToFinancingNonSQL(&financing)
+		promotion.Bank = *bankModel
+		promotionsFinancing = append(promotionsFinancing, *promotion)
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// UpdatePromotion applies the given changes to a promotion. Changes that do not apply to its type are ignored.
+func (r *PromotionRepositoryMongo) UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) error {
+	changes := bson.M{"updated_at": time.Now()}
+	if update.PromotionTitle != nil {
+		changes["promotion_entity.promotion_title"] = *update.PromotionTitle
+	}
+	if update.Comments != nil {
+		changes["promotion_entity.comments"] = *update.Comments
+	}
+
+	discountChanges := bson.M{}
+	if update.DiscountPercentage != nil {
+		discountChanges["discount_percentage"] = *update.DiscountPercentage
+	}
+	if update.PriceCap != nil {
+		discountChanges["price_cap"] = *update.PriceCap
+	}
+	if update.OnlyCash != nil {
+		discountChanges["only_cash"] = *update.OnlyCash
 	}
 
-	return nil, errors.New("No discount or financing promotion found with code " + code)
+	financingChanges := bson.M{}
+	if update.NumberOfQuotas != nil {
+		financingChanges["number_of_quotas"] = *update.NumberOfQuotas
+	}
+	if update.Interest != nil {
+		financingChanges["interest"] = *update.Interest
+	}
+
+	updated, err := r.updatePromotionDocument(ctx, "discounts", code, mergeChanges(changes, discountChanges))
+	if err != nil || updated {
+		return err
+	}
+	updated, err = r.updatePromotionDocument(ctx, "financings", code, mergeChanges(changes, financingChanges))
+	if err != nil || updated {
+		return err
+	}
+
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// RestorePromotion clears the logical delete of a promotion.
+func (r *PromotionRepositoryMongo) RestorePromotion(ctx context.Context, code string) error {
+	changes := bson.M{"is_deleted": false, "updated_at": time.Now()}
+
+	updated, err := r.updatePromotionDocument(ctx, "discounts", code, changes)
+	if err != nil || updated {
+		return err
+	}
+	updated, err = r.updatePromotionDocument(ctx, "financings", code, changes)
+	if err != nil || updated {
+		return err
+	}
+
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// updatePromotionDocument sets the changes on the promotion with the given code in the collection.
+// It reports whether such a promotion exists.
+func (r *PromotionRepositoryMongo) updatePromotionDocument(ctx context.Context, collection string, code string, changes bson.M) (bool, error) {
+	result, err := r.db.Collection(collection).UpdateOne(ctx, bson.M{"promotion_entity.code": code}, bson.M{"$set": changes})
+	if err != nil {
+		return false, fmt.Errorf("error updating promotion %s: %w", code, err)
+	}
+	if result.MatchedCount == 0 {
+		return false, nil
+	}
+
+	logger.Info("Promotion %s updated: %v", code, changes)
+	return true, nil
+}
+
+// bankByID resolves the bank referenced by a promotion, leaving it empty when it cannot be found.
+func (r *PromotionRepositoryMongo) bankByID(ctx context.Context, bankID bson.ObjectID) models.Bank {
+	var bank entities.BankEntityNonSQL
+	if err := r.db.Collection("banks").FindOne(ctx, bson.M{"_id": bankID}).Decode(&bank); err != nil {
+		return models.Bank{}
+	}
+	return *entities.ToBankNonSQL(&bank)
+}
+
+// promotionStatusFilter matches the promotions with the given status on the given date.
+// Documents written before the soft-delete flag existed have no is_deleted field and count as not deleted.
+func promotionStatusFilter(status models.PromotionStatus, date time.Time) bson.M {
+	switch status {
+	case models.PromotionStatusDeleted:
+		return bson.M{"is_deleted": true}
+	case models.PromotionStatusExpired:
+		return bson.M{"is_deleted": bson.M{"$ne": true}, "promotion_entity.validity_end_date": bson.M{"$lt": date}}
+	default:
+		return bson.M{"is_deleted": bson.M{"$ne": true}, "promotion_entity.validity_end_date": bson.M{"$gte": date}}
+	}
+}
+
+func mergeChanges(common bson.M, specific bson.M) bson.M {
+	merged := make(bson.M, len(common)+len(specific))
+	for field, value := range common {
+		merged[field] = value
+	}
+	for field, value := range specific {
+		merged[field] = value
+	}
+	return merged
+}
+
+// findPromotionByCode retrieves the financing or discount with the given code as a *models.Financing or *models.Discount.
+func (r *PromotionRepositoryMongo) findPromotionByCode(ctx context.Context, code string) (interface{}, error) {
+	logger.Info("Finding promotion with code %s", code)
+
+	detail, err := r.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Financing != nil {
+		return detail.Financing, nil
+	}
+	return detail.Discount, nil
 }
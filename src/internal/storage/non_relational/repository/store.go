@@ -20,26 +20,30 @@ func NewStoreNonRelationalRepository(db *mongo.Database) *StoreRepositoryMongo {
 	return &StoreRepositoryMongo{db: db}
 }
 
-// GetStoreWithHighestRevenueByMonth retrieves the store with the highest revenue for a specific month and year.
-func (r *StoreRepositoryMongo) GetStoreWithHighestRevenueByMonth(month int, year int) (models.StoreDTO, error) {
+// GetStoreWithHighestRevenueByMonth retrieves the store with the highest revenue for a specific month and year,
+// adding up single and monthly purchases. Ties are broken by the lowest CUIT; without purchases the result is empty.
+func (r *StoreRepositoryMongo) GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error) {
 
 	logger.Info("Retrieving store with highest revenue for month %d, year %d", month, year)
 
-	singlePurchasesCollection := r.db.Collection("purchase_single_payments")
-	monthlyPurchasesCollection := r.db.Collection("purchase_monthly_payments")
-
 	// Define the start and end dates for the given month and year
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
 
-	// Aggregation pipeline
+	inMonth := bson.D{{Key: "$match", Value: bson.M{
+		"purchase.created_at": bson.M{
+			"$gte": startDate,
+			"$lt":  endDate,
+		},
+	}}}
+
+	// Aggregation pipeline over both purchase collections
 	pipeline := mongo.Pipeline{
+		inMonth,
 		{
-			{Key: "$match", Value: bson.M{
-				"purchase.created_at": bson.M{
-					"$gte": startDate,
-					"$lt":  endDate,
-				},
+			{Key: "$unionWith", Value: bson.M{
+				"coll":     "purchase_monthly_payments",
+				"pipeline": mongo.Pipeline{inMonth},
 			}},
 		},
 		{
@@ -52,96 +56,45 @@ func (r *StoreRepositoryMongo) GetStoreWithHighestRevenueByMonth(month int, year
 			}},
 		},
 		{
-			{Key: "$sort", Value: bson.M{"total_amount": -1}},
+			{Key: "$sort", Value: bson.D{
+				{Key: "total_amount", Value: -1},
+				{Key: "_id.cuit_store", Value: 1},
+			}},
 		},
 		{
 			{Key: "$limit", Value: 1},
 		},
 	}
 
-	// Process single payments
-	cursorSingle, err := singlePurchasesCollection.Aggregate(context.TODO(), pipeline)
+	cursor, err := r.db.Collection("purchase_single_payments").Aggregate(ctx, pipeline)
 	if err != nil {
-		return models.StoreDTO{}, fmt.Errorf("error retrieving store with highest revenue from single purchases: %w", err)
+		return models.StoreDTO{}, fmt.Errorf("error retrieving store with highest revenue: %w", err)
 	}
-	defer cursorSingle.Close(context.TODO())
+	defer cursor.Close(ctx)
 
-	// Process monthly payments
-	cursorMonthly, err := monthlyPurchasesCollection.Aggregate(context.TODO(), pipeline)
-	if err != nil {
-		return models.StoreDTO{}, fmt.Errorf("error retrieving store with highest revenue from monthly purchases: %w", err)
-	}
-	defer cursorMonthly.Close(context.TODO())
-
-	// Decode results
-	var singleResult, monthlyResult struct {
+	var result struct {
 		ID struct {
 			Name string `bson:"store"`
 			Cuit string `bson:"cuit_store"`
 		} `bson:"_id"`
-		TotalAmount float64 `bson:"total_amount"`
-	}
-
-	hasSingle := cursorSingle.Next(context.TODO())
-	if hasSingle {
-		if err := cursorSingle.Decode(&singleResult); err != nil {
-			return models.StoreDTO{}, fmt.Errorf("error decoding single purchases: %w", err)
-		}
-		logger.Info("Single payment result: %v", singleResult)
+		TotalAmount models.Money `bson:"total_amount"`
 	}
 
-	hasMonthly := cursorMonthly.Next(context.TODO())
-	if hasMonthly {
-		if err := cursorMonthly.Decode(&monthlyResult); err != nil {
-			return models.StoreDTO{}, fmt.Errorf("error decoding monthly purchases: %w", err)
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return models.StoreDTO{}, fmt.Errorf("error reading store with highest revenue: %w", err)
 		}
-		logger.Info("Monthly payment result: %v", monthlyResult)
-	}
-
-	// Determine which store has the highest revenue
-	var bestStore struct {
-		Name        string
-		Cuit        string
-		TotalAmount float64
+		logger.Info("No purchases found for month %d, year %d", month, year)
+		return models.StoreDTO{}, nil
 	}
-
-	if hasSingle && hasMonthly {
-		if singleResult.TotalAmount >= monthlyResult.TotalAmount {
-			bestStore = struct {
-				Name        string
-				Cuit        string
-				TotalAmount float64
-			}{singleResult.ID.Name, singleResult.ID.Cuit, singleResult.TotalAmount}
-			logger.Info("Single payment result is higher: %v", bestStore)
-		} else {
-			bestStore = struct {
-				Name        string
-				Cuit        string
-				TotalAmount float64
-			}{monthlyResult.ID.Name, monthlyResult.ID.Cuit, monthlyResult.TotalAmount}
-			logger.Info("Monthly payment result is higher: %v", bestStore)
-		}
-	} else if hasSingle {
-		bestStore = struct {
-			Name        string
-			Cuit        string
-			TotalAmount float64
-		}{singleResult.ID.Name, singleResult.ID.Cuit, singleResult.TotalAmount}
-	} else if hasMonthly {
-		bestStore = struct {
-			Name        string
-			Cuit        string
-			TotalAmount float64
-		}{monthlyResult.ID.Name, monthlyResult.ID.Cuit, monthlyResult.TotalAmount}
-	} else {
-		return models.StoreDTO{}, fmt.Errorf("no results found for month %d, year %d", month, year)
+	if err := cursor.Decode(&result); err != nil {
+		return models.StoreDTO{}, fmt.Errorf("error decoding store with highest revenue: %w", err)
 	}
+	logger.Info("Store with highest revenue: %v", result)
 
 	// Map the result to the StoreDTO model
-	storeDTO := models.StoreDTO{
-		Name: bestStore.Name,
-		Cuit: bestStore.Cuit,
-	}
-
-	return storeDTO, nil
+	return models.StoreDTO{
+		Name: result.ID.Name,
+		Cuit: result.ID.Cuit,
+	}, nil
 }
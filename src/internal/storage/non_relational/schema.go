@@ -0,0 +1,512 @@
+/*
+ * Payment Registration System - MongoDB Schema
+ * --------------------------------------------
+ * This file declares the indexes and the $jsonSchema validators of every collection, and applies
+ * them idempotently: missing collections and indexes are created, and validators and indexes that
+ * differ from their declaration are replaced. Validators use the moderate validation level, so
+ * documents written before a validator existed can still be updated. Amounts and percentages that
+ * earlier versions stored as doubles are converted to Decimal128.
+ *
+ * Created: Mar. 20, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package nonrelational
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// collectionSchema declares the indexes and the validator of a collection.
+type collectionSchema struct {
+	Name      string
+	Indexes   []indexDefinition
+	Validator bson.D   // $jsonSchema document, ordered so that it can be compared with the stored one
+	Decimals  []string // Fields stored as Decimal128, a field of the elements of an array is written array[].field
+}
+
+// indexDefinition declares an index, named as MongoDB names it by default from its keys.
+type indexDefinition struct {
+	Keys   bson.D
+	Unique bool
+}
+
+// Name returns the default MongoDB name of the index, e.g. "promotion_entity.code_1".
+func (d indexDefinition) Name() string {
+	parts := make([]string, 0, len(d.Keys))
+	for _, key := range d.Keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", key.Key, key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+func ascending(keys ...string) bson.D {
+	index := bson.D{}
+	for _, key := range keys {
+		index = append(index, bson.E{Key: key, Value: 1})
+	}
+	return index
+}
+
+// object returns a $jsonSchema object with the given required fields and properties.
+func object(required []string, properties bson.D) bson.D {
+	schema := bson.D{{Key: "bsonType", Value: "object"}}
+	if len(required) > 0 {
+		schema = append(schema, bson.E{Key: "required", Value: required})
+	}
+	return append(schema, bson.E{Key: "properties", Value: properties})
+}
+
+func typed(bsonType string) bson.D {
+	return bson.D{{Key: "bsonType", Value: bsonType}}
+}
+
+func arrayOf(items bson.D) bson.D {
+	return bson.D{{Key: "bsonType", Value: "array"}, {Key: "items", Value: items}}
+}
+
+// purchaseSchema is the schema of the purchase embedded in single and monthly payments.
+var purchaseSchema = object(
+	[]string{"payment_voucher", "store", "cuit_store", "amount", "final_amount"},
+	bson.D{
+		{Key: "payment_voucher", Value: typed("string")},
+		{Key: "store", Value: typed("string")},
+		{Key: "cuit_store", Value: typed("string")},
+		{Key: "amount", Value: typed("number")},
+		{Key: "final_amount", Value: typed("number")},
+		{Key: "currency", Value: bson.D{{Key: "enum", Value: bson.A{"ARS", "USD"}}}},
+		{Key: "card_number", Value: typed("string")},
+		{Key: "promotion_code", Value: typed("string")},
+		{Key: "created_at", Value: typed("date")},
+	},
+)
+
+var singlePaymentSchema = object(
+	[]string{"purchase"},
+	bson.D{
+		{Key: "purchase", Value: purchaseSchema},
+		{Key: "store_discount", Value: typed("number")},
+		{Key: "paid_amount", Value: typed("number")},
+	},
+)
+
+// interestModelSchema accepts the interest models of financings and installment purchases.
+var interestModelSchema = bson.D{{Key: "enum", Value: bson.A{"flat", "french", "zero"}}}
+
+var monthlyPaymentSchema = object(
+	[]string{"purchase", "number_of_quotas"},
+	bson.D{
+		{Key: "purchase", Value: purchaseSchema},
+		{Key: "interest", Value: typed("number")},
+		{Key: "interest_model", Value: interestModelSchema},
+		{Key: "number_of_quotas", Value: typed("number")},
+		{Key: "quotas", Value: arrayOf(object(
+			[]string{"number", "price", "month", "year"},
+			bson.D{
+				{Key: "number", Value: typed("number")},
+				{Key: "price", Value: typed("number")},
+				{Key: "month", Value: typed("string")},
+				{Key: "year", Value: typed("string")},
+				{Key: "paid_amount", Value: typed("number")},
+			},
+		))},
+	},
+)
+
+// promotionSchema is the schema shared by discounts and financings, with the fields of each kind.
+func promotionSchema(required []string, properties bson.D) bson.D {
+	return object(
+		append([]string{"promotion_entity", "is_deleted"}, required...),
+		append(bson.D{
+			{Key: "promotion_entity", Value: object(
+				[]string{"code", "validity_start_date", "validity_end_date"},
+				bson.D{
+					{Key: "code", Value: typed("string")},
+					{Key: "promotion_title", Value: typed("string")},
+					{Key: "name_store", Value: typed("string")},
+					{Key: "cuit_store", Value: typed("string")},
+					{Key: "validity_start_date", Value: typed("date")},
+					{Key: "validity_end_date", Value: typed("date")},
+				},
+			)},
+			{Key: "is_deleted", Value: typed("bool")},
+			{Key: "bank_id", Value: typed("objectId")},
+		}, properties...),
+	)
+}
+
+// schemas declares the indexes and validators of every collection written by the repositories.
+var schemas = []collectionSchema{
+	{
+		Name:    "banks",
+		Indexes: []indexDefinition{{Keys: ascending("cuit"), Unique: true}},
+		Validator: object(
+			[]string{"cuit", "name"},
+			bson.D{
+				{Key: "cuit", Value: typed("string")},
+				{Key: "name", Value: typed("string")},
+				{Key: "customers", Value: arrayOf(typed("objectId"))},
+				{Key: "billing_cycle", Value: typed("object")},
+			},
+		),
+		Decimals: []string{"billing_cycle.surcharge_percentage"},
+	},
+	{
+		Name: "customers",
+		Indexes: []indexDefinition{
+			{Keys: ascending("cuit"), Unique: true},
+			{Keys: ascending("dni"), Unique: true},
+		},
+		Validator: object(
+			[]string{"complete_name", "dni", "cuit", "entry_date"},
+			bson.D{
+				{Key: "complete_name", Value: typed("string")},
+				{Key: "dni", Value: typed("string")},
+				{Key: "cuit", Value: typed("string")},
+				{Key: "entry_date", Value: typed("date")},
+				{Key: "banks", Value: arrayOf(typed("objectId"))},
+				{Key: "cards", Value: arrayOf(typed("objectId"))},
+			},
+		),
+	},
+	{
+		Name: "cards",
+		Indexes: []indexDefinition{
+			{Keys: ascending("number"), Unique: true},
+			{Keys: ascending("customer_cuit")},
+		},
+		Validator: object(
+			[]string{"number", "ccv", "since", "expiration_date"},
+			bson.D{
+				{Key: "number", Value: typed("string")},
+				{Key: "ccv", Value: typed("string")},
+				{Key: "cardholder_name_in_card", Value: typed("string")},
+				{Key: "since", Value: typed("date")},
+				{Key: "expiration_date", Value: typed("date")},
+				{Key: "status", Value: bson.D{{Key: "enum", Value: bson.A{"active", "blocked", "cancelled"}}}},
+				{Key: "bank_cuit", Value: typed("string")},
+				{Key: "customer_cuit", Value: typed("string")},
+			},
+		),
+	},
+	{
+		Name: "purchase_single_payments",
+		Indexes: []indexDefinition{
+			{Keys: ascending("purchase.card_number")},
+			{Keys: ascending("purchase.card_number", "purchase.payment_voucher"), Unique: true},
+			{Keys: ascending("purchase.created_at")},
+		},
+		Validator: singlePaymentSchema,
+		Decimals:  []string{"purchase.amount", "purchase.final_amount", "store_discount"},
+	},
+	{
+		Name: "purchase_monthly_payments",
+		Indexes: []indexDefinition{
+			{Keys: ascending("purchase.card_number")},
+			{Keys: ascending("purchase.card_number", "purchase.payment_voucher"), Unique: true},
+			{Keys: ascending("purchase.created_at")},
+		},
+		Validator: monthlyPaymentSchema,
+		Decimals:  []string{"purchase.amount", "purchase.final_amount", "interest", "quotas[].price"},
+	},
+	{
+		Name: "discounts",
+		Indexes: []indexDefinition{
+			{Keys: ascending("promotion_entity.code"), Unique: true},
+			{Keys: ascending("promotion_entity.cuit_store")},
+		},
+		Validator: promotionSchema(
+			[]string{"discount_percentage"},
+			bson.D{
+				{Key: "discount_percentage", Value: typed("number")},
+				{Key: "price_cap", Value: typed("number")},
+				{Key: "only_cash", Value: typed("bool")},
+			},
+		),
+		Decimals: []string{"discount_percentage", "price_cap"},
+	},
+	{
+		Name: "financings",
+		Indexes: []indexDefinition{
+			{Keys: ascending("promotion_entity.code"), Unique: true},
+			{Keys: ascending("promotion_entity.cuit_store")},
+		},
+		Validator: promotionSchema(
+			[]string{"number_of_quotas"},
+			bson.D{
+				{Key: "number_of_quotas", Value: typed("number")},
+				{Key: "interest", Value: typed("number")},
+				{Key: "interest_model", Value: interestModelSchema},
+			},
+		),
+		Decimals: []string{"interest"},
+	},
+	{
+		Name: "payment_summaries",
+		// A card has a single summary per month, SavePaymentSummary relies on it
+		Indexes: []indexDefinition{{Keys: ascending("card_number", "month", "year"), Unique: true}},
+		Validator: object(
+			[]string{"card_number", "month", "year", "total_price"},
+			bson.D{
+				{Key: "card_number", Value: typed("string")},
+				{Key: "month", Value: typed("number")},
+				{Key: "year", Value: typed("number")},
+				{Key: "total_price", Value: typed("number")},
+				{Key: "single_payments", Value: arrayOf(singlePaymentSchema)},
+				{Key: "monthly_payments", Value: arrayOf(monthlyPaymentSchema)},
+				{Key: "subtotals", Value: arrayOf(object(
+					[]string{"currency", "subtotal", "exchange_rate", "total"},
+					bson.D{
+						{Key: "currency", Value: typed("string")},
+						{Key: "subtotal", Value: typed("number")},
+						{Key: "exchange_rate", Value: typed("number")},
+						{Key: "total", Value: typed("number")},
+					},
+				))},
+			},
+		),
+		// The purchases of the snapshot are left as they are, they are still decoded from doubles
+		Decimals: []string{"surcharge_percentage", "total_price"},
+	},
+	{
+		Name:    "exchange_rates",
+		Indexes: []indexDefinition{{Keys: ascending("currency", "date"), Unique: true}},
+		Validator: object(
+			[]string{"currency", "date", "rate"},
+			bson.D{
+				{Key: "currency", Value: typed("string")},
+				{Key: "date", Value: typed("date")},
+				{Key: "rate", Value: typed("number")},
+			},
+		),
+		Decimals: []string{"rate"},
+	},
+	{
+		Name: "payments",
+		Indexes: []indexDefinition{
+			{Keys: ascending("code"), Unique: true},
+			{Keys: ascending("card_number", "month", "year")},
+		},
+		Validator: object(
+			[]string{"code", "card_number", "month", "year", "amount", "payment_date", "allocations"},
+			bson.D{
+				{Key: "code", Value: typed("string")},
+				{Key: "card_number", Value: typed("string")},
+				{Key: "month", Value: typed("number")},
+				{Key: "year", Value: typed("number")},
+				{Key: "amount", Value: typed("number")},
+				{Key: "payment_date", Value: typed("date")},
+				{Key: "allocations", Value: arrayOf(object(
+					[]string{"payment_voucher", "amount"},
+					bson.D{
+						{Key: "payment_voucher", Value: typed("string")},
+						{Key: "quota_number", Value: typed("number")},
+						{Key: "purchase_date", Value: typed("date")},
+						{Key: "amount", Value: typed("number")},
+					},
+				))},
+			},
+		),
+	},
+	{
+		// Checkpoints of the replication from the SQL database, one per table
+		Name: "sync_checkpoints",
+		Validator: object(
+			[]string{"updated_at", "id"},
+			bson.D{
+				{Key: "updated_at", Value: typed("string")},
+				{Key: "id", Value: typed("number")},
+				{Key: "synced_at", Value: typed("date")},
+			},
+		),
+	},
+}
+
+// EnsureSchema creates the declared collections, validators and indexes that are missing from the database,
+// replaces the ones that differ from their declaration and converts the decimal fields stored as numbers of
+// another type. It returns a description of every change, so an empty report means the database was already
+// up to date.
+func EnsureSchema(ctx context.Context, db *mongo.Database) ([]string, error) {
+	var changes []string
+	for _, schema := range schemas {
+		collectionChanges, err := ensureCollection(ctx, db, schema)
+		changes = append(changes, collectionChanges...)
+		if err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+func ensureCollection(ctx context.Context, db *mongo.Database, schema collectionSchema) ([]string, error) {
+	var changes []string
+	validator := bson.D{{Key: "$jsonSchema", Value: schema.Validator}}
+
+	specifications, err := db.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: schema.Name}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection %s: %w", schema.Name, err)
+	}
+
+	if len(specifications) == 0 {
+		opts := options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate")
+		if err := db.CreateCollection(ctx, schema.Name, opts); err != nil {
+			return nil, fmt.Errorf("failed to create collection %s: %w", schema.Name, err)
+		}
+		changes = append(changes, fmt.Sprintf("created collection %s with its validator", schema.Name))
+	} else {
+		upToDate, err := validatorUpToDate(specifications[0].Options, validator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the validator of %s: %w", schema.Name, err)
+		}
+		if !upToDate {
+			command := bson.D{
+				{Key: "collMod", Value: schema.Name},
+				{Key: "validator", Value: validator},
+				{Key: "validationLevel", Value: "moderate"},
+			}
+			if err := db.RunCommand(ctx, command).Err(); err != nil {
+				return nil, fmt.Errorf("failed to update the validator of %s: %w", schema.Name, err)
+			}
+			changes = append(changes, fmt.Sprintf("updated the validator of %s", schema.Name))
+		}
+	}
+
+	indexes := db.Collection(schema.Name).Indexes()
+	existing, err := indexes.ListSpecifications(ctx)
+	if err != nil {
+		return changes, fmt.Errorf("failed to list the indexes of %s: %w", schema.Name, err)
+	}
+	byName := make(map[string]mongo.IndexSpecification, len(existing))
+	for _, specification := range existing {
+		byName[specification.Name] = specification
+	}
+
+	for _, index := range schema.Indexes {
+		name := index.Name()
+		specification, found := byName[name]
+		if found {
+			same, err := indexUpToDate(specification, index)
+			if err != nil {
+				return changes, fmt.Errorf("failed to compare index %s.%s: %w", schema.Name, name, err)
+			}
+			if same {
+				continue
+			}
+			if err := indexes.DropOne(ctx, name); err != nil {
+				return changes, fmt.Errorf("failed to drop index %s.%s: %w", schema.Name, name, err)
+			}
+		}
+
+		model := mongo.IndexModel{Keys: index.Keys, Options: options.Index().SetName(name).SetUnique(index.Unique)}
+		if _, err := indexes.CreateOne(ctx, model); err != nil {
+			return changes, fmt.Errorf("failed to create index %s.%s: %w", schema.Name, name, err)
+		}
+		if found {
+			changes = append(changes, fmt.Sprintf("recreated index %s.%s", schema.Name, name))
+		} else {
+			changes = append(changes, fmt.Sprintf("created index %s.%s", schema.Name, name))
+		}
+	}
+
+	for _, field := range schema.Decimals {
+		converted, err := convertToDecimal(ctx, db.Collection(schema.Name), field)
+		if err != nil {
+			return changes, fmt.Errorf("failed to convert %s.%s to Decimal128: %w", schema.Name, field, err)
+		}
+		if converted > 0 {
+			changes = append(changes, fmt.Sprintf("converted %s.%s to Decimal128 in %d documents", schema.Name, field, converted))
+		}
+	}
+
+	return changes, nil
+}
+
+// convertToDecimal converts a field stored as a double or an integer to a Decimal128 rounded to two decimal places,
+// as the models encode amounts and percentages, and returns the number of documents updated. A field of the elements
+// of an array, written array[].field, is converted in every element.
+func convertToDecimal(ctx context.Context, collection *mongo.Collection, field string) (int64, error) {
+	toDecimal := func(value string) bson.D {
+		return bson.D{{Key: "$round", Value: bson.A{bson.D{{Key: "$toDecimal", Value: value}}, 2}}}
+	}
+
+	var filterPath string
+	var set bson.D
+	if array, elementField, ok := strings.Cut(field, "[]."); ok {
+		filterPath = array + "." + elementField
+		set = bson.D{{Key: array, Value: bson.D{{Key: "$map", Value: bson.D{
+			{Key: "input", Value: "$" + array},
+			{Key: "in", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{
+				"$$this",
+				bson.D{{Key: elementField, Value: toDecimal("$$this." + elementField)}},
+			}}}},
+		}}}}}
+	} else {
+		filterPath = field
+		set = bson.D{{Key: field, Value: toDecimal("$" + field)}}
+	}
+
+	filter := bson.D{{Key: filterPath, Value: bson.D{{Key: "$type", Value: bson.A{"double", "int", "long"}}}}}
+	result, err := collection.UpdateMany(ctx, filter, mongo.Pipeline{{{Key: "$set", Value: set}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// validatorUpToDate reports whether the collection options hold the given validator with the moderate level.
+// Both validators are decoded before comparing them, so that the comparison does not depend on how the
+// server encodes them.
+func validatorUpToDate(collectionOptions bson.Raw, validator bson.D) (bool, error) {
+	level, err := collectionOptions.LookupErr("validationLevel")
+	if err != nil || level.StringValue() != "moderate" {
+		return false, nil
+	}
+	stored, err := collectionOptions.LookupErr("validator")
+	if err != nil {
+		return false, nil
+	}
+
+	var current, expected bson.D
+	if err := bson.Unmarshal(stored.Document(), &current); err != nil {
+		return false, err
+	}
+	encoded, err := bson.Marshal(validator)
+	if err != nil {
+		return false, err
+	}
+	if err := bson.Unmarshal(encoded, &expected); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(current, expected), nil
+}
+
+// indexUpToDate reports whether an existing index has the keys and uniqueness of its definition.
+// Key directions are compared as numbers, the server may return them as int32, int64 or double.
+func indexUpToDate(specification mongo.IndexSpecification, index indexDefinition) (bool, error) {
+	unique := specification.Unique != nil && *specification.Unique
+	if unique != index.Unique {
+		return false, nil
+	}
+
+	elements, err := specification.KeysDocument.Elements()
+	if err != nil {
+		return false, err
+	}
+	if len(elements) != len(index.Keys) {
+		return false, nil
+	}
+	for i, element := range elements {
+		direction, ok := element.Value().AsInt64OK()
+		if !ok || element.Key() != index.Keys[i].Key || direction != int64(index.Keys[i].Value.(int)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package nonrelational
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestIndexNamesFollowMongoDefaults(t *testing.T) {
+	require.Equal(t, "cuit_1", indexDefinition{Keys: ascending("cuit")}.Name())
+	require.Equal(t, "promotion_entity.code_1", indexDefinition{Keys: ascending("promotion_entity.code")}.Name())
+	require.Equal(t, "card_number_1_month_1_year_1", indexDefinition{Keys: ascending("card_number", "month", "year")}.Name())
+}
+
+func TestSchemasDeclareUniqueIdentifiers(t *testing.T) {
+	unique := map[string]bool{}
+	names := map[string]bool{}
+	for _, schema := range schemas {
+		require.False(t, names[schema.Name], "collection %s is declared twice", schema.Name)
+		names[schema.Name] = true
+		require.NotEmpty(t, schema.Validator, schema.Name)
+		for _, index := range schema.Indexes {
+			if index.Unique {
+				unique[schema.Name+"."+index.Name()] = true
+			}
+		}
+	}
+
+	for _, index := range []string{"banks.cuit_1", "cards.number_1", "discounts.promotion_entity.code_1", "financings.promotion_entity.code_1", "exchange_rates.currency_1_date_1", "payments.code_1"} {
+		require.True(t, unique[index], "missing unique index %s", index)
+	}
+}
+
+func TestIndexUpToDate(t *testing.T) {
+	index := indexDefinition{Keys: ascending("card_number", "month"), Unique: true}
+	unique := true
+
+	keys := func(document bson.D) bson.Raw {
+		raw, err := bson.Marshal(document)
+		require.NoError(t, err)
+		return raw
+	}
+
+	// The server may return the directions as doubles
+	same, err := indexUpToDate(mongo.IndexSpecification{
+		KeysDocument: keys(bson.D{{Key: "card_number", Value: 1.0}, {Key: "month", Value: int64(1)}}),
+		Unique:       &unique,
+	}, index)
+	require.NoError(t, err)
+	require.True(t, same)
+
+	same, err = indexUpToDate(mongo.IndexSpecification{KeysDocument: keys(bson.D{{Key: "card_number", Value: 1}, {Key: "month", Value: 1}})}, index)
+	require.NoError(t, err)
+	require.False(t, same, "an index that is not unique differs")
+
+	same, err = indexUpToDate(mongo.IndexSpecification{KeysDocument: keys(bson.D{{Key: "card_number", Value: -1}, {Key: "month", Value: 1}}), Unique: &unique}, index)
+	require.NoError(t, err)
+	require.False(t, same, "an index with another direction differs")
+}
+
+func TestValidatorUpToDate(t *testing.T) {
+	validator := bson.D{{Key: "$jsonSchema", Value: schemas[0].Validator}}
+	collectionOptions := func(level string, validator bson.D) bson.Raw {
+		raw, err := bson.Marshal(bson.D{{Key: "validator", Value: validator}, {Key: "validationLevel", Value: level}})
+		require.NoError(t, err)
+		return raw
+	}
+
+	same, err := validatorUpToDate(collectionOptions("moderate", validator), validator)
+	require.NoError(t, err)
+	require.True(t, same)
+
+	same, err = validatorUpToDate(collectionOptions("strict", validator), validator)
+	require.NoError(t, err)
+	require.False(t, same, "a validator with another level differs")
+
+	other := bson.D{{Key: "$jsonSchema", Value: schemas[1].Validator}}
+	same, err = validatorUpToDate(collectionOptions("moderate", other), validator)
+	require.NoError(t, err)
+	require.False(t, same, "another validator differs")
+
+	withoutValidator, err := bson.Marshal(bson.D{{Key: "validationLevel", Value: "moderate"}})
+	require.NoError(t, err)
+	same, err = validatorUpToDate(withoutValidator, validator)
+	require.NoError(t, err)
+	require.False(t, same, "a collection without validator differs")
+}
@@ -0,0 +1,206 @@
+package relational
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/migrations"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// SQL dialects supported by NewSQLDB.
+const (
+	DialectMySQL    = "mysql"    // MySQL server, the DSN is a go-sql-driver/mysql DSN
+	DialectSQLite   = "sqlite"   // SQLite file through a pure-Go driver, the DSN is a file path or "file::memory:?cache=shared"
+	DialectPostgres = "postgres" // PostgreSQL server, the DSN is a pgx keyword/value DSN or a postgres:// URL
+)
+
+// OpenSQLDB opens a connection to a database of the given dialect, leaving its schema untouched.
+// The schema is managed by the versioned migrations of the migrations package.
+func OpenSQLDB(dialect string, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch dialect {
+	case DialectMySQL:
+		dialector = mysql.Open(dsn)
+	case DialectSQLite:
+		dialector = sqlite.Open(dsn)
+	case DialectPostgres:
+		dialector = postgres.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unknown SQL dialect %q, expected %s, %s or %s", dialect, DialectMySQL, DialectSQLite, DialectPostgres)
+	}
+
+	newLogger := gorm_logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
+		gorm_logger.Config{
+			SlowThreshold:             time.Second,        // Slow SQL threshold
+			LogLevel:                  gorm_logger.Silent, // Log level
+			IgnoreRecordNotFoundError: false,              // Ignore ErrRecordNotFound error for logger
+			ParameterizedQueries:      false,              // Don't include params in the SQL log
+			Colorful:                  true,               // Disable color
+		},
+	)
+	// Open a new GORM connection using the driver of the dialect. Driver errors are translated so that
+	// repositories can tell unique violations apart with gorm.ErrDuplicatedKey on every dialect.
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         newLogger,
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s database: %w", dialect, err)
+	}
+
+	if dialect == DialectSQLite {
+		// SQLite allows a single writer, concurrent requests wait for the connection instead of failing with SQLITE_BUSY
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve database connection: %w", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	return db, nil
+}
+
+// NewSQLDB opens a connection to a database of the given dialect and creates the schema with AutoMigrate,
+// dropping every table first if cleanDB is true. It is meant for development and tests, deployed databases
+// are opened with OpenSQLDB and migrated with the migrate command.
+func NewSQLDB(dialect string, dsn string, cleanDB bool) (*gorm.DB, error) {
+	db, err := OpenSQLDB(dialect, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize the database schema
+	if err := initSQLDB(db, cleanDB); err != nil {
+		return nil, fmt.Errorf("failed to initialize %s database: %w", dialect, err)
+	}
+
+	return db, nil
+}
+
+// NewMySQLDB creates a new connection to the MySQL database and initializes the schema.
+func NewMySQLDB(dsn string, cleanDB bool) (*gorm.DB, error) {
+	return NewSQLDB(DialectMySQL, dsn, cleanDB)
+}
+
+// CloseDB gracefully closes the database connection.
+func CloseDB(database *gorm.DB) error {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve database connection: %w", err)
+	}
+
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database connection: %w", err)
+	}
+
+	return nil
+}
+
+// initSQLDB initializes the database schema.
+// If `cleanDB` is true, it will drop existing tables before migrating.
+func initSQLDB(database *gorm.DB, cleanDB bool) error {
+	if cleanDB {
+		logger.Info("Cleaning the database: dropping existing tables...")
+		// Retrieve the list of tables
+		tables, err := database.Migrator().GetTables()
+		if err != nil {
+			return fmt.Errorf("failed to retrieve table list: %w", err)
+		}
+
+		// Drop each table, except the internal tables of SQLite
+		for _, table := range tables {
+			if strings.HasPrefix(table, "sqlite_") {
+				continue
+			}
+			if err := database.Migrator().DropTable(table); err != nil {
+				return fmt.Errorf("failed to drop table %s: %w", table, err)
+			}
+		}
+	}
+
+	migrator, err := migrations.New(database)
+	if err != nil {
+		return err
+	}
+	baseline, err := requireAutoMigratable(database, migrator)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Migrating database schema...")
+	// Automate the creation of tables based on entity definitions
+	if err := database.AutoMigrate(
+		&entities.BankEntitySQL{},
+		&entities.CustomerEntitySQL{},
+		&entities.CardEntitySQL{},
+		&entities.QuotaEntitySQL{},
+		&entities.PurchaseMonthlyPaymentsEntitySQL{},
+		&entities.PurchaseSinglePaymentEntitySQL{},
+		&entities.DiscountEntitySQL{},
+		&entities.FinancingEntitySQL{},
+		&entities.PaymentSummaryEntitySQL{},
+		&entities.BillingCycleEntitySQL{},
+		&entities.PaymentSummarySubtotalEntitySQL{},
+		&entities.ExchangeRateEntitySQL{},
+		&entities.PaymentEntitySQL{},
+		&entities.PaymentAllocationEntitySQL{},
+	); err != nil {
+		return fmt.Errorf("failed to migrate database schema: %w", err)
+	}
+
+	// The schema created from scratch now matches the latest migration, so the migrate command must not apply any of them
+	if baseline {
+		if _, err := migrator.Baseline(); err != nil {
+			return fmt.Errorf("failed to record the migrations of the schema: %w", err)
+		}
+	}
+
+	logger.Info("Database schema initialized successfully.")
+	return nil
+}
+
+// requireAutoMigratable returns an error if AutoMigrate cannot be trusted to leave the schema at the latest
+// migration, and whether the database is empty, so that the migrations must be recorded after AutoMigrate.
+// A database with some migrations pending would skip their data changes, and one with tables but no recorded
+// migration has a schema of unknown version: both must be migrated with the migrate command, or cleaned.
+func requireAutoMigratable(database *gorm.DB, migrator *migrations.Migrator) (bool, error) {
+	tables, err := database.Migrator().GetTables()
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve table list: %w", err)
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		return false, err
+	}
+
+	applied := 0
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			applied++
+		}
+	}
+	switch {
+	case applied == len(statuses):
+		return false, nil
+	case applied > 0:
+		return false, fmt.Errorf("the schema has %d pending migrations, run the migrate command instead of AutoMigrate", len(statuses)-applied)
+	}
+
+	for _, table := range tables {
+		if table != "schema_migrations" && !strings.HasPrefix(table, "sqlite_") {
+			return false, fmt.Errorf("table %s exists but no migration is recorded, run the migrate command or clean the database instead of AutoMigrate", table)
+		}
+	}
+	return true, nil
+}
@@ -19,14 +19,14 @@ INSERT INTO CARDS (number, ccv, cardholder_name_in_card, since, expiration_date,
 INSERT INTO PURCHASE_SINGLE_PAYMENTS (payment_voucher, store, cuit_store, amount, final_amount, created_at, updated_at, card_id, store_discount ) VALUES ( 'PV20241001','Store A','30-12345678-9',100.00,90.00,'2024-10-01 12:00:00',NOW(),2,10.00);
 INSERT INTO PURCHASE_MONTHLY_PAYMENTS (payment_voucher, store, cuit_store, amount, final_amount, created_at, updated_at, card_id, interest, number_of_quotas ) VALUES ('PV20241001', 'Store A', '30-12345678-9', 110.00, 330.00, '2024-10-01 12:00:00', NOW(), 2, 10.0, 3 );
 INSERT INTO QUOTAS (number, price, month, year, purchase_monthly_payments_entity_id, created_at, updated_at) VALUES(1, 110.00, '10', '2024', 3, NOW(), NOW()),(2, 110.00, '11', '2024', 3, NOW(), NOW()),(3, 110.00, '12', '2024', 3, NOW(), NOW());
-INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(1, 'SUMMARY-2024-10', 10, 2024, '2024-10-28 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 410.0, 2, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
+INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(1, 'SUMMARY-2024-09', 9, 2024, '2024-10-28 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 410.0, 2, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
 INSERT INTO CARDS (number, ccv, cardholder_name_in_card, since, expiration_date, bank_id, customer_id, created_at, updated_at ) VALUES ('123456789987654', '456', 'Martin Antolini', '2022-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW() );
-INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(2, 'SUMMARY-2024-10', 10, 2024, '2024-11-09 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 600.0, 3, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
+INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(2, 'SUMMARY-2024-09', 9, 2024, '2024-11-09 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 600.0, 3, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
 INSERT INTO CARDS (number, ccv, cardholder_name_in_card, since, expiration_date, bank_id, customer_id, created_at, updated_at ) VALUES ('987654321123321', '456', 'Rocio Amanate', '2022-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW() );
-INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(3, 'SUMMARY-2024-10', 10, 2024, '2025-01-30 17:34:54.239', '2025-02-10 17:34:54.239', 5.0, 800.0, 4, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
+INSERT INTO PAYMENT_SUMMARIES (id, code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at) VALUES(3, 'SUMMARY-2024-09', 9, 2024, '2025-01-30 17:34:54.239', '2025-02-10 17:34:54.239', 5.0, 800.0, 4, '2024-10-16 17:34:54.239', '2024-10-16 17:34:54.239');
 INSERT INTO CARDS (number, ccv, cardholder_name_in_card, since, expiration_date, bank_id, customer_id, created_at, updated_at)VALUES ('1111222233334444', '123', 'User A', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222233335555', '234', 'User B', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222233336666', '345', 'User C', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222233337777', '456', 'User D', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222233338888', '567', 'User E', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222233339999', '678', 'User F', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244440000', '789', 'User G', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244441111', '890', 'User H', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244442222', '901', 'User I', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244443333', '012', 'User J', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244444444', '123', 'User K', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244445555', '234', 'User L', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244446666', '345', 'User M', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244447777', '456', 'User N', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW()),('1111222244448888', '567', 'User O', '2021-01-01 10:00:00', '2025-12-31 23:59:59', 1, 1, NOW(), NOW());
 INSERT INTO PURCHASE_SINGLE_PAYMENTS (payment_voucher, store, cuit_store, amount, final_amount, created_at, updated_at, card_id, store_discount ) VALUES ('SUMMERSALE2024', 'Store D', '20-98765432-1', 25000.00, 23000.00,  '2024-11-10 16:45:00', NOW(), 19, 20.00 );
-INSERT INTO PURCHASE_SINGLE_PAYMENTS (payment_voucher, store, cuit_store, amount, final_amount, created_at, updated_at, card_id, store_discount)VALUES('PV20241001', 'Store A', '30-12345678-9', 100.00, 90.00, '2024-10-05 12:00:00', NOW(), 1, 10.00),('PV20241002', 'Store B', '30-22334455-6', 200.00, 180.00, '2024-10-06 12:00:00', NOW(), 2, 20.00),('PV20241003', 'Store C', '30-33445566-7', 300.00, 270.00, '2024-10-07 12:00:00', NOW(), 3, 30.00),('SUMMERSALE2024', 'Store D', '20-98765432-1', 150.00, 135.00, '2024-10-08 12:00:00', NOW(), 4, 15.00),('SPRINGDEAL2024', 'Store E', '20-98765432-1', 250.00, 225.00, '2024-10-09 12:00:00', NOW(), 5, 25.00),('PV20241006', 'Store F', '30-66778899-0', 350.00, 315.00, '2024-10-10 12:00:00', NOW(), 6, 35.00),('PV20241007', 'Store G', '30-77889900-1', 450.00, 405.00, '2024-10-11 12:00:00', NOW(), 7, 45.00),('PV20241008', 'Store H', '30-88990011-2', 500.00, 450.00, '2024-10-12 12:00:00', NOW(), 8, 50.00),('PV20241009', 'Store I', '30-99001122-3', 600.00, 540.00, '2024-10-13 12:00:00', NOW(), 9, 60.00),('PV20241010', 'Store J', '30-10011223-4', 700.00, 630.00, '2024-10-14 12:00:00', NOW(), 10, 70.00),('PV20241011', 'Store K', '30-11022334-5', 800.00, 720.00, '2024-10-15 12:00:00', NOW(), 11, 80.00),('PV20241012', 'Store L', '30-12033445-6', 900.00, 810.00, '2024-10-16 12:00:00', NOW(), 12, 90.00),('PV20241013', 'Store M', '30-13044556-7', 1000.00, 900.00, '2024-10-17 12:00:00', NOW(), 13, 100.00),('PV20241014', 'Store N', '30-14055667-8', 1100.00, 990.00, '2024-10-18 12:00:00', NOW(), 14, 110.00),('PV20241015', 'Store O', '30-15066778-9', 1200.00, 1080.00, '2024-10-19 12:00:00', NOW(), 15, 120.00);
+INSERT INTO PURCHASE_SINGLE_PAYMENTS (payment_voucher, store, cuit_store, amount, final_amount, created_at, updated_at, card_id, store_discount)VALUES('PV20241005', 'Store A', '30-12345678-9', 100.00, 90.00, '2024-10-05 12:00:00', NOW(), 1, 10.00),('PV20241002', 'Store B', '30-22334455-6', 200.00, 180.00, '2024-10-06 12:00:00', NOW(), 2, 20.00),('PV20241003', 'Store C', '30-33445566-7', 300.00, 270.00, '2024-10-07 12:00:00', NOW(), 3, 30.00),('SUMMERSALE2024', 'Store D', '20-98765432-1', 150.00, 135.00, '2024-10-08 12:00:00', NOW(), 4, 15.00),('SPRINGDEAL2024', 'Store E', '20-98765432-1', 250.00, 225.00, '2024-10-09 12:00:00', NOW(), 5, 25.00),('PV20241006', 'Store F', '30-66778899-0', 350.00, 315.00, '2024-10-10 12:00:00', NOW(), 6, 35.00),('PV20241007', 'Store G', '30-77889900-1', 450.00, 405.00, '2024-10-11 12:00:00', NOW(), 7, 45.00),('PV20241008', 'Store H', '30-88990011-2', 500.00, 450.00, '2024-10-12 12:00:00', NOW(), 8, 50.00),('PV20241009', 'Store I', '30-99001122-3', 600.00, 540.00, '2024-10-13 12:00:00', NOW(), 9, 60.00),('PV20241010', 'Store J', '30-10011223-4', 700.00, 630.00, '2024-10-14 12:00:00', NOW(), 10, 70.00),('PV20241011', 'Store K', '30-11022334-5', 800.00, 720.00, '2024-10-15 12:00:00', NOW(), 11, 80.00),('PV20241012', 'Store L', '30-12033445-6', 900.00, 810.00, '2024-10-16 12:00:00', NOW(), 12, 90.00),('PV20241013', 'Store M', '30-13044556-7', 1000.00, 900.00, '2024-10-17 12:00:00', NOW(), 13, 100.00),('PV20241014', 'Store N', '30-14055667-8', 1100.00, 990.00, '2024-10-18 12:00:00', NOW(), 14, 110.00),('PV20241015', 'Store O', '30-15066778-9', 1200.00, 1080.00, '2024-10-19 12:00:00', NOW(), 15, 120.00);
 INSERT INTO PAYMENT_SUMMARIES (code, `month`, `year`, first_expiration, second_expiration, surcharge_percentage, total_price, card_id, created_at, updated_at)VALUES('SUMMARY-2024-10-A', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 330.00, 1, NOW(), NOW()),('SUMMARY-2024-10-B', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 440.00, 2, NOW(), NOW()),('SUMMARY-2024-10-C', 10, 2024, '2024-11-09 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 550.00, 3, NOW(), NOW()),('SUMMARY-2024-10-D', 10, 2024, '2024-11-09 17:34:54.239', '2024-11-10 17:34:54.239', 5.0, 360.00, 4, NOW(), NOW()),('SUMMARY-2024-10-E', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 270.00, 5, NOW(), NOW()),('SUMMARY-2024-10-F', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 315.00, 6, NOW(), NOW()),('SUMMARY-2024-10-G', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 405.00, 7, NOW(), NOW()),('SUMMARY-2024-10-H', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 450.00, 8, NOW(), NOW()),('SUMMARY-2024-10-I', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 540.00, 9, NOW(), NOW()),( 'SUMMARY-2024-10-J', 10, 2024, '2024-12-09 17:34:54.239', '2025-01-10 17:34:54.239', 5.0, 630.00, 10, NOW(), NOW());
 INSERT INTO BANKS (id, name, cuit, address, telephone, created_at, updated_at) VALUES (2, 'BBVA', '30-98765432-1', '456 High St, Buenos Aires', '+54 11 8765 4321', '2024-10-14 01:05:00', '2024-10-14 01:05:00'),(3, 'HSBC', '30-11223344-5', '789 Park Ave, Buenos Aires', '+54 11 1122 3344', '2024-10-14 01:10:00', '2024-10-14 01:10:00'),(4, 'Banco Nación', '30-55667788-2', '1010 State St, Buenos Aires', '+54 11 5566 7788', '2024-10-14 01:15:00', '2024-10-14 01:15:00');
 INSERT INTO CUSTOMERS (complete_name, dni, cuit, address, telephone, entry_date, created_at, updated_at) VALUES ('Jane Smith', '23456789', '20-23456789-0', '2345 Maple Street', '321-654-9870', '2023-02-10', NOW(), NOW()),('Paul Brown', '34567890', '20-34567890-1', '3456 Oak Street', '123-987-6540', '2023-03-20', NOW(), NOW()),('Emily White', '45678901', '20-45678901-2', '4567 Pine Street', '987-654-3210', '2023-04-25', NOW(), NOW()),('Michael Green', '56789012', '20-56789012-3', '5678 Cedar Street', '654-321-0987', '2023-05-30', NOW(), NOW()),('Laura Black', '67890123', '20-67890123-4', '6789 Birch Street', '789-012-3456', '2023-06-15', NOW(), NOW());
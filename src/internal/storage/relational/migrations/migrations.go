@@ -0,0 +1,312 @@
+/*
+ * Payment Registration System - Relational Schema Migrations
+ * ----------------------------------------------------------
+ * This file defines the versioned migrations of the relational schema. Every migration is a pair of
+ * SQL files, <version>_<name>.up.sql and <version>_<name>.down.sql, embedded for each dialect in the
+ * directory of the dialect. The applied versions are recorded in the schema_migrations table.
+ *
+ * Created: Mar. 18, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"gorm.io/gorm"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Migration is a versioned change of the schema and the statements that revert it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and whether it has been applied to the database.
+type Status struct {
+	Migration
+	AppliedAt *time.Time // nil if the migration is pending
+}
+
+// schemaMigration is a row of the table recording the applied migrations.
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load returns the migrations of a dialect ordered by version.
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for SQL dialect %q", dialect)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("unexpected migration file %s/%s", dialect, name)
+		}
+
+		// File names are <version>_<name>.<direction>.sql
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, migrationName, found := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %s/%s does not start with a positive version", dialect, name)
+		}
+
+		content, err := files.ReadFile(path.Join(dialect, name))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s/%s: %w", dialect, name, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: migrationName}
+			byVersion[version] = migration
+		} else if migration.Name != migrationName {
+			return nil, fmt.Errorf("migration %d of %s is named both %s and %s", version, dialect, migration.Name, migrationName)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s of %s needs both an up and a down file", migration.Version, migration.Name, dialect)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts the migrations of the dialect of a database.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the database, using the migrations of its dialect.
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in version order and returns the applied migrations.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		logger.Info("Applying migration %04d_%s...", migration.Version, migration.Name)
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("error applying migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Baseline records every migration as applied without running it, for a schema created from scratch by other
+// means, such as AutoMigrate, that matches the latest migration. It refuses a database with recorded migrations,
+// whose schema was created by them. It returns the recorded migrations.
+func (m *Migrator) Baseline() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) < len(m.migrations) {
+		return nil, fmt.Errorf("the database has %d migrations recorded, only a database without any can be baselined", len(m.migrations)-len(pending))
+	}
+
+	for _, migration := range pending {
+		if err := m.db.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error; err != nil {
+			return nil, fmt.Errorf("error recording migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down reverts the given number of applied migrations, latest first, and returns the reverted migrations.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("the number of migrations to revert must be positive, got %d", steps)
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		logger.Info("Reverting migration %04d_%s...", migration.Version, migration.Name)
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("error reverting migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Status returns every migration of the dialect and when it was applied.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			result = append(result, status.Migration)
+		}
+	}
+	return result, nil
+}
+
+// RequireUpToDate returns an error if the database has pending migrations.
+func RequireUpToDate(db *gorm.DB) error {
+	migrator, err := New(db)
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("the schema has %d pending migrations starting at %04d_%s, run the migrate command", len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// applied returns the recorded migrations by version, creating the schema_migrations table if missing.
+func (m *Migrator) applied() (map[int64]schemaMigration, error) {
+	if !m.db.Migrator().HasTable(&schemaMigration{}) {
+		if err := m.db.Migrator().CreateTable(&schemaMigration{}); err != nil {
+			return nil, fmt.Errorf("error creating the schema_migrations table: %w", err)
+		}
+	}
+
+	var rows []schemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error reading the applied migrations: %w", err)
+	}
+
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// execStatements runs the statements of a migration file one by one. Statements end with a semicolon at the
+// end of a line, and lines starting with "--" are comments.
+func execStatements(tx *gorm.DB, script string) error {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";\n") {
+		// The pure-Go SQLite driver rejects a trailing semicolon
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement == "" {
+			continue
+		}
+		if err := tx.Exec(statement).Error; err != nil {
+			return fmt.Errorf("error executing %q: %w", firstLine(statement), err)
+		}
+	}
+	return nil
+}
+
+func firstLine(statement string) string {
+	line, _, _ := strings.Cut(statement, "\n")
+	return line
+}
@@ -0,0 +1,184 @@
+package migrations_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/migrations"
+	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+func openSQLite(t *testing.T) *gorm.DB {
+	db, err := relational.OpenSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+	return db
+}
+
+func TestEveryDialectHasTheSameMigrations(t *testing.T) {
+	reference, err := migrations.Load(relational.DialectMySQL)
+	require.NoError(t, err)
+	require.NotEmpty(t, reference)
+
+	for _, dialect := range []string{relational.DialectSQLite, relational.DialectPostgres} {
+		loaded, err := migrations.Load(dialect)
+		require.NoError(t, err, dialect)
+		require.Len(t, loaded, len(reference), dialect)
+		for i := range reference {
+			require.Equal(t, reference[i].Version, loaded[i].Version, dialect)
+			require.Equal(t, reference[i].Name, loaded[i].Name, dialect)
+		}
+	}
+
+	_, err = migrations.Load("oracle")
+	require.Error(t, err)
+}
+
+func TestUpAndDown(t *testing.T) {
+	db := openSQLite(t)
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	all, err := migrations.Load(relational.DialectSQLite)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, len(all))
+	require.True(t, db.Migrator().HasTable(&entities.BankEntitySQL{}))
+
+	// Applying again is a no-op
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.NotNil(t, status.AppliedAt, "migration %d", status.Version)
+	}
+
+	reverted, err := migrator.Down(len(all))
+	require.NoError(t, err)
+	require.Len(t, reverted, len(all))
+	require.Equal(t, all[len(all)-1].Version, reverted[0].Version, "the latest migration is reverted first")
+	require.False(t, db.Migrator().HasTable(&entities.BankEntitySQL{}))
+
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, len(all))
+	require.Error(t, migrations.RequireUpToDate(db))
+
+	_, err = migrator.Down(0)
+	require.Error(t, err)
+
+	// The schema can be recreated after reverting it
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, len(all))
+}
+
+func TestUpAdoptsAnAutoMigratedDatabase(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "payment_registration.db")
+	db, err := relational.NewSQLDB(relational.DialectSQLite, dsn, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	require.NoError(t, migrations.RequireUpToDate(db))
+}
+
+func TestAutoMigrateRecordsEveryMigration(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "payment_registration.db")
+	db, err := relational.NewSQLDB(relational.DialectSQLite, dsn, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+
+	require.NoError(t, migrations.RequireUpToDate(db), "AutoMigrate creates the schema of the latest migration")
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Baseline()
+	require.Error(t, err, "a database with recorded migrations cannot be baselined again")
+
+	// Restarting with AutoMigrate keeps the recorded migrations
+	restarted, err := relational.NewSQLDB(relational.DialectSQLite, dsn, false)
+	require.NoError(t, err)
+	require.NoError(t, relational.CloseDB(restarted))
+	require.NoError(t, migrations.RequireUpToDate(db))
+}
+
+func TestAutoMigrateRefusesAnUnverifiedSchema(t *testing.T) {
+	// Some migrations pending: AutoMigrate would skip their data changes
+	dsn := filepath.Join(t.TempDir(), "partial.db")
+	db, err := relational.OpenSQLDB(relational.DialectSQLite, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	_, err = migrator.Down(1)
+	require.NoError(t, err)
+
+	_, err = relational.NewSQLDB(relational.DialectSQLite, dsn, false)
+	require.Error(t, err)
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "the pending migration is not recorded")
+
+	// Tables without recorded migrations: their schema could be of any version
+	dsn = filepath.Join(t.TempDir(), "unrecorded.db")
+	db, err = relational.OpenSQLDB(relational.DialectSQLite, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+	require.NoError(t, db.Migrator().CreateTable(&entities.BankEntitySQL{}))
+
+	_, err = relational.NewSQLDB(relational.DialectSQLite, dsn, false)
+	require.Error(t, err)
+	require.Error(t, migrations.RequireUpToDate(db))
+
+	// Cleaning the database drops the unverified schema first
+	cleaned, err := relational.NewSQLDB(relational.DialectSQLite, dsn, true)
+	require.NoError(t, err)
+	require.NoError(t, migrations.RequireUpToDate(cleaned))
+	require.NoError(t, relational.CloseDB(cleaned))
+}
+
+// TestMigratedSchemaStorageContract runs the storage contract against a schema created by the migrations
+// instead of AutoMigrate, so both keep matching the entities.
+func TestMigratedSchemaStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storages {
+		db := openSQLite(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up()
+		require.NoError(t, err)
+
+		return storagetest.Storages{
+			Banks:         relational_repository.NewBankRelationalRepository(db),
+			Cards:         relational_repository.NewCardRelationalRepository(db),
+			Promotions:    relational_repository.NewPromotionRelationRepository(db),
+			Stores:        relational_repository.NewStoreRelationalRepository(db),
+			Customers:     relational_repository.NewCustomerRelationalRepository(db),
+			ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(db),
+			Payments:      relational_repository.NewPaymentRelationalRepository(db),
+			Compensation:  relational_repository.NewCompensationRelationalRepository(db),
+		}
+	})
+}
@@ -0,0 +1,202 @@
+-- Schema created by AutoMigrate before versioned migrations were introduced. Every table is created
+-- only if missing, so databases created by AutoMigrate are adopted as version 1.
+
+CREATE TABLE IF NOT EXISTS `BANKS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(255),
+    `cuit` varchar(255) NOT NULL,
+    `address` varchar(255),
+    `telephone` varchar(255),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_BANKS_cuit` (`cuit`)
+);
+
+CREATE TABLE IF NOT EXISTS `CUSTOMERS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `complete_name` varchar(255) NOT NULL,
+    `dni` varchar(20) NOT NULL,
+    `cuit` varchar(20) NOT NULL,
+    `address` varchar(255),
+    `telephone` varchar(50),
+    `entry_date` datetime(3) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_CUSTOMERS_dni` UNIQUE (`dni`),
+    CONSTRAINT `uni_CUSTOMERS_cuit` UNIQUE (`cuit`)
+);
+
+CREATE TABLE IF NOT EXISTS `customers_banks` (
+    `customer_entity_sql_id` bigint unsigned,
+    `bank_entity_sql_id` bigint unsigned,
+    PRIMARY KEY (`customer_entity_sql_id`, `bank_entity_sql_id`),
+    CONSTRAINT `fk_customers_banks_customer_entity_sql` FOREIGN KEY (`customer_entity_sql_id`) REFERENCES `CUSTOMERS` (`id`),
+    CONSTRAINT `fk_customers_banks_bank_entity_sql` FOREIGN KEY (`bank_entity_sql_id`) REFERENCES `BANKS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `CARDS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `number` varchar(16) NOT NULL,
+    `ccv` varchar(3) NOT NULL,
+    `cardholder_name_in_card` varchar(255) NOT NULL,
+    `since` datetime(3) NOT NULL,
+    `expiration_date` datetime(3) NOT NULL,
+    `status` varchar(20) NOT NULL DEFAULT 'active',
+    `bank_id` bigint unsigned,
+    `customer_id` bigint unsigned,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_CARDS_bank_id` (`bank_id`),
+    INDEX `idx_CARDS_customer_id` (`customer_id`),
+    CONSTRAINT `fk_CARDS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `fk_CUSTOMERS_cards` FOREIGN KEY (`customer_id`) REFERENCES `CUSTOMERS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `PURCHASE_MONTHLY_PAYMENTS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `payment_voucher` varchar(255) NOT NULL,
+    `store` varchar(255) NOT NULL,
+    `cuit_store` varchar(20) NOT NULL,
+    `amount` double NOT NULL,
+    `final_amount` double NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `card_id` bigint unsigned NOT NULL,
+    `promotion_code` varchar(255),
+    `interest` double NOT NULL,
+    `number_of_quotas` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_PURCHASE_MONTHLY_PAYMENTS_card_id` (`card_id`),
+    CONSTRAINT `fk_CARDS_purchase_monthly_payments` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `QUOTAS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `number` bigint NOT NULL,
+    `price` double NOT NULL,
+    `month` varchar(2) NOT NULL,
+    `year` varchar(4) NOT NULL,
+    `purchase_monthly_payments_entity_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_QUOTAS_purchase_monthly_payments_entity_id` (`purchase_monthly_payments_entity_id`),
+    CONSTRAINT `fk_PURCHASE_MONTHLY_PAYMENTS_quotas` FOREIGN KEY (`purchase_monthly_payments_entity_id`) REFERENCES `PURCHASE_MONTHLY_PAYMENTS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `PURCHASE_SINGLE_PAYMENTS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `payment_voucher` varchar(255) NOT NULL,
+    `store` varchar(255) NOT NULL,
+    `cuit_store` varchar(20) NOT NULL,
+    `amount` double NOT NULL,
+    `final_amount` double NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `card_id` bigint unsigned NOT NULL,
+    `promotion_code` varchar(255),
+    `store_discount` double NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_PURCHASE_SINGLE_PAYMENTS_card_id` (`card_id`),
+    CONSTRAINT `fk_CARDS_purchase_single_payments` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `DISCOUNTS` (
+    `code` varchar(255),
+    `promotion_title` varchar(255),
+    `name_store` varchar(255),
+    `cuit_store` varchar(255),
+    `validity_start_date` datetime(3) NOT NULL,
+    `validity_end_date` datetime(3) NOT NULL,
+    `comments` varchar(255),
+    `bank_id` bigint unsigned,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `is_deleted` boolean NOT NULL DEFAULT false,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `discount_percentage` double NOT NULL,
+    `price_cap` double NOT NULL DEFAULT 0,
+    `only_cash` boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (`id`),
+    INDEX `idx_DISCOUNTS_bank_id` (`bank_id`),
+    CONSTRAINT `fk_DISCOUNTS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `uni_DISCOUNTS_code` UNIQUE (`code`)
+);
+
+CREATE TABLE IF NOT EXISTS `FINANCINGS` (
+    `code` varchar(255),
+    `promotion_title` varchar(255),
+    `name_store` varchar(255),
+    `cuit_store` varchar(255),
+    `validity_start_date` datetime(3) NOT NULL,
+    `validity_end_date` datetime(3) NOT NULL,
+    `comments` varchar(255),
+    `bank_id` bigint unsigned,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `is_deleted` boolean NOT NULL DEFAULT false,
+    `id` bigint unsigned AUTO_INCREMENT,
+    `number_of_quotas` bigint NOT NULL,
+    `interest` double NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_FINANCINGS_bank_id` (`bank_id`),
+    CONSTRAINT `fk_FINANCINGS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `uni_FINANCINGS_code` UNIQUE (`code`)
+);
+
+CREATE TABLE IF NOT EXISTS `PAYMENT_SUMMARIES` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `code` varchar(255) NOT NULL,
+    `month` bigint NOT NULL,
+    `year` bigint NOT NULL,
+    `first_expiration` datetime(3) NOT NULL,
+    `second_expiration` datetime(3) NOT NULL,
+    `surcharge_percentage` double NOT NULL,
+    `total_price` double NOT NULL,
+    `card_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_PAYMENT_SUMMARIES_card` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_quotas` (
+    `payment_summary_entity_sql_id` bigint unsigned,
+    `quota_entity_sql_id` bigint unsigned,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `quota_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_quotas_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_quotas_quota_entity_sql` FOREIGN KEY (`quota_entity_sql_id`) REFERENCES `QUOTAS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_monthly_payments` (
+    `payment_summary_entity_sql_id` bigint unsigned,
+    `purchase_monthly_payments_entity_sql_id` bigint unsigned,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `purchase_monthly_payments_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_monthly_payments_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_monthly_payments_purchase_monthly_payaeb7e574` FOREIGN KEY (`purchase_monthly_payments_entity_sql_id`) REFERENCES `PURCHASE_MONTHLY_PAYMENTS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_single_payments` (
+    `payment_summary_entity_sql_id` bigint unsigned,
+    `purchase_single_payment_entity_sql_id` bigint unsigned,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `purchase_single_payment_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_single_payments_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_single_payments_purchase_single_paymed8fafafc` FOREIGN KEY (`purchase_single_payment_entity_sql_id`) REFERENCES `PURCHASE_SINGLE_PAYMENTS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `BILLING_CYCLES` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `bank_id` bigint unsigned NOT NULL,
+    `closing_day` bigint NOT NULL,
+    `first_due_days` bigint NOT NULL,
+    `second_due_days` bigint NOT NULL,
+    `surcharge_percentage` double NOT NULL DEFAULT 0,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_BILLING_CYCLES_bank_id` (`bank_id`),
+    CONSTRAINT `fk_BILLING_CYCLES_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
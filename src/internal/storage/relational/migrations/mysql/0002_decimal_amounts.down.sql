@@ -0,0 +1,28 @@
+-- Reverts amounts and percentages to floating point numbers.
+
+ALTER TABLE `PURCHASE_MONTHLY_PAYMENTS`
+    MODIFY `amount` double NOT NULL,
+    MODIFY `final_amount` double NOT NULL,
+    MODIFY `interest` double NOT NULL;
+
+ALTER TABLE `QUOTAS`
+    MODIFY `price` double NOT NULL;
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    MODIFY `amount` double NOT NULL,
+    MODIFY `final_amount` double NOT NULL,
+    MODIFY `store_discount` double NOT NULL;
+
+ALTER TABLE `DISCOUNTS`
+    MODIFY `discount_percentage` double NOT NULL,
+    MODIFY `price_cap` double NOT NULL DEFAULT 0;
+
+ALTER TABLE `FINANCINGS`
+    MODIFY `interest` double NOT NULL DEFAULT 0;
+
+ALTER TABLE `PAYMENT_SUMMARIES`
+    MODIFY `surcharge_percentage` double NOT NULL,
+    MODIFY `total_price` double NOT NULL;
+
+ALTER TABLE `BILLING_CYCLES`
+    MODIFY `surcharge_percentage` double NOT NULL DEFAULT 0;
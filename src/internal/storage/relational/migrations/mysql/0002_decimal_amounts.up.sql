@@ -0,0 +1,29 @@
+-- Stores amounts and percentages as exact decimals with two decimal places instead of floating point
+-- numbers. Existing values are rounded to the cent.
+
+ALTER TABLE `PURCHASE_MONTHLY_PAYMENTS`
+    MODIFY `amount` decimal(15,2) NOT NULL,
+    MODIFY `final_amount` decimal(15,2) NOT NULL,
+    MODIFY `interest` decimal(7,2) NOT NULL;
+
+ALTER TABLE `QUOTAS`
+    MODIFY `price` decimal(15,2) NOT NULL;
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    MODIFY `amount` decimal(15,2) NOT NULL,
+    MODIFY `final_amount` decimal(15,2) NOT NULL,
+    MODIFY `store_discount` decimal(7,2) NOT NULL;
+
+ALTER TABLE `DISCOUNTS`
+    MODIFY `discount_percentage` decimal(7,2) NOT NULL,
+    MODIFY `price_cap` decimal(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE `FINANCINGS`
+    MODIFY `interest` decimal(7,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE `PAYMENT_SUMMARIES`
+    MODIFY `surcharge_percentage` decimal(7,2) NOT NULL,
+    MODIFY `total_price` decimal(15,2) NOT NULL;
+
+ALTER TABLE `BILLING_CYCLES`
+    MODIFY `surcharge_percentage` decimal(7,2) NOT NULL DEFAULT 0;
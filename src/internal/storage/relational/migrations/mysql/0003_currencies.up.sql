@@ -0,0 +1,31 @@
+-- Records the currency of purchases, pesos for the existing ones, the daily exchange rates of foreign
+-- currencies and the amount billed in each currency in a payment summary.
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    ADD `currency` varchar(3) NOT NULL DEFAULT 'ARS';
+
+ALTER TABLE `PURCHASE_MONTHLY_PAYMENTS`
+    ADD `currency` varchar(3) NOT NULL DEFAULT 'ARS';
+
+CREATE TABLE `EXCHANGE_RATES` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `currency` varchar(3) NOT NULL,
+    `date` datetime(3) NOT NULL,
+    `rate` decimal(15,2) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_EXCHANGE_RATES_currency_date` (`currency`, `date`)
+);
+
+CREATE TABLE `PAYMENT_SUMMARY_SUBTOTALS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `payment_summary_id` bigint unsigned NOT NULL,
+    `currency` varchar(3) NOT NULL,
+    `subtotal` decimal(15,2) NOT NULL,
+    `exchange_rate` decimal(15,2) NOT NULL,
+    `total` decimal(15,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_PAYMENT_SUMMARY_SUBTOTALS_payment_summary_id` (`payment_summary_id`),
+    CONSTRAINT `fk_PAYMENT_SUMMARIES_subtotals` FOREIGN KEY (`payment_summary_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,7 @@
+-- Drops the interest model of installment purchases and financing promotions.
+
+ALTER TABLE `PURCHASE_MONTHLY_PAYMENTS`
+    DROP COLUMN `interest_model`;
+
+ALTER TABLE `FINANCINGS`
+    DROP COLUMN `interest_model`;
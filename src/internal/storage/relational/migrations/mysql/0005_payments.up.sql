@@ -0,0 +1,40 @@
+-- Records the payments of payment summaries, their allocations to the billed quotas and single-payment
+-- purchases, and the amount paid of each of them, nothing for the existing ones.
+
+ALTER TABLE `QUOTAS`
+    ADD `paid_amount` decimal(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    ADD `paid_amount` decimal(15,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE `PAYMENTS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `code` varchar(255) NOT NULL,
+    `card_id` bigint unsigned NOT NULL,
+    `payment_summary_id` bigint unsigned NOT NULL,
+    `amount` decimal(15,2) NOT NULL,
+    `payment_date` datetime(3) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_PAYMENTS_code` (`code`),
+    INDEX `idx_PAYMENTS_card_id` (`card_id`),
+    INDEX `idx_PAYMENTS_payment_summary_id` (`payment_summary_id`),
+    CONSTRAINT `fk_PAYMENTS_card` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENTS_payment_summary` FOREIGN KEY (`payment_summary_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE `PAYMENT_ALLOCATIONS` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `payment_id` bigint unsigned NOT NULL,
+    `quota_id` bigint unsigned NULL,
+    `purchase_single_payment_id` bigint unsigned NULL,
+    `amount` decimal(15,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_PAYMENT_ALLOCATIONS_payment_id` (`payment_id`),
+    INDEX `idx_PAYMENT_ALLOCATIONS_quota_id` (`quota_id`),
+    INDEX `idx_PAYMENT_ALLOCATIONS_purchase_single_payment_id` (`purchase_single_payment_id`),
+    CONSTRAINT `fk_PAYMENTS_allocations` FOREIGN KEY (`payment_id`) REFERENCES `PAYMENTS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENT_ALLOCATIONS_quota` FOREIGN KEY (`quota_id`) REFERENCES `QUOTAS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENT_ALLOCATIONS_purchase_single_payment` FOREIGN KEY (`purchase_single_payment_id`) REFERENCES `PURCHASE_SINGLE_PAYMENTS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,4 @@
+-- Drops the uniqueness of payment vouchers among the purchases of a card.
+
+DROP INDEX `idx_PURCHASE_MONTHLY_PAYMENTS_card_payment_voucher` ON `PURCHASE_MONTHLY_PAYMENTS`;
+DROP INDEX `idx_PURCHASE_SINGLE_PAYMENTS_card_payment_voucher` ON `PURCHASE_SINGLE_PAYMENTS`;
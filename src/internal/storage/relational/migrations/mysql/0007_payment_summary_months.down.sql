@@ -0,0 +1,3 @@
+-- Drops the uniqueness of the payment summaries per card and month.
+
+DROP INDEX `idx_PAYMENT_SUMMARIES_card_month_year` ON `PAYMENT_SUMMARIES`;
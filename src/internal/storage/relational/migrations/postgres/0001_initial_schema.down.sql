@@ -0,0 +1,16 @@
+-- Drops the whole schema, dependent tables first.
+
+DROP TABLE IF EXISTS "BILLING_CYCLES";
+DROP TABLE IF EXISTS "payment_summary_single_payments";
+DROP TABLE IF EXISTS "payment_summary_monthly_payments";
+DROP TABLE IF EXISTS "payment_summary_quotas";
+DROP TABLE IF EXISTS "PAYMENT_SUMMARIES";
+DROP TABLE IF EXISTS "FINANCINGS";
+DROP TABLE IF EXISTS "DISCOUNTS";
+DROP TABLE IF EXISTS "PURCHASE_SINGLE_PAYMENTS";
+DROP TABLE IF EXISTS "QUOTAS";
+DROP TABLE IF EXISTS "PURCHASE_MONTHLY_PAYMENTS";
+DROP TABLE IF EXISTS "CARDS";
+DROP TABLE IF EXISTS "customers_banks";
+DROP TABLE IF EXISTS "CUSTOMERS";
+DROP TABLE IF EXISTS "BANKS";
@@ -0,0 +1,28 @@
+-- Reverts amounts and percentages to decimals of unbounded precision.
+
+ALTER TABLE "PURCHASE_MONTHLY_PAYMENTS"
+    ALTER COLUMN "amount" TYPE decimal,
+    ALTER COLUMN "final_amount" TYPE decimal,
+    ALTER COLUMN "interest" TYPE decimal;
+
+ALTER TABLE "QUOTAS"
+    ALTER COLUMN "price" TYPE decimal;
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    ALTER COLUMN "amount" TYPE decimal,
+    ALTER COLUMN "final_amount" TYPE decimal,
+    ALTER COLUMN "store_discount" TYPE decimal;
+
+ALTER TABLE "DISCOUNTS"
+    ALTER COLUMN "discount_percentage" TYPE decimal,
+    ALTER COLUMN "price_cap" TYPE decimal;
+
+ALTER TABLE "FINANCINGS"
+    ALTER COLUMN "interest" TYPE decimal;
+
+ALTER TABLE "PAYMENT_SUMMARIES"
+    ALTER COLUMN "surcharge_percentage" TYPE decimal,
+    ALTER COLUMN "total_price" TYPE decimal;
+
+ALTER TABLE "BILLING_CYCLES"
+    ALTER COLUMN "surcharge_percentage" TYPE decimal;
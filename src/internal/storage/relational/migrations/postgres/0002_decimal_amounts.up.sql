@@ -0,0 +1,29 @@
+-- Stores amounts and percentages as exact decimals with two decimal places instead of floating point
+-- numbers. Existing values are rounded to the cent.
+
+ALTER TABLE "PURCHASE_MONTHLY_PAYMENTS"
+    ALTER COLUMN "amount" TYPE decimal(15,2),
+    ALTER COLUMN "final_amount" TYPE decimal(15,2),
+    ALTER COLUMN "interest" TYPE decimal(7,2);
+
+ALTER TABLE "QUOTAS"
+    ALTER COLUMN "price" TYPE decimal(15,2);
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    ALTER COLUMN "amount" TYPE decimal(15,2),
+    ALTER COLUMN "final_amount" TYPE decimal(15,2),
+    ALTER COLUMN "store_discount" TYPE decimal(7,2);
+
+ALTER TABLE "DISCOUNTS"
+    ALTER COLUMN "discount_percentage" TYPE decimal(7,2),
+    ALTER COLUMN "price_cap" TYPE decimal(15,2);
+
+ALTER TABLE "FINANCINGS"
+    ALTER COLUMN "interest" TYPE decimal(7,2);
+
+ALTER TABLE "PAYMENT_SUMMARIES"
+    ALTER COLUMN "surcharge_percentage" TYPE decimal(7,2),
+    ALTER COLUMN "total_price" TYPE decimal(15,2);
+
+ALTER TABLE "BILLING_CYCLES"
+    ALTER COLUMN "surcharge_percentage" TYPE decimal(7,2);
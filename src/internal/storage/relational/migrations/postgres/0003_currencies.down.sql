@@ -0,0 +1,10 @@
+-- Drops the exchange rates and the subtotals of the payment summaries, and the currency of purchases.
+
+DROP TABLE IF EXISTS "PAYMENT_SUMMARY_SUBTOTALS";
+DROP TABLE IF EXISTS "EXCHANGE_RATES";
+
+ALTER TABLE "PURCHASE_MONTHLY_PAYMENTS"
+    DROP COLUMN "currency";
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    DROP COLUMN "currency";
@@ -0,0 +1,31 @@
+-- Records the currency of purchases, pesos for the existing ones, the daily exchange rates of foreign
+-- currencies and the amount billed in each currency in a payment summary.
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    ADD "currency" varchar(3) NOT NULL DEFAULT 'ARS';
+
+ALTER TABLE "PURCHASE_MONTHLY_PAYMENTS"
+    ADD "currency" varchar(3) NOT NULL DEFAULT 'ARS';
+
+CREATE TABLE "EXCHANGE_RATES" (
+    "id" bigserial,
+    "currency" varchar(3) NOT NULL,
+    "date" timestamptz NOT NULL,
+    "rate" decimal(15,2) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_EXCHANGE_RATES_currency_date" ON "EXCHANGE_RATES" ("currency", "date");
+
+CREATE TABLE "PAYMENT_SUMMARY_SUBTOTALS" (
+    "id" bigserial,
+    "payment_summary_id" bigint NOT NULL,
+    "currency" varchar(3) NOT NULL,
+    "subtotal" decimal(15,2) NOT NULL,
+    "exchange_rate" decimal(15,2) NOT NULL,
+    "total" decimal(15,2) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_PAYMENT_SUMMARIES_subtotals" FOREIGN KEY ("payment_summary_id") REFERENCES "PAYMENT_SUMMARIES" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_PAYMENT_SUMMARY_SUBTOTALS_payment_summary_id" ON "PAYMENT_SUMMARY_SUBTOTALS" ("payment_summary_id");
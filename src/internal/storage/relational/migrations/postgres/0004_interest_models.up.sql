@@ -0,0 +1,7 @@
+-- Records the interest model of financing promotions and installment purchases, flat for the existing ones.
+
+ALTER TABLE "FINANCINGS"
+    ADD "interest_model" varchar(10) NOT NULL DEFAULT 'flat';
+
+ALTER TABLE "PURCHASE_MONTHLY_PAYMENTS"
+    ADD "interest_model" varchar(10) NOT NULL DEFAULT 'flat';
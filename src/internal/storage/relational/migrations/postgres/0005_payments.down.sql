@@ -0,0 +1,10 @@
+-- Drops the payments and their allocations, and the amount paid of quotas and single-payment purchases.
+
+DROP TABLE IF EXISTS "PAYMENT_ALLOCATIONS";
+DROP TABLE IF EXISTS "PAYMENTS";
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    DROP COLUMN "paid_amount";
+
+ALTER TABLE "QUOTAS"
+    DROP COLUMN "paid_amount";
@@ -0,0 +1,40 @@
+-- Records the payments of payment summaries, their allocations to the billed quotas and single-payment
+-- purchases, and the amount paid of each of them, nothing for the existing ones.
+
+ALTER TABLE "QUOTAS"
+    ADD "paid_amount" decimal(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE "PURCHASE_SINGLE_PAYMENTS"
+    ADD "paid_amount" decimal(15,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE "PAYMENTS" (
+    "id" bigserial,
+    "code" varchar(255) NOT NULL,
+    "card_id" bigint NOT NULL,
+    "payment_summary_id" bigint NOT NULL,
+    "amount" decimal(15,2) NOT NULL,
+    "payment_date" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_PAYMENTS_card" FOREIGN KEY ("card_id") REFERENCES "CARDS" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_PAYMENTS_payment_summary" FOREIGN KEY ("payment_summary_id") REFERENCES "PAYMENT_SUMMARIES" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX "idx_PAYMENTS_code" ON "PAYMENTS" ("code");
+CREATE INDEX "idx_PAYMENTS_card_id" ON "PAYMENTS" ("card_id");
+CREATE INDEX "idx_PAYMENTS_payment_summary_id" ON "PAYMENTS" ("payment_summary_id");
+
+CREATE TABLE "PAYMENT_ALLOCATIONS" (
+    "id" bigserial,
+    "payment_id" bigint NOT NULL,
+    "quota_id" bigint,
+    "purchase_single_payment_id" bigint,
+    "amount" decimal(15,2) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_PAYMENTS_allocations" FOREIGN KEY ("payment_id") REFERENCES "PAYMENTS" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_PAYMENT_ALLOCATIONS_quota" FOREIGN KEY ("quota_id") REFERENCES "QUOTAS" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_PAYMENT_ALLOCATIONS_purchase_single_payment" FOREIGN KEY ("purchase_single_payment_id") REFERENCES "PURCHASE_SINGLE_PAYMENTS" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_PAYMENT_ALLOCATIONS_payment_id" ON "PAYMENT_ALLOCATIONS" ("payment_id");
+CREATE INDEX "idx_PAYMENT_ALLOCATIONS_quota_id" ON "PAYMENT_ALLOCATIONS" ("quota_id");
+CREATE INDEX "idx_PAYMENT_ALLOCATIONS_purchase_single_payment_id" ON "PAYMENT_ALLOCATIONS" ("purchase_single_payment_id");
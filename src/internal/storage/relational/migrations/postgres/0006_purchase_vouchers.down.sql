@@ -0,0 +1,4 @@
+-- Drops the uniqueness of payment vouchers among the purchases of a card.
+
+DROP INDEX IF EXISTS "idx_PURCHASE_MONTHLY_PAYMENTS_card_payment_voucher";
+DROP INDEX IF EXISTS "idx_PURCHASE_SINGLE_PAYMENTS_card_payment_voucher";
@@ -0,0 +1,192 @@
+-- Schema created by AutoMigrate before versioned migrations were introduced. Every table is created
+-- only if missing, so databases created by AutoMigrate are adopted as version 1.
+
+CREATE TABLE IF NOT EXISTS `BANKS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text,
+    `cuit` text NOT NULL,
+    `address` text,
+    `telephone` text,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_BANKS_cuit` ON `BANKS` (`cuit`);
+
+CREATE TABLE IF NOT EXISTS `CUSTOMERS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `complete_name` text NOT NULL,
+    `dni` text NOT NULL,
+    `cuit` text NOT NULL,
+    `address` text,
+    `telephone` text,
+    `entry_date` datetime NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `uni_CUSTOMERS_dni` UNIQUE (`dni`),
+    CONSTRAINT `uni_CUSTOMERS_cuit` UNIQUE (`cuit`)
+);
+
+CREATE TABLE IF NOT EXISTS `customers_banks` (
+    `customer_entity_sql_id` integer,
+    `bank_entity_sql_id` integer,
+    PRIMARY KEY (`customer_entity_sql_id`, `bank_entity_sql_id`),
+    CONSTRAINT `fk_customers_banks_customer_entity_sql` FOREIGN KEY (`customer_entity_sql_id`) REFERENCES `CUSTOMERS` (`id`),
+    CONSTRAINT `fk_customers_banks_bank_entity_sql` FOREIGN KEY (`bank_entity_sql_id`) REFERENCES `BANKS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `CARDS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `number` text NOT NULL,
+    `ccv` text NOT NULL,
+    `cardholder_name_in_card` text NOT NULL,
+    `since` datetime NOT NULL,
+    `expiration_date` datetime NOT NULL,
+    `status` text NOT NULL DEFAULT 'active',
+    `bank_id` integer,
+    `customer_id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_CARDS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `fk_CUSTOMERS_cards` FOREIGN KEY (`customer_id`) REFERENCES `CUSTOMERS` (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_CARDS_bank_id` ON `CARDS` (`bank_id`);
+CREATE INDEX IF NOT EXISTS `idx_CARDS_customer_id` ON `CARDS` (`customer_id`);
+
+CREATE TABLE IF NOT EXISTS `PURCHASE_MONTHLY_PAYMENTS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `payment_voucher` text NOT NULL,
+    `store` text NOT NULL,
+    `cuit_store` text NOT NULL,
+    `amount` real NOT NULL,
+    `final_amount` real NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `card_id` integer NOT NULL,
+    `promotion_code` text,
+    `interest` real NOT NULL,
+    `number_of_quotas` integer NOT NULL,
+    CONSTRAINT `fk_CARDS_purchase_monthly_payments` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_PURCHASE_MONTHLY_PAYMENTS_card_id` ON `PURCHASE_MONTHLY_PAYMENTS` (`card_id`);
+
+CREATE TABLE IF NOT EXISTS `QUOTAS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `number` integer NOT NULL,
+    `price` real NOT NULL,
+    `month` text NOT NULL,
+    `year` text NOT NULL,
+    `purchase_monthly_payments_entity_id` integer NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_PURCHASE_MONTHLY_PAYMENTS_quotas` FOREIGN KEY (`purchase_monthly_payments_entity_id`) REFERENCES `PURCHASE_MONTHLY_PAYMENTS` (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_QUOTAS_purchase_monthly_payments_entity_id` ON `QUOTAS` (`purchase_monthly_payments_entity_id`);
+
+CREATE TABLE IF NOT EXISTS `PURCHASE_SINGLE_PAYMENTS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `payment_voucher` text NOT NULL,
+    `store` text NOT NULL,
+    `cuit_store` text NOT NULL,
+    `amount` real NOT NULL,
+    `final_amount` real NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `card_id` integer NOT NULL,
+    `promotion_code` text,
+    `store_discount` real NOT NULL,
+    CONSTRAINT `fk_CARDS_purchase_single_payments` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_PURCHASE_SINGLE_PAYMENTS_card_id` ON `PURCHASE_SINGLE_PAYMENTS` (`card_id`);
+
+CREATE TABLE IF NOT EXISTS `DISCOUNTS` (
+    `code` text,
+    `promotion_title` text,
+    `name_store` text,
+    `cuit_store` text,
+    `validity_start_date` datetime NOT NULL,
+    `validity_end_date` datetime NOT NULL,
+    `comments` text,
+    `bank_id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `is_deleted` numeric NOT NULL DEFAULT false,
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `discount_percentage` real NOT NULL,
+    `price_cap` real NOT NULL DEFAULT 0,
+    `only_cash` numeric NOT NULL DEFAULT false,
+    CONSTRAINT `fk_DISCOUNTS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `uni_DISCOUNTS_code` UNIQUE (`code`)
+);
+CREATE INDEX IF NOT EXISTS `idx_DISCOUNTS_bank_id` ON `DISCOUNTS` (`bank_id`);
+
+CREATE TABLE IF NOT EXISTS `FINANCINGS` (
+    `code` text,
+    `promotion_title` text,
+    `name_store` text,
+    `cuit_store` text,
+    `validity_start_date` datetime NOT NULL,
+    `validity_end_date` datetime NOT NULL,
+    `comments` text,
+    `bank_id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `is_deleted` numeric NOT NULL DEFAULT false,
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `number_of_quotas` integer NOT NULL,
+    `interest` real NOT NULL DEFAULT 0,
+    CONSTRAINT `fk_FINANCINGS_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT `uni_FINANCINGS_code` UNIQUE (`code`)
+);
+CREATE INDEX IF NOT EXISTS `idx_FINANCINGS_bank_id` ON `FINANCINGS` (`bank_id`);
+
+CREATE TABLE IF NOT EXISTS `PAYMENT_SUMMARIES` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `code` text NOT NULL,
+    `month` integer NOT NULL,
+    `year` integer NOT NULL,
+    `first_expiration` datetime NOT NULL,
+    `second_expiration` datetime NOT NULL,
+    `surcharge_percentage` real NOT NULL,
+    `total_price` real NOT NULL,
+    `card_id` integer NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_PAYMENT_SUMMARIES_card` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_quotas` (
+    `payment_summary_entity_sql_id` integer,
+    `quota_entity_sql_id` integer,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `quota_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_quotas_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_quotas_quota_entity_sql` FOREIGN KEY (`quota_entity_sql_id`) REFERENCES `QUOTAS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_monthly_payments` (
+    `payment_summary_entity_sql_id` integer,
+    `purchase_monthly_payments_entity_sql_id` integer,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `purchase_monthly_payments_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_monthly_payments_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_monthly_payments_purchase_monthly_payaeb7e574` FOREIGN KEY (`purchase_monthly_payments_entity_sql_id`) REFERENCES `PURCHASE_MONTHLY_PAYMENTS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `payment_summary_single_payments` (
+    `payment_summary_entity_sql_id` integer,
+    `purchase_single_payment_entity_sql_id` integer,
+    PRIMARY KEY (`payment_summary_entity_sql_id`, `purchase_single_payment_entity_sql_id`),
+    CONSTRAINT `fk_payment_summary_single_payments_payment_summary_entity_sql` FOREIGN KEY (`payment_summary_entity_sql_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`),
+    CONSTRAINT `fk_payment_summary_single_payments_purchase_single_paymed8fafafc` FOREIGN KEY (`purchase_single_payment_entity_sql_id`) REFERENCES `PURCHASE_SINGLE_PAYMENTS` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `BILLING_CYCLES` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `bank_id` integer NOT NULL,
+    `closing_day` integer NOT NULL,
+    `first_due_days` integer NOT NULL,
+    `second_due_days` integer NOT NULL,
+    `surcharge_percentage` real NOT NULL DEFAULT 0,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_BILLING_CYCLES_bank` FOREIGN KEY (`bank_id`) REFERENCES `BANKS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_BILLING_CYCLES_bank_id` ON `BILLING_CYCLES` (`bank_id`);
@@ -0,0 +1,2 @@
+-- SQLite ignores the precision of decimal columns and keeps storing amounts and percentages as real
+-- numbers, which the money types round to the cent when reading them. Nothing to change.
@@ -0,0 +1,29 @@
+-- Records the currency of purchases, pesos for the existing ones, the daily exchange rates of foreign
+-- currencies and the amount billed in each currency in a payment summary.
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    ADD `currency` text NOT NULL DEFAULT 'ARS';
+
+ALTER TABLE `PURCHASE_MONTHLY_PAYMENTS`
+    ADD `currency` text NOT NULL DEFAULT 'ARS';
+
+CREATE TABLE `EXCHANGE_RATES` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `currency` text NOT NULL,
+    `date` datetime NOT NULL,
+    `rate` real NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_EXCHANGE_RATES_currency_date` ON `EXCHANGE_RATES` (`currency`, `date`);
+
+CREATE TABLE `PAYMENT_SUMMARY_SUBTOTALS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `payment_summary_id` integer NOT NULL,
+    `currency` text NOT NULL,
+    `subtotal` real NOT NULL,
+    `exchange_rate` real NOT NULL,
+    `total` real NOT NULL,
+    CONSTRAINT `fk_PAYMENT_SUMMARIES_subtotals` FOREIGN KEY (`payment_summary_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX `idx_PAYMENT_SUMMARY_SUBTOTALS_payment_summary_id` ON `PAYMENT_SUMMARY_SUBTOTALS` (`payment_summary_id`);
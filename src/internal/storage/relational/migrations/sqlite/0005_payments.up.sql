@@ -0,0 +1,38 @@
+-- Records the payments of payment summaries, their allocations to the billed quotas and single-payment
+-- purchases, and the amount paid of each of them, nothing for the existing ones.
+
+ALTER TABLE `QUOTAS`
+    ADD `paid_amount` real NOT NULL DEFAULT 0;
+
+ALTER TABLE `PURCHASE_SINGLE_PAYMENTS`
+    ADD `paid_amount` real NOT NULL DEFAULT 0;
+
+CREATE TABLE `PAYMENTS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `code` text NOT NULL,
+    `card_id` integer NOT NULL,
+    `payment_summary_id` integer NOT NULL,
+    `amount` real NOT NULL,
+    `payment_date` datetime NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_PAYMENTS_card` FOREIGN KEY (`card_id`) REFERENCES `CARDS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENTS_payment_summary` FOREIGN KEY (`payment_summary_id`) REFERENCES `PAYMENT_SUMMARIES` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX `idx_PAYMENTS_code` ON `PAYMENTS` (`code`);
+CREATE INDEX `idx_PAYMENTS_card_id` ON `PAYMENTS` (`card_id`);
+CREATE INDEX `idx_PAYMENTS_payment_summary_id` ON `PAYMENTS` (`payment_summary_id`);
+
+CREATE TABLE `PAYMENT_ALLOCATIONS` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `payment_id` integer NOT NULL,
+    `quota_id` integer,
+    `purchase_single_payment_id` integer,
+    `amount` real NOT NULL,
+    CONSTRAINT `fk_PAYMENTS_allocations` FOREIGN KEY (`payment_id`) REFERENCES `PAYMENTS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENT_ALLOCATIONS_quota` FOREIGN KEY (`quota_id`) REFERENCES `QUOTAS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_PAYMENT_ALLOCATIONS_purchase_single_payment` FOREIGN KEY (`purchase_single_payment_id`) REFERENCES `PURCHASE_SINGLE_PAYMENTS` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX `idx_PAYMENT_ALLOCATIONS_payment_id` ON `PAYMENT_ALLOCATIONS` (`payment_id`);
+CREATE INDEX `idx_PAYMENT_ALLOCATIONS_quota_id` ON `PAYMENT_ALLOCATIONS` (`quota_id`);
+CREATE INDEX `idx_PAYMENT_ALLOCATIONS_purchase_single_payment_id` ON `PAYMENT_ALLOCATIONS` (`purchase_single_payment_id`);
@@ -0,0 +1,5 @@
+-- Makes payment vouchers unique among the purchases of a card in each purchase table. Cards with a repeated
+-- voucher must have it renamed before migrating.
+
+CREATE UNIQUE INDEX `idx_PURCHASE_SINGLE_PAYMENTS_card_payment_voucher` ON `PURCHASE_SINGLE_PAYMENTS` (`card_id`, `payment_voucher`);
+CREATE UNIQUE INDEX `idx_PURCHASE_MONTHLY_PAYMENTS_card_payment_voucher` ON `PURCHASE_MONTHLY_PAYMENTS` (`card_id`, `payment_voucher`);
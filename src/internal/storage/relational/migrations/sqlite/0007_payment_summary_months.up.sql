@@ -0,0 +1,4 @@
+-- Makes the payment summaries unique per card and month. Cards with two summaries for the same month must have
+-- one of them removed before migrating.
+
+CREATE UNIQUE INDEX `idx_PAYMENT_SUMMARIES_card_month_year` ON `PAYMENT_SUMMARIES` (`card_id`, `month`, `year`);
@@ -1,6 +1,8 @@
 package relational_repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BankRepositoryGORM struct {
@@ -20,24 +23,131 @@ func NewBankRelationalRepository(db *gorm.DB) storage.IBankStorage {
 	return &BankRepositoryGORM{db: db}
 }
 
-// Implementación de la interfaz BankRepository
-func (r *BankRepositoryGORM) AddFinancingPromotionToBank(promotionFinancing models.Financing) error {
-	var bankEntity entities.BankEntitySQL
+// CreateBank registers a new bank. The CUIT is unique among banks.
+func (r *BankRepositoryGORM) CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error) {
+	db := r.db.WithContext(ctx)
 
-	cuit := strings.TrimSpace(promotionFinancing.Bank.Cuit)
-	logger.Info("Searching for bank with 'cuit' %s", cuit)
-	if err := r.db.First(&bankEntity, "cuit = ?", cuit).Error; err != nil {
-		return fmt.Errorf("could not find bank with 'cuit' %s: %v", promotionFinancing.Bank.Cuit, err)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.BankEntitySQL{}).Where("cuit = ?", bank.Cuit).Count(&count).Error; err != nil {
+			return fmt.Errorf("error checking existing banks: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("a bank with cuit %s already exists: %w", bank.Cuit, storage.ErrAlreadyExists)
+		}
+
+		if err := tx.Create(entities.ToBankEntity(&bank)).Error; err != nil {
+			return fmt.Errorf("error inserting bank: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Bank %s registered", bank.Cuit)
+	return r.GetBankByCuit(ctx, bank.Cuit)
+}
+
+// GetBanks retrieves all banks ordered by CUIT.
+func (r *BankRepositoryGORM) GetBanks(ctx context.Context) (*[]models.Bank, error) {
+	db := r.db.WithContext(ctx)
+
+	var bankEntities []entities.BankEntitySQL
+	if err := db.Order("cuit").Find(&bankEntities).Error; err != nil {
+		return nil, fmt.Errorf("error retrieving banks: %v", err)
+	}
+
+	banks := []models.Bank{}
+	for _, bank := range bankEntities {
+		banks = append(banks, *entities.ToBank(&bank))
+	}
+	return &banks, nil
+}
+
+// GetBankByCuit retrieves a bank by its CUIT.
+func (r *BankRepositoryGORM) GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error) {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, cuit)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ToBank(bank), nil
+}
+
+// UpdateBank replaces the name, address and telephone of a bank.
+func (r *BankRepositoryGORM) UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error) {
+	db := r.db.WithContext(ctx)
+
+	existing, err := findBankByCuit(db, cuit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(existing).Updates(map[string]interface{}{
+		"name":      bank.Name,
+		"address":   bank.Address,
+		"telephone": bank.Telephone,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("error updating bank %s: %v", cuit, err)
+	}
+
+	logger.Info("Bank %s updated", cuit)
+	return r.GetBankByCuit(ctx, cuit)
+}
+
+// AddFinancingPromotionToBank adds a financing promotion to the bank identified by the promotion's bank CUIT.
+func (r *BankRepositoryGORM) AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error {
+	db := r.db.WithContext(ctx)
+
+	bankEntity, err := findBankByCuit(db, strings.TrimSpace(promotionFinancing.Bank.Cuit))
+	if err != nil {
+		return err
+	}
+
+	// The bank already exists, only the promotion row is written
+	if err := db.Omit("Bank").Create(entities.ToFinancingEntity(&promotionFinancing, bankEntity.ID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("a financing promotion with code %s already exists: %w", promotionFinancing.Code, storage.ErrAlreadyExists)
+		}
+		return fmt.Errorf("could not add financing promotion %s: %v", promotionFinancing.Code, err)
+	}
+
+	logger.Info("Financing promotion %s added to bank %s", promotionFinancing.Code, bankEntity.Cuit)
+	return nil
+}
+
+// AddDiscountPromotionToBank adds a discount promotion to the bank identified by the promotion's bank CUIT.
+func (r *BankRepositoryGORM) AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error {
+	db := r.db.WithContext(ctx)
+
+	bankEntity, err := findBankByCuit(db, strings.TrimSpace(promotionDiscount.Bank.Cuit))
+	if err != nil {
+		return err
+	}
+
+	// The bank already exists, only the promotion row is written
+	if err := db.Omit("Bank").Create(entities.ToDiscountEntity(&promotionDiscount, bankEntity.ID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("a discount promotion with code %s already exists: %w", promotionDiscount.Code, storage.ErrAlreadyExists)
+		}
+		return fmt.Errorf("could not add discount promotion %s: %v", promotionDiscount.Code, err)
 	}
 
-	// Lógica para agregar la promoción al banco
-	return r.db.Create(entities.ToFinancingEntity(&promotionFinancing, bankEntity.ID)).Error
+	logger.Info("Discount promotion %s added to bank %s", promotionDiscount.Code, bankEntity.Cuit)
+	return nil
 }
 
-func (r *BankRepositoryGORM) ExtendFinancingPromotionValidity(code string, newDate time.Time) error {
+func (r *BankRepositoryGORM) ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	db := r.db.WithContext(ctx)
+
 	// Find the promotion by ID
 	var promotion entities.FinancingEntitySQL
-	if err := r.db.First(&promotion, "code = ?", code).Error; err != nil {
+	if err := db.First(&promotion, "code = ?", code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+		}
 		return fmt.Errorf("could not find promotion with code %s: %v", code, err)
 	}
 
@@ -45,18 +155,23 @@ func (r *BankRepositoryGORM) ExtendFinancingPromotionValidity(code string, newDa
 	promotion.ValidityEndDate = newDate
 
 	// Save the changes to the database
-	if err := r.db.Save(&promotion).Error; err != nil {
+	if err := db.Save(&promotion).Error; err != nil {
 		return fmt.Errorf("could not update promotion dates: %v", err)
 	}
 
-	fmt.Printf("Promotion Code %s updated successfully\n", code)
+	logger.Info("Promotion %s validity extended to %v", code, newDate)
 	return nil
 }
 
-func (r *BankRepositoryGORM) ExtendDiscountPromotionValidity(code string, newDate time.Time) error {
+func (r *BankRepositoryGORM) ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error {
+	db := r.db.WithContext(ctx)
+
 	// Find the promotion by ID
 	var promotion entities.DiscountEntitySQL
-	if err := r.db.First(&promotion, "code = ?", code).Error; err != nil {
+	if err := db.First(&promotion, "code = ?", code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+		}
 		return fmt.Errorf("could not find promotion with code %s: %v", code, err)
 	}
 
@@ -64,22 +179,24 @@ func (r *BankRepositoryGORM) ExtendDiscountPromotionValidity(code string, newDat
 	promotion.ValidityEndDate = newDate
 
 	// Save the changes to the database
-	if err := r.db.Save(&promotion).Error; err != nil {
+	if err := db.Save(&promotion).Error; err != nil {
 		return fmt.Errorf("could not update promotion dates: %v", err)
 	}
 
-	fmt.Printf("Promotion Code %s updated successfully\n", code)
+	logger.Info("Promotion %s validity extended to %v", code, newDate)
 	return nil
 }
 
-func (r *BankRepositoryGORM) DeleteFinancingPromotion(code string) error {
+func (r *BankRepositoryGORM) DeleteFinancingPromotion(ctx context.Context, code string) error {
+	db := r.db.WithContext(ctx)
+
 	var discount entities.FinancingEntitySQL
 
 	// Search for the DiscountEntity by code
-	if err := r.db.Where("code = ?", code).First(&discount).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	if err := db.Where("code = ?", code).First(&discount).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Info("DiscountEntity with code %s not found.", code)
-			return err
+			return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
 		}
 		logger.Info("Error finding DiscountEntity with code %s: %v", code, err)
 		return err
@@ -89,7 +206,7 @@ func (r *BankRepositoryGORM) DeleteFinancingPromotion(code string) error {
 	discount.IsDeleted = true
 
 	// Save changes to the database
-	if err := r.db.Save(&discount).Error; err != nil {
+	if err := db.Save(&discount).Error; err != nil {
 		logger.Info("Error updating IsDeleted for DiscountEntity with code %s: %v", code, err)
 		return err
 	}
@@ -98,14 +215,16 @@ func (r *BankRepositoryGORM) DeleteFinancingPromotion(code string) error {
 	return nil
 }
 
-func (r *BankRepositoryGORM) DeleteDiscountPromotion(code string) error {
+func (r *BankRepositoryGORM) DeleteDiscountPromotion(ctx context.Context, code string) error {
+	db := r.db.WithContext(ctx)
+
 	var financing entities.DiscountEntitySQL
 
 	// Search for the FinancingEntity by code
-	if err := r.db.Where("code = ?", code).First(&financing).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	if err := db.Where("code = ?", code).First(&financing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Info("FinancingEntity with code %s not found.", code)
-			return err
+			return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
 		}
 		logger.Info("Error finding FinancingEntity with code %s: %v", code, err)
 		return err
@@ -115,7 +234,7 @@ func (r *BankRepositoryGORM) DeleteDiscountPromotion(code string) error {
 	financing.IsDeleted = true
 
 	// Save changes to the database
-	if err := r.db.Save(&financing).Error; err != nil {
+	if err := db.Save(&financing).Error; err != nil {
 		logger.Info("Error updating IsDeleted for FinancingEntity with code %s: %v", code, err)
 		return err
 	}
@@ -124,21 +243,86 @@ func (r *BankRepositoryGORM) DeleteDiscountPromotion(code string) error {
 	return nil
 }
 
-func (r *BankRepositoryGORM) GetBankCustomerCounts() ([]models.BankCustomerCountDTO, error) {
+func (r *BankRepositoryGORM) GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error) {
+	db := r.db.WithContext(ctx)
+
 	var results []models.BankCustomerCountDTO
 
-	r.db.Raw(`
-		SELECT 
+	// Table names are bound as clauses so that every dialect quotes them, PostgreSQL folds unquoted names to lower case.
+	// The join table is named by GORM from the many2many tag of the entities, which it writes in lower case.
+	if err := db.Raw(`
+		SELECT
 			b.cuit AS bank_cuit,
 			b.name AS bank_name,
 			COUNT(cb.customer_entity_sql_id) AS customer_count
-		FROM 
-			BANKS b
-		LEFT JOIN 
-			customers_banks cb ON b.id = cb.bank_entity_sql_id
-		GROUP BY 
-			b.id, b.name
-	`).Scan(&results)
+		FROM
+			? b
+		LEFT JOIN
+			? cb ON b.id = cb.bank_entity_sql_id
+		GROUP BY
+			b.id, b.cuit, b.name
+		ORDER BY
+			b.id
+	`, clause.Table{Name: entities.BankEntitySQL{}.TableName()}, clause.Table{Name: db.NamingStrategy.JoinTableName("CUSTOMERS_BANKS")}).Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("error counting the customers of each bank: %v", err)
+	}
 
 	return results, nil
 }
+
+// SaveBillingCycle creates or replaces the billing cycle configuration of a bank.
+func (r *BankRepositoryGORM) SaveBillingCycle(ctx context.Context, cycle models.BillingCycle) error {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, cycle.BankCuit)
+	if err != nil {
+		return err
+	}
+
+	cycleEntity := entities.ToBillingCycleEntity(&cycle, bank.ID)
+	if err := db.Where("bank_id = ?", bank.ID).
+		Assign(map[string]interface{}{
+			"closing_day":          cycleEntity.ClosingDay,
+			"first_due_days":       cycleEntity.FirstDueDays,
+			"second_due_days":      cycleEntity.SecondDueDays,
+			"surcharge_percentage": cycleEntity.SurchargePercentage,
+		}).
+		FirstOrCreate(cycleEntity).Error; err != nil {
+		return fmt.Errorf("error saving billing cycle for bank %s: %v", cycle.BankCuit, err)
+	}
+
+	logger.Info("Billing cycle of bank %s saved: closing day %d", cycle.BankCuit, cycle.ClosingDay)
+	return nil
+}
+
+// GetBillingCycle retrieves the billing cycle configuration of a bank, or nil if the bank has not configured one.
+func (r *BankRepositoryGORM) GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error) {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, bankCuit)
+	if err != nil {
+		return nil, err
+	}
+
+	var cycleEntity entities.BillingCycleEntitySQL
+	if err := db.Where("bank_id = ?", bank.ID).First(&cycleEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding billing cycle for bank %s: %v", bankCuit, err)
+	}
+
+	return entities.ToBillingCycle(&cycleEntity, bank.Cuit), nil
+}
+
+// findBankByCuit retrieves the bank entity with the given CUIT or a wrapped storage.ErrNotFound.
+func findBankByCuit(db *gorm.DB, cuit string) (*entities.BankEntitySQL, error) {
+	var bank entities.BankEntitySQL
+	if err := db.Where("cuit = ?", strings.TrimSpace(cuit)).First(&bank).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find bank with cuit %s: %w", cuit, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find bank with cuit %s: %v", cuit, err)
+	}
+	return &bank, nil
+}
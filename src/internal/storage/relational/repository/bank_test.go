@@ -1,12 +1,14 @@
 package relational_repository
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 	entities "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	mysql "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/testutils"
@@ -15,6 +17,7 @@ import (
 
 // Test the BankRepository with a real MySQL connection
 func TestAddFinancingPromotionToBank(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -53,14 +56,14 @@ func TestAddFinancingPromotionToBank(t *testing.T) {
 			Bank:              newBank,
 		},
 		NumberOfQuotas: 12,
-		Interest:       5.5, // Tasa de interés
+		Interest:       models.MustParsePercentage("5.5"), // Tasa de interés
 	}
 
 	// Create a new BankRepository instance
 	bankRepo := NewBankRelationalRepository(database)
 
 	// Add the bank to the repository
-	err = bankRepo.AddFinancingPromotionToBank(newFinancingPromotion)
+	err = bankRepo.AddFinancingPromotionToBank(ctx, newFinancingPromotion)
 
 	if err != nil {
 		fmt.Println("Failed to insert record:", result.Error)
@@ -78,7 +81,69 @@ func TestAddFinancingPromotionToBank(t *testing.T) {
 	assert.Equal(t, newFinancingPromotion.Interest, financingEntity.Interest)
 }
 
+func TestAddDiscountPromotionToBank(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	newDiscountPromotion := models.Discount{
+		Promotion: models.Promotion{
+			Code:              "DISC-2025",
+			PromotionTitle:    "Back to school",
+			NameStore:         "Tech Store",
+			CuitStore:         "30-98765432-1",
+			ValidityStartDate: time.Now().AddDate(0, -1, 0).Format(time.RFC3339),
+			ValidityEndDate:   time.Now().AddDate(0, 1, 0).Format(time.RFC3339),
+			Bank:              models.Bank{Cuit: "30-12345678-9"},
+		},
+		DiscountPercentage: models.MustParsePercentage("15"),
+		PriceCap:           models.MustParseMoney("5000"),
+		OnlyCash:           true,
+	}
+
+	bankRepo := NewBankRelationalRepository(database)
+
+	var banksBefore, banksAfter int64
+	database.Model(&entities.BankEntitySQL{}).Count(&banksBefore)
+
+	err = bankRepo.AddDiscountPromotionToBank(ctx, newDiscountPromotion)
+	assert.NoError(t, err)
+
+	database.Model(&entities.BankEntitySQL{}).Count(&banksAfter)
+
+	var discountEntity entities.DiscountEntitySQL
+	err = database.Preload("Bank").First(&discountEntity, "code = ?", "DISC-2025").Error
+	assert.NoError(t, err, "Error fetching promotion from database")
+
+	// The promotion references the existing bank, no bank row is created
+	assert.Equal(t, "Santander", discountEntity.Bank.Name)
+	assert.Equal(t, models.MustParsePercentage("15"), discountEntity.DiscountPercentage)
+	assert.Equal(t, models.MustParseMoney("5000"), discountEntity.PriceCap)
+	assert.True(t, discountEntity.OnlyCash)
+	assert.False(t, discountEntity.IsDeleted)
+	assert.Equal(t, banksBefore, banksAfter)
+
+	newDiscountPromotion.Code = "DISC-UNKNOWN-BANK"
+	newDiscountPromotion.Bank.Cuit = "30-99999999-9"
+	err = bankRepo.AddDiscountPromotionToBank(ctx, newDiscountPromotion)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
 func TestExtendPromotionValidity(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -101,7 +166,7 @@ func TestExtendPromotionValidity(t *testing.T) {
 
 	bankRepo := NewBankRelationalRepository(database)
 
-	bankRepo.ExtendFinancingPromotionValidity(testCode, testTime)
+	bankRepo.ExtendFinancingPromotionValidity(ctx, testCode, testTime)
 
 	var promotion entities.FinancingEntitySQL
 	if err := database.Where("code = ?", testCode).First(&promotion).Error; err != nil {
@@ -115,7 +180,7 @@ func TestExtendPromotionValidity(t *testing.T) {
 	testDiscountCode := "WINTERSALE2024"
 	testDiscountTime := time.Now().AddDate(0, 1, 3)
 
-	bankRepo.ExtendDiscountPromotionValidity(testDiscountCode, testDiscountTime)
+	bankRepo.ExtendDiscountPromotionValidity(ctx, testDiscountCode, testDiscountTime)
 
 	var promotionDiscount entities.DiscountEntitySQL
 	if err := database.Where("code = ?", testDiscountCode).First(&promotionDiscount).Error; err != nil {
@@ -127,6 +192,7 @@ func TestExtendPromotionValidity(t *testing.T) {
 }
 
 func TestDeleteFinancingPromotion(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -148,7 +214,7 @@ func TestDeleteFinancingPromotion(t *testing.T) {
 
 	bankRepo := NewBankRelationalRepository(database)
 
-	bankRepo.DeleteFinancingPromotion(testCode)
+	bankRepo.DeleteFinancingPromotion(ctx, testCode)
 
 	var promotion entities.FinancingEntitySQL
 	if err := database.Where("code = ?", testCode).First(&promotion).Error; err != nil {
@@ -159,6 +225,7 @@ func TestDeleteFinancingPromotion(t *testing.T) {
 }
 
 func TestDeleteDiscountPromotion(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -180,7 +247,7 @@ func TestDeleteDiscountPromotion(t *testing.T) {
 
 	bankRepo := NewBankRelationalRepository(database)
 
-	bankRepo.DeleteDiscountPromotion(testCode)
+	bankRepo.DeleteDiscountPromotion(ctx, testCode)
 
 	var promotion entities.DiscountEntitySQL
 	if err := database.Where("code = ?", testCode).First(&promotion).Error; err != nil {
@@ -191,6 +258,7 @@ func TestDeleteDiscountPromotion(t *testing.T) {
 }
 
 func TestGetBankCustomerCounts(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -209,7 +277,7 @@ func TestGetBankCustomerCounts(t *testing.T) {
 
 	bankRepo := NewBankRelationalRepository(database)
 
-	result, err := bankRepo.GetBankCustomerCounts()
+	result, err := bankRepo.GetBankCustomerCounts(ctx)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
@@ -225,3 +293,62 @@ func TestGetBankCustomerCounts(t *testing.T) {
 	assert.Equal(t, bank.BankName, "Santander")
 	assert.Equal(t, bank.CustomerCount, 2)
 }
+
+func TestBankRegistry(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	bankRepo := NewBankRelationalRepository(database)
+
+	newBank := models.Bank{
+		Name:      "Banco Galicia",
+		Cuit:      "30-50000173-5",
+		Address:   "Tte. Gral. Juan D. Perón 430",
+		Telephone: "0810-444-6500",
+	}
+	created, err := bankRepo.CreateBank(ctx, newBank)
+	assert.NoError(t, err)
+	assert.Equal(t, "Banco Galicia", created.Name)
+
+	_, err = bankRepo.CreateBank(ctx, newBank)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	newBank.Telephone = "0810-444-6501"
+	updated, err := bankRepo.UpdateBank(ctx, "30-50000173-5", newBank)
+	assert.NoError(t, err)
+	assert.Equal(t, "0810-444-6501", updated.Telephone)
+
+	_, err = bankRepo.UpdateBank(ctx, "30-00000000-0", newBank)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	bank, err := bankRepo.GetBankByCuit(ctx, "30-50000173-5")
+	assert.NoError(t, err)
+	assert.Equal(t, "0810-444-6501", bank.Telephone)
+
+	banks, err := bankRepo.GetBanks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*banks))
+
+	// A registered bank can receive promotions
+	err = bankRepo.AddDiscountPromotionToBank(ctx, models.Discount{
+		Promotion: models.Promotion{
+			Code:              "GALICIA-2025",
+			PromotionTitle:    "Galicia Days",
+			NameStore:         "Tech Store",
+			CuitStore:         "30-98765432-1",
+			ValidityStartDate: "2025-03-01T00:00:00Z",
+			ValidityEndDate:   "2025-03-31T00:00:00Z",
+			Bank:              newBank,
+		},
+		DiscountPercentage: models.MustParsePercentage("10"),
+	})
+	assert.NoError(t, err)
+}
@@ -1,7 +1,10 @@
 package relational_repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
@@ -9,6 +12,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CardRepositoryGORM struct {
@@ -20,69 +24,138 @@ func NewCardRelationalRepository(db *gorm.DB) storage.ICardStorage {
 	return &CardRepositoryGORM{db: db}
 }
 
-func (r *CardRepositoryGORM) GetPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error) {
-	// Retrieve a card and its purchases in a specific month
-	var card entities.CardEntitySQL
-
-	// Define the date range for the given month and year
-	// startDate is the first day of the month, and endDate is the first day of the following month
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0) // One month later
+// GetPaymentSummary retrieves the stored payment summary of a card for a month, including the purchases it bills.
+func (r *CardRepositoryGORM) GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error) {
+	db := r.db.WithContext(ctx)
 
-	// Query the card by its number and load purchases that match the month and year
-	if err := r.db.Where("number = ?", cardNumber).
-		Preload("PurchaseSinglePayments", "created_at >= ? AND created_at < ?", startDate, endDate).
-		Preload("PurchaseMonthlyPayments", "created_at >= ? AND created_at < ?", startDate, endDate).
-		First(&card).Error; err != nil {
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
 		return nil, err
 	}
 
-	// Calculate the total purchases in that month
-	var totalPrice float64
-	for _, purchase := range card.PurchaseSinglePayments {
-		totalPrice += purchase.PurchaseEntity.Amount
+	var paymentSummary entities.PaymentSummaryEntitySQL
+	if err := preloadPaymentSummary(db).
+		Where(&entities.PaymentSummaryEntitySQL{CardID: card.ID, Month: month, Year: year}).
+		Order("id").
+		First(&paymentSummary).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error finding payment summary for card %s: %v", cardNumber, err)
+	}
+
+	return entities.ToPaymentSummary(&paymentSummary), nil
+}
+
+// SavePaymentSummary stores the payment summary of a card for a month, linking it to the purchases it bills.
+func (r *CardRepositoryGORM) SavePaymentSummary(ctx context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
 	}
-	for _, purchase := range card.PurchaseMonthlyPayments {
-		totalPrice += purchase.PurchaseEntity.Amount
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		paymentSummary := entities.ToPaymentSummaryEntityRelational(&summary)
+		paymentSummary.CardID = card.ID
+
+		// Link the billed purchases, identified by their payment voucher
+		if vouchers := singlePaymentVouchers(summary.SinglePayments); len(vouchers) > 0 {
+			if err := tx.Where("card_id = ? AND payment_voucher IN ?", card.ID, vouchers).Find(&paymentSummary.SinglePayments).Error; err != nil {
+				return fmt.Errorf("error finding billed single-payment purchases: %v", err)
+			}
+		}
+		if vouchers := monthlyPaymentVouchers(summary.MonthlyPayments); len(vouchers) > 0 {
+			if err := tx.Where("card_id = ? AND payment_voucher IN ?", card.ID, vouchers).Find(&paymentSummary.MonthlyPayments).Error; err != nil {
+				return fmt.Errorf("error finding billed monthly-payment purchases: %v", err)
+			}
+		}
+
+		if len(summary.Quotas) > 0 {
+			dueQuotas, err := findDueQuotas(tx, card.ID, summary.Month, summary.Year)
+			if err != nil {
+				return err
+			}
+			billed := dueQuotaKeys(summary.Quotas)
+			for _, quota := range dueQuotas {
+				if billed[dueQuotaKey(quota.PurchaseMonthlyPaymentsEntity.PurchaseEntity.PaymentVoucher, quota.Number)] {
+					paymentSummary.Quotas = append(paymentSummary.Quotas, quota)
+				}
+			}
+		}
+
+		// Only the subtotals and the join rows are written, the purchases and quotas themselves are left untouched,
+		// and the unique index on the card and month rejects a second summary for the month
+		if err := tx.Omit("Card", "SinglePayments.*", "MonthlyPayments.*", "Quotas.*").Create(paymentSummary).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("card %s already has a payment summary for %02d/%d: %w", cardNumber, summary.Month, summary.Year, storage.ErrAlreadyExists)
+			}
+			return fmt.Errorf("error inserting payment summary: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Define expiration dates
-	firstExpiration := time.Now().AddDate(0, 0, 15)       // 15 days from today
-	secondExpiration := firstExpiration.AddDate(0, 0, 10) // 10 days from today
+	logger.Info("Payment summary %s stored for card %s", summary.Code, cardNumber)
+	return r.GetPaymentSummary(ctx, cardNumber, summary.Month, summary.Year)
+}
 
-	// Generate a unique code for the Payment Summary
-	code := fmt.Sprintf("SUMMARY-%d-%d", year, month)
+// GetPurchasesInPeriod retrieves the purchases made with a card between from (inclusive) and to (exclusive).
+func (r *CardRepositoryGORM) GetPurchasesInPeriod(ctx context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error) {
+	db := r.db.WithContext(ctx)
 
-	// Create the PaymentSummaryEntity object
-	paymentSummary := entities.PaymentSummaryEntitySQL{
-		Code:                code,
-		Month:               month,
-		Year:                year,
-		FirstExpiration:     firstExpiration,
-		SecondExpiration:    secondExpiration,
-		SurchargePercentage: 5.0,        // Example: a 5% surcharge
-		TotalPrice:          totalPrice, // Total of all purchases
-		CardID:              card.ID,    // The card ID
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := r.db.Create(&paymentSummary).Error; err != nil {
-		return nil, fmt.Errorf("error inserting payment summary: %v", err)
+	var singlePayments []entities.PurchaseSinglePaymentEntitySQL
+	if err := db.Where("card_id = ? AND created_at >= ? AND created_at < ?", card.ID, from, to).
+		Order("created_at").
+		Find(&singlePayments).Error; err != nil {
+		return nil, nil, fmt.Errorf("error finding single-payment purchases of card %s: %v", cardNumber, err)
 	}
 
-	paymentSummary.Card = card
+	var monthlyPayments []entities.PurchaseMonthlyPaymentsEntitySQL
+	if err := db.Preload("Quotas").
+		Where("card_id = ? AND created_at >= ? AND created_at < ?", card.ID, from, to).
+		Order("created_at").
+		Find(&monthlyPayments).Error; err != nil {
+		return nil, nil, fmt.Errorf("error finding monthly-payment purchases of card %s: %v", cardNumber, err)
+	}
 
-	return entities.ToPaymentSummary(&paymentSummary), nil
+	return entities.ConvertPurchaseSinglePaymentList(&singlePayments), entities.ConvertPurchaseMonthlyPaymentsList(&monthlyPayments), nil
+}
+
+// GetQuotasDueInMonth retrieves the installment quotas of a card due in the given month, across all its installment purchases.
+func (r *CardRepositoryGORM) GetQuotasDueInMonth(ctx context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := findDueQuotas(db, card.ID, month, year)
+	if err != nil {
+		return nil, fmt.Errorf("error finding quotas of card %s due in %02d/%d: %v", cardNumber, month, year, err)
+	}
+
+	return entities.ConvertDueQuotaList(&quotas), nil
 }
 
-func (r *CardRepositoryGORM) GetCardsExpiringInNext30Days(day int, month int, year int) (*[]models.Card, error) {
+func (r *CardRepositoryGORM) GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error) {
+	db := r.db.WithContext(ctx)
+
 	startDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 	next30Days := startDate.AddDate(0, 0, 30)
 
 	var paymentSummaryList []entities.PaymentSummaryEntitySQL
 
-	if err := r.db.Preload("Card").Where("first_expiration BETWEEN ? AND ?", startDate, next30Days).Find(&paymentSummaryList).Error; err != nil {
+	if err := db.Preload("Card").Where("first_expiration BETWEEN ? AND ?", startDate, next30Days).Find(&paymentSummaryList).Error; err != nil {
 		return nil, err
 	}
 
@@ -94,40 +167,55 @@ func (r *CardRepositoryGORM) GetCardsExpiringInNext30Days(day int, month int, ye
 	return &cards, nil
 }
 
-func (r *CardRepositoryGORM) GetPurchaseSingle(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+func (r *CardRepositoryGORM) GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error) {
+	db := r.db.WithContext(ctx)
+
 	var paymentEntity entities.PurchaseSinglePaymentEntitySQL
 
-	if err := r.db.Where("cuit_store = ? AND final_amount = ? AND payment_voucher = ?", cuit, finalAmount, paymentVoucher).
+	if err := db.Where("cuit_store = ? AND final_amount = ? AND payment_voucher = ?", cuit, finalAmount, paymentVoucher).
 		First(&paymentEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find single-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+		}
 		return nil, err
 	}
 
 	return entities.ToPurchaseSinglePayment(&paymentEntity), nil
 }
 
-func (r *CardRepositoryGORM) GetPurchaseMonthly(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+func (r *CardRepositoryGORM) GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error) {
+	db := r.db.WithContext(ctx)
+
 	var paymentEntity entities.PurchaseMonthlyPaymentsEntitySQL
 
-	if err := r.db.Preload("Quotas").Where("cuit_store = ? AND final_amount = ? AND payment_voucher = ?", cuit, finalAmount, paymentVoucher).
+	if err := db.Preload("Quotas").Where("cuit_store = ? AND final_amount = ? AND payment_voucher = ?", cuit, finalAmount, paymentVoucher).
 		First(&paymentEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find monthly-payment purchase %s: %w", paymentVoucher, storage.ErrNotFound)
+		}
 		return nil, err
 	}
 
 	return entities.ToPurchaseMonthlyPayments(&paymentEntity), nil
 }
 
-func (r *CardRepositoryGORM) GetTop10CardsByPurchases() (*[]models.Card, error) {
+func (r *CardRepositoryGORM) GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error) {
+	db := r.db.WithContext(ctx)
+
 	var cardEntities []entities.CardEntitySQL
 
-	// Query to find the top 10 cards by number of purchases, with preloads for payments and quotas
-	if err := r.db.Table("CARDS").
-		Select("CARDS.*, (COUNT(PURCHASE_SINGLE_PAYMENTS.id) + COUNT(PURCHASE_MONTHLY_PAYMENTS.id)) as purchase_count").
-		Joins("LEFT JOIN PURCHASE_SINGLE_PAYMENTS ON PURCHASE_SINGLE_PAYMENTS.card_id = CARDS.id").
-		Joins("LEFT JOIN PURCHASE_MONTHLY_PAYMENTS ON PURCHASE_MONTHLY_PAYMENTS.card_id = CARDS.id").
+	// Query to find the top 10 cards by number of purchases, with preloads for payments and quotas.
+	// Each kind of purchase is counted in its own subquery: joining both tables would multiply the counts.
+	// Table names are bound as clauses so that every dialect quotes them
+	if err := db.Table("? AS c", clause.Table{Name: entities.CardEntitySQL{}.TableName()}).
+		Select("c.*, "+
+			"(SELECT COUNT(*) FROM ? s WHERE s.card_id = c.id) + "+
+			"(SELECT COUNT(*) FROM ? m WHERE m.card_id = c.id) AS purchase_count",
+			clause.Table{Name: entities.PurchaseSinglePaymentEntitySQL{}.TableName()},
+			clause.Table{Name: entities.PurchaseMonthlyPaymentsEntitySQL{}.TableName()}).
 		Preload("PurchaseSinglePayments").
 		Preload("PurchaseMonthlyPayments.Quotas").
-		Group("CARDS.id").
-		Order("purchase_count DESC").
+		Order("purchase_count DESC, c.number").
 		Limit(10).
 		Find(&cardEntities).Error; err != nil {
 		logger.Info("Error retrieving top 10 cards by purchases: %v", err)
@@ -142,3 +230,205 @@ func (r *CardRepositoryGORM) GetTop10CardsByPurchases() (*[]models.Card, error)
 
 	return &cards, nil
 }
+
+func (r *CardRepositoryGORM) GetCardByNumber(ctx context.Context, cardNumber string) (*models.Card, error) {
+	db := r.db.WithContext(ctx)
+
+	var card entities.CardEntitySQL
+	if err := db.Preload("Bank").Where("number = ?", cardNumber).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find card with number %s: %v", cardNumber, err)
+	}
+
+	var customerCuits []string
+	if err := db.Model(&entities.CustomerEntitySQL{}).Where("id = ?", card.CustomerID).Pluck("cuit", &customerCuits).Error; err != nil {
+		return nil, fmt.Errorf("could not find the customer of card %s: %v", cardNumber, err)
+	}
+
+	result := entities.ToCard(&card)
+	if len(customerCuits) > 0 {
+		result.CustomerCuit = customerCuits[0]
+	}
+	return result, nil
+}
+
+// IssueCard issues a new card to the customer with the card's customer CUIT at the card's bank. Card numbers are unique.
+func (r *CardRepositoryGORM) IssueCard(ctx context.Context, card models.Card) (*models.Card, error) {
+	db := r.db.WithContext(ctx)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.CardEntitySQL{}).Where("number = ?", card.Number).Count(&count).Error; err != nil {
+			return fmt.Errorf("error checking existing cards: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("a card with number %s already exists: %w", card.Number, storage.ErrAlreadyExists)
+		}
+
+		bank, err := findBankByCuit(tx, card.Bank.Cuit)
+		if err != nil {
+			return err
+		}
+		customer, err := findCustomerByCuit(tx, card.CustomerCuit)
+		if err != nil {
+			return err
+		}
+
+		cardEntity := entities.ToCardEntityRelational(&card)
+		cardEntity.BankID = bank.ID
+		cardEntity.CustomerID = customer.ID
+		if err := tx.Omit("Bank").Create(cardEntity).Error; err != nil {
+			return fmt.Errorf("error inserting card: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Card %s issued to customer %s at bank %s", card.Number, card.CustomerCuit, card.Bank.Cuit)
+	return r.GetCardByNumber(ctx, card.Number)
+}
+
+// UpdateCardExpiration replaces the expiration date of a card.
+func (r *CardRepositoryGORM) UpdateCardExpiration(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error) {
+	return r.updateCard(ctx, cardNumber, "expiration_date", expirationDate)
+}
+
+// UpdateCardStatus replaces the lifecycle status of a card.
+func (r *CardRepositoryGORM) UpdateCardStatus(ctx context.Context, cardNumber string, status models.CardStatus) (*models.Card, error) {
+	return r.updateCard(ctx, cardNumber, "status", string(status))
+}
+
+// updateCard sets a single column of the card with the given number and returns the updated card.
+func (r *CardRepositoryGORM) updateCard(ctx context.Context, cardNumber string, column string, value interface{}) (*models.Card, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(card).Update(column, value).Error; err != nil {
+		return nil, fmt.Errorf("error updating %s of card %s: %v", column, cardNumber, err)
+	}
+
+	logger.Info("Card %s: %s set to %v", cardNumber, column, value)
+	return r.GetCardByNumber(ctx, cardNumber)
+}
+
+func (r *CardRepositoryGORM) AddPurchaseSinglePayment(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentEntity := entities.ToPurchaseSinglePaymentEntity(&purchase)
+	paymentEntity.PurchaseEntity.CardID = card.ID
+	if err := db.Create(paymentEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("card %s already has a single-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting single-payment purchase: %v", err)
+	}
+
+	logger.Info("Single-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return entities.ToPurchaseSinglePayment(paymentEntity), nil
+}
+
+func (r *CardRepositoryGORM) AddPurchaseMonthlyPayment(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// The quotas are created together with the purchase through the association
+	paymentEntity := entities.ToPurchaseMonthlyPaymentsEntity(&purchase)
+	paymentEntity.PurchaseEntity.CardID = card.ID
+	if err := db.Create(paymentEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("card %s already has a monthly-payment purchase with voucher %s: %w", cardNumber, purchase.PaymentVoucher, storage.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("error inserting monthly-payment purchase: %v", err)
+	}
+
+	logger.Info("Monthly-payment purchase %s registered on card %s", purchase.PaymentVoucher, cardNumber)
+	return entities.ToPurchaseMonthlyPayments(paymentEntity), nil
+}
+
+// findCardByNumber retrieves the card entity with the given number or a wrapped storage.ErrNotFound.
+func findCardByNumber(db *gorm.DB, cardNumber string) (*entities.CardEntitySQL, error) {
+	var card entities.CardEntitySQL
+	if err := db.Where("number = ?", cardNumber).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find card with number %s: %v", cardNumber, err)
+	}
+	return &card, nil
+}
+
+// findDueQuotas retrieves the quotas of a card's installment purchases due in the given month, with their purchase preloaded.
+// Months are stored zero-padded, unpadded values from older rows are matched as well.
+func findDueQuotas(db *gorm.DB, cardID uint, month int, year int) ([]entities.QuotaEntitySQL, error) {
+	var quotas []entities.QuotaEntitySQL
+	err := db.Preload("PurchaseMonthlyPaymentsEntity").
+		Where("purchase_monthly_payments_entity_id IN (?)",
+			db.Model(&entities.PurchaseMonthlyPaymentsEntitySQL{}).Select("id").Where("card_id = ?", cardID)).
+		Where("month IN ? AND year = ?", quotaMonths(month), strconv.Itoa(year)).
+		Order("purchase_monthly_payments_entity_id, number").
+		Find(&quotas).Error
+	return quotas, err
+}
+
+// preloadPaymentSummary preloads the card, billed purchases, quotas and subtotals of the payment summaries queried.
+func preloadPaymentSummary(db *gorm.DB) *gorm.DB {
+	return db.Preload("Card.Bank").
+		Preload("SinglePayments").
+		Preload("MonthlyPayments.Quotas").
+		Preload("Quotas", func(db *gorm.DB) *gorm.DB {
+			return db.Order(clause.OrderByColumn{Column: clause.Column{Table: entities.QuotaEntitySQL{}.TableName(), Name: "id"}})
+		}).
+		Preload("Quotas.PurchaseMonthlyPaymentsEntity").
+		Preload("Subtotals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		})
+}
+
+func quotaMonths(month int) []string {
+	return []string{fmt.Sprintf("%02d", month), strconv.Itoa(month)}
+}
+
+func dueQuotaKey(paymentVoucher string, number int) string {
+	return fmt.Sprintf("%s#%d", paymentVoucher, number)
+}
+
+func dueQuotaKeys(quotas []models.DueQuota) map[string]bool {
+	keys := make(map[string]bool, len(quotas))
+	for _, quota := range quotas {
+		keys[dueQuotaKey(quota.PaymentVoucher, quota.Number)] = true
+	}
+	return keys
+}
+
+func singlePaymentVouchers(purchases []models.PurchaseSinglePayment) []string {
+	vouchers := make([]string, 0, len(purchases))
+	for _, purchase := range purchases {
+		vouchers = append(vouchers, purchase.PaymentVoucher)
+	}
+	return vouchers
+}
+
+func monthlyPaymentVouchers(purchases []models.PurchaseMonthlyPayment) []string {
+	vouchers := make([]string, 0, len(purchases))
+	for _, purchase := range purchases {
+		vouchers = append(vouchers, purchase.PaymentVoucher)
+	}
+	return vouchers
+}
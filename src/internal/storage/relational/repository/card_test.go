@@ -1,12 +1,14 @@
 package relational_repository
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 	entities "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	mysql "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/testutils"
@@ -14,6 +16,7 @@ import (
 )
 
 func TestGetPaymentSummary(t *testing.T) {
+	ctx := context.Background()
 	cardNumber := "1234567812345678"
 	month := 10
 	year := 2024
@@ -37,42 +40,139 @@ func TestGetPaymentSummary(t *testing.T) {
 	// Test Repository
 	cardRepo := NewCardRelationalRepository(database)
 
-	paymentSummary, err := cardRepo.GetPaymentSummary(cardNumber, month, year)
+	// The stored summary is returned as is, reading it does not create new rows
+	var countBefore, countAfter int64
+	database.Model(&entities.PaymentSummaryEntitySQL{}).Count(&countBefore)
+
+	paymentSummary, err := cardRepo.GetPaymentSummary(ctx, cardNumber, month, year)
+	assert.NoError(t, err)
+
+	database.Model(&entities.PaymentSummaryEntitySQL{}).Count(&countAfter)
+
+	// Assert
+	assert.Equal(t, "SUMMARY-2024-10-A", paymentSummary.Code)
+	assert.Equal(t, models.MustParseMoney("330"), paymentSummary.TotalPrice)
+	assert.Equal(t, models.MustParsePercentage("5"), paymentSummary.SurchargePercentage)
+	assert.Equal(t, cardNumber, paymentSummary.Card.Number)
+	assert.Equal(t, countBefore, countAfter)
+
+	// Months without a closed cycle have no summary
+	_, err = cardRepo.GetPaymentSummary(ctx, cardNumber, 1, 2030)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestSavePaymentSummary(t *testing.T) {
+	ctx := context.Background()
+	cardNumber := "1234567812345678"
+
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
 
-	// Assert
-	code := fmt.Sprintf("SUMMARY-%d-%d", year, month)
+	cardRepo := NewCardRelationalRepository(database)
+
+	from := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)
+	singlePayments, monthlyPayments, err := cardRepo.GetPurchasesInPeriod(ctx, cardNumber, from, from.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, *singlePayments)
+
+	summary := models.PaymentSummary{
+		Code:                "SUMMARY-1234567812345678-2024-09",
+		Month:               9,
+		Year:                2024,
+		FirstExpiration:     time.Date(2024, time.October, 15, 0, 0, 0, 0, time.UTC),
+		SecondExpiration:    time.Date(2024, time.October, 25, 0, 0, 0, 0, time.UTC),
+		SurchargePercentage: models.MustParsePercentage("5.0"),
+		TotalPrice:          models.MustParseMoney("100.0"),
+		SinglePayments:      *singlePayments,
+		MonthlyPayments:     *monthlyPayments,
+	}
+
+	saved, err := cardRepo.SavePaymentSummary(ctx, cardNumber, summary)
+	assert.NoError(t, err)
+	assert.Equal(t, summary.Code, saved.Code)
+	assert.Equal(t, len(*singlePayments), len(saved.SinglePayments))
+	assert.Equal(t, len(*monthlyPayments), len(saved.MonthlyPayments))
+
+	// A card has at most one summary per month
+	_, err = cardRepo.SavePaymentSummary(ctx, cardNumber, summary)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	_, err = cardRepo.SavePaymentSummary(ctx, "0000000000000000", summary)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGetQuotasDueInMonth(t *testing.T) {
+	ctx := context.Background()
+	cardNumber := "1234567812345678"
+
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
 
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0) // One month later
+	cardRepo := NewCardRelationalRepository(database)
 
-	var paymentSummaryEntity entities.PaymentSummaryEntitySQL
-	if err := database.
-		Joins("JOIN CARDS ON CARDS.id = PAYMENT_SUMMARIES.card_id").
-		Preload("Card").
-		Preload("Card.PurchaseSinglePayments", "created_at >= ? AND created_at < ?", startDate, endDate).
-		Preload("Card.PurchaseMonthlyPayments", "created_at >= ? AND created_at < ?", startDate, endDate).
-		Where("number = ?", cardNumber).
-		Where("code = ?", code).First(&paymentSummaryEntity).Error; err != nil {
-		panic(fmt.Errorf("could not find promotion with code %s: %v", code, err))
+	// November holds the second quota of the October purchase and the first quota of the November one
+	quotas, err := cardRepo.GetQuotasDueInMonth(ctx, cardNumber, 11, 2024)
+	assert.NoError(t, err)
+	if assert.Len(t, *quotas, 2) {
+		assert.Equal(t, "PV20241001", (*quotas)[0].PaymentVoucher)
+		assert.Equal(t, 2, (*quotas)[0].Number)
+		assert.Equal(t, 3, (*quotas)[0].NumberOfQuotas)
+		assert.Equal(t, "PV20241101", (*quotas)[1].PaymentVoucher)
+		assert.Equal(t, 1, (*quotas)[1].Number)
+		assert.Equal(t, models.MustParseMoney("110"), (*quotas)[1].Price)
 	}
 
-	assert.Equal(t, paymentSummaryEntity.Code, code)
-	assert.Equal(t, paymentSummaryEntity.TotalPrice, 510.00)
-	assert.Equal(t, len(paymentSummaryEntity.Card.PurchaseMonthlyPayments), 1)
-	assert.Equal(t, len(paymentSummaryEntity.Card.PurchaseSinglePayments), 3)
+	// The due quotas are linked to the summary of the month
+	summary := models.PaymentSummary{
+		Code:                "SUMMARY-1234567812345678-2024-11",
+		Month:               11,
+		Year:                2024,
+		FirstExpiration:     time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC),
+		SecondExpiration:    time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+		SurchargePercentage: models.MustParsePercentage("5.0"),
+		TotalPrice:          models.MustParseMoney("220.0"),
+		Quotas:              *quotas,
+	}
+	saved, err := cardRepo.SavePaymentSummary(ctx, cardNumber, summary)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Quotas, 2)
 
-	paymentSummaryMapper := entities.ToPaymentSummary(&paymentSummaryEntity)
+	quotas, err = cardRepo.GetQuotasDueInMonth(ctx, cardNumber, 6, 2030)
+	assert.NoError(t, err)
+	assert.Empty(t, *quotas)
 
-	assert.Equal(t, paymentSummaryMapper.Code, paymentSummary.Code)
-	assert.Equal(t, paymentSummaryMapper.TotalPrice, paymentSummary.TotalPrice)
-	assert.Equal(t, len(paymentSummaryMapper.Card.PurchaseMonthlyPayments), len(paymentSummary.Card.PurchaseMonthlyPayments))
-	assert.Equal(t, len(paymentSummaryMapper.Card.PurchaseSinglePayments), len(paymentSummary.Card.PurchaseSinglePayments))
+	_, err = cardRepo.GetQuotasDueInMonth(ctx, "0000000000000000", 11, 2024)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
 func TestGetCardsExpiringInNext30Days(t *testing.T) {
+	ctx := context.Background()
 	day := 16
 	month := 10
 	year := 2024
@@ -95,7 +195,7 @@ func TestGetCardsExpiringInNext30Days(t *testing.T) {
 
 	cardRepo := NewCardRelationalRepository(database)
 
-	cards, err := cardRepo.GetCardsExpiringInNext30Days(day, month, year)
+	cards, err := cardRepo.GetCardsExpiringInNext30Days(ctx, day, month, year)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
@@ -104,9 +204,10 @@ func TestGetCardsExpiringInNext30Days(t *testing.T) {
 }
 
 func TestGetPurchaseSingle(t *testing.T) {
+	ctx := context.Background()
 	paymentVoucher := "PV20241001"
 	cuit := "30-12345678-9"
-	finalAmount := 100.00
+	finalAmount := models.MustParseMoney("100")
 
 	testutils.InitTestSetup()
 
@@ -126,7 +227,7 @@ func TestGetPurchaseSingle(t *testing.T) {
 
 	cardRepo := NewCardRelationalRepository(database)
 
-	payment, err := cardRepo.GetPurchaseSingle(cuit, finalAmount, paymentVoucher)
+	payment, err := cardRepo.GetPurchaseSingle(ctx, cuit, finalAmount, paymentVoucher)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
@@ -135,9 +236,10 @@ func TestGetPurchaseSingle(t *testing.T) {
 }
 
 func TestGetPurchaseMonthly(t *testing.T) {
+	ctx := context.Background()
 	paymentVoucher := "PV20241101"
 	cuit := "20-98765432-1"
-	finalAmount := 440.0
+	finalAmount := models.MustParseMoney("440")
 
 	testutils.InitTestSetup()
 
@@ -157,17 +259,18 @@ func TestGetPurchaseMonthly(t *testing.T) {
 
 	cardRepo := NewCardRelationalRepository(database)
 
-	payment, err := cardRepo.GetPurchaseMonthly(cuit, finalAmount, paymentVoucher)
+	payment, err := cardRepo.GetPurchaseMonthly(ctx, cuit, finalAmount, paymentVoucher)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
 
 	assert.Equal(t, payment.Purchase.Store, "Store B")
-	assert.Equal(t, payment.Purchase.Amount, 110.00)
+	assert.Equal(t, models.MustParseMoney("110"), payment.Purchase.Amount)
 	assert.Equal(t, len(payment.Quota), 4)
 }
 
 func TestGetTop10CardsByPurchases(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -186,7 +289,7 @@ func TestGetTop10CardsByPurchases(t *testing.T) {
 
 	cardRepo := NewCardRelationalRepository(database)
 
-	cards, err := cardRepo.GetTop10CardsByPurchases()
+	cards, err := cardRepo.GetTop10CardsByPurchases(ctx)
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -211,3 +314,110 @@ func TestGetTop10CardsByPurchases(t *testing.T) {
 	}
 	assert.Equal(t, len(purchaseMonthly.Quota), 3)
 }
+
+func TestAddPurchaseSinglePayment(t *testing.T) {
+	ctx := context.Background()
+	cardNumber := "1234567812345678"
+	purchaseDate := time.Date(2024, time.Month(10), 20, 12, 0, 0, 0, time.UTC)
+
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	cardRepo := NewCardRelationalRepository(database)
+	purchase, err := cardRepo.AddPurchaseSinglePayment(ctx, cardNumber, models.PurchaseSinglePayment{
+		Purchase: models.Purchase{
+			PaymentVoucher: "PVTEST0001",
+			Store:          "Store A",
+			CuitStore:      "30-12345678-9",
+			Amount:         models.MustParseMoney("120.00"),
+			FinalAmount:    models.MustParseMoney("120.00"),
+			PurchaseDate:   purchaseDate,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "PVTEST0001", purchase.PaymentVoucher)
+
+	var paymentEntity entities.PurchaseSinglePaymentEntitySQL
+	if err := database.Where("payment_voucher = ?", "PVTEST0001").First(&paymentEntity).Error; err != nil {
+		panic(fmt.Errorf("could not find purchase with voucher %s: %v", "PVTEST0001", err))
+	}
+	assert.Equal(t, paymentEntity.PurchaseEntity.CreatedAt.Unix(), purchaseDate.Unix())
+
+	_, err = cardRepo.AddPurchaseSinglePayment(ctx, "0000000000000000", *purchase)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestCardLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	cardRepo := NewCardRelationalRepository(database)
+
+	// Seeded cards are active
+	card, err := cardRepo.GetCardByNumber(ctx, "1234567812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusActive, card.Status)
+	assert.Equal(t, "20-12345678-9", card.CustomerCuit)
+
+	newCard := models.Card{
+		Number:               "4509123412341234",
+		Ccv:                  "321",
+		CardholderNameInCard: "JANE SMITH",
+		Since:                time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		ExpirationDate:       time.Date(2030, time.March, 31, 0, 0, 0, 0, time.UTC),
+		Status:               models.CardStatusActive,
+		Bank:                 models.Bank{Cuit: "30-98765432-1"},
+		CustomerCuit:         "20-23456789-0",
+	}
+	issued, err := cardRepo.IssueCard(ctx, newCard)
+	assert.NoError(t, err)
+	assert.Equal(t, "BBVA", issued.Bank.Name)
+	assert.Equal(t, "20-23456789-0", issued.CustomerCuit)
+
+	_, err = cardRepo.IssueCard(ctx, newCard)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	unknownCustomer := newCard
+	unknownCustomer.Number = "4509123412349999"
+	unknownCustomer.CustomerCuit = "20-00000000-0"
+	_, err = cardRepo.IssueCard(ctx, unknownCustomer)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	renewed, err := cardRepo.UpdateCardExpiration(ctx, "4509123412341234", time.Date(2035, time.March, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 2035, renewed.ExpirationDate.Year())
+
+	blocked, err := cardRepo.UpdateCardStatus(ctx, "4509123412341234", models.CardStatusBlocked)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CardStatusBlocked, blocked.Status)
+
+	_, err = cardRepo.UpdateCardStatus(ctx, "0000000000000000", models.CardStatusCancelled)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,245 @@
+package relational_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type CompensationRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewCompensationRelationalRepository creates a new instance of CompensationRepositoryGORM
+func NewCompensationRelationalRepository(db *gorm.DB) storage.ICompensationStorage {
+	return &CompensationRepositoryGORM{db: db}
+}
+
+// RemoveBank removes a bank by its CUIT.
+func (r *CompensationRepositoryGORM) RemoveBank(ctx context.Context, cuit string) error {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, cuit)
+	if err != nil {
+		return err
+	}
+	if err := db.Delete(bank).Error; err != nil {
+		return fmt.Errorf("error removing bank %s: %v", cuit, err)
+	}
+
+	logger.Info("Bank %s removed", cuit)
+	return nil
+}
+
+// RemoveCustomer removes a customer by its CUIT.
+func (r *CompensationRepositoryGORM) RemoveCustomer(ctx context.Context, cuit string) error {
+	db := r.db.WithContext(ctx)
+
+	customer, err := findCustomerByCuit(db, cuit)
+	if err != nil {
+		return err
+	}
+	if err := db.Delete(customer).Error; err != nil {
+		return fmt.Errorf("error removing customer %s: %v", cuit, err)
+	}
+
+	logger.Info("Customer %s removed", cuit)
+	return nil
+}
+
+// RemoveCard removes a card by its number.
+func (r *CompensationRepositoryGORM) RemoveCard(ctx context.Context, cardNumber string) error {
+	db := r.db.WithContext(ctx)
+
+	result := db.Where("number = ?", cardNumber).Delete(&entities.CardEntitySQL{})
+	if result.Error != nil {
+		return fmt.Errorf("error removing card %s: %v", cardNumber, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("could not find card with number %s: %w", cardNumber, storage.ErrNotFound)
+	}
+
+	logger.Info("Card %s removed", cardNumber)
+	return nil
+}
+
+// RemovePromotion removes a discount or financing promotion by its code.
+func (r *CompensationRepositoryGORM) RemovePromotion(ctx context.Context, code string) error {
+	db := r.db.WithContext(ctx)
+
+	var removed int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&entities.DiscountEntitySQL{}, &entities.FinancingEntitySQL{}} {
+			result := tx.Where("code = ?", code).Delete(model)
+			if result.Error != nil {
+				return fmt.Errorf("error removing promotion %s: %v", code, result.Error)
+			}
+			removed += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+	}
+
+	logger.Info("Promotion %s removed", code)
+	return nil
+}
+
+// RemovePurchaseSinglePayment removes the latest single-payment purchase registered on a card with the given voucher.
+func (r *CompensationRepositoryGORM) RemovePurchaseSinglePayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return err
+	}
+
+	var purchase entities.PurchaseSinglePaymentEntitySQL
+	if err := db.Where("card_id = ? AND payment_voucher = ?", card.ID, paymentVoucher).Order("id DESC").First(&purchase).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not find single-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound)
+		}
+		return fmt.Errorf("error finding single-payment purchase %s of card %s: %v", paymentVoucher, cardNumber, err)
+	}
+	if err := db.Delete(&purchase).Error; err != nil {
+		return fmt.Errorf("error removing single-payment purchase %s of card %s: %v", paymentVoucher, cardNumber, err)
+	}
+
+	logger.Info("Single-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePurchaseMonthlyPayment removes the latest installment purchase registered on a card with the given voucher, and its quotas.
+func (r *CompensationRepositoryGORM) RemovePurchaseMonthlyPayment(ctx context.Context, cardNumber string, paymentVoucher string) error {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return err
+	}
+
+	var purchase entities.PurchaseMonthlyPaymentsEntitySQL
+	if err := db.Where("card_id = ? AND payment_voucher = ?", card.ID, paymentVoucher).Order("id DESC").First(&purchase).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not find monthly-payment purchase %s of card %s: %w", paymentVoucher, cardNumber, storage.ErrNotFound)
+		}
+		return fmt.Errorf("error finding monthly-payment purchase %s of card %s: %v", paymentVoucher, cardNumber, err)
+	}
+
+	// Quotas are removed explicitly, as SQLite only cascades when foreign keys are enabled
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("purchase_monthly_payments_entity_id = ?", purchase.ID).Delete(&entities.QuotaEntitySQL{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&purchase).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error removing monthly-payment purchase %s of card %s: %v", paymentVoucher, cardNumber, err)
+	}
+
+	logger.Info("Monthly-payment purchase %s removed from card %s", paymentVoucher, cardNumber)
+	return nil
+}
+
+// RemovePaymentSummary removes the payment summary of a card for a month, its subtotals and its links to the billed purchases and quotas.
+func (r *CompensationRepositoryGORM) RemovePaymentSummary(ctx context.Context, cardNumber string, month int, year int) error {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return err
+	}
+
+	var summary entities.PaymentSummaryEntitySQL
+	if err := db.Where(&entities.PaymentSummaryEntitySQL{CardID: card.ID, Month: month, Year: year}).First(&summary).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+		}
+		return fmt.Errorf("error finding payment summary of card %s for %02d/%d: %v", cardNumber, month, year, err)
+	}
+
+	// Selecting the many2many associations deletes their join rows only, the purchases and quotas are kept
+	if err := db.Select("Subtotals", "SinglePayments", "MonthlyPayments", "Quotas").Delete(&summary).Error; err != nil {
+		return fmt.Errorf("error removing payment summary of card %s for %02d/%d: %v", cardNumber, month, year, err)
+	}
+
+	logger.Info("Payment summary of card %s for %02d/%d removed", cardNumber, month, year)
+	return nil
+}
+
+// RemoveBillingCycle removes the billing cycle configuration of a bank.
+func (r *CompensationRepositoryGORM) RemoveBillingCycle(ctx context.Context, bankCuit string) error {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, bankCuit)
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("bank_id = ?", bank.ID).Delete(&entities.BillingCycleEntitySQL{})
+	if result.Error != nil {
+		return fmt.Errorf("error removing billing cycle of bank %s: %v", bankCuit, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bank %s has no billing cycle: %w", bankCuit, storage.ErrNotFound)
+	}
+
+	logger.Info("Billing cycle of bank %s removed", bankCuit)
+	return nil
+}
+
+// RemoveExchangeRate removes the rate of a currency for a day.
+func (r *CompensationRepositoryGORM) RemoveExchangeRate(ctx context.Context, currency models.Currency, date time.Time) error {
+	day := models.RateDay(date)
+	result := r.db.WithContext(ctx).Where(&entities.ExchangeRateEntitySQL{Currency: string(currency), Date: day}).Delete(&entities.ExchangeRateEntitySQL{})
+	if result.Error != nil {
+		return fmt.Errorf("error removing %s exchange rate of %s: %v", currency, day.Format(time.DateOnly), result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no %s exchange rate on %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound)
+	}
+
+	logger.Info("%s exchange rate of %s removed", currency, day.Format(time.DateOnly))
+	return nil
+}
+
+// RemovePayment removes a payment by its code and its allocations, and takes them off the paid amounts of the billed items.
+func (r *CompensationRepositoryGORM) RemovePayment(ctx context.Context, code string) error {
+	db := r.db.WithContext(ctx)
+
+	var payment entities.PaymentEntitySQL
+	if err := db.Preload("Allocations").Where("code = ?", code).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not find payment with code %s: %w", code, storage.ErrNotFound)
+		}
+		return fmt.Errorf("error finding payment %s: %v", code, err)
+	}
+
+	// Allocations are removed explicitly, as SQLite only cascades when foreign keys are enabled
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := allocatePayment(tx, payment.PaymentSummaryID, payment.Allocations, nil, -1); err != nil {
+			return err
+		}
+		if err := tx.Where("payment_id = ?", payment.ID).Delete(&entities.PaymentAllocationEntitySQL{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&payment).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error removing payment %s: %v", code, err)
+	}
+
+	logger.Info("Payment %s removed", code)
+	return nil
+}
@@ -0,0 +1,214 @@
+package relational_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type CustomerRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewCustomerRelationalRepository creates a new instance of CustomerRepositoryGORM
+func NewCustomerRelationalRepository(db *gorm.DB) storage.ICustomerStorage {
+	return &CustomerRepositoryGORM{db: db}
+}
+
+// CreateCustomer stores a new customer. CUIT and DNI are unique among customers.
+func (r *CustomerRepositoryGORM) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	db := r.db.WithContext(ctx)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.CustomerEntitySQL{}).
+			Where("cuit = ? OR dni = ?", customer.Cuit, customer.Dni).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("error checking existing customers: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("a customer with cuit %s or dni %s already exists: %w", customer.Cuit, customer.Dni, storage.ErrAlreadyExists)
+		}
+
+		if err := tx.Create(entities.ToCustomerEntityRelational(&customer)).Error; err != nil {
+			return fmt.Errorf("error inserting customer: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Customer %s registered", customer.Cuit)
+	return r.GetCustomerByCuit(ctx, customer.Cuit)
+}
+
+// GetCustomerByCuit retrieves a customer, including the CUITs of its banks, by its CUIT.
+func (r *CustomerRepositoryGORM) GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error) {
+	db := r.db.WithContext(ctx)
+
+	customer, err := findCustomerByCuit(db.Preload("Banks", func(db *gorm.DB) *gorm.DB { return db.Order("cuit") }), cuit)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ToCustomer(customer), nil
+}
+
+// UpdateCustomer replaces the name, DNI, address, telephone and entry date of a customer.
+func (r *CustomerRepositoryGORM) UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error) {
+	db := r.db.WithContext(ctx)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findCustomerByCuit(tx, cuit)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.CustomerEntitySQL{}).
+			Where("dni = ? AND id <> ?", customer.Dni, existing.ID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("error checking existing customers: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("a customer with dni %s already exists: %w", customer.Dni, storage.ErrAlreadyExists)
+		}
+
+		if err := tx.Model(existing).Updates(map[string]interface{}{
+			"complete_name": customer.CompleteName,
+			"dni":           customer.Dni,
+			"address":       customer.Address,
+			"telephone":     customer.Telephone,
+			"entry_date":    customer.EntryDate,
+		}).Error; err != nil {
+			return fmt.Errorf("error updating customer %s: %v", cuit, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Customer %s updated", cuit)
+	return r.GetCustomerByCuit(ctx, cuit)
+}
+
+// GetCustomers retrieves all customers ordered by CUIT.
+func (r *CustomerRepositoryGORM) GetCustomers(ctx context.Context) (*[]models.Customer, error) {
+	db := r.db.WithContext(ctx)
+
+	var customerEntities []entities.CustomerEntitySQL
+	if err := db.Preload("Banks", func(db *gorm.DB) *gorm.DB { return db.Order("cuit") }).
+		Order("cuit").
+		Find(&customerEntities).Error; err != nil {
+		return nil, fmt.Errorf("error retrieving customers: %v", err)
+	}
+
+	customers := []models.Customer{}
+	for _, customer := range customerEntities {
+		customers = append(customers, *entities.ToCustomer(&customer))
+	}
+	return &customers, nil
+}
+
+// AddCustomerToBank makes a customer a member of a bank.
+func (r *CustomerRepositoryGORM) AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	db := r.db.WithContext(ctx)
+
+	customer, bank, err := r.findMembership(ctx, customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+
+	if isMember(customer, bank) {
+		return fmt.Errorf("customer %s is already a member of bank %s: %w", customerCuit, bankCuit, storage.ErrAlreadyExists)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(customer).Omit("Banks.*").Association("Banks").Append(bank); err != nil {
+			return err
+		}
+		return touchCustomer(tx, customer)
+	})
+	if err != nil {
+		return fmt.Errorf("error adding customer %s to bank %s: %v", customerCuit, bankCuit, err)
+	}
+
+	logger.Info("Customer %s joined bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// RemoveCustomerFromBank ends the membership of a customer in a bank.
+func (r *CustomerRepositoryGORM) RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error {
+	db := r.db.WithContext(ctx)
+
+	customer, bank, err := r.findMembership(ctx, customerCuit, bankCuit)
+	if err != nil {
+		return err
+	}
+
+	if !isMember(customer, bank) {
+		return fmt.Errorf("customer %s is not a member of bank %s: %w", customerCuit, bankCuit, storage.ErrNotFound)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(customer).Association("Banks").Delete(bank); err != nil {
+			return err
+		}
+		return touchCustomer(tx, customer)
+	})
+	if err != nil {
+		return fmt.Errorf("error removing customer %s from bank %s: %v", customerCuit, bankCuit, err)
+	}
+
+	logger.Info("Customer %s left bank %s", customerCuit, bankCuit)
+	return nil
+}
+
+// findMembership retrieves the customer, with its banks preloaded, and the bank of a membership.
+func (r *CustomerRepositoryGORM) findMembership(ctx context.Context, customerCuit string, bankCuit string) (*entities.CustomerEntitySQL, *entities.BankEntitySQL, error) {
+	db := r.db.WithContext(ctx)
+
+	customer, err := findCustomerByCuit(db.Preload("Banks"), customerCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+	bank, err := findBankByCuit(db, bankCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return customer, bank, nil
+}
+
+// findCustomerByCuit retrieves the customer entity with the given CUIT or a wrapped storage.ErrNotFound.
+func findCustomerByCuit(db *gorm.DB, cuit string) (*entities.CustomerEntitySQL, error) {
+	var customer entities.CustomerEntitySQL
+	if err := db.Where("cuit = ?", strings.TrimSpace(cuit)).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find customer with cuit %s: %w", cuit, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not find customer with cuit %s: %v", cuit, err)
+	}
+	return &customer, nil
+}
+
+// touchCustomer updates the UpdatedAt of a customer whose memberships changed, as the CUSTOMERS_BANKS rows have no
+// timestamp of their own. The replication job finds the changed memberships through it.
+func touchCustomer(tx *gorm.DB, customer *entities.CustomerEntitySQL) error {
+	return tx.Model(customer).UpdateColumn("updated_at", time.Now()).Error
+}
+
+func isMember(customer *entities.CustomerEntitySQL, bank *entities.BankEntitySQL) bool {
+	for _, member := range customer.Banks {
+		if member.ID == bank.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package relational_repository
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	mysql "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	customerRepo := NewCustomerRelationalRepository(database)
+
+	// Seeded customer with its bank
+	customer, err := customerRepo.GetCustomerByCuit(ctx, "20-12345678-9")
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", customer.CompleteName)
+	assert.Equal(t, []string{"30-12345678-9"}, customer.BankCuits)
+
+	_, err = customerRepo.GetCustomerByCuit(ctx, "20-00000000-0")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Registration
+	newCustomer := models.Customer{
+		CompleteName: "Ana Gómez",
+		Dni:          "31234567",
+		Cuit:         "27-31234567-3",
+		Address:      "742 Evergreen Terrace",
+		Telephone:    "+54 11 4444 5555",
+		EntryDate:    time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+	created, err := customerRepo.CreateCustomer(ctx, newCustomer)
+	assert.NoError(t, err)
+	assert.Equal(t, "27-31234567-3", created.Cuit)
+	assert.Empty(t, created.BankCuits)
+
+	_, err = customerRepo.CreateCustomer(ctx, newCustomer)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	duplicatedDni := newCustomer
+	duplicatedDni.Cuit = "27-99999999-3"
+	_, err = customerRepo.CreateCustomer(ctx, duplicatedDni)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	// Update
+	newCustomer.Telephone = "+54 11 6666 7777"
+	updated, err := customerRepo.UpdateCustomer(ctx, "27-31234567-3", newCustomer)
+	assert.NoError(t, err)
+	assert.Equal(t, "+54 11 6666 7777", updated.Telephone)
+
+	newCustomer.Dni = "12345678"
+	_, err = customerRepo.UpdateCustomer(ctx, "27-31234567-3", newCustomer)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	// Bank memberships
+	assert.NoError(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-12345678-9"))
+	assert.NoError(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-98765432-1"))
+	assert.ErrorIs(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-12345678-9"), storage.ErrAlreadyExists)
+	assert.ErrorIs(t, customerRepo.AddCustomerToBank(ctx, "27-31234567-3", "30-00000000-0"), storage.ErrNotFound)
+
+	customer, err = customerRepo.GetCustomerByCuit(ctx, "27-31234567-3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"30-12345678-9", "30-98765432-1"}, customer.BankCuits)
+
+	assert.NoError(t, customerRepo.RemoveCustomerFromBank(ctx, "27-31234567-3", "30-12345678-9"))
+	assert.ErrorIs(t, customerRepo.RemoveCustomerFromBank(ctx, "27-31234567-3", "30-12345678-9"), storage.ErrNotFound)
+
+	customers, err := customerRepo.GetCustomers(ctx)
+	assert.NoError(t, err)
+	for _, c := range *customers {
+		if c.Cuit == "27-31234567-3" {
+			assert.Equal(t, []string{"30-98765432-1"}, c.BankCuits)
+		}
+	}
+}
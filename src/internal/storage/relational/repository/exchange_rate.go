@@ -0,0 +1,86 @@
+package relational_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRelationalRepository creates a new instance of ExchangeRateRepositoryGORM
+func NewExchangeRateRelationalRepository(db *gorm.DB) storage.IExchangeRateStorage {
+	return &ExchangeRateRepositoryGORM{db: db}
+}
+
+// dateColumn is the day of an exchange rate, quoted by GORM as `date` is a keyword in every dialect.
+var dateColumn = clause.Column{Name: "date"}
+
+// SaveExchangeRates stores exchange rates, replacing the rate of a currency already stored for the same day.
+func (r *ExchangeRateRepositoryGORM) SaveExchangeRates(ctx context.Context, rates []models.ExchangeRate) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rate := range rates {
+			entity := entities.ToExchangeRateEntity(&rate)
+			if err := tx.Where(&entities.ExchangeRateEntitySQL{Currency: entity.Currency, Date: entity.Date}).
+				Assign(map[string]interface{}{"rate": entity.Rate}).
+				FirstOrCreate(entity).Error; err != nil {
+				return fmt.Errorf("error saving %s exchange rate of %s: %v", rate.Currency, entity.Date.Format(time.DateOnly), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("%d exchange rates saved", len(rates))
+	return nil
+}
+
+// GetExchangeRates retrieves the rates of a currency from one day to another, both included, ordered by day.
+func (r *ExchangeRateRepositoryGORM) GetExchangeRates(ctx context.Context, currency models.Currency, from time.Time, to time.Time) (*[]models.ExchangeRate, error) {
+	var rateEntities []entities.ExchangeRateEntitySQL
+	if err := r.db.WithContext(ctx).
+		Where(&entities.ExchangeRateEntitySQL{Currency: string(currency)}).
+		Where(clause.Gte{Column: dateColumn, Value: models.RateDay(from)}).
+		Where(clause.Lte{Column: dateColumn, Value: models.RateDay(to)}).
+		Order(clause.OrderByColumn{Column: dateColumn}).
+		Find(&rateEntities).Error; err != nil {
+		return nil, fmt.Errorf("error finding %s exchange rates: %v", currency, err)
+	}
+
+	rates := []models.ExchangeRate{}
+	for _, entity := range rateEntities {
+		rates = append(rates, *entities.ToExchangeRate(&entity))
+	}
+	return &rates, nil
+}
+
+// GetExchangeRate retrieves the latest rate of a currency on or before a day.
+func (r *ExchangeRateRepositoryGORM) GetExchangeRate(ctx context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error) {
+	day := models.RateDay(date)
+
+	var entity entities.ExchangeRateEntitySQL
+	if err := r.db.WithContext(ctx).
+		Where(&entities.ExchangeRateEntitySQL{Currency: string(currency)}).
+		Where(clause.Lte{Column: dateColumn, Value: day}).
+		Order(clause.OrderByColumn{Column: dateColumn, Desc: true}).
+		First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no %s exchange rate on or before %s: %w", currency, day.Format(time.DateOnly), storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error finding %s exchange rate for %s: %v", currency, day.Format(time.DateOnly), err)
+	}
+
+	return entities.ToExchangeRate(&entity), nil
+}
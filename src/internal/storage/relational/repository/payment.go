@@ -0,0 +1,221 @@
+package relational_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type PaymentRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewPaymentRelationalRepository creates a new instance of PaymentRepositoryGORM
+func NewPaymentRelationalRepository(db *gorm.DB) storage.IPaymentStorage {
+	return &PaymentRepositoryGORM{db: db}
+}
+
+// RecordPayment stores a payment of a payment summary and adds its allocations to the paid amounts of the billed
+// quotas and single-payment purchases. The code is unique among payments, enforced by the unique index on its column.
+func (r *PaymentRepositoryGORM) RecordPayment(ctx context.Context, payment models.Payment) (*models.Payment, error) {
+	db := r.db.WithContext(ctx)
+
+	summary, err := findPaymentSummary(db, payment.CardNumber, payment.Month, payment.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := &entities.PaymentEntitySQL{
+		Code:             payment.Code,
+		CardID:           summary.CardID,
+		PaymentSummaryID: summary.ID,
+		Amount:           payment.Amount,
+		PaymentDate:      payment.PaymentDate,
+	}
+	var billedAmounts []models.Money
+	for _, allocation := range payment.Allocations {
+		billed, err := findBilledItem(summary, allocation)
+		if err != nil {
+			return nil, err
+		}
+		billed.Amount = allocation.Amount
+		entity.Allocations = append(entity.Allocations, *billed)
+		billedAmounts = append(billedAmounts, allocation.BilledAmount)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Card", "PaymentSummary").Create(entity).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("payment %s already exists: %w", payment.Code, storage.ErrAlreadyExists)
+			}
+			return fmt.Errorf("error inserting payment %s: %v", payment.Code, err)
+		}
+		return allocatePayment(tx, summary.ID, entity.Allocations, billedAmounts, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Payment %s of %s recorded for card %s in %02d/%d", payment.Code, payment.Amount, payment.CardNumber, payment.Month, payment.Year)
+	return findPayment(db, payment.Code)
+}
+
+// GetPayments retrieves the payments of the summary of a card for a month, ordered by payment date.
+func (r *PaymentRepositoryGORM) GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error) {
+	db := r.db.WithContext(ctx)
+
+	summary, err := findPaymentSummary(db, cardNumber, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentEntities []entities.PaymentEntitySQL
+	if err := preloadPayment(db).
+		Where(&entities.PaymentEntitySQL{PaymentSummaryID: summary.ID}).
+		Order("payment_date, id").
+		Find(&paymentEntities).Error; err != nil {
+		return nil, fmt.Errorf("error finding payments of card %s for %02d/%d: %v", cardNumber, month, year, err)
+	}
+
+	payments := []models.Payment{}
+	for _, entity := range paymentEntities {
+		payments = append(payments, *entities.ToPayment(&entity))
+	}
+	return &payments, nil
+}
+
+// GetPaymentSummaries retrieves the stored payment summaries of a card, ordered by year and month.
+func (r *PaymentRepositoryGORM) GetPaymentSummaries(ctx context.Context, cardNumber string) (*[]models.PaymentSummary, error) {
+	db := r.db.WithContext(ctx)
+
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaryEntities []entities.PaymentSummaryEntitySQL
+	if err := preloadPaymentSummary(db).
+		Where(&entities.PaymentSummaryEntitySQL{CardID: card.ID}).
+		Order("year, month").
+		Find(&summaryEntities).Error; err != nil {
+		return nil, fmt.Errorf("error finding payment summaries of card %s: %v", cardNumber, err)
+	}
+
+	summaries := []models.PaymentSummary{}
+	for _, entity := range summaryEntities {
+		summaries = append(summaries, *entities.ToPaymentSummary(&entity))
+	}
+	return &summaries, nil
+}
+
+// findPaymentSummary retrieves the payment summary of a card for a month, with its billed single-payment purchases and
+// quotas preloaded, or a wrapped storage.ErrNotFound.
+func findPaymentSummary(db *gorm.DB, cardNumber string, month int, year int) (*entities.PaymentSummaryEntitySQL, error) {
+	card, err := findCardByNumber(db, cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary entities.PaymentSummaryEntitySQL
+	if err := db.Preload("SinglePayments").
+		Preload("Quotas.PurchaseMonthlyPaymentsEntity").
+		Where(&entities.PaymentSummaryEntitySQL{CardID: card.ID, Month: month, Year: year}).
+		First(&summary).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no payment summary for card %s in %02d/%d: %w", cardNumber, month, year, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error finding payment summary of card %s for %02d/%d: %v", cardNumber, month, year, err)
+	}
+	return &summary, nil
+}
+
+// findBilledItem returns an allocation referencing the quota or single-payment purchase of a summary that an allocation
+// names, or a wrapped storage.ErrNotFound.
+func findBilledItem(summary *entities.PaymentSummaryEntitySQL, allocation models.PaymentAllocation) (*entities.PaymentAllocationEntitySQL, error) {
+	if allocation.PurchaseDate == nil {
+		for _, quota := range summary.Quotas {
+			if quota.PurchaseMonthlyPaymentsEntity.PurchaseEntity.PaymentVoucher == allocation.PaymentVoucher && quota.Number == allocation.QuotaNumber {
+				return &entities.PaymentAllocationEntitySQL{QuotaID: &quota.ID}, nil
+			}
+		}
+		return nil, fmt.Errorf("summary %s bills no quota %d of purchase %s: %w", summary.Code, allocation.QuotaNumber, allocation.PaymentVoucher, storage.ErrNotFound)
+	}
+
+	for _, purchase := range summary.SinglePayments {
+		if purchase.PurchaseEntity.PaymentVoucher == allocation.PaymentVoucher && purchase.PurchaseEntity.CreatedAt.Equal(*allocation.PurchaseDate) {
+			return &entities.PaymentAllocationEntitySQL{PurchaseSinglePaymentID: &purchase.ID}, nil
+		}
+	}
+	return nil, fmt.Errorf("summary %s bills no purchase %s of %s: %w", summary.Code, allocation.PaymentVoucher, allocation.PurchaseDate.Format("2006-01-02"), storage.ErrNotFound)
+}
+
+// allocatePayment adds the amounts of allocations, times sign, to the paid amounts of the quotas and single-payment
+// purchases they reference. The installment purchases of the quotas and the summary are touched as well, so that the
+// replication picks up the paid amounts it copies from them.
+//
+// When billedAmounts is given, each paid amount is only updated if it stays within the billed amount of its allocation.
+// The condition is checked by the UPDATE itself, which locks the row, so concurrent payments cannot both pass it; an
+// item left with too little to pay is reported as a wrapped storage.ErrAlreadyExists.
+func allocatePayment(tx *gorm.DB, summaryID uint, allocations []entities.PaymentAllocationEntitySQL, billedAmounts []models.Money, sign models.Money) error {
+	now := time.Now()
+	for i, allocation := range allocations {
+		var item *gorm.DB
+		if allocation.QuotaID != nil {
+			item = tx.Model(&entities.QuotaEntitySQL{}).Where("id = ?", *allocation.QuotaID)
+		} else {
+			item = tx.Model(&entities.PurchaseSinglePaymentEntitySQL{}).Where("id = ?", *allocation.PurchaseSinglePaymentID)
+		}
+		// The bound is computed beforehand: adding a DECIMAL column and a parameter is done in floating point by some databases
+		if billedAmounts != nil {
+			item = item.Where("paid_amount <= ?", billedAmounts[i]-allocation.Amount)
+		}
+
+		result := item.Update("paid_amount", gorm.Expr("paid_amount + ?", sign*allocation.Amount))
+		if result.Error != nil {
+			return fmt.Errorf("error updating paid amounts: %v", result.Error)
+		}
+		if billedAmounts != nil && result.RowsAffected == 0 {
+			return fmt.Errorf("allocation of %s exceeds what is left to pay of the billed item: %w", allocation.Amount, storage.ErrAlreadyExists)
+		}
+		if allocation.QuotaID != nil {
+			purchaseID := tx.Model(&entities.QuotaEntitySQL{}).Select("purchase_monthly_payments_entity_id").Where("id = ?", *allocation.QuotaID)
+			if err := tx.Model(&entities.PurchaseMonthlyPaymentsEntitySQL{}).Where("id = (?)", purchaseID).Update("updated_at", now).Error; err != nil {
+				return fmt.Errorf("error updating paid amounts: %v", err)
+			}
+		}
+	}
+	if err := tx.Model(&entities.PaymentSummaryEntitySQL{}).Where("id = ?", summaryID).Update("updated_at", now).Error; err != nil {
+		return fmt.Errorf("error updating payment summary: %v", err)
+	}
+	return nil
+}
+
+// preloadPayment preloads the card, summary and allocated items of the payments queried.
+func preloadPayment(db *gorm.DB) *gorm.DB {
+	return db.Preload("Card").
+		Preload("PaymentSummary").
+		Preload("Allocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).
+		Preload("Allocations.Quota.PurchaseMonthlyPaymentsEntity").
+		Preload("Allocations.PurchaseSinglePayment")
+}
+
+// findPayment retrieves a payment by its code or a wrapped storage.ErrNotFound.
+func findPayment(db *gorm.DB, code string) (*models.Payment, error) {
+	var payment entities.PaymentEntitySQL
+	if err := preloadPayment(db).Where("code = ?", code).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("could not find payment with code %s: %w", code, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error finding payment %s: %v", code, err)
+	}
+	return entities.ToPayment(&payment), nil
+}
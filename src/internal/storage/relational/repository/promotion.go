@@ -1,7 +1,9 @@
 package relational_repository
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
@@ -9,6 +11,7 @@ import (
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PromotionRepositoryGORM struct {
@@ -20,13 +23,15 @@ func NewPromotionRelationRepository(db *gorm.DB) storage.IPromotionStorage {
 	return &PromotionRepositoryGORM{db: db}
 }
 
-func (r *PromotionRepositoryGORM) GetAvailablePromotionsByStoreAndDateRange(cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+func (r *PromotionRepositoryGORM) GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	db := r.db.WithContext(ctx)
+
 	var promotionsDiscount []models.Discount
 	var promotionsFinancing []models.Financing
 
 	// Search for DiscountEntity within the date range and matching CUIT
 	var discounts []entities.DiscountEntitySQL
-	if err := r.db.Where("cuit_store = ? AND is_deleted = ? AND ((validity_start_date >= ? AND validity_end_date <= ?) OR (validity_start_date <= ? AND validity_end_date >= ?))",
+	if err := db.Where("cuit_store = ? AND is_deleted = ? AND ((validity_start_date >= ? AND validity_end_date <= ?) OR (validity_start_date <= ? AND validity_end_date >= ?))",
 		cuit, false, startDate, endDate, startDate, endDate).Find(&discounts).Error; err != nil {
 		logger.Info("Error finding DiscountEntity with CUIT %s between %v and %v: %v", cuit, startDate, endDate, err)
 		return nil, nil, err
@@ -34,7 +39,7 @@ func (r *PromotionRepositoryGORM) GetAvailablePromotionsByStoreAndDateRange(cuit
 
 	// Search for FinancingEntity within the date range and matching CUIT
 	var financings []entities.FinancingEntitySQL
-	if err := r.db.Where("cuit_store = ? AND is_deleted = ? AND ((validity_start_date >= ? AND validity_end_date <= ?) OR (validity_start_date <= ? AND validity_end_date >= ?))",
+	if err := db.Where("cuit_store = ? AND is_deleted = ? AND ((validity_start_date >= ? AND validity_end_date <= ?) OR (validity_start_date <= ? AND validity_end_date >= ?))",
 		cuit, false, startDate, endDate, startDate, endDate).Find(&financings).Error; err != nil {
 		logger.Info("Error finding DiscountEntity with CUIT %s between %v and %v: %v", cuit, startDate, endDate, err)
 		return nil, nil, err
@@ -51,8 +56,11 @@ func (r *PromotionRepositoryGORM) GetAvailablePromotionsByStoreAndDateRange(cuit
 	return &promotionsFinancing, &promotionsDiscount, nil
 }
 
-// GetMostUsedDiscountPromotion retrieves the most used discount promotion based on its usage in single and monthly payments.
-func (r *PromotionRepositoryGORM) GetMostUsedPromotion() (interface{}, error) {
+// GetMostUsedPromotion retrieves the promotion whose code is used most often as a payment voucher, counting
+// single and monthly purchases together. Ties are broken by the lowest code.
+func (r *PromotionRepositoryGORM) GetMostUsedPromotion(ctx context.Context) (interface{}, error) {
+	db := r.db.WithContext(ctx)
+
 	var result entities.PaymentVoucherCountSQL
 
 	query := `
@@ -62,25 +70,33 @@ func (r *PromotionRepositoryGORM) GetMostUsedPromotion() (interface{}, error) {
 		FROM
 			(
 			SELECT
-				month.payment_voucher
+				monthly.payment_voucher
 			FROM
-				PURCHASE_MONTHLY_PAYMENTS month
+				? monthly
 			UNION ALL
 			SELECT
 				single.payment_voucher
 			FROM
-				PURCHASE_SINGLE_PAYMENTS single
-			) as payment_voucher
+				? single
+			) AS vouchers
 		GROUP BY
 			payment_voucher
 		ORDER BY
-			total_repeticiones DESC
-		LIMIT 1;
+			total_repeticiones DESC,
+			payment_voucher
+		LIMIT 1
 		`
 
-	r.db.Raw(query).Scan(&result)
+	monthly := clause.Table{Name: entities.PurchaseMonthlyPaymentsEntitySQL{}.TableName()}
+	single := clause.Table{Name: entities.PurchaseSinglePaymentEntitySQL{}.TableName()}
+	if err := db.Raw(query, monthly, single).Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("error counting promotion usage: %v", err)
+	}
+	if result.PaymentVoucher == "" {
+		return nil, fmt.Errorf("no purchases to find the most used promotion: %w", storage.ErrNotFound)
+	}
 
-	promotion, err := findPromotionByCode(r.db, result.PaymentVoucher)
+	promotion, err := findPromotionByCode(db, result.PaymentVoucher)
 	if err != nil {
 		return nil, err
 	}
@@ -88,17 +104,217 @@ func (r *PromotionRepositoryGORM) GetMostUsedPromotion() (interface{}, error) {
 	return promotion, nil
 }
 
+// GetApplicablePromotions retrieves the non-deleted promotions of a bank for a store that are valid on the given date.
+func (r *PromotionRepositoryGORM) GetApplicablePromotions(ctx context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	db := r.db.WithContext(ctx)
+
+	promotionsDiscount := []models.Discount{}
+	promotionsFinancing := []models.Financing{}
+
+	bankIDs := db.Model(&entities.BankEntitySQL{}).Select("id").Where("cuit = ?", bankCuit)
+
+	var discounts []entities.DiscountEntitySQL
+	if err := db.Where("bank_id IN (?) AND cuit_store = ? AND is_deleted = ? AND validity_start_date <= ? AND validity_end_date >= ?",
+		bankIDs, storeCuit, false, date, date).Find(&discounts).Error; err != nil {
+		logger.Error("Error finding applicable discounts for bank %s and store %s on %v: %v", bankCuit, storeCuit, date, err)
+		return nil, nil, err
+	}
+
+	var financings []entities.FinancingEntitySQL
+	if err := db.Where("bank_id IN (?) AND cuit_store = ? AND is_deleted = ? AND validity_start_date <= ? AND validity_end_date >= ?",
+		bankIDs, storeCuit, false, date, date).Find(&financings).Error; err != nil {
+		logger.Error("Error finding applicable financings for bank %s and store %s on %v: %v", bankCuit, storeCuit, date, err)
+		return nil, nil, err
+	}
+
+	for _, discount := range discounts {
+		promotionsDiscount = append(promotionsDiscount, *entities.ToDiscount(&discount))
+	}
+	for _, financing := range financings {
+		promotionsFinancing = append(promotionsFinancing, *entities.ToFinancing(&financing))
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+func (r *PromotionRepositoryGORM) GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error) {
+	db := r.db.WithContext(ctx)
+
+	var discount entities.DiscountEntitySQL
+	err := db.Preload("Bank").Where("code = ?", code).First(&discount).Error
+	if err == nil {
+		return &models.PromotionDetail{Type: models.PromotionTypeDiscount, IsDeleted: discount.IsDeleted, Discount: entities.ToDiscount(&discount)}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error finding discount promotion %s: %v", code, err)
+	}
+
+	var financing entities.FinancingEntitySQL
+	err = db.Preload("Bank").Where("code = ?", code).First(&financing).Error
+	if err == nil {
+		return &models.PromotionDetail{Type: models.PromotionTypeFinancing, IsDeleted: financing.IsDeleted, Financing: entities.ToFinancing(&financing)}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error finding financing promotion %s: %v", code, err)
+	}
+
+	return nil, fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// GetBankPromotions retrieves the promotions of a bank that have the given status on the given date.
+func (r *PromotionRepositoryGORM) GetBankPromotions(ctx context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error) {
+	db := r.db.WithContext(ctx)
+
+	bank, err := findBankByCuit(db, bankCuit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	promotionsDiscount := []models.Discount{}
+	promotionsFinancing := []models.Financing{}
+
+	var discounts []entities.DiscountEntitySQL
+	if err := db.Preload("Bank").Where("bank_id = ?", bank.ID).Scopes(promotionStatusScope(status, date)).
+		Order("validity_start_date, code").Find(&discounts).Error; err != nil {
+		return nil, nil, fmt.Errorf("error finding %s discounts of bank %s: %v", status, bankCuit, err)
+	}
+
+	var financings []entities.FinancingEntitySQL
+	if err := db.Preload("Bank").Where("bank_id = ?", bank.ID).Scopes(promotionStatusScope(status, date)).
+		Order("validity_start_date, code").Find(&financings).Error; err != nil {
+		return nil, nil, fmt.Errorf("error finding %s financings of bank %s: %v", status, bankCuit, err)
+	}
+
+	for _, discount := range discounts {
+		promotionsDiscount = append(promotionsDiscount, *entities.ToDiscount(&discount))
+	}
+	for _, financing := range financings {
+		promotionsFinancing = append(promotionsFinancing, *entities.ToFinancing(&financing))
+	}
+
+	return &promotionsFinancing, &promotionsDiscount, nil
+}
+
+// UpdatePromotion applies the given changes to a promotion. Changes that do not apply to its type are ignored.
+func (r *PromotionRepositoryGORM) UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) error {
+	db := r.db.WithContext(ctx)
+
+	changes := map[string]interface{}{}
+	if update.PromotionTitle != nil {
+		changes["promotion_title"] = *update.PromotionTitle
+	}
+	if update.Comments != nil {
+		changes["comments"] = *update.Comments
+	}
+
+	discountChanges := map[string]interface{}{}
+	if update.DiscountPercentage != nil {
+		discountChanges["discount_percentage"] = *update.DiscountPercentage
+	}
+	if update.PriceCap != nil {
+		discountChanges["price_cap"] = *update.PriceCap
+	}
+	if update.OnlyCash != nil {
+		discountChanges["only_cash"] = *update.OnlyCash
+	}
+
+	financingChanges := map[string]interface{}{}
+	if update.NumberOfQuotas != nil {
+		financingChanges["number_of_quotas"] = *update.NumberOfQuotas
+	}
+	if update.Interest != nil {
+		financingChanges["interest"] = *update.Interest
+	}
+
+	updated, err := updatePromotionRow(db, &entities.DiscountEntitySQL{}, code, mergeChanges(changes, discountChanges))
+	if err != nil || updated {
+		return err
+	}
+	updated, err = updatePromotionRow(db, &entities.FinancingEntitySQL{}, code, mergeChanges(changes, financingChanges))
+	if err != nil || updated {
+		return err
+	}
+
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// RestorePromotion clears the logical delete of a promotion.
+func (r *PromotionRepositoryGORM) RestorePromotion(ctx context.Context, code string) error {
+	db := r.db.WithContext(ctx)
+
+	changes := map[string]interface{}{"is_deleted": false}
+
+	updated, err := updatePromotionRow(db, &entities.DiscountEntitySQL{}, code, changes)
+	if err != nil || updated {
+		return err
+	}
+	updated, err = updatePromotionRow(db, &entities.FinancingEntitySQL{}, code, changes)
+	if err != nil || updated {
+		return err
+	}
+
+	return fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
+}
+
+// promotionStatusScope restricts a promotion query to the promotions with the given status on the given date.
+func promotionStatusScope(status models.PromotionStatus, date time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch status {
+		case models.PromotionStatusDeleted:
+			return db.Where("is_deleted = ?", true)
+		case models.PromotionStatusExpired:
+			return db.Where("is_deleted = ? AND validity_end_date < ?", false, date)
+		default:
+			return db.Where("is_deleted = ? AND validity_end_date >= ?", false, date)
+		}
+	}
+}
+
+// updatePromotionRow applies the changes to the promotion with the given code in the table of the model.
+// It reports whether such a promotion exists.
+func updatePromotionRow(db *gorm.DB, model interface{}, code string, changes map[string]interface{}) (bool, error) {
+	var count int64
+	if err := db.Model(model).Where("code = ?", code).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error finding promotion %s: %v", code, err)
+	}
+	if count == 0 {
+		return false, nil
+	}
+	if len(changes) == 0 {
+		return true, nil
+	}
+
+	if err := db.Model(model).Where("code = ?", code).Updates(changes).Error; err != nil {
+		return true, fmt.Errorf("error updating promotion %s: %v", code, err)
+	}
+	logger.Info("Promotion %s updated: %v", code, changes)
+	return true, nil
+}
+
+func mergeChanges(common map[string]interface{}, specific map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(common)+len(specific))
+	for column, value := range common {
+		merged[column] = value
+	}
+	for column, value := range specific {
+		merged[column] = value
+	}
+	return merged
+}
+
+// findPromotionByCode retrieves the financing or discount with the given code as a *models.Financing or *models.Discount.
 func findPromotionByCode(db *gorm.DB, code string) (interface{}, error) {
 	var discountPromo entities.DiscountEntitySQL
 	var financingPromo entities.FinancingEntitySQL
 
-	if err := db.Where("code = ?", code).First(&financingPromo).Error; err == nil {
-		return financingPromo, nil
+	if err := db.Preload("Bank").Where("code = ?", code).First(&financingPromo).Error; err == nil {
+		return entities.ToFinancing(&financingPromo), nil
 	}
 
-	if err := db.Where("code = ?", code).First(&discountPromo).Error; err == nil {
-		return discountPromo, nil
+	if err := db.Preload("Bank").Where("code = ?", code).First(&discountPromo).Error; err == nil {
+		return entities.ToDiscount(&discountPromo), nil
 	}
 
-	return nil, errors.New("promotion not found with the provided code")
+	return nil, fmt.Errorf("could not find promotion with code %s: %w", code, storage.ErrNotFound)
 }
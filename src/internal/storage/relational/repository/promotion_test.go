@@ -1,17 +1,20 @@
 package relational_repository
 
 import (
+	"context"
 	"log"
 	"testing"
 	"time"
 
-	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 	mysql "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/testutils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -35,7 +38,7 @@ func TestGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
 
 	promotionRepo := NewPromotionRelationRepository(database)
 
-	financingPromotions, discountPromotions, err := promotionRepo.GetAvailablePromotionsByStoreAndDateRange(testStore, startDate, endDate)
+	financingPromotions, discountPromotions, err := promotionRepo.GetAvailablePromotionsByStoreAndDateRange(ctx, testStore, startDate, endDate)
 
 	if err != nil {
 		panic(err)
@@ -46,6 +49,7 @@ func TestGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
 }
 
 func TestGetMostUsedPromotion(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -65,16 +69,128 @@ func TestGetMostUsedPromotion(t *testing.T) {
 	// Test Financing Promotion
 	promotionRepo := NewPromotionRelationRepository(database)
 
-	mostUsed, err := promotionRepo.GetMostUsedPromotion()
+	mostUsed, err := promotionRepo.GetMostUsedPromotion(ctx)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
 	switch p := mostUsed.(type) {
-	case entities.DiscountEntitySQL:
+	case *models.Discount:
 		log.Fatalf("Error")
-	case entities.FinancingEntitySQL:
-		assert.Equal(t, p.PromotionEntitySQL.Code, "PV20241001")
+	case *models.Financing:
+		assert.Equal(t, p.Code, "PV20241001")
 	default:
 		log.Fatalf("Error")
 	}
 }
+
+func TestGetApplicablePromotions(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	promotionRepo := NewPromotionRelationRepository(database)
+	purchaseDate := time.Date(2024, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	// Deleted (SUMMERSALE2024) and expired (SPRINGDEAL2024) promotions are excluded
+	financingPromotions, discountPromotions, err := promotionRepo.GetApplicablePromotions(ctx, "30-12345678-9", "20-98765432-1", purchaseDate)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*financingPromotions))
+	assert.Equal(t, "PROMO123", (*financingPromotions)[0].Code)
+	assert.Equal(t, 1, len(*discountPromotions))
+	assert.Equal(t, "WINTERSALE2024", (*discountPromotions)[0].Code)
+
+	// Promotions belong to the bank that issued them
+	financingPromotions, discountPromotions, err = promotionRepo.GetApplicablePromotions(ctx, "30-98765432-1", "20-98765432-1", purchaseDate)
+	assert.NoError(t, err)
+	assert.Empty(t, *financingPromotions)
+	assert.Empty(t, *discountPromotions)
+}
+
+func TestPromotionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testutils.InitTestSetup()
+
+	// Use the MySQL connection from mysql.go
+	dsn := testutils.DSN
+	database, err := mysql.NewMySQLDB(dsn, true)
+	if err != nil {
+		log.Fatalf("Error initializing database: %v", err)
+	}
+	defer mysql.CloseDB(database)
+
+	// Insert Data
+	err = mysql.ExecuteSQLFile(database, "../insert.sql")
+	if err != nil {
+		log.Fatalf("Failed to execute SQL file: %v", err)
+	}
+
+	promotionRepo := NewPromotionRelationRepository(database)
+	date := time.Date(2024, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	// Promotions of the bank by status
+	financingPromotions, discountPromotions, err := promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusActive, date)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(*financingPromotions))
+	assert.Equal(t, 1, len(*discountPromotions))
+	assert.Equal(t, "WINTERSALE2024", (*discountPromotions)[0].Code)
+
+	_, discountPromotions, err = promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusExpired, date)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*discountPromotions))
+	assert.Equal(t, "SPRINGDEAL2024", (*discountPromotions)[0].Code)
+
+	_, discountPromotions, err = promotionRepo.GetBankPromotions(ctx, "30-12345678-9", models.PromotionStatusDeleted, date)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*discountPromotions))
+	assert.Equal(t, "SUMMERSALE2024", (*discountPromotions)[0].Code)
+
+	_, _, err = promotionRepo.GetBankPromotions(ctx, "30-00000000-0", models.PromotionStatusActive, date)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Lookup by code, including deleted promotions
+	detail, err := promotionRepo.GetPromotionByCode(ctx, "SUMMERSALE2024")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PromotionTypeDiscount, detail.Type)
+	assert.True(t, detail.IsDeleted)
+	assert.Equal(t, "30-12345678-9", detail.Discount.Bank.Cuit)
+
+	detail, err = promotionRepo.GetPromotionByCode(ctx, "PROMO123")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PromotionTypeFinancing, detail.Type)
+
+	_, err = promotionRepo.GetPromotionByCode(ctx, "MISSING")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Update and restore
+	title, interest := "Summer Sale 2024 - extended", models.MustParsePercentage("2.5")
+	err = promotionRepo.UpdatePromotion(ctx, "PROMO123", models.PromotionUpdate{PromotionTitle: &title, Interest: &interest})
+	assert.NoError(t, err)
+
+	detail, err = promotionRepo.GetPromotionByCode(ctx, "PROMO123")
+	assert.NoError(t, err)
+	assert.Equal(t, title, detail.Financing.PromotionTitle)
+	assert.Equal(t, interest, detail.Financing.Interest)
+
+	err = promotionRepo.RestorePromotion(ctx, "SUMMERSALE2024")
+	assert.NoError(t, err)
+
+	detail, err = promotionRepo.GetPromotionByCode(ctx, "SUMMERSALE2024")
+	assert.NoError(t, err)
+	assert.False(t, detail.IsDeleted)
+
+	assert.ErrorIs(t, promotionRepo.RestorePromotion(ctx, "MISSING"), storage.ErrNotFound)
+	assert.ErrorIs(t, promotionRepo.UpdatePromotion(ctx, "MISSING", models.PromotionUpdate{PromotionTitle: &title}), storage.ErrNotFound)
+}
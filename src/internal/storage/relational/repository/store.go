@@ -1,11 +1,15 @@
 package relational_repository
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StoreRepositoryGORM struct {
@@ -17,25 +21,50 @@ func NewStoreRelationalRepository(db *gorm.DB) storage.IStoreStorage {
 	return &StoreRepositoryGORM{db: db}
 }
 
-func (r *StoreRepositoryGORM) GetStoreWithHighestRevenueByMonth(month int, year int) (models.StoreDTO, error) {
+func (r *StoreRepositoryGORM) GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error) {
+	db := r.db.WithContext(ctx)
+
 	var result models.StoreDTO
 
-	query := "SELECT store as name, cuit_store as cuit, SUM(final_amount) AS total_amount " +
-		"FROM (" +
-		"SELECT month.store, month.cuit_store, month.final_amount " +
-		"FROM PURCHASE_MONTHLY_PAYMENTS month " +
-		"WHERE MONTH(month.created_at) = %d AND YEAR(month.created_at) = %d " +
-		"UNION ALL " +
-		"SELECT single.store, single.cuit_store, single.final_amount " +
-		"FROM PURCHASE_SINGLE_PAYMENTS single " +
-		"WHERE MONTH(single.created_at) = %d AND YEAR(single.created_at) = %d" +
-		") AS combined_payments " +
-		"GROUP BY store, cuit_store " +
-		"ORDER BY total_amount DESC;"
-
-	formattedQuery := fmt.Sprintf(query, month, year, month, year)
-
-	r.db.Raw(formattedQuery).Scan(&result)
+	// The month is matched as a date range, which every SQL dialect compares the same way
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, 0)
+
+	query := `
+		SELECT
+			store AS name,
+			cuit_store AS cuit,
+			SUM(final_amount) AS total_amount
+		FROM
+			(
+			SELECT
+				monthly.store, monthly.cuit_store, monthly.final_amount
+			FROM
+				? monthly
+			WHERE
+				monthly.created_at >= ? AND monthly.created_at < ?
+			UNION ALL
+			SELECT
+				single.store, single.cuit_store, single.final_amount
+			FROM
+				? single
+			WHERE
+				single.created_at >= ? AND single.created_at < ?
+			) AS combined_payments
+		GROUP BY
+			store, cuit_store
+		ORDER BY
+			total_amount DESC,
+			cuit_store
+		LIMIT 1
+		`
+
+	// The table names are bound as identifiers so that every dialect quotes them
+	monthly := clause.Table{Name: entities.PurchaseMonthlyPaymentsEntitySQL{}.TableName()}
+	single := clause.Table{Name: entities.PurchaseSinglePaymentEntitySQL{}.TableName()}
+	if err := db.Raw(query, monthly, startDate, endDate, single, startDate, endDate).Scan(&result).Error; err != nil {
+		return models.StoreDTO{}, fmt.Errorf("error finding the store with the highest revenue in %02d/%d: %v", month, year, err)
+	}
 
 	return result, nil
 }
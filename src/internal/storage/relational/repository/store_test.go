@@ -1,6 +1,7 @@
 package relational_repository
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 )
 
 func TestGetStoreWithHighestRevenueByMonth(t *testing.T) {
+	ctx := context.Background()
 	testutils.InitTestSetup()
 
 	// Use the MySQL connection from mysql.go
@@ -27,7 +29,7 @@ func TestGetStoreWithHighestRevenueByMonth(t *testing.T) {
 
 	storeRepo := NewStoreRelationalRepository(database)
 
-	result, err := storeRepo.GetStoreWithHighestRevenueByMonth(10, 2024)
+	result, err := storeRepo.GetStoreWithHighestRevenueByMonth(ctx, 10, 2024)
 	if err != nil {
 		log.Fatalf("Failed to execute SQL file: %v", err)
 	}
@@ -0,0 +1,55 @@
+package relational_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+func TestSQLiteStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storages {
+		db, err := relational.NewSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"), true)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = relational.CloseDB(db) })
+
+		return storagetest.Storages{
+			Banks:         relational_repository.NewBankRelationalRepository(db),
+			Cards:         relational_repository.NewCardRelationalRepository(db),
+			Promotions:    relational_repository.NewPromotionRelationRepository(db),
+			Stores:        relational_repository.NewStoreRelationalRepository(db),
+			Customers:     relational_repository.NewCustomerRelationalRepository(db),
+			ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(db),
+			Payments:      relational_repository.NewPaymentRelationalRepository(db),
+			Compensation:  relational_repository.NewCompensationRelationalRepository(db),
+		}
+	})
+}
+
+func TestNewSQLDBRejectsUnknownDialect(t *testing.T) {
+	_, err := relational.NewSQLDB("oracle", "", false)
+	require.Error(t, err)
+}
+
+func TestSQLiteRepositoryStopsOnCancelledContext(t *testing.T) {
+	db, err := relational.NewSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = relational_repository.NewBankRelationalRepository(db).GetBanks(ctx)
+	require.ErrorContains(t, err, context.Canceled.Error())
+}
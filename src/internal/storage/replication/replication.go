@@ -0,0 +1,225 @@
+/*
+ * Payment Registration System - Replication
+ * --------------------------------------------------
+ * This file implements the job that replicates the relational database into MongoDB. Every table is read
+ * in batches of rows changed after its checkpoint, ordered by their UpdatedAt watermark and their ID, and
+ * the equivalent documents are upserted by their natural key (CUIT, card number, promotion code, ...).
+ *
+ * The checkpoint of a table is stored in MongoDB after each batch, so an interrupted sync resumes from the
+ * last replicated batch. Writes are upserts, replicating a batch again leaves the same documents.
+ *
+ * UpdatedAt is set when a row is written, not when its transaction commits, so a row may become visible
+ * behind a checkpoint already stored. Every sync starts an overlap window behind the checkpoint of each
+ * table and replicates the rows in it again, catching the rows that committed within that window.
+ *
+ * Created: Mar. 24, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"gorm.io/gorm"
+)
+
+// CheckpointsCollection is the MongoDB collection holding the checkpoint of every replicated table.
+const CheckpointsCollection = "sync_checkpoints"
+
+// DefaultBatchSize is the number of rows of a table read and written at once.
+const DefaultBatchSize = 500
+
+// DefaultOverlap is how far behind its checkpoint the sync of a table starts. It must exceed the time between
+// writing a row and committing it, bounded by the longest request deadline.
+const DefaultOverlap = 5 * time.Minute
+
+// TableReport is the number of rows of a table replicated by a sync.
+type TableReport struct {
+	Table string
+	Rows  int
+}
+
+// checkpoint is the last replicated row of a table. Rows are replicated in (UpdatedAt, ID) order, so every row
+// changed after the checkpoint sorts after it.
+type checkpoint struct {
+	Table     string
+	UpdatedAt time.Time
+	ID        uint
+}
+
+// checkpointDocument stores a checkpoint. The watermark keeps the nanoseconds of the SQL timestamp, which a
+// MongoDB date would truncate to milliseconds.
+type checkpointDocument struct {
+	Table     string    `bson:"_id"`
+	UpdatedAt string    `bson:"updated_at"`
+	ID        int64     `bson:"id"`
+	SyncedAt  time.Time `bson:"synced_at"`
+}
+
+// Syncer replicates the tables of a relational database into a MongoDB database.
+type Syncer struct {
+	source    *gorm.DB
+	target    *mongo.Database
+	ids       idResolver
+	batchSize int
+	overlap   time.Duration
+}
+
+// NewSyncer creates a Syncer reading from source and writing to target, batchSize rows at a time, and
+// replicating again the rows changed up to overlap before the checkpoint of each table.
+func NewSyncer(source *gorm.DB, target *mongo.Database, batchSize int, overlap time.Duration) *Syncer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	return &Syncer{source: source, target: target, ids: mongoIDs{db: target}, batchSize: batchSize, overlap: overlap}
+}
+
+/*
+ * Run
+ * --------------------------------------------------
+ * Replicates every table, in dependency order, from its checkpoint. A full sync discards the checkpoints
+ * first and replicates every row again.
+ *
+ * Params:
+ * - ctx (context.Context): Context of the sync.
+ * - full (bool): Whether to replicate every row instead of the rows changed since the last sync.
+ *
+ * Returns:
+ * - []TableReport: The number of rows replicated per table, up to the failing table on error.
+ * - error: If reading, writing or storing a checkpoint fails.
+ */
+func (s *Syncer) Run(ctx context.Context, full bool) ([]TableReport, error) {
+	if full {
+		if _, err := s.target.Collection(CheckpointsCollection).DeleteMany(ctx, bson.M{}); err != nil {
+			return nil, fmt.Errorf("error discarding the sync checkpoints: %w", err)
+		}
+	}
+
+	reports := []TableReport{}
+	for _, table := range tables {
+		rows, err := s.syncTable(ctx, table)
+		reports = append(reports, TableReport{Table: table.name(), Rows: rows})
+		if err != nil {
+			return reports, fmt.Errorf("error replicating table %s: %w", table.name(), err)
+		}
+	}
+	return reports, nil
+}
+
+// syncTable replicates the rows of a table changed after the overlap window behind its checkpoint, storing the
+// checkpoint after each batch.
+func (s *Syncer) syncTable(ctx context.Context, table replicatedTable) (int, error) {
+	last, err := s.loadCheckpoint(ctx, table.name())
+	if err != nil {
+		return 0, err
+	}
+	after := s.rewind(last)
+
+	total := 0
+	for {
+		next, rows, err := table.replicate(ctx, s, after)
+		if err != nil {
+			return total, err
+		}
+		if rows == 0 {
+			return total, nil
+		}
+
+		if err := s.saveCheckpoint(ctx, next); err != nil {
+			return total, err
+		}
+		total += rows
+		logger.Info("Replicated %d rows of %s, up to %v", total, table.name(), next.UpdatedAt)
+
+		if rows < s.batchSize {
+			return total, nil
+		}
+		after = next
+	}
+}
+
+// rewind returns the position the sync of a table starts from: the start of the overlap window behind its
+// checkpoint, before every row changed within it.
+func (s *Syncer) rewind(last checkpoint) checkpoint {
+	if last.UpdatedAt.IsZero() || s.overlap == 0 {
+		return last
+	}
+	return checkpoint{Table: last.Table, UpdatedAt: last.UpdatedAt.Add(-s.overlap)}
+}
+
+// loadCheckpoint returns the checkpoint of a table, the zero checkpoint if it has never been replicated.
+func (s *Syncer) loadCheckpoint(ctx context.Context, table string) (checkpoint, error) {
+	var document checkpointDocument
+	err := s.target.Collection(CheckpointsCollection).FindOne(ctx, bson.M{"_id": table}).Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return checkpoint{Table: table}, nil
+	}
+	if err != nil {
+		return checkpoint{}, fmt.Errorf("error loading the checkpoint of %s: %w", table, err)
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, document.UpdatedAt)
+	if err != nil {
+		return checkpoint{}, fmt.Errorf("invalid checkpoint of %s: %w", table, err)
+	}
+	return checkpoint{Table: table, UpdatedAt: updatedAt, ID: uint(document.ID)}, nil
+}
+
+// saveCheckpoint stores the checkpoint of a table.
+func (s *Syncer) saveCheckpoint(ctx context.Context, last checkpoint) error {
+	document := checkpointDocument{
+		Table:     last.Table,
+		UpdatedAt: last.UpdatedAt.Format(time.RFC3339Nano),
+		ID:        int64(last.ID),
+		SyncedAt:  time.Now(),
+	}
+	_, err := s.target.Collection(CheckpointsCollection).ReplaceOne(ctx, bson.M{"_id": last.Table}, document, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving the checkpoint of %s: %w", last.Table, err)
+	}
+	return nil
+}
+
+// idResolver finds the ObjectIDs of the documents of a collection by the value of a unique field.
+type idResolver interface {
+	IDs(ctx context.Context, collection string, field string, values []string) (map[string]bson.ObjectID, error)
+}
+
+// mongoIDs resolves ObjectIDs by querying the target database.
+type mongoIDs struct {
+	db *mongo.Database
+}
+
+func (r mongoIDs) IDs(ctx context.Context, collection string, field string, values []string) (map[string]bson.ObjectID, error) {
+	ids := map[string]bson.ObjectID{}
+	if len(values) == 0 {
+		return ids, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1, field: 1})
+	cursor, err := r.db.Collection(collection).Find(ctx, bson.M{field: bson.M{"$in": values}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding %s by %s: %w", collection, field, err)
+	}
+	var documents []bson.M
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", collection, err)
+	}
+
+	for _, document := range documents {
+		value, _ := document[field].(string)
+		id, _ := document["_id"].(bson.ObjectID)
+		ids[value] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,271 @@
+package replication
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false, "")
+	os.Exit(m.Run())
+}
+
+// fakeIDs resolves the values it knows, as if their documents had been replicated.
+type fakeIDs map[string]bson.ObjectID
+
+func (f fakeIDs) IDs(_ context.Context, collection string, _ string, values []string) (map[string]bson.ObjectID, error) {
+	ids := map[string]bson.ObjectID{}
+	for _, value := range values {
+		if id, ok := f[collection+"/"+value]; ok {
+			ids[value] = id
+		}
+	}
+	return ids, nil
+}
+
+// newSource loads the default storage fixture into a SQLite database.
+func newSource(t *testing.T) (*gorm.DB, storagetest.Storages) {
+	db, err := relational.NewSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(db) })
+
+	storages := storagetest.Storages{
+		Banks:         relational_repository.NewBankRelationalRepository(db),
+		Cards:         relational_repository.NewCardRelationalRepository(db),
+		Promotions:    relational_repository.NewPromotionRelationRepository(db),
+		Stores:        relational_repository.NewStoreRelationalRepository(db),
+		Customers:     relational_repository.NewCustomerRelationalRepository(db),
+		ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(db),
+	}
+	require.NoError(t, storagetest.DefaultFixture().Load(storages))
+	return db, storages
+}
+
+// replicatedIDs knows every bank, customer and card of the default fixture.
+func replicatedIDs() fakeIDs {
+	ids := fakeIDs{}
+	fixture := storagetest.DefaultFixture()
+	for _, bank := range fixture.Banks {
+		ids["banks/"+bank.Cuit] = bson.NewObjectID()
+	}
+	for _, customer := range fixture.Customers {
+		ids["customers/"+customer.Cuit] = bson.NewObjectID()
+	}
+	for _, card := range fixture.Cards {
+		ids["cards/"+card.Number] = bson.NewObjectID()
+	}
+	return ids
+}
+
+func findTable[T any](t *testing.T) table[T] {
+	for _, replicated := range tables {
+		if found, ok := replicated.(table[T]); ok {
+			return found
+		}
+	}
+	t.Fatalf("table of %T is not replicated", *new(T))
+	return table[T]{}
+}
+
+func lastCheckpoint[T any](replicated table[T], rows []T) checkpoint {
+	id, updatedAt := replicated.Key(&rows[len(rows)-1])
+	return checkpoint{Table: replicated.Table, UpdatedAt: updatedAt, ID: id}
+}
+
+func TestChangesFollowCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	db, storages := newSource(t)
+	banks := findTable[entities.BankEntitySQL](t)
+
+	first, err := banks.changes(ctx, db, checkpoint{}, 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, storagetest.BankCuit, first[0].Cuit)
+
+	second, err := banks.changes(ctx, db, lastCheckpoint(banks, first), 10)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, storagetest.OtherBankCuit, second[0].Cuit)
+
+	none, err := banks.changes(ctx, db, lastCheckpoint(banks, second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	// An update moves the bank after the checkpoint
+	_, err = storages.Banks.UpdateBank(ctx, storagetest.BankCuit, models.Bank{Name: "Banco Renombrado", Address: "Av. Corrientes 1000", Telephone: "0800-111-1111"})
+	require.NoError(t, err)
+	updated, err := banks.changes(ctx, db, lastCheckpoint(banks, second), 10)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "Banco Renombrado", updated[0].Name)
+}
+
+func TestOverlapReplicatesRowsCommittedBehindCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSource(t)
+	banks := findTable[entities.BankEntitySQL](t)
+
+	all, err := banks.changes(ctx, db, checkpoint{}, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	last := lastCheckpoint(banks, all)
+
+	// A bank written before the checkpoint, whose transaction commits after the sync stored it
+	late := entities.BankEntitySQL{Name: "Banco Tardío", Cuit: "30-99999999-9", UpdatedAt: last.UpdatedAt.Add(-time.Minute)}
+	require.NoError(t, db.Create(&late).Error)
+
+	missed, err := banks.changes(ctx, db, last, 10)
+	require.NoError(t, err)
+	assert.Empty(t, missed, "the bank sorts before the checkpoint")
+
+	s := &Syncer{source: db, batchSize: DefaultBatchSize, overlap: DefaultOverlap}
+	rescanned, err := banks.changes(ctx, db, s.rewind(last), 10)
+	require.NoError(t, err)
+	require.Len(t, rescanned, 3, "the overlap window replicates the banks behind the checkpoint again")
+	assert.Equal(t, late.Cuit, rescanned[0].Cuit)
+	assert.Equal(t, last, lastCheckpoint(banks, rescanned), "the checkpoint does not move back")
+
+	// A table never replicated starts from the beginning
+	assert.Equal(t, checkpoint{Table: banks.Table}, s.rewind(checkpoint{Table: banks.Table}))
+}
+
+func TestMembershipChangesMoveCustomerAfterCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	db, storages := newSource(t)
+	customers := findTable[entities.CustomerEntitySQL](t)
+
+	all, err := customers.changes(ctx, db, checkpoint{}, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	require.NoError(t, storages.Customers.RemoveCustomerFromBank(ctx, storagetest.OtherCustomer, storagetest.OtherBankCuit))
+	changed, err := customers.changes(ctx, db, lastCheckpoint(customers, all), 10)
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	assert.Equal(t, storagetest.OtherCustomer, changed[0].Cuit)
+	assert.Len(t, changed[0].Banks, 1)
+}
+
+func TestWritesOfEveryTable(t *testing.T) {
+	ctx := context.Background()
+	db, storages := newSource(t)
+	require.NoError(t, storages.Banks.SaveBillingCycle(ctx, models.BillingCycle{BankCuit: storagetest.BankCuit, ClosingDay: 20, FirstDueDays: 10, SecondDueDays: 5}))
+	_, err := storages.Cards.SavePaymentSummary(ctx, storagetest.CardNumber, models.PaymentSummary{Code: "SUM-2025-03", Month: 3, Year: 2025, TotalPrice: models.MustParseMoney("1350")})
+	require.NoError(t, err)
+
+	s := &Syncer{source: db, ids: replicatedIDs(), batchSize: DefaultBatchSize}
+	written := map[string]int{}
+	for _, replicated := range tables {
+		var writes []write
+		switch replicated := replicated.(type) {
+		case table[entities.BankEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.BillingCycleEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.CustomerEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.CardEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.DiscountEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.FinancingEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.PurchaseSinglePaymentEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.PurchaseMonthlyPaymentsEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.PaymentSummaryEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.ExchangeRateEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		case table[entities.PaymentEntitySQL]:
+			writes = tableWrites(t, s, replicated)
+		default:
+			t.Fatalf("unexpected table %s", replicated.name())
+		}
+		require.NotEmpty(t, writes, replicated.name())
+		written[replicated.name()] = len(writes[0].Models)
+	}
+
+	assert.Equal(t, map[string]int{
+		"BANKS":                     2,
+		"BILLING_CYCLES":            1,
+		"CUSTOMERS":                 2,
+		"CARDS":                     3,
+		"DISCOUNTS":                 2,
+		"FINANCINGS":                2,
+		"PURCHASE_SINGLE_PAYMENTS":  3,
+		"PURCHASE_MONTHLY_PAYMENTS": 2,
+		"PAYMENT_SUMMARIES":         1,
+		"EXCHANGE_RATES":            3,
+		"PAYMENTS":                  0,
+	}, written)
+}
+
+func tableWrites[T any](t *testing.T, s *Syncer, replicated table[T]) []write {
+	rows, err := replicated.changes(context.Background(), s.source, checkpoint{}, s.batchSize)
+	require.NoError(t, err)
+	writes, err := replicated.Writes(context.Background(), s, rows)
+	require.NoError(t, err, replicated.Table)
+	return writes
+}
+
+func TestCustomerWritesResolveMemberships(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSource(t)
+	ids := replicatedIDs()
+	s := &Syncer{source: db, ids: ids, batchSize: DefaultBatchSize}
+
+	customers := findTable[entities.CustomerEntitySQL](t)
+	rows, err := customers.changes(ctx, db, checkpoint{}, 10)
+	require.NoError(t, err)
+	writes, err := customerWrites(ctx, s, rows)
+	require.NoError(t, err)
+	require.Len(t, writes, 2)
+
+	// The second customer is a member of both banks
+	upsert := writes[0].Models[1].(*mongo.UpdateOneModel)
+	assert.Equal(t, bson.M{"cuit": storagetest.OtherCustomer}, upsert.Filter)
+	update := upsert.Update.(bson.M)
+	assert.ElementsMatch(t, []bson.ObjectID{ids["banks/"+storagetest.BankCuit], ids["banks/"+storagetest.OtherBankCuit]}, update["$set"].(bson.M)["banks"])
+	assert.Equal(t, ids["customers/"+storagetest.OtherCustomer], update["$setOnInsert"].(bson.M)["_id"], "an existing customer keeps its ObjectID")
+
+	// Each customer is added to its banks and removed from the others
+	require.Len(t, writes[1].Models, 4)
+	pull := writes[1].Models[3].(*mongo.UpdateManyModel)
+	assert.Equal(t, bson.M{"$pull": bson.M{"customers": ids["customers/"+storagetest.OtherCustomer]}}, pull.Update)
+}
+
+func TestWritesRequireReferencedDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newSource(t)
+	s := &Syncer{source: db, ids: fakeIDs{}, batchSize: DefaultBatchSize}
+
+	customers := findTable[entities.CustomerEntitySQL](t)
+	rows, err := customers.changes(ctx, db, checkpoint{}, 10)
+	require.NoError(t, err)
+	_, err = customerWrites(ctx, s, rows)
+	assert.ErrorIs(t, err, errNotReplicated, "the banks of the customers are not replicated")
+
+	// Banks do not reference other documents
+	banks := findTable[entities.BankEntitySQL](t)
+	bankRows, err := banks.changes(ctx, db, checkpoint{}, 10)
+	require.NoError(t, err)
+	_, err = bankWrites(ctx, s, bankRows)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,563 @@
+/*
+ * Payment Registration System - Replicated Tables
+ * --------------------------------------------------
+ * This file declares the replicated tables, in dependency order, and maps their rows to the writes of the
+ * equivalent MongoDB documents. Documents are matched by their natural key, and the references between
+ * them (customer banks, customer cards, promotion bank) are resolved to the ObjectIDs of the target.
+ *
+ * Created: Mar. 24, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"gorm.io/gorm"
+)
+
+// errNotReplicated is returned when a row references a document that is not in MongoDB yet, e.g. a bank created
+// after the banks were replicated. The batch is replicated again by the next sync.
+var errNotReplicated = errors.New("referenced document is not replicated yet, run the sync again")
+
+// replicatedTable is a relational table replicated into MongoDB.
+type replicatedTable interface {
+	name() string
+	// replicate writes the batch of rows changed after the checkpoint, returning the checkpoint of its last row
+	replicate(ctx context.Context, s *Syncer, after checkpoint) (checkpoint, int, error)
+}
+
+// write is a list of writes to a collection.
+type write struct {
+	Collection string
+	Models     []mongo.WriteModel
+}
+
+// table replicates the rows of type T, whose table has the updated_at and id columns.
+type table[T any] struct {
+	Table  string
+	Query  func(db *gorm.DB) *gorm.DB // Preloads the associations needed by Writes
+	Key    func(row *T) (uint, time.Time)
+	Writes func(ctx context.Context, s *Syncer, rows []T) ([]write, error)
+}
+
+// tables are the replicated tables, every table after the tables it references.
+var tables = []replicatedTable{
+	table[entities.BankEntitySQL]{
+		Table:  entities.BankEntitySQL{}.TableName(),
+		Key:    func(bank *entities.BankEntitySQL) (uint, time.Time) { return bank.ID, bank.UpdatedAt },
+		Writes: bankWrites,
+	},
+	table[entities.BillingCycleEntitySQL]{
+		Table:  entities.BillingCycleEntitySQL{}.TableName(),
+		Query:  func(db *gorm.DB) *gorm.DB { return db.Preload("Bank") },
+		Key:    func(cycle *entities.BillingCycleEntitySQL) (uint, time.Time) { return cycle.ID, cycle.UpdatedAt },
+		Writes: billingCycleWrites,
+	},
+	table[entities.CustomerEntitySQL]{
+		Table:  entities.CustomerEntitySQL{}.TableName(),
+		Query:  func(db *gorm.DB) *gorm.DB { return db.Preload("Banks") },
+		Key:    func(customer *entities.CustomerEntitySQL) (uint, time.Time) { return customer.ID, customer.UpdatedAt },
+		Writes: customerWrites,
+	},
+	table[entities.CardEntitySQL]{
+		Table:  entities.CardEntitySQL{}.TableName(),
+		Query:  func(db *gorm.DB) *gorm.DB { return db.Preload("Bank") },
+		Key:    func(card *entities.CardEntitySQL) (uint, time.Time) { return card.ID, card.UpdatedAt },
+		Writes: cardWrites,
+	},
+	table[entities.DiscountEntitySQL]{
+		Table:  entities.DiscountEntitySQL{}.TableName(),
+		Query:  func(db *gorm.DB) *gorm.DB { return db.Preload("Bank") },
+		Key:    func(discount *entities.DiscountEntitySQL) (uint, time.Time) { return discount.ID, discount.UpdatedAt },
+		Writes: discountWrites,
+	},
+	table[entities.FinancingEntitySQL]{
+		Table: entities.FinancingEntitySQL{}.TableName(),
+		Query: func(db *gorm.DB) *gorm.DB { return db.Preload("Bank") },
+		Key: func(financing *entities.FinancingEntitySQL) (uint, time.Time) {
+			return financing.ID, financing.UpdatedAt
+		},
+		Writes: financingWrites,
+	},
+	table[entities.PurchaseSinglePaymentEntitySQL]{
+		Table: entities.PurchaseSinglePaymentEntitySQL{}.TableName(),
+		Key: func(purchase *entities.PurchaseSinglePaymentEntitySQL) (uint, time.Time) {
+			return purchase.ID, purchase.PurchaseEntity.UpdatedAt
+		},
+		Writes: singlePaymentWrites,
+	},
+	table[entities.PurchaseMonthlyPaymentsEntitySQL]{
+		Table: entities.PurchaseMonthlyPaymentsEntitySQL{}.TableName(),
+		Query: func(db *gorm.DB) *gorm.DB { return db.Preload("Quotas", byQuotaNumber) },
+		Key: func(purchase *entities.PurchaseMonthlyPaymentsEntitySQL) (uint, time.Time) {
+			return purchase.ID, purchase.PurchaseEntity.UpdatedAt
+		},
+		Writes: monthlyPaymentWrites,
+	},
+	table[entities.PaymentSummaryEntitySQL]{
+		Table: entities.PaymentSummaryEntitySQL{}.TableName(),
+		Query: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Card").
+				Preload("Subtotals", byID).
+				Preload("SinglePayments").
+				Preload("MonthlyPayments.Quotas", byQuotaNumber).
+				Preload("Quotas.PurchaseMonthlyPaymentsEntity")
+		},
+		Key: func(summary *entities.PaymentSummaryEntitySQL) (uint, time.Time) {
+			return summary.ID, summary.UpdatedAt
+		},
+		Writes: paymentSummaryWrites,
+	},
+	table[entities.ExchangeRateEntitySQL]{
+		Table:  entities.ExchangeRateEntitySQL{}.TableName(),
+		Key:    func(rate *entities.ExchangeRateEntitySQL) (uint, time.Time) { return rate.ID, rate.UpdatedAt },
+		Writes: exchangeRateWrites,
+	},
+	table[entities.PaymentEntitySQL]{
+		Table: entities.PaymentEntitySQL{}.TableName(),
+		Query: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Card").
+				Preload("PaymentSummary").
+				Preload("Allocations", byID).
+				Preload("Allocations.Quota.PurchaseMonthlyPaymentsEntity").
+				Preload("Allocations.PurchaseSinglePayment")
+		},
+		Key:    func(payment *entities.PaymentEntitySQL) (uint, time.Time) { return payment.ID, payment.UpdatedAt },
+		Writes: paymentWrites,
+	},
+}
+
+func byQuotaNumber(db *gorm.DB) *gorm.DB {
+	return db.Order("number")
+}
+
+func byID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func (t table[T]) name() string {
+	return t.Table
+}
+
+func (t table[T]) replicate(ctx context.Context, s *Syncer, after checkpoint) (checkpoint, int, error) {
+	rows, err := t.changes(ctx, s.source, after, s.batchSize)
+	if err != nil {
+		return after, 0, err
+	}
+	if len(rows) == 0 {
+		return after, 0, nil
+	}
+
+	writes, err := t.Writes(ctx, s, rows)
+	if err != nil {
+		return after, 0, err
+	}
+	for _, w := range writes {
+		if len(w.Models) == 0 {
+			continue
+		}
+		if _, err := s.target.Collection(w.Collection).BulkWrite(ctx, w.Models, options.BulkWrite().SetOrdered(true)); err != nil {
+			return after, 0, fmt.Errorf("error writing %s: %w", w.Collection, err)
+		}
+	}
+
+	id, updatedAt := t.Key(&rows[len(rows)-1])
+	return checkpoint{Table: t.Table, UpdatedAt: updatedAt, ID: id}, len(rows), nil
+}
+
+// changes loads up to limit rows changed after the checkpoint, in (updated_at, id) order.
+func (t table[T]) changes(ctx context.Context, db *gorm.DB, after checkpoint, limit int) ([]T, error) {
+	query := db.WithContext(ctx)
+	if t.Query != nil {
+		query = t.Query(query)
+	}
+	if !after.UpdatedAt.IsZero() || after.ID != 0 {
+		query = query.Where("updated_at > ? OR (updated_at = ? AND id > ?)", after.UpdatedAt, after.UpdatedAt, after.ID)
+	}
+
+	var rows []T
+	if err := query.Order("updated_at").Order("id").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error reading changed rows: %w", err)
+	}
+	return rows, nil
+}
+
+// requireIDs resolves the ObjectIDs of the documents referenced by a batch, failing if any is missing.
+func (s *Syncer) requireIDs(ctx context.Context, collection string, field string, values []string) (map[string]bson.ObjectID, error) {
+	ids, err := s.ids.IDs(ctx, collection, field, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if _, ok := ids[value]; !ok {
+			return nil, fmt.Errorf("%s with %s %s: %w", collection, field, value, errNotReplicated)
+		}
+	}
+	return ids, nil
+}
+
+// idOrNew returns the ObjectID of an existing document, or a new one to insert it with.
+func idOrNew(ids map[string]bson.ObjectID, key string) bson.ObjectID {
+	if id, ok := ids[key]; ok {
+		return id
+	}
+	return bson.NewObjectID()
+}
+
+// upsert matches a document by filter, setting fields and, when it is inserted, onInsert.
+func upsert(filter bson.M, fields bson.M, onInsert bson.M) mongo.WriteModel {
+	return mongo.NewUpdateOneModel().
+		SetFilter(filter).
+		SetUpdate(bson.M{"$set": fields, "$setOnInsert": onInsert}).
+		SetUpsert(true)
+}
+
+// ------------ Writes ------------	//
+
+func bankWrites(_ context.Context, _ *Syncer, banks []entities.BankEntitySQL) ([]write, error) {
+	models := []mongo.WriteModel{}
+	for _, bank := range banks {
+		models = append(models, upsert(
+			bson.M{"cuit": bank.Cuit},
+			bson.M{"name": bank.Name, "address": bank.Address, "telephone": bank.Telephone, "updated_at": bank.UpdatedAt},
+			bson.M{"created_at": bank.CreatedAt},
+		))
+	}
+	return []write{{Collection: "banks", Models: models}}, nil
+}
+
+// billingCycleWrites embeds the billing cycles in the documents of their banks.
+func billingCycleWrites(ctx context.Context, s *Syncer, cycles []entities.BillingCycleEntitySQL) ([]write, error) {
+	cuits := []string{}
+	for _, cycle := range cycles {
+		cuits = append(cuits, cycle.Bank.Cuit)
+	}
+	banks, err := s.requireIDs(ctx, "banks", "cuit", cuits)
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.WriteModel{}
+	for _, cycle := range cycles {
+		billingCycle := entities.BillingCycleEntityNonSQL{
+			ClosingDay:          cycle.ClosingDay,
+			FirstDueDays:        cycle.FirstDueDays,
+			SecondDueDays:       cycle.SecondDueDays,
+			SurchargePercentage: cycle.SurchargePercentage,
+			UpdatedAt:           cycle.UpdatedAt,
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": banks[cycle.Bank.Cuit]}).
+			SetUpdate(bson.M{"$set": bson.M{"billing_cycle": billingCycle}}))
+	}
+	return []write{{Collection: "banks", Models: models}}, nil
+}
+
+// customerWrites upserts the customers and keeps both sides of their bank memberships in line with the table.
+func customerWrites(ctx context.Context, s *Syncer, customers []entities.CustomerEntitySQL) ([]write, error) {
+	cuits, bankCuits := []string{}, []string{}
+	for _, customer := range customers {
+		cuits = append(cuits, customer.Cuit)
+		for _, bank := range customer.Banks {
+			bankCuits = append(bankCuits, bank.Cuit)
+		}
+	}
+	existing, err := s.ids.IDs(ctx, "customers", "cuit", cuits)
+	if err != nil {
+		return nil, err
+	}
+	banks, err := s.requireIDs(ctx, "banks", "cuit", bankCuits)
+	if err != nil {
+		return nil, err
+	}
+
+	customerModels, bankModels := []mongo.WriteModel{}, []mongo.WriteModel{}
+	for _, customer := range customers {
+		id := idOrNew(existing, customer.Cuit)
+		bankIDs := []bson.ObjectID{}
+		for _, bank := range customer.Banks {
+			bankIDs = append(bankIDs, banks[bank.Cuit])
+		}
+
+		customerModels = append(customerModels, upsert(
+			bson.M{"cuit": customer.Cuit},
+			bson.M{
+				"complete_name": customer.CompleteName,
+				"dni":           customer.Dni,
+				"address":       customer.Address,
+				"telephone":     customer.Telephone,
+				"entry_date":    customer.EntryDate,
+				"banks":         bankIDs,
+				"updated_at":    customer.UpdatedAt,
+			},
+			bson.M{"_id": id, "created_at": customer.CreatedAt},
+		))
+		bankModels = append(bankModels,
+			mongo.NewUpdateManyModel().
+				SetFilter(bson.M{"_id": bson.M{"$in": bankIDs}}).
+				SetUpdate(bson.M{"$addToSet": bson.M{"customers": id}}),
+			mongo.NewUpdateManyModel().
+				SetFilter(bson.M{"_id": bson.M{"$nin": bankIDs}, "customers": id}).
+				SetUpdate(bson.M{"$pull": bson.M{"customers": id}}),
+		)
+	}
+	return []write{{Collection: "customers", Models: customerModels}, {Collection: "banks", Models: bankModels}}, nil
+}
+
+// cardWrites upserts the cards and adds them to the cards of their customers.
+func cardWrites(ctx context.Context, s *Syncer, cards []entities.CardEntitySQL) ([]write, error) {
+	numbers, customerIDs := []string{}, []uint{}
+	for _, card := range cards {
+		numbers = append(numbers, card.Number)
+		customerIDs = append(customerIDs, card.CustomerID)
+	}
+	customerCuits, err := customerCuits(ctx, s.source, customerIDs)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.ids.IDs(ctx, "cards", "number", numbers)
+	if err != nil {
+		return nil, err
+	}
+	cuits := []string{}
+	for _, cuit := range customerCuits {
+		cuits = append(cuits, cuit)
+	}
+	customers, err := s.requireIDs(ctx, "customers", "cuit", cuits)
+	if err != nil {
+		return nil, err
+	}
+
+	cardModels, customerModels := []mongo.WriteModel{}, []mongo.WriteModel{}
+	for _, card := range cards {
+		id := idOrNew(existing, card.Number)
+		customerCuit := customerCuits[card.CustomerID]
+
+		cardModels = append(cardModels, upsert(
+			bson.M{"number": card.Number},
+			bson.M{
+				"ccv":                     card.Ccv,
+				"cardholder_name_in_card": card.CardholderNameInCard,
+				"since":                   card.Since,
+				"expiration_date":         card.ExpirationDate,
+				"status":                  card.Status,
+				"bank_cuit":               card.Bank.Cuit,
+				"customer_cuit":           customerCuit,
+				"updated_at":              card.UpdatedAt,
+			},
+			bson.M{"_id": id, "created_at": card.CreatedAt},
+		))
+		if customerCuit != "" {
+			customerModels = append(customerModels, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": customers[customerCuit]}).
+				SetUpdate(bson.M{"$addToSet": bson.M{"cards": id}}))
+		}
+	}
+	return []write{{Collection: "cards", Models: cardModels}, {Collection: "customers", Models: customerModels}}, nil
+}
+
+func discountWrites(ctx context.Context, s *Syncer, discounts []entities.DiscountEntitySQL) ([]write, error) {
+	promotions := []entities.PromotionEntitySQL{}
+	for _, discount := range discounts {
+		promotions = append(promotions, discount.PromotionEntitySQL)
+	}
+	banks, err := promotionBanks(ctx, s, promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.WriteModel{}
+	for _, discount := range discounts {
+		fields := promotionFields(&discount.PromotionEntitySQL, banks)
+		fields["discount_percentage"] = discount.DiscountPercentage
+		fields["price_cap"] = discount.PriceCap
+		fields["only_cash"] = discount.OnlyCash
+		models = append(models, upsert(bson.M{"promotion_entity.code": discount.Code}, fields, bson.M{"created_at": discount.CreatedAt}))
+	}
+	return []write{{Collection: "discounts", Models: models}}, nil
+}
+
+func financingWrites(ctx context.Context, s *Syncer, financings []entities.FinancingEntitySQL) ([]write, error) {
+	promotions := []entities.PromotionEntitySQL{}
+	for _, financing := range financings {
+		promotions = append(promotions, financing.PromotionEntitySQL)
+	}
+	banks, err := promotionBanks(ctx, s, promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.WriteModel{}
+	for _, financing := range financings {
+		fields := promotionFields(&financing.PromotionEntitySQL, banks)
+		fields["number_of_quotas"] = financing.NumberOfQuotas
+		fields["interest"] = financing.Interest
+		fields["interest_model"] = financing.InterestModel
+		models = append(models, upsert(bson.M{"promotion_entity.code": financing.Code}, fields, bson.M{"created_at": financing.CreatedAt}))
+	}
+	return []write{{Collection: "financings", Models: models}}, nil
+}
+
+// promotionBanks resolves the ObjectIDs of the banks of the promotions.
+func promotionBanks(ctx context.Context, s *Syncer, promotions []entities.PromotionEntitySQL) (map[string]bson.ObjectID, error) {
+	cuits := []string{}
+	for _, promotion := range promotions {
+		cuits = append(cuits, promotion.Bank.Cuit)
+	}
+	return s.requireIDs(ctx, "banks", "cuit", cuits)
+}
+
+// promotionFields are the fields shared by discounts and financings.
+func promotionFields(promotion *entities.PromotionEntitySQL, banks map[string]bson.ObjectID) bson.M {
+	return bson.M{
+		"promotion_entity": entities.PromotionEntityNonSQL{
+			Code:              promotion.Code,
+			PromotionTitle:    promotion.PromotionTitle,
+			NameStore:         promotion.NameStore,
+			CuitStore:         promotion.CuitStore,
+			ValidityStartDate: promotion.ValidityStartDate,
+			ValidityEndDate:   promotion.ValidityEndDate,
+			Comments:          promotion.Comments,
+		},
+		"is_deleted": promotion.IsDeleted,
+		"bank_id":    banks[promotion.Bank.Cuit],
+		"updated_at": promotion.UpdatedAt,
+	}
+}
+
+// singlePaymentWrites replaces the single payment purchases.
+func singlePaymentWrites(ctx context.Context, s *Syncer, purchases []entities.PurchaseSinglePaymentEntitySQL) ([]write, error) {
+	cardIDs := []uint{}
+	for _, purchase := range purchases {
+		cardIDs = append(cardIDs, purchase.PurchaseEntity.CardID)
+	}
+	numbers, err := cardNumbers(ctx, s.source, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.WriteModel{}
+	for _, purchase := range purchases {
+		number := numbers[purchase.PurchaseEntity.CardID]
+		entity := entities.ToPurchaseSinglePaymentEntityNonSQL(entities.ToPurchaseSinglePayment(&purchase), number)
+		entity.PurchaseEntity.UpdatedAt = purchase.PurchaseEntity.UpdatedAt
+		models = append(models, purchaseReplacement(number, &purchase.PurchaseEntity, entity))
+	}
+	return []write{{Collection: "purchase_single_payments", Models: models}}, nil
+}
+
+// monthlyPaymentWrites replaces the installment purchases, with their quotas.
+func monthlyPaymentWrites(ctx context.Context, s *Syncer, purchases []entities.PurchaseMonthlyPaymentsEntitySQL) ([]write, error) {
+	cardIDs := []uint{}
+	for _, purchase := range purchases {
+		cardIDs = append(cardIDs, purchase.PurchaseEntity.CardID)
+	}
+	numbers, err := cardNumbers(ctx, s.source, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.WriteModel{}
+	for _, purchase := range purchases {
+		number := numbers[purchase.PurchaseEntity.CardID]
+		entity := entities.ToPurchaseMonthlyPaymentsEntityNonSQL(entities.ToPurchaseMonthlyPayments(&purchase), number)
+		entity.PurchaseEntity.UpdatedAt = purchase.PurchaseEntity.UpdatedAt
+		for i := range entity.Quotas {
+			entity.Quotas[i].CreatedAt = purchase.Quotas[i].CreatedAt
+			entity.Quotas[i].UpdatedAt = purchase.Quotas[i].UpdatedAt
+		}
+		models = append(models, purchaseReplacement(number, &purchase.PurchaseEntity, entity))
+	}
+	return []write{{Collection: "purchase_monthly_payments", Models: models}}, nil
+}
+
+// purchaseReplacement replaces a purchase, matched by card number, payment voucher and purchase date. The payment
+// voucher alone is not unique, purchases loaded from seed data reuse the promotion code as voucher.
+func purchaseReplacement(cardNumber string, purchase *entities.PurchaseEntitySQL, document interface{}) mongo.WriteModel {
+	return mongo.NewReplaceOneModel().
+		SetFilter(bson.M{
+			"purchase.card_number":     cardNumber,
+			"purchase.payment_voucher": purchase.PaymentVoucher,
+			"purchase.created_at":      purchase.CreatedAt,
+		}).
+		SetReplacement(document).
+		SetUpsert(true)
+}
+
+// paymentSummaryWrites replaces the summaries, with their snapshot of purchases, matched by card and month.
+func paymentSummaryWrites(_ context.Context, _ *Syncer, summaries []entities.PaymentSummaryEntitySQL) ([]write, error) {
+	models := []mongo.WriteModel{}
+	for _, summary := range summaries {
+		entity := entities.ToPaymentSummaryEntityNonRelational(entities.ToPaymentSummary(&summary))
+		entity.CreatedAt = summary.CreatedAt
+		entity.UpdatedAt = summary.UpdatedAt
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"card_number": summary.Card.Number, "month": summary.Month, "year": summary.Year}).
+			SetReplacement(entity).
+			SetUpsert(true))
+	}
+	return []write{{Collection: "payment_summaries", Models: models}}, nil
+}
+
+// exchangeRateWrites upserts the rates, matched by currency and day.
+func exchangeRateWrites(_ context.Context, _ *Syncer, rates []entities.ExchangeRateEntitySQL) ([]write, error) {
+	models := []mongo.WriteModel{}
+	for _, rate := range rates {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"currency": rate.Currency, "date": entities.ToExchangeRate(&rate).Date}).
+			SetUpdate(bson.M{"$set": bson.M{"rate": rate.Rate, "updated_at": rate.UpdatedAt}}).
+			SetUpsert(true))
+	}
+	return []write{{Collection: "exchange_rates", Models: models}}, nil
+}
+
+// paymentWrites replaces the payments, with their allocations, matched by code. The paid amounts of the billed items
+// are replicated with the purchases and summaries they belong to.
+func paymentWrites(_ context.Context, _ *Syncer, payments []entities.PaymentEntitySQL) ([]write, error) {
+	models := []mongo.WriteModel{}
+	for _, payment := range payments {
+		entity := entities.ToPaymentEntityNonSQL(entities.ToPayment(&payment))
+		entity.CreatedAt = payment.CreatedAt
+		entity.UpdatedAt = payment.UpdatedAt
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"code": payment.Code}).
+			SetReplacement(entity).
+			SetUpsert(true))
+	}
+	return []write{{Collection: "payments", Models: models}}, nil
+}
+
+// customerCuits maps the IDs of customers to their CUIT.
+func customerCuits(ctx context.Context, db *gorm.DB, ids []uint) (map[uint]string, error) {
+	var customers []entities.CustomerEntitySQL
+	if err := db.WithContext(ctx).Select("id", "cuit").Where("id IN ?", ids).Find(&customers).Error; err != nil {
+		return nil, fmt.Errorf("error reading the customers of the cards: %w", err)
+	}
+	cuits := map[uint]string{}
+	for _, customer := range customers {
+		cuits[customer.ID] = customer.Cuit
+	}
+	return cuits, nil
+}
+
+// cardNumbers maps the IDs of cards to their number.
+func cardNumbers(ctx context.Context, db *gorm.DB, ids []uint) (map[uint]string, error) {
+	var cards []entities.CardEntitySQL
+	if err := db.WithContext(ctx).Select("id", "number").Where("id IN ?", ids).Find(&cards).Error; err != nil {
+		return nil, fmt.Errorf("error reading the cards of the purchases: %w", err)
+	}
+	numbers := map[uint]string{}
+	for _, card := range cards {
+		numbers[card.ID] = card.Number
+	}
+	return numbers, nil
+}
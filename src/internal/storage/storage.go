@@ -1,55 +1,175 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
 )
 
+// ErrNotFound is returned (wrapped) by storage implementations when the requested record does not exist.
+var ErrNotFound = errors.New("record not found")
+
+// ErrAlreadyExists is returned (wrapped) by storage implementations when a record that must be unique already exists.
+var ErrAlreadyExists = errors.New("record already exists")
+
 // IBankStorage is the interface that defines methods related to bank operations,
-// such as adding, deleting, and extending promotions, and fetching customer counts.
+// such as registering banks, adding, deleting, and extending promotions, and fetching customer counts.
 type IBankStorage interface {
+	// CreateBank registers a new bank. The CUIT is unique among banks.
+	CreateBank(ctx context.Context, bank models.Bank) (*models.Bank, error)
+	// GetBanks retrieves all banks ordered by CUIT.
+	GetBanks(ctx context.Context) (*[]models.Bank, error)
+	// GetBankByCuit retrieves a bank by its CUIT.
+	GetBankByCuit(ctx context.Context, cuit string) (*models.Bank, error)
+	// UpdateBank replaces the name, address and telephone of a bank.
+	UpdateBank(ctx context.Context, cuit string, bank models.Bank) (*models.Bank, error)
 	// AddFinancingPromotionToBank adds a financing promotion to the bank.
-	AddFinancingPromotionToBank(promotionFinancing models.Financing) error
+	AddFinancingPromotionToBank(ctx context.Context, promotionFinancing models.Financing) error
+	// AddDiscountPromotionToBank adds a discount promotion to the bank.
+	AddDiscountPromotionToBank(ctx context.Context, promotionDiscount models.Discount) error
 	// ExtendFinancingPromotionValidity extends the validity of a financing promotion.
-	ExtendFinancingPromotionValidity(code string, newDate time.Time) error
+	ExtendFinancingPromotionValidity(ctx context.Context, code string, newDate time.Time) error
 	// ExtendDiscountPromotionValidity extends the validity of a discount promotion.
-	ExtendDiscountPromotionValidity(code string, newDate time.Time) error
+	ExtendDiscountPromotionValidity(ctx context.Context, code string, newDate time.Time) error
 	// DeleteFinancingPromotion deletes a financing promotion by its code.
-	DeleteFinancingPromotion(code string) error
+	DeleteFinancingPromotion(ctx context.Context, code string) error
 	// DeleteDiscountPromotion deletes a discount promotion by its code.
-	DeleteDiscountPromotion(code string) error
+	DeleteDiscountPromotion(ctx context.Context, code string) error
 	// GetBankCustomerCounts retrieves the count of customers for each bank.
-	GetBankCustomerCounts() ([]models.BankCustomerCountDTO, error)
+	GetBankCustomerCounts(ctx context.Context) ([]models.BankCustomerCountDTO, error)
+	// SaveBillingCycle creates or replaces the billing cycle configuration of a bank.
+	SaveBillingCycle(ctx context.Context, cycle models.BillingCycle) error
+	// GetBillingCycle retrieves the billing cycle configuration of a bank, or nil if the bank has not configured one.
+	GetBillingCycle(ctx context.Context, bankCuit string) (*models.BillingCycle, error)
 }
 
 // ICardStorage is the interface that defines methods related to card operations,
 // such as retrieving payment summaries, card expiration data, and purchases.
 type ICardStorage interface {
-	// GetPaymentSummary retrieves the payment summary for a card.
-	GetPaymentSummary(cardNumber string, month int, year int) (*models.PaymentSummary, error)
+	// GetPaymentSummary retrieves the stored payment summary of a card for a month.
+	GetPaymentSummary(ctx context.Context, cardNumber string, month int, year int) (*models.PaymentSummary, error)
+	// SavePaymentSummary stores the payment summary of a card for a month. A card has at most one summary per month.
+	SavePaymentSummary(ctx context.Context, cardNumber string, summary models.PaymentSummary) (*models.PaymentSummary, error)
+	// GetQuotasDueInMonth retrieves the installment quotas of a card due in the given month, across all its installment purchases.
+	GetQuotasDueInMonth(ctx context.Context, cardNumber string, month int, year int) (*[]models.DueQuota, error)
+	// GetPurchasesInPeriod retrieves the purchases made with a card between from (inclusive) and to (exclusive).
+	GetPurchasesInPeriod(ctx context.Context, cardNumber string, from time.Time, to time.Time) (*[]models.PurchaseSinglePayment, *[]models.PurchaseMonthlyPayment, error)
 	// GetCardsExpiringInNext30Days retrieves cards that will expire in the next 30 days.
-	GetCardsExpiringInNext30Days(day int, month int, year int) (*[]models.Card, error)
+	GetCardsExpiringInNext30Days(ctx context.Context, day int, month int, year int) (*[]models.Card, error)
 	// GetPurchaseMonthly retrieves the monthly purchase details for a card.
-	GetPurchaseMonthly(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseMonthlyPayment, error)
+	GetPurchaseMonthly(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseMonthlyPayment, error)
 	// GetPurchaseSingle retrieves the single purchase details for a card.
-	GetPurchaseSingle(cuit string, finalAmount float64, paymentVoucher string) (*models.PurchaseSinglePayment, error)
+	GetPurchaseSingle(ctx context.Context, cuit string, finalAmount models.Money, paymentVoucher string) (*models.PurchaseSinglePayment, error)
 	// GetTop10CardsByPurchases retrieves the top 10 cards by purchases.
-	GetTop10CardsByPurchases() (*[]models.Card, error)
+	GetTop10CardsByPurchases(ctx context.Context) (*[]models.Card, error)
+	// GetCardByNumber retrieves a card, including its issuing bank, by its number.
+	GetCardByNumber(ctx context.Context, cardNumber string) (*models.Card, error)
+	// AddPurchaseSinglePayment registers a single-payment purchase on the card. Vouchers are unique among the
+	// single-payment purchases of a card, a repeated one is rejected with a wrapped ErrAlreadyExists.
+	AddPurchaseSinglePayment(ctx context.Context, cardNumber string, purchase models.PurchaseSinglePayment) (*models.PurchaseSinglePayment, error)
+	// AddPurchaseMonthlyPayment registers an installment purchase on the card. Vouchers are unique among the
+	// installment purchases of a card, a repeated one is rejected with a wrapped ErrAlreadyExists.
+	AddPurchaseMonthlyPayment(ctx context.Context, cardNumber string, purchase models.PurchaseMonthlyPayment) (*models.PurchaseMonthlyPayment, error)
+	// IssueCard issues a new card to the customer with the card's customer CUIT at the card's bank. Card numbers are unique.
+	IssueCard(ctx context.Context, card models.Card) (*models.Card, error)
+	// UpdateCardExpiration replaces the expiration date of a card.
+	UpdateCardExpiration(ctx context.Context, cardNumber string, expirationDate time.Time) (*models.Card, error)
+	// UpdateCardStatus replaces the lifecycle status of a card.
+	UpdateCardStatus(ctx context.Context, cardNumber string, status models.CardStatus) (*models.Card, error)
 }
 
 // IPromotionStorage is the interface that defines methods related to promotion operations,
 // such as retrieving available promotions and the most used promotion.
 type IPromotionStorage interface {
 	// GetAvailablePromotionsByStoreAndDateRange retrieves available promotions for a store within a date range.
-	GetAvailablePromotionsByStoreAndDateRange(cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error)
+	GetAvailablePromotionsByStoreAndDateRange(ctx context.Context, cuit string, startDate time.Time, endDate time.Time) (*[]models.Financing, *[]models.Discount, error)
 	// GetMostUsedPromotion retrieves the most used promotion.
-	GetMostUsedPromotion() (interface{}, error)
+	GetMostUsedPromotion(ctx context.Context) (interface{}, error)
+	// GetApplicablePromotions retrieves the non-deleted promotions of a bank for a store that are valid on the given date.
+	GetApplicablePromotions(ctx context.Context, bankCuit string, storeCuit string, date time.Time) (*[]models.Financing, *[]models.Discount, error)
+	// GetPromotionByCode retrieves a discount or financing promotion, deleted or not, by its code.
+	GetPromotionByCode(ctx context.Context, code string) (*models.PromotionDetail, error)
+	// GetBankPromotions retrieves the promotions of a bank that have the given status on the given date.
+	GetBankPromotions(ctx context.Context, bankCuit string, status models.PromotionStatus, date time.Time) (*[]models.Financing, *[]models.Discount, error)
+	// UpdatePromotion applies the given changes to a promotion. Changes that do not apply to its type are ignored.
+	UpdatePromotion(ctx context.Context, code string, update models.PromotionUpdate) error
+	// RestorePromotion clears the logical delete of a promotion.
+	RestorePromotion(ctx context.Context, code string) error
 }
 
 // IStoreStorage is the interface that defines methods related to store operations,
 // such as retrieving the store with the highest revenue in a given month.
 type IStoreStorage interface {
 	// GetStoreWithHighestRevenueByMonth retrieves the store with the highest revenue in a specific month.
-	GetStoreWithHighestRevenueByMonth(month int, year int) (models.StoreDTO, error)
+	GetStoreWithHighestRevenueByMonth(ctx context.Context, month int, year int) (models.StoreDTO, error)
+}
+
+// ICustomerStorage is the interface that defines methods related to customer operations,
+// such as registering and updating customers and managing their bank memberships.
+type ICustomerStorage interface {
+	// CreateCustomer stores a new customer. CUIT and DNI are unique among customers.
+	CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error)
+	// GetCustomerByCuit retrieves a customer, including the CUITs of its banks, by its CUIT.
+	GetCustomerByCuit(ctx context.Context, cuit string) (*models.Customer, error)
+	// UpdateCustomer replaces the name, DNI, address, telephone and entry date of a customer.
+	UpdateCustomer(ctx context.Context, cuit string, customer models.Customer) (*models.Customer, error)
+	// GetCustomers retrieves all customers ordered by CUIT.
+	GetCustomers(ctx context.Context) (*[]models.Customer, error)
+	// AddCustomerToBank makes a customer a member of a bank.
+	AddCustomerToBank(ctx context.Context, customerCuit string, bankCuit string) error
+	// RemoveCustomerFromBank ends the membership of a customer in a bank.
+	RemoveCustomerFromBank(ctx context.Context, customerCuit string, bankCuit string) error
+}
+
+// IExchangeRateStorage is the interface that defines methods related to the daily exchange rates of foreign currencies.
+// A currency has at most one rate per day.
+type IExchangeRateStorage interface {
+	// SaveExchangeRates stores exchange rates, replacing the rate of a currency already stored for the same day.
+	SaveExchangeRates(ctx context.Context, rates []models.ExchangeRate) error
+	// GetExchangeRates retrieves the rates of a currency from one day to another, both included, ordered by day.
+	GetExchangeRates(ctx context.Context, currency models.Currency, from time.Time, to time.Time) (*[]models.ExchangeRate, error)
+	// GetExchangeRate retrieves the latest rate of a currency on or before a day.
+	GetExchangeRate(ctx context.Context, currency models.Currency, date time.Time) (*models.ExchangeRate, error)
+}
+
+// IPaymentStorage is the interface that defines methods related to the payments of payment summaries.
+// A payment is allocated to the quotas and single-payment purchases billed in a summary, whose paid amounts it increases.
+type IPaymentStorage interface {
+	// RecordPayment stores a payment of the summary of its card for its month and adds each allocation to the paid amount
+	// of the billed quota or single-payment purchase it names. Payment codes are unique. The check of each paid amount
+	// against the billed amount of its allocation and the update are atomic, so that concurrent payments cannot pay an
+	// item beyond it: a wrapped ErrAlreadyExists is returned for a taken code or an item left with too little to pay.
+	RecordPayment(ctx context.Context, payment models.Payment) (*models.Payment, error)
+	// GetPayments retrieves the payments of the summary of a card for a month, ordered by payment date.
+	GetPayments(ctx context.Context, cardNumber string, month int, year int) (*[]models.Payment, error)
+	// GetPaymentSummaries retrieves the stored payment summaries of a card, ordered by year and month.
+	GetPaymentSummaries(ctx context.Context, cardNumber string) (*[]models.PaymentSummary, error)
+}
+
+// ICompensationStorage is the interface that defines methods removing records that were just created, used to
+// compensate a write that could not be completed on another backend. Removals do not cascade: the records are
+// expected to have no dependents yet.
+type ICompensationStorage interface {
+	// RemoveBank removes a bank by its CUIT.
+	RemoveBank(ctx context.Context, cuit string) error
+	// RemoveCustomer removes a customer by its CUIT.
+	RemoveCustomer(ctx context.Context, cuit string) error
+	// RemoveCard removes a card by its number.
+	RemoveCard(ctx context.Context, cardNumber string) error
+	// RemovePromotion removes a discount or financing promotion by its code.
+	RemovePromotion(ctx context.Context, code string) error
+	// RemovePurchaseSinglePayment removes the latest single-payment purchase registered on a card with the given voucher.
+	RemovePurchaseSinglePayment(ctx context.Context, cardNumber string, paymentVoucher string) error
+	// RemovePurchaseMonthlyPayment removes the latest installment purchase registered on a card with the given voucher, and its quotas.
+	RemovePurchaseMonthlyPayment(ctx context.Context, cardNumber string, paymentVoucher string) error
+	// RemovePaymentSummary removes the payment summary of a card for a month.
+	RemovePaymentSummary(ctx context.Context, cardNumber string, month int, year int) error
+	// RemoveBillingCycle removes the billing cycle configuration of a bank.
+	RemoveBillingCycle(ctx context.Context, bankCuit string) error
+	// RemoveExchangeRate removes the rate of a currency for a day.
+	RemoveExchangeRate(ctx context.Context, currency models.Currency, date time.Time) error
+	// RemovePayment removes a payment by its code and takes its allocations off the paid amounts of the billed items.
+	RemovePayment(ctx context.Context, code string) error
 }
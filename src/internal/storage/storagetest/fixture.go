@@ -0,0 +1,223 @@
+/*
+ * Payment Registration System - Storage Contract Fixture
+ * ------------------------------------------------------
+ * This file defines the fixture loaded before every storage contract case. The fixture is written
+ * through the storage interfaces themselves, so the same data can be loaded into any backend.
+ *
+ * Created: Mar. 14, 2025
+ * License: GNU General Public License v3.0
+ */
+
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+)
+
+// Fixture is a data set that can be loaded into any set of storages.
+type Fixture struct {
+	Banks         []models.Bank
+	Customers     []models.Customer // BankCuits are loaded as bank memberships
+	Cards         []models.Card     // PurchaseSinglePayments and PurchaseMonthlyPayments are loaded as purchases of the card
+	Discounts     []models.Discount
+	Financings    []models.Financing
+	ExchangeRates []models.ExchangeRate
+}
+
+// Identifiers of the default fixture, used by the contract cases.
+const (
+	BankCuit        = "30-70000001-1" // Bank with both customers and most promotions
+	OtherBankCuit   = "30-70000002-2" // Bank with a single customer
+	CustomerCuit    = "27-70000001-1" // Member of BankCuit
+	OtherCustomer   = "20-70000002-2" // Member of both banks
+	CardNumber      = "4000000000000001"
+	OtherCardNumber = "4000000000000002"
+	IdleCardNumber  = "4000000000000003" // Card without purchases
+	NorthStoreCuit  = "30-80000001-1"
+	SouthStoreCuit  = "30-80000002-2"
+)
+
+// DefaultFixture returns the data set shared by every contract case:
+//   - two banks, two customers and three cards;
+//   - five purchases in March and April 2025 whose payment vouchers are the promotion codes
+//     (DISC-2025 twice as a single payment, FIN-2025 once as a single payment and twice in installments);
+//   - an active and an expired financing and a current and a future discount;
+//   - dollar exchange rates for March 31st, April 1st and April 30th 2025.
+func DefaultFixture() Fixture {
+	return Fixture{
+		Banks: []models.Bank{
+			{Name: "Banco Contrato", Cuit: BankCuit, Address: "Av. Corrientes 1000", Telephone: "0800-111-1111"},
+			{Name: "Banco Paridad", Cuit: OtherBankCuit, Address: "Av. Santa Fe 2000", Telephone: "0800-222-2222"},
+		},
+		Customers: []models.Customer{
+			{CompleteName: "Ana Pérez", Dni: "70000001", Cuit: CustomerCuit, EntryDate: date(2023, time.January, 10), BankCuits: []string{BankCuit}},
+			{CompleteName: "Bruno Díaz", Dni: "70000002", Cuit: OtherCustomer, EntryDate: date(2023, time.June, 1), BankCuits: []string{BankCuit, OtherBankCuit}},
+		},
+		Cards: []models.Card{
+			{
+				Number: CardNumber, Ccv: "111", CardholderNameInCard: "ANA PEREZ",
+				Since: date(2023, time.January, 15), ExpirationDate: date(2030, time.January, 31),
+				Bank: models.Bank{Cuit: BankCuit}, CustomerCuit: CustomerCuit,
+				PurchaseSinglePayments: []models.PurchaseSinglePayment{
+					{Purchase: purchase("DISC-2025", "Tienda Norte", NorthStoreCuit, "1000", "900", date(2025, time.March, 5))},
+					{Purchase: purchase("DISC-2025-2", "Tienda Norte", NorthStoreCuit, "500.10", "450.09", date(2025, time.March, 12))},
+				},
+				PurchaseMonthlyPayments: []models.PurchaseMonthlyPayment{
+					{
+						Purchase:       purchase("FIN-2025", "Tienda Sur", SouthStoreCuit, "300", "300", date(2025, time.April, 10)),
+						NumberOfQuotas: 3,
+						Quota:          quotas("100", 2025, 4, 5, 6),
+					},
+				},
+			},
+			{
+				Number: OtherCardNumber, Ccv: "222", CardholderNameInCard: "BRUNO DIAZ",
+				Since: date(2023, time.June, 15), ExpirationDate: date(2029, time.June, 30),
+				Bank: models.Bank{Cuit: OtherBankCuit}, CustomerCuit: OtherCustomer,
+				PurchaseSinglePayments: []models.PurchaseSinglePayment{
+					{Purchase: purchase("FIN-2025", "Tienda Sur", SouthStoreCuit, "700", "700", date(2025, time.March, 25))},
+				},
+				PurchaseMonthlyPayments: []models.PurchaseMonthlyPayment{
+					{
+						Purchase:       purchase("FIN-2025", "Tienda Sur", SouthStoreCuit, "800", "800", date(2025, time.March, 8)),
+						NumberOfQuotas: 2,
+						Quota:          quotas("400", 2025, 3, 4),
+					},
+				},
+			},
+			{
+				Number: IdleCardNumber, Ccv: "333", CardholderNameInCard: "BRUNO DIAZ",
+				Since: date(2024, time.February, 1), ExpirationDate: date(2031, time.February, 28),
+				Bank: models.Bank{Cuit: BankCuit}, CustomerCuit: OtherCustomer,
+			},
+		},
+		Discounts: []models.Discount{
+			{
+				Promotion:          promotion("DISC-2025", "March discount", "Tienda Norte", NorthStoreCuit, BankCuit, date(2025, time.March, 1), date(2025, time.March, 31)),
+				DiscountPercentage: models.MustParsePercentage("10"),
+				PriceCap:           models.MustParseMoney("5000"),
+			},
+			{
+				Promotion:          promotion("DISC-SPRING", "Spring discount", "Tienda Norte", NorthStoreCuit, OtherBankCuit, date(2025, time.September, 21), date(2025, time.December, 21)),
+				DiscountPercentage: models.MustParsePercentage("15"),
+				PriceCap:           models.MustParseMoney("3000"),
+				OnlyCash:           true,
+			},
+		},
+		Financings: []models.Financing{
+			{
+				Promotion:      promotion("FIN-2025", "Year financing", "Tienda Sur", SouthStoreCuit, BankCuit, date(2025, time.January, 1), date(2025, time.December, 31)),
+				NumberOfQuotas: 3,
+				Interest:       0,
+			},
+			{
+				Promotion:      promotion("FIN-OLD", "Expired financing", "Tienda Sur", SouthStoreCuit, BankCuit, date(2024, time.January, 1), date(2024, time.June, 30)),
+				NumberOfQuotas: 6,
+				Interest:       models.MustParsePercentage("5.5"),
+				InterestModel:  models.InterestModelFrench,
+			},
+		},
+		ExchangeRates: []models.ExchangeRate{
+			{Currency: models.CurrencyUSD, Date: date(2025, time.March, 31), Rate: models.MustParseMoney("1050")},
+			{Currency: models.CurrencyUSD, Date: date(2025, time.April, 1), Rate: models.MustParseMoney("1060.50")},
+			{Currency: models.CurrencyUSD, Date: date(2025, time.April, 30), Rate: models.MustParseMoney("1072.25")},
+		},
+	}
+}
+
+// Load writes the fixture through the given storages: banks, customers and their memberships,
+// cards and their purchases, the promotions and finally the exchange rates.
+func (f Fixture) Load(s Storages) error {
+	ctx := context.Background()
+	for _, bank := range f.Banks {
+		if _, err := s.Banks.CreateBank(ctx, bank); err != nil {
+			return fmt.Errorf("loading bank %s: %w", bank.Cuit, err)
+		}
+	}
+
+	for _, customer := range f.Customers {
+		if _, err := s.Customers.CreateCustomer(ctx, customer); err != nil {
+			return fmt.Errorf("loading customer %s: %w", customer.Cuit, err)
+		}
+		for _, bankCuit := range customer.BankCuits {
+			if err := s.Customers.AddCustomerToBank(ctx, customer.Cuit, bankCuit); err != nil {
+				return fmt.Errorf("loading membership of customer %s in bank %s: %w", customer.Cuit, bankCuit, err)
+			}
+		}
+	}
+
+	for _, card := range f.Cards {
+		if _, err := s.Cards.IssueCard(ctx, card); err != nil {
+			return fmt.Errorf("loading card %s: %w", card.Number, err)
+		}
+		for _, purchase := range card.PurchaseSinglePayments {
+			if _, err := s.Cards.AddPurchaseSinglePayment(ctx, card.Number, purchase); err != nil {
+				return fmt.Errorf("loading purchase %s of card %s: %w", purchase.PaymentVoucher, card.Number, err)
+			}
+		}
+		for _, purchase := range card.PurchaseMonthlyPayments {
+			if _, err := s.Cards.AddPurchaseMonthlyPayment(ctx, card.Number, purchase); err != nil {
+				return fmt.Errorf("loading purchase %s of card %s: %w", purchase.PaymentVoucher, card.Number, err)
+			}
+		}
+	}
+
+	for _, discount := range f.Discounts {
+		if err := s.Banks.AddDiscountPromotionToBank(ctx, discount); err != nil {
+			return fmt.Errorf("loading discount %s: %w", discount.Code, err)
+		}
+	}
+	for _, financing := range f.Financings {
+		if err := s.Banks.AddFinancingPromotionToBank(ctx, financing); err != nil {
+			return fmt.Errorf("loading financing %s: %w", financing.Code, err)
+		}
+	}
+
+	if len(f.ExchangeRates) > 0 {
+		if err := s.ExchangeRates.SaveExchangeRates(ctx, f.ExchangeRates); err != nil {
+			return fmt.Errorf("loading exchange rates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func purchase(voucher string, store string, cuitStore string, amount string, finalAmount string, purchaseDate time.Time) models.Purchase {
+	return models.Purchase{
+		PaymentVoucher: voucher,
+		Store:          store,
+		CuitStore:      cuitStore,
+		Amount:         models.MustParseMoney(amount),
+		FinalAmount:    models.MustParseMoney(finalAmount),
+		PurchaseDate:   purchaseDate,
+	}
+}
+
+// quotas returns one quota of the given price for each month, numbered from 1, in the zero-padded format used by the quota schedule.
+func quotas(price string, year int, months ...int) []models.Quota {
+	result := make([]models.Quota, 0, len(months))
+	for i, month := range months {
+		result = append(result, models.Quota{Number: i + 1, Price: models.MustParseMoney(price), Month: fmt.Sprintf("%02d", month), Year: fmt.Sprint(year)})
+	}
+	return result
+}
+
+func promotion(code string, title string, store string, cuitStore string, bankCuit string, start time.Time, end time.Time) models.Promotion {
+	return models.Promotion{
+		Code:              code,
+		PromotionTitle:    title,
+		NameStore:         store,
+		CuitStore:         cuitStore,
+		ValidityStartDate: start.Format(time.RFC3339),
+		ValidityEndDate:   end.Format(time.RFC3339),
+		Bank:              models.Bank{Cuit: bankCuit},
+	}
+}
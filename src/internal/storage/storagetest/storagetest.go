@@ -0,0 +1,922 @@
+/*
+ * Payment Registration System - Storage Contract Suite
+ * ----------------------------------------------------
+ * This file defines the behavioral contract shared by every implementation of the storage
+ * interfaces. Each backend runs the same table of cases against a fresh set of storages loaded
+ * with the default fixture, so that the SQL, NoSQL and in-memory backends answer alike.
+ *
+ * Created: Mar. 14, 2025
+ * License: GNU General Public License v3.0
+ */
+
+// Package storagetest provides a contract suite and a fixture loader for implementations of the storage interfaces.
+package storagetest
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Storages groups one implementation of each storage interface, all backed by the same database.
+// Compensation is only used by the contract suite, loading the fixture does not require it.
+type Storages struct {
+	Banks         storage.IBankStorage
+	Cards         storage.ICardStorage
+	Promotions    storage.IPromotionStorage
+	Stores        storage.IStoreStorage
+	Customers     storage.ICustomerStorage
+	ExchangeRates storage.IExchangeRateStorage
+	Payments      storage.IPaymentStorage
+	Compensation  storage.ICompensationStorage
+}
+
+// contractCase is a single behavior every backend must show, checked against storages loaded with the default fixture.
+type contractCase struct {
+	name string
+	run  func(t *testing.T, s Storages)
+}
+
+// Run runs the contract suite. newStorages is called once per case and must return storages over an empty database;
+// the default fixture is loaded into them before the case runs.
+//
+// GetCardsExpiringInNext30Days is not part of the contract: the relational and in-memory backends derive it from
+// the first expiration of the stored payment summaries while the NoSQL backend uses the card expiration date.
+func Run(t *testing.T, newStorages func(t *testing.T) Storages) {
+	for _, tc := range contractCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newStorages(t)
+			require.NoError(t, DefaultFixture().Load(s), "loading the default fixture")
+			tc.run(t, s)
+		})
+	}
+}
+
+var contractCases = []contractCase{
+	{name: "banks/create and lookup", run: testBanks},
+	{name: "banks/customer counts", run: testBankCustomerCounts},
+	{name: "banks/billing cycle", run: testBillingCycle},
+	{name: "customers/memberships", run: testCustomers},
+	{name: "cards/lifecycle", run: testCardLifecycle},
+	{name: "cards/purchases in period", run: testPurchasesInPeriod},
+	{name: "cards/quotas due in month", run: testQuotasDueInMonth},
+	{name: "cards/purchase lookup", run: testPurchaseLookup},
+	{name: "cards/purchase currencies", run: testPurchaseCurrencies},
+	{name: "cards/purchase interest models", run: testPurchaseInterestModels},
+	{name: "cards/purchase vouchers", run: testPurchaseVouchers},
+	{name: "cards/payment summary", run: testPaymentSummary},
+	{name: "cards/top 10 by purchases", run: testTop10CardsByPurchases},
+	{name: "promotions/available by store and date range", run: testAvailablePromotions},
+	{name: "promotions/add to unknown bank", run: testAddPromotionToUnknownBank},
+	{name: "promotions/duplicate code", run: testAddDuplicatePromotion},
+	{name: "promotions/delete and restore", run: testDeleteAndRestorePromotion},
+	{name: "promotions/extend validity", run: testExtendPromotionValidity},
+	{name: "promotions/update", run: testUpdatePromotion},
+	{name: "promotions/bank promotions by status", run: testBankPromotions},
+	{name: "promotions/applicable", run: testApplicablePromotions},
+	{name: "promotions/most used", run: testMostUsedPromotion},
+	{name: "stores/highest revenue by month", run: testStoreWithHighestRevenue},
+	{name: "exchange rates/save and lookup", run: testExchangeRates},
+	{name: "payments/record and allocate", run: testPayments},
+	{name: "payments/summaries of a card", run: testPaymentSummaries},
+	{name: "compensation/remove created records", run: testCompensation},
+	{name: "compensation/remove payment", run: testRemovePayment},
+}
+
+func testBanks(t *testing.T, s Storages) {
+	ctx := context.Background()
+	bank, err := s.Banks.GetBankByCuit(ctx, BankCuit)
+	require.NoError(t, err)
+	assert.Equal(t, "Banco Contrato", bank.Name)
+
+	_, err = s.Banks.CreateBank(ctx, models.Bank{Name: "Duplicado", Cuit: BankCuit})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	_, err = s.Banks.GetBankByCuit(ctx, "30-00000000-0")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	updated, err := s.Banks.UpdateBank(ctx, OtherBankCuit, models.Bank{Name: "Banco Paridad S.A.", Address: "Av. Santa Fe 2100", Telephone: "0800-333-3333"})
+	require.NoError(t, err)
+	assert.Equal(t, "Banco Paridad S.A.", updated.Name)
+	_, err = s.Banks.UpdateBank(ctx, "30-00000000-0", models.Bank{Name: "Nadie"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	banks, err := s.Banks.GetBanks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{BankCuit, OtherBankCuit}, bankCuits(*banks))
+}
+
+func testBankCustomerCounts(t *testing.T, s Storages) {
+	ctx := context.Background()
+	counts, err := s.Banks.GetBankCustomerCounts(ctx)
+	require.NoError(t, err)
+
+	byCuit := make(map[string]int)
+	for _, count := range counts {
+		byCuit[count.BankCuit] = count.CustomerCount
+	}
+	assert.Equal(t, map[string]int{BankCuit: 2, OtherBankCuit: 1}, byCuit)
+}
+
+func testBillingCycle(t *testing.T, s Storages) {
+	ctx := context.Background()
+	cycle, err := s.Banks.GetBillingCycle(ctx, BankCuit)
+	require.NoError(t, err)
+	assert.Nil(t, cycle, "a bank has no billing cycle until it configures one")
+
+	configured := models.BillingCycle{BankCuit: BankCuit, ClosingDay: 20, FirstDueDays: 10, SecondDueDays: 5, SurchargePercentage: models.MustParsePercentage("2.5")}
+	require.NoError(t, s.Banks.SaveBillingCycle(ctx, configured))
+	configured.ClosingDay = 28
+	require.NoError(t, s.Banks.SaveBillingCycle(ctx, configured), "saving again replaces the billing cycle")
+
+	cycle, err = s.Banks.GetBillingCycle(ctx, BankCuit)
+	require.NoError(t, err)
+	require.NotNil(t, cycle)
+	assert.Equal(t, configured, *cycle)
+
+	assert.ErrorIs(t, s.Banks.SaveBillingCycle(ctx, models.BillingCycle{BankCuit: "30-00000000-0", ClosingDay: 1}), storage.ErrNotFound)
+}
+
+func testCustomers(t *testing.T, s Storages) {
+	ctx := context.Background()
+	customer, err := s.Customers.GetCustomerByCuit(ctx, OtherCustomer)
+	require.NoError(t, err)
+	assert.Equal(t, "70000002", customer.Dni)
+	assert.ElementsMatch(t, []string{BankCuit, OtherBankCuit}, customer.BankCuits)
+
+	_, err = s.Customers.CreateCustomer(ctx, models.Customer{CompleteName: "Otra Ana", Dni: "79999999", Cuit: CustomerCuit})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "the CUIT belongs to another customer")
+	_, err = s.Customers.CreateCustomer(ctx, models.Customer{CompleteName: "Otra Ana", Dni: "70000001", Cuit: "27-79999999-9"})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "the DNI belongs to another customer")
+	_, err = s.Customers.GetCustomerByCuit(ctx, "20-00000000-0")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.ErrorIs(t, s.Customers.AddCustomerToBank(ctx, CustomerCuit, BankCuit), storage.ErrAlreadyExists)
+	assert.ErrorIs(t, s.Customers.AddCustomerToBank(ctx, CustomerCuit, "30-00000000-0"), storage.ErrNotFound)
+	assert.ErrorIs(t, s.Customers.RemoveCustomerFromBank(ctx, CustomerCuit, OtherBankCuit), storage.ErrNotFound)
+	require.NoError(t, s.Customers.RemoveCustomerFromBank(ctx, OtherCustomer, OtherBankCuit))
+
+	customers, err := s.Customers.GetCustomers(ctx)
+	require.NoError(t, err)
+	require.Len(t, *customers, 2)
+	assert.Equal(t, OtherCustomer, (*customers)[0].Cuit, "customers are ordered by CUIT")
+	assert.Equal(t, []string{BankCuit}, (*customers)[0].BankCuits)
+}
+
+func testCardLifecycle(t *testing.T, s Storages) {
+	ctx := context.Background()
+	card, err := s.Cards.GetCardByNumber(ctx, CardNumber)
+	require.NoError(t, err)
+	assert.Equal(t, models.CardStatusActive, card.Status)
+	assert.Equal(t, BankCuit, card.Bank.Cuit)
+	assert.Equal(t, CustomerCuit, card.CustomerCuit)
+
+	_, err = s.Cards.IssueCard(ctx, models.Card{Number: CardNumber, Bank: models.Bank{Cuit: BankCuit}, CustomerCuit: CustomerCuit})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	_, err = s.Cards.IssueCard(ctx, models.Card{Number: "4000000000000099", Bank: models.Bank{Cuit: BankCuit}, CustomerCuit: "20-00000000-0"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.Cards.GetCardByNumber(ctx, "4000000000000099")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	card, err = s.Cards.UpdateCardStatus(ctx, CardNumber, models.CardStatusBlocked)
+	require.NoError(t, err)
+	assert.Equal(t, models.CardStatusBlocked, card.Status)
+
+	renewal := date(2035, time.December, 31)
+	card, err = s.Cards.UpdateCardExpiration(ctx, CardNumber, renewal)
+	require.NoError(t, err)
+	assert.True(t, renewal.Equal(card.ExpirationDate), "expected %v, got %v", renewal, card.ExpirationDate)
+
+	_, err = s.Cards.UpdateCardStatus(ctx, "4000000000000099", models.CardStatusCancelled)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testPurchasesInPeriod(t *testing.T, s Storages) {
+	ctx := context.Background()
+	singlePayments, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 5), date(2025, time.April, 10))
+	require.NoError(t, err)
+	assert.Len(t, *singlePayments, 2, "the start of the period is inclusive")
+	assert.Empty(t, *monthlyPayments, "the end of the period is exclusive")
+
+	singlePayments, monthlyPayments, err = s.Cards.GetPurchasesInPeriod(ctx, IdleCardNumber, date(2025, time.January, 1), date(2026, time.January, 1))
+	require.NoError(t, err)
+	assert.Empty(t, *singlePayments)
+	assert.Empty(t, *monthlyPayments)
+}
+
+func testQuotasDueInMonth(t *testing.T, s Storages) {
+	ctx := context.Background()
+	quotas, err := s.Cards.GetQuotasDueInMonth(ctx, CardNumber, 5, 2025)
+	require.NoError(t, err)
+	require.Len(t, *quotas, 1)
+	due := (*quotas)[0]
+	assert.Equal(t, 2, due.Number)
+	assert.Equal(t, models.MustParseMoney("100"), due.Price)
+	assert.Equal(t, "FIN-2025", due.PaymentVoucher)
+	assert.Equal(t, SouthStoreCuit, due.CuitStore)
+	assert.Equal(t, 3, due.NumberOfQuotas)
+
+	quotas, err = s.Cards.GetQuotasDueInMonth(ctx, CardNumber, 7, 2025)
+	require.NoError(t, err)
+	assert.Empty(t, *quotas)
+}
+
+func testPurchaseLookup(t *testing.T, s Storages) {
+	ctx := context.Background()
+	single, err := s.Cards.GetPurchaseSingle(ctx, NorthStoreCuit, models.MustParseMoney("450.09"), "DISC-2025-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("500.10"), single.Amount)
+
+	monthly, err := s.Cards.GetPurchaseMonthly(ctx, SouthStoreCuit, models.MustParseMoney("800"), "FIN-2025")
+	require.NoError(t, err)
+	assert.Equal(t, 2, monthly.NumberOfQuotas)
+	assert.Len(t, monthly.Quota, 2)
+
+	_, err = s.Cards.GetPurchaseSingle(ctx, NorthStoreCuit, models.MustParseMoney("450.10"), "DISC-2025-2")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.Cards.GetPurchaseMonthly(ctx, NorthStoreCuit, models.MustParseMoney("800"), "FIN-2025")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testPurchaseCurrencies(t *testing.T, s Storages) {
+	ctx := context.Background()
+	dollars := purchase("USD-2025", "Tienda Norte", NorthStoreCuit, "25.50", "25.50", date(2025, time.May, 2))
+	dollars.Currency = models.CurrencyUSD
+	_, err := s.Cards.AddPurchaseSinglePayment(ctx, IdleCardNumber, models.PurchaseSinglePayment{Purchase: dollars})
+	require.NoError(t, err)
+
+	singlePayments, _, err := s.Cards.GetPurchasesInPeriod(ctx, IdleCardNumber, date(2025, time.May, 1), date(2025, time.June, 1))
+	require.NoError(t, err)
+	require.Len(t, *singlePayments, 1)
+	assert.Equal(t, models.CurrencyUSD, (*singlePayments)[0].Currency)
+
+	singlePayments, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 1), date(2025, time.May, 1))
+	require.NoError(t, err)
+	require.NotEmpty(t, *singlePayments)
+	require.NotEmpty(t, *monthlyPayments)
+	assert.Equal(t, models.BaseCurrency, (*singlePayments)[0].Currency, "purchases without a currency are in pesos")
+	assert.Equal(t, models.BaseCurrency, (*monthlyPayments)[0].Currency, "purchases without a currency are in pesos")
+}
+
+func testPurchaseInterestModels(t *testing.T, s Storages) {
+	ctx := context.Background()
+	_, err := s.Cards.AddPurchaseMonthlyPayment(ctx, IdleCardNumber, models.PurchaseMonthlyPayment{
+		Purchase:       purchase("FRENCH-2025", "Tienda Sur", SouthStoreCuit, "1000", "1020.07", date(2025, time.May, 2)),
+		Interest:       models.MustParsePercentage("12"),
+		InterestModel:  models.InterestModelFrench,
+		NumberOfQuotas: 3,
+		Quota:          quotas("340.02", 2025, 5, 6, 7),
+	})
+	require.NoError(t, err)
+
+	_, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, IdleCardNumber, date(2025, time.May, 1), date(2025, time.June, 1))
+	require.NoError(t, err)
+	require.Len(t, *monthlyPayments, 1)
+	assert.Equal(t, models.InterestModelFrench, (*monthlyPayments)[0].InterestModel)
+
+	_, monthlyPayments, err = s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.April, 1), date(2025, time.May, 1))
+	require.NoError(t, err)
+	require.NotEmpty(t, *monthlyPayments)
+	assert.Equal(t, models.InterestModelFlat, (*monthlyPayments)[0].InterestModel, "purchases without an interest model are flat")
+
+	detail, err := s.Promotions.GetPromotionByCode(ctx, "FIN-OLD")
+	require.NoError(t, err)
+	require.NotNil(t, detail.Financing)
+	assert.Equal(t, models.InterestModelFrench, detail.Financing.InterestModel)
+	detail, err = s.Promotions.GetPromotionByCode(ctx, "FIN-2025")
+	require.NoError(t, err)
+	require.NotNil(t, detail.Financing)
+	assert.Equal(t, models.InterestModelFlat, detail.Financing.InterestModel, "financings without an interest model are flat")
+}
+
+func testPurchaseVouchers(t *testing.T, s Storages) {
+	ctx := context.Background()
+	_, err := s.Cards.AddPurchaseSinglePayment(ctx, CardNumber, models.PurchaseSinglePayment{
+		Purchase: purchase("DISC-2025", "Tienda Norte", NorthStoreCuit, "100", "100", date(2025, time.May, 2)),
+	})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "vouchers are unique among the single payments of a card")
+	_, err = s.Cards.AddPurchaseMonthlyPayment(ctx, CardNumber, models.PurchaseMonthlyPayment{
+		Purchase:       purchase("FIN-2025", "Tienda Sur", SouthStoreCuit, "200", "200", date(2025, time.May, 2)),
+		NumberOfQuotas: 2,
+		Quota:          quotas("100", 2025, 5, 6),
+	})
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "vouchers are unique among the installment purchases of a card")
+
+	// A voucher can be used again by another card or by the other purchase type
+	_, err = s.Cards.AddPurchaseSinglePayment(ctx, IdleCardNumber, models.PurchaseSinglePayment{
+		Purchase: purchase("DISC-2025", "Tienda Norte", NorthStoreCuit, "100", "100", date(2025, time.May, 2)),
+	})
+	require.NoError(t, err)
+	_, err = s.Cards.AddPurchaseMonthlyPayment(ctx, IdleCardNumber, models.PurchaseMonthlyPayment{
+		Purchase:       purchase("DISC-2025", "Tienda Norte", NorthStoreCuit, "200", "200", date(2025, time.May, 2)),
+		NumberOfQuotas: 2,
+		Quota:          quotas("100", 2025, 5, 6),
+	})
+	require.NoError(t, err)
+
+	singlePayments, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.May, 1), date(2025, time.June, 1))
+	require.NoError(t, err)
+	assert.Empty(t, *singlePayments, "rejected purchases are not stored")
+	assert.Empty(t, *monthlyPayments, "rejected purchases are not stored")
+}
+
+func testPaymentSummary(t *testing.T, s Storages) {
+	ctx := context.Background()
+	_, err := s.Cards.GetPaymentSummary(ctx, CardNumber, 3, 2025)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	singlePayments, _, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 1), date(2025, time.April, 1))
+	require.NoError(t, err)
+	summary := models.PaymentSummary{
+		Code:             "SUM-4000000000000001-2025-03",
+		Month:            3,
+		Year:             2025,
+		FirstExpiration:  date(2025, time.April, 15),
+		SecondExpiration: date(2025, time.April, 25),
+		TotalPrice:       models.MustParseMoney("1350"),
+		Subtotals: []models.CurrencySubtotal{
+			{Currency: models.CurrencyARS, Subtotal: models.MustParseMoney("1350"), ExchangeRate: models.MustParseMoney("1"), Total: models.MustParseMoney("1350")},
+		},
+		SinglePayments: *singlePayments,
+	}
+	_, err = s.Cards.SavePaymentSummary(ctx, CardNumber, summary)
+	require.NoError(t, err)
+	_, err = s.Cards.SavePaymentSummary(ctx, CardNumber, summary)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "a card has at most one summary per month")
+
+	stored, err := s.Cards.GetPaymentSummary(ctx, CardNumber, 3, 2025)
+	require.NoError(t, err)
+	assert.Equal(t, summary.Code, stored.Code)
+	assert.Equal(t, models.MustParseMoney("1350"), stored.TotalPrice)
+	assert.True(t, summary.FirstExpiration.Equal(stored.FirstExpiration), "expected %v, got %v", summary.FirstExpiration, stored.FirstExpiration)
+	assert.Equal(t, summary.Subtotals, stored.Subtotals)
+	assert.Len(t, stored.SinglePayments, 2)
+
+	_, err = s.Cards.GetPaymentSummary(ctx, OtherCardNumber, 3, 2025)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testTop10CardsByPurchases(t *testing.T, s Storages) {
+	ctx := context.Background()
+	cards, err := s.Cards.GetTop10CardsByPurchases(ctx)
+	require.NoError(t, err)
+
+	numbers := make([]string, 0, len(*cards))
+	for _, card := range *cards {
+		numbers = append(numbers, card.Number)
+	}
+	assert.Equal(t, []string{CardNumber, OtherCardNumber, IdleCardNumber}, numbers, "cards are ordered by purchase count")
+}
+
+func testAvailablePromotions(t *testing.T, s Storages) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		storeCuit  string
+		start, end time.Time
+		financings []string
+		discounts  []string
+	}{
+		{name: "promotion covering the range", storeCuit: NorthStoreCuit, start: date(2025, time.March, 10), end: date(2025, time.March, 20), discounts: []string{"DISC-2025"}},
+		{name: "promotion within the range", storeCuit: NorthStoreCuit, start: date(2025, time.February, 1), end: date(2025, time.April, 30), discounts: []string{"DISC-2025"}},
+		{name: "promotion partially overlapping the range", storeCuit: NorthStoreCuit, start: date(2025, time.March, 25), end: date(2025, time.April, 15)},
+		{name: "expired promotion covering the range", storeCuit: SouthStoreCuit, start: date(2024, time.February, 1), end: date(2024, time.March, 1), financings: []string{"FIN-OLD"}},
+		{name: "other store", storeCuit: "30-89999999-9", start: date(2025, time.January, 1), end: date(2025, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			financings, discounts, err := s.Promotions.GetAvailablePromotionsByStoreAndDateRange(ctx, tt.storeCuit, tt.start, tt.end)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.financings, financingCodes(*financings))
+			assert.ElementsMatch(t, tt.discounts, discountCodes(*discounts))
+		})
+	}
+}
+
+func testAddPromotionToUnknownBank(t *testing.T, s Storages) {
+	ctx := context.Background()
+	discount := DefaultFixture().Discounts[0]
+	discount.Code = "DISC-NOBANK"
+	discount.Bank.Cuit = "30-00000000-0"
+	assert.ErrorIs(t, s.Banks.AddDiscountPromotionToBank(ctx, discount), storage.ErrNotFound)
+
+	financing := DefaultFixture().Financings[0]
+	financing.Code = "FIN-NOBANK"
+	financing.Bank.Cuit = "30-00000000-0"
+	assert.ErrorIs(t, s.Banks.AddFinancingPromotionToBank(ctx, financing), storage.ErrNotFound)
+}
+
+func testAddDuplicatePromotion(t *testing.T, s Storages) {
+	ctx := context.Background()
+	discount := DefaultFixture().Discounts[0]
+	assert.ErrorIs(t, s.Banks.AddDiscountPromotionToBank(ctx, discount), storage.ErrAlreadyExists)
+
+	financing := DefaultFixture().Financings[0]
+	assert.ErrorIs(t, s.Banks.AddFinancingPromotionToBank(ctx, financing), storage.ErrAlreadyExists)
+}
+
+func testDeleteAndRestorePromotion(t *testing.T, s Storages) {
+	ctx := context.Background()
+	require.NoError(t, s.Banks.DeleteDiscountPromotion(ctx, "DISC-2025"))
+	require.NoError(t, s.Banks.DeleteFinancingPromotion(ctx, "FIN-2025"))
+
+	detail, err := s.Promotions.GetPromotionByCode(ctx, "DISC-2025")
+	require.NoError(t, err, "deleted promotions can still be looked up")
+	assert.True(t, detail.IsDeleted)
+
+	_, discounts, err := s.Promotions.GetAvailablePromotionsByStoreAndDateRange(ctx, NorthStoreCuit, date(2025, time.March, 10), date(2025, time.March, 20))
+	require.NoError(t, err)
+	assert.Empty(t, *discounts, "deleted promotions are not available")
+
+	require.NoError(t, s.Promotions.RestorePromotion(ctx, "DISC-2025"))
+	detail, err = s.Promotions.GetPromotionByCode(ctx, "DISC-2025")
+	require.NoError(t, err)
+	assert.False(t, detail.IsDeleted)
+
+	assert.ErrorIs(t, s.Banks.DeleteDiscountPromotion(ctx, "UNKNOWN"), storage.ErrNotFound)
+	assert.ErrorIs(t, s.Banks.DeleteFinancingPromotion(ctx, "UNKNOWN"), storage.ErrNotFound)
+	assert.ErrorIs(t, s.Promotions.RestorePromotion(ctx, "UNKNOWN"), storage.ErrNotFound)
+	_, err = s.Promotions.GetPromotionByCode(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testExtendPromotionValidity(t *testing.T, s Storages) {
+	ctx := context.Background()
+	require.NoError(t, s.Banks.ExtendDiscountPromotionValidity(ctx, "DISC-2025", date(2025, time.April, 30)))
+	require.NoError(t, s.Banks.ExtendFinancingPromotionValidity(ctx, "FIN-OLD", date(2024, time.December, 31)))
+
+	_, discounts, err := s.Promotions.GetAvailablePromotionsByStoreAndDateRange(ctx, NorthStoreCuit, date(2025, time.March, 25), date(2025, time.April, 15))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DISC-2025"}, discountCodes(*discounts), "the extended discount now covers the range")
+
+	detail, err := s.Promotions.GetPromotionByCode(ctx, "FIN-OLD")
+	require.NoError(t, err)
+	require.NotNil(t, detail.Financing)
+	assert.True(t, sameDay(detail.Financing.ValidityEndDate, date(2024, time.December, 31)), "got %s", detail.Financing.ValidityEndDate)
+
+	assert.ErrorIs(t, s.Banks.ExtendDiscountPromotionValidity(ctx, "UNKNOWN", date(2025, time.April, 30)), storage.ErrNotFound)
+	assert.ErrorIs(t, s.Banks.ExtendFinancingPromotionValidity(ctx, "UNKNOWN", date(2025, time.April, 30)), storage.ErrNotFound)
+}
+
+func testUpdatePromotion(t *testing.T, s Storages) {
+	ctx := context.Background()
+	title := "Autumn discount"
+	percentage := models.MustParsePercentage("20")
+	quotas := 12
+	require.NoError(t, s.Promotions.UpdatePromotion(ctx, "DISC-2025", models.PromotionUpdate{PromotionTitle: &title, DiscountPercentage: &percentage, NumberOfQuotas: &quotas}))
+
+	detail, err := s.Promotions.GetPromotionByCode(ctx, "DISC-2025")
+	require.NoError(t, err)
+	assert.Equal(t, models.PromotionTypeDiscount, detail.Type)
+	require.NotNil(t, detail.Discount)
+	assert.Equal(t, title, detail.Discount.PromotionTitle)
+	assert.Equal(t, percentage, detail.Discount.DiscountPercentage)
+	assert.Equal(t, models.MustParseMoney("5000"), detail.Discount.PriceCap, "fields absent from the update are kept")
+
+	require.NoError(t, s.Promotions.UpdatePromotion(ctx, "FIN-2025", models.PromotionUpdate{NumberOfQuotas: &quotas}))
+	detail, err = s.Promotions.GetPromotionByCode(ctx, "FIN-2025")
+	require.NoError(t, err)
+	assert.Equal(t, models.PromotionTypeFinancing, detail.Type)
+	require.NotNil(t, detail.Financing)
+	assert.Equal(t, quotas, detail.Financing.NumberOfQuotas)
+
+	assert.ErrorIs(t, s.Promotions.UpdatePromotion(ctx, "UNKNOWN", models.PromotionUpdate{PromotionTitle: &title}), storage.ErrNotFound)
+}
+
+func testBankPromotions(t *testing.T, s Storages) {
+	ctx := context.Background()
+	require.NoError(t, s.Banks.DeleteDiscountPromotion(ctx, "DISC-2025"))
+
+	tests := []struct {
+		name       string
+		bankCuit   string
+		status     models.PromotionStatus
+		date       time.Time
+		financings []string
+		discounts  []string
+	}{
+		{name: "active", bankCuit: BankCuit, status: models.PromotionStatusActive, date: date(2025, time.March, 15), financings: []string{"FIN-2025"}},
+		{name: "active ordered by start date", bankCuit: BankCuit, status: models.PromotionStatusActive, date: date(2024, time.March, 1), financings: []string{"FIN-OLD", "FIN-2025"}},
+		{name: "expired", bankCuit: BankCuit, status: models.PromotionStatusExpired, date: date(2025, time.March, 15), financings: []string{"FIN-OLD"}},
+		{name: "deleted", bankCuit: BankCuit, status: models.PromotionStatusDeleted, date: date(2025, time.March, 15), discounts: []string{"DISC-2025"}},
+		{name: "other bank", bankCuit: OtherBankCuit, status: models.PromotionStatusActive, date: date(2025, time.October, 1), discounts: []string{"DISC-SPRING"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			financings, discounts, err := s.Promotions.GetBankPromotions(ctx, tt.bankCuit, tt.status, tt.date)
+			require.NoError(t, err)
+			assert.Equal(t, orEmpty(tt.financings), financingCodes(*financings))
+			assert.Equal(t, orEmpty(tt.discounts), discountCodes(*discounts))
+		})
+	}
+
+	_, _, err := s.Promotions.GetBankPromotions(ctx, "30-00000000-0", models.PromotionStatusActive, date(2025, time.March, 15))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testApplicablePromotions(t *testing.T, s Storages) {
+	ctx := context.Background()
+	financings, discounts, err := s.Promotions.GetApplicablePromotions(ctx, BankCuit, NorthStoreCuit, date(2025, time.March, 31))
+	require.NoError(t, err)
+	assert.Empty(t, *financings)
+	assert.Equal(t, []string{"DISC-2025"}, discountCodes(*discounts), "the last day of the validity is included")
+
+	financings, _, err = s.Promotions.GetApplicablePromotions(ctx, BankCuit, SouthStoreCuit, date(2025, time.June, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FIN-2025"}, financingCodes(*financings))
+
+	_, discounts, err = s.Promotions.GetApplicablePromotions(ctx, OtherBankCuit, NorthStoreCuit, date(2025, time.March, 15))
+	require.NoError(t, err)
+	assert.Empty(t, *discounts, "promotions of other banks do not apply")
+}
+
+func testMostUsedPromotion(t *testing.T, s Storages) {
+	ctx := context.Background()
+	mostUsed, err := s.Promotions.GetMostUsedPromotion(ctx)
+	require.NoError(t, err)
+
+	// FIN-2025 is used by one single payment and two installment purchases, DISC-2025 by a single payment
+	financing, ok := mostUsed.(*models.Financing)
+	require.True(t, ok, "expected a financing, got %T", mostUsed)
+	assert.Equal(t, "FIN-2025", financing.Code)
+}
+
+func testStoreWithHighestRevenue(t *testing.T, s Storages) {
+	ctx := context.Background()
+	// March 2025: Tienda Sur sells 700 + 800 across both kinds of purchase, Tienda Norte 900 + 450.09
+	store, err := s.Stores.GetStoreWithHighestRevenueByMonth(ctx, 3, 2025)
+	require.NoError(t, err)
+	assert.Equal(t, models.StoreDTO{Name: "Tienda Sur", Cuit: SouthStoreCuit}, store)
+
+	store, err = s.Stores.GetStoreWithHighestRevenueByMonth(ctx, 1, 2020)
+	require.NoError(t, err)
+	assert.Equal(t, models.StoreDTO{}, store)
+}
+
+func testCompensation(t *testing.T, s Storages) {
+	ctx := context.Background()
+	const bankCuit, customerCuit, cardNumber = "30-79999999-9", "20-79999999-9", "4000000000000099"
+
+	_, err := s.Banks.CreateBank(ctx, models.Bank{Name: "Banco Efímero", Cuit: bankCuit})
+	require.NoError(t, err)
+	require.NoError(t, s.Compensation.RemoveBank(ctx, bankCuit))
+	_, err = s.Banks.GetBankByCuit(ctx, bankCuit)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, s.Compensation.RemoveBank(ctx, bankCuit), storage.ErrNotFound)
+
+	_, err = s.Customers.CreateCustomer(ctx, models.Customer{CompleteName: "Cliente Efímero", Dni: "79999999", Cuit: customerCuit, EntryDate: date(2025, time.March, 1)})
+	require.NoError(t, err)
+	require.NoError(t, s.Compensation.RemoveCustomer(ctx, customerCuit))
+	_, err = s.Customers.GetCustomerByCuit(ctx, customerCuit)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, s.Compensation.RemoveCustomer(ctx, customerCuit), storage.ErrNotFound)
+
+	_, err = s.Cards.IssueCard(ctx, models.Card{
+		Number: cardNumber, Ccv: "999", CardholderNameInCard: "ANA PEREZ", Since: date(2025, time.March, 1), ExpirationDate: date(2030, time.March, 31),
+		Bank: models.Bank{Cuit: BankCuit}, CustomerCuit: CustomerCuit,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Compensation.RemoveCard(ctx, cardNumber))
+	_, err = s.Cards.GetCardByNumber(ctx, cardNumber)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, s.Compensation.RemoveCard(ctx, cardNumber), storage.ErrNotFound)
+	_, err = s.Customers.GetCustomerByCuit(ctx, CustomerCuit)
+	assert.NoError(t, err, "the holder of a removed card is kept")
+
+	require.NoError(t, s.Banks.AddDiscountPromotionToBank(ctx, models.Discount{
+		Promotion:          promotion("DISC-TEMP", "Temporary discount", "Tienda Norte", NorthStoreCuit, BankCuit, date(2025, time.May, 1), date(2025, time.May, 31)),
+		DiscountPercentage: models.MustParsePercentage("5"),
+	}))
+	require.NoError(t, s.Banks.AddFinancingPromotionToBank(ctx, models.Financing{
+		Promotion:      promotion("FIN-TEMP", "Temporary financing", "Tienda Sur", SouthStoreCuit, BankCuit, date(2025, time.May, 1), date(2025, time.May, 31)),
+		NumberOfQuotas: 3,
+	}))
+	for _, code := range []string{"DISC-TEMP", "FIN-TEMP"} {
+		require.NoError(t, s.Compensation.RemovePromotion(ctx, code), code)
+		_, err = s.Promotions.GetPromotionByCode(ctx, code)
+		assert.ErrorIs(t, err, storage.ErrNotFound, code)
+		assert.ErrorIs(t, s.Compensation.RemovePromotion(ctx, code), storage.ErrNotFound, code)
+	}
+
+	purchaseDate := date(2025, time.May, 10)
+	_, err = s.Cards.AddPurchaseSinglePayment(ctx, IdleCardNumber, models.PurchaseSinglePayment{Purchase: purchase("SINGLE-TEMP", "Tienda Norte", NorthStoreCuit, "100", "100", purchaseDate)})
+	require.NoError(t, err)
+	_, err = s.Cards.AddPurchaseMonthlyPayment(ctx, IdleCardNumber, models.PurchaseMonthlyPayment{
+		Purchase:       purchase("MONTHLY-TEMP", "Tienda Sur", SouthStoreCuit, "200", "200", purchaseDate),
+		NumberOfQuotas: 2,
+		Quota:          quotas("100", 2025, 5, 6),
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Compensation.RemovePurchaseSinglePayment(ctx, IdleCardNumber, "SINGLE-TEMP"))
+	require.NoError(t, s.Compensation.RemovePurchaseMonthlyPayment(ctx, IdleCardNumber, "MONTHLY-TEMP"))
+	singlePayments, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, IdleCardNumber, date(2025, time.May, 1), date(2025, time.June, 1))
+	require.NoError(t, err)
+	assert.Empty(t, *singlePayments)
+	assert.Empty(t, *monthlyPayments)
+	dueQuotas, err := s.Cards.GetQuotasDueInMonth(ctx, IdleCardNumber, 5, 2025)
+	require.NoError(t, err)
+	assert.Empty(t, *dueQuotas, "the quotas of a removed purchase are removed")
+	assert.ErrorIs(t, s.Compensation.RemovePurchaseMonthlyPayment(ctx, IdleCardNumber, "MONTHLY-TEMP"), storage.ErrNotFound)
+	assert.ErrorIs(t, s.Compensation.RemovePurchaseSinglePayment(ctx, CardNumber, "SINGLE-TEMP"), storage.ErrNotFound)
+
+	summary := models.PaymentSummary{
+		Code: "SUM-TEMP", Month: 5, Year: 2025, FirstExpiration: date(2025, time.June, 15), SecondExpiration: date(2025, time.June, 25), TotalPrice: models.MustParseMoney("100"),
+		Subtotals: []models.CurrencySubtotal{{Currency: models.CurrencyARS, Subtotal: models.MustParseMoney("100"), ExchangeRate: models.MustParseMoney("1"), Total: models.MustParseMoney("100")}},
+	}
+	_, err = s.Cards.SavePaymentSummary(ctx, IdleCardNumber, summary)
+	require.NoError(t, err)
+	require.NoError(t, s.Compensation.RemovePaymentSummary(ctx, IdleCardNumber, 5, 2025))
+	_, err = s.Cards.GetPaymentSummary(ctx, IdleCardNumber, 5, 2025)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.Cards.SavePaymentSummary(ctx, IdleCardNumber, summary)
+	assert.NoError(t, err, "the month can be billed again")
+
+	require.NoError(t, s.Banks.SaveBillingCycle(ctx, models.BillingCycle{BankCuit: OtherBankCuit, ClosingDay: 10, FirstDueDays: 10, SecondDueDays: 5}))
+	require.NoError(t, s.Compensation.RemoveBillingCycle(ctx, OtherBankCuit))
+	cycle, err := s.Banks.GetBillingCycle(ctx, OtherBankCuit)
+	require.NoError(t, err)
+	assert.Nil(t, cycle)
+	assert.ErrorIs(t, s.Compensation.RemoveBillingCycle(ctx, OtherBankCuit), storage.ErrNotFound)
+
+	require.NoError(t, s.Compensation.RemoveExchangeRate(ctx, models.CurrencyUSD, date(2025, time.April, 1)))
+	rate, err := s.ExchangeRates.GetExchangeRate(ctx, models.CurrencyUSD, date(2025, time.April, 1))
+	require.NoError(t, err)
+	assert.True(t, date(2025, time.March, 31).Equal(rate.Date), "the previous rate applies, got %v", rate.Date)
+	assert.ErrorIs(t, s.Compensation.RemoveExchangeRate(ctx, models.CurrencyUSD, date(2025, time.April, 1)), storage.ErrNotFound)
+}
+
+func testPayments(t *testing.T, s Storages) {
+	ctx := context.Background()
+	_, err := s.Payments.GetPayments(ctx, CardNumber, 4, 2025)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.Payments.RecordPayment(ctx, models.Payment{Code: "PAY-0", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("1")})
+	assert.ErrorIs(t, err, storage.ErrNotFound, "a payment needs a summary")
+
+	saveBilledSummary(t, s)
+	payments, err := s.Payments.GetPayments(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+	assert.Empty(t, *payments)
+
+	first := models.Payment{
+		Code: "PAY-1", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("600"),
+		PaymentDate: time.Date(2025, time.May, 5, 10, 0, 0, 0, time.UTC),
+		Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "FIN-2025", QuotaNumber: 1, Amount: models.MustParseMoney("100"), BilledAmount: models.MustParseMoney("100")},
+			{PaymentVoucher: "DISC-2025", PurchaseDate: datePtr(2025, time.March, 5), Amount: models.MustParseMoney("500"), BilledAmount: models.MustParseMoney("900")},
+		},
+	}
+	recorded, err := s.Payments.RecordPayment(ctx, first)
+	require.NoError(t, err)
+	assertPayment(t, first, *recorded)
+	_, err = s.Payments.RecordPayment(ctx, first)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "payment codes are unique")
+
+	unbilled := models.Payment{
+		Code: "PAY-X", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("150"), PaymentDate: date(2025, time.May, 6),
+		Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "DISC-2025-2", PurchaseDate: datePtr(2025, time.March, 12), Amount: models.MustParseMoney("50"), BilledAmount: models.MustParseMoney("450.09")},
+			{PaymentVoucher: "FIN-2025", QuotaNumber: 2, Amount: models.MustParseMoney("100"), BilledAmount: models.MustParseMoney("100")},
+		},
+	}
+	_, err = s.Payments.RecordPayment(ctx, unbilled)
+	assert.ErrorIs(t, err, storage.ErrNotFound, "the second quota is billed in May")
+
+	second := models.Payment{
+		Code: "PAY-2", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("450"),
+		PaymentDate: time.Date(2025, time.May, 1, 9, 30, 0, 0, time.UTC),
+		Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "DISC-2025", PurchaseDate: datePtr(2025, time.March, 5), Amount: models.MustParseMoney("400"), BilledAmount: models.MustParseMoney("900")},
+			{PaymentVoucher: "DISC-2025-2", PurchaseDate: datePtr(2025, time.March, 12), Amount: models.MustParseMoney("50"), BilledAmount: models.MustParseMoney("450.09")},
+		},
+	}
+	_, err = s.Payments.RecordPayment(ctx, second)
+	require.NoError(t, err)
+
+	// A payment planned before the others were recorded would pay the purchase of March 5 beyond what was billed
+	stale := models.Payment{
+		Code: "PAY-3", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("20"), PaymentDate: date(2025, time.May, 7),
+		Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "DISC-2025-2", PurchaseDate: datePtr(2025, time.March, 12), Amount: models.MustParseMoney("10"), BilledAmount: models.MustParseMoney("450.09")},
+			{PaymentVoucher: "DISC-2025", PurchaseDate: datePtr(2025, time.March, 5), Amount: models.MustParseMoney("10"), BilledAmount: models.MustParseMoney("900")},
+		},
+	}
+	_, err = s.Payments.RecordPayment(ctx, stale)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists, "items cannot be paid beyond their billed amount")
+
+	payments, err = s.Payments.GetPayments(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+	require.Len(t, *payments, 2)
+	assertPayment(t, second, (*payments)[0])
+	assertPayment(t, first, (*payments)[1])
+
+	summary, err := s.Cards.GetPaymentSummary(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+	require.Len(t, summary.Quotas, 1)
+	assert.Equal(t, models.MustParseMoney("100"), summary.Quotas[0].PaidAmount)
+	assert.Equal(t, map[string]models.Money{"2025-03-05": models.MustParseMoney("900"), "2025-03-12": models.MustParseMoney("50")}, singlePaidAmounts(summary.SinglePayments))
+
+	singlePayments, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 1), date(2025, time.May, 1))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.Money{"2025-03-05": models.MustParseMoney("900"), "2025-03-12": models.MustParseMoney("50")}, singlePaidAmounts(*singlePayments))
+	require.Len(t, *monthlyPayments, 1)
+	purchaseQuotas := (*monthlyPayments)[0].Quota
+	sort.Slice(purchaseQuotas, func(i, j int) bool { return purchaseQuotas[i].Number < purchaseQuotas[j].Number })
+	assert.Equal(t, []models.Money{models.MustParseMoney("100"), 0, 0}, []models.Money{purchaseQuotas[0].PaidAmount, purchaseQuotas[1].PaidAmount, purchaseQuotas[2].PaidAmount})
+
+	_, err = s.Payments.GetPayments(ctx, OtherCardNumber, 4, 2025)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testPaymentSummaries(t *testing.T, s Storages) {
+	ctx := context.Background()
+	summaries, err := s.Payments.GetPaymentSummaries(ctx, CardNumber)
+	require.NoError(t, err)
+	assert.Empty(t, *summaries)
+
+	saveBilledSummary(t, s)
+	_, err = s.Cards.SavePaymentSummary(ctx, CardNumber, models.PaymentSummary{
+		Code: "SUM-2024-12", Month: 12, Year: 2024, FirstExpiration: date(2025, time.January, 15), SecondExpiration: date(2025, time.January, 25),
+	})
+	require.NoError(t, err)
+
+	summaries, err = s.Payments.GetPaymentSummaries(ctx, CardNumber)
+	require.NoError(t, err)
+	require.Len(t, *summaries, 2)
+	assert.Equal(t, "SUM-2024-12", (*summaries)[0].Code, "summaries are ordered by year and month")
+	assert.Equal(t, "SUM-2025-04", (*summaries)[1].Code)
+	assert.Len(t, (*summaries)[1].Quotas, 1)
+	assert.Len(t, (*summaries)[1].SinglePayments, 2)
+
+	_, err = s.Payments.GetPaymentSummaries(ctx, "4000000000009999")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testRemovePayment(t *testing.T, s Storages) {
+	ctx := context.Background()
+	saveBilledSummary(t, s)
+	for _, payment := range []models.Payment{
+		{Code: "PAY-KEEP", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("150"), PaymentDate: date(2025, time.May, 2), Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "FIN-2025", QuotaNumber: 1, Amount: models.MustParseMoney("100"), BilledAmount: models.MustParseMoney("100")},
+			{PaymentVoucher: "DISC-2025", PurchaseDate: datePtr(2025, time.March, 5), Amount: models.MustParseMoney("50"), BilledAmount: models.MustParseMoney("900")},
+		}},
+		{Code: "PAY-TEMP", CardNumber: CardNumber, Month: 4, Year: 2025, Amount: models.MustParseMoney("300"), PaymentDate: date(2025, time.May, 3), Allocations: []models.PaymentAllocation{
+			{PaymentVoucher: "DISC-2025", PurchaseDate: datePtr(2025, time.March, 5), Amount: models.MustParseMoney("300"), BilledAmount: models.MustParseMoney("900")},
+		}},
+	} {
+		_, err := s.Payments.RecordPayment(ctx, payment)
+		require.NoError(t, err, payment.Code)
+	}
+
+	require.NoError(t, s.Compensation.RemovePayment(ctx, "PAY-TEMP"))
+	payments, err := s.Payments.GetPayments(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+	require.Len(t, *payments, 1)
+	assert.Equal(t, "PAY-KEEP", (*payments)[0].Code)
+
+	summary, err := s.Cards.GetPaymentSummary(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("100"), summary.Quotas[0].PaidAmount, "the allocations of other payments are kept")
+	assert.Equal(t, models.MustParseMoney("50"), singlePaidAmounts(summary.SinglePayments)["2025-03-05"], "the allocations of the removed payment are taken off")
+	singlePayments, _, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 1), date(2025, time.April, 1))
+	require.NoError(t, err)
+	assert.Equal(t, models.MustParseMoney("50"), singlePaidAmounts(*singlePayments)["2025-03-05"])
+
+	assert.ErrorIs(t, s.Compensation.RemovePayment(ctx, "PAY-TEMP"), storage.ErrNotFound)
+}
+
+// saveBilledSummary stores the April 2025 summary of CardNumber, billing its March single-payment purchases and
+// the first quota of its installment purchase.
+func saveBilledSummary(t *testing.T, s Storages) {
+	ctx := context.Background()
+	singlePayments, _, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.March, 1), date(2025, time.April, 1))
+	require.NoError(t, err)
+	_, monthlyPayments, err := s.Cards.GetPurchasesInPeriod(ctx, CardNumber, date(2025, time.April, 1), date(2025, time.May, 1))
+	require.NoError(t, err)
+	dueQuotas, err := s.Cards.GetQuotasDueInMonth(ctx, CardNumber, 4, 2025)
+	require.NoError(t, err)
+
+	_, err = s.Cards.SavePaymentSummary(ctx, CardNumber, models.PaymentSummary{
+		Code: "SUM-2025-04", Month: 4, Year: 2025, FirstExpiration: date(2025, time.May, 15), SecondExpiration: date(2025, time.May, 25),
+		TotalPrice:      models.MustParseMoney("1450.09"),
+		Subtotals:       []models.CurrencySubtotal{{Currency: models.CurrencyARS, Subtotal: models.MustParseMoney("1450.09"), ExchangeRate: models.MustParseMoney("1"), Total: models.MustParseMoney("1450.09")}},
+		SinglePayments:  *singlePayments,
+		MonthlyPayments: *monthlyPayments,
+		Quotas:          *dueQuotas,
+	})
+	require.NoError(t, err)
+}
+
+// assertPayment checks a stored payment against the one recorded, comparing dates as instants.
+func assertPayment(t *testing.T, expected models.Payment, actual models.Payment) {
+	t.Helper()
+	assert.Equal(t, expected.Code, actual.Code)
+	assert.Equal(t, []any{expected.CardNumber, expected.Month, expected.Year, expected.Amount}, []any{actual.CardNumber, actual.Month, actual.Year, actual.Amount})
+	assert.True(t, expected.PaymentDate.Equal(actual.PaymentDate), "expected %v, got %v", expected.PaymentDate, actual.PaymentDate)
+	require.Len(t, actual.Allocations, len(expected.Allocations))
+	for i, allocation := range expected.Allocations {
+		stored := actual.Allocations[i]
+		assert.Equal(t, []any{allocation.PaymentVoucher, allocation.QuotaNumber, allocation.Amount}, []any{stored.PaymentVoucher, stored.QuotaNumber, stored.Amount}, "allocation %d", i)
+		if allocation.PurchaseDate == nil {
+			assert.Nil(t, stored.PurchaseDate, "allocation %d", i)
+		} else if assert.NotNil(t, stored.PurchaseDate, "allocation %d", i) {
+			assert.True(t, allocation.PurchaseDate.Equal(*stored.PurchaseDate), "allocation %d: expected %v, got %v", i, allocation.PurchaseDate, stored.PurchaseDate)
+		}
+	}
+}
+
+// singlePaidAmounts returns the paid amounts of single-payment purchases by purchase day.
+func singlePaidAmounts(purchases []models.PurchaseSinglePayment) map[string]models.Money {
+	paid := map[string]models.Money{}
+	for _, purchase := range purchases {
+		paid[purchase.PurchaseDate.UTC().Format(time.DateOnly)] = purchase.PaidAmount
+	}
+	return paid
+}
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	d := date(year, month, day)
+	return &d
+}
+
+func testExchangeRates(t *testing.T, s Storages) {
+	ctx := context.Background()
+	rates, err := s.ExchangeRates.GetExchangeRates(ctx, models.CurrencyUSD, date(2025, time.March, 31), date(2025, time.April, 30))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Money{models.MustParseMoney("1050"), models.MustParseMoney("1060.50"), models.MustParseMoney("1072.25")}, rateValues(*rates), "both ends are inclusive")
+
+	rate, err := s.ExchangeRates.GetExchangeRate(ctx, models.CurrencyUSD, time.Date(2025, time.April, 15, 18, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyUSD, rate.Currency)
+	assert.True(t, date(2025, time.April, 1).Equal(rate.Date), "the latest rate on or before the day applies, got %v", rate.Date)
+	assert.Equal(t, models.MustParseMoney("1060.50"), rate.Rate)
+
+	_, err = s.ExchangeRates.GetExchangeRate(ctx, models.CurrencyUSD, date(2025, time.March, 30))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.ExchangeRates.GetExchangeRate(ctx, "EUR", date(2025, time.April, 15))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// A day has at most one rate, saving it again replaces it
+	require.NoError(t, s.ExchangeRates.SaveExchangeRates(ctx, []models.ExchangeRate{
+		{Currency: models.CurrencyUSD, Date: time.Date(2025, time.April, 1, 15, 0, 0, 0, time.UTC), Rate: models.MustParseMoney("1065")},
+		{Currency: models.CurrencyUSD, Date: date(2025, time.April, 2), Rate: models.MustParseMoney("1066")},
+	}))
+	rates, err = s.ExchangeRates.GetExchangeRates(ctx, models.CurrencyUSD, date(2025, time.April, 1), date(2025, time.April, 2))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Money{models.MustParseMoney("1065"), models.MustParseMoney("1066")}, rateValues(*rates))
+}
+
+func rateValues(rates []models.ExchangeRate) []models.Money {
+	values := make([]models.Money, 0, len(rates))
+	for _, rate := range rates {
+		values = append(values, rate.Rate)
+	}
+	return values
+}
+
+func bankCuits(banks []models.Bank) []string {
+	cuits := make([]string, 0, len(banks))
+	for _, bank := range banks {
+		cuits = append(cuits, bank.Cuit)
+	}
+	return cuits
+}
+
+func financingCodes(financings []models.Financing) []string {
+	codes := make([]string, 0, len(financings))
+	for _, financing := range financings {
+		codes = append(codes, financing.Code)
+	}
+	return codes
+}
+
+func discountCodes(discounts []models.Discount) []string {
+	codes := make([]string, 0, len(discounts))
+	for _, discount := range discounts {
+		codes = append(codes, discount.Code)
+	}
+	return codes
+}
+
+func orEmpty(codes []string) []string {
+	if codes == nil {
+		return []string{}
+	}
+	return codes
+}
+
+// sameDay reports whether a validity date, as returned by a storage, falls on the same day as want.
+func sameDay(validity string, want time.Time) bool {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, validity); err == nil {
+			return parsed.Format(time.DateOnly) == want.Format(time.DateOnly)
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+ * Payment Registration System - MongoDB Schema Component Tests
+ * -------------------------------------------------------------
+ * This file checks that the indexes and validators declared for MongoDB are applied idempotently
+ * and enforced by the server. It works on its own database, recreated by the test.
+ *
+ * Created: Mar. 20, 2025
+ * License: GNU General Public License v3.0
+ */
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const schemaDatabase = "payment_registration_schema"
+
+func TestMongoSchemaIsAppliedOnce(t *testing.T) {
+	db, err := nonrelational.NewMongoDB(contractMongoURI, schemaDatabase, true)
+	require.NoError(t, err, "Error initializing the MongoDB schema database")
+	t.Cleanup(func() { _ = nonrelational.CloseMongoDB(db.Client()) })
+
+	changes, err := nonrelational.EnsureSchema(context.Background(), db)
+	require.NoError(t, err)
+	require.Empty(t, changes, "a second run must not change anything")
+}
+
+func TestMongoSchemaIsEnforced(t *testing.T) {
+	ctx := context.Background()
+	db, err := nonrelational.NewMongoDB(contractMongoURI, schemaDatabase, true)
+	require.NoError(t, err, "Error initializing the MongoDB schema database")
+	t.Cleanup(func() { _ = nonrelational.CloseMongoDB(db.Client()) })
+
+	bank := bson.M{"name": "Banco Esquema", "cuit": "30-90000001-1"}
+	_, err = db.Collection("banks").InsertOne(ctx, bank)
+	require.NoError(t, err)
+
+	// The bank CUIT is unique
+	_, err = db.Collection("banks").InsertOne(ctx, bson.M{"name": "Banco Copia", "cuit": "30-90000001-1"})
+	require.True(t, mongo.IsDuplicateKeyError(err), "expected a duplicate key error, got %v", err)
+
+	// A bank without CUIT is rejected by the validator
+	_, err = db.Collection("banks").InsertOne(ctx, bson.M{"name": "Banco Sin CUIT"})
+	var writeErr mongo.WriteException
+	require.ErrorAs(t, err, &writeErr)
+	require.True(t, writeErr.HasErrorCode(121), "expected a document validation failure, got %v", err)
+
+	// A promotion code is unique within its collection
+	discount := bson.M{
+		"promotion_entity": bson.M{
+			"code":                "ESQ-2025",
+			"validity_start_date": bson.NewDateTimeFromTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			"validity_end_date":   bson.NewDateTimeFromTime(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)),
+		},
+		"discount_percentage": 10.0,
+		"is_deleted":          false,
+	}
+	_, err = db.Collection("discounts").InsertOne(ctx, discount)
+	require.NoError(t, err)
+	_, err = db.Collection("discounts").InsertOne(ctx, discount)
+	require.True(t, mongo.IsDuplicateKeyError(err), "expected a duplicate key error, got %v", err)
+}
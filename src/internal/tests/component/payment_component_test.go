@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/services"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage"
 	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/entities"
 	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
 	non_relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational/repository"
@@ -152,6 +154,7 @@ func TestMain(m *testing.M) {
 // ---------------     BANK TESTS --------------------
 // ---------------------------------------------------
 func TestAddFinancingPromotionToBank(t *testing.T) {
+	ctx := context.Background()
 	var codeString = "PROMO123TEST"
 	// Create a new bank
 	newBank := models.Bank{
@@ -178,14 +181,14 @@ func TestAddFinancingPromotionToBank(t *testing.T) {
 			Bank:              newBank,
 		},
 		NumberOfQuotas: 12,
-		Interest:       5.5, // Interest rate
+		Interest:       models.MustParsePercentage("5.5"), // Interest rate
 	}
 
 	// ------ SQL (MySQL) ------
 
 	// Use BankRelationalRepository to insert the financing promotion
 	bankRepo := relational_repository.NewBankRelationalRepository(SQLDatabase)
-	err := bankRepo.AddFinancingPromotionToBank(newFinancingPromotion)
+	err := bankRepo.AddFinancingPromotionToBank(ctx, newFinancingPromotion)
 	assert.NoError(t, err, "Error inserting financing promotion into MySQL")
 
 	// Verify that the promotion was inserted
@@ -202,7 +205,7 @@ func TestAddFinancingPromotionToBank(t *testing.T) {
 
 	// Use MongoDB repository
 	noSQLBankRepo := non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase)
-	err = noSQLBankRepo.AddFinancingPromotionToBank(newFinancingPromotion)
+	err = noSQLBankRepo.AddFinancingPromotionToBank(ctx, newFinancingPromotion)
 	assert.NoError(t, err, "Error inserting financing promotion into MongoDB")
 
 	var resultMongo struct {
@@ -240,13 +243,14 @@ func TestAddFinancingPromotionToBank(t *testing.T) {
 }
 
 func TestBankExtendPromotionValidity(t *testing.T) {
+	ctx := context.Background()
 	// TestBank Financing Promotion
 	testCode := "PROMO123TEST"
 	testTime := time.Now().AddDate(0, 1, 1)
 
 	bankRepo := relational_repository.NewBankRelationalRepository(SQLDatabase)
 
-	err := bankRepo.ExtendFinancingPromotionValidity(testCode, testTime)
+	err := bankRepo.ExtendFinancingPromotionValidity(ctx, testCode, testTime)
 	assert.NoError(t, err, "Error extending financing promotion validity in MySQL")
 
 	// Validate in SQL Database
@@ -260,7 +264,7 @@ func TestBankExtendPromotionValidity(t *testing.T) {
 	testDiscountCode := "WINTERSALE2024"
 	testDiscountTime := time.Now().AddDate(0, 1, 3)
 
-	err = bankRepo.ExtendDiscountPromotionValidity(testDiscountCode, testDiscountTime)
+	err = bankRepo.ExtendDiscountPromotionValidity(ctx, testDiscountCode, testDiscountTime)
 	assert.NoError(t, err, "Error extending discount promotion validity in MySQL")
 
 	// Validate in SQL Database
@@ -275,7 +279,7 @@ func TestBankExtendPromotionValidity(t *testing.T) {
 	// Use MongoDB repository
 	noSQLBankRepo := non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase)
 
-	err = noSQLBankRepo.ExtendFinancingPromotionValidity(testCode, testTime)
+	err = noSQLBankRepo.ExtendFinancingPromotionValidity(ctx, testCode, testTime)
 	fmt.Println(err)
 	assert.NoError(t, err, "Error extending financing promotion validity in MongoDB")
 
@@ -295,12 +299,13 @@ func TestBankExtendPromotionValidity(t *testing.T) {
 }
 
 func TestBankDeleteFinancingPromotion(t *testing.T) {
+	ctx := context.Background()
 	// TestBank Financing Promotion
 	testCode := "PV20241001"
 
 	bankRepo := relational_repository.NewBankRelationalRepository(SQLDatabase)
 
-	bankRepo.DeleteFinancingPromotion(testCode)
+	bankRepo.DeleteFinancingPromotion(ctx, testCode)
 
 	var promotion entities.FinancingEntitySQL
 	if err := SQLDatabase.Where("code = ?", testCode).First(&promotion).Error; err != nil {
@@ -314,17 +319,18 @@ func TestBankDeleteFinancingPromotion(t *testing.T) {
 	// Use MongoDB repository
 	noSQLBankRepo := non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase)
 
-	err := noSQLBankRepo.DeleteFinancingPromotion(testCode)
+	err := noSQLBankRepo.DeleteFinancingPromotion(ctx, testCode)
 	assert.NoError(t, err, "Error deleting financing promotion in MongoDB")
 }
 
 func TestBankDeleteDiscountPromotion(t *testing.T) {
+	ctx := context.Background()
 	// Test Financing Promotion
 	testCode := "SPRINGDEAL2024"
 
 	bankRepo := relational_repository.NewBankRelationalRepository(SQLDatabase)
 
-	bankRepo.DeleteDiscountPromotion(testCode)
+	bankRepo.DeleteDiscountPromotion(ctx, testCode)
 
 	var promotion entities.DiscountEntitySQL
 	if err := SQLDatabase.Where("code = ?", testCode).First(&promotion).Error; err != nil {
@@ -337,15 +343,52 @@ func TestBankDeleteDiscountPromotion(t *testing.T) {
 
 	// Use MongoDB repository
 	noSQLBankRepo := non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase)
-	err := noSQLBankRepo.DeleteDiscountPromotion(testCode)
+	err := noSQLBankRepo.DeleteDiscountPromotion(ctx, testCode)
 	assert.NoError(t, err, "Error deleting discount promotion in MongoDB")
 
 }
 
+func TestPromotionDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	testCode := "WINTERSALE2024"
+
+	repositories := map[string]struct {
+		bank      storage.IBankStorage
+		promotion storage.IPromotionStorage
+	}{
+		"MySQL":   {relational_repository.NewBankRelationalRepository(SQLDatabase), relational_repository.NewPromotionRelationRepository(SQLDatabase)},
+		"MongoDB": {non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase), non_relational_repository.NewPromotionNonRelationalRepository(NoSQLDatabase)},
+	}
+
+	for name, repos := range repositories {
+		t.Run(name, func(t *testing.T) {
+			promotionService := services.NewPromotionService(repos.promotion)
+
+			// ✅ Deleting a promotion is logical, it can still be looked up by code
+			err := repos.bank.DeleteDiscountPromotion(ctx, testCode)
+			assert.NoError(t, err)
+
+			detail, err := promotionService.GetPromotionByCode(ctx, testCode)
+			assert.NoError(t, err)
+			assert.Equal(t, models.PromotionTypeDiscount, detail.Type)
+			assert.Equal(t, models.PromotionStatusDeleted, detail.Status)
+
+			// ✅ Restoring it clears the flag
+			detail, err = promotionService.RestorePromotion(ctx, testCode)
+			assert.NoError(t, err)
+			assert.False(t, detail.IsDeleted)
+
+			_, err = promotionService.RestorePromotion(ctx, testCode)
+			assert.ErrorIs(t, err, services.ErrValidation)
+		})
+	}
+}
+
 func TestBankGetBankCustomerCounts(t *testing.T) {
+	ctx := context.Background()
 	bankRepo := relational_repository.NewBankRelationalRepository(SQLDatabase)
 
-	result, err := bankRepo.GetBankCustomerCounts()
+	result, err := bankRepo.GetBankCustomerCounts(ctx)
 	assert.NoError(t, err, "Error fetching bank customer counts from MySQL")
 
 	assert.Greater(t, len(result), 0)
@@ -362,7 +405,7 @@ func TestBankGetBankCustomerCounts(t *testing.T) {
 
 	// Use MongoDB repository
 	noSQLBankRepo := non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase)
-	resultMongo, err := noSQLBankRepo.GetBankCustomerCounts()
+	resultMongo, err := noSQLBankRepo.GetBankCustomerCounts(ctx)
 
 	assert.NoError(t, err, "Error fetching bank customer counts from MongoDB")
 
@@ -382,6 +425,7 @@ func TestBankGetBankCustomerCounts(t *testing.T) {
 // ---------------     CARD TESTS --------------------
 // ---------------------------------------------------
 func TestCardGetPaymentSummary(t *testing.T) {
+	ctx := context.Background()
 	cardNumber := "1234567812345678"
 	month := 10
 	year := 2024
@@ -389,68 +433,66 @@ func TestCardGetPaymentSummary(t *testing.T) {
 	// ------ SQL (MySQL) ------
 	cardRepo := relational_repository.NewCardRelationalRepository(SQLDatabase)
 
-	paymentSummary, err := cardRepo.GetPaymentSummary(cardNumber, month, year)
+	// ✅ The stored summary is returned, reading it does not generate a new one
+	paymentSummary, err := cardRepo.GetPaymentSummary(ctx, cardNumber, month, year)
 	assert.NoError(t, err, "Error fetching payment summary from MySQL")
 
-	// ✅ Generate expected code format
-	expectedCode := fmt.Sprintf("SUMMARY-%d-%d", year, month)
-
-	// ✅ Define start & end date for the query
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0) // One month later
-
-	// ✅ Fetch from MySQL to validate correctness
 	var paymentSummaryEntity entities.PaymentSummaryEntitySQL
 	if err := SQLDatabase.
 		Joins("JOIN CARDS ON CARDS.id = PAYMENT_SUMMARIES.card_id").
-		Preload("Card").
-		Preload("Card.PurchaseSinglePayments", "created_at >= ? AND created_at < ?", startDate, endDate).
-		Preload("Card.PurchaseMonthlyPayments", "created_at >= ? AND created_at < ?", startDate, endDate).
 		Where("number = ?", cardNumber).
-		Where("code = ?", expectedCode).First(&paymentSummaryEntity).Error; err != nil {
-		panic(fmt.Errorf("could not find promotion with code %s: %v", expectedCode, err))
+		Where("`month` = ? AND `year` = ?", month, year).
+		Order("PAYMENT_SUMMARIES.id").
+		First(&paymentSummaryEntity).Error; err != nil {
+		panic(fmt.Errorf("could not find payment summary of card %s: %v", cardNumber, err))
 	}
 
-	assert.Equal(t, paymentSummaryEntity.Code, expectedCode)
-	assert.Equal(t, paymentSummaryEntity.TotalPrice, 510.00)
-	assert.Equal(t, len(paymentSummaryEntity.Card.PurchaseMonthlyPayments), 1)
-	assert.Equal(t, len(paymentSummaryEntity.Card.PurchaseSinglePayments), 3)
-
-	paymentSummaryMapper := entities.ToPaymentSummary(&paymentSummaryEntity)
-
-	assert.Equal(t, paymentSummaryMapper.Code, paymentSummary.Code)
-	assert.Equal(t, paymentSummaryMapper.TotalPrice, paymentSummary.TotalPrice)
-	assert.Equal(t, len(paymentSummaryMapper.Card.PurchaseMonthlyPayments), len(paymentSummary.Card.PurchaseMonthlyPayments))
-	assert.Equal(t, len(paymentSummaryMapper.Card.PurchaseSinglePayments), len(paymentSummary.Card.PurchaseSinglePayments))
+	assert.Equal(t, paymentSummaryEntity.Code, paymentSummary.Code)
+	assert.Equal(t, paymentSummaryEntity.TotalPrice, paymentSummary.TotalPrice)
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLCardRepo := non_relational_repository.NewCardNonRelationalRepository(NoSQLDatabase)
-	paymentSummaryMongo, err := noSQLCardRepo.GetPaymentSummary(cardNumber, month, year)
 
+	// ✅ No summary exists until the cycle is closed
+	_, err = noSQLCardRepo.GetPaymentSummary(ctx, cardNumber, month, year)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	billingService := services.NewBillingService(non_relational_repository.NewBankNonRelationalRepository(NoSQLDatabase), noSQLCardRepo, non_relational_repository.NewExchangeRateNonRelationalRepository(NoSQLDatabase))
+	closedSummary, err := billingService.CloseCycle(ctx, cardNumber, month, year)
+	assert.NoError(t, err, "Error closing billing cycle in MongoDB")
+
+	paymentSummaryMongo, err := noSQLCardRepo.GetPaymentSummary(ctx, cardNumber, month, year)
 	assert.NoError(t, err, "Error fetching payment summary from MongoDB")
 
-	assert.Equal(t, paymentSummaryMongo.Code, expectedCode)
-	assert.Equal(t, 420.0, paymentSummaryMongo.TotalPrice)
+	assert.Equal(t, closedSummary.Code, paymentSummaryMongo.Code)
+	// The single payment (90) plus the first quota of the installment purchase (110)
+	assert.Equal(t, models.MustParseMoney("200"), paymentSummaryMongo.TotalPrice)
 	assert.Equal(t, 1, len(paymentSummaryMongo.SinglePayments))
 	assert.Equal(t, 1, len(paymentSummaryMongo.MonthlyPayments))
+	assert.Equal(t, 1, len(paymentSummaryMongo.Quotas))
+	assert.Equal(t, "PV20241001", paymentSummaryMongo.Quotas[0].PaymentVoucher)
 
+	// ✅ A closed cycle cannot be closed again
+	_, err = billingService.CloseCycle(ctx, cardNumber, month, year)
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
 }
 
 func TestCardGetCardsExpiringInNext30Days(t *testing.T) {
+	ctx := context.Background()
 	day := 16
 	month := 10
 	year := 2024
 
 	cardRepo := relational_repository.NewCardRelationalRepository(SQLDatabase)
 
-	cards, err := cardRepo.GetCardsExpiringInNext30Days(day, month, year)
+	cards, err := cardRepo.GetCardsExpiringInNext30Days(ctx, day, month, year)
 	assert.NoError(t, err, "Error fetching cards expiring in the next 30 days from MySQL")
 
 	assert.Equal(t, 4, len(*cards))
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLCardRepo := non_relational_repository.NewCardNonRelationalRepository(NoSQLDatabase)
-	cardsMongo, err := noSQLCardRepo.GetCardsExpiringInNext30Days(day, month, year)
+	cardsMongo, err := noSQLCardRepo.GetCardsExpiringInNext30Days(ctx, day, month, year)
 
 	assert.NoError(t, err, "Error fetching cards expiring in the next 30 days from MongoDB")
 
@@ -458,21 +500,22 @@ func TestCardGetCardsExpiringInNext30Days(t *testing.T) {
 }
 
 func TestCardGetPurchaseSingle(t *testing.T) {
+	ctx := context.Background()
 	paymentVoucher := "PV20241001"
 	cuit := "30-12345678-9"
-	finalAmount := 100.00
+	finalAmount := models.MustParseMoney("100")
 
 	cardRepo := relational_repository.NewCardRelationalRepository(SQLDatabase)
 
-	payment, err := cardRepo.GetPurchaseSingle(cuit, finalAmount, paymentVoucher)
+	payment, err := cardRepo.GetPurchaseSingle(ctx, cuit, finalAmount, paymentVoucher)
 	assert.NoError(t, err, "Error fetching purchase single from MySQL")
 
 	assert.Equal(t, payment.Purchase.Store, "Store A")
 
 	// ------ NoSQL (MongoDB) ------
-	finalAmount = 90
+	finalAmount = models.MustParseMoney("90")
 	noSQLCardRepo := non_relational_repository.NewCardNonRelationalRepository(NoSQLDatabase)
-	paymentMongo, err := noSQLCardRepo.GetPurchaseSingle(cuit, finalAmount, paymentVoucher)
+	paymentMongo, err := noSQLCardRepo.GetPurchaseSingle(ctx, cuit, finalAmount, paymentVoucher)
 
 	assert.NoError(t, err, "Error fetching purchase single from MongoDB")
 
@@ -480,39 +523,41 @@ func TestCardGetPurchaseSingle(t *testing.T) {
 }
 
 func TestCardGetPurchaseMonthly(t *testing.T) {
+	ctx := context.Background()
 	paymentVoucher := "PV20241101"
 	cuit := "20-98765432-1"
-	finalAmount := 440.0
+	finalAmount := models.MustParseMoney("440")
 
 	cardRepo := relational_repository.NewCardRelationalRepository(SQLDatabase)
 
-	payment, err := cardRepo.GetPurchaseMonthly(cuit, finalAmount, paymentVoucher)
+	payment, err := cardRepo.GetPurchaseMonthly(ctx, cuit, finalAmount, paymentVoucher)
 	assert.NoError(t, err, "Error fetching purchase monthly from MySQL")
 
 	assert.Equal(t, payment.Purchase.Store, "Store B")
-	assert.Equal(t, payment.Purchase.Amount, 110.00)
+	assert.Equal(t, models.MustParseMoney("110"), payment.Purchase.Amount)
 	assert.Equal(t, len(payment.Quota), 4)
 
 	// ------ NoSQL (MongoDB) ------
 	paymentVoucher = "PV20241001"
 	cuit = "30-12345678-9"
-	finalAmount = 330.00
+	finalAmount = models.MustParseMoney("330")
 
 	noSQLCardRepo := non_relational_repository.NewCardNonRelationalRepository(NoSQLDatabase)
-	paymentMongo, err := noSQLCardRepo.GetPurchaseMonthly(cuit, finalAmount, paymentVoucher)
+	paymentMongo, err := noSQLCardRepo.GetPurchaseMonthly(ctx, cuit, finalAmount, paymentVoucher)
 
 	assert.NoError(t, err, "Error fetching purchase single from MongoDB")
 
 	assert.Equal(t, paymentMongo.Store, "Store A")
-	assert.Equal(t, paymentMongo.Amount, 300.00)
+	assert.Equal(t, models.MustParseMoney("300"), paymentMongo.Amount)
 	assert.Equal(t, len(paymentMongo.Quota), 3)
-	assert.Equal(t, paymentMongo.Quota[0].Price, 110.00)
+	assert.Equal(t, models.MustParseMoney("110"), paymentMongo.Quota[0].Price)
 }
 func TestCardGetTop10CardsByPurchases(t *testing.T) {
+	ctx := context.Background()
 
 	cardRepo := relational_repository.NewCardRelationalRepository(SQLDatabase)
 
-	cards, err := cardRepo.GetTop10CardsByPurchases()
+	cards, err := cardRepo.GetTop10CardsByPurchases(ctx)
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -539,7 +584,7 @@ func TestCardGetTop10CardsByPurchases(t *testing.T) {
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLCardRepo := non_relational_repository.NewCardNonRelationalRepository(NoSQLDatabase)
-	cardsMongo, err := noSQLCardRepo.GetTop10CardsByPurchases()
+	cardsMongo, err := noSQLCardRepo.GetTop10CardsByPurchases(ctx)
 
 	assert.NoError(t, err, "Error fetching top 10 cards by purchases from MongoDB")
 
@@ -567,9 +612,9 @@ func TestCardGetTop10CardsByPurchases(t *testing.T) {
 	}
 
 	assert.Equal(t, 12, len(purchaseMonthlyMongo.Quota))
-	assert.Equal(t, float64(4), purchaseMonthlyMongo.Interest)
-	assert.Equal(t, 53.3, purchaseMonthlyMongo.Quota[0].Price)
-	assert.Equal(t, int64(purchaseMonthlyMongo.FinalAmount), int64(float64(len(purchaseMonthlyMongo.Quota))*purchaseMonthlyMongo.Quota[0].Price))
+	assert.Equal(t, models.MustParsePercentage("4"), purchaseMonthlyMongo.Interest)
+	assert.Equal(t, models.MustParseMoney("53.3"), purchaseMonthlyMongo.Quota[0].Price)
+	assert.Equal(t, purchaseMonthlyMongo.FinalAmount, models.Money(len(purchaseMonthlyMongo.Quota))*purchaseMonthlyMongo.Quota[0].Price)
 
 }
 
@@ -578,6 +623,7 @@ func TestCardGetTop10CardsByPurchases(t *testing.T) {
 // ---------------------------------------------------
 
 func TestPromotionGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
+	ctx := context.Background()
 	// Test Financing Promotion
 	testStore := "30-12345678-9"
 	startDate := time.Date(2024, time.Month(10), 1, 0, 0, 0, 0, time.UTC)
@@ -585,7 +631,7 @@ func TestPromotionGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
 
 	promotionRepo := relational_repository.NewPromotionRelationRepository(SQLDatabase)
 
-	financingPromotions, discountPromotions, err := promotionRepo.GetAvailablePromotionsByStoreAndDateRange(testStore, startDate, endDate)
+	financingPromotions, discountPromotions, err := promotionRepo.GetAvailablePromotionsByStoreAndDateRange(ctx, testStore, startDate, endDate)
 
 	if err != nil {
 		panic(err)
@@ -596,7 +642,7 @@ func TestPromotionGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLPromotionRepo := non_relational_repository.NewPromotionNonRelationalRepository(NoSQLDatabase)
-	financingPromotionsMongo, discountPromotionsMongo, err := noSQLPromotionRepo.GetAvailablePromotionsByStoreAndDateRange(testStore, startDate, endDate)
+	financingPromotionsMongo, discountPromotionsMongo, err := noSQLPromotionRepo.GetAvailablePromotionsByStoreAndDateRange(ctx, testStore, startDate, endDate)
 
 	assert.NoError(t, err, "Error fetching available promotions by store and date range from MongoDB")
 
@@ -605,31 +651,32 @@ func TestPromotionGetAvailablePromotionsByStoreAndDateRange(t *testing.T) {
 }
 
 func TestPromotionGetMostUsedPromotion(t *testing.T) {
+	ctx := context.Background()
 	// Test Financing Promotion
 	promotionRepo := relational_repository.NewPromotionRelationRepository(SQLDatabase)
 
-	mostUsed, err := promotionRepo.GetMostUsedPromotion()
+	mostUsed, err := promotionRepo.GetMostUsedPromotion(ctx)
 	assert.NoError(t, err, "Error fetching most used promotion from MySQL")
 
 	switch p := mostUsed.(type) {
-	case entities.DiscountEntitySQL:
+	case *models.Discount:
 		log.Fatalf("Error")
-	case entities.FinancingEntitySQL:
-		assert.Equal(t, p.PromotionEntitySQL.Code, "PV20241001")
+	case *models.Financing:
+		assert.Equal(t, p.Code, "PV20241001")
 	default:
 		log.Fatalf("Error")
 	}
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLPromotionRepo := non_relational_repository.NewPromotionNonRelationalRepository(NoSQLDatabase)
-	mostUsedMongo, err := noSQLPromotionRepo.GetMostUsedPromotion()
+	mostUsedMongo, err := noSQLPromotionRepo.GetMostUsedPromotion(ctx)
 
 	assert.NoError(t, err, "Error fetching most used promotion from MongoDB")
 
 	switch p := mostUsedMongo.(type) {
-	case entities.DiscountEntityNonSQL:
-		assert.Equal(t, p.PromotionEntity.Code, "WINTERSALE2024")
-	case entities.FinancingEntityNonSQL:
+	case *models.Discount:
+		assert.Equal(t, p.Code, "WINTERSALE2024")
+	case *models.Financing:
 		log.Fatal("This should not be a financing promotion")
 	default:
 		log.Fatalf("This should have been a discount promotion or a financing promotion")
@@ -641,9 +688,10 @@ func TestPromotionGetMostUsedPromotion(t *testing.T) {
 // ---------------------------------------------------
 
 func TestStoreGetStoreWithHighestRevenueByMonth(t *testing.T) {
+	ctx := context.Background()
 	storeRepo := relational_repository.NewStoreRelationalRepository(SQLDatabase)
 
-	result, err := storeRepo.GetStoreWithHighestRevenueByMonth(10, 2024)
+	result, err := storeRepo.GetStoreWithHighestRevenueByMonth(ctx, 10, 2024)
 	assert.NoError(t, err, "Error fetching store with highest revenue by month from MySQL")
 
 	assert.Equal(t, result.Cuit, "30-15066778-9")
@@ -651,7 +699,7 @@ func TestStoreGetStoreWithHighestRevenueByMonth(t *testing.T) {
 
 	// ------ NoSQL (MongoDB) ------
 	noSQLStoreRepo := non_relational_repository.NewStoreNonRelationalRepository(NoSQLDatabase)
-	resultMongo, err := noSQLStoreRepo.GetStoreWithHighestRevenueByMonth(10, 2024)
+	resultMongo, err := noSQLStoreRepo.GetStoreWithHighestRevenueByMonth(ctx, 10, 2024)
 
 	assert.NoError(t, err, "Error fetching store with highest revenue by month from MongoDB")
 
@@ -0,0 +1,181 @@
+/*
+ * Payment Registration System - Replication Component Tests
+ * ----------------------------------------------------------
+ * This file replicates the storage contract fixture, loaded into a SQLite database, into its own MongoDB
+ * database and checks that both backends answer the same, and that the consistency check finds no
+ * difference between them, before and after incremental syncs.
+ *
+ * Created: Mar. 24, 2025
+ * License: GNU General Public License v3.0
+ */
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/models"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/consistency"
+	nonrelational "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational"
+	non_relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/non_relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational"
+	relational_repository "github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/relational/repository"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/replication"
+	"github.com/GabrielEValenzuela/Payment-Registration-System/src/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const replicationDatabase = "payment_registration_replication"
+
+func TestReplicationFromSQLToMongo(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := relational.NewSQLDB(relational.DialectSQLite, filepath.Join(t.TempDir(), "payment_registration.db"), true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = relational.CloseDB(source) })
+	sql := storagetest.Storages{
+		Banks:         relational_repository.NewBankRelationalRepository(source),
+		Cards:         relational_repository.NewCardRelationalRepository(source),
+		Promotions:    relational_repository.NewPromotionRelationRepository(source),
+		Stores:        relational_repository.NewStoreRelationalRepository(source),
+		Customers:     relational_repository.NewCustomerRelationalRepository(source),
+		ExchangeRates: relational_repository.NewExchangeRateRelationalRepository(source),
+	}
+	require.NoError(t, storagetest.DefaultFixture().Load(sql))
+
+	target, err := nonrelational.NewMongoDB(contractMongoURI, replicationDatabase, true)
+	require.NoError(t, err, "Error initializing the MongoDB replication database")
+	t.Cleanup(func() { _ = nonrelational.CloseMongoDB(target.Client()) })
+	noSQL := storagetest.Storages{
+		Banks:         non_relational_repository.NewBankNonRelationalRepository(target),
+		Cards:         non_relational_repository.NewCardNonRelationalRepository(target),
+		Promotions:    non_relational_repository.NewPromotionNonRelationalRepository(target),
+		Stores:        non_relational_repository.NewStoreNonRelationalRepository(target),
+		Customers:     non_relational_repository.NewCustomerNonRelationalRepository(target),
+		ExchangeRates: non_relational_repository.NewExchangeRateNonRelationalRepository(target),
+	}
+
+	// A small batch size makes the sync resume from checkpoints within a table. Without an overlap window, the
+	// reports count the changed rows only
+	syncer := replication.NewSyncer(source, target, 1, 0)
+	reports, err := syncer.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"BANKS": 2, "BILLING_CYCLES": 0, "CUSTOMERS": 2, "CARDS": 3, "DISCOUNTS": 2, "FINANCINGS": 2,
+		"PURCHASE_SINGLE_PAYMENTS": 3, "PURCHASE_MONTHLY_PAYMENTS": 2, "PAYMENT_SUMMARIES": 0,
+		"EXCHANGE_RATES": 3, "PAYMENTS": 0,
+	}, rowsByTable(reports))
+	assertSameAnswers(t, sql, noSQL)
+	assertConsistent(t, consistency.NewSQLSource(source), consistency.NewMongoSource(target))
+
+	// Nothing changed since the last sync
+	reports, err = syncer.Run(ctx, false)
+	require.NoError(t, err)
+	for _, report := range reports {
+		assert.Zero(t, report.Rows, report.Table)
+	}
+
+	// Changes are replicated by the next incremental sync
+	_, err = sql.Banks.UpdateBank(ctx, storagetest.BankCuit, models.Bank{Name: "Banco Renombrado", Address: "Av. Corrientes 1000", Telephone: "0800-111-1111"})
+	require.NoError(t, err)
+	require.NoError(t, sql.Customers.RemoveCustomerFromBank(ctx, storagetest.OtherCustomer, storagetest.OtherBankCuit))
+	_, err = sql.Cards.UpdateCardStatus(ctx, storagetest.IdleCardNumber, models.CardStatusBlocked)
+	require.NoError(t, err)
+	require.NoError(t, sql.Banks.DeleteDiscountPromotion(ctx, "DISC-2025"))
+
+	// The consistency check sees the changes that are not replicated yet
+	report, err := consistency.Check(ctx, consistency.NewSQLSource(source), consistency.NewMongoSource(target))
+	require.NoError(t, err)
+	assert.False(t, report.Consistent)
+	assert.Contains(t, report.Differences, models.Difference{
+		Entity: consistency.EntityBank, Key: storagetest.BankCuit, Kind: models.DifferenceMismatch,
+		Field: "name", SQLValue: "Banco Renombrado", NoSQLValue: "Banco Contrato",
+	})
+	assert.Contains(t, report.Differences, models.Difference{
+		Entity: consistency.EntityCard, Key: storagetest.IdleCardNumber, Kind: models.DifferenceMismatch,
+		Field: "status", SQLValue: string(models.CardStatusBlocked), NoSQLValue: string(models.CardStatusActive),
+	})
+
+	reports, err = syncer.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"BANKS": 1, "BILLING_CYCLES": 0, "CUSTOMERS": 1, "CARDS": 1, "DISCOUNTS": 1, "FINANCINGS": 0,
+		"PURCHASE_SINGLE_PAYMENTS": 0, "PURCHASE_MONTHLY_PAYMENTS": 0, "PAYMENT_SUMMARIES": 0,
+		"EXCHANGE_RATES": 3, "PAYMENTS": 0,
+	}, rowsByTable(reports))
+	assertSameAnswers(t, sql, noSQL)
+	assertConsistent(t, consistency.NewSQLSource(source), consistency.NewMongoSource(target))
+
+	// A full sync writes the same documents again
+	_, err = syncer.Run(ctx, true)
+	require.NoError(t, err)
+	assertSameAnswers(t, sql, noSQL)
+}
+
+// assertConsistent runs the consistency check and expects no difference between the stores.
+func assertConsistent(t *testing.T, sql consistency.Source, noSQL consistency.Source) {
+	report, err := consistency.Check(context.Background(), sql, noSQL)
+	require.NoError(t, err)
+	assert.True(t, report.Consistent, "differences: %v", report.Differences)
+}
+
+func rowsByTable(reports []replication.TableReport) map[string]int {
+	rows := map[string]int{}
+	for _, report := range reports {
+		rows[report.Table] = report.Rows
+	}
+	return rows
+}
+
+// assertSameAnswers compares the answers of both backends for the data of the default fixture.
+func assertSameAnswers(t *testing.T, sql storagetest.Storages, noSQL storagetest.Storages) {
+	ctx := context.Background()
+	sqlBanks, err := sql.Banks.GetBanks(ctx)
+	require.NoError(t, err)
+	noSQLBanks, err := noSQL.Banks.GetBanks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, *sqlBanks, *noSQLBanks)
+
+	sqlCounts, err := sql.Banks.GetBankCustomerCounts(ctx)
+	require.NoError(t, err)
+	noSQLCounts, err := noSQL.Banks.GetBankCustomerCounts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, sqlCounts, noSQLCounts)
+
+	for _, cuit := range []string{storagetest.CustomerCuit, storagetest.OtherCustomer} {
+		sqlCustomer, err := sql.Customers.GetCustomerByCuit(ctx, cuit)
+		require.NoError(t, err)
+		noSQLCustomer, err := noSQL.Customers.GetCustomerByCuit(ctx, cuit)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, sqlCustomer.BankCuits, noSQLCustomer.BankCuits, cuit)
+	}
+
+	for _, number := range []string{storagetest.CardNumber, storagetest.OtherCardNumber, storagetest.IdleCardNumber} {
+		sqlCard, err := sql.Cards.GetCardByNumber(ctx, number)
+		require.NoError(t, err)
+		noSQLCard, err := noSQL.Cards.GetCardByNumber(ctx, number)
+		require.NoError(t, err)
+		assert.Equal(t, sqlCard.Status, noSQLCard.Status, number)
+		assert.Equal(t, sqlCard.CustomerCuit, noSQLCard.CustomerCuit, number)
+
+		from, to := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+		sqlSingle, sqlMonthly, err := sql.Cards.GetPurchasesInPeriod(ctx, number, from, to)
+		require.NoError(t, err)
+		noSQLSingle, noSQLMonthly, err := noSQL.Cards.GetPurchasesInPeriod(ctx, number, from, to)
+		require.NoError(t, err)
+		assert.Len(t, *noSQLSingle, len(*sqlSingle), number)
+		assert.Len(t, *noSQLMonthly, len(*sqlMonthly), number)
+	}
+
+	for _, code := range []string{"DISC-2025", "DISC-SPRING", "FIN-2025", "FIN-OLD"} {
+		sqlPromotion, err := sql.Promotions.GetPromotionByCode(ctx, code)
+		require.NoError(t, err)
+		noSQLPromotion, err := noSQL.Promotions.GetPromotionByCode(ctx, code)
+		require.NoError(t, err)
+		assert.Equal(t, sqlPromotion.IsDeleted, noSQLPromotion.IsDeleted, code)
+		assert.Equal(t, sqlPromotion.Type, noSQLPromotion.Type, code)
+	}
+}